@@ -0,0 +1,151 @@
+//go:build devnet
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/local/go-eth-demo/go-eth-demo/counter"
+	"github.com/local/go-eth-demo/internal/devnettest"
+	"github.com/local/go-eth-demo/pkg/counterflow"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+// buildCLI 编译命令行程序，返回可执行文件路径
+func buildCLI(t *testing.T) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), "go-eth-demo")
+	out, err := exec.Command("go", "build", "-o", bin, "github.com/local/go-eth-demo/go-eth-demo").CombinedOutput()
+	if err != nil {
+		t.Fatalf("build go-eth-demo: %v\n%s", err, out)
+	}
+	return bin
+}
+
+// runCLI 在临时目录中运行命令行程序，只传入 env 和 PATH、HOME，不读取开发者的 .env 和其他环境变量。
+// 交易历史写在临时目录中。返回合并的输出和退出码
+func runCLI(t *testing.T, bin string, env map[string]string, args ...string) (string, int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Dir = t.TempDir()
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + cmd.Dir, "LOG_LEVEL=warn"}
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	out, err := cmd.CombinedOutput()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return string(out), exit.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("run go-eth-demo %s: %v", strings.Join(args, " "), err)
+	}
+	return string(out), 0
+}
+
+// accountEnv 返回以 w 签名、连接 node 的环境变量
+func accountEnv(node *devnettest.Node, w *wallet.Wallet) map[string]string {
+	return map[string]string{
+		"RPC_URL":     node.State.RPCURL,
+		"PRIVATE_KEY": hexutil.Encode(crypto.FromECDSA(w.PrivateKey()))[2:],
+	}
+}
+
+// TestDevnetTasks 不带参数运行命令行程序（task01 转账加 task02 递增 Counter），检查链上结果
+func TestDevnetTasks(t *testing.T) {
+	node := devnettest.Start(t)
+	bin := buildCLI(t)
+	w := node.NewAccount(t, 10)
+	address := node.DeployCounter(t, w)
+	recipient := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	node.SetBalance(t, recipient, big.NewInt(0))
+
+	env := accountEnv(node, w)
+	env["RECIPIENT_ADDR"] = recipient.Hex()
+	env["CONTRACT_ADDR"] = address.Hex()
+	env["TRANSFER_AMOUNT"] = "0.5 eth"
+	out, code := runCLI(t, bin, env)
+	if code != 0 {
+		t.Fatalf("tasks exited with %d:\n%s", code, out)
+	}
+
+	ctx := context.Background()
+	want := new(big.Int).Div(big.NewInt(params.Ether), big.NewInt(2))
+	if balance, err := node.Client.BalanceAt(ctx, recipient, nil); err != nil || balance.Cmp(want) != 0 {
+		t.Errorf("recipient balance = %v, %v, want %v", balance, err, want)
+	}
+	contract, err := counter.NewCounter(address, node.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count, err := contract.GetCount(&bind.CallOpts{Context: ctx}); err != nil || count.Int64() != 1 {
+		t.Errorf("count = %v, %v, want 1", count, err)
+	}
+}
+
+// TestDevnetExitCodes 检查命令在余额不足和节点不可用时以对应的退出码失败
+func TestDevnetExitCodes(t *testing.T) {
+	node := devnettest.Start(t)
+	bin := buildCLI(t)
+	w := node.NewAccount(t, 1)
+	env := accountEnv(node, w)
+	to := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8").Hex()
+
+	out, code := runCLI(t, bin, env, "transfer", "-to", to, "-amount", "5eth")
+	if code != 3 {
+		t.Errorf("transfer more than the balance exited with %d, want 3:\n%s", code, out)
+	}
+	env["RPC_URL"] = "http://127.0.0.1:1"
+	out, code = runCLI(t, bin, env, "transfer", "-to", to, "-amount", "0.1eth")
+	if code != 6 {
+		t.Errorf("transfer with the node unreachable exited with %d, want 6:\n%s", code, out)
+	}
+}
+
+// TestDevnetSnapshot 检查快照回滚会撤销合约状态和 nonce
+func TestDevnetSnapshot(t *testing.T) {
+	node := devnettest.Start(t)
+	w := node.NewAccount(t, 1)
+	address := node.DeployCounter(t, w)
+	auth, err := w.Transactor(node.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	nonce, err := node.Client.NonceAt(ctx, w.Address(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revert := node.Snapshot(t)
+	if _, err := counterflow.Increment(ctx, node.Client, address, auth); err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	revert()
+
+	contract, err := counter.NewCounter(address, node.Client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count, err := contract.GetCount(&bind.CallOpts{Context: ctx}); err != nil || count.Sign() != 0 {
+		t.Errorf("count after revert = %v, %v, want 0", count, err)
+	}
+	if after, err := node.Client.NonceAt(ctx, w.Address(), nil); err != nil || after != nonce {
+		t.Errorf("nonce after revert = %d, %v, want %d", after, err, nonce)
+	}
+}
@@ -0,0 +1,12 @@
+// Package e2e 包含针对 Sepolia 的端到端测试。测试文件带有 e2e 构建标签，默认的
+// `go test ./...` 不会运行它们；需要显式开启：
+//
+//	E2E_RPC_URL=wss://... E2E_PRIVATE_KEY=... go test -tags e2e -v -count=1 ./e2e
+//
+// 测试会发送真实交易，请使用只有少量测试币的专用账户。
+//
+// 带有 devnet 构建标签的测试在本地 Anvil 节点上编译并运行命令行程序（见 internal/devnettest），
+// PATH 中有 anvil 时自动启动，也可以用 DEVNET_RPC_URL 连接已有的 anvil 或 hardhat 节点：
+//
+//	go test -tags devnet -v -count=1 ./e2e
+package e2e
@@ -0,0 +1,207 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/counterflow"
+)
+
+// 每个测试的最长运行时间，Sepolia 出块约 12 秒，留出足够的确认时间
+const testTimeout = 5 * time.Minute
+
+// env 是测试所需的连接和账户
+type env struct {
+	client  *ethclient.Client
+	key     *ecdsa.PrivateKey
+	from    common.Address
+	chainID *big.Int
+}
+
+// setup 从环境变量读取配置，未配置时跳过测试
+func setup(t *testing.T) (context.Context, *env) {
+	t.Helper()
+	url := os.Getenv("E2E_RPC_URL")
+	keyHex := strings.TrimPrefix(os.Getenv("E2E_PRIVATE_KEY"), "0x")
+	if url == "" || keyHex == "" {
+		t.Skip("E2E_RPC_URL and E2E_PRIVATE_KEY must be set to run e2e tests")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	t.Cleanup(cancel)
+
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		t.Fatalf("dial %s: %v", url, err)
+	}
+	t.Cleanup(client.Close)
+
+	key, err := crypto.HexToECDSA(keyHex)
+	if err != nil {
+		t.Fatalf("parse E2E_PRIVATE_KEY: %v", err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		t.Fatalf("get chain id: %v", err)
+	}
+	return ctx, &env{client: client, key: key, from: crypto.PubkeyToAddress(key.PublicKey), chainID: chainID}
+}
+
+// TestTransfer 向自己转账 1 wei，验证余额恰好减少了实际支付的手续费
+func TestTransfer(t *testing.T) {
+	ctx, e := setup(t)
+
+	before, err := e.client.BalanceAt(ctx, e.from, nil)
+	if err != nil {
+		t.Fatalf("get balance: %v", err)
+	}
+	nonce, err := e.client.PendingNonceAt(ctx, e.from)
+	if err != nil {
+		t.Fatalf("get nonce: %v", err)
+	}
+	tip, err := e.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		t.Fatalf("suggest tip: %v", err)
+	}
+	head, err := e.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatalf("get head: %v", err)
+	}
+	feeCap := new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+
+	tx, err := types.SignNewTx(e.key, types.LatestSignerForChainID(e.chainID), &types.DynamicFeeTx{
+		ChainID:   e.chainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       21000,
+		To:        &e.from,
+		Value:     big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatalf("sign transaction: %v", err)
+	}
+	if err := e.client.SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("send transaction: %v", err)
+	}
+	t.Logf("sent %s", tx.Hash().Hex())
+
+	receipt, err := bind.WaitMined(ctx, e.client, tx)
+	if err != nil {
+		t.Fatalf("wait for transaction: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("transaction failed with status %d", receipt.Status)
+	}
+
+	after, err := e.client.BalanceAt(ctx, e.from, receipt.BlockNumber)
+	if err != nil {
+		t.Fatalf("get balance after transfer: %v", err)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	// 余额在交易所在区块上读取；同一账户的并发交易会干扰该检查
+	if got := new(big.Int).Sub(before, after); got.Cmp(fee) != 0 {
+		t.Errorf("balance decreased by %s, want fee %s (is another job using this account?)", got, fee)
+	}
+}
+
+// TestCounterReadWrite 读取、递增并再次读取 Counter 合约。设置 E2E_COUNTER 时复用已部署的
+// 合约，否则先部署一个新的。
+func TestCounterReadWrite(t *testing.T) {
+	ctx, e := setup(t)
+
+	auth, err := bind.NewKeyedTransactorWithChainID(e.key, e.chainID)
+	if err != nil {
+		t.Fatalf("create transactor: %v", err)
+	}
+	auth.Context = ctx
+
+	var address common.Address
+	if addr := os.Getenv("E2E_COUNTER"); addr != "" {
+		if !common.IsHexAddress(addr) {
+			t.Fatalf("E2E_COUNTER is not a valid address: %s", addr)
+		}
+		address = common.HexToAddress(addr)
+	} else {
+		address, _, err = counterflow.Deploy(ctx, e.client, auth)
+		if err != nil {
+			t.Fatalf("deploy counter: %v", err)
+		}
+		t.Logf("deployed counter at %s", address.Hex())
+	}
+
+	result, err := counterflow.Increment(ctx, e.client, address, auth)
+	if err != nil {
+		t.Fatalf("increment: %v", err)
+	}
+	if !result.Incremented() {
+		t.Errorf("count did not increase: before %s, after %s", result.Before, result.After)
+	}
+}
+
+// TestSubscriptions 验证新区块头和日志订阅。订阅需要 WebSocket 连接，HTTP 端点会跳过该测试。
+func TestSubscriptions(t *testing.T) {
+	ctx, e := setup(t)
+
+	t.Run("NewHead", func(t *testing.T) {
+		heads := make(chan *types.Header)
+		sub, err := e.client.SubscribeNewHead(ctx, heads)
+		skipIfUnsupported(t, err)
+		defer sub.Unsubscribe()
+
+		select {
+		case head := <-heads:
+			if head.Number == nil || head.Number.Sign() == 0 {
+				t.Errorf("received head without block number: %+v", head)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription error: %v", err)
+		case <-ctx.Done():
+			t.Fatal("no new head received before timeout")
+		}
+	})
+
+	t.Run("Logs", func(t *testing.T) {
+		// 不限制地址，Sepolia 上几乎每个区块都有日志
+		logs := make(chan types.Log)
+		sub, err := e.client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{}, logs)
+		skipIfUnsupported(t, err)
+		defer sub.Unsubscribe()
+
+		select {
+		case log := <-logs:
+			if log.BlockHash == (common.Hash{}) || log.TxHash == (common.Hash{}) {
+				t.Errorf("received log without block or tx hash: %+v", log)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription error: %v", err)
+		case <-ctx.Done():
+			t.Fatal("no log received before timeout")
+		}
+	})
+}
+
+func skipIfUnsupported(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		t.Skip("subscriptions require a WebSocket RPC URL")
+	}
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/aa"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// aaFlags 是 aa 命令共用的参数
+type aaFlags struct {
+	salt *int64
+}
+
+func newAAFlags(fs *pflag.FlagSet) aaFlags {
+	return aaFlags{
+		salt: fs.Int64("salt", 0, "salt of the smart account (one owner can control several accounts)"),
+	}
+}
+
+// account 连接节点并返回 PRIVATE_KEY 控制的 SimpleAccount
+func (f aaFlags) account(ctx context.Context) (*ethclient.Client, *aa.SimpleAccount, error) {
+	key, err := loadPrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := dial(ctx, rpcEndpoint())
+	if err != nil {
+		return nil, nil, err
+	}
+	account, err := aa.NewSimpleAccount(ctx, client, key, big.NewInt(*f.salt))
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	return client, account, nil
+}
+
+// aaAddress 显示 PRIVATE_KEY 控制的智能账户地址、部署状态和余额
+func aaAddress() *cobra.Command {
+	cmd := &cobra.Command{Use: "address", Short: "Show the smart account's address, deployment status and balance"}
+	fs := cmd.Flags()
+	af := newAAFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := rootCtx
+		client, account, err := af.account(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		deployed, err := account.Deployed(ctx, client)
+		if err != nil {
+			return err
+		}
+		bal, err := client.BalanceAt(ctx, account.Address, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get balance: %w", err)
+		}
+		deposit, err := account.Deposit(ctx, client)
+		if err != nil {
+			return err
+		}
+		preset := presetFor(ctx, client)
+		fmt.Printf("Owner:     %s\n", account.Owner.Hex())
+		fmt.Printf("Account:   %s\n", account.Address.Hex())
+		fmt.Printf("Deployed:  %v\n", deployed)
+		fmt.Printf("Balance:   %s %s\n", units.FormatUnits(bal, preset.Currency.Decimals), preset.Currency.Symbol)
+		fmt.Printf("EntryPoint deposit: %s %s\n", units.FormatUnits(deposit, preset.Currency.Decimals), preset.Currency.Symbol)
+		if !deployed {
+			fmt.Println("\nThe account is deployed with its first user operation; fund it first so it can pay for gas.")
+		}
+		return nil
+	}
+	return cmd
+}
+
+// aaSend 通过 bundler 以智能账户身份发送一笔调用
+func aaSend() *cobra.Command {
+	cmd := &cobra.Command{Use: "send", Short: "Send a call from the smart account through a bundler"}
+	fs := cmd.Flags()
+	af := newAAFlags(fs)
+	bundlerURL := fs.String("bundler", os.Getenv("BUNDLER_URL"), "ERC-4337 bundler endpoint (default $BUNDLER_URL)")
+	to := fs.String("to", "", "call target")
+	valueStr := fs.String("value", "0", "value to send, e.g. 0.001eth")
+	dataHex := fs.String("data", "", "call data (hex)")
+	wait := fs.Bool("wait", true, "wait for the user operation to be included")
+	pmFlags := newPaymasterFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if *bundlerURL == "" {
+			return fmt.Errorf("a bundler is required: set BUNDLER_URL or use --bundler")
+		}
+		target, err := address.ParseHex(*to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+		value, err := units.ParseAmount(*valueStr)
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, account, err := af.account(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		bundler, err := aa.DialBundler(ctx, *bundlerURL)
+		if err != nil {
+			return err
+		}
+		defer bundler.Close()
+		pm, closePM, err := pmFlags.paymaster(ctx, *bundlerURL)
+		if err != nil {
+			return err
+		}
+		defer closePM()
+
+		op, hash, err := aa.SendCalls(ctx, client, bundler, account, pm, aa.Call{To: target, Value: value, Data: common.FromHex(*dataHex)})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Account:     %s\n", account.Address.Hex())
+		if op.Factory != nil {
+			fmt.Println("Deploying:   yes (first user operation)")
+		}
+		if op.Paymaster != nil {
+			fmt.Printf("Paymaster:   %s (%s)\n", op.Paymaster.Hex(), *pmFlags.mode)
+		} else {
+			fmt.Printf("Max gas fee: %s ETH\n", units.FormatUnits(op.RequiredPrefund(), 18))
+		}
+		fmt.Printf("UserOp hash: %s\n", hash.Hex())
+		if !*wait {
+			return nil
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+		receipt, err := bundler.WaitForReceipt(waitCtx, hash, 2*time.Second)
+		if err != nil {
+			return fmt.Errorf("wait for user operation: %w", err)
+		}
+		return printUserOpReceipt(receipt)
+	}
+	return cmd
+}
+
+// aaReceipt 查询 aa send --wait=false 提交的 userOpHash 的收据，--wait 时轮询直到被打包
+func aaReceipt() *cobra.Command {
+	cmd := &cobra.Command{Use: "receipt <userOpHash>", Short: "Look up the receipt of a UserOperation"}
+	fs := cmd.Flags()
+	bundlerURL := fs.String("bundler", os.Getenv("BUNDLER_URL"), "ERC-4337 bundler endpoint (default $BUNDLER_URL)")
+	wait := fs.Bool("wait", false, "poll until the user operation is included")
+	timeout := fs.Duration("timeout", 5*time.Minute, "how long to wait with --wait")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: aa receipt [flags] <userOpHash>")
+		}
+		if *bundlerURL == "" {
+			return fmt.Errorf("a bundler is required: set BUNDLER_URL or use --bundler")
+		}
+		hashHex := fs.Arg(0)
+		if len(common.FromHex(hashHex)) != common.HashLength {
+			return fmt.Errorf("invalid userOpHash %q", hashHex)
+		}
+		hash := common.HexToHash(hashHex)
+
+		ctx := rootCtx
+		bundler, err := aa.DialBundler(ctx, *bundlerURL)
+		if err != nil {
+			return err
+		}
+		defer bundler.Close()
+		var receipt *aa.Receipt
+		if *wait {
+			waitCtx, cancel := context.WithTimeout(ctx, *timeout)
+			defer cancel()
+			if receipt, err = bundler.WaitForReceipt(waitCtx, hash, 2*time.Second); err != nil {
+				return fmt.Errorf("wait for user operation: %w", err)
+			}
+		} else if receipt, err = bundler.GetReceipt(ctx, hash); err != nil {
+			return err
+		}
+		if receipt == nil {
+			fmt.Println("Pending: the bundler has not included this user operation yet")
+			return nil
+		}
+		fmt.Printf("Account:     %s (nonce %s)\n", receipt.Sender.Hex(), receipt.Nonce.ToInt())
+		return printUserOpReceipt(receipt)
+	}
+	return cmd
+}
+
+// printUserOpReceipt 打印 user operation 所在的交易和实际 gas 费用，执行失败时返回错误
+func printUserOpReceipt(receipt *aa.Receipt) error {
+	if receipt.Receipt != nil {
+		fmt.Printf("Included in tx %s (block %s)\n", receipt.Receipt.TxHash.Hex(), receipt.Receipt.BlockNumber)
+	}
+	if !receipt.Success {
+		return fmt.Errorf("user operation reverted: %s", receipt.Reason)
+	}
+	fmt.Printf("✅ Success, actual gas cost %s ETH\n", units.FormatUnits(receipt.ActualGasCost.ToInt(), 18))
+	return nil
+}
+
+// paymasterFlags 选择 aa send 的 gas 支付方式
+type paymasterFlags struct {
+	mode    *string
+	url     *string
+	policy  *string
+	token   *string
+	address *string
+}
+
+func newPaymasterFlags(fs *pflag.FlagSet) paymasterFlags {
+	return paymasterFlags{
+		mode:    fs.String("paymaster", "none", "who pays for gas: none (the account), verifying (sponsored) or erc20 (paid in --gas-token)"),
+		url:     fs.String("paymaster-url", os.Getenv("PAYMASTER_URL"), "ERC-7677 paymaster endpoint (default $PAYMASTER_URL, then the bundler)"),
+		policy:  fs.String("policy", "", "sponsorship policy ID for the verifying paymaster"),
+		token:   fs.String("gas-token", "", "ERC-20 token used to pay for gas with --paymaster erc20"),
+		address: fs.String("paymaster-address", "", "ERC-20 paymaster contract to approve with --paymaster erc20"),
+	}
+}
+
+// paymaster 返回选择的 paymaster，未使用时为 nil；很多服务商在 bundler 端点上同时提供 pm_ 方法
+func (f paymasterFlags) paymaster(ctx context.Context, bundlerURL string) (aa.Paymaster, func(), error) {
+	if *f.mode == "none" {
+		return nil, func() {}, nil
+	}
+	url := *f.url
+	if url == "" {
+		url = bundlerURL
+	}
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect to paymaster: %w", err)
+	}
+	switch *f.mode {
+	case "verifying":
+		return aa.NewVerifyingPaymaster(client, *f.policy), client.Close, nil
+	case "erc20":
+		if *f.token == "" || *f.address == "" {
+			client.Close()
+			return nil, nil, fmt.Errorf("--paymaster erc20 requires --gas-token and --paymaster-address")
+		}
+		token, err := address.ParseHex(*f.token)
+		if err != nil {
+			client.Close()
+			return nil, nil, fmt.Errorf("--gas-token: %w", err)
+		}
+		paymaster, err := address.ParseHex(*f.address)
+		if err != nil {
+			client.Close()
+			return nil, nil, fmt.Errorf("--paymaster-address: %w", err)
+		}
+		return aa.NewERC20Paymaster(client, token, paymaster), client.Close, nil
+	}
+	client.Close()
+	return nil, nil, fmt.Errorf("unknown paymaster mode %q (want none, verifying or erc20)", *f.mode)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/abicall"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/decode"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+)
+
+// printOutputs 逐行显示解码后的返回值，数组和 tuple 以 JSON 显示
+func printOutputs(outputs []decode.Arg) {
+	for i, o := range outputs {
+		name := o.Name
+		if name == "" {
+			name = fmt.Sprintf("[%d]", i)
+		}
+		value := fmt.Sprint(o.Value)
+		switch o.Value.(type) {
+		case []interface{}, map[string]interface{}:
+			b, _ := json.Marshal(o.Value)
+			value = string(b)
+		}
+		fmt.Printf("%s (%s): %s\n", name, o.Type, value)
+	}
+}
+
+// contractCall 按 ABI 文件打包参数，执行 eth_call 并解码返回值，不需要 abigen 绑定
+func contractCall() *cobra.Command {
+	cmd := &cobra.Command{Use: "call <address> <method|signature> [args...]", Short: "Call a contract method through its ABI and decode the result"}
+	fs := cmd.Flags()
+	abiPath := fs.String("abi", "", "ABI JSON file, or a Hardhat/Foundry artifact with an \"abi\" field")
+	from := fs.String("from", "", "caller address (msg.sender) for the call")
+	asJSON := fs.Bool("json", false, "print the outputs as JSON")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("usage: call <address> <method|signature> [args...] --abi file.json")
+		}
+		to, err := address.ParseHex(args[0])
+		if err != nil {
+			return err
+		}
+		if *abiPath == "" {
+			return errors.New("--abi is required")
+		}
+		contract, err := abicall.LoadABI(*abiPath)
+		if err != nil {
+			return err
+		}
+		method, data, err := abicall.Pack(contract, args[1], args[2:])
+		if err != nil {
+			return err
+		}
+		msg := ethereum.CallMsg{To: &to, Data: data}
+		if *from != "" {
+			if msg.From, err = address.ParseHex(*from); err != nil {
+				return fmt.Errorf("--from: %w", err)
+			}
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		outputs, err := abicall.Call(ctx, client, contract, method, msg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", method.Sig, err)
+		}
+		if *asJSON {
+			out, err := json.MarshalIndent(outputs, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+		if !strings.Contains(method.StateMutability, "view") && method.StateMutability != "pure" {
+			fmt.Printf("Note: %s is %s; the call only simulates it, use send to execute it\n", method.Sig, method.StateMutability)
+		}
+		printOutputs(outputs)
+		return nil
+	}
+	return cmd
+}
+
+// contractSend 是 call 的写入版本：按 ABI 打包参数，先模拟再估算 gas，签名广播并等待收据，
+// 最后按 ABI 解码交易产生的事件
+func contractSend() *cobra.Command {
+	cmd := &cobra.Command{Use: "send <address> <method|signature> [args...]", Short: "Send a contract transaction through its ABI and decode its events"}
+	fs := cmd.Flags()
+	abiPath := fs.String("abi", "", "ABI JSON file, or a Hardhat/Foundry artifact with an \"abi\" field")
+	valueStr := fs.String("value", "0", "ETH to send with the call, e.g. 0.01eth (payable methods only)")
+	cf := newCallFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errors.New("usage: send <address> <method|signature> [args...] --abi file.json [--value 0.01eth]")
+		}
+		to, err := address.ParseHex(args[0])
+		if err != nil {
+			return err
+		}
+		if *abiPath == "" {
+			return errors.New("--abi is required")
+		}
+		value, err := units.ParseAmount(*valueStr)
+		if err != nil {
+			return err
+		}
+		contract, err := abicall.LoadABI(*abiPath)
+		if err != nil {
+			return err
+		}
+		method, data, err := abicall.Pack(contract, args[1], args[2:])
+		if err != nil {
+			return err
+		}
+		if value.Sign() > 0 && !method.IsPayable() {
+			return fmt.Errorf("%s is not payable, it cannot receive --value", method.Sig)
+		}
+		if err := cf.check(); err != nil {
+			return err
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// 先模拟，会回滚时按 ABI 中的自定义错误显示原因，不花费 gas
+		if _, err := abicall.Call(ctx, client, contract, method, ethereum.CallMsg{From: w.Address(), To: &to, Value: value, Data: data}); err != nil {
+			return fmt.Errorf("%s: %w", method.Sig, err)
+		}
+		t, err := ethtx.PrepareCall(ctx, client, w.Address(), to, value, data)
+		if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			return fmt.Errorf("%s: %w", method.Sig, abicall.RevertError(contract, err))
+		}
+		fmt.Printf("Contract:  %s\n", to.Hex())
+		fmt.Printf("Method:    %s\n", method.Sig)
+		fmt.Printf("From:      %s\n", t.From.Hex())
+		if value.Sign() > 0 {
+			fmt.Printf("Value:     %s ETH\n", units.FormatEther(value, 6))
+		}
+		receipt, err := cf.send(ctx, client, w, t)
+		if err != nil || receipt == nil {
+			return err
+		}
+		printEvents(contract, receipt)
+		return nil
+	}
+	return cmd
+}
+
+// printEvents 按 ABI 解码收据中的事件，ABI 中没有的事件只显示数量
+func printEvents(contract *abi.ABI, receipt *types.Receipt) {
+	unknown := 0
+	for _, l := range receipt.Logs {
+		event, err := decode.Log(contract, l)
+		if err != nil {
+			unknown++
+			continue
+		}
+		args := make([]string, len(event.Args))
+		for i, arg := range event.Args {
+			args[i] = fmt.Sprintf("%s=%v", arg.Name, arg.Value)
+		}
+		fmt.Printf("Event %s(%s) from %s\n", event.Name, strings.Join(args, ", "), event.Address)
+	}
+	if unknown > 0 {
+		fmt.Printf("%d more logs not in the ABI\n", unknown)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/ens"
+	"github.com/local/go-eth-demo/pkg/explorer"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+)
+
+// accountHistory 通过区块浏览器 API 列出地址的普通交易、内部交易和代币转账。默认使用 Etherscan V2
+// （ETHERSCAN_API_KEY，链由所连节点决定），EXPLORER_API 设置为 Blockscout 的 /api 地址时改用 Blockscout
+func accountHistory() *cobra.Command {
+	cmd := &cobra.Command{Use: "history <address|name>", Short: "List an address's transactions and token transfers from a block explorer"}
+	fs := cmd.Flags()
+	api := fs.String("api", os.Getenv("EXPLORER_API"), "Etherscan-compatible API URL, e.g. https://eth-sepolia.blockscout.com/api (default $EXPLORER_API or Etherscan V2)")
+	kindsStr := fs.String("kinds", "", "comma-separated kinds to fetch: normal, internal, token (default all)")
+	limit := fs.IntP("limit", "n", explorer.DefaultLimit, "records per kind")
+	page := fs.Int("page", 1, "page number, starting at 1")
+	startBlock := fs.Uint64("start-block", 0, "first block to include")
+	endBlock := fs.Uint64("end-block", 0, "last block to include (default latest)")
+	output := outputFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return errors.New("usage: account history [flags] <address or name>")
+		}
+		kinds, err := explorer.ParseKinds(*kindsStr)
+		if err != nil {
+			return err
+		}
+		if err := setOutput(*output); err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		addr, err := resolveAddress(ctx, client, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		preset := presetFor(ctx, client)
+
+		var c *explorer.Client
+		if *api != "" {
+			c = explorer.NewBlockscout(*api)
+			c.APIKey = os.Getenv("EXPLORER_API_KEY")
+		} else {
+			key := os.Getenv("ETHERSCAN_API_KEY")
+			if key == "" {
+				return errors.New("ETHERSCAN_API_KEY is required for the Etherscan API, or set --api/EXPLORER_API to a Blockscout /api URL")
+			}
+			// 不在链预设中的链（chainId 为 0）向节点查询链 ID
+			chainID := preset.ChainID
+			if chainID == 0 {
+				id, err := client.ChainID(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to get chain ID: %w", err)
+				}
+				chainID = id.Uint64()
+			}
+			c = explorer.NewEtherscan(key, chainID)
+		}
+		logger("api").Debug("fetching account history", "address", addr.Hex(), "kinds", kinds, "api", endpointHosts(c.APIBase))
+		transfers, err := c.History(ctx, addr, kinds, explorer.Options{
+			Page: *page, Limit: *limit, StartBlock: *startBlock, EndBlock: *endBlock,
+		})
+		if err != nil {
+			return err
+		}
+		if jsonStdout != nil {
+			out := make([]historyJSON, len(transfers))
+			for i, t := range transfers {
+				out[i] = newHistoryJSON(t)
+			}
+			return emit(out)
+		}
+		if len(transfers) == 0 {
+			fmt.Printf("No transactions found for %s\n", addr.Hex())
+			return nil
+		}
+
+		var others []common.Address
+		for _, t := range transfers {
+			others = append(others, counterparty(t, addr))
+		}
+		label := addressLabels(ctx, client, preset, others...)
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "BLOCK\tTIME\tKIND\tHASH\tDIR\tCOUNTERPARTY\tAMOUNT\tSTATUS")
+		for _, t := range transfers {
+			dir := "OUT"
+			switch {
+			case t.From == addr && t.To != nil && *t.To == addr:
+				dir = "SELF"
+			case t.From != addr:
+				dir = "IN"
+			}
+			other := label(counterparty(t, addr))
+			if t.To == nil {
+				other = "(create " + ens.Short(t.Contract) + ")"
+			}
+			amount := units.FormatUnits(t.Value, preset.Currency.Decimals) + " " + preset.Currency.Symbol
+			if t.Token != nil {
+				amount = units.FormatUnits(t.Value, t.Token.Decimals) + " " + t.Token.Symbol
+			}
+			status := "ok"
+			if t.Failed {
+				status = "failed"
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s…\t%s\t%s\t%s\t%s\n", t.Block, t.Time.Local().Format("2006-01-02 15:04"),
+				t.Kind, t.Hash.Hex()[:10], dir, other, amount, status)
+		}
+		return tw.Flush()
+	}
+	return cmd
+}
+
+// counterparty 返回记录中 addr 之外的另一方，部署合约时为新合约
+func counterparty(t explorer.Transfer, addr common.Address) common.Address {
+	switch {
+	case t.To == nil:
+		return t.Contract
+	case t.From == addr:
+		return *t.To
+	}
+	return t.From
+}
+
+// historyJSON 是 --output json 时的一条记录，数量为十进制字符串
+type historyJSON struct {
+	Kind     explorer.Kind   `json:"kind"`
+	Hash     common.Hash     `json:"hash"`
+	Block    uint64          `json:"block"`
+	Time     time.Time       `json:"time"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Value    string          `json:"value"`
+	Failed   bool            `json:"failed"`
+	Method   string          `json:"method,omitempty"`
+	Contract *common.Address `json:"contract,omitempty"` // 代币合约或新部署的合约
+	Token    *explorer.Token `json:"token,omitempty"`
+}
+
+func newHistoryJSON(t explorer.Transfer) historyJSON {
+	h := historyJSON{
+		Kind: t.Kind, Hash: t.Hash, Block: t.Block, Time: t.Time.UTC(), From: t.From, To: t.To,
+		Value: t.Value.String(), Failed: t.Failed, Method: t.Method, Token: t.Token,
+	}
+	if t.Contract != (common.Address{}) {
+		h.Contract = &t.Contract
+	}
+	return h
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/addressbook"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// loadAddressBook 读取 $ADDRESS_BOOK（默认 addressbook.json）中的地址簿
+func loadAddressBook() (*addressbook.Book, error) {
+	return addressbook.Load(envOr("ADDRESS_BOOK", "addressbook.json"))
+}
+
+// resolveAddress 把命令行中的地址解析为地址：十六进制地址直接使用，否则在地址簿中按所连链查找名称
+func resolveAddress(ctx context.Context, client chain.Client, s string) (common.Address, error) {
+	if strings.HasPrefix(s, "0x") || common.IsHexAddress(s) {
+		return address.ParseHex(s)
+	}
+	book, err := loadAddressBook()
+	if err != nil {
+		return common.Address{}, err
+	}
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return book.Resolve(s, id.Uint64())
+}
+
+// bookNames 返回地址在所连链上的地址簿名称查询函数，地址簿无法读取时只给出警告
+func bookNames(ctx context.Context, client chain.Client) func(common.Address) string {
+	book, err := loadAddressBook()
+	if err != nil {
+		logger("config").Warn("address book unavailable", "err", err)
+		book = &addressbook.Book{}
+	}
+	var chainID uint64
+	if len(book.Entries) > 0 {
+		if id, err := client.ChainID(ctx); err == nil {
+			chainID = id.Uint64()
+		}
+	}
+	return func(addr common.Address) string {
+		return book.Name(addr, chainID)
+	}
+}
+
+// addressBookChainFlag 注册 --chain-id，返回的函数在解析参数后取值：未指定时为 NETWORK（或 --chain）选择的链，
+// 未选择网络时为 0（所有链通用）
+func addressBookChainFlag(fs *pflag.FlagSet) func() uint64 {
+	id := fs.Uint64("chain-id", 0, "chain the name applies to, 0 for all chains (default: chain of $NETWORK, else 0)")
+	return func() uint64 {
+		if p, ok := selectedNetwork(); ok && !fs.Changed("chain-id") {
+			return p.ChainID
+		}
+		return *id
+	}
+}
+
+// addressBookAdd 添加或更新地址簿中的名称
+func addressBookAdd() *cobra.Command {
+	cmd := &cobra.Command{Use: "add <name> <address>", Short: "Add or update a name in the address book"}
+	fs := cmd.Flags()
+	chainIDFlag := addressBookChainFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 2 {
+			return fmt.Errorf("usage: addressbook add [--chain-id N] <name> <address>")
+		}
+		chainID := chainIDFlag()
+		addr, err := address.ParseHex(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		book, err := loadAddressBook()
+		if err != nil {
+			return err
+		}
+		name := fs.Arg(0)
+		replaced, err := book.Add(name, addr, chainID)
+		if err != nil {
+			return err
+		}
+		if err := book.Save(); err != nil {
+			return err
+		}
+		if replaced {
+			fmt.Printf("Updated %s -> %s (%s)\n", name, addr.Hex(), chainLabel(chainID))
+		} else {
+			fmt.Printf("Saved %s -> %s (%s)\n", name, addr.Hex(), chainLabel(chainID))
+		}
+		return nil
+	}
+	return cmd
+}
+
+// addressBookList 列出地址簿，--chain-id 只显示对该链有效的名称
+func addressBookList() *cobra.Command {
+	cmd := &cobra.Command{Use: "list", Short: "List the address book"}
+	fs := cmd.Flags()
+	chainID := fs.Uint64("chain-id", 0, "only show names usable on this chain (default: all entries)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		book, err := loadAddressBook()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tADDRESS\tCHAIN")
+		for _, e := range book.Entries {
+			if *chainID != 0 && e.ChainID != 0 && e.ChainID != *chainID {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Name, e.Address.Hex(), chainLabel(e.ChainID))
+		}
+		return w.Flush()
+	}
+	return cmd
+}
+
+// addressBookRemove 删除地址簿中的名称
+func addressBookRemove() *cobra.Command {
+	cmd := &cobra.Command{Use: "remove <name>", Short: "Remove a name from the address book"}
+	fs := cmd.Flags()
+	chainIDFlag := addressBookChainFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: addressbook remove [--chain-id N] <name>")
+		}
+		chainID := chainIDFlag()
+		book, err := loadAddressBook()
+		if err != nil {
+			return err
+		}
+		if err := book.Remove(fs.Arg(0), chainID); err != nil {
+			return err
+		}
+		if err := book.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s (%s)\n", fs.Arg(0), chainLabel(chainID))
+		return nil
+	}
+	return cmd
+}
+
+// chainLabel 显示条目适用的链，已知链 ID 附带网络名称
+func chainLabel(chainID uint64) string {
+	if chainID == 0 {
+		return "all chains"
+	}
+	if registry, err := loadNetworks(); err == nil {
+		if p, ok := registry.ByChainID(new(big.Int).SetUint64(chainID)); ok {
+			return fmt.Sprintf("%s, chain %d", p.Name, chainID)
+		}
+	}
+	return fmt.Sprintf("chain %d", chainID)
+}
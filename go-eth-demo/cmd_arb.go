@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/pkg/arbitrum"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// arbFlags 是 arb 命令共用的参数
+type arbFlags struct {
+	network *string
+	l1RPC   *string
+	l2RPC   *string
+}
+
+func newArbFlags(fs *pflag.FlagSet) arbFlags {
+	return arbFlags{
+		network: fs.String("network", "arbitrum-sepolia", "Arbitrum network: arbitrum-one, arbitrum-nova or arbitrum-sepolia"),
+		l1RPC:   fs.String("l1-rpc", "", "L1 RPC endpoint (default --rpc)"),
+		l2RPC:   fs.String("l2-rpc", "", "L2 RPC endpoint (default $<NETWORK>_RPC, e.g. ARBITRUM_SEPOLIA_RPC)"),
+	}
+}
+
+func (f arbFlags) dial(ctx context.Context) (arbitrum.Network, *ethclient.Client, *ethclient.Client, error) {
+	net, err := arbitrum.LookupNetwork(*f.network)
+	if err != nil {
+		return arbitrum.Network{}, nil, nil, err
+	}
+	l1, l2, err := dialPair(ctx, *f.network, *f.l1RPC, *f.l2RPC, net.L1ChainID, net.L2ChainID)
+	return net, l1, l2, err
+}
+
+// arbRetryable 通过 Inbox 创建可重试票据，在 L2 上调用 --to（默认把 --value 转给自己）
+func arbRetryable() *cobra.Command {
+	cmd := &cobra.Command{Use: "retryable", Short: "Create a retryable ticket through the Arbitrum Inbox"}
+	fs := cmd.Flags()
+	af := newArbFlags(fs)
+	to := fs.String("to", "", "L2 call target (default: sender)")
+	valueStr := fs.String("value", "0", "value sent with the L2 call, e.g. 0.01eth")
+	dataHex := fs.String("data", "", "L2 calldata (hex)")
+	gasLimit := fs.Uint64("gas-limit", 0, "L2 gas limit (default: estimated)")
+	noRedeem := fs.Bool("no-redeem", false, "create the ticket without auto-redeem, redeem it later with `arb redeem`")
+	wait := fs.Bool("wait", true, "wait for the ticket to be created and redeemed on L2")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		value, err := units.ParseAmount(*valueStr)
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		net, l1, l2, err := af.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer l1.Close()
+		defer l2.Close()
+		auth, err := loadTransactor(ctx, l1)
+		if err != nil {
+			return err
+		}
+		target, err := recipientOrSelf(*to, auth)
+		if err != nil {
+			return err
+		}
+
+		r := &arbitrum.Retryable{From: auth.From, To: target, L2CallValue: value, Data: common.FromHex(*dataHex), GasLimit: *gasLimit}
+		if err := arbitrum.Estimate(ctx, l1, l2, net, r); err != nil {
+			return err
+		}
+		if *noRedeem {
+			// gas 上限和费用为零时票据只会被创建，不会自动执行
+			r.GasLimit, r.MaxFeePerGas = 0, new(big.Int)
+		}
+		fmt.Printf("Submission cost: %s ETH (max)\n", units.FormatUnits(r.MaxSubmissionCost, 18))
+		fmt.Printf("L2 gas:          %d @ %s gwei (max)\n", r.GasLimit, units.FormatUnits(r.MaxFeePerGas, 9))
+		fmt.Printf("Deposit:         %s ETH (excess refunded to %s on L2)\n", units.FormatUnits(r.Deposit(), 18), auth.From.Hex())
+
+		tx, err := arbitrum.CreateRetryableTicket(ctx, l1, net, auth, r)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("L1 tx: %s\n", tx.Hash().Hex())
+		receipt, err := waitSuccess(ctx, l1, tx)
+		if err != nil {
+			return err
+		}
+		tickets, err := arbitrum.ParseTickets(receipt, net)
+		if err != nil {
+			return err
+		}
+		if len(tickets) == 0 {
+			return fmt.Errorf("no retryable ticket in %s", tx.Hash().Hex())
+		}
+		ticketID := tickets[0].ID()
+		fmt.Printf("Ticket ID: %s\n", ticketID.Hex())
+		if !*wait {
+			return nil
+		}
+
+		fmt.Println("Waiting for the ticket on L2 (usually ~10 minutes)...")
+		waitCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+		defer cancel()
+		for {
+			p, err := arbitrum.GetStatus(waitCtx, l2, ticketID)
+			if err != nil {
+				return err
+			}
+			if p.Status != arbitrum.StatusNotCreated {
+				printTicketStatus(p)
+				return nil
+			}
+			select {
+			case <-waitCtx.Done():
+				return fmt.Errorf("wait for ticket %s: %w", ticketID.Hex(), waitCtx.Err())
+			case <-time.After(10 * time.Second):
+			}
+		}
+	}
+	return cmd
+}
+
+// arbStatus 根据 L1 交易哈希报告其中所有票据的状态
+func arbStatus() *cobra.Command {
+	cmd := &cobra.Command{Use: "status <l1 tx hash>", Short: "Report the status of the tickets created by an L1 transaction"}
+	fs := cmd.Flags()
+	af := newArbFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: arb status [flags] <l1 tx hash>")
+		}
+
+		ctx := rootCtx
+		net, l1, l2, err := af.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer l1.Close()
+		defer l2.Close()
+		hash := common.HexToHash(fs.Arg(0))
+		receipt, err := l1.TransactionReceipt(ctx, hash)
+		if err != nil {
+			return fmt.Errorf("failed to get L1 receipt %s: %w", hash.Hex(), err)
+		}
+		tickets, err := arbitrum.ParseTickets(receipt, net)
+		if err != nil {
+			return err
+		}
+		if len(tickets) == 0 {
+			return fmt.Errorf("no retryable ticket in %s", hash.Hex())
+		}
+		for _, t := range tickets {
+			p, err := arbitrum.GetStatus(ctx, l2, t.ID())
+			if err != nil {
+				return err
+			}
+			printTicketStatus(p)
+		}
+		return nil
+	}
+	return cmd
+}
+
+// arbRedeem 在 L2 上手动兑现自动兑现失败的票据
+func arbRedeem() *cobra.Command {
+	cmd := &cobra.Command{Use: "redeem <ticket id>", Short: "Redeem a ticket on L2 after its auto-redeem failed"}
+	fs := cmd.Flags()
+	af := newArbFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: arb redeem [flags] <ticket id>")
+		}
+
+		ctx := rootCtx
+		_, l1, l2, err := af.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer l1.Close()
+		defer l2.Close()
+		ticketID := common.HexToHash(fs.Arg(0))
+		p, err := arbitrum.GetStatus(ctx, l2, ticketID)
+		if err != nil {
+			return err
+		}
+		if p.Status != arbitrum.StatusRedeemable {
+			return fmt.Errorf("ticket is %s, not %s", p.Status, arbitrum.StatusRedeemable)
+		}
+		auth, err := loadTransactor(ctx, l2)
+		if err != nil {
+			return err
+		}
+		tx, err := arbitrum.Redeem(ctx, l2, auth, ticketID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Redeem tx: %s\n", tx.Hash().Hex())
+		if _, err := waitSuccess(ctx, l2, tx); err != nil {
+			return err
+		}
+		fmt.Println("✅ Ticket redeemed")
+		return nil
+	}
+	return cmd
+}
+
+func printTicketStatus(p *arbitrum.Progress) {
+	fmt.Printf("Ticket %s: %s\n", p.TicketID.Hex(), p.Status)
+	switch p.Status {
+	case arbitrum.StatusRedeemed:
+		fmt.Printf("Redeemed by L2 tx: %s\n", p.RedeemTx.Hex())
+	case arbitrum.StatusRedeemable:
+		fmt.Printf("Redeem with `arb redeem` before %s\n", p.Timeout.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/pkg/blockfetch"
+	"github.com/local/go-eth-demo/pkg/export"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// rangeFlags 是按区块范围批量下载的参数，blocks fetch 和 export 命令共用
+type rangeFlags struct {
+	from     *uint64
+	to       *int64
+	workers  *int
+	attempts *int
+	quiet    *bool
+}
+
+func newRangeFlags(fs *pflag.FlagSet) rangeFlags {
+	return rangeFlags{
+		from:     fs.Uint64("from", 0, "first block"),
+		to:       fs.Int64("to", -1, "last block (default: latest)"),
+		workers:  fs.Int("workers", 8, "number of concurrent requests"),
+		attempts: fs.Int("attempts", 3, "attempts per block before giving up"),
+		quiet:    fs.Bool("quiet", false, "do not print progress"),
+	}
+}
+
+// fetch 按参数开始下载，--to 未设置时下载到最新区块。进度每秒写到标准错误，重试以警告记录
+func (f rangeFlags) fetch(ctx context.Context, client *ethclient.Client, opts blockfetch.Options) (*blockfetch.Stream, error) {
+	last := uint64(*f.to)
+	if *f.to < 0 {
+		var err error
+		if last, err = client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get latest block: %w", err)
+		}
+	}
+	if *f.from > last {
+		return nil, fmt.Errorf("--from %d is after --to %d", *f.from, last)
+	}
+	opts.Workers, opts.Attempts = *f.workers, *f.attempts
+	opts.OnRetry = func(number uint64, attempt int, err error) {
+		logger("rpc").Warn("failed to fetch block, retrying", "block", number, "attempt", attempt, "err", err)
+	}
+	var lastReport time.Time
+	opts.OnProgress = func(p blockfetch.Progress) {
+		if *f.quiet || (time.Since(lastReport) < time.Second && p.Done != p.Total) {
+			return
+		}
+		lastReport = time.Now()
+		fmt.Fprintf(os.Stderr, "Fetched %d/%d blocks (%.1f blocks/s, %s left)\n",
+			p.Done, p.Total, p.Rate(), p.Remaining().Round(time.Second))
+	}
+	return blockfetch.Fetch(ctx, client, *f.from, last, opts), nil
+}
+
+// blocksFetch 并发下载一段区块范围，按区块号顺序每个区块输出一行摘要，进度每秒写到标准错误
+func blocksFetch() *cobra.Command {
+	cmd := &cobra.Command{Use: "fetch", Short: "Fetch a block range concurrently and print one line per block"}
+	fs := cmd.Flags()
+	rf := newRangeFlags(fs)
+	receipts := fs.Bool("receipts", false, "also fetch receipts (needs eth_getBlockReceipts)")
+	headers := fs.Bool("headers", false, "fetch headers only, without transactions")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 0 {
+			return errors.New("usage: blocks fetch [flags]")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		s, err := rf.fetch(ctx, client, blockfetch.Options{HeadersOnly: *headers, Receipts: *receipts})
+		if err != nil {
+			return err
+		}
+		defer s.Stop()
+
+		fmt.Printf("%-10s %-66s %-19s %5s %12s", "BLOCK", "HASH", "TIME", "TXS", "GAS USED")
+		if *receipts {
+			fmt.Printf(" %6s", "FAILED")
+		}
+		fmt.Println()
+		for r := range s.C {
+			txs := "-"
+			if r.Block != nil {
+				txs = fmt.Sprint(len(r.Block.Transactions()))
+			}
+			fmt.Printf("%-10d %-66s %-19s %5s %12d", r.Number, r.Header.Hash().Hex(),
+				time.Unix(int64(r.Header.Time), 0).Local().Format("2006-01-02 15:04:05"), txs, r.Header.GasUsed)
+			if *receipts {
+				failed := 0
+				for _, receipt := range r.Receipts {
+					if receipt.Status == types.ReceiptStatusFailed {
+						failed++
+					}
+				}
+				fmt.Printf(" %6d", failed)
+			}
+			fmt.Println()
+		}
+		return s.Err()
+	}
+	return cmd
+}
+
+// exportBlocks 把一段区块导出为 CSV 或 JSON Lines，每个区块一行
+func exportBlocks() *cobra.Command {
+	cmd := &cobra.Command{Use: "blocks", Short: "Export a block range as CSV or JSON Lines"}
+	fs := cmd.Flags()
+	rf := newRangeFlags(fs)
+	out := fs.StringP("output", "o", "", "output file (default stdout)")
+	format := fs.String("format", "", "csv or jsonl (default from the --output extension, otherwise csv)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 0 {
+			return errors.New("usage: export blocks [flags]")
+		}
+		return runExport(rf, *out, *format, export.BlockColumns, blockfetch.Options{HeadersOnly: true},
+			func(r blockfetch.Result, _ types.Signer, w *export.Writer) error {
+				return w.Write(export.BlockRow(r.Header, -1))
+			})
+	}
+	return cmd
+}
+
+// exportTxs 把一段区块中的交易导出为 CSV 或 JSON Lines，每笔交易一行，gas 用量、费用和状态取自收据
+func exportTxs() *cobra.Command {
+	cmd := &cobra.Command{Use: "txs", Short: "Export the transactions in a block range as CSV or JSON Lines"}
+	fs := cmd.Flags()
+	rf := newRangeFlags(fs)
+	out := fs.StringP("output", "o", "", "output file (default stdout)")
+	format := fs.String("format", "", "csv or jsonl (default from the --output extension, otherwise csv)")
+	noReceipts := fs.Bool("no-receipts", false, "skip receipts: faster, but gas used, fees and status are empty")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 0 {
+			return errors.New("usage: export txs [flags]")
+		}
+		return runExport(rf, *out, *format, export.TxColumns, blockfetch.Options{Receipts: !*noReceipts},
+			func(r blockfetch.Result, signer types.Signer, w *export.Writer) error {
+				for _, row := range export.TxRows(r.Block, r.Receipts, signer) {
+					if err := w.Write(row); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+	}
+	return cmd
+}
+
+// runExport 下载区块并用 write 把每个区块写成若干行。中断或出错时已写出的行保留在文件中
+func runExport(rf rangeFlags, path, formatName string, columns []string, opts blockfetch.Options,
+	write func(blockfetch.Result, types.Signer, *export.Writer) error) error {
+	format, err := export.ParseFormat(formatName, path)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	client, err := dial(ctx, rpcEndpoint())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	signer := types.LatestSignerForChainID(chainID)
+
+	var dst io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		dst = f
+	}
+	w, err := export.NewWriter(dst, format, columns)
+	if err != nil {
+		return err
+	}
+	s, err := rf.fetch(ctx, client, opts)
+	if err != nil {
+		return err
+	}
+	defer s.Stop()
+	for r := range s.C {
+		if err := write(r, signer, w); err != nil {
+			w.Flush()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if path != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d rows to %s\n", w.Rows(), path)
+	}
+	return s.Err()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/arbitrum"
+	"github.com/local/go-eth-demo/pkg/bridge"
+	"github.com/local/go-eth-demo/pkg/opstack"
+	"github.com/spf13/cobra"
+)
+
+// bridgeStatus 跟踪 L1 或 L2 上的桥交易，报告跨链消息所处的阶段
+func bridgeStatus() *cobra.Command {
+	cmd := &cobra.Command{Use: "status <l1 or l2 tx hash>", Short: "Report the stage of a bridge message"}
+	fs := cmd.Flags()
+	network := fs.String("network", "op-sepolia", "L2 network (OP Stack or Arbitrum)")
+	l1RPC := fs.String("l1-rpc", "", "L1 RPC endpoint (default --rpc)")
+	l2RPC := fs.String("l2-rpc", "", "L2 RPC endpoint (default $<NETWORK>_RPC)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: bridge status [flags] <l1 or l2 tx hash>")
+		}
+		tx := common.HexToHash(fs.Arg(0))
+
+		ctx := rootCtx
+		var transfer *bridge.Transfer
+		if net, ok := opstack.Networks[*network]; ok {
+			l1, l2, err := dialPair(ctx, *network, *l1RPC, *l2RPC, net.L1ChainID, net.L2ChainID)
+			if err != nil {
+				return err
+			}
+			defer l1.Close()
+			defer l2.Close()
+			if transfer, err = bridge.TrackOPStack(ctx, l1, l2, net, tx); err != nil {
+				return err
+			}
+		} else if net, ok := arbitrum.Networks[*network]; ok {
+			l1, l2, err := dialPair(ctx, *network, *l1RPC, *l2RPC, net.L1ChainID, net.L2ChainID)
+			if err != nil {
+				return err
+			}
+			defer l1.Close()
+			defer l2.Close()
+			if transfer, err = bridge.TrackArbitrum(ctx, l1, l2, net, tx); err != nil {
+				return err
+			}
+		} else {
+			var names []string
+			for name := range opstack.Networks {
+				names = append(names, name)
+			}
+			for name := range arbitrum.Networks {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown bridge network %q (known: %s)", *network, strings.Join(names, ", "))
+		}
+
+		fmt.Printf("Network:   %s\n", transfer.Network)
+		fmt.Printf("Direction: %s\n", transfer.Direction)
+		fmt.Printf("Stage:     %s\n", transfer.Stage)
+		if transfer.Detail != "" {
+			fmt.Printf("Detail:    %s\n", transfer.Detail)
+		}
+		if !transfer.ReadyAt.IsZero() {
+			fmt.Printf("Ready at:  %s (in %s)\n", transfer.ReadyAt.Format(time.RFC3339), time.Until(transfer.ReadyAt).Round(time.Minute))
+		}
+		if transfer.TargetTx != (common.Hash{}) {
+			fmt.Printf("Target tx: %s\n", transfer.TargetTx.Hex())
+		}
+		return nil
+	}
+	return cmd
+}
@@ -0,0 +1,477 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/devnet"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// devnetUp 启动本地开发链，之后 NETWORK=local 的命令都会连接到它
+func devnetUp() *cobra.Command {
+	cmd := &cobra.Command{Use: "up", Short: "Start a local development chain"}
+	fs := cmd.Flags()
+	cfg := devnetConfigFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		st, err := devnet.Up(context.Background(), *cfg)
+		if err != nil {
+			return err
+		}
+		printDevnet(st)
+		return nil
+	}
+	return cmd
+}
+
+// devnetConfigFlags 注册 devnet up/fork 共用的节点参数
+func devnetConfigFlags(fs *pflag.FlagSet) *devnet.Config {
+	cfg := &devnet.Config{Dir: devnet.DefaultDir}
+	fs.StringVar(&cfg.Kind, "kind", devnet.KindAnvil, "node implementation: anvil or hardhat")
+	fs.IntVar(&cfg.Port, "port", 8545, "RPC port")
+	fs.Uint64Var(&cfg.ChainID, "chain-id", 31337, "chain ID (anvil only)")
+	fs.IntVar(&cfg.Accounts, "accounts", 10, "number of funded accounts (anvil only)")
+	fs.Uint64Var(&cfg.Balance, "balance", 10000, "ETH balance of each funded account (anvil only)")
+	return cfg
+}
+
+func printDevnet(st *devnet.State) {
+	fmt.Printf("Devnet (%s) running, pid %d\n", st.Kind, st.PID)
+	if st.Fork != nil {
+		fmt.Printf("Forked from: %s at block %s\n", st.Fork.Network, forkBlockString(st.Fork.Block))
+	}
+	fmt.Printf("RPC URL: %s\n", st.RPCURL)
+	fmt.Printf("Chain ID: %d\n", st.ChainID)
+	fmt.Println("\n=== Funded Accounts ===")
+	for i, acc := range st.Accounts {
+		fmt.Printf("(%d) %s\n", i, acc.Address)
+	}
+	fmt.Println("\nSet NETWORK=local to run the demos against this node (the first account is used when PRIVATE_KEY is unset).")
+}
+
+func forkBlockString(block uint64) string {
+	if block == 0 {
+		return "latest"
+	}
+	return strconv.FormatUint(block, 10)
+}
+
+// devnetFork 启动一个从真实网络分叉的本地节点，可选地模拟任意账户，
+// 以便在真实状态上预演交易
+func devnetFork() *cobra.Command {
+	cmd := &cobra.Command{Use: "fork", Short: "Start a local node forked from a live network"}
+	fs := cmd.Flags()
+	cfg := devnetConfigFlags(fs)
+	network := fs.String("network", "mainnet", "network to fork; its RPC URL is read from $<NETWORK>_RPC (e.g. MAINNET_RPC)")
+	forkURL := fs.String("fork-url", "", "RPC URL to fork from (overrides --network lookup)")
+	fs.Uint64Var(&cfg.ForkBlock, "block", 0, "block number to fork at (default latest; pin it for reproducible runs)")
+	var impersonate stringList
+	fs.Var(&impersonate, "impersonate", "address to impersonate via anvil_impersonateAccount (repeatable)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg.ForkNetwork = *network
+		cfg.ForkURL = *forkURL
+		if cfg.ForkURL == "" {
+			cfg.ForkURL = networkRPCURL(*network)
+		}
+		if cfg.ForkURL == "" {
+			return fmt.Errorf("no RPC URL for network %q: set %s or use --fork-url", *network, networkRPCEnv(*network))
+		}
+		var addrs []common.Address
+		for _, a := range impersonate {
+			addr, err := address.ParseHex(a)
+			if err != nil {
+				return fmt.Errorf("impersonate: %w", err)
+			}
+			addrs = append(addrs, addr)
+		}
+
+		ctx := rootCtx
+		st, err := devnet.Up(ctx, *cfg)
+		if err != nil {
+			return err
+		}
+		if len(addrs) > 0 {
+			client, err := rpc.DialContext(ctx, st.RPCURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to devnet: %w", err)
+			}
+			defer client.Close()
+			for _, addr := range addrs {
+				if err := devnet.Impersonate(ctx, devnet.DefaultDir, client, st, addr); err != nil {
+					return err
+				}
+			}
+		}
+		printDevnet(st)
+		for _, a := range st.Impersonated {
+			fmt.Printf("Impersonating: %s (send with `devnet send-as`)\n", a)
+		}
+		return nil
+	}
+	return cmd
+}
+
+// devnetImpersonate 在运行中的本地节点上模拟账户
+func devnetImpersonate() *cobra.Command {
+	cmd := &cobra.Command{Use: "impersonate <address>", Short: "Impersonate an account on the local node"}
+	fs := cmd.Flags()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: devnet impersonate <address>")
+		}
+		addr, err := address.ParseHex(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		st, client, err := dialDevnet(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		if err := devnet.Impersonate(ctx, devnet.DefaultDir, client, st, addr); err != nil {
+			return err
+		}
+		fmt.Printf("Impersonating %s\n", addr.Hex())
+		return nil
+	}
+	return cmd
+}
+
+// devnetSendAs 以被模拟的账户发送交易
+func devnetSendAs() *cobra.Command {
+	cmd := &cobra.Command{Use: "send-as", Short: "Send a transaction as an impersonated account"}
+	fs := cmd.Flags()
+	from := fs.String("from", "", "impersonated sender address")
+	to := fs.String("to", "", "recipient or contract address")
+	valueWei := fs.String("value", "0", "value in wei")
+	data := fs.String("data", "", "hex-encoded calldata")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if *from == "" || *to == "" {
+			return fmt.Errorf("usage: devnet send-as --from <address> --to <address> [--value wei] [--data 0x...]")
+		}
+		sender, err := address.ParseHex(*from)
+		if err != nil {
+			return fmt.Errorf("--from: %w", err)
+		}
+		recipient, err := address.ParseHex(*to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+		value, ok := new(big.Int).SetString(*valueWei, 10)
+		if !ok {
+			return fmt.Errorf("invalid value: %s", *valueWei)
+		}
+
+		ctx := rootCtx
+		st, client, err := dialDevnet(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		if !slices.Contains(st.Impersonated, sender.Hex()) {
+			return fmt.Errorf("%s is not impersonated, run `devnet impersonate %s` first", sender.Hex(), sender.Hex())
+		}
+
+		hash, err := devnet.SendAs(ctx, client, sender, recipient, value, common.FromHex(*data))
+		if err != nil {
+			return err
+		}
+		receipt, err := ethclient.NewClient(client).TransactionReceipt(ctx, hash)
+		if err != nil {
+			fmt.Printf("Transaction sent: %s (receipt not yet available: %v)\n", hash.Hex(), err)
+			return nil
+		}
+		fmt.Printf("Transaction %s mined in block %d, status %d, gas used %d\n",
+			hash.Hex(), receipt.BlockNumber.Uint64(), receipt.Status, receipt.GasUsed)
+		return nil
+	}
+	return cmd
+}
+
+// devnetFund 给任意账户充值，例如 devnet fund 0x... 10eth
+func devnetFund() *cobra.Command {
+	cmd := &cobra.Command{Use: "fund <address> <amount>", Short: "Fund any account on the local node"}
+	fs := cmd.Flags()
+	transfer := fs.Bool("transfer", false, "send a transfer from the first dev account instead of setting the balance")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 2 {
+			return fmt.Errorf("usage: devnet fund [--transfer] <address> <amount, e.g. 10eth>")
+		}
+		addr, err := address.ParseHex(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		amount, err := units.ParseAmount(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		st, client, err := dialDevnet(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		hash, err := devnet.Fund(ctx, client, st, addr, amount, *transfer)
+		if err != nil {
+			return err
+		}
+		balance, err := ethclient.NewClient(client).BalanceAt(ctx, addr, nil)
+		if err != nil {
+			return err
+		}
+		if *transfer {
+			fmt.Printf("Transfer: %s\n", hash.Hex())
+		}
+		fmt.Printf("Funded %s with %s ETH, balance now %s ETH\n", addr.Hex(), weiToEth(amount), weiToEth(balance))
+		return nil
+	}
+	return cmd
+}
+
+// stringList 是可重复的字符串参数
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+func (l *stringList) Type() string       { return "strings" }
+
+// devnetDown 停止本地开发链
+func devnetDown() *cobra.Command {
+	cmd := &cobra.Command{Use: "down", Short: "Stop the local development chain"}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := devnet.Down(ctx, devnet.DefaultDir); err != nil {
+			return err
+		}
+		fmt.Println("Devnet stopped")
+		return nil
+	}
+	return cmd
+}
+
+// dialDevnet 连接正在运行的本地节点
+func dialDevnet(ctx context.Context) (*devnet.State, *rpc.Client, error) {
+	st, err := devnet.Load(devnet.DefaultDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := rpc.DialContext(ctx, st.RPCURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to devnet: %w", err)
+	}
+	return st, client, nil
+}
+
+// devnetSnapshotSave 保存当前链状态为命名快照
+func devnetSnapshotSave() *cobra.Command {
+	cmd := &cobra.Command{Use: "save <name>", Short: "Save the chain state as a named snapshot"}
+	fs := cmd.Flags()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: devnet snapshot save <name>")
+		}
+
+		ctx := rootCtx
+		_, client, err := dialDevnet(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		snap, err := devnet.TakeSnapshot(ctx, devnet.DefaultDir, client, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Snapshot %q saved at block %d (id %s)\n", snap.Name, snap.Block, snap.ID)
+		return nil
+	}
+	return cmd
+}
+
+// devnetSnapshotRevert 回滚到命名快照
+func devnetSnapshotRevert() *cobra.Command {
+	cmd := &cobra.Command{Use: "revert <name>", Short: "Revert to a named snapshot"}
+	fs := cmd.Flags()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: devnet snapshot revert <name>")
+		}
+
+		ctx := rootCtx
+		_, client, err := dialDevnet(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		snap, err := devnet.RevertSnapshot(ctx, devnet.DefaultDir, client, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted to snapshot %q (block %d)\n", snap.Name, snap.Block)
+		fmt.Println("Note: the snapshot and any taken after it are consumed; save it again to reuse it.")
+		return nil
+	}
+	return cmd
+}
+
+// devnetSnapshotList 列出已保存的快照
+func devnetSnapshotList() *cobra.Command {
+	cmd := &cobra.Command{Use: "list", Short: "List the saved snapshots"}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if _, err := devnet.Load(devnet.DefaultDir); err != nil {
+			return err
+		}
+		snaps, err := devnet.Snapshots(devnet.DefaultDir)
+		if err != nil {
+			return err
+		}
+		if len(snaps) == 0 {
+			fmt.Println("No snapshots")
+			return nil
+		}
+		for _, s := range snaps {
+			fmt.Printf("%-20s block %-8d id %-6s %s\n", s.Name, s.Block, s.ID, s.TakenAt.Format(time.RFC3339))
+		}
+		return nil
+	}
+	return cmd
+}
+
+// devnetTimeIncrease 把链上时间向前推进，默认随即挖一个区块使其生效
+func devnetTimeIncrease() *cobra.Command {
+	cmd := &cobra.Command{Use: "increase <duration>", Short: "Move the chain time forward"}
+	fs := cmd.Flags()
+	mine := fs.Bool("mine", true, "mine a block so the new time takes effect immediately")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: devnet time increase [flags] <duration>  (e.g. 3600, 90m, 24h)")
+		}
+		d, err := parseDuration(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		_, client, err := dialDevnet(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		total, err := devnet.IncreaseTime(ctx, client, d)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Time increased by %s (total offset %ds)\n", d, total)
+		if *mine {
+			return printMined(devnet.Mine(ctx, client, 1))
+		}
+		return nil
+	}
+	return cmd
+}
+
+// devnetTimeSet 指定下一个区块的时间戳
+func devnetTimeSet() *cobra.Command {
+	cmd := &cobra.Command{Use: "set <unix-seconds|RFC3339>", Short: "Set the timestamp of the next block"}
+	fs := cmd.Flags()
+	mine := fs.Bool("mine", true, "mine a block with the new timestamp immediately")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: devnet time set [flags] <unix-seconds|RFC3339>")
+		}
+		ts, err := parseTimestamp(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		st, client, err := dialDevnet(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if err := devnet.SetNextBlockTimestamp(ctx, client, st.Kind, ts); err != nil {
+			return err
+		}
+		fmt.Printf("Next block timestamp set to %s\n", ts.UTC().Format(time.RFC3339))
+		if *mine {
+			return printMined(devnet.Mine(ctx, client, 1))
+		}
+		return nil
+	}
+	return cmd
+}
+
+// devnetMine 立即挖出若干区块
+func devnetMine() *cobra.Command {
+	cmd := &cobra.Command{Use: "mine [count]", Short: "Mine blocks immediately"}
+	fs := cmd.Flags()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		n := 1
+		if fs.NArg() > 0 {
+			var err error
+			if n, err = strconv.Atoi(fs.Arg(0)); err != nil || n < 1 {
+				return fmt.Errorf("usage: devnet mine [count]")
+			}
+		}
+
+		ctx := rootCtx
+		_, client, err := dialDevnet(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		return printMined(devnet.Mine(ctx, client, n))
+	}
+	return cmd
+}
+
+func printMined(number uint64, ts time.Time, err error) error {
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Latest block: %d (timestamp %s)\n", number, ts.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// parseDuration 接受纯秒数或 Go 的 duration 格式
+func parseDuration(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// parseTimestamp 接受 Unix 秒数或 RFC3339 时间
+func parseTimestamp(s string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	ts, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return ts, nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/disburse"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+)
+
+// disburseCmd 按 CSV 清单（每行 address,amount[,memo]）批量转账：校验所有行、显示总金额和最大费用，
+// 然后逐笔发送并等待确认。各行状态保存在状态文件中，中断或失败后用同样的参数重新运行会从未完成的行继续。
+// --disperse 通过 Disperse 合约每笔交易支付 --batch-size 行，加 --token 时分发 ERC-20 代币（金额按代币单位解析）
+func disburseCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "disburse <payouts.csv>", Short: "Pay out a CSV list of transfers, resuming where an earlier run stopped"}
+	fs := cmd.Flags()
+	statePath := fs.String("state", "", "file recording the status of each row (default <file>.state.json)")
+	strategyName := feeStrategyFlag(fs)
+	confirmations := fs.Uint64("confirmations", envUint("WAIT_CONFIRMATIONS", 1), "blocks to wait for each transfer (default $WAIT_CONFIRMATIONS or 1)")
+	dryRun := fs.Bool("dry-run", envBool("DRY_RUN"), "validate the file and print the summary without sending (default $DRY_RUN)")
+	useDisperse := fs.Bool("disperse", false, "pay each batch of rows in one transaction through the Disperse contract")
+	disperseAddr := fs.String("disperse-address", envOr("DISPERSE_ADDR", disburse.DefaultDisperseAddress.Hex()), "Disperse contract address (default $DISPERSE_ADDR or the disperse.app deployment)")
+	tokenAddr := fs.String("token", "", "ERC-20 token to disperse instead of ETH, requires --disperse")
+	batchSize := fs.Int("batch-size", disburse.DefaultBatchSize, "recipients per Disperse transaction")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return errors.New("usage: disburse [flags] <payouts.csv>")
+		}
+		if *tokenAddr != "" && !*useDisperse {
+			return errors.New("--token requires --disperse")
+		}
+		var tokenAddress common.Address
+		if *tokenAddr != "" {
+			var err error
+			if tokenAddress, err = address.ParseHex(*tokenAddr); err != nil {
+				return fmt.Errorf("--token: %w", err)
+			}
+		}
+		disperseAddress, err := address.ParseHex(*disperseAddr)
+		if err != nil {
+			return fmt.Errorf("--disperse-address: %w", err)
+		}
+		path := fs.Arg(0)
+		if *statePath == "" {
+			*statePath = strings.TrimSuffix(path, ".csv") + ".state.json"
+		}
+		strategy, err := ethtx.ParseFeeStrategy(*strategyName)
+		if err != nil {
+			return err
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		dopts := disburse.DisperseOptions{
+			Options:   disburse.Options{Strategy: strategy, Confirmations: *confirmations},
+			Contract:  disperseAddress,
+			BatchSize: *batchSize,
+		}
+		var parseAmount func(string) (*big.Int, error)
+		if *tokenAddr != "" {
+			if dopts.Token, err = erc20.Load(ctx, client, tokenAddress); err != nil {
+				return err
+			}
+			parseAmount = dopts.Token.ParseAmount
+		}
+		payouts, err := disburse.LoadCSV(path, func(s string) (common.Address, error) {
+			return resolveAddress(ctx, client, s)
+		}, parseAmount)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		state, err := disburse.LoadState(*statePath, chainID.Uint64())
+		if err != nil {
+			return err
+		}
+
+		var plan *disburse.Plan
+		if *useDisperse {
+			plan, err = disburse.NewDispersePlan(ctx, client, w.Address(), payouts, state, dopts)
+		} else {
+			plan, err = disburse.NewPlan(ctx, client, w.Address(), payouts, state, strategy)
+		}
+		if err != nil {
+			return err
+		}
+		preset := presetFor(ctx, client)
+		eth := func(v *big.Int) string {
+			return units.FormatUnits(v, preset.Currency.Decimals) + " " + preset.Currency.Symbol
+		}
+		amount := eth
+		if dopts.Token != nil {
+			amount = dopts.Token.Format
+		}
+		fmt.Printf("From:      %s\n", w.Address().Hex())
+		if *useDisperse {
+			fmt.Printf("Disperse:  %s (%d rows per transaction)\n", dopts.Contract.Hex(), *batchSize)
+		}
+		fmt.Printf("Rows:      %d (%d remaining)\n", len(payouts), len(plan.Payouts))
+		fmt.Printf("Amount:    %s\n", amount(plan.Amount))
+		if plan.Token != nil {
+			fmt.Printf("Tokens:    %s\n", amount(plan.TokenBalance))
+		}
+		fmt.Printf("Gas:       %d\n", plan.Gas)
+		fmt.Printf("Max fee:   %s\n", eth(plan.GasFee))
+		fmt.Printf("Max cost:  %s\n", eth(plan.Cost()))
+		fmt.Printf("Balance:   %s\n", eth(plan.Balance))
+		fmt.Printf("State:     %s\n", *statePath)
+		if err := plan.Check(); err != nil {
+			return err
+		}
+		if *dryRun || len(plan.Payouts) == 0 {
+			return nil
+		}
+
+		fmt.Println()
+		dopts.OnSent = func(p disburse.Payout, r *disburse.Row) {
+			fmt.Printf("line %-4d %s → %s  sent %s\n", p.Line, amount(p.Amount), p.To.Hex(), r.Hash.Hex())
+		}
+		dopts.OnDone = func(p disburse.Payout, r *disburse.Row) {
+			fmt.Printf("line %-4d %s in block %d\n", p.Line, r.Status, r.Block)
+		}
+		if *useDisperse {
+			dopts.OnApprove = func(tx *types.Transaction) {
+				fmt.Printf("approve %s for %s  sent %s\n", dopts.Token.Symbol, dopts.Contract.Hex(), tx.Hash().Hex())
+			}
+			err = disburse.RunDisperse(ctx, client, w, payouts, state, dopts)
+		} else {
+			err = disburse.Run(ctx, client, w, payouts, state, dopts.Options)
+		}
+		fmt.Println()
+		if rerr := printDisbursement(payouts, state, amount, eth); rerr != nil {
+			return rerr
+		}
+		if err != nil {
+			return fmt.Errorf("%w (run the same command again to resume)", err)
+		}
+		return nil
+	}
+	return cmd
+}
+
+// printDisbursement 打印每一行的最终状态和已完成行的合计。同一笔 Disperse 交易的多行记录同一个费用，只计一次
+func printDisbursement(payouts []disburse.Payout, state *disburse.State, amount, eth func(*big.Int) string) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tTO\tAMOUNT\tSTATUS\tTX\tMEMO")
+	paid, fees, done := new(big.Int), new(big.Int), 0
+	counted := make(map[common.Hash]bool)
+	for _, p := range payouts {
+		r := state.Row(p)
+		tx := "-"
+		if r.Hash != (common.Hash{}) {
+			tx = r.Hash.Hex()
+		}
+		status := r.Status
+		if r.Error != "" {
+			status += ": " + r.Error
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", p.Line, p.To.Hex(), amount(p.Amount), status, tx, p.Memo)
+		if r.Status == disburse.StatusConfirmed {
+			done++
+			paid.Add(paid, p.Amount)
+		}
+		if fee, ok := new(big.Int).SetString(r.Fee, 10); ok && !counted[r.Hash] {
+			counted[r.Hash] = true
+			fees.Add(fees, fee)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d/%d rows confirmed, paid %s, gas fees %s\n", done, len(payouts), amount(paid), eth(fees))
+	return nil
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/multicall"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/local/go-eth-demo/pkg/wallet"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// callFlags 是发送合约调用（代币、NFT 转账等）的命令共用的费用、访问列表、预演和等待参数
+type callFlags struct {
+	strategy   *string
+	gasBuffer  *int
+	accessList *bool
+	wait       *bool
+	dryRun     *bool
+	waitFlags
+}
+
+func newCallFlags(fs *pflag.FlagSet) callFlags {
+	return callFlags{
+		strategy:   feeStrategyFlag(fs),
+		gasBuffer:  gasBufferFlag(fs),
+		accessList: fs.Bool("access-list", envBool("ACCESS_LIST"), "attach an EIP-2930 access list from eth_createAccessList when it saves gas (default $ACCESS_LIST)"),
+		wait:       fs.Bool("wait", true, "wait for the transaction to be mined"),
+		dryRun:     dryRunFlag(fs),
+		waitFlags:  newWaitFlags(fs),
+	}
+}
+
+// check 在连接节点前检查参数
+func (f callFlags) check() error {
+	if _, err := f.tag(); err != nil {
+		return err
+	}
+	_, err := ethtx.ParseFeeStrategy(*f.strategy)
+	return err
+}
+
+// send 按 --fee-strategy、--gas-buffer 和 --access-list 调整 t，打印 gas 和费用后签名发送（--dry-run 时只打印已签名交易），
+// 按需等待确认并返回收据；没有等待时收据为 nil。t 可以来自返回 ErrInsufficientFunds 的 Prepare，调整后会重新检查余额。
+func (f callFlags) send(ctx context.Context, client *ethclient.Client, w wallet.Signer, t *ethtx.Transfer) (*types.Receipt, error) {
+	strategy, err := ethtx.ParseFeeStrategy(*f.strategy)
+	if err != nil {
+		return nil, err
+	}
+	if strategy != ethtx.StandardFees {
+		if err := t.SetFees(ctx, client, strategy); err != nil {
+			return nil, err
+		}
+	}
+	if *f.gasBuffer != ethtx.DefaultGasBuffer {
+		if err := t.EstimateGas(ctx, client, *f.gasBuffer); err != nil {
+			return nil, err
+		}
+	}
+	if *f.accessList {
+		if err := attachAccessList(ctx, client, t, *f.gasBuffer); err != nil {
+			return nil, err
+		}
+	}
+	if err := adjustForL2(ctx, client, t, *f.gasBuffer, false); err != nil {
+		return nil, err
+	}
+	if err := t.Check(); err != nil {
+		return nil, err
+	}
+
+	preset := presetFor(ctx, client)
+	fmt.Printf("Gas Limit: %d\n", t.GasLimit)
+	printTransferFees(t)
+	fmt.Printf("Max cost:  %s %s\n", units.FormatUnits(t.Cost(), preset.Currency.Decimals), preset.Currency.Symbol)
+
+	if *f.dryRun {
+		tx, err := ethtx.Sign(w, t)
+		if err != nil {
+			return nil, err
+		}
+		return nil, printDryRun(tx)
+	}
+	tx, err := ethtx.Send(ctx, client, w, t)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Transaction: %s\n", tx.Hash().Hex())
+	if url := preset.TxURL(tx.Hash()); url != "" {
+		fmt.Printf("Explorer:    %s\n", url)
+	}
+	if !*f.wait {
+		return nil, nil
+	}
+	return f.waitFlags.wait(ctx, client, tx)
+}
+
+// attachAccessList 用 eth_createAccessList 为 t 生成访问列表并报告估算的 gas 变化，只有能节省 gas 时才附加到交易上，
+// 之后按 buffer 重新估算 gas 上限
+func attachAccessList(ctx context.Context, client *ethclient.Client, t *ethtx.Transfer, buffer int) error {
+	e, err := ethtx.EstimateAccessList(ctx, client, client.Client(), t.CallMsg())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Access list: %d addresses, %d storage keys\n", len(e.AccessList), e.AccessList.StorageKeys())
+	fmt.Printf("Gas estimate: %d without, %d with (saves %d)\n", e.GasWithout, e.GasWith, e.Saving())
+	if e.Saving() <= 0 {
+		fmt.Println("Access list does not save gas, sending without it")
+		return nil
+	}
+	t.AccessList = e.AccessList
+	return t.EstimateGas(ctx, client, buffer)
+}
+
+// erc20Transfer 是 task03 的命令行版本：按代币的 decimals 解析 "12.5 USDC" 形式的数量并调用 transfer
+func erc20Transfer() *cobra.Command {
+	cmd := &cobra.Command{Use: "transfer", Short: "Transfer ERC-20 tokens"}
+	fs := cmd.Flags()
+	tokenAddr := fs.String("token", os.Getenv("TOKEN_ADDR"), "ERC-20 token address (default $TOKEN_ADDR)")
+	to := fs.String("to", os.Getenv("RECIPIENT_ADDR"), "recipient address or address book name (default $RECIPIENT_ADDR)")
+	amountStr := fs.String("amount", os.Getenv("TOKEN_AMOUNT"), "amount in token units, e.g. 12.5 or \"12.5 USDC\" (default $TOKEN_AMOUNT)")
+	cf := newCallFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		tokenAddress, err := address.ParseHex(*tokenAddr)
+		if err != nil {
+			return fmt.Errorf("--token: %w", err)
+		}
+		if *to == "" {
+			return errors.New("--to or RECIPIENT_ADDR is required")
+		}
+		if *amountStr == "" {
+			return errors.New("--amount or TOKEN_AMOUNT is required")
+		}
+		if err := cf.check(); err != nil {
+			return err
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		recipient, err := resolveAddress(ctx, client, *to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+
+		token, err := erc20.Load(ctx, client, tokenAddress)
+		if err != nil {
+			return err
+		}
+		amount, err := token.ParseAmount(*amountStr)
+		if err != nil {
+			return err
+		}
+		t, err := token.Prepare(ctx, client, w.Address(), recipient, amount)
+		if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			return err
+		}
+		fmt.Printf("Token:     %s (%s, %d decimals)\n", token.Address.Hex(), token.Symbol, token.Decimals)
+		label := addressLabels(ctx, client, presetFor(ctx, client), t.From, recipient)
+		fmt.Printf("From:      %s\n", label(t.From))
+		fmt.Printf("To:        %s\n", label(recipient))
+		fmt.Printf("Amount:    %s\n", token.Format(amount))
+		_, err = cf.send(ctx, client, w, t)
+		return err
+	}
+	return cmd
+}
+
+// tokenInfo 显示代币的 name、symbol、decimals、totalSupply，以及 --account 的余额
+func tokenInfo() *cobra.Command {
+	cmd := &cobra.Command{Use: "info <token address>...", Short: "Show an ERC-20 token's metadata and balance"}
+	fs := cmd.Flags()
+	account := fs.String("account", "", "also show the balance of this address")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() == 0 {
+			return fmt.Errorf("usage: token info [flags] <token address>...")
+		}
+		var tokens []common.Address
+		for _, arg := range fs.Args() {
+			addr, err := address.ParseHex(arg)
+			if err != nil {
+				return fmt.Errorf("token: %w", err)
+			}
+			tokens = append(tokens, addr)
+		}
+		var owner *common.Address
+		if *account != "" {
+			addr, err := address.ParseHex(*account)
+			if err != nil {
+				return fmt.Errorf("--account: %w", err)
+			}
+			owner = &addr
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if len(tokens) > 1 {
+			return printTokens(ctx, client, tokens, owner)
+		}
+		info, err := erc20.Inspect(ctx, client, tokens[0])
+		if err != nil {
+			return err
+		}
+		orMissing := func(s string) string {
+			if s == "" {
+				return "(not available)"
+			}
+			return s
+		}
+		fmt.Printf("Token:        %s\n", info.Address.Hex())
+		fmt.Printf("Name:         %s\n", orMissing(info.Name))
+		fmt.Printf("Symbol:       %s\n", orMissing(info.Symbol))
+		if info.NoDecimals {
+			fmt.Println("Decimals:     (not available, amounts shown in base units)")
+		} else {
+			fmt.Printf("Decimals:     %d\n", info.Decimals)
+		}
+		if info.TotalSupply != nil {
+			fmt.Printf("Total supply: %s\n", info.Format(info.TotalSupply))
+		} else {
+			fmt.Println("Total supply: (not available)")
+		}
+		if owner == nil {
+			return nil
+		}
+		bal, err := info.BalanceOf(ctx, client, *owner)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Balance of %s: %s\n", owner.Hex(), info.Format(bal))
+		return nil
+	}
+	return cmd
+}
+
+// printTokens 用 Multicall3 一次读取多个代币的信息（owner 不为 nil 时再一次读取它的余额）并列表显示，
+// 链上没有 Multicall3 时逐个读取
+func printTokens(ctx context.Context, client chain.Client, tokens []common.Address, owner *common.Address) error {
+	mc := multicall.New(client, presetFor(ctx, client).Multicall)
+	infos, err := erc20.InspectBatch(ctx, mc, tokens)
+	sequential := errors.Is(err, multicall.ErrNotDeployed)
+	if sequential {
+		infos = make([]*erc20.Info, len(tokens))
+		for i, addr := range tokens {
+			infos[i], _ = erc20.Inspect(ctx, client, addr)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	balances := make([]string, len(tokens))
+	if owner != nil {
+		calls := make([]multicall.Call, len(tokens))
+		for i, addr := range tokens {
+			if calls[i], err = multicall.Pack(addr, &erc20.ABI, "balanceOf", *owner); err != nil {
+				return err
+			}
+		}
+		var results []multicall.Result
+		if !sequential {
+			if results, err = mc.Aggregate(ctx, calls, nil); err != nil {
+				return err
+			}
+		}
+		for i, info := range infos {
+			if info == nil {
+				continue
+			}
+			var bal *big.Int
+			if sequential {
+				bal, err = info.BalanceOf(ctx, client, *owner)
+			} else {
+				var values []interface{}
+				if values, err = results[i].Unpack(&erc20.ABI, "balanceOf"); err == nil {
+					bal = values[0].(*big.Int)
+				}
+			}
+			if err != nil {
+				balances[i] = "error: " + err.Error()
+			} else {
+				balances[i] = info.Format(bal)
+			}
+		}
+	}
+
+	dash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "TOKEN\tNAME\tSYMBOL\tDECIMALS\tTOTAL SUPPLY"
+	if owner != nil {
+		header += "\tBALANCE"
+	}
+	fmt.Fprintln(tw, header)
+	for i, info := range infos {
+		if info == nil {
+			fmt.Fprintf(tw, "%s\t(not an ERC-20 token)\n", tokens[i].Hex())
+			continue
+		}
+		decimals, supply := fmt.Sprint(info.Decimals), "-"
+		if info.NoDecimals {
+			decimals = "-"
+		}
+		if info.TotalSupply != nil {
+			supply = info.Format(info.TotalSupply)
+		}
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s", tokens[i].Hex(), dash(info.Name), dash(info.Symbol), decimals, supply)
+		if owner != nil {
+			line += "\t" + balances[i]
+		}
+		fmt.Fprintln(tw, line)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/networks"
+	"github.com/local/go-eth-demo/pkg/pricefeed"
+	"github.com/spf13/cobra"
+)
+
+// feedPrice 读取一个或多个 Chainlink 喂价的最新价格，任何一个无效或过期时返回错误
+func feedPrice() *cobra.Command {
+	cmd := &cobra.Command{Use: "price [pair|address]...", Short: "Read the latest Chainlink feed prices (default ETH/USD)"}
+	fs := cmd.Flags()
+	maxAge := fs.Duration("max-age", feedMaxAge(), "fail when an answer is older than this, 0 to skip the check (default $FEED_MAX_AGE or 1h10m)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		feeds := fs.Args()
+		if len(feeds) == 0 {
+			feeds = []string{"ETH/USD"}
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		preset := presetFor(ctx, client)
+		var failed error
+		for i, s := range feeds {
+			feed, err := resolveFeed(ctx, client, s)
+			if err != nil {
+				return err
+			}
+			round, err := pricefeed.Latest(ctx, client, feed)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			printRound(preset, round)
+			if err := round.Check(time.Now(), *maxAge); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				failed = err
+			}
+		}
+		return failed
+	}
+	return cmd
+}
+
+// feedMaxAge 返回 $FEED_MAX_AGE，未设置或无效时为 pricefeed.DefaultMaxAge
+func feedMaxAge() time.Duration {
+	if d, err := time.ParseDuration(envOr("FEED_MAX_AGE", "")); err == nil {
+		return d
+	}
+	return pricefeed.DefaultMaxAge
+}
+
+// resolveFeed 把喂价地址或交易对（如 ETH/USD，按所连链查找地址）解析为喂价地址
+func resolveFeed(ctx context.Context, client chain.Client, s string) (common.Address, error) {
+	if strings.HasPrefix(s, "0x") || common.IsHexAddress(s) {
+		return address.ParseHex(s)
+	}
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	feed, ok := pricefeed.Lookup(id.Uint64(), s)
+	if !ok {
+		return common.Address{}, fmt.Errorf("no known %s feed on chain %s, pass the feed address instead", s, id)
+	}
+	return feed, nil
+}
+
+func printRound(preset networks.Preset, r *pricefeed.Round) {
+	fmt.Printf("Feed:     %s (%s)\n", r.Description, r.Feed.Hex())
+	fmt.Printf("Price:    %s\n", r.Price())
+	fmt.Printf("Round:    %s\n", r.RoundID)
+	fmt.Printf("Updated:  %s (%s ago)\n", r.UpdatedAt.UTC().Format(time.RFC3339), time.Since(r.UpdatedAt).Round(time.Second))
+	if url := preset.AddressURL(r.Feed); url != "" {
+		fmt.Printf("Explorer: %s\n", url)
+	}
+}
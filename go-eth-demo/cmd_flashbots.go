@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/flashbots"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// relayFlag 注册 --relay：未指定时使用 PRIVATE_TX 中的中继 URL，否则按链使用 Flashbots 中继
+func relayFlag(fs *pflag.FlagSet) *string {
+	def := os.Getenv("PRIVATE_TX")
+	if !strings.HasPrefix(def, "http://") && !strings.HasPrefix(def, "https://") {
+		def = ""
+	}
+	return fs.String("relay", def, "relay URL (default the PRIVATE_TX relay URL, or the Flashbots relay for the chain)")
+}
+
+// flashbotsBundle 把 tx sign 生成的已签名交易作为 bundle 提交给中继，交易按顺序全部打包在同一个区块中或都不打包。
+// 每个参数是十六进制编码的交易或包含它们的文件（每行一笔，- 为 stdin），依次提交到之后的 --blocks 个区块
+func flashbotsBundle() *cobra.Command {
+	cmd := &cobra.Command{Use: "bundle <signed tx hex|file|->...", Short: "Submit signed transactions to a relay as a bundle"}
+	fs := cmd.Flags()
+	relayURL := relayFlag(fs)
+	blocks := fs.Uint64("blocks", 3, "number of upcoming blocks to target")
+	wait := fs.Bool("wait", true, "wait until the target blocks are mined and report whether the bundle was included")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() == 0 {
+			return errors.New("usage: flashbots bundle [flags] <signed tx hex|file|->...")
+		}
+		if *blocks == 0 {
+			return errors.New("--blocks must be at least 1")
+		}
+		var txs []*types.Transaction
+		var raws [][]byte
+		for _, arg := range fs.Args() {
+			lines := []string{arg}
+			if !strings.HasPrefix(arg, "0x") {
+				data, err := readInput(arg)
+				if err != nil {
+					return err
+				}
+				lines = nil
+				sc := bufio.NewScanner(bytes.NewReader(data))
+				sc.Buffer(nil, 1<<20)
+				for sc.Scan() {
+					if line := strings.TrimSpace(sc.Text()); line != "" {
+						lines = append(lines, line)
+					}
+				}
+			}
+			for _, line := range lines {
+				raw, err := hexutil.Decode(line)
+				if err != nil {
+					return fmt.Errorf("invalid signed transaction %.20q: %w", line, err)
+				}
+				tx := new(types.Transaction)
+				if err := tx.UnmarshalBinary(raw); err != nil {
+					return fmt.Errorf("decode signed transaction: %w", err)
+				}
+				txs, raws = append(txs, tx), append(raws, raw)
+			}
+		}
+		if len(txs) == 0 {
+			return errors.New("no transactions to bundle")
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		for i, tx := range txs {
+			if tx.ChainId().Cmp(chainID) != 0 {
+				return fmt.Errorf("transaction %d is for chain %s but the node is on chain %s", i+1, tx.ChainId(), chainID)
+			}
+		}
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get block number: %w", err)
+		}
+
+		relay := &flashbots.Relay{URL: *relayURL, Identity: flashbotsIdentity()}
+		for _, tx := range txs {
+			fmt.Printf("Transaction: %s\n", tx.Hash().Hex())
+		}
+		for block := head + 1; block <= head+*blocks; block++ {
+			hash, err := relay.SendBundle(ctx, raws, block)
+			if err != nil {
+				return fmt.Errorf("block %d: %w", block, err)
+			}
+			fmt.Printf("Bundle %s submitted for block %d\n", hash.Hex(), block)
+		}
+		if !*wait {
+			return nil
+		}
+
+		last := head + *blocks
+		first := txs[0].Hash()
+		for {
+			receipt, err := client.TransactionReceipt(ctx, first)
+			if err == nil {
+				fmt.Printf("Bundle included in block %s\n", receipt.BlockNumber)
+				if url := presetFor(ctx, client).TxURL(first); url != "" {
+					fmt.Printf("Explorer:    %s\n", url)
+				}
+				return nil
+			}
+			n, err := client.BlockNumber(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get block number: %w", err)
+			}
+			if n >= last {
+				return fmt.Errorf("bundle was not included in blocks %d-%d", head+1, last)
+			}
+			time.Sleep(2 * time.Second)
+		}
+	}
+	return cmd
+}
+
+// flashbotsCancel 撤回以 PRIVATE_TX=flashbots 或中继 URL 提交、还没有打包的私有交易
+func flashbotsCancel() *cobra.Command {
+	cmd := &cobra.Command{Use: "cancel <tx hash>", Short: "Cancel a private transaction that has not been included"}
+	fs := cmd.Flags()
+	relayURL := relayFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 || len(common.FromHex(fs.Arg(0))) != common.HashLength {
+			return errors.New("usage: flashbots cancel [flags] <tx hash>")
+		}
+		hash := common.HexToHash(fs.Arg(0))
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		relay := &flashbots.Relay{URL: *relayURL, Identity: flashbotsIdentity()}
+		ok, err := relay.CancelPrivateTransaction(ctx, chainID.Uint64(), hash)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("relay did not cancel %s (already included, unknown, or submitted with another identity)", hash.Hex())
+		}
+		fmt.Printf("Cancelled %s\n", hash.Hex())
+		return nil
+	}
+	return cmd
+}
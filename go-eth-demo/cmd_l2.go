@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/opstack"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// l2Flags 是 l2 命令共用的参数
+type l2Flags struct {
+	network *string
+	l1RPC   *string
+	l2RPC   *string
+}
+
+func newL2Flags(fs *pflag.FlagSet) l2Flags {
+	return l2Flags{
+		network: fs.String("network", "op-sepolia", "OP Stack network: optimism, base, op-sepolia or base-sepolia"),
+		l1RPC:   fs.String("l1-rpc", "", "L1 RPC endpoint (default --rpc)"),
+		l2RPC:   fs.String("l2-rpc", "", "L2 RPC endpoint (default $<NETWORK>_RPC, e.g. OP_SEPOLIA_RPC)"),
+	}
+}
+
+// l2Conn 是连接到 L1 和 L2 的客户端
+type l2Conn struct {
+	net opstack.Network
+	l1  *ethclient.Client
+	l2  *ethclient.Client
+}
+
+func (c *l2Conn) Close() {
+	c.l1.Close()
+	c.l2.Close()
+}
+
+// dial 连接 L1 和 L2，并确认两条链与所选网络一致，避免把交易发到错误的链上
+func (f l2Flags) dial(ctx context.Context) (*l2Conn, error) {
+	net, err := opstack.LookupNetwork(*f.network)
+	if err != nil {
+		return nil, err
+	}
+	l1, l2, err := dialPair(ctx, *f.network, *f.l1RPC, *f.l2RPC, net.L1ChainID, net.L2ChainID)
+	if err != nil {
+		return nil, err
+	}
+	return &l2Conn{net: net, l1: l1, l2: l2}, nil
+}
+
+// dialPair 连接一对 L1/L2 端点并检查链 ID。l1URL 为空时使用 --rpc 或默认端点，l2URL 为空时从 $<NETWORK>_RPC 读取。
+func dialPair(ctx context.Context, network, l1URL, l2URL string, l1ChainID, l2ChainID uint64) (*ethclient.Client, *ethclient.Client, error) {
+	if l2URL == "" {
+		l2URL = networkRPCURL(network)
+	}
+	if l2URL == "" {
+		return nil, nil, fmt.Errorf("no L2 RPC URL: set %s or use --l2-rpc", networkRPCEnv(network))
+	}
+	if l1URL == "" {
+		l1URL = rpcEndpoint()
+	}
+	l1, err := ethclient.DialContext(ctx, l1URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to L1: %w", err)
+	}
+	l2, err := ethclient.DialContext(ctx, l2URL)
+	if err != nil {
+		l1.Close()
+		return nil, nil, fmt.Errorf("failed to connect to L2: %w", err)
+	}
+	for _, c := range []struct {
+		name   string
+		client *ethclient.Client
+		want   uint64
+	}{{"L1", l1, l1ChainID}, {"L2", l2, l2ChainID}} {
+		id, err := c.client.ChainID(ctx)
+		if err != nil {
+			l1.Close()
+			l2.Close()
+			return nil, nil, fmt.Errorf("failed to get %s chain ID: %w", c.name, err)
+		}
+		if id.Uint64() != c.want {
+			l1.Close()
+			l2.Close()
+			return nil, nil, fmt.Errorf("%s RPC is chain %d, but %s expects %d", c.name, id, network, c.want)
+		}
+	}
+	return l1, l2, nil
+}
+
+// l2Deposit 通过 OptimismPortal 把 ETH 从 L1 存入 L2，并等待 L2 上的存款交易
+func l2Deposit() *cobra.Command {
+	cmd := &cobra.Command{Use: "deposit", Short: "Deposit ETH from L1 to L2 through the OptimismPortal"}
+	fs := cmd.Flags()
+	lf := newL2Flags(fs)
+	amountStr := fs.String("amount", "", "amount to deposit, e.g. 0.01eth")
+	to := fs.String("to", "", "L2 recipient (default: sender)")
+	gasLimit := fs.Uint64("gas-limit", opstack.DefaultDepositGasLimit, "L2 gas limit for the deposit")
+	wait := fs.Bool("wait", true, "wait for the deposit to be included on L2")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		amount, err := units.ParseAmount(*amountStr)
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		conn, err := lf.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		auth, err := loadTransactor(ctx, conn.l1)
+		if err != nil {
+			return err
+		}
+		recipient, err := recipientOrSelf(*to, auth)
+		if err != nil {
+			return err
+		}
+
+		tx, err := opstack.Deposit(ctx, conn.l1, conn.net, auth, recipient, amount, *gasLimit)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("L1 deposit tx: %s\n", tx.Hash().Hex())
+		receipt, err := waitSuccess(ctx, conn.l1, tx)
+		if err != nil {
+			return err
+		}
+		deposits, err := opstack.ParseDeposits(receipt, conn.net.Portal)
+		if err != nil {
+			return err
+		}
+		if len(deposits) == 0 {
+			return fmt.Errorf("no TransactionDeposited event in %s", tx.Hash().Hex())
+		}
+		l2Hash := deposits[0].Hash()
+		fmt.Printf("L2 deposit tx: %s\n", l2Hash.Hex())
+		if !*wait {
+			return nil
+		}
+
+		fmt.Println("Waiting for the deposit on L2 (usually 1-3 minutes)...")
+		waitCtx, cancel := context.WithTimeout(ctx, 20*time.Minute)
+		defer cancel()
+		l2Receipt, err := opstack.WaitForReceipt(waitCtx, conn.l2, l2Hash, 5*time.Second)
+		if err != nil {
+			return fmt.Errorf("wait for L2 deposit: %w", err)
+		}
+		fmt.Printf("✅ Minted %s ETH to %s in L2 block %d\n", units.FormatUnits(amount, 18), recipient.Hex(), l2Receipt.BlockNumber.Uint64())
+		return nil
+	}
+	return cmd
+}
+
+// l2Withdraw 在 L2 上发起提款，之后需要 l2 prove 和 l2 finalize
+func l2Withdraw() *cobra.Command {
+	cmd := &cobra.Command{Use: "withdraw", Short: "Start a withdrawal on L2"}
+	fs := cmd.Flags()
+	lf := newL2Flags(fs)
+	amountStr := fs.String("amount", "", "amount to withdraw, e.g. 0.01eth")
+	to := fs.String("to", "", "L1 recipient (default: sender)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		amount, err := units.ParseAmount(*amountStr)
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		conn, err := lf.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		auth, err := loadTransactor(ctx, conn.l2)
+		if err != nil {
+			return err
+		}
+		recipient, err := recipientOrSelf(*to, auth)
+		if err != nil {
+			return err
+		}
+
+		tx, err := opstack.InitiateWithdrawal(ctx, conn.l2, auth, recipient, amount, opstack.DefaultWithdrawalGasLimit)
+		if err != nil {
+			return err
+		}
+		receipt, err := waitSuccess(ctx, conn.l2, tx)
+		if err != nil {
+			return err
+		}
+		w, err := opstack.ParseWithdrawal(receipt)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("L2 withdrawal tx: %s (block %d)\n", tx.Hash().Hex(), w.L2Block)
+		fmt.Printf("Withdrawal hash:  %s\n", w.Hash.Hex())
+		fmt.Printf("Next: run `l2 prove --network %s %s` once `l2 status` reports %s\n", conn.net.Name, tx.Hash().Hex(), opstack.StatusReadyToProve)
+		return nil
+	}
+	return cmd
+}
+
+// loadWithdrawal 从 L2 交易收据中读取提款
+func loadWithdrawal(ctx context.Context, conn *l2Conn, txHash string) (*opstack.Withdrawal, error) {
+	hash := common.HexToHash(txHash)
+	receipt, err := conn.l2.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 receipt %s: %w", hash.Hex(), err)
+	}
+	return opstack.ParseWithdrawal(receipt)
+}
+
+// l2Status 报告提款所处的阶段
+func l2Status() *cobra.Command {
+	cmd := &cobra.Command{Use: "status <l2 withdrawal tx hash>", Short: "Report the stage of a withdrawal"}
+	fs := cmd.Flags()
+	lf := newL2Flags(fs)
+	prover := fs.String("prover", "", "address that submitted the proof (default: the PRIVATE_KEY account)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: l2 status [flags] <l2 withdrawal tx hash>")
+		}
+
+		ctx := rootCtx
+		conn, err := lf.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		w, err := loadWithdrawal(ctx, conn, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		var proverAddr common.Address
+		if *prover != "" {
+			if proverAddr, err = address.ParseHex(*prover); err != nil {
+				return fmt.Errorf("--prover: %w", err)
+			}
+		} else {
+			auth, err := loadTransactor(ctx, conn.l1)
+			if err != nil {
+				return fmt.Errorf("--prover is required without PRIVATE_KEY: %w", err)
+			}
+			proverAddr = auth.From
+		}
+
+		p, err := opstack.GetStatus(ctx, conn.l1, conn.net, w, proverAddr)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Withdrawal %s: %s\n", w.Hash.Hex(), p.Status)
+		if !p.ProvenAt.IsZero() {
+			fmt.Printf("Proven at:      %s (dispute game %s)\n", p.ProvenAt.Format(time.RFC3339), p.Game.Hex())
+			fmt.Printf("Finalizable at: %s\n", p.FinalizableAt.Format(time.RFC3339))
+		}
+		return nil
+	}
+	return cmd
+}
+
+// l2Prove 在 L1 上提交提款证明
+func l2Prove() *cobra.Command {
+	cmd := &cobra.Command{Use: "prove <l2 withdrawal tx hash>", Short: "Prove a withdrawal on L1"}
+	fs := cmd.Flags()
+	lf := newL2Flags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: l2 prove [flags] <l2 withdrawal tx hash>")
+		}
+
+		ctx := rootCtx
+		conn, err := lf.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		w, err := loadWithdrawal(ctx, conn, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		auth, err := loadTransactor(ctx, conn.l1)
+		if err != nil {
+			return err
+		}
+		tx, err := opstack.Prove(ctx, conn.l1, conn.l2, gethclient.New(conn.l2.Client()), conn.net, auth, w)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Prove tx: %s\n", tx.Hash().Hex())
+		if _, err := waitSuccess(ctx, conn.l1, tx); err != nil {
+			return err
+		}
+		fmt.Println("✅ Withdrawal proven; finalize after the challenge period with `l2 finalize`")
+		return nil
+	}
+	return cmd
+}
+
+// l2Finalize 在挑战期结束后于 L1 上最终确认提款
+func l2Finalize() *cobra.Command {
+	cmd := &cobra.Command{Use: "finalize <l2 withdrawal tx hash>", Short: "Finalize a withdrawal on L1 after the challenge period"}
+	fs := cmd.Flags()
+	lf := newL2Flags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: l2 finalize [flags] <l2 withdrawal tx hash>")
+		}
+
+		ctx := rootCtx
+		conn, err := lf.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		w, err := loadWithdrawal(ctx, conn, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		auth, err := loadTransactor(ctx, conn.l1)
+		if err != nil {
+			return err
+		}
+		p, err := opstack.GetStatus(ctx, conn.l1, conn.net, w, auth.From)
+		if err != nil {
+			return err
+		}
+		if p.Status != opstack.StatusReadyToFinalize {
+			return fmt.Errorf("withdrawal is %s, not %s", p.Status, opstack.StatusReadyToFinalize)
+		}
+		tx, err := opstack.Finalize(ctx, conn.l1, conn.net, auth, w)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Finalize tx: %s\n", tx.Hash().Hex())
+		if _, err := waitSuccess(ctx, conn.l1, tx); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Withdrawal finalized, %s ETH released to %s\n", units.FormatUnits(w.Value, 18), w.Target.Hex())
+		return nil
+	}
+	return cmd
+}
+
+// recipientOrSelf 解析 --to 参数，为空时使用发送者地址
+func recipientOrSelf(to string, auth *bind.TransactOpts) (common.Address, error) {
+	if to == "" {
+		return auth.From, nil
+	}
+	addr, err := address.ParseHex(to)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("recipient: %w", err)
+	}
+	return addr, nil
+}
+
+// waitSuccess 等待交易被打包并确认执行成功
+func waitSuccess(ctx context.Context, client *ethclient.Client, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		return nil, fmt.Errorf("wait for transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %s failed with status: %d", tx.Hash().Hex(), receipt.Status)
+	}
+	return receipt, nil
+}
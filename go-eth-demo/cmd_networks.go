@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// networksList 列出内置和用户配置的链预设
+func networksList() *cobra.Command {
+	cmd := &cobra.Command{Use: "list", Short: "List the built-in and configured network presets"}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		registry, err := loadNetworks()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tCHAIN ID\tCURRENCY\tMULTICALL\tWETH\tENS\tEXPLORER\tRPC\tACCOUNT")
+		for _, name := range registry.Names() {
+			p, _ := registry.Lookup(name)
+			// 显示未展开的 RPC，避免打印环境变量中的 API key
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", name, p.ChainID, p.Currency.Symbol,
+				orDash(p.Multicall), orDash(p.WETH), orDash(p.ENS), p.Explorer, p.RPC, orDash(p.Account))
+		}
+		return w.Flush()
+	}
+	return cmd
+}
+
+func orDash(addr common.Address) string {
+	if addr == (common.Address{}) {
+		return "-"
+	}
+	return addr.Hex()
+}
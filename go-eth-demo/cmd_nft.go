@@ -0,0 +1,281 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/erc1155"
+	"github.com/local/go-eth-demo/pkg/erc721"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/spf13/cobra"
+)
+
+// parseTokenID 解析十进制或 0x 开头的十六进制 tokenId
+func parseTokenID(s string) (*big.Int, error) {
+	id, ok := new(big.Int).SetString(s, 0)
+	if !ok || id.Sign() < 0 {
+		return nil, fmt.Errorf("invalid token ID %q", s)
+	}
+	return id, nil
+}
+
+// nftInfo 显示 NFT 的所有者和 tokenURI，并读取、格式化显示元数据 JSON
+func nftInfo() *cobra.Command {
+	cmd := &cobra.Command{Use: "info <contract> <token ID>", Short: "Show an NFT's owner, token URI and metadata"}
+	fs := cmd.Flags()
+	gateway := fs.String("gateway", envOr("IPFS_GATEWAY", erc721.DefaultGateway), "HTTP gateway for ipfs:// URIs (default $IPFS_GATEWAY)")
+	metadata := fs.Bool("metadata", true, "fetch and print the metadata JSON")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 2 {
+			return fmt.Errorf("usage: nft info [flags] <contract> <token ID>")
+		}
+		contract, err := address.ParseHex(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		id, err := parseTokenID(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		c := erc721.New(client, contract)
+		owner, err := c.OwnerOf(ctx, id)
+		if err != nil {
+			return err
+		}
+		name := c.Name(ctx)
+		if symbol := c.Symbol(ctx); symbol != "" {
+			name += " (" + symbol + ")"
+		}
+		fmt.Printf("Collection: %s %s\n", c.Address.Hex(), name)
+		fmt.Printf("Token ID:   %s\n", id)
+		fmt.Printf("Owner:      %s\n", owner.Hex())
+		uri, err := c.TokenURI(ctx, id)
+		if err != nil {
+			// tokenURI 属于可选的元数据扩展
+			fmt.Printf("Token URI:  (not available: %v)\n", err)
+			return nil
+		}
+		fmt.Printf("Token URI:  %s\n", uri)
+		if !*metadata || uri == "" {
+			return nil
+		}
+
+		m, err := erc721.FetchMetadata(ctx, &http.Client{Timeout: 30 * time.Second}, uri, *gateway)
+		if err != nil {
+			return err
+		}
+		fmt.Println("\n=== Metadata ===")
+		if m.Name != "" {
+			fmt.Printf("Name:        %s\n", m.Name)
+		}
+		if m.Description != "" {
+			fmt.Printf("Description: %s\n", m.Description)
+		}
+		if m.Image != "" {
+			fmt.Printf("Image:       %s\n", erc721.ResolveURI(m.Image, *gateway))
+		}
+		for _, a := range m.Attributes {
+			fmt.Printf("  %-20s %v\n", a.TraitType+":", a.Value)
+		}
+		var pretty any
+		json.Unmarshal(m.Raw, &pretty)
+		out, _ := json.MarshalIndent(pretty, "", "  ")
+		fmt.Printf("\n%s\n", out)
+		return nil
+	}
+	return cmd
+}
+
+// nftTransfer 用 safeTransferFrom 把发送方持有的 NFT 转给 --to
+func nftTransfer() *cobra.Command {
+	cmd := &cobra.Command{Use: "transfer", Short: "Transfer an ERC-721 token with safeTransferFrom"}
+	fs := cmd.Flags()
+	contract := fs.String("contract", os.Getenv("NFT_ADDR"), "ERC-721 contract address (default $NFT_ADDR)")
+	idStr := fs.String("id", "", "token ID to transfer")
+	to := fs.String("to", os.Getenv("RECIPIENT_ADDR"), "recipient address or address book name (default $RECIPIENT_ADDR)")
+	cf := newCallFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		contractAddress, err := address.ParseHex(*contract)
+		if err != nil {
+			return fmt.Errorf("--contract: %w", err)
+		}
+		if *to == "" {
+			return errors.New("--to or RECIPIENT_ADDR is required")
+		}
+		if *idStr == "" {
+			return errors.New("--id is required")
+		}
+		id, err := parseTokenID(*idStr)
+		if err != nil {
+			return err
+		}
+		if err := cf.check(); err != nil {
+			return err
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		recipient, err := resolveAddress(ctx, client, *to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+
+		c := erc721.New(client, contractAddress)
+		t, err := c.Prepare(ctx, w.Address(), recipient, id)
+		if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			return err
+		}
+		fmt.Printf("Collection: %s\n", c.Address.Hex())
+		fmt.Printf("Token ID:   %s\n", id)
+		label := addressLabels(ctx, client, presetFor(ctx, client), t.From, recipient)
+		fmt.Printf("From:       %s\n", label(t.From))
+		fmt.Printf("To:         %s\n", label(recipient))
+		_, err = cf.send(ctx, client, w, t)
+		return err
+	}
+	return cmd
+}
+
+// erc1155Transfer 用 safeBatchTransferFrom 一次转出 --items 文件（JSON 或 CSV）中的多个 ID 和数量
+func erc1155Transfer() *cobra.Command {
+	cmd := &cobra.Command{Use: "transfer", Short: "Transfer several ERC-1155 IDs with one safeBatchTransferFrom"}
+	fs := cmd.Flags()
+	contract := fs.String("contract", os.Getenv("NFT_ADDR"), "ERC-1155 contract address (default $NFT_ADDR)")
+	itemsPath := fs.String("items", "", `JSON ([{"id":1,"amount":10}]) or CSV ("id,amount" per line) file listing what to send`)
+	to := fs.String("to", os.Getenv("RECIPIENT_ADDR"), "recipient address or address book name (default $RECIPIENT_ADDR)")
+	cf := newCallFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		contractAddress, err := address.ParseHex(*contract)
+		if err != nil {
+			return fmt.Errorf("--contract: %w", err)
+		}
+		if *to == "" {
+			return errors.New("--to or RECIPIENT_ADDR is required")
+		}
+		if *itemsPath == "" {
+			return errors.New("--items is required")
+		}
+		items, err := erc1155.LoadItems(*itemsPath)
+		if err != nil {
+			return err
+		}
+		if err := cf.check(); err != nil {
+			return err
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		recipient, err := resolveAddress(ctx, client, *to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+
+		c := erc1155.New(client, contractAddress)
+		t, err := c.Prepare(ctx, w.Address(), recipient, items)
+		if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			return err
+		}
+		fmt.Printf("Collection: %s\n", c.Address.Hex())
+		label := addressLabels(ctx, client, presetFor(ctx, client), t.From, recipient)
+		fmt.Printf("From:       %s\n", label(t.From))
+		fmt.Printf("To:         %s\n", label(recipient))
+		for _, item := range items {
+			fmt.Printf("  id %-10s amount %s\n", item.ID, item.Amount)
+		}
+		_, err = cf.send(ctx, client, w, t)
+		return err
+	}
+	return cmd
+}
+
+// erc1155Balances 用一次 balanceOfBatch 查询每个账户持有的每个 ID
+func erc1155Balances() *cobra.Command {
+	cmd := &cobra.Command{Use: "balances <contract> <id>...", Short: "Query ERC-1155 balances with one balanceOfBatch"}
+	fs := cmd.Flags()
+	accountList := fs.String("account", "", "comma separated accounts to query")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() < 2 {
+			return fmt.Errorf("usage: erc1155 balances --account 0x...[,0x...] <contract> <id>...")
+		}
+		contract, err := address.ParseHex(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		var accounts []common.Address
+		for _, a := range strings.Split(*accountList, ",") {
+			account, err := address.ParseHex(a)
+			if err != nil {
+				return fmt.Errorf("--account: %w", err)
+			}
+			accounts = append(accounts, account)
+		}
+		var ids []*big.Int
+		for _, arg := range fs.Args()[1:] {
+			id, err := parseTokenID(arg)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		// 每个账户和每个 ID 组合成一对，一次调用查询全部
+		var owners []common.Address
+		var pairIDs []*big.Int
+		for _, a := range accounts {
+			for _, id := range ids {
+				owners, pairIDs = append(owners, a), append(pairIDs, id)
+			}
+		}
+		balances, err := erc1155.New(client, contract).BalanceOfBatch(ctx, owners, pairIDs)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ACCOUNT\tID\tBALANCE")
+		for i := range owners {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", owners[i].Hex(), pairIDs[i], balances[i])
+		}
+		return tw.Flush()
+	}
+	return cmd
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/pkg/notify"
+	"github.com/spf13/cobra"
+)
+
+// sampleAlerts 是 notify test 发送的示例数据，与各告警类型的默认模板对应
+var sampleAlerts = map[string]interface{}{
+	notify.AlertTx: notify.TxEvent{
+		Status: notify.TxConfirmed, Hash: "0x0000000000000000000000000000000000000000000000000000000000000000",
+		ChainID: 11155111, From: "0x0000000000000000000000000000000000000000", Block: 1, Confirmations: 1, GasUsed: 21000,
+	},
+	notify.AlertGas:     notify.GasEvent{Price: "12.5", Direction: "below", Threshold: "15", ChainID: 11155111, Block: 1},
+	notify.AlertAddress: notify.AddressEvent{Address: "0x0000000000000000000000000000000000000000", Message: "test notification from go-eth-demo", ChainID: 11155111},
+	notify.AlertStuck: notify.StuckEvent{
+		Hash: "0x0000000000000000000000000000000000000000000000000000000000000000", ChainID: 11155111,
+		From: "0x0000000000000000000000000000000000000000", Pending: "15m0s", State: "pending",
+		Message: "pending for 15m0s, still in the mempool",
+	},
+}
+
+// notifyTest 用示例数据发送一条告警，检查通知配置是否可用
+func notifyTest() *cobra.Command {
+	cmd := &cobra.Command{Use: "test", Short: "Send a sample alert to check the notification settings"}
+	fs := cmd.Flags()
+	configPath := fs.String("config", envOr("NOTIFY_CONFIG", "notify.json"), "notification config file (default $NOTIFY_CONFIG or notify.json)")
+	alert := fs.String("type", notify.AlertAddress, "alert type to send: tx, gas, address or stuck")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := notify.LoadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		n, err := notify.New(cfg)
+		if err != nil {
+			return err
+		}
+		data, ok := sampleAlerts[*alert]
+		if !ok {
+			return fmt.Errorf("no sample data for alert type %q", *alert)
+		}
+		if !n.Enabled(*alert) {
+			return fmt.Errorf("alert type %q has no sinks in %s", *alert, *configPath)
+		}
+		if err := n.Notify(context.Background(), *alert, data); err != nil {
+			return err
+		}
+		fmt.Printf("Sent test %s notification\n", *alert)
+		return nil
+	}
+	return cmd
+}
+
+// txNotifier 发送交易事件通知，通知配置中没有 tx 告警时为 nil
+var txNotifier *notify.Notifier
+
+// alertNotifier 是从通知配置创建的 Notifier，供 watch gas 等常驻命令发送告警，没有配置时为 nil
+var alertNotifier *notify.Notifier
+
+// setupNotify 加载 NOTIFY_CONFIG（默认 notify.json）中的通知配置。没有设置 NOTIFY_CONFIG 且默认文件不存在时不发送通知
+func setupNotify() error {
+	path := envOr("NOTIFY_CONFIG", "notify.json")
+	cfg, err := notify.LoadConfig(path)
+	if errors.Is(err, fs.ErrNotExist) && os.Getenv("NOTIFY_CONFIG") == "" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	n, err := notify.New(cfg)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	alertNotifier = n
+	if n.Enabled(notify.AlertTx) {
+		txNotifier = n
+		logger("notify").Debug("transaction notifications enabled", "config", path)
+	}
+	return nil
+}
+
+// txEvents 为一笔交易发送 tx 告警，base 是各事件共有的字段，confirmations 是 confirmed 事件要求的确认数
+type txEvents struct {
+	base          notify.TxEvent
+	confirmations uint64
+}
+
+// newTxEvents 在启用了交易通知时返回 tx 的事件发送器，否则返回 nil（send 为空操作）
+func newTxEvents(ctx context.Context, client *ethclient.Client, tx *types.Transaction, confirmations uint64) *txEvents {
+	if txNotifier == nil {
+		return nil
+	}
+	base := notify.TxEvent{Hash: tx.Hash().Hex(), ChainID: tx.ChainId().Uint64(), Nonce: tx.Nonce()}
+	if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		base.From = from.Hex()
+	}
+	if tx.To() != nil {
+		base.To = tx.To().Hex()
+	}
+	base.Explorer = presetFor(ctx, client).TxURL(tx.Hash())
+	return &txEvents{base: base, confirmations: confirmations}
+}
+
+// send 发送一个事件。通知失败只记录警告，不影响交易流程
+func (e *txEvents) send(ctx context.Context, status string, receipt *types.Receipt, txErr error) {
+	if e == nil {
+		return
+	}
+	ev := e.base
+	ev.Status = status
+	if receipt != nil {
+		ev.Block = receipt.BlockNumber.Uint64()
+		ev.GasUsed = receipt.GasUsed
+		ev.Confirmations = 1
+		if status == notify.TxConfirmed {
+			ev.Confirmations = e.confirmations
+		}
+	}
+	if txErr != nil {
+		ev.Error = txErr.Error()
+	}
+	if err := txNotifier.Notify(ctx, notify.AlertTx, ev); err != nil {
+		logger("notify").Warn("failed to send transaction notification", "hash", ev.Hash, "status", status, "err", err)
+	}
+}
+
+// envOr 返回环境变量的值，未设置时返回 def
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/spf13/cobra"
+)
+
+// erc20Permit 为 EIP-2612 代币签名 permit：授权 --spender 使用 --amount 个代币，自己不发送交易也不需要 ETH。
+// 输出 permit 的 JSON（含 v、r、s），由 spender、中继或之后的交易用 erc20 permit-send 提交
+func erc20Permit() *cobra.Command {
+	cmd := &cobra.Command{Use: "permit", Short: "Sign an EIP-2612 permit without sending a transaction"}
+	fs := cmd.Flags()
+	tokenAddr := fs.String("token", os.Getenv("TOKEN_ADDR"), "ERC-20 token address (default $TOKEN_ADDR)")
+	spenderStr := fs.String("spender", "", "address allowed to spend the tokens")
+	amountStr := fs.String("amount", "", "allowance in token units, e.g. 12.5 or \"12.5 USDC\", or max")
+	deadlineStr := fs.String("deadline", "1h", "how long the permit stays valid, e.g. 30m, or a Unix timestamp")
+	out := fs.String("out", "", "write the permit JSON to this file instead of stdout")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		tokenAddress, err := address.ParseHex(*tokenAddr)
+		if err != nil {
+			return fmt.Errorf("--token: %w", err)
+		}
+		if *spenderStr == "" {
+			return errors.New("--spender is required")
+		}
+		if *amountStr == "" {
+			return errors.New("--amount is required")
+		}
+		deadline, err := parseDeadline(*deadlineStr, time.Now())
+		if err != nil {
+			return err
+		}
+		key, err := loadPrivateKey()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		spender, err := resolveAddress(ctx, client, *spenderStr)
+		if err != nil {
+			return fmt.Errorf("--spender: %w", err)
+		}
+		token, err := erc20.Load(ctx, client, tokenAddress)
+		if err != nil {
+			return err
+		}
+		amount := math.MaxBig256
+		if *amountStr != "max" {
+			if amount, err = token.ParseAmount(*amountStr); err != nil {
+				return err
+			}
+		}
+
+		owner := crypto.PubkeyToAddress(key.PublicKey)
+		p, err := erc20.NewPermit(ctx, client, token.Address, owner, spender, amount, deadline)
+		if err != nil {
+			return err
+		}
+		if err := p.Sign(key); err != nil {
+			return err
+		}
+		allowance := "unlimited"
+		if *amountStr != "max" {
+			allowance = token.Format(amount)
+		}
+		fmt.Fprintf(os.Stderr, "Permit %s (%s version %s) for %s to spend %s, nonce %s, valid until %s\n",
+			token.Address.Hex(), p.Name, p.Version, spender.Hex(), allowance, p.Nonce, time.Unix(deadline.Int64(), 0).UTC().Format(time.RFC3339))
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeOutput(*out, append(data, '\n'))
+	}
+	return cmd
+}
+
+// parseDeadline 把 Go 时长（相对 now）或 Unix 秒解析为 permit 的 deadline
+func parseDeadline(s string, now time.Time) (*big.Int, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return nil, fmt.Errorf("--deadline %s is not in the future", s)
+		}
+		return big.NewInt(now.Add(d).Unix()), nil
+	}
+	ts, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --deadline %q: want a duration like 1h or a Unix timestamp", s)
+	}
+	if ts <= now.Unix() {
+		return nil, fmt.Errorf("--deadline %s is in the past", s)
+	}
+	return big.NewInt(ts), nil
+}
+
+// erc20PermitSend 用当前签名账户提交 erc20 permit 生成的授权（permit 交易由提交者支付 gas），
+// 提交前检查签名、链 ID、deadline 和 nonce，避免发送必然失败的交易
+func erc20PermitSend() *cobra.Command {
+	cmd := &cobra.Command{Use: "permit-send", Short: "Submit a permit signed with erc20 permit"}
+	fs := cmd.Flags()
+	in := fs.String("in", "-", "permit JSON file, - for stdin")
+	cf := newCallFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := cf.check(); err != nil {
+			return err
+		}
+		data, err := readInput(*in)
+		if err != nil {
+			return err
+		}
+		var p erc20.Permit
+		if err := json.Unmarshal(data, &p); err != nil {
+			return fmt.Errorf("parse permit: %w", err)
+		}
+		if err := p.Verify(); err != nil {
+			return err
+		}
+		if p.Deadline.Cmp(big.NewInt(time.Now().Unix())) < 0 {
+			return fmt.Errorf("permit expired at %s", time.Unix(p.Deadline.Int64(), 0).UTC().Format(time.RFC3339))
+		}
+		calldata, err := p.CallData()
+		if err != nil {
+			return err
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		// 用当前的 nonce 和域重新构造，与签名的内容不同说明 permit 已被使用或属于其他链/代币
+		current, err := erc20.NewPermit(ctx, client, p.Token, p.Owner, p.Spender, p.Value, p.Deadline)
+		if err != nil {
+			return err
+		}
+		if current.ChainID.Cmp(p.ChainID) != 0 {
+			return fmt.Errorf("permit is for chain %s but the node is on chain %s", p.ChainID, current.ChainID)
+		}
+		if current.Nonce.Cmp(p.Nonce) != 0 {
+			return fmt.Errorf("permit nonce %s is stale, the owner's current nonce is %s (already used or replaced)", p.Nonce, current.Nonce)
+		}
+
+		t, err := ethtx.PrepareCall(ctx, client, w.Address(), p.Token, new(big.Int), calldata)
+		if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			return err
+		}
+		fmt.Printf("Token:     %s\n", p.Token.Hex())
+		fmt.Printf("Owner:     %s\n", p.Owner.Hex())
+		fmt.Printf("Spender:   %s\n", p.Spender.Hex())
+		fmt.Printf("Value:     %s\n", p.Value)
+		_, err = cf.send(ctx, client, w, t)
+		return err
+	}
+	return cmd
+}
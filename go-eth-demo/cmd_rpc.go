@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/rpcbench"
+	"github.com/local/go-eth-demo/pkg/rpccompare"
+	"github.com/spf13/cobra"
+)
+
+// rpcCompare 对多个 RPC 端点执行相同查询并输出差异
+func rpcCompare() *cobra.Command {
+	cmd := &cobra.Command{Use: "compare", Short: "Run the same queries against several RPC endpoints and show the differences"}
+	fs := cmd.Flags()
+	urls := fs.String("urls", os.Getenv("RPC_COMPARE_URLS"), "comma-separated RPC URLs to compare (default $RPC_COMPARE_URLS)")
+	addrs := fs.String("addresses", os.Getenv("RECIPIENT_ADDR"), "comma-separated addresses whose balances are compared")
+	logAddr := fs.String("log-address", os.Getenv("CONTRACT_ADDR"), "contract address whose logs are compared")
+	logRange := fs.Uint64("log-range", 100, "number of blocks covered by the log query")
+	lag := fs.Uint64("lag", 2, "blocks below the lowest head to compare at")
+	maxLag := fs.Uint64("max-lag", 3, "report providers whose head is more than this many blocks behind")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		endpoints := splitList(*urls)
+		if len(endpoints) < 2 {
+			return fmt.Errorf("at least two RPC URLs are required (use --urls or RPC_COMPARE_URLS)")
+		}
+
+		ctx := rootCtx
+		var providers []rpccompare.Provider
+		for i, name := range providerNames(endpoints) {
+			client, err := ethclient.DialContext(ctx, endpoints[i])
+			if err != nil {
+				return fmt.Errorf("failed to connect to %s: %w", name, err)
+			}
+			defer client.Close()
+			providers = append(providers, rpccompare.Provider{Name: name, Client: client})
+		}
+
+		opts := rpccompare.Options{LogRange: *logRange, Lag: *lag}
+		for _, a := range splitList(*addrs) {
+			addr, err := address.ParseHex(a)
+			if err != nil {
+				return err
+			}
+			opts.Addresses = append(opts.Addresses, addr)
+		}
+		if *logAddr != "" {
+			addr, err := address.ParseHex(*logAddr)
+			if err != nil {
+				return fmt.Errorf("log address: %w", err)
+			}
+			opts.LogAddress = &addr
+		}
+
+		report, err := rpccompare.Compare(ctx, providers, opts)
+		if err != nil {
+			return err
+		}
+		// 查询失败时的错误信息可能带有端点 URL，输出经过 redactor
+		return printComparison(redactor.Writer(os.Stdout), providers, report, *maxLag)
+	}
+	return cmd
+}
+
+// providerNames 返回 rpc compare 输出中各端点的名称：URL 的主机名，不含路径和查询参数中的 API key。
+// 同一主机出现多次时（如同一提供商的两个 key）依次加上 #2、#3 区分
+func providerNames(urls []string) []string {
+	names := endpointHosts(strings.Join(urls, ","))
+	seen := make(map[string]int)
+	for i, name := range names {
+		if seen[name]++; seen[name] > 1 {
+			names[i] = fmt.Sprintf("%s#%d", name, seen[name])
+		}
+	}
+	return names
+}
+
+// printComparison 输出各提供商的区块高度和每项检查的结果，有不一致或落后的提供商时返回错误
+func printComparison(w io.Writer, providers []rpccompare.Provider, report *rpccompare.Report, maxLag uint64) error {
+	fmt.Fprintln(w, "=== Provider Heads ===")
+	for _, p := range providers {
+		fmt.Fprintf(w, "%-50s %d\n", p.Name, report.Heads[p.Name])
+	}
+	fmt.Fprintf(w, "\n=== Comparing at block %d ===\n", report.CompareAt)
+	for _, c := range report.Checks {
+		status := "OK"
+		if !c.Consistent() {
+			status = "MISMATCH"
+		}
+		fmt.Fprintf(w, "[%s] %s\n", status, c.Name)
+		if !c.Consistent() {
+			for _, p := range providers {
+				fmt.Fprintf(w, "    %-46s %s\n", p.Name, c.Values[p.Name])
+			}
+		}
+	}
+
+	lagging := report.Lagging(maxLag)
+	mismatches := report.Mismatches()
+	if len(lagging) > 0 {
+		fmt.Fprintf(w, "\n❌ Lagging providers (> %d blocks behind): %s\n", maxLag, strings.Join(lagging, ", "))
+	}
+	if len(mismatches) > 0 || len(lagging) > 0 {
+		return fmt.Errorf("providers are inconsistent: %d mismatched checks, %d lagging", len(mismatches), len(lagging))
+	}
+	fmt.Fprintln(w, "\n✅ All providers are consistent")
+	return nil
+}
+
+// splitList 解析逗号分隔的列表，忽略空白项
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// benchRPC 测量逐个请求、批量请求和 Multicall3 三种读取策略在一个提供商上的延迟
+func benchRPC() *cobra.Command {
+	cmd := &cobra.Command{Use: "rpc", Short: "Measure sequential, batched and Multicall3 reads against an RPC provider"}
+	fs := cmd.Flags()
+	addrs := fs.String("addresses", "", "comma-separated addresses to query (default: --count generated addresses)")
+	n := fs.IntP("count", "n", 50, "number of generated addresses when --addresses is empty")
+	rounds := fs.Int("rounds", 10, "number of rounds per strategy")
+	strategies := fs.String("strategies", "sequential,batched,multicall", "comma-separated strategies to run")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var targets []common.Address
+		for _, a := range splitList(*addrs) {
+			addr, err := address.ParseHex(a)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, addr)
+		}
+		if len(targets) == 0 {
+			for i := 0; i < *n; i++ {
+				targets = append(targets, common.BytesToAddress(crypto.Keccak256([]byte(fmt.Sprint("bench", i)))))
+			}
+		}
+		var selected []rpcbench.Strategy
+		for _, s := range splitList(*strategies) {
+			strategy, err := rpcbench.ParseStrategy(s)
+			if err != nil {
+				return err
+			}
+			selected = append(selected, strategy)
+		}
+
+		ctx := rootCtx
+		url := rpcEndpoint()
+		client, err := rpc.DialContext(ctx, url)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", url, err)
+		}
+		defer client.Close()
+		// 固定在同一个区块上查询，保证各策略读取相同的数据
+		head, err := ethclient.NewClient(client).BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get block number: %w", err)
+		}
+		block := new(big.Int).SetUint64(head)
+		// 使用链预设中的 Multicall3 地址（自定义链可能部署在其他地址）
+		if p := presetFor(ctx, ethclient.NewClient(client)); p.Multicall != (common.Address{}) {
+			rpcbench.MulticallAddress = p.Multicall
+		}
+
+		fmt.Printf("Querying %d balances at block %d, %d rounds per strategy\n\n", len(targets), head, *rounds)
+		fmt.Printf("%-12s %10s %10s %10s %10s %10s %12s\n", "STRATEGY", "MIN", "MEAN", "P50", "P95", "MAX", "PER CALL")
+		for _, strategy := range selected {
+			result, err := rpcbench.Run(ctx, client, strategy, targets, block, *rounds)
+			if err != nil {
+				fmt.Printf("%-12s error: %v\n", strategy, err)
+				continue
+			}
+			fmt.Printf("%-12s %10s %10s %10s %10s %10s %12s\n", strategy,
+				roundDuration(result.Min), roundDuration(result.Mean), roundDuration(result.P50),
+				roundDuration(result.P95), roundDuration(result.Max), roundDuration(result.PerCall()))
+		}
+		return nil
+	}
+	return cmd
+}
+
+// roundDuration 把耗时截断到便于阅读的精度
+func roundDuration(d time.Duration) time.Duration {
+	if d >= time.Millisecond {
+		return d.Round(100 * time.Microsecond)
+	}
+	return d.Round(time.Microsecond)
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/safe"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// safeFlags 是 safe 子命令共用的参数
+type safeFlags struct {
+	address *string
+	service *string
+	submit  *bool
+}
+
+func newSafeFlags(fs *pflag.FlagSet) safeFlags {
+	return safeFlags{
+		address: fs.String("safe", os.Getenv("SAFE_ADDR"), "Safe address (default $SAFE_ADDR)"),
+		service: fs.String("service", os.Getenv("SAFE_TX_SERVICE"), "Safe Transaction Service URL (default $SAFE_TX_SERVICE or the public service for the chain)"),
+		submit:  fs.Bool("submit", false, "also submit to the Safe Transaction Service so other owners can confirm in Safe{Wallet}"),
+	}
+}
+
+// load 连接节点并读取 Safe 的状态
+func (f safeFlags) load(ctx context.Context) (*ethclient.Client, *safe.Info, error) {
+	safeAddress, err := address.ParseHex(*f.address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("--safe: %w", err)
+	}
+	client, err := dial(ctx, rpcEndpoint())
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := safe.Load(ctx, client, safeAddress)
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	return client, info, nil
+}
+
+// serviceFor 返回 Transaction Service 客户端，API key 取自 SAFE_API_KEY
+func (f safeFlags) serviceFor(info *safe.Info) (*safe.Service, error) {
+	url := *f.service
+	if url == "" {
+		url = safe.ServiceURL(info.ChainID.Uint64())
+	}
+	if url == "" {
+		return nil, fmt.Errorf("no Safe Transaction Service known for chain %s, set --service or SAFE_TX_SERVICE", info.ChainID)
+	}
+	return &safe.Service{URL: url, APIKey: os.Getenv("SAFE_API_KEY")}, nil
+}
+
+// safeInfo 显示 Safe 的版本、owners、threshold 和下一个 nonce
+func safeInfo() *cobra.Command {
+	cmd := &cobra.Command{Use: "info", Short: "Show a Safe's version, owners, threshold and next nonce"}
+	fs := cmd.Flags()
+	sf := newSafeFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := rootCtx
+		client, info, err := sf.load(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		fmt.Printf("Safe:      %s (version %s, chain %s)\n", info.Address.Hex(), info.Version, info.ChainID)
+		fmt.Printf("Threshold: %d of %d\n", info.Threshold, len(info.Owners))
+		fmt.Printf("Nonce:     %s\n", info.Nonce)
+		for _, o := range info.Owners {
+			fmt.Printf("Owner:     %s\n", o.Hex())
+		}
+		return nil
+	}
+	return cmd
+}
+
+// safePropose 构造下一个 nonce 上的 Safe 交易，用本地私钥（必须是 owner）签名，写出包含签名的 JSON 供其他 owner
+// 用 safe sign 签名；--submit 时同时提交到 Transaction Service
+func safePropose() *cobra.Command {
+	cmd := &cobra.Command{Use: "propose", Short: "Build and sign the next Safe transaction"}
+	fs := cmd.Flags()
+	sf := newSafeFlags(fs)
+	to := fs.String("to", "", "target address or address book name")
+	valueStr := fs.String("value", "0", "ETH sent by the Safe, e.g. 0.01eth")
+	dataHex := fs.String("data", "", "hex calldata for the target, e.g. from abi encode")
+	delegate := fs.Bool("delegatecall", false, "execute as DELEGATECALL instead of CALL (only for trusted library contracts)")
+	nonce := fs.Int64("nonce", -1, "Safe nonce to use (default: the next nonce), e.g. to replace a queued transaction")
+	out := fs.String("out", "", "write the Safe transaction JSON to this file instead of stdout")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if *to == "" {
+			return errors.New("--to is required")
+		}
+		value, err := units.ParseAmount(*valueStr)
+		if err != nil {
+			return fmt.Errorf("invalid --value: %w", err)
+		}
+		var data []byte
+		if *dataHex != "" {
+			if data, err = hexutil.Decode(*dataHex); err != nil {
+				return fmt.Errorf("invalid --data: %w", err)
+			}
+		}
+		key, err := loadPrivateKey()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, info, err := sf.load(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		owner := crypto.PubkeyToAddress(key.PublicKey)
+		if !info.IsOwner(owner) {
+			return fmt.Errorf("%s is not an owner of Safe %s", owner.Hex(), info.Address.Hex())
+		}
+		target, err := resolveAddress(ctx, client, *to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+		operation := safe.Call
+		if *delegate {
+			operation = safe.DelegateCall
+		}
+		tx := info.NewTx(target, value, data, operation)
+		if *nonce >= 0 {
+			tx.Nonce = big.NewInt(*nonce)
+		}
+		if _, err := tx.Sign(key); err != nil {
+			return err
+		}
+		if err := printSafeTx(tx, info); err != nil {
+			return err
+		}
+		if *sf.submit {
+			service, err := sf.serviceFor(info)
+			if err != nil {
+				return err
+			}
+			if err := service.Propose(ctx, tx, owner); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Submitted to %s\n", service.URL)
+		}
+		return writeSafeTx(*out, tx)
+	}
+	return cmd
+}
+
+// safeSign 用本地私钥为 Safe 交易追加签名：--in 读取 safe propose 写出的 JSON 并写回（或写到 --out），
+// --hash 从 Transaction Service 读取提案并提交确认
+func safeSign() *cobra.Command {
+	cmd := &cobra.Command{Use: "sign", Short: "Add a signature to a Safe transaction"}
+	fs := cmd.Flags()
+	sf := newSafeFlags(fs)
+	in := fs.String("in", "", "Safe transaction JSON file, - for stdin")
+	hashHex := fs.String("hash", "", "safeTxHash of a proposal in the Transaction Service")
+	out := fs.String("out", "", "where to write the signed JSON (default: overwrite --in, stdout for -)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if (*in == "") == (*hashHex == "") {
+			return errors.New("give either --in or --hash")
+		}
+		key, err := loadPrivateKey()
+		if err != nil {
+			return err
+		}
+		ctx := rootCtx
+		client, info, err := sf.load(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		owner := crypto.PubkeyToAddress(key.PublicKey)
+		if !info.IsOwner(owner) {
+			return fmt.Errorf("%s is not an owner of Safe %s", owner.Hex(), info.Address.Hex())
+		}
+
+		tx, service, err := loadSafeTx(ctx, sf, info, *in, *hashHex)
+		if err != nil {
+			return err
+		}
+		sig, err := tx.Sign(key)
+		if err != nil {
+			return err
+		}
+		if err := printSafeTx(tx, info); err != nil {
+			return err
+		}
+		if *sf.submit || *hashHex != "" {
+			if service == nil {
+				if service, err = sf.serviceFor(info); err != nil {
+					return err
+				}
+			}
+			hash, _ := tx.Hash()
+			if err := service.Confirm(ctx, hash, sig.Data); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Confirmation submitted to %s\n", service.URL)
+		}
+		if *in == "" {
+			return nil
+		}
+		if *out == "" && *in != "-" {
+			*out = *in
+		}
+		return writeSafeTx(*out, tx)
+	}
+	return cmd
+}
+
+// safeExec 用当前签名账户调用 execTransaction 执行已收集到足够签名的 Safe 交易。执行者是 owner 时自身算作一个确认；
+// --submit 时合并 Transaction Service 中的确认
+func safeExec() *cobra.Command {
+	cmd := &cobra.Command{Use: "exec", Short: "Execute a Safe transaction that has enough signatures"}
+	fs := cmd.Flags()
+	sf := newSafeFlags(fs)
+	in := fs.String("in", "", "Safe transaction JSON file, - for stdin")
+	hashHex := fs.String("hash", "", "safeTxHash of a proposal in the Transaction Service")
+	cf := newCallFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if (*in == "") == (*hashHex == "") {
+			return errors.New("give either --in or --hash")
+		}
+		if err := cf.check(); err != nil {
+			return err
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+		ctx := rootCtx
+		client, info, err := sf.load(ctx)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		tx, _, err := loadSafeTx(ctx, sf, info, *in, *hashHex)
+		if err != nil {
+			return err
+		}
+		if c := tx.Nonce.Cmp(info.Nonce); c != 0 {
+			if c < 0 {
+				return fmt.Errorf("transaction nonce %s was already used, the Safe is at nonce %s", tx.Nonce, info.Nonce)
+			}
+			return fmt.Errorf("transaction nonce %s is queued behind nonce %s, execute the earlier transactions first", tx.Nonce, info.Nonce)
+		}
+		signatures, err := tx.PackSignatures(info, w.Address())
+		if err != nil {
+			return err
+		}
+		data, err := tx.ExecData(signatures)
+		if err != nil {
+			return err
+		}
+		if err := printSafeTx(tx, info); err != nil {
+			return err
+		}
+		t, err := ethtx.PrepareCall(ctx, client, w.Address(), info.Address, new(big.Int), data)
+		if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			return fmt.Errorf("execTransaction would fail: %w", err)
+		}
+		_, err = cf.send(ctx, client, w, t)
+		return err
+	}
+	return cmd
+}
+
+// loadSafeTx 从文件或 Transaction Service 读取 Safe 交易，检查它属于 info 所在的 Safe 和链。
+// 从文件读取且 --submit 时合并服务中的确认；返回使用过的服务客户端
+func loadSafeTx(ctx context.Context, sf safeFlags, info *safe.Info, in, hashHex string) (*safe.Tx, *safe.Service, error) {
+	if hashHex != "" {
+		service, err := sf.serviceFor(info)
+		if err != nil {
+			return nil, nil, err
+		}
+		tx, executed, err := service.Get(ctx, info, common.HexToHash(hashHex))
+		if err != nil {
+			return nil, nil, err
+		}
+		if executed {
+			return nil, nil, fmt.Errorf("safe transaction %s was already executed", hashHex)
+		}
+		return tx, service, nil
+	}
+
+	data, err := readInput(in)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tx safe.Tx
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, nil, fmt.Errorf("parse Safe transaction: %w", err)
+	}
+	if tx.Safe != info.Address || tx.ChainID == nil || tx.ChainID.Cmp(info.ChainID) != 0 {
+		return nil, nil, fmt.Errorf("transaction is for Safe %s on chain %v, not %s on chain %s", tx.Safe.Hex(), tx.ChainID, info.Address.Hex(), info.ChainID)
+	}
+	// 文件中的签名可能被编辑过，逐个重新恢复签名者
+	sigs := tx.Signatures
+	tx.Signatures = nil
+	for _, s := range sigs {
+		signer, err := tx.AddSignature(s.Data)
+		if err != nil || signer != s.Signer {
+			return nil, nil, fmt.Errorf("signature listed for %s is not valid for this transaction", s.Signer.Hex())
+		}
+	}
+	if !*sf.submit {
+		return &tx, nil, nil
+	}
+	service, err := sf.serviceFor(info)
+	if err != nil {
+		return nil, nil, err
+	}
+	hash, err := tx.Hash()
+	if err != nil {
+		return nil, nil, err
+	}
+	remote, _, err := service.Get(ctx, info, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range remote.Signatures {
+		tx.AddSignature(s.Data)
+	}
+	return &tx, service, nil
+}
+
+// printSafeTx 把交易内容和签名进度写到 stderr
+func printSafeTx(tx *safe.Tx, info *safe.Info) error {
+	hash, err := tx.Hash()
+	if err != nil {
+		return err
+	}
+	op := "call"
+	if tx.Operation == safe.DelegateCall {
+		op = "delegatecall"
+	}
+	var owners []string
+	for _, s := range tx.Signatures {
+		if info.IsOwner(s.Signer) {
+			owners = append(owners, s.Signer.Hex())
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Safe tx:   %s\n", hash.Hex())
+	fmt.Fprintf(os.Stderr, "Action:    %s %s with %s wei and %d bytes of data, nonce %s\n", op, tx.To.Hex(), tx.Value, len(tx.Data), tx.Nonce)
+	fmt.Fprintf(os.Stderr, "Signed:    %d of %d required", len(owners), info.Threshold)
+	if len(owners) > 0 {
+		fmt.Fprintf(os.Stderr, " (%s)", strings.Join(owners, ", "))
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
+
+// writeSafeTx 把 Safe 交易写成缩进的 JSON
+func writeSafeTx(path string, tx *safe.Tx) error {
+	data, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeOutput(path, append(data, '\n'))
+}
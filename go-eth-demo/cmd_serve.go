@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/api"
+	"github.com/local/go-eth-demo/pkg/gateway"
+	"github.com/local/go-eth-demo/pkg/metrics"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// serve 以 HTTP/JSON API 的形式提供余额、代币余额、交易提交、合约调用和事件查询，
+// 设置 --grpc-addr 时同时在该地址上提供相同能力的 gRPC 服务（pkg/gateway）
+func serve() *cobra.Command {
+	cmd := &cobra.Command{Use: "serve", Short: "Serve balances, transactions, calls and events over HTTP and gRPC"}
+	fs := cmd.Flags()
+	addr := fs.String("addr", "127.0.0.1:8080", "listen address")
+	grpcAddr := fs.String("grpc-addr", os.Getenv("GRPC_ADDR"), "also serve the gRPC gateway (h2c) on this address, e.g. 127.0.0.1:9090 (default $GRPC_ADDR, disabled if empty)")
+	token := fs.String("token", os.Getenv("API_TOKEN"), "bearer token required by every request (default $API_TOKEN)")
+	mf := newMetricsFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if *token == "" {
+			return fmt.Errorf("an API token is required (use --token or API_TOKEN)")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		m, wallets, err := mf.start(ctx)
+		if err != nil {
+			return err
+		}
+		client, err := dialInstrumented(ctx, rpcEndpoint(), m)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the Ethereum client: %w", err)
+		}
+		defer client.Close()
+
+		handler, err := api.NewServer(client, *token)
+		if err != nil {
+			return err
+		}
+		if m != nil {
+			handler.OnRequest = m.Request
+			go m.Poll(ctx, client, wallets, *mf.interval)
+		}
+		srv := &http.Server{Addr: *addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+		errc := make(chan error, 2)
+		go func() { errc <- srv.ListenAndServe() }()
+		fmt.Printf("API listening on http://%s\n", *addr)
+
+		var grpcServer *grpc.Server
+		if *grpcAddr != "" {
+			gw, err := gateway.New(client, *token)
+			if err != nil {
+				return err
+			}
+			gw.OnRequest = func(method string, code codes.Code, elapsed time.Duration) {
+				logger("grpc").Debug("grpc call", "method", method, "code", code, "elapsed", elapsed)
+			}
+			lis, err := net.Listen("tcp", *grpcAddr)
+			if err != nil {
+				srv.Close()
+				return err
+			}
+			grpcServer = gw.GRPCServer()
+			go func() { errc <- grpcServer.Serve(lis) }()
+			fmt.Printf("gRPC gateway listening on %s\n", *grpcAddr)
+		}
+
+		select {
+		case err := <-errc:
+			srv.Close()
+			if grpcServer != nil {
+				grpcServer.Stop()
+			}
+			return err
+		case <-ctx.Done():
+		}
+		logger("api").Info("shutting down API server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if grpcServer != nil {
+			// 流式调用（WatchConfirmations）在客户端断开前不会结束，超时后直接关闭连接
+			stopped := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-shutdownCtx.Done():
+				grpcServer.Stop()
+			}
+		}
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			srv.Close()
+			if !errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+		}
+		return nil
+	}
+	return cmd
+}
+
+// metricsFlags 是常驻命令共用的指标参数
+type metricsFlags struct {
+	addr     *string
+	wallets  *string
+	interval *time.Duration
+}
+
+func newMetricsFlags(fs *pflag.FlagSet) metricsFlags {
+	return metricsFlags{
+		addr:     fs.String("metrics-addr", os.Getenv("METRICS_ADDR"), "serve Prometheus metrics on this address at /metrics, e.g. 127.0.0.1:6060 (default $METRICS_ADDR, disabled if empty)"),
+		wallets:  fs.String("metrics-wallets", os.Getenv("METRICS_WALLETS"), "comma-separated addresses whose balances and pending transactions are exported"),
+		interval: fs.Duration("metrics-interval", 30*time.Second, "how often the head and wallet gauges are refreshed"),
+	}
+}
+
+// start 在启用指标时启动 /metrics 服务，未启用时返回 nil
+func (mf metricsFlags) start(ctx context.Context) (*metrics.Metrics, []common.Address, error) {
+	if *mf.addr == "" {
+		return nil, nil, nil
+	}
+	var wallets []common.Address
+	for _, a := range splitList(*mf.wallets) {
+		addr, err := address.ParseHex(a)
+		if err != nil {
+			return nil, nil, fmt.Errorf("metrics wallet: %w", err)
+		}
+		wallets = append(wallets, addr)
+	}
+	m := metrics.New()
+	go func() {
+		if err := m.ListenAndServe(ctx, *mf.addr); err != nil {
+			logger("metrics").Warn("metrics server stopped", "err", err)
+		}
+	}()
+	fmt.Printf("Metrics available at http://%s/metrics\n", *mf.addr)
+	return m, wallets, nil
+}
+
+// dialInstrumented 连接节点，使用 RPC_CACHE 缓存，请求速率不超过 RPC_RATE_LIMIT，暂时性失败按 RPC_RETRIES 重试；m 不为 nil 时通过统计 RPC 调用的 transport 连接
+// （仅 HTTP 端点），每次重试都计入调用次数
+func dialInstrumented(ctx context.Context, url string, m *metrics.Metrics) (*ethclient.Client, error) {
+	t := cacheTransport(retryTransport(limitTransport(nil)))
+	if m != nil {
+		t = cacheTransport(retryTransport(limitTransport(m.Transport(nil))))
+	}
+	if t == nil {
+		return ethclient.DialContext(ctx, url)
+	}
+	client, err := rpc.DialOptions(ctx, url, rpc.WithHTTPClient(&http.Client{Transport: t}))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
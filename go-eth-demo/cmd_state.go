@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/multicall"
+	"github.com/local/go-eth-demo/pkg/networks"
+	"github.com/local/go-eth-demo/pkg/price"
+	"github.com/local/go-eth-demo/pkg/proof"
+	"github.com/local/go-eth-demo/pkg/rpcbatch"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// stateFlags 是 balance/storage 共用的参数
+type stateFlags struct {
+	verify    *bool
+	verifyURL *string
+}
+
+func newStateFlags(fs *pflag.FlagSet) stateFlags {
+	return stateFlags{
+		verify:    fs.Bool("verify", false, "verify the result with eth_getProof against a header from an independent provider"),
+		verifyURL: fs.String("verify-url", os.Getenv("VERIFY_RPC"), "independent RPC endpoint providing block headers (default $VERIFY_RPC)"),
+	}
+}
+
+// fiatFlag 注册 --fiat，默认取 $FIAT
+func fiatFlag(fs *pflag.FlagSet) *string {
+	return fs.String("fiat", os.Getenv("FIAT"), "also show values in this fiat currency, e.g. usd, eur or cny (default $FIAT)")
+}
+
+// fiatValues 返回把原生代币数量显示为法币价值的函数，结果形如 " (≈ $4,500.18)"。fiat 为空或查询价格
+// 失败时（只给出一次警告）返回空字符串，不影响其余输出
+func fiatValues(ctx context.Context, fiat string, currency networks.Currency) func(*big.Int) string {
+	none := func(*big.Int) string { return "" }
+	if fiat == "" {
+		return none
+	}
+	provider, err := priceProvider()
+	var p *big.Rat
+	if err == nil {
+		p, err = provider.Price(ctx, currency.Symbol, fiat)
+	}
+	if err != nil {
+		logger("price").Warn("price unavailable", "symbol", currency.Symbol, "fiat", strings.ToUpper(fiat), "err", err)
+		return none
+	}
+	return func(amount *big.Int) string {
+		return " (≈ " + price.Format(price.Value(amount, currency.Decimals, p), fiat) + ")"
+	}
+}
+
+// priceProvider 返回 PRICE_PROVIDER 选择的价格提供商（coingecko 或 coinbase），价格缓存在 $PRICE_CACHE
+func priceProvider() (price.Provider, error) {
+	var p price.Provider
+	switch name := envOr("PRICE_PROVIDER", "coingecko"); name {
+	case "coingecko":
+		p = price.NewCoinGecko(os.Getenv("COINGECKO_API_KEY"))
+	case "coinbase":
+		p = price.NewCoinbase()
+	default:
+		return nil, fmt.Errorf("unknown PRICE_PROVIDER %q, want coingecko or coinbase", name)
+	}
+	return price.NewCache(p, 0, envOr("PRICE_CACHE", ".price-cache.json")), nil
+}
+
+// balance 查询账户余额，--verify 时用 Merkle 证明验证。给出多个地址时通过 Multicall3 一次查询全部余额
+func balance() *cobra.Command {
+	cmd := &cobra.Command{Use: "balance <address>...", Short: "Show account balances, optionally verified with Merkle proofs"}
+	fs := cmd.Flags()
+	sf := newStateFlags(fs)
+	fiat := fiatFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() == 0 {
+			return fmt.Errorf("usage: balance [flags] <address>...")
+		}
+		var addrs []common.Address
+		for _, arg := range fs.Args() {
+			addr, err := address.ParseHex(arg)
+			if err != nil {
+				return err
+			}
+			addrs = append(addrs, addr)
+		}
+		if *sf.verify && len(addrs) > 1 {
+			return errors.New("--verify supports a single address")
+		}
+		addr := addrs[0]
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		preset := presetFor(ctx, client)
+		value := fiatValues(ctx, *fiat, preset.Currency)
+		if len(addrs) > 1 {
+			return printBalances(ctx, client, preset, addrs, value)
+		}
+		if !*sf.verify {
+			bal, err := client.BalanceAt(ctx, addr, nil)
+			if err != nil {
+				return fmt.Errorf("failed to get balance: %w", err)
+			}
+			printBalance(preset, addr, bal, value)
+			return nil
+		}
+
+		account, err := verifiedAccount(ctx, client, *sf.verifyURL, addr, nil)
+		if err != nil {
+			return err
+		}
+		printBalance(preset, addr, account.Balance, value)
+		fmt.Printf("Nonce: %d\n", account.Nonce)
+		fmt.Printf("✅ Verified against block %d (state root %s)\n", account.BlockNumber, account.StateRoot.Hex())
+		return nil
+	}
+	return cmd
+}
+
+// printBalances 在同一个区块上查询多个地址的余额并列表显示。优先用 Multicall3 一次查询，
+// 链上没有部署 Multicall3 时退回逐个 eth_getBalance
+func printBalances(ctx context.Context, client chain.Client, preset networks.Preset, addrs []common.Address, value func(*big.Int) string) error {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	block := new(big.Int).SetUint64(head)
+	balances, err := multicall.New(client, preset.Multicall).Balances(ctx, addrs, block)
+	if errors.Is(err, multicall.ErrNotDeployed) {
+		balances = make([]*big.Int, len(addrs))
+		for i, addr := range addrs {
+			if balances[i], err = client.BalanceAt(ctx, addr, block); err != nil {
+				return fmt.Errorf("failed to get balance of %s: %w", addr.Hex(), err)
+			}
+		}
+	} else if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "ADDRESS\tBALANCE (%s)\t\n", preset.Currency.Symbol)
+	for i, addr := range addrs {
+		fmt.Fprintf(tw, "%s\t%s%s\t\n", addr.Hex(), units.FormatUnits(balances[i], preset.Currency.Decimals), value(balances[i]))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("Block: %d\n", head)
+	return nil
+}
+
+// balanceHistory 用批量 JSON-RPC 请求读取账户在一段区块范围内均匀分布的 --points 个区块上的余额。
+// 较早的区块需要归档节点
+func balanceHistory() *cobra.Command {
+	cmd := &cobra.Command{Use: "history <address>", Short: "Show an account's balance across a block range"}
+	fs := cmd.Flags()
+	from := fs.Int64("from", -1, "first block (default: 1000 blocks before --to)")
+	to := fs.Int64("to", -1, "last block (default: latest)")
+	points := fs.Int("points", 20, "number of blocks to sample between --from and --to")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: balance history [flags] <address>")
+		}
+		if *points < 2 {
+			return fmt.Errorf("--points must be at least 2")
+		}
+		addr, err := address.ParseHex(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		end := uint64(*to)
+		if *to < 0 {
+			if end, err = client.BlockNumber(ctx); err != nil {
+				return err
+			}
+		}
+		start := uint64(*from)
+		if *from < 0 {
+			start = end - min(end, 1000)
+		}
+		if start > end {
+			return fmt.Errorf("--from %d is after --to %d", start, end)
+		}
+		// 在 [start, end] 中均匀取样，包含两端，区块数不足时每个区块都取
+		var blocks []uint64
+		for i := 0; i < *points; i++ {
+			b := start + (end-start)*uint64(i)/uint64(*points-1)
+			if len(blocks) == 0 || blocks[len(blocks)-1] != b {
+				blocks = append(blocks, b)
+			}
+		}
+		balances, err := rpcbatch.BalancesAt(ctx, client.Client(), addr, blocks)
+		if err != nil {
+			return err
+		}
+		preset := presetFor(ctx, client)
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintf(tw, "BLOCK\tBALANCE (%s)\tCHANGE\t\n", preset.Currency.Symbol)
+		for i, b := range blocks {
+			change := ""
+			if i > 0 {
+				if delta := new(big.Int).Sub(balances[i], balances[i-1]); delta.Sign() != 0 {
+					change = units.FormatUnits(delta, preset.Currency.Decimals)
+					if delta.Sign() > 0 {
+						change = "+" + change
+					}
+				}
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t\n", b, units.FormatUnits(balances[i], preset.Currency.Decimals), change)
+		}
+		return tw.Flush()
+	}
+	return cmd
+}
+
+// printBalance 按链的原生代币显示余额，有区块浏览器时附上地址链接
+func printBalance(preset networks.Preset, addr common.Address, bal *big.Int, value func(*big.Int) string) {
+	fmt.Printf("Balance of %s: %s %s%s\n", addr.Hex(), units.FormatUnits(bal, preset.Currency.Decimals), preset.Currency.Symbol, value(bal))
+	if url := preset.AddressURL(addr); url != "" {
+		fmt.Printf("Explorer: %s\n", url)
+	}
+}
+
+// storage 读取合约存储槽，--verify 时用 Merkle 证明验证
+func storage() *cobra.Command {
+	cmd := &cobra.Command{Use: "storage <address> <slot>", Short: "Read a contract storage slot, optionally verified with a Merkle proof"}
+	fs := cmd.Flags()
+	sf := newStateFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 2 {
+			return fmt.Errorf("usage: storage [flags] <address> <slot>")
+		}
+		addr, err := address.ParseHex(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		slot := common.HexToHash(fs.Arg(1))
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if !*sf.verify {
+			value, err := client.StorageAt(ctx, addr, slot, nil)
+			if err != nil {
+				return fmt.Errorf("failed to read storage: %w", err)
+			}
+			fmt.Printf("Storage %s[%s]: %s\n", addr.Hex(), slot.Hex(), common.BytesToHash(value).Hex())
+			return nil
+		}
+
+		account, err := verifiedAccount(ctx, client, *sf.verifyURL, addr, []common.Hash{slot})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Storage %s[%s]: %s\n", addr.Hex(), slot.Hex(), account.Storage[slot].Hex())
+		fmt.Printf("✅ Verified against block %d (state root %s)\n", account.BlockNumber, account.StateRoot.Hex())
+		return nil
+	}
+	return cmd
+}
+
+// verifiedAccount 连接独立的参考提供商并验证账户状态
+func verifiedAccount(ctx context.Context, client *ethclient.Client, verifyURL string, addr common.Address, slots []common.Hash) (*proof.Account, error) {
+	if verifyURL == "" {
+		return nil, fmt.Errorf("--verify requires an independent provider (use --verify-url or VERIFY_RPC)")
+	}
+	reference, err := ethclient.DialContext(ctx, verifyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the reference provider: %w", err)
+	}
+	defer reference.Close()
+
+	account, err := proof.NewVerifier(gethclient.New(client.Client()), reference).Account(ctx, addr, slots, nil)
+	if errors.Is(err, proof.ErrMismatch) {
+		return nil, fmt.Errorf("❌ verification FAILED, the provider returned data inconsistent with the reference header: %w", err)
+	}
+	return account, err
+}
@@ -0,0 +1,809 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/go-eth-demo/counter"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/arbitrum"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/counterflow"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/multicall"
+	"github.com/local/go-eth-demo/pkg/notify"
+	"github.com/local/go-eth-demo/pkg/opstack"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// transfer 是 task01 转账流程的命令行版本，金额、接收方和 gas 参数都可以通过参数指定
+func transfer() *cobra.Command {
+	cmd := &cobra.Command{Use: "transfer", Short: "Send ETH (task01 with flags)"}
+	fs := cmd.Flags()
+	to := fs.String("to", os.Getenv("RECIPIENT_ADDR"), "recipient address or address book name (default $RECIPIENT_ADDR)")
+	amountStr := fs.String("amount", "0.001eth", "amount to send, e.g. 0.001eth or 1000000 gwei")
+	all := fs.Bool("all", false, "send the whole balance minus the maximum gas fee, leaving the account empty")
+	legacy := fs.Bool("legacy", false, "send a legacy transaction instead of EIP-1559 (for chains without dynamic fees)")
+	gasPriceStr := fs.String("gas-price", "", "legacy gas price, e.g. 2gwei (default: eth_gasPrice)")
+	maxFeeStr := fs.String("max-fee", "", "EIP-1559 maxFeePerGas, e.g. 30gwei (default: 2 × base fee + tip)")
+	tipStr := fs.String("priority-fee", "", "EIP-1559 maxPriorityFeePerGas, e.g. 1gwei (default: median tip from eth_feeHistory)")
+	strategyName := feeStrategyFlag(fs)
+	dataHex := fs.String("data", "", "hex calldata to include, e.g. 0x1234")
+	fiat := fiatFlag(fs)
+	gasLimit := fs.Uint64("gas-limit", 0, "gas limit (default: estimated)")
+	gasBuffer := gasBufferFlag(fs)
+	wait := fs.Bool("wait", true, "wait for the transaction to be mined")
+	dryRun := dryRunFlag(fs)
+	wf := newWaitFlags(fs)
+	output := outputFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if *to == "" {
+			return errors.New("--to or RECIPIENT_ADDR is required")
+		}
+		if err := setOutput(*output); err != nil {
+			return err
+		}
+		if *gasPriceStr != "" && !*legacy {
+			return fmt.Errorf("--gas-price requires --legacy, use --max-fee and --priority-fee for EIP-1559")
+		}
+		if (*maxFeeStr != "" || *tipStr != "") && *legacy {
+			return fmt.Errorf("--max-fee and --priority-fee cannot be used with --legacy")
+		}
+		if *all {
+			if fs.Changed("amount") {
+				return errors.New("--amount cannot be used with --all")
+			}
+			if *tipStr != "" {
+				return errors.New("--priority-fee cannot be used with --all, which pays the whole max fee as the priority fee")
+			}
+			*amountStr = "0"
+		}
+		if _, err := wf.tag(); err != nil {
+			return err
+		}
+		strategy, err := ethtx.ParseFeeStrategy(*strategyName)
+		if err != nil {
+			return err
+		}
+		amount, err := units.ParseAmount(*amountStr)
+		if err != nil {
+			return err
+		}
+		var data []byte
+		if *dataHex != "" {
+			if data, err = hexutil.Decode(*dataHex); err != nil {
+				return fmt.Errorf("invalid --data: %w", err)
+			}
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		recipient, err := resolveAddress(ctx, client, *to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+
+		prepare := ethtx.Prepare
+		if *legacy {
+			prepare = ethtx.PrepareLegacy
+		}
+		t, err := prepare(ctx, client, w.Address(), recipient, amount)
+		if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			return err
+		}
+		if (*maxFeeStr != "" || *tipStr != "") && !t.Dynamic() {
+			return fmt.Errorf("%w, use --legacy with --gas-price", ethtx.ErrNoDynamicFees)
+		}
+		if strategy != ethtx.StandardFees {
+			if err := t.SetFees(ctx, client, strategy); err != nil {
+				return err
+			}
+		}
+		// 覆盖节点建议的 gas 参数后重新检查余额
+		if t.Data = data; *gasLimit != 0 {
+			t.GasLimit = *gasLimit
+		} else if len(t.Data) > 0 || *gasBuffer != ethtx.DefaultGasBuffer {
+			if err := t.EstimateGas(ctx, client, *gasBuffer); err != nil {
+				return err
+			}
+		}
+		for _, o := range []struct {
+			flag  string
+			value **big.Int
+		}{{*gasPriceStr, &t.GasPrice}, {*maxFeeStr, &t.GasFeeCap}, {*tipStr, &t.GasTipCap}} {
+			if o.flag == "" {
+				continue
+			}
+			if *o.value, err = units.ParseAmount(o.flag); err != nil {
+				return err
+			}
+		}
+		// 转出全部余额时 L2 的费用按余额作为金额估算（编码最长），金额取决于最终的 gas 参数，最后计算
+		if *all {
+			t.Value = t.Balance
+		}
+		if err := adjustForL2(ctx, client, t, *gasBuffer, *gasLimit != 0); err != nil {
+			return err
+		}
+		if *all {
+			if err := t.SendAll(); err != nil {
+				return err
+			}
+			if t.GasLimit != ethtx.TransferGas {
+				logger("tx").Warn("recipient is a contract or --data is set, unused gas will be refunded and left in the account", "gas", t.GasLimit)
+			}
+		}
+		if t.Dynamic() && t.GasTipCap.Cmp(t.GasFeeCap) > 0 {
+			return fmt.Errorf("priority fee %s gwei exceeds max fee %s gwei", units.FormatGwei(t.GasTipCap, 2), units.FormatGwei(t.GasFeeCap, 2))
+		}
+		if err := t.Check(); err != nil {
+			return err
+		}
+
+		preset := presetFor(ctx, client)
+		symbol := preset.Currency.Symbol
+		label := addressLabels(ctx, client, preset, t.From, t.To)
+		value := fiatValues(ctx, *fiat, preset.Currency)
+		fmt.Printf("From:      %s\n", label(t.From))
+		fmt.Printf("To:        %s\n", label(t.To))
+		fmt.Printf("Amount:    %s %s%s\n", units.FormatUnits(t.Value, preset.Currency.Decimals), symbol, value(t.Value))
+		fmt.Printf("Gas Limit: %d\n", t.GasLimit)
+		printTransferFees(t)
+		fmt.Printf("Max cost:  %s %s%s\n", units.FormatUnits(t.Cost(), preset.Currency.Decimals), symbol, value(t.Cost()))
+
+		if *dryRun {
+			tx, err := ethtx.Sign(w, t)
+			if err != nil {
+				return err
+			}
+			if err := printDryRun(tx); err != nil {
+				return err
+			}
+			report := newTxReport(preset, t.From, tx)
+			report.BalanceBefore = t.Balance.String()
+			if err := report.setRaw(tx); err != nil {
+				return err
+			}
+			return emit(report)
+		}
+		tx, err := ethtx.Send(ctx, client, w, t)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction: %s\n", tx.Hash().Hex())
+		if url := preset.TxURL(tx.Hash()); url != "" {
+			fmt.Printf("Explorer:    %s\n", url)
+		}
+		report := newTxReport(preset, t.From, tx)
+		report.BalanceBefore = t.Balance.String()
+		if *wait {
+			receipt, err := wf.wait(ctx, client, tx)
+			if err != nil {
+				return err
+			}
+			report.setReceipt(receipt)
+			// 发送方在交易所在区块之后的余额，减少量为金额加实际费用
+			after, err := client.BalanceAt(ctx, t.From, receipt.BlockNumber)
+			if err != nil {
+				return err
+			}
+			report.BalanceAfter = after.String()
+		}
+		return emit(report)
+	}
+	return cmd
+}
+
+// waitFlags 是等待交易确认的参数，默认只等待打包
+type waitFlags struct {
+	confirmations *uint64
+	finality      *string
+}
+
+func newWaitFlags(fs *pflag.FlagSet) waitFlags {
+	return waitFlags{
+		confirmations: fs.Uint64("confirmations", envUint("WAIT_CONFIRMATIONS", 1), "blocks to wait for, including the one with the transaction (default $WAIT_CONFIRMATIONS or 1)"),
+		finality:      fs.String("finality", os.Getenv("WAIT_FINALITY"), "also wait until the transaction's block is safe or finalized (default $WAIT_FINALITY)"),
+	}
+}
+
+// waitFromEnv 返回只由 WAIT_CONFIRMATIONS/WAIT_FINALITY 决定的等待参数，供 task01/task02 使用
+func waitFromEnv() waitFlags {
+	return newWaitFlags(pflag.NewFlagSet("wait", pflag.ContinueOnError))
+}
+
+// enabled 报告是否需要在打包之外继续等待
+func (f waitFlags) enabled() bool {
+	return *f.confirmations > 1 || *f.finality != ""
+}
+
+// tag 返回 --finality 对应的区块标签，未设置时为 0
+func (f waitFlags) tag() (rpc.BlockNumber, error) {
+	switch *f.finality {
+	case "":
+		return 0, nil
+	case "safe":
+		return rpc.SafeBlockNumber, nil
+	case "finalized":
+		return rpc.FinalizedBlockNumber, nil
+	}
+	return 0, fmt.Errorf("invalid finality %q, want safe or finalized", *f.finality)
+}
+
+// wait 等待交易达到要求的确认数，再按需等待 safe/finalized，打印进度
+func (f waitFlags) wait(ctx context.Context, client *ethclient.Client, tx *types.Transaction) (*types.Receipt, error) {
+	tag, err := f.tag()
+	if err != nil {
+		return nil, err
+	}
+	logger("tx").Debug("waiting for transaction", "hash", tx.Hash().Hex(), "confirmations", *f.confirmations, "finality", *f.finality)
+	// wait 在交易广播之后调用，由它发送交易的各个事件通知
+	events := newTxEvents(ctx, client, tx, max(*f.confirmations, 1))
+	events.send(ctx, notify.TxSubmitted, nil, nil)
+	receipt, err := ethtx.WaitConfirmed(ctx, client, tx, 1)
+	if err == nil {
+		events.send(ctx, notify.TxMined, receipt, nil)
+		if *f.confirmations > 1 {
+			receipt, err = ethtx.WaitConfirmed(ctx, client, tx, *f.confirmations)
+		}
+	}
+	if err != nil {
+		if receipt != nil {
+			events.send(ctx, notify.TxFailed, receipt, err)
+		}
+		return nil, err
+	}
+	events.send(ctx, notify.TxConfirmed, receipt, nil)
+	fmt.Printf("✅ Included in block %s, gas used %d", receipt.BlockNumber, receipt.GasUsed)
+	if *f.confirmations > 1 {
+		fmt.Printf(" (%d confirmations)", *f.confirmations)
+	}
+	fmt.Println()
+	if tag == 0 {
+		return receipt, nil
+	}
+	fmt.Printf("Waiting for block %s to be %s...\n", receipt.BlockNumber, *f.finality)
+	if receipt, err = ethtx.WaitFinalized(ctx, client, tx, tag); err != nil {
+		return nil, err
+	}
+	fmt.Printf("✅ Block %s is %s\n", receipt.BlockNumber, *f.finality)
+	return receipt, nil
+}
+
+// envUint 读取非负整数环境变量，未设置或无效时返回 def
+func envUint(key string, def uint64) uint64 {
+	if v, err := strconv.ParseUint(os.Getenv(key), 10, 64); err == nil {
+		return v
+	}
+	return def
+}
+
+// feeStrategyFlag 注册 --fee-strategy，默认取 $FEE_STRATEGY
+func feeStrategyFlag(fs *pflag.FlagSet) *string {
+	return fs.String("fee-strategy", os.Getenv("FEE_STRATEGY"), "EIP-1559 fee strategy: slow, standard, fast or a tip percentile such as p75 (default $FEE_STRATEGY or standard)")
+}
+
+// applyFeeStrategy 按策略 s 设置 auth 的 EIP-1559 费用，链不支持 EIP-1559 时交给绑定使用 eth_gasPrice
+func applyFeeStrategy(ctx context.Context, client *ethclient.Client, auth *bind.TransactOpts, s ethtx.FeeStrategy) error {
+	fees, err := ethtx.SuggestFeesWith(ctx, client, s)
+	if errors.Is(err, ethtx.ErrNoDynamicFees) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	auth.GasTipCap, auth.GasFeeCap = fees.GasTipCap, fees.GasFeeCap
+	return nil
+}
+
+// gasBufferFlag 注册 --gas-buffer：估算 gas 后增加的余量百分比，默认取 $GAS_BUFFER
+func gasBufferFlag(fs *pflag.FlagSet) *int {
+	return fs.Int("gas-buffer", int(envUint("GAS_BUFFER", ethtx.DefaultGasBuffer)), "percent added to the estimated gas limit (default $GAS_BUFFER or 20)")
+}
+
+// dryRunFlag 注册 --dry-run，默认取 $DRY_RUN
+func dryRunFlag(fs *pflag.FlagSet) *bool {
+	return fs.Bool("dry-run", envBool("DRY_RUN"), "sign the transaction but print it instead of broadcasting (default $DRY_RUN)")
+}
+
+// envBool 读取布尔环境变量，未设置或无效时返回 false
+func envBool(key string) bool {
+	v, _ := strconv.ParseBool(os.Getenv(key))
+	return v
+}
+
+// printDryRun 显示未广播的已签名交易，可以稍后用 tx broadcast 发送
+func printDryRun(tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("encode transaction: %w", err)
+	}
+	fmt.Println("\n=== Dry Run: transaction NOT broadcast ===")
+	fmt.Printf("Transaction Hash: %s\n", tx.Hash().Hex())
+	fmt.Printf("Raw Transaction: %s\n", hexutil.Encode(raw))
+	return nil
+}
+
+// prepareIncrement 模拟 increment，auth 未指定 gas 上限时估算并加上 buffer% 的余量
+func prepareIncrement(ctx context.Context, client *ethclient.Client, address common.Address, auth *bind.TransactOpts, buffer int) error {
+	msg, err := counterflow.IncrementCall(address, auth.From)
+	if err != nil {
+		return err
+	}
+	if err := ethtx.Simulate(ctx, client, msg); err != nil {
+		return err
+	}
+	if auth.GasLimit == 0 {
+		auth.GasLimit, err = ethtx.EstimateGas(ctx, client, msg, buffer)
+	}
+	return err
+}
+
+// dryRunIncrement 签名 increment 交易但不广播，打印后返回该交易，应先调用 prepareIncrement
+func dryRunIncrement(client *ethclient.Client, address common.Address, auth *bind.TransactOpts) (*types.Transaction, error) {
+	contract, err := counter.NewCounter(address, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contract instance: %w", err)
+	}
+	opts := *auth
+	opts.NoSend = true
+	tx, err := contract.Increment(&opts)
+	if err != nil {
+		return nil, fmt.Errorf("increment counter: %w", err)
+	}
+	fmt.Printf("Nonce:     %d\n", tx.Nonce())
+	fmt.Printf("Gas Limit: %d\n", tx.Gas())
+	if tx.Type() == types.DynamicFeeTxType {
+		fmt.Printf("Max Fee: %s Gwei\n", units.FormatGwei(tx.GasFeeCap(), 2))
+		fmt.Printf("Priority Fee: %s Gwei\n", units.FormatGwei(tx.GasTipCap(), 2))
+	} else {
+		fmt.Printf("Gas Price: %s Gwei (legacy)\n", units.FormatGwei(tx.GasPrice(), 2))
+	}
+	fmt.Printf("Max cost:  %s ETH\n", units.FormatEther(tx.Cost(), 6))
+	return tx, printDryRun(tx)
+}
+
+// printTransferFees 显示转账的费用参数：EIP-1559 交易显示 maxFee 和小费，传统交易显示 gas 价格
+func printTransferFees(t *ethtx.Transfer) {
+	if t.Dynamic() {
+		fmt.Printf("Max Fee: %s Gwei\n", units.FormatGwei(t.GasFeeCap, 2))
+		fmt.Printf("Priority Fee: %s Gwei\n", units.FormatGwei(t.GasTipCap, 2))
+	} else {
+		fmt.Printf("Gas Price: %s Gwei (legacy)\n", units.FormatGwei(t.GasPrice, 2))
+	}
+	if t.L1Fee != nil {
+		fmt.Printf("L1 Data Fee: %s ETH (estimated, +%d%%)\n", units.FormatEther(t.L1Fee, 9), ethtx.L1FeeBuffer)
+	}
+}
+
+// l1FeeOracle 返回 client 所在链的 L1 数据费用适配器：OP Stack 链使用 GasPriceOracle 预部署合约，
+// 其他链返回 nil
+func l1FeeOracle(ctx context.Context, client *ethclient.Client) (ethtx.L1FeeOracle, error) {
+	l2, err := opstack.Detect(ctx, client)
+	if err != nil || !l2 {
+		return nil, err
+	}
+	return &opstack.GasPriceOracle{Client: client}, nil
+}
+
+// adjustForL2 按 client 所在的 L2 调整 t 的费用估算，之后需要重新 Check；其他链不做处理。
+// OP Stack 链另外收取 L1 数据费用，计入 Cost（见 l1FeeOracle）。Arbitrum 把 L1 数据费用折算为 gas 计入 gas 用量，
+// gas 价格乘 gas 上限的算法会高估费用，这里用 gasEstimateComponents 重新估算 gas 上限（加 buffer% 余量，
+// keepGasLimit 时保留用户指定的上限），打印 L1 和 L2 两部分以及按当前 L2 基础费计算的预计费用
+func adjustForL2(ctx context.Context, client *ethclient.Client, t *ethtx.Transfer, buffer int, keepGasLimit bool) error {
+	o, err := l1FeeOracle(ctx, client)
+	if err != nil {
+		return err
+	}
+	if o != nil {
+		return t.SetL1Fee(ctx, o)
+	}
+	if arb, err := arbitrum.Detect(ctx, client); err != nil || !arb {
+		return err
+	}
+	g, err := arbitrum.EstimateGas(ctx, client, t.CallMsg())
+	if err != nil {
+		return err
+	}
+	if !keepGasLimit {
+		// L1 部分随 L1 gas 价格波动，余量加在总量上
+		t.GasLimit = g.Total + g.Total*uint64(max(buffer, 0))/100
+	}
+	fmt.Printf("Arbitrum gas: %d for L2 execution + %d for L1 data = %d\n", g.L2(), g.ForL1, g.Total)
+	fmt.Printf("Expected fee: %s ETH at L2 base fee %s Gwei (priority fee is ignored)\n",
+		units.FormatEther(g.Fee(), 9), units.FormatGwei(g.L2BaseFee, 4))
+	return nil
+}
+
+// counterDeploy 用 abigen 绑定部署新的 Counter 合约，等待收据后显示合约地址；
+// --write-env 时把 CONTRACT_ADDR 写回 .env，之后的 task02 和 counter 命令直接使用新合约
+func counterDeploy() *cobra.Command {
+	cmd := &cobra.Command{Use: "deploy", Short: "Deploy a new Counter contract"}
+	fs := cmd.Flags()
+	writeEnv := fs.Bool("write-env", false, "save the deployed address as CONTRACT_ADDR in --env-file")
+	envFile := fs.String("env-file", ".env", "dotenv file updated by --write-env")
+	strategyName := feeStrategyFlag(fs)
+	dryRun := dryRunFlag(fs)
+	wf := newWaitFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if _, err := wf.tag(); err != nil {
+			return err
+		}
+		strategy, err := ethtx.ParseFeeStrategy(*strategyName)
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		auth, err := loadTransactor(ctx, client)
+		if err != nil {
+			return err
+		}
+		auth.Context = ctx
+		auth.NoSend = *dryRun
+		if err := applyFeeStrategy(ctx, client, auth, strategy); err != nil {
+			return err
+		}
+		address, tx, _, err := counter.DeployCounter(auth, client)
+		if err != nil {
+			return fmt.Errorf("deploy counter: %w", err)
+		}
+		fmt.Printf("Deployer:    %s\n", auth.From.Hex())
+		fmt.Printf("Address:     %s (predicted from nonce %d)\n", address.Hex(), tx.Nonce())
+		if *dryRun {
+			return printDryRun(tx)
+		}
+		fmt.Printf("Transaction: %s\n", tx.Hash().Hex())
+		if url := presetFor(ctx, client).TxURL(tx.Hash()); url != "" {
+			fmt.Printf("Explorer:    %s\n", url)
+		}
+		receipt, err := wf.wait(ctx, client, tx)
+		if err != nil {
+			return err
+		}
+		if receipt.ContractAddress != address {
+			return fmt.Errorf("receipt reports contract address %s, expected %s", receipt.ContractAddress.Hex(), address.Hex())
+		}
+		fmt.Printf("✅ Counter deployed at %s\n", address.Hex())
+		if !*writeEnv {
+			fmt.Printf("Set CONTRACT_ADDR=%s (or rerun with --write-env) to use it in task02\n", address.Hex())
+			return nil
+		}
+		if err := setEnvVar(*envFile, "CONTRACT_ADDR", address.Hex()); err != nil {
+			return err
+		}
+		fmt.Printf("Saved CONTRACT_ADDR to %s\n", *envFile)
+		return nil
+	}
+	return cmd
+}
+
+// setEnvVar 在 dotenv 文件中把 key 设为 value：替换已有的 key= 行（保留 export 前缀），
+// 没有时追加到末尾，文件不存在时创建。其他行和注释保持不变。
+func setEnvVar(path, key, value string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	found := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		prefix := ""
+		if rest, ok := strings.CutPrefix(trimmed, "export "); ok {
+			prefix, trimmed = "export ", strings.TrimSpace(rest)
+		}
+		if name, _, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(name) == key {
+			lines[i] = prefix + key + "=" + value
+			found = true
+		}
+	}
+	if !found {
+		lines = append(lines, key+"="+value)
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+}
+
+// counterIncrement 是 task02 的命令行版本：对 Counter 合约调用 increment 并显示前后的计数
+func counterIncrement() *cobra.Command {
+	cmd := &cobra.Command{Use: "increment", Short: "Increment the Counter contract (task02 with flags)"}
+	fs := cmd.Flags()
+	contractAddr := fs.String("contract", os.Getenv("CONTRACT_ADDR"), "Counter contract address (default $CONTRACT_ADDR)")
+	gasPriceStr := fs.String("gas-price", "", "legacy gas price, e.g. 2gwei (default: EIP-1559 fees from --fee-strategy)")
+	gasLimit := fs.Uint64("gas-limit", 0, "gas limit (default: estimated)")
+	gasBuffer := gasBufferFlag(fs)
+	strategyName := feeStrategyFlag(fs)
+	dryRun := dryRunFlag(fs)
+	wf := newWaitFlags(fs)
+	output := outputFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		contract, err := address.ParseHex(*contractAddr)
+		if err != nil {
+			return fmt.Errorf("--contract: %w", err)
+		}
+		if err := setOutput(*output); err != nil {
+			return err
+		}
+		if _, err := wf.tag(); err != nil {
+			return err
+		}
+		strategy, err := ethtx.ParseFeeStrategy(*strategyName)
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		auth, err := loadTransactor(ctx, client)
+		if err != nil {
+			return err
+		}
+		auth.Context = ctx
+		auth.GasLimit = *gasLimit
+		if *gasPriceStr != "" {
+			if auth.GasPrice, err = units.ParseAmount(*gasPriceStr); err != nil {
+				return err
+			}
+		} else if err := applyFeeStrategy(ctx, client, auth, strategy); err != nil {
+			return err
+		}
+		if err := prepareIncrement(ctx, client, contract, auth, *gasBuffer); err != nil {
+			return err
+		}
+		preset := presetFor(ctx, client)
+		if *dryRun {
+			tx, err := dryRunIncrement(client, contract, auth)
+			if err != nil {
+				return err
+			}
+			report := newTxReport(preset, auth.From, tx)
+			if err := report.setRaw(tx); err != nil {
+				return err
+			}
+			return emit(report)
+		}
+		result, err := counterflow.Increment(ctx, client, contract, auth)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction: %s (block %s, gas used %d)\n", result.Tx.Hash().Hex(), result.Receipt.BlockNumber, result.Receipt.GasUsed)
+		fmt.Printf("Count:       %s -> %s\n", result.Before, result.After)
+		for _, ev := range result.Events {
+			fmt.Printf("Event:       CountIncremented(newValue=%s, by=%s)\n", ev.NewValue, ev.By.Hex())
+		}
+		if !result.Incremented() {
+			return fmt.Errorf("counter did not increment: before %s, after %s", result.Before, result.After)
+		}
+		report := newTxReport(preset, auth.From, result.Tx)
+		report.setIncrement(result)
+		if wf.enabled() {
+			receipt, err := wf.wait(ctx, client, result.Tx)
+			if err != nil {
+				return err
+			}
+			report.setReceipt(receipt)
+		}
+		return emit(report)
+	}
+	return cmd
+}
+
+// counterGet 读取 Counter 合约的当前计数。--contract 给出多个地址时通过 Multicall3 一次读取，
+// 单个合约读取失败只在对应行显示错误
+func counterGet() *cobra.Command {
+	cmd := &cobra.Command{Use: "get", Short: "Read the Counter contract's count"}
+	fs := cmd.Flags()
+	contractAddr := fs.String("contract", os.Getenv("CONTRACT_ADDR"), "Counter contract address, comma separated for several (default $CONTRACT_ADDR)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var addrs []common.Address
+		for _, a := range strings.Split(*contractAddr, ",") {
+			addr, err := address.ParseHex(a)
+			if err != nil {
+				return fmt.Errorf("--contract: %w", err)
+			}
+			addrs = append(addrs, addr)
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		if len(addrs) > 1 {
+			return printCounts(ctx, client, addrs)
+		}
+		contract, err := counter.NewCounter(addrs[0], client)
+		if err != nil {
+			return fmt.Errorf("failed to create contract instance: %w", err)
+		}
+		count, err := contract.GetCount(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return fmt.Errorf("failed to get counter value: %w", err)
+		}
+		fmt.Println(count)
+		return nil
+	}
+	return cmd
+}
+
+// printCounts 用 Multicall3 一次读取多个 Counter 的计数，链上没有 Multicall3 时逐个读取
+func printCounts(ctx context.Context, client chain.Client, addrs []common.Address) error {
+	parsed, err := counter.CounterMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	calls := make([]multicall.Call, len(addrs))
+	for i, addr := range addrs {
+		if calls[i], err = multicall.Pack(addr, parsed, "getCount"); err != nil {
+			return err
+		}
+	}
+	results, err := multicall.New(client, presetFor(ctx, client).Multicall).Aggregate(ctx, calls, nil)
+	if errors.Is(err, multicall.ErrNotDeployed) {
+		results = make([]multicall.Result, len(addrs))
+		for i, call := range calls {
+			out, err := client.CallContract(ctx, ethereum.CallMsg{To: &call.Target, Data: call.Data}, nil)
+			results[i] = multicall.Result{Success: err == nil, ReturnData: out}
+		}
+	} else if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTRACT\tCOUNT")
+	for i, r := range results {
+		values, err := r.Unpack(parsed, "getCount")
+		if err != nil {
+			fmt.Fprintf(tw, "%s\terror: %v\n", addrs[i].Hex(), err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", addrs[i].Hex(), values[0])
+	}
+	return tw.Flush()
+}
+
+// counterHistory 查询一段区块范围内的 CountIncremented 事件，默认查询最近 --blocks 个区块
+func counterHistory() *cobra.Command {
+	cmd := &cobra.Command{Use: "history", Short: "List CountIncremented events in a block range"}
+	fs := cmd.Flags()
+	contractAddr := fs.String("contract", os.Getenv("CONTRACT_ADDR"), "Counter contract address (default $CONTRACT_ADDR)")
+	from := fs.Int64("from", -1, "first block (default: --blocks before --to)")
+	to := fs.Int64("to", -1, "last block (default: latest)")
+	blocks := fs.Uint64("blocks", 10000, "number of recent blocks to search when --from is not set")
+	byList := fs.String("by", "", "only show increments sent by these addresses, comma separated")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		contract, err := address.ParseHex(*contractAddr)
+		if err != nil {
+			return fmt.Errorf("--contract: %w", err)
+		}
+		var by []common.Address
+		for _, a := range strings.Split(*byList, ",") {
+			if a = strings.TrimSpace(a); a == "" {
+				continue
+			}
+			addr, err := address.ParseHex(a)
+			if err != nil {
+				return fmt.Errorf("--by: %w", err)
+			}
+			by = append(by, addr)
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		end := uint64(*to)
+		if *to < 0 {
+			if end, err = client.BlockNumber(ctx); err != nil {
+				return err
+			}
+		}
+		start := uint64(*from)
+		if *from < 0 {
+			start = 0
+			if end >= *blocks {
+				start = end - *blocks + 1
+			}
+		}
+		if start > end {
+			return fmt.Errorf("--from %d is after --to %d", start, end)
+		}
+		events, err := counterflow.History(ctx, client, contract, start, end, by)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("CountIncremented events in blocks %d-%d: %d\n", start, end, len(events))
+		if len(events) == 0 {
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "BLOCK\tNEW VALUE\tBY\tTX")
+		for _, ev := range events {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", ev.Raw.BlockNumber, ev.NewValue, ev.By.Hex(), ev.Raw.TxHash.Hex())
+		}
+		return tw.Flush()
+	}
+	return cmd
+}
+
+// blockGet 显示区块的基本信息，参数为区块号或 latest（默认）
+func blockGet() *cobra.Command {
+	cmd := &cobra.Command{Use: "get [number|latest]", Short: "Show a block's summary"}
+	fs := cmd.Flags()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() > 1 {
+			return fmt.Errorf("usage: block get [flags] [number|latest]")
+		}
+		var number *big.Int
+		if arg := fs.Arg(0); arg != "" && arg != "latest" {
+			n, err := strconv.ParseUint(arg, 0, 64)
+			if err != nil {
+				return fmt.Errorf("invalid block number %q", arg)
+			}
+			number = new(big.Int).SetUint64(n)
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		block, err := client.BlockByNumber(ctx, number)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve block: %w", err)
+		}
+		fmt.Printf("Block Number: %d\n", block.NumberU64())
+		fmt.Printf("Block Hash: %s\n", block.Hash().Hex())
+		fmt.Printf("Block Time: %d\n", block.Time())
+		fmt.Printf("Block Transactions: %d\n", len(block.Transactions()))
+		fmt.Printf("Gas Used: %d / %d\n", block.GasUsed(), block.GasLimit())
+		if fee := block.BaseFee(); fee != nil {
+			fmt.Printf("Base Fee: %s Gwei\n", units.FormatGwei(fee, 2))
+		}
+		return nil
+	}
+	return cmd
+}
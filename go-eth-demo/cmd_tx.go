@@ -0,0 +1,968 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/go-eth-demo/counter"
+	"github.com/local/go-eth-demo/pkg/abicall"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/addressbook"
+	"github.com/local/go-eth-demo/pkg/decode"
+	"github.com/local/go-eth-demo/pkg/ens"
+	"github.com/local/go-eth-demo/pkg/erc1155"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/erc721"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/multicall"
+	"github.com/local/go-eth-demo/pkg/networks"
+	"github.com/local/go-eth-demo/pkg/rpcbatch"
+	"github.com/local/go-eth-demo/pkg/safe"
+	"github.com/local/go-eth-demo/pkg/txhistory"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/spf13/cobra"
+)
+
+// txBuild 在联网机器上构造未签名的转账交易（JSON），只需要发送方地址，不需要私钥
+func txBuild() *cobra.Command {
+	cmd := &cobra.Command{Use: "build", Short: "Build an unsigned transfer for offline signing"}
+	fs := cmd.Flags()
+	from := fs.String("from", "", "sender address (default: address of PRIVATE_KEY)")
+	to := fs.String("to", os.Getenv("RECIPIENT_ADDR"), "recipient address (default $RECIPIENT_ADDR)")
+	amountStr := fs.String("amount", "0.001eth", "amount to send, e.g. 0.001eth")
+	legacy := fs.Bool("legacy", false, "build a legacy transaction instead of EIP-1559")
+	nonce := fs.Int64("nonce", -1, "nonce (default: pending nonce of --from)")
+	out := fs.String("out", "", "write the unsigned transaction to this file instead of stdout")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		recipient, err := address.ParseHex(*to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+		sender, err := senderAddress(*from)
+		if err != nil {
+			return err
+		}
+		amount, err := units.ParseAmount(*amountStr)
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		prepare := ethtx.Prepare
+		if *legacy {
+			prepare = ethtx.PrepareLegacy
+		}
+		t, err := prepare(ctx, client, sender, recipient, amount)
+		if errors.Is(err, ethtx.ErrInsufficientFunds) {
+			// 签名前余额可能还会变化，只给出警告
+			fmt.Fprintf(stderr, "Warning: %v\n", err)
+		} else if err != nil {
+			return err
+		}
+		if *nonce >= 0 {
+			t.Nonce = uint64(*nonce)
+		}
+		u, err := t.Unsigned()
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(u, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeOutput(*out, append(data, '\n'))
+	}
+	return cmd
+}
+
+// txSign 在离线机器上签名 tx build 生成的交易，输出已签名交易的十六进制编码
+func txSign() *cobra.Command {
+	cmd := &cobra.Command{Use: "sign", Short: "Sign a transaction from tx build offline"}
+	fs := cmd.Flags()
+	in := fs.String("in", "-", "unsigned transaction JSON file, - for stdin")
+	out := fs.String("out", "", "write the signed transaction hex to this file instead of stdout")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		data, err := readInput(*in)
+		if err != nil {
+			return err
+		}
+		var u ethtx.Unsigned
+		if err := json.Unmarshal(data, &u); err != nil {
+			return fmt.Errorf("parse unsigned transaction: %w", err)
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+		signed, err := ethtx.SignOffline(&u, w)
+		if err != nil {
+			return err
+		}
+		// 签名的是 Raw 中的交易，把它显示出来供核对（写到 stderr 以免混入输出）
+		d := decode.Transaction(signed)
+		fmt.Fprintf(os.Stderr, "Signed %s transaction for chain %s: nonce %d, to %s, value %s wei, gas %d\n",
+			d.Type, u.ChainID.ToInt(), d.Nonce, d.To, d.Value, d.Gas)
+		fmt.Fprintf(os.Stderr, "Transaction hash: %s\n", signed.Hash().Hex())
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return writeOutput(*out, []byte(hexutil.Encode(raw)+"\n"))
+	}
+	return cmd
+}
+
+// txBroadcast 广播已签名的交易，参数为十六进制编码或包含它的文件（- 为 stdin）
+func txBroadcast() *cobra.Command {
+	cmd := &cobra.Command{Use: "broadcast <signed tx hex|file|->", Short: "Broadcast a signed transaction"}
+	fs := cmd.Flags()
+	wait := fs.Bool("wait", true, "wait for the transaction to be mined")
+	wf := newWaitFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: tx broadcast [flags] <signed tx hex|file|->")
+		}
+		if _, err := wf.tag(); err != nil {
+			return err
+		}
+		rawHex := fs.Arg(0)
+		if !strings.HasPrefix(rawHex, "0x") {
+			data, err := readInput(rawHex)
+			if err != nil {
+				return err
+			}
+			rawHex = string(data)
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		tx, err := ethtx.Broadcast(ctx, client, rawHex)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction: %s\n", tx.Hash().Hex())
+		if url := presetFor(ctx, client).TxURL(tx.Hash()); url != "" {
+			fmt.Printf("Explorer:    %s\n", url)
+		}
+		if !*wait {
+			return nil
+		}
+		_, err = wf.wait(ctx, client, tx)
+		return err
+	}
+	return cmd
+}
+
+// rawTxArg 读取参数中十六进制编码的交易，参数不以 0x 开头时作为文件读取（- 为 stdin）
+func rawTxArg(arg string) (string, error) {
+	if strings.HasPrefix(arg, "0x") {
+		return arg, nil
+	}
+	data, err := readInput(arg)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// txDecode 离线解码任意类型（legacy、EIP-2930、EIP-1559、EIP-4844、EIP-7702）的原始交易，显示各字段并恢复发送者
+func txDecode() *cobra.Command {
+	cmd := &cobra.Command{Use: "decode <raw tx hex|file|->", Short: "Decode a raw transaction offline"}
+	fs := cmd.Flags()
+	asJSON := fs.Bool("json", false, "print the decoded transaction as JSON")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return errors.New("usage: tx decode [--json] <raw tx hex|file|->")
+		}
+		rawHex, err := rawTxArg(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		tx, err := decode.ParseRawTransaction(rawHex)
+		if err != nil {
+			return err
+		}
+		d := decode.Transaction(tx)
+		if *asJSON {
+			data, err := json.MarshalIndent(d, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		row := func(name, value string) {
+			if value != "" {
+				fmt.Fprintf(tw, "%s:\t%s\n", name, value)
+			}
+		}
+		row("Hash", d.Hash)
+		row("Type", d.Type)
+		row("Chain ID", d.ChainID)
+		row("Nonce", fmt.Sprint(d.Nonce))
+		switch {
+		case d.Signature == nil:
+			row("From", "(unsigned)")
+		case d.From == "":
+			row("From", "(invalid signature)")
+		default:
+			row("From", d.From)
+		}
+		if d.To == "" {
+			row("To", "(contract creation)")
+		}
+		row("To", d.To)
+		row("Value", units.FormatUnits(tx.Value(), 18)+" ETH")
+		row("Gas Limit", fmt.Sprint(d.Gas))
+		if d.GasPrice != "" {
+			row("Gas Price", weiToGwei(tx.GasPrice())+" Gwei")
+		}
+		if d.GasFeeCap != "" {
+			row("Max Priority Fee", weiToGwei(tx.GasTipCap())+" Gwei")
+			row("Max Fee", weiToGwei(tx.GasFeeCap())+" Gwei")
+		}
+		if d.BlobFeeCap != "" {
+			row("Max Blob Fee", weiToGwei(tx.BlobGasFeeCap())+" Gwei")
+		}
+		for _, tuple := range d.AccessList {
+			row("Access List", fmt.Sprintf("%s (%d storage keys)", tuple.Address.Hex(), len(tuple.StorageKeys)))
+		}
+		for _, h := range d.BlobHashes {
+			row("Blob Hash", h)
+		}
+		for _, auth := range tx.SetCodeAuthorizations() {
+			row("Authorization", fmt.Sprintf("delegate to %s (chain %s, nonce %d)", auth.Address.Hex(), auth.ChainID.Dec(), auth.Nonce))
+		}
+		if d.Selector != "" {
+			row("Selector", d.Selector)
+		}
+		row("Data", fmt.Sprintf("%d bytes", len(tx.Data())))
+		if d.Signature != nil {
+			row("Signature", fmt.Sprintf("v=%s r=%s s=%s", d.Signature.V, d.Signature.R, d.Signature.S))
+		}
+		return tw.Flush()
+	}
+	return cmd
+}
+
+// txResign 用 PRIVATE_KEY（或 SIGNER 指定的签名器）重新签名一笔原始交易，字段不变只替换签名，输出新的十六进制编码。
+// 可以用来签名别处构造的未签名交易，或把 legacy 交易按 --chain-id 加上 EIP-155 重放保护
+func txResign() *cobra.Command {
+	cmd := &cobra.Command{Use: "resign <raw tx hex|file|->", Short: "Replace a raw transaction's signature with the current signer's"}
+	fs := cmd.Flags()
+	chainID := fs.Uint64("chain-id", 0, "chain ID to sign for (default the transaction's own; required for legacy transactions without EIP-155)")
+	out := fs.String("out", "", "write the signed transaction hex to this file instead of stdout")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return errors.New("usage: tx resign [flags] <raw tx hex|file|->")
+		}
+		rawHex, err := rawTxArg(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		tx, err := decode.ParseRawTransaction(rawHex)
+		if err != nil {
+			return err
+		}
+		w, err := loadSigner()
+		if err != nil {
+			return err
+		}
+		var id *big.Int
+		if *chainID != 0 {
+			id = new(big.Int).SetUint64(*chainID)
+		}
+		if prev := decode.Transaction(tx); prev.From != "" && prev.From != w.Address().Hex() {
+			fmt.Fprintf(os.Stderr, "Replacing signature of %s\n", prev.From)
+		}
+		signed, err := ethtx.Resign(tx, w, id)
+		if err != nil {
+			return err
+		}
+		d := decode.Transaction(signed)
+		fmt.Fprintf(os.Stderr, "Signed %s transaction for chain %s as %s: nonce %d, to %s, value %s wei, gas %d\n",
+			d.Type, d.ChainID, d.From, d.Nonce, d.To, d.Value, d.Gas)
+		fmt.Fprintf(os.Stderr, "Transaction hash: %s\n", signed.Hash().Hex())
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return writeOutput(*out, []byte(hexutil.Encode(raw)+"\n"))
+	}
+	return cmd
+}
+
+// senderAddress 返回 --from 指定的地址，未指定时使用 PRIVATE_KEY 对应的地址
+func senderAddress(from string) (common.Address, error) {
+	if from != "" {
+		addr, err := address.ParseHex(from)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("--from: %w", err)
+		}
+		return addr, nil
+	}
+	w, err := loadSigner()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("--from is required without a signer: %w", err)
+	}
+	return w.Address(), nil
+}
+
+// readInput 读取文件内容，path 为 - 时读取 stdin
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// writeOutput 把 data 写入文件，path 为空时写到 stdout
+func writeOutput(path string, data []byte) error {
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	return nil
+}
+
+// txSpeedUp 用相同的 nonce 和内容、更高的费用重新签名并广播一笔卡住的交易
+func txSpeedUp() *cobra.Command {
+	cmd := &cobra.Command{Use: "speedup <pending tx hash>", Short: "Resend a stuck transaction with a higher fee"}
+	fs := cmd.Flags()
+	percent := fs.Int("bump", 20, fmt.Sprintf("fee increase in percent (at least %d)", ethtx.MinBumpPercent))
+	wait := fs.Bool("wait", true, "wait for the replacement to be mined")
+	dryRun := dryRunFlag(fs)
+	wf := newWaitFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: tx speedup [flags] <pending tx hash>")
+		}
+		if _, err := wf.tag(); err != nil {
+			return err
+		}
+		hash := common.HexToHash(fs.Arg(0))
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		original, from, err := ethtx.Pending(ctx, client, hash)
+		if err != nil {
+			return err
+		}
+		replacement, err := ethtx.SpeedUp(ctx, client, original, *percent)
+		if err != nil {
+			return err
+		}
+		signed, err := sendReplacement(ctx, client, from, original, replacement, *dryRun)
+		if err != nil || *dryRun || !*wait {
+			return err
+		}
+		_, err = wf.wait(ctx, client, signed)
+		return err
+	}
+	return cmd
+}
+
+// txCancel 用同一 nonce 向自己发送 0 ETH、费用更高的交易来取消卡住的交易，并报告哪一笔最终被打包
+func txCancel() *cobra.Command {
+	cmd := &cobra.Command{Use: "cancel <pending tx hash>", Short: "Cancel a stuck transaction with a higher-fee self-transfer"}
+	fs := cmd.Flags()
+	percent := fs.Int("bump", 20, fmt.Sprintf("fee increase in percent (at least %d)", ethtx.MinBumpPercent))
+	wait := fs.Bool("wait", true, "wait until the nonce is used and report which transaction was mined")
+	dryRun := dryRunFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: tx cancel [flags] <pending tx hash>")
+		}
+		hash := common.HexToHash(fs.Arg(0))
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		original, from, err := ethtx.Pending(ctx, client, hash)
+		if err != nil {
+			return err
+		}
+		cancel, err := ethtx.Cancel(ctx, client, original, from, *percent)
+		if err != nil {
+			return err
+		}
+		signed, err := sendReplacement(ctx, client, from, original, cancel, *dryRun)
+		if err != nil || *dryRun || !*wait {
+			return err
+		}
+
+		fmt.Printf("Waiting for nonce %d to be used...\n", original.Nonce())
+		receipt, err := ethtx.WaitNonce(ctx, client, from, original.Nonce(), signed.Hash(), original.Hash())
+		if err != nil {
+			return err
+		}
+		switch {
+		case receipt == nil:
+			return fmt.Errorf("nonce %d was used by another transaction", original.Nonce())
+		case receipt.TxHash == signed.Hash():
+			fmt.Printf("✅ Cancelled: %s was replaced in block %s\n", original.Hash().Hex(), receipt.BlockNumber)
+			return nil
+		default:
+			return fmt.Errorf("original transaction %s was mined in block %s before the cancellation", original.Hash().Hex(), receipt.BlockNumber)
+		}
+	}
+	return cmd
+}
+
+// sendReplacement 签名并广播替换交易，显示新旧费用；dryRun 时只显示签名后的交易
+func sendReplacement(ctx context.Context, client *ethclient.Client, from common.Address, original, replacement *types.Transaction, dryRun bool) (*types.Transaction, error) {
+	s, err := loadSigner()
+	if err != nil {
+		return nil, err
+	}
+	if s.Address() != from {
+		return nil, fmt.Errorf("transaction %s was sent by %s, but the signer is %s", original.Hash().Hex(), from.Hex(), s.Address().Hex())
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	signed, err := s.SignTx(replacement, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Replacing:   %s (nonce %d)\n", original.Hash().Hex(), original.Nonce())
+	if original.Type() == types.DynamicFeeTxType {
+		fmt.Printf("Max Fee:     %s -> %s Gwei\n", units.FormatGwei(original.GasFeeCap(), 2), units.FormatGwei(signed.GasFeeCap(), 2))
+		fmt.Printf("Priority:    %s -> %s Gwei\n", units.FormatGwei(original.GasTipCap(), 2), units.FormatGwei(signed.GasTipCap(), 2))
+	} else {
+		fmt.Printf("Gas Price:   %s -> %s Gwei\n", units.FormatGwei(original.GasPrice(), 2), units.FormatGwei(signed.GasPrice(), 2))
+	}
+	if dryRun {
+		return signed, printDryRun(signed)
+	}
+	if err := client.SendTransaction(ctx, signed); err != nil {
+		return nil, fmt.Errorf("failed to send replacement: %w", err)
+	}
+	fmt.Printf("Transaction: %s\n", signed.Hash().Hex())
+	if url := presetFor(ctx, client).TxURL(signed.Hash()); url != "" {
+		fmt.Printf("Explorer:    %s\n", url)
+	}
+	return signed, nil
+}
+
+// txReceipts 用批量 JSON-RPC 请求一次读取多笔交易的收据，哈希来自参数或 --file（每行一个，- 为 stdin）
+func txReceipts() *cobra.Command {
+	cmd := &cobra.Command{Use: "receipts <hash>...", Short: "Fetch many receipts in one batch request"}
+	fs := cmd.Flags()
+	file := fs.String("file", "", "file with one transaction hash per line (- for stdin)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		items := fs.Args()
+		if *file != "" {
+			data, err := readInput(*file)
+			if err != nil {
+				return err
+			}
+			items = append(items, strings.Fields(string(data))...)
+		}
+		if len(items) == 0 {
+			return errors.New("usage: tx receipts [--file hashes.txt] <hash>...")
+		}
+		hashes := make([]common.Hash, len(items))
+		for i, item := range items {
+			b, err := hexutil.Decode(item)
+			if err != nil || len(b) != common.HashLength {
+				return fmt.Errorf("invalid transaction hash %q", item)
+			}
+			hashes[i] = common.BytesToHash(b)
+		}
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		receipts, err := rpcbatch.Receipts(ctx, client.Client(), hashes)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "HASH\tSTATUS\tBLOCK\tGAS USED\tFEE (ETH)")
+		for i, r := range receipts {
+			if r == nil {
+				fmt.Fprintf(tw, "%s\tpending or unknown\t-\t-\t-\n", hashes[i].Hex())
+				continue
+			}
+			status := "success"
+			if r.Status != types.ReceiptStatusSuccessful {
+				status = "failed"
+			}
+			fee := "-"
+			if r.EffectiveGasPrice != nil {
+				fee = units.FormatEther(new(big.Int).Mul(r.EffectiveGasPrice, new(big.Int).SetUint64(r.GasUsed)), 8)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", r.TxHash.Hex(), status, r.BlockNumber, r.GasUsed, fee)
+		}
+		return tw.Flush()
+	}
+	return cmd
+}
+
+// legacyHistory 是旧版本保存交易历史的 JSON 文件
+const legacyHistory = ".tx-history.json"
+
+// txHistory 返回记录发送交易的历史（$TX_HISTORY，默认 .tx-history.db），TX_HISTORY=off 时为 nil。
+// 使用默认路径且当前目录中有旧版本的 .tx-history.json 时，先把其中的记录导入数据库，再把文件改名为 .imported
+func txHistory() *txhistory.Store {
+	path := envOr("TX_HISTORY", ".tx-history.db")
+	if path == "off" {
+		return nil
+	}
+	store := txhistory.NewStore(path)
+	if os.Getenv("TX_HISTORY") == "" {
+		if _, err := os.Stat(legacyHistory); err == nil {
+			n, err := store.Import(legacyHistory)
+			if err == nil {
+				err = os.Rename(legacyHistory, legacyHistory+".imported")
+			}
+			if err != nil {
+				logger("tx").Warn("failed to import the JSON transaction history", "file", legacyHistory, "err", err)
+			} else {
+				logger("tx").Info("imported the JSON transaction history", "file", legacyHistory, "records", n, "into", path)
+			}
+		}
+	}
+	return store
+}
+
+// txList 列出本地记录的已发送交易，最近的在前
+func txList() *cobra.Command {
+	cmd := &cobra.Command{Use: "list", Short: "List sent transactions from the local history"}
+	fs := cmd.Flags()
+	limit := fs.IntP("limit", "n", 20, "show at most this many transactions, 0 for all")
+	from := fs.String("from", "", "only show transactions sent by this address")
+	status := fs.String("status", "", "only show pending, success or reverted transactions")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		store := txHistory()
+		if store == nil {
+			return errors.New("transaction history is disabled (TX_HISTORY=off)")
+		}
+		defer store.Close()
+		records, err := store.List()
+		if err != nil {
+			return err
+		}
+		book, err := loadAddressBook()
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "SENT\tHASH\tCHAIN\tNONCE\tTO\tVALUE (ETH)\tSTATUS\tBLOCK")
+		shown := 0
+		for _, r := range records {
+			if *from != "" && !strings.EqualFold(r.From.Hex(), *from) || *status != "" && r.Status != *status {
+				continue
+			}
+			if *limit > 0 && shown == *limit {
+				break
+			}
+			shown++
+			value, _ := new(big.Int).SetString(r.Value, 10)
+			block := "-"
+			if r.Block != 0 {
+				block = fmt.Sprint(r.Block)
+			}
+			fmt.Fprintf(tw, "%s\t%s…\t%d\t%d\t%s\t%s\t%s\t%s\n", r.SentAt.Local().Format("2006-01-02 15:04:05"), r.Hash.Hex()[:10],
+				r.ChainID, r.Nonce, historyRecipient(book, r), units.FormatEther(value, 6), r.Status, block)
+		}
+		if shown == 0 {
+			fmt.Println("No transactions recorded yet")
+			return nil
+		}
+		return tw.Flush()
+	}
+	return cmd
+}
+
+// historyRecipient 显示接收方，地址簿中有名称时带上名称
+func historyRecipient(book *addressbook.Book, r txhistory.Record) string {
+	if r.To == nil {
+		return "(contract creation)"
+	}
+	if name := book.Name(*r.To, r.ChainID); name != "" {
+		return ens.Short(*r.To) + " (" + name + ")"
+	}
+	return ens.Short(*r.To)
+}
+
+// txShow 显示历史中一笔交易的详情。交易仍未确认时向节点查询收据，查到后同时更新历史
+func txShow() *cobra.Command {
+	cmd := &cobra.Command{Use: "show <hash or hash prefix>", Short: "Show a transaction from the local history"}
+	fs := cmd.Flags()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() != 1 {
+			return errors.New("usage: tx show <hash or hash prefix>")
+		}
+		store := txHistory()
+		if store == nil {
+			return errors.New("transaction history is disabled (TX_HISTORY=off)")
+		}
+		defer store.Close()
+		r, err := store.Get(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if r.Status == txhistory.StatusPending {
+			// 经 dial 的 transport 查询收据时历史会自动更新；节点不可用时显示记录中的状态
+			ctx := rootCtx
+			if client, err := dial(ctx, rpcEndpoint()); err == nil {
+				client.TransactionReceipt(ctx, r.Hash)
+				client.Close()
+				if updated, err := store.Get(r.Hash.Hex()); err == nil {
+					r = updated
+				}
+			} else {
+				logger("rpc").Warn("cannot refresh pending transaction", "err", err)
+			}
+		}
+		book, err := loadAddressBook()
+		if err != nil {
+			return err
+		}
+
+		value, _ := new(big.Int).SetString(r.Value, 10)
+		fmt.Printf("Hash:      %s\n", r.Hash.Hex())
+		fmt.Printf("Chain:     %s\n", chainLabel(r.ChainID))
+		fmt.Printf("From:      %s\n", r.From.Hex())
+		if r.To != nil {
+			fmt.Printf("To:        %s\n", historyRecipient(book, r))
+		} else {
+			fmt.Println("To:        (contract creation)")
+		}
+		fmt.Printf("Nonce:     %d\n", r.Nonce)
+		fmt.Printf("Value:     %s ETH\n", units.FormatUnits(value, 18))
+		fmt.Printf("Gas Limit: %d\n", r.Gas)
+		if r.GasPrice != "" {
+			fmt.Printf("Gas Price: %s Gwei (legacy)\n", formatGweiString(r.GasPrice))
+		} else {
+			fmt.Printf("Max Fee: %s Gwei\n", formatGweiString(r.MaxFeePerGas))
+			fmt.Printf("Priority Fee: %s Gwei\n", formatGweiString(r.MaxPriorityFeePerGas))
+		}
+		fmt.Printf("Sent:      %s\n", r.SentAt.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("Status:    %s\n", r.Status)
+		if r.Status == txhistory.StatusPending {
+			return nil
+		}
+		fmt.Printf("Block:     %d\n", r.Block)
+		fmt.Printf("Mined:     %s (first seen)\n", r.MinedAt.Local().Format("2006-01-02 15:04:05"))
+		fmt.Printf("Gas Used:  %d\n", r.GasUsed)
+		if price, ok := new(big.Int).SetString(r.EffectiveGasPrice, 10); ok {
+			fee := new(big.Int).Mul(price, new(big.Int).SetUint64(r.GasUsed))
+			fmt.Printf("Fee:       %s ETH (%s Gwei per gas)\n", units.FormatEther(fee, 8), units.FormatGwei(price, 2))
+		}
+		return nil
+	}
+	return cmd
+}
+
+// formatGweiString 把十进制 wei 字符串显示为 Gwei
+func formatGweiString(wei string) string {
+	v, ok := new(big.Int).SetString(wei, 10)
+	if !ok {
+		return "-"
+	}
+	return units.FormatGwei(v, 2)
+}
+
+// txInspect 按哈希从节点读取任意交易和收据，显示类型、nonce、实际支付的费用（effectiveGasPrice），
+// 按 --abi 文件和内置 ABI（Counter、ERC-20、ERC-721、ERC-1155、Safe、Multicall3）解码调用数据和事件，
+// 以及当前链的区块浏览器链接
+func txInspect() *cobra.Command {
+	cmd := &cobra.Command{Use: "inspect <hash>", Short: "Fetch and decode any transaction by hash"}
+	fs := cmd.Flags()
+	var abiFiles stringList
+	fs.Var(&abiFiles, "abi", "ABI JSON file or Hardhat/Foundry artifact to decode calldata and logs with, tried before the built-in ABIs (repeatable)")
+	output := outputFlag(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return errors.New("usage: tx inspect [--abi file.json] [--output json] <hash>")
+		}
+		hash, err := parseTxHash(args[0])
+		if err != nil {
+			return err
+		}
+		if err := setOutput(*output); err != nil {
+			return err
+		}
+		var abis decode.ABIs
+		for _, path := range abiFiles {
+			contract, err := abicall.LoadABI(path)
+			if err != nil {
+				return err
+			}
+			abis = append(abis, contract)
+		}
+		abis = append(abis, knownABIs()...)
+
+		ctx := rootCtx
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		preset := presetFor(ctx, client)
+
+		tx, pending, err := client.TransactionByHash(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("transaction %s not found on %s", hash.Hex(), preset.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+		var receipt *types.Receipt
+		var baseFee *big.Int
+		if !pending {
+			if receipt, err = client.TransactionReceipt(ctx, hash); err != nil {
+				return fmt.Errorf("failed to get receipt: %w", err)
+			}
+			header, err := client.HeaderByNumber(ctx, receipt.BlockNumber)
+			if err != nil {
+				return fmt.Errorf("failed to get block %s: %w", receipt.BlockNumber, err)
+			}
+			baseFee = header.BaseFee
+		}
+
+		r := newInspectReport(preset, tx, receipt, baseFee, abis)
+		if jsonStdout != nil {
+			return emit(r)
+		}
+		printInspect(os.Stdout, preset, r)
+		return nil
+	}
+	return cmd
+}
+
+// parseTxHash 解析 32 字节的十六进制交易哈希
+func parseTxHash(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid transaction hash %q, want 0x followed by 64 hex digits", s)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// knownABIs 返回 tx inspect 内置的 ABI。ERC-20 在 ERC-721 之前，两者的 Transfer 签名相同，按 indexed 参数个数区分
+func knownABIs() decode.ABIs {
+	counterABI, err := counter.CounterMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	return decode.ABIs{counterABI, &erc20.ABI, &erc20.PermitABI, &erc721.ABI, &erc1155.ABI, &safe.ABI, &multicall.ABI}
+}
+
+// inspectReport 是 tx inspect 的输出，数量都是十进制的 wei 字符串。交易字段来自 decode.Tx，
+// 收据字段在交易仍在交易池中时为空
+type inspectReport struct {
+	Network  string `json:"network"`
+	Explorer string `json:"explorer,omitempty"`
+	*decode.Tx
+
+	Status          string `json:"status"` // pending、success 或 reverted
+	Block           uint64 `json:"block,omitempty"`
+	ContractAddress string `json:"contractAddress,omitempty"` // 合约创建交易部署的地址
+	GasUsed         uint64 `json:"gasUsed,omitempty"`
+	// 实际支付的价格：baseFeePerGas 被销毁，priorityFeePerGas（effectiveGasPrice - baseFee）付给出块者
+	BaseFee           string `json:"baseFeePerGas,omitempty"`
+	EffectiveGasPrice string `json:"effectiveGasPrice,omitempty"`
+	PriorityFee       string `json:"priorityFeePerGas,omitempty"`
+	BlobGasUsed       uint64 `json:"blobGasUsed,omitempty"`
+	BlobGasPrice      string `json:"blobGasPrice,omitempty"`
+	Fee               string `json:"fee,omitempty"` // gasUsed × effectiveGasPrice，加上 blob 费用
+
+	Call *decode.Call   `json:"call,omitempty"`
+	Logs []inspectedLog `json:"logs,omitempty"`
+}
+
+// inspectedLog 是收据中的一条日志，能按 ABI 解码时有 Event，否则只有原始的 topics 和 data
+type inspectedLog struct {
+	Index   uint          `json:"index"`
+	Address string        `json:"address"`
+	Event   *decode.Event `json:"event,omitempty"`
+	Topics  []string      `json:"topics,omitempty"`
+	Data    string        `json:"data,omitempty"`
+}
+
+// newInspectReport 从交易、收据（pending 时为 nil）和所在区块的 baseFee 填写报告，用 abis 解码调用数据和日志
+func newInspectReport(preset networks.Preset, tx *types.Transaction, receipt *types.Receipt, baseFee *big.Int, abis decode.ABIs) *inspectReport {
+	r := &inspectReport{Network: preset.Name, Explorer: preset.TxURL(tx.Hash()), Tx: decode.Transaction(tx), Status: "pending"}
+	if tx.To() != nil && len(tx.Data()) >= 4 {
+		r.Call, _ = abis.CallData(tx.Data())
+	}
+	if receipt == nil {
+		return r
+	}
+	r.Status = "success"
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		r.Status = "reverted"
+	}
+	r.Block = receipt.BlockNumber.Uint64()
+	if tx.To() == nil {
+		r.ContractAddress = receipt.ContractAddress.Hex()
+	}
+	r.GasUsed = receipt.GasUsed
+	fee := new(big.Int)
+	if price := receipt.EffectiveGasPrice; price != nil {
+		r.EffectiveGasPrice = price.String()
+		fee.Mul(price, new(big.Int).SetUint64(receipt.GasUsed))
+		if baseFee != nil {
+			r.BaseFee = baseFee.String()
+			r.PriorityFee = new(big.Int).Sub(price, baseFee).String()
+		}
+	}
+	if receipt.BlobGasUsed > 0 && receipt.BlobGasPrice != nil {
+		r.BlobGasUsed, r.BlobGasPrice = receipt.BlobGasUsed, receipt.BlobGasPrice.String()
+		fee.Add(fee, new(big.Int).Mul(receipt.BlobGasPrice, new(big.Int).SetUint64(receipt.BlobGasUsed)))
+	}
+	r.Fee = fee.String()
+	for _, l := range receipt.Logs {
+		out := inspectedLog{Index: l.Index, Address: l.Address.Hex()}
+		if event, err := abis.Log(l); err == nil {
+			out.Event = event
+		} else {
+			for _, topic := range l.Topics {
+				out.Topics = append(out.Topics, topic.Hex())
+			}
+			if len(l.Data) > 0 {
+				out.Data = hexutil.Encode(l.Data)
+			}
+		}
+		r.Logs = append(r.Logs, out)
+	}
+	return r
+}
+
+// printInspect 以文本形式输出报告，金额按 preset 的原生代币显示
+func printInspect(w io.Writer, preset networks.Preset, r *inspectReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", name, value)
+		}
+	}
+	symbol := preset.Currency.Symbol
+	if symbol == "" {
+		symbol = "ETH"
+	}
+	eth := func(wei string) string {
+		v, ok := new(big.Int).SetString(wei, 10)
+		if !ok {
+			return ""
+		}
+		return units.FormatUnits(v, 18) + " " + symbol
+	}
+	gwei := func(wei string) string {
+		if wei == "" {
+			return ""
+		}
+		return formatGweiString(wei) + " Gwei"
+	}
+
+	row("Hash", r.Hash)
+	row("Network", r.Network)
+	row("Type", r.Type)
+	row("Status", r.Status)
+	if r.Block > 0 {
+		row("Block", fmt.Sprint(r.Block))
+	}
+	row("From", r.From)
+	if r.To == "" {
+		row("To", "(contract creation)")
+		row("Contract", r.ContractAddress)
+	}
+	row("To", r.To)
+	row("Value", eth(r.Value))
+	row("Nonce", fmt.Sprint(r.Nonce))
+	if r.GasUsed > 0 {
+		row("Gas", fmt.Sprintf("%d used of %d (%.1f%%)", r.GasUsed, r.Gas, float64(r.GasUsed)*100/float64(r.Gas)))
+	} else {
+		row("Gas Limit", fmt.Sprint(r.Gas))
+	}
+	row("Gas Price", gwei(r.GasPrice))
+	row("Max Fee", gwei(r.GasFeeCap))
+	row("Max Priority Fee", gwei(r.GasTipCap))
+	row("Base Fee", gwei(r.BaseFee))
+	row("Effective Gas Price", gwei(r.EffectiveGasPrice))
+	row("Priority Fee Paid", gwei(r.PriorityFee))
+	if r.BlobGasUsed > 0 {
+		row("Blob Gas", fmt.Sprintf("%d at %s", r.BlobGasUsed, gwei(r.BlobGasPrice)))
+	}
+	row("Fee", eth(r.Fee))
+	switch {
+	case r.Call != nil:
+		row("Call", formatArgs(r.Call.Method, r.Call.Args))
+	case r.Selector != "":
+		row("Call", r.Selector+" (unknown selector, pass --abi to decode)")
+	}
+	if r.Data != "" {
+		row("Data", fmt.Sprintf("%d bytes", (len(r.Data)-2)/2))
+	}
+	for _, l := range r.Logs {
+		if l.Event != nil {
+			row(fmt.Sprintf("Log #%d", l.Index), fmt.Sprintf("%s %s", l.Address, formatArgs(l.Event.Name, l.Event.Args)))
+			continue
+		}
+		topic0 := "-"
+		if len(l.Topics) > 0 {
+			topic0 = l.Topics[0]
+		}
+		row(fmt.Sprintf("Log #%d", l.Index), fmt.Sprintf("%s topic0 %s data %d bytes", l.Address, topic0, max(len(l.Data)-2, 0)/2))
+	}
+	row("Explorer", r.Explorer)
+	tw.Flush()
+}
+
+// formatArgs 把解码后的参数显示为 name(a=1, b=0x…)
+func formatArgs(name string, args []decode.Arg) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprintf("%s=%v", arg.Name, arg.Value)
+	}
+	return name + "(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/local/go-eth-demo/pkg/wallet"
+	"github.com/spf13/cobra"
+)
+
+// walletImport 把十六进制私钥加密为 keystore 文件，之后可以用 KEYSTORE 代替 PRIVATE_KEY
+func walletImport() *cobra.Command {
+	cmd := &cobra.Command{Use: "import [private key hex]", Short: "Encrypt a private key into a keystore file"}
+	fs := cmd.Flags()
+	dir := fs.String("dir", "keystore", "keystore directory")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() > 1 {
+			return fmt.Errorf("usage: wallet import [--dir keystore] [private key hex]")
+		}
+
+		// 私钥默认取自 PRIVATE_KEY，避免出现在 shell 历史中
+		keyHex := fs.Arg(0)
+		if keyHex == "" {
+			keyHex = os.Getenv("PRIVATE_KEY")
+		}
+		if keyHex == "" {
+			var err error
+			if keyHex, err = readPassphrase("Private key (hex): "); err != nil {
+				return err
+			}
+		}
+		w, err := wallet.FromHex(keyHex)
+		if err != nil {
+			return err
+		}
+
+		pass, ok := os.LookupEnv("KEYSTORE_PASSWORD")
+		if !ok {
+			if pass, err = readPassphrase("New passphrase: "); err != nil {
+				return err
+			}
+			again, err := readPassphrase("Repeat passphrase: ")
+			if err != nil {
+				return err
+			}
+			if pass != again {
+				return fmt.Errorf("passphrases do not match")
+			}
+		}
+		if pass == "" {
+			return fmt.Errorf("refusing to create a keystore with an empty passphrase")
+		}
+
+		path, err := w.SaveKeystore(*dir, pass)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Address:  %s\n", w.Address().Hex())
+		fmt.Printf("Keystore: %s\n", path)
+		fmt.Printf("Use it with KEYSTORE=%s and remove PRIVATE_KEY from .env\n", path)
+		return nil
+	}
+	return cmd
+}
+
+// walletKeychain 把私钥（取自 PRIVATE_KEY 或终端输入）存入操作系统钥匙串，之后用 KEY_SOURCE=keychain 签名，
+// 私钥不再出现在 .env 中；--delete 删除条目
+func walletKeychain() *cobra.Command {
+	cmd := &cobra.Command{Use: "keychain", Short: "Store the private key in the OS keychain"}
+	fs := cmd.Flags()
+	del := fs.Bool("delete", false, "delete the keychain entry instead of storing a key")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if fs.NArg() > 0 {
+			return fmt.Errorf("usage: wallet keychain [--delete]")
+		}
+		k := keychainEntry()
+		if *del {
+			if err := k.Delete(); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted keychain entry %s/%s\n", k.Service, k.Account)
+			return nil
+		}
+
+		keyHex := os.Getenv("PRIVATE_KEY")
+		if keyHex == "" {
+			var err error
+			if keyHex, err = readPassphrase("Private key (hex): "); err != nil {
+				return err
+			}
+		}
+		w, err := wallet.FromHex(keyHex)
+		if err != nil {
+			return err
+		}
+		if err := k.Set(strings.TrimPrefix(strings.TrimSpace(keyHex), "0x")); err != nil {
+			return fmt.Errorf("failed to store key in keychain: %w", err)
+		}
+		fmt.Printf("Address:  %s\n", w.Address().Hex())
+		fmt.Printf("Keychain: %s/%s\n", k.Service, k.Account)
+		fmt.Println("Use it with KEY_SOURCE=keychain and remove PRIVATE_KEY from .env")
+		return nil
+	}
+	return cmd
+}
+
+// walletDerive 列出助记词（$MNEMONIC 或终端输入）在派生路径上的前若干个地址，
+// 选定后用 HD_INDEX 指定签名账户
+func walletDerive() *cobra.Command {
+	cmd := &cobra.Command{Use: "derive", Short: "List the addresses derived from a mnemonic"}
+	fs := cmd.Flags()
+	path := fs.String("path", envOr("HD_PATH", wallet.DefaultHDBase), "base derivation path; the account index is appended")
+	start := fs.Uint("start", 0, "first account index")
+	count := fs.Uint("count", 5, "number of accounts to list")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		mnemonic := os.Getenv("MNEMONIC")
+		if mnemonic == "" {
+			var err error
+			if mnemonic, err = readPassphrase("Mnemonic: "); err != nil {
+				return err
+			}
+		}
+		h, err := wallet.NewHDWallet(mnemonic, os.Getenv("MNEMONIC_PASSPHRASE"))
+		if err != nil {
+			return err
+		}
+		for i := *start; i < *start+*count; i++ {
+			w, err := h.DeriveIndex(*path, uint32(i))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%-4d %s/%d  %s\n", i, strings.TrimSuffix(*path, "/"), i, w.Address().Hex())
+		}
+		return nil
+	}
+	return cmd
+}
+
+// stdin 是读取口令用的输入，多次提示共用同一个缓冲
+var stdin = bufio.NewReader(os.Stdin)
+
+// readPassphrase 在 stderr 上提示并从 stdin 读取一行，终端输入时关闭回显
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	if echoOff() {
+		defer func() {
+			echoOn()
+			fmt.Fprintln(os.Stderr)
+		}()
+	}
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// echoOff 用 stty 关闭终端回显，stdin 不是终端或没有 stty 时返回 false
+func echoOff() bool {
+	cmd := exec.Command("stty", "-echo")
+	cmd.Stdin = os.Stdin
+	return cmd.Run() == nil
+}
+
+func echoOn() {
+	cmd := exec.Command("stty", "echo")
+	cmd.Stdin = os.Stdin
+	cmd.Run()
+}
@@ -0,0 +1,613 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/balancewatch"
+	"github.com/local/go-eth-demo/pkg/decode"
+	"github.com/local/go-eth-demo/pkg/notify"
+	"github.com/local/go-eth-demo/pkg/stuckwatch"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/local/go-eth-demo/pkg/watch"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// watchFlags 是 watch 命令共用的连接参数
+type watchFlags struct {
+	stall       *time.Duration
+	reorgWindow *int
+}
+
+func newWatchFlags(fs *pflag.FlagSet) watchFlags {
+	return watchFlags{
+		stall:       fs.Duration("stall", time.Minute, "reconnect when nothing is received for this long (0 disables)"),
+		reorgWindow: fs.Int("reorg-window", watch.DefaultReorgWindow, "recent blocks kept to detect reorgs and recheck after reconnecting"),
+	}
+}
+
+// url 返回订阅的端点：依次为 --rpc、$WS_RPC 和默认的 RPC 端点
+func (f watchFlags) url() string {
+	if rpcFlag != "" {
+		return rpcFlag
+	}
+	if url := os.Getenv("WS_RPC"); url != "" {
+		return url
+	}
+	return rpcEndpoint()
+}
+
+// options 返回重连参数，每次重连时打印原因，发现重组时打印深度和被替换的区块
+func (f watchFlags) options() watch.Options {
+	return watch.Options{
+		StallTimeout: *f.stall,
+		ReorgWindow:  *f.reorgWindow,
+		OnReconnect: func(attempt int, err error) {
+			logger("watch").Warn("connection lost, reconnecting", "attempt", attempt, "err", err)
+		},
+		OnReorg: printReorg,
+	}
+}
+
+func printReorg(r watch.Reorg) {
+	depth := fmt.Sprint(r.Depth)
+	if r.Deep {
+		depth = "at least " + depth
+	}
+	logger("watch").Warn("chain reorg", "depth", r.Depth, "ancestor", r.Ancestor, "deep", r.Deep)
+	head := r.Added[len(r.Added)-1]
+	fmt.Printf("reorg: %s block(s) after block %d replaced, new head %s (%s)\n", depth, r.Ancestor, head.Number, head.Hash().Hex())
+}
+
+// watchHeads 订阅新区块头并逐行显示区块号、base fee、gas 使用率和交易数，断线后自动重连并补上错过的区块，
+// 发生重组时打印一行说明并重新显示新链上的区块，Ctrl-C 退出
+func watchHeads() *cobra.Command {
+	cmd := &cobra.Command{Use: "heads", Short: "Follow new block headers"}
+	fs := cmd.Flags()
+	wf := newWatchFlags(fs)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err := watch.Heads(ctx, wf.url(), wf.options(), func(client *ethclient.Client, head *types.Header) error {
+			printHead(ctx, client, head)
+			return nil
+		})
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+	return cmd
+}
+
+func printHead(ctx context.Context, client *ethclient.Client, head *types.Header) {
+	baseFee := "-"
+	if head.BaseFee != nil {
+		baseFee = units.FormatGwei(head.BaseFee, 3) + " gwei"
+	}
+	used := 0.0
+	if head.GasLimit > 0 {
+		used = float64(head.GasUsed) * 100 / float64(head.GasLimit)
+	}
+	txs := "?"
+	if n, err := client.TransactionCount(ctx, head.Hash()); err == nil {
+		txs = fmt.Sprint(n)
+	}
+	fmt.Printf("%s  block %-10s base fee %-14s gas used %5.1f%%  txs %s\n",
+		time.Unix(int64(head.Time), 0).Format(time.TimeOnly), head.Number, baseFee, used, txs)
+}
+
+// watchLogs 订阅合约事件日志并逐行打印，断线重连后用 eth_getLogs 补齐错过的区块，Ctrl-C 退出
+func watchLogs() *cobra.Command {
+	cmd := &cobra.Command{Use: "logs", Short: "Follow contract event logs"}
+	fs := cmd.Flags()
+	wf := newWatchFlags(fs)
+	contracts := fs.String("address", os.Getenv("CONTRACT_ADDR"), "contract address to watch, comma separated (default $CONTRACT_ADDR)")
+	topic := fs.String("topic", "", "topic0 filter: a 32-byte hash or an event signature such as Transfer(address,address,uint256)")
+	from := fs.Int64("from", -1, "also backfill logs starting at this block (default: only new logs)")
+	abiPath := fs.String("abi", "", "ABI JSON file used to decode the events")
+	fs.Set("stall", "0") // 事件可能很久才出现一次，默认不按静默时间重连
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var q ethereum.FilterQuery
+		for _, a := range strings.Split(*contracts, ",") {
+			if a = strings.TrimSpace(a); a == "" {
+				continue
+			}
+			addr, err := address.ParseHex(a)
+			if err != nil {
+				return fmt.Errorf("--address: %w", err)
+			}
+			q.Addresses = append(q.Addresses, addr)
+		}
+		if len(q.Addresses) == 0 {
+			return errors.New("--address or CONTRACT_ADDR is required")
+		}
+		if *topic != "" {
+			q.Topics = [][]common.Hash{{topicHash(*topic)}}
+		}
+		if *from >= 0 {
+			q.FromBlock = big.NewInt(*from)
+		}
+		var contract *abi.ABI
+		if *abiPath != "" {
+			data, err := os.ReadFile(*abiPath)
+			if err != nil {
+				return err
+			}
+			parsed, err := abi.JSON(strings.NewReader(string(data)))
+			if err != nil {
+				return fmt.Errorf("parse ABI: %w", err)
+			}
+			contract = &parsed
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err := watch.Logs(ctx, wf.url(), q, wf.options(), func(l types.Log) error {
+			printLog(contract, &l)
+			return nil
+		})
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+	return cmd
+}
+
+// watchPending 订阅交易池中的新交易，按发送方、接收方和最小金额过滤后逐行打印，
+// 可以在交易打包之前看到转入自己地址的交易，Ctrl-C 退出
+func watchPending() *cobra.Command {
+	cmd := &cobra.Command{Use: "pending", Short: "Follow new transactions in the mempool"}
+	fs := cmd.Flags()
+	wf := newWatchFlags(fs)
+	from := fs.String("from", "", "only transactions sent by these addresses, comma separated")
+	to := fs.String("to", "", "only transactions sent to these addresses, comma separated")
+	either := fs.String("address", "", "only transactions from or to these addresses, comma separated")
+	minValue := fs.String("min-value", "", "only transactions transferring at least this amount, e.g. 0.1eth (no unit means wei)")
+	fs.Set("stall", "0") // 只关心少数地址时可能很久没有推送，默认不按静默时间重连
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var filter watch.PendingFilter
+		for _, list := range []struct {
+			flag string
+			s    string
+			dst  *[]common.Address
+		}{{"from", *from, &filter.From}, {"to", *to, &filter.To}, {"address", *either, &filter.Address}} {
+			for _, a := range splitList(list.s) {
+				addr, err := address.ParseHex(a)
+				if err != nil {
+					return fmt.Errorf("-%s: %w", list.flag, err)
+				}
+				*list.dst = append(*list.dst, addr)
+			}
+		}
+		if *minValue != "" {
+			v, err := units.ParseAmount(*minValue)
+			if err != nil {
+				return fmt.Errorf("invalid --min-value: %w", err)
+			}
+			filter.MinValue = v
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err := watch.Pending(ctx, wf.url(), filter, wf.options(), func(tx *types.Transaction, from common.Address) error {
+			to := "(contract creation)"
+			if tx.To() != nil {
+				to = tx.To().Hex()
+			}
+			fmt.Printf("%s  pending %s  %s → %s  %s ETH  nonce %d\n", time.Now().Format(time.TimeOnly),
+				tx.Hash().Hex(), from.Hex(), to, units.FormatEther(tx.Value(), 6), tx.Nonce())
+			return nil
+		})
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+	return cmd
+}
+
+// gasThreshold 判断 gas 价格是否越过阈值。价格进入阈值之外的区间时触发一次，回到阈值之内后才会再次触发，
+// 价格在阈值附近来回时不会每个区块都告警
+type gasThreshold struct {
+	below, above *big.Int // nil 表示不检查
+	zone         string   // 上一次观察到的区间：below、above 或 normal，开始时为空
+}
+
+// observe 记录一次价格，进入 below 或 above 区间时返回区间名和 true
+func (g *gasThreshold) observe(price *big.Int) (string, bool) {
+	zone := "normal"
+	switch {
+	case g.below != nil && price.Cmp(g.below) < 0:
+		zone = "below"
+	case g.above != nil && price.Cmp(g.above) > 0:
+		zone = "above"
+	}
+	changed := zone != g.zone
+	g.zone = zone
+	return zone, changed && zone != "normal"
+}
+
+// errGasAlerted 在 --once 时结束监视
+var errGasAlerted = errors.New("gas alert sent")
+
+// watchGas 监视 base fee，跌破 --below 或超过 --above 时打印一行并发送 gas 告警（按通知配置发到
+// Telegram、webhook 等），适合等待低 gas 时再发送不急的转账。ws:// 地址订阅新区块头，http(s) 地址按
+// --interval 轮询最新区块；没有 base fee 的链改用 eth_gasPrice。Ctrl-C 退出
+func watchGas() *cobra.Command {
+	cmd := &cobra.Command{Use: "gas", Short: "Alert when the base fee crosses a threshold"}
+	fs := cmd.Flags()
+	wf := newWatchFlags(fs)
+	below := fs.String("below", "", "alert when the base fee drops below this price, e.g. 20gwei")
+	above := fs.String("above", "", "alert when the base fee rises above this price, e.g. 100gwei")
+	interval := fs.Duration("interval", 12*time.Second, "polling interval for http(s) endpoints")
+	once := fs.Bool("once", false, "exit after the first alert")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var th gasThreshold
+		for _, t := range []struct {
+			flag string
+			s    string
+			dst  **big.Int
+		}{{"below", *below, &th.below}, {"above", *above, &th.above}} {
+			if t.s == "" {
+				continue
+			}
+			v, err := units.ParseAmount(t.s)
+			if err != nil {
+				return fmt.Errorf("invalid -%s: %w", t.flag, err)
+			}
+			*t.dst = v
+		}
+		if th.below == nil && th.above == nil {
+			return errors.New("--below or --above is required")
+		}
+		if !alertNotifier.Enabled(notify.AlertGas) {
+			logger("notify").Debug("gas alerts are not configured, printing only")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		var chainID uint64
+		check := func(client *ethclient.Client, head *types.Header) error {
+			price, name := head.BaseFee, "base fee"
+			if price == nil {
+				p, err := client.SuggestGasPrice(ctx)
+				if err != nil {
+					logger("watch").Warn("failed to get gas price", "err", err)
+					return nil
+				}
+				price, name = p, "gas price"
+			}
+			logger("watch").Debug(name, "block", head.Number, "gwei", units.FormatGwei(price, 3))
+			direction, fire := th.observe(price)
+			if !fire {
+				return nil
+			}
+			threshold := th.below
+			if direction == "above" {
+				threshold = th.above
+			}
+			if chainID == 0 {
+				if id, err := client.ChainID(ctx); err == nil {
+					chainID = id.Uint64()
+				}
+			}
+			ev := notify.GasEvent{
+				Price:     units.FormatGwei(price, 3),
+				Direction: direction,
+				Threshold: units.FormatGwei(threshold, 3),
+				ChainID:   chainID,
+				Block:     head.Number.Uint64(),
+			}
+			fmt.Printf("%s  block %-10s %s %s gwei is %s %s gwei\n", time.Now().Format(time.TimeOnly),
+				head.Number, name, ev.Price, direction, ev.Threshold)
+			if err := alertNotifier.Notify(ctx, notify.AlertGas, ev); err != nil {
+				logger("notify").Warn("failed to send gas alert", "err", err)
+			}
+			if *once {
+				return errGasAlerted
+			}
+			return nil
+		}
+
+		var err error
+		if url := wf.url(); strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://") {
+			err = watch.Heads(ctx, url, wf.options(), check)
+		} else {
+			err = pollGas(ctx, url, *interval, check)
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, errGasAlerted) {
+			return nil
+		}
+		return err
+	}
+	return cmd
+}
+
+// pollGas 每隔 interval 读取一次最新区块头交给 check，同一区块只检查一次，读取失败只记录警告
+func pollGas(ctx context.Context, url string, interval time.Duration, check func(*ethclient.Client, *types.Header) error) error {
+	client, err := dial(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last common.Hash
+	for {
+		head, err := client.HeaderByNumber(ctx, nil)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logger("watch").Warn("failed to get latest block", "err", err)
+		case head.Hash() != last:
+			last = head.Hash()
+			if err := check(client, head); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchBalances 按配置文件定期检查一组地址的 ETH 和 ERC-20 余额，余额跌破阈值或恢复时打印一行并发送
+// address 告警。--once 检查一次并打印所有余额，有余额不足时以错误退出，可以放进 cron。Ctrl-C 退出
+func watchBalances() *cobra.Command {
+	cmd := &cobra.Command{Use: "balances", Short: "Alert when watched balances fall below their thresholds"}
+	fs := cmd.Flags()
+	configPath := fs.String("config", envOr("BALANCE_WATCH_CONFIG", "balances.json"), "balance watch config (default $BALANCE_WATCH_CONFIG or balances.json)")
+	interval := fs.Duration("interval", 0, "check interval (default: interval in the config, otherwise 5m)")
+	once := fs.Bool("once", false, "check once, print all balances and fail if any is low")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := balancewatch.LoadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		every := *interval
+		if every <= 0 {
+			if every, err = cfg.IntervalOr(5 * time.Minute); err != nil {
+				return fmt.Errorf("%s: %w", *configPath, err)
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		checks, err := cfg.Checks(ctx, client, func(s string) (common.Address, error) {
+			return resolveAddress(ctx, client, s)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", *configPath, err)
+		}
+		if !alertNotifier.Enabled(notify.AlertAddress) {
+			logger("notify").Debug("address alerts are not configured, printing only")
+		}
+
+		w := balancewatch.New(checks)
+		if *once {
+			low := 0
+			for _, s := range w.Poll(ctx, client) {
+				if s.Err != nil {
+					return fmt.Errorf("%s: %w", s.Check.Label(), s.Err)
+				}
+				mark := "ok"
+				if s.Low() {
+					mark, low = "LOW", low+1
+					sendBalanceAlert(ctx, chainID.Uint64(), s)
+				}
+				fmt.Printf("%-4s %-42s %s (min %s)\n", mark, s.Check.Label(), s.Check.Format(s.Balance), s.Check.Format(s.Check.Min))
+			}
+			if low > 0 {
+				return fmt.Errorf("%d balance(s) below threshold", low)
+			}
+			return nil
+		}
+
+		fmt.Printf("Watching %d balance(s) every %s\n", len(checks), every)
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		for {
+			for _, s := range w.Poll(ctx, client) {
+				switch {
+				case s.Err != nil:
+					if ctx.Err() == nil {
+						logger("watch").Warn("failed to check balance", "account", s.Check.Label(), "err", s.Err)
+					}
+				case s.Changed:
+					state := "is below"
+					if !s.Low() {
+						state = "is back above"
+					}
+					fmt.Printf("%s  %s balance %s %s %s\n", time.Now().Format(time.TimeOnly), s.Check.Label(),
+						s.Check.Format(s.Balance), state, s.Check.Format(s.Check.Min))
+					sendBalanceAlert(ctx, chainID.Uint64(), s)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+	return cmd
+}
+
+// sendBalanceAlert 为余额不足或恢复发送 address 告警，失败只记录警告
+func sendBalanceAlert(ctx context.Context, chainID uint64, s balancewatch.Status) {
+	ev := notify.AddressEvent{
+		Address:   s.Check.Account.Hex(),
+		Name:      s.Check.Name,
+		ChainID:   chainID,
+		Balance:   s.Check.Format(s.Balance),
+		Threshold: s.Check.Format(s.Check.Min),
+		Low:       s.Low(),
+	}
+	ev.Message = fmt.Sprintf("balance %s is below %s", ev.Balance, ev.Threshold)
+	if !ev.Low {
+		ev.Message = fmt.Sprintf("balance %s is back above %s", ev.Balance, ev.Threshold)
+	}
+	if ev.Name != "" {
+		ev.Message = ev.Name + " " + ev.Message
+	}
+	if err := alertNotifier.Notify(ctx, notify.AlertAddress, ev); err != nil {
+		logger("notify").Warn("failed to send balance alert", "account", s.Check.Label(), "err", err)
+	}
+}
+
+// watchStuck 定期检查交易历史中的待确认交易，发送超过 --after 仍没有打包时打印一行并发送 stuck 告警，
+// 说明交易仍在交易池中、已被节点丢弃还是 nonce 已被另一笔交易使用；已经打包的交易把收据写回历史。
+// --once 检查一次，有卡住的交易时以错误退出，可以放进 cron。Ctrl-C 退出
+func watchStuck() *cobra.Command {
+	cmd := &cobra.Command{Use: "stuck", Short: "Alert when sent transactions stay pending for too long"}
+	fs := cmd.Flags()
+	after := fs.Duration("after", 10*time.Minute, "report transactions still pending this long after they were sent")
+	within := fs.Duration("within", 24*time.Hour, "only check transactions sent within this long (0 checks the whole history)")
+	interval := fs.Duration("interval", time.Minute, "check interval")
+	once := fs.Bool("once", false, "check once and fail if any transaction is stuck")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if *after <= 0 {
+			return errors.New("--after must be positive")
+		}
+		store := txHistory()
+		if store == nil {
+			return errors.New("transaction history is disabled (TX_HISTORY=off)")
+		}
+		defer store.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		client, err := dial(ctx, rpcEndpoint())
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		if !alertNotifier.Enabled(notify.AlertStuck) {
+			logger("notify").Debug("stuck alerts are not configured, printing only")
+		}
+		preset := presetFor(ctx, client)
+
+		d := stuckwatch.New(store, chainID.Uint64(), *after)
+		check := func() (int, error) {
+			now := time.Now()
+			since := time.Unix(0, 0)
+			if *within > 0 {
+				since = now.Add(-*within)
+			}
+			stuck, err := d.Check(ctx, client, since, now)
+			for _, s := range stuck {
+				fmt.Printf("%s  %-8s %s  nonce %d  %s\n", now.Format(time.TimeOnly), s.State, s.Record.Hash.Hex(), s.Record.Nonce, s.Message())
+				ev := notify.StuckEvent{
+					Hash:     s.Record.Hash.Hex(),
+					ChainID:  s.Record.ChainID,
+					From:     s.Record.From.Hex(),
+					Nonce:    s.Record.Nonce,
+					SentAt:   s.Record.SentAt,
+					Pending:  s.Age.Round(time.Second).String(),
+					State:    s.State,
+					Message:  s.Message(),
+					Explorer: preset.TxURL(s.Record.Hash),
+				}
+				if err := alertNotifier.Notify(ctx, notify.AlertStuck, ev); err != nil {
+					logger("notify").Warn("failed to send stuck alert", "hash", ev.Hash, "err", err)
+				}
+			}
+			return len(stuck), err
+		}
+
+		if *once {
+			n, err := check()
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				return fmt.Errorf("%d transaction(s) pending for more than %s", n, *after)
+			}
+			fmt.Println("No stuck transactions")
+			return nil
+		}
+
+		fmt.Printf("Watching pending transactions every %s, reporting after %s\n", *interval, *after)
+		ticker := time.NewTicker(*interval)
+		defer ticker.Stop()
+		for {
+			if _, err := check(); err != nil && ctx.Err() == nil {
+				logger("watch").Warn("failed to check pending transactions", "err", err)
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+	return cmd
+}
+
+// topicHash 接受 32 字节十六进制哈希，否则把参数当作事件签名计算 keccak256
+func topicHash(s string) common.Hash {
+	if strings.HasPrefix(s, "0x") && len(s) == 66 {
+		return common.HexToHash(s)
+	}
+	return crypto.Keccak256Hash([]byte(s))
+}
+
+func printLog(contract *abi.ABI, l *types.Log) {
+	prefix := fmt.Sprintf("block %-10d tx %s #%d", l.BlockNumber, l.TxHash.Hex(), l.Index)
+	if l.Removed {
+		prefix += " (removed by reorg)"
+	}
+	if contract != nil {
+		if event, err := decode.Log(contract, l); err == nil {
+			args := make([]string, len(event.Args))
+			for i, arg := range event.Args {
+				args[i] = fmt.Sprintf("%s=%v", arg.Name, arg.Value)
+			}
+			fmt.Printf("%s  %s(%s)\n", prefix, event.Name, strings.Join(args, ", "))
+			return
+		}
+	}
+	topic0 := "-"
+	if len(l.Topics) > 0 {
+		topic0 = l.Topics[0].Hex()
+	}
+	fmt.Printf("%s  %s topic0 %s data %d bytes\n", prefix, l.Address.Hex(), topic0, len(l.Data))
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGasThreshold(t *testing.T) {
+	th := gasThreshold{below: big.NewInt(20), above: big.NewInt(100)}
+	steps := []struct {
+		price int64
+		want  string // 期望触发的区间，空表示不触发
+	}{
+		{15, "below"}, // 一开始就低于阈值也会触发
+		{10, ""},
+		{19, ""},
+		{20, ""}, // 等于阈值不算低于
+		{18, "below"},
+		{150, "above"},
+		{120, ""},
+		{50, ""},
+		{101, "above"},
+	}
+	for i, s := range steps {
+		got := ""
+		if zone, fired := th.observe(big.NewInt(s.price)); fired {
+			got = zone
+		}
+		if got != s.want {
+			t.Errorf("step %d: price %d fired %q, want %q", i, s.price, got, s.want)
+		}
+	}
+}
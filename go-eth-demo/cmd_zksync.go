@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/local/go-eth-demo/pkg/zksync"
+	"github.com/spf13/cobra"
+)
+
+// zksyncSend 在 zkSync Era 上用原生 EIP-712 交易（类型 0x71）转账或调用合约
+func zksyncSend() *cobra.Command {
+	cmd := &cobra.Command{Use: "send", Short: "Send a native EIP-712 transaction on zkSync Era"}
+	fs := cmd.Flags()
+	network := fs.String("network", "zksync-sepolia", "Era network; its RPC URL is read from $<NETWORK>_RPC unless --rpc is set")
+	to := fs.String("to", "", "recipient or contract address")
+	valueStr := fs.String("value", "0", "value to send, e.g. 0.001eth")
+	dataHex := fs.String("data", "", "call data (hex)")
+	gasPerPubdata := fs.Uint64("gas-per-pubdata", 0, "max gas per pubdata byte (default: from zks_estimateFee)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		recipient, err := address.ParseHex(*to)
+		if err != nil {
+			return fmt.Errorf("--to: %w", err)
+		}
+		value, err := units.ParseAmount(*valueStr)
+		if err != nil {
+			return err
+		}
+		url := rpcFlag
+		if url == "" {
+			url = networkRPCURL(*network)
+		}
+		if url == "" {
+			return fmt.Errorf("no RPC URL: set %s or use --rpc", networkRPCEnv(*network))
+		}
+		key, err := loadPrivateKey()
+		if err != nil {
+			return err
+		}
+
+		ctx := rootCtx
+		client, err := zksync.Dial(ctx, url)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", url, err)
+		}
+		defer client.Close()
+
+		tx := &zksync.Transaction{
+			From:          crypto.PubkeyToAddress(key.PublicKey),
+			To:            &recipient,
+			Value:         value,
+			Data:          common.FromHex(*dataHex),
+			GasPerPubdata: *gasPerPubdata,
+		}
+		if err := client.Populate(ctx, tx); err != nil {
+			return err
+		}
+		if err := tx.Sign(key); err != nil {
+			return err
+		}
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(tx.GasLimit), tx.MaxFeePerGas)
+		fmt.Printf("From:            %s\n", tx.From.Hex())
+		fmt.Printf("Gas limit:       %d (gas per pubdata %d)\n", tx.GasLimit, tx.GasPerPubdata)
+		fmt.Printf("Max fee:         %s ETH\n", units.FormatUnits(fee, 18))
+
+		hash, err := client.SendEIP712(ctx, tx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Transaction:     %s\n", hash.Hex())
+		if url := presetFor(ctx, client).TxURL(hash); url != "" {
+			fmt.Printf("Explorer:        %s\n", url)
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+		for {
+			receipt, err := client.TransactionReceipt(waitCtx, hash)
+			if err == nil {
+				if receipt.Status != 1 {
+					return fmt.Errorf("transaction %s failed with status: %d", hash.Hex(), receipt.Status)
+				}
+				fmt.Printf("✅ Included in block %s, gas used %d\n", receipt.BlockNumber, receipt.GasUsed)
+				return nil
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				return fmt.Errorf("failed to get receipt: %w", err)
+			}
+			select {
+			case <-waitCtx.Done():
+				return fmt.Errorf("wait for transaction %s: %w", hash.Hex(), waitCtx.Err())
+			case <-time.After(time.Second):
+			}
+		}
+	}
+	return cmd
+}
@@ -0,0 +1,471 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/networks"
+	"github.com/local/go-eth-demo/pkg/secret"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/local/go-eth-demo/pkg/wallet"
+	"github.com/spf13/cobra"
+)
+
+// config 是启动时从环境变量读取并校验过的设置。各命令仍按需读取环境变量和命令行参数，
+// loadConfig 在运行任何命令之前检查所有已设置的值，把问题一次全部报告，而不是运行到一半才因某个变量失败
+type config struct {
+	Network        string // 为空时未选择网络
+	ChainID        uint64 // NETWORK 对应的链 ID，未选择网络时为 0
+	RPCURLs        []string
+	Signer         string         // local 或 clef
+	Account        common.Address // 由 PRIVATE_KEY 或 MNEMONIC 得到的签名账户，使用 keystore 或 Clef 时为零值
+	KeySource      string         // 签名账户的来源，如 PRIVATE_KEY、MNEMONIC、KEYSTORE、keychain
+	Recipient      string         // 地址或地址簿名称
+	Contract       common.Address
+	TransferAmount *big.Int
+	FeeStrategy    ethtx.FeeStrategy
+	GasBuffer      uint64
+	Confirmations  uint64
+	DryRun         bool
+}
+
+// configProblem 是一个设置的问题，Message 说明怎样修正；不包含变量的值，以免私钥等出现在输出中
+type configProblem struct {
+	Key     string
+	Message string
+}
+
+// configError 汇总 loadConfig 发现的所有问题
+type configError []configProblem
+
+func (e configError) Error() string {
+	var b strings.Builder
+	if len(e) == 1 {
+		b.WriteString("invalid configuration (1 problem):")
+	} else {
+		fmt.Fprintf(&b, "invalid configuration (%d problems):", len(e))
+	}
+	for _, p := range e {
+		fmt.Fprintf(&b, "\n  %s: %s", p.Key, p.Message)
+	}
+	return b.String()
+}
+
+// configChecker 逐项检查环境变量并收集问题
+type configChecker struct {
+	problems configError
+}
+
+func (c *configChecker) addf(key, format string, args ...interface{}) {
+	c.problems = append(c.problems, configProblem{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// check 在 key 已设置时用 fn 校验它的值，fn 的错误作为该变量的问题
+func (c *configChecker) check(key string, fn func(v string) error) {
+	if v := os.Getenv(key); v != "" {
+		if err := fn(v); err != nil {
+			c.addf(key, "%v", err)
+		}
+	}
+}
+
+// require 报告缺少的必填变量
+func (c *configChecker) require(key, why string) {
+	if os.Getenv(key) == "" {
+		c.addf(key, "required %s", why)
+	}
+}
+
+// loadConfig 校验所有已设置的环境变量：URL 能解析、私钥是 32 字节、金额和数字能解析、地址符合 EIP-55、
+// NETWORK 是已知的链。tasks 为 true（不带子命令运行任务）时还要求任务用到的变量都已设置。
+// 有问题时返回 configError，列出全部问题
+func loadConfig(tasks bool) (*config, error) {
+	c := &configChecker{}
+	cfg := &config{Signer: envOr("SIGNER", "local")}
+
+	// 网络和 RPC 端点
+	registry, err := loadNetworks()
+	if err != nil {
+		c.addf("NETWORKS_CONFIG", "%v", err)
+		registry = networks.Default()
+	}
+	if cfg.Network = os.Getenv("NETWORK"); cfg.Network != "" {
+		if p, err := registry.Lookup(cfg.Network); err != nil {
+			c.addf("NETWORK", "unknown network %q, run networks list to see the known networks or add it to %s", cfg.Network, envOr("NETWORKS_CONFIG", "networks.json"))
+		} else {
+			cfg.ChainID = p.ChainID
+		}
+		c.check(networkRPCEnv(cfg.Network), checkEndpoints)
+	}
+	for _, key := range []string{"RPC_URL", "SEPOLIA_RPC", "RPC_COMPARE_URLS"} {
+		c.check(key, checkEndpoints)
+	}
+	for _, key := range []string{"WS_RPC", "VERIFY_RPC"} {
+		c.check(key, checkEndpoint)
+	}
+	for _, key := range []string{"CLEF_URL", "BUNDLER_URL", "PAYMASTER_URL", "EXPLORER_API", "SAFE_TX_SERVICE", "IPFS_GATEWAY",
+		"OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"} {
+		c.check(key, checkHTTPURL)
+	}
+	c.check("PRIVATE_TX", func(v string) error {
+		if v == "off" || v == "protect" || v == "flashbots" {
+			return nil
+		}
+		if checkHTTPURL(v) != nil {
+			return errors.New("want off, protect, flashbots or a relay URL")
+		}
+		return nil
+	})
+	if rpc := rpcEndpoint(); rpc != "" {
+		cfg.RPCURLs = strings.Split(rpc, ",")
+	}
+
+	// 签名账户
+	c.check("SIGNER", func(v string) error {
+		if v != "local" && v != "clef" {
+			return errors.New("want local or clef")
+		}
+		return nil
+	})
+	c.check("CLEF_ACCOUNT", checkAddress)
+	c.check("FLASHBOTS_KEY", func(v string) error {
+		_, err := parseKey(v)
+		return err
+	})
+	c.check("KEY_SOURCE", func(v string) error {
+		switch v {
+		case "env":
+		case "prompt", "keychain":
+			cfg.KeySource = v
+		case "file":
+			cfg.KeySource = "PRIVATE_KEY_FILE"
+			if os.Getenv("PRIVATE_KEY_FILE") == "" {
+				return errors.New("file requires PRIVATE_KEY_FILE")
+			}
+		default:
+			return errors.New("want env, prompt, keychain or file")
+		}
+		return nil
+	})
+	c.check("PRIVATE_KEY_FILE", func(v string) error {
+		key, err := secret.ReadFile(v)
+		if err != nil {
+			return err
+		}
+		w, err := parseKey(key)
+		if err == nil && cfg.KeySource == "PRIVATE_KEY_FILE" {
+			cfg.Account = w.Address()
+		}
+		return err
+	})
+	c.check("PRIVATE_KEY", func(v string) error {
+		// KEY_SOURCE 的目的是让私钥不出现在环境中，同时设置时提醒删除
+		if cfg.KeySource != "" {
+			return fmt.Errorf("ignored because KEY_SOURCE=%s, remove it from the environment and .env", os.Getenv("KEY_SOURCE"))
+		}
+		w, err := parseKey(v)
+		if err == nil && cfg.KeySource == "" {
+			cfg.Account, cfg.KeySource = w.Address(), "PRIVATE_KEY"
+		}
+		return err
+	})
+	var index uint64
+	c.check("HD_INDEX", func(v string) (err error) {
+		if index, err = strconv.ParseUint(v, 10, 31); err != nil {
+			return errors.New("want an account index between 0 and 2147483647")
+		}
+		return nil
+	})
+	c.check("HD_PATH", func(v string) error {
+		if _, err := accounts.ParseDerivationPath(v); err != nil {
+			return fmt.Errorf("invalid derivation path, want a base path such as %s", wallet.DefaultHDBase)
+		}
+		return nil
+	})
+	c.check("MNEMONIC", func(v string) error {
+		h, err := wallet.NewHDWallet(v, os.Getenv("MNEMONIC_PASSPHRASE"))
+		if err != nil {
+			return errors.New("want 12, 15, 18, 21 or 24 BIP-39 words")
+		}
+		if w, err := h.DeriveIndex(envOr("HD_PATH", wallet.DefaultHDBase), uint32(index)); err == nil && cfg.KeySource == "" && os.Getenv("KEYSTORE") == "" {
+			cfg.Account, cfg.KeySource = w.Address(), "MNEMONIC"
+		}
+		return nil
+	})
+	c.check("KEYSTORE", func(v string) error {
+		if _, err := os.Stat(v); err != nil {
+			return fmt.Errorf("keystore file: %w", err)
+		}
+		if cfg.KeySource == "" {
+			cfg.KeySource = "KEYSTORE"
+		}
+		return nil
+	})
+	if cfg.Signer == "clef" {
+		cfg.Account, cfg.KeySource = common.Address{}, "clef"
+	}
+
+	// 地址
+	for _, key := range []string{"TOKEN_ADDR", "NFT_ADDR", "DISPERSE_ADDR", "SAFE_ADDR"} {
+		c.check(key, checkAddress)
+	}
+	c.check("METRICS_WALLETS", func(v string) error {
+		for _, a := range splitList(v) {
+			if err := checkAddress(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	// CONTRACT_ADDR 可以是逗号分隔的多个合约（counter get、watch logs），任务使用第一个
+	c.check("CONTRACT_ADDR", func(v string) error {
+		for i, a := range strings.Split(v, ",") {
+			addr, err := address.ParseHex(a)
+			if err != nil {
+				return err
+			}
+			if i == 0 {
+				cfg.Contract = addr
+			}
+		}
+		return nil
+	})
+	// 收款方和喂价也可以是地址簿名称或交易对，只有看起来像地址时才校验
+	cfg.Recipient = os.Getenv("RECIPIENT_ADDR")
+	for _, key := range []string{"RECIPIENT_ADDR", "PRICE_FEED"} {
+		c.check(key, func(v string) error {
+			if strings.HasPrefix(v, "0x") || common.IsHexAddress(v) {
+				return checkAddress(v)
+			}
+			return nil
+		})
+	}
+
+	// 金额、数字和选项
+	c.check("TRANSFER_AMOUNT", func(v string) (err error) {
+		cfg.TransferAmount, err = units.ParseAmount(v)
+		return err
+	})
+	c.check("TOKEN_AMOUNT", func(v string) error {
+		// 精度取决于代币，这里只检查数字部分，符号在读取代币后核对
+		num, _, _ := strings.Cut(strings.TrimSpace(v), " ")
+		if r, ok := new(big.Rat).SetString(num); !ok || r.Sign() < 0 {
+			return errors.New(`want a token amount such as 12.5 or "12.5 USDC"`)
+		}
+		return nil
+	})
+	c.check("FEE_STRATEGY", func(v string) (err error) {
+		cfg.FeeStrategy, err = ethtx.ParseFeeStrategy(v)
+		return err
+	})
+	if cfg.FeeStrategy.Name == "" {
+		cfg.FeeStrategy = ethtx.StandardFees
+	}
+	cfg.GasBuffer, cfg.Confirmations = ethtx.DefaultGasBuffer, 1
+	for _, n := range []struct {
+		key string
+		dst *uint64
+	}{{"GAS_BUFFER", &cfg.GasBuffer}, {"WAIT_CONFIRMATIONS", &cfg.Confirmations}, {"RPC_RETRIES", new(uint64)}, {"RPC_BURST", new(uint64)}} {
+		c.check(n.key, func(v string) (err error) {
+			if *n.dst, err = strconv.ParseUint(v, 10, 64); err != nil {
+				return errors.New("want a non-negative integer")
+			}
+			return nil
+		})
+	}
+	c.check("RPC_RATE_LIMIT", func(v string) error {
+		if n, err := strconv.ParseFloat(v, 64); err != nil || n < 0 {
+			return errors.New("want requests per second, e.g. 10 or 0.5")
+		}
+		return nil
+	})
+	for _, key := range []string{"RPC_CACHE_TTL", "FEED_MAX_AGE"} {
+		c.check(key, func(v string) error {
+			if _, err := time.ParseDuration(v); err != nil {
+				return errors.New("want a duration such as 30s or 1h")
+			}
+			return nil
+		})
+	}
+	for _, key := range []string{"DRY_RUN", "ALLOW_CHAIN_MISMATCH", "ACCESS_LIST"} {
+		c.check(key, func(v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return errors.New("want true or false")
+			}
+			if key == "DRY_RUN" {
+				cfg.DryRun = b
+			}
+			return nil
+		})
+	}
+	c.check("WAIT_FINALITY", func(v string) error {
+		if v != "safe" && v != "finalized" {
+			return errors.New("want safe or finalized")
+		}
+		return nil
+	})
+	c.check("OUTPUT", func(v string) error {
+		if v != "text" && v != "json" {
+			return errors.New("want text or json")
+		}
+		return nil
+	})
+	c.check("LOG_LEVEL", func(v string) error {
+		var level slog.Level
+		if level.UnmarshalText([]byte(v)) != nil {
+			return errors.New("want debug, info, warn or error")
+		}
+		return nil
+	})
+	c.check("LOG_FORMAT", func(v string) error {
+		if v != "text" && v != "json" {
+			return errors.New("want text or json")
+		}
+		return nil
+	})
+	c.check("PRICE_PROVIDER", func(v string) error {
+		if v != "coingecko" && v != "coinbase" {
+			return errors.New("want coingecko or coinbase")
+		}
+		return nil
+	})
+
+	// 不带子命令时依次运行的任务需要收款方、合约和签名账户
+	if tasks {
+		c.require("RECIPIENT_ADDR", "by task01, the address or address book name to send ETH to")
+		c.require("CONTRACT_ADDR", "by task02, the deployed Counter contract (see counter deploy)")
+		if cfg.KeySource == "" && loadPrivateKeyHex() == "" {
+			c.addf("PRIVATE_KEY", "required to sign transactions: set PRIVATE_KEY, KEY_SOURCE, KEYSTORE or MNEMONIC, or SIGNER=clef")
+		}
+	}
+	if len(c.problems) > 0 {
+		return cfg, c.problems
+	}
+	return cfg, nil
+}
+
+// parseKey 解析 32 字节的十六进制私钥，错误信息不包含私钥本身
+func parseKey(v string) (*wallet.Wallet, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(v), "0x")
+	if len(hex) != 64 {
+		return nil, fmt.Errorf("want a 32-byte private key (64 hex characters), got %d characters", len(hex))
+	}
+	w, err := wallet.FromHex(hex)
+	if err != nil {
+		return nil, errors.New("not a valid secp256k1 private key in hex")
+	}
+	return w, nil
+}
+
+func checkAddress(v string) error {
+	_, err := address.ParseHex(v)
+	return err
+}
+
+// checkEndpoints 检查逗号分隔的 RPC 端点（多个时在它们之间故障切换）
+func checkEndpoints(v string) error {
+	for _, e := range strings.Split(v, ",") {
+		if err := checkEndpoint(strings.TrimSpace(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkEndpoint 检查 RPC 端点：http(s) 或 ws(s) URL，或者 IPC 文件路径。错误中只出现主机名，不出现 URL 里的 API key
+func checkEndpoint(v string) error {
+	if !strings.Contains(v, "://") {
+		if _, err := os.Stat(v); err != nil {
+			return errors.New("want an http(s) or ws(s) URL or the path of an IPC socket")
+		}
+		return nil
+	}
+	u, err := url.Parse(v)
+	if err != nil {
+		return errors.New("not a valid URL")
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return fmt.Errorf("unsupported scheme %q, want http, https, ws or wss", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("URL has no host")
+	}
+	return nil
+}
+
+// checkHTTPURL 检查 http(s) URL
+func checkHTTPURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil {
+		return errors.New("not a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return errors.New("want an http:// or https:// URL")
+	}
+	return nil
+}
+
+// configCheck 校验所有环境变量（包括 .env 中的），列出全部问题；没有问题时显示生效的主要设置
+func configCheck() *cobra.Command {
+	cmd := &cobra.Command{Use: "check", Short: "Validate the environment and show the effective settings"}
+	fs := cmd.Flags()
+	tasks := fs.Bool("tasks", false, "also require the settings used when running the tasks without a command")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(*tasks)
+		if err != nil {
+			fmt.Println(err)
+			return errors.New("configuration check failed")
+		}
+		network := cfg.Network
+		if network == "" {
+			network = "(not set)"
+		} else {
+			network = fmt.Sprintf("%s (chain %d)", network, cfg.ChainID)
+		}
+		fmt.Printf("Network:         %s\n", network)
+		fmt.Printf("RPC endpoints:   %s\n", strings.Join(endpointHosts(strings.Join(cfg.RPCURLs, ",")), ", "))
+		switch {
+		case cfg.Account != (common.Address{}):
+			fmt.Printf("Signer:          %s from %s\n", cfg.Account.Hex(), cfg.KeySource)
+		case cfg.KeySource == "keychain":
+			k := keychainEntry()
+			fmt.Printf("Signer:          keychain entry %s/%s\n", k.Service, k.Account)
+		case cfg.KeySource != "":
+			fmt.Printf("Signer:          %s\n", cfg.KeySource)
+		default:
+			fmt.Println("Signer:          (none)")
+		}
+		if cfg.Recipient != "" {
+			fmt.Printf("Recipient:       %s\n", cfg.Recipient)
+		}
+		if cfg.Contract != (common.Address{}) {
+			fmt.Printf("Contract:        %s\n", cfg.Contract.Hex())
+		}
+		if cfg.TransferAmount != nil {
+			fmt.Printf("Transfer amount: %s ETH\n", units.FormatUnits(cfg.TransferAmount, 18))
+		}
+		fmt.Printf("Fee strategy:    %s\n", cfg.FeeStrategy.Name)
+		fmt.Printf("Gas buffer:      %d%%\n", cfg.GasBuffer)
+		fmt.Printf("Confirmations:   %d\n", cfg.Confirmations)
+		if cfg.DryRun {
+			fmt.Println("Dry run:         yes")
+		}
+		fmt.Println("Configuration OK")
+		return nil
+	}
+	return cmd
+}
@@ -1,11 +1,14 @@
 // SPDX-License-Identifier: MIT
-pragma solidity ^0.8.0;
+pragma solidity 0.8.30;
 
 contract Counter {
     uint public count;
 
+    event CountIncremented(uint256 newValue, address indexed by);
+
     function increment() public {
         count += 1;
+        emit CountIncremented(count, msg.sender);
     }
 
     function getCount() public view returns (uint) {
@@ -31,8 +31,8 @@ var (
 
 // CounterMetaData contains all meta data concerning the Counter contract.
 var CounterMetaData = &bind.MetaData{
-	ABI: "[{\"inputs\":[],\"name\":\"count\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"increment\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
-	Bin: "0x6080604052348015600e575f5ffd5b506101778061001c5f395ff3fe608060405234801561000f575f5ffd5b506004361061003f575f3560e01c806306661abd14610043578063a87d942c14610061578063d09de08a1461007f575b5f5ffd5b61004b610089565b60405161005891906100c8565b60405180910390f35b61006961008e565b60405161007691906100c8565b60405180910390f35b610087610096565b005b5f5481565b5f5f54905090565b60015f5f8282546100a7919061010e565b92505081905550565b5f819050919050565b6100c2816100b0565b82525050565b5f6020820190506100db5f8301846100b9565b92915050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52601160045260245ffd5b5f610118826100b0565b9150610123836100b0565b925082820190508082111561013b5761013a6100e1565b5b9291505056fea264697066735822122090659d50550ba2f93a8d4e7467627b68fddca9eca00e97993fbb29890f3e73b564736f6c634300081e0033",
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newValue\",\"type\":\"uint256\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"by\",\"type\":\"address\"}],\"name\":\"CountIncremented\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"count\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"increment\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x6080604052348015600e575f5ffd5b506100b98061001c5f395ff3fe608060405234801561000f575f80fd5b506004361061003f575f3560e01c806306661abd14610043578063a87d942c14610043578063d09de08a14610050575b5f80fd5b5f54604051908152602090f35b5f54600101801561008c57805f55604051818152337f673c1c9e933f8c4e54060f6e9c7e7c0983e491d719c3946117f45357440ad64f602083a2005b7f4e487b71000000000000000000000000000000000000000000000000000000005f52601160045260245ffd",
 }
 
 // CounterABI is the input ABI used to generate the binding from.
@@ -284,3 +284,148 @@ func (_Counter *CounterSession) Increment() (*types.Transaction, error) {
 func (_Counter *CounterTransactorSession) Increment() (*types.Transaction, error) {
 	return _Counter.Contract.Increment(&_Counter.TransactOpts)
 }
+
+// CounterCountIncrementedIterator is returned from FilterCountIncremented and is used to iterate over the raw logs and unpacked data for CountIncremented events raised by the Counter contract.
+type CounterCountIncrementedIterator struct {
+	Event *CounterCountIncremented // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CounterCountIncrementedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CounterCountIncremented)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CounterCountIncremented)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CounterCountIncrementedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CounterCountIncrementedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CounterCountIncremented represents a CountIncremented event raised by the Counter contract.
+type CounterCountIncremented struct {
+	NewValue *big.Int
+	By       common.Address
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterCountIncremented is a free log retrieval operation binding the contract event 0x673c1c9e933f8c4e54060f6e9c7e7c0983e491d719c3946117f45357440ad64f.
+//
+// Solidity: event CountIncremented(uint256 newValue, address indexed by)
+func (_Counter *CounterFilterer) FilterCountIncremented(opts *bind.FilterOpts, by []common.Address) (*CounterCountIncrementedIterator, error) {
+
+	var byRule []interface{}
+	for _, byItem := range by {
+		byRule = append(byRule, byItem)
+	}
+
+	logs, sub, err := _Counter.contract.FilterLogs(opts, "CountIncremented", byRule)
+	if err != nil {
+		return nil, err
+	}
+	return &CounterCountIncrementedIterator{contract: _Counter.contract, event: "CountIncremented", logs: logs, sub: sub}, nil
+}
+
+// WatchCountIncremented is a free log subscription operation binding the contract event 0x673c1c9e933f8c4e54060f6e9c7e7c0983e491d719c3946117f45357440ad64f.
+//
+// Solidity: event CountIncremented(uint256 newValue, address indexed by)
+func (_Counter *CounterFilterer) WatchCountIncremented(opts *bind.WatchOpts, sink chan<- *CounterCountIncremented, by []common.Address) (event.Subscription, error) {
+
+	var byRule []interface{}
+	for _, byItem := range by {
+		byRule = append(byRule, byItem)
+	}
+
+	logs, sub, err := _Counter.contract.WatchLogs(opts, "CountIncremented", byRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CounterCountIncremented)
+				if err := _Counter.contract.UnpackLog(event, "CountIncremented", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseCountIncremented is a log parse operation binding the contract event 0x673c1c9e933f8c4e54060f6e9c7e7c0983e491d719c3946117f45357440ad64f.
+//
+// Solidity: event CountIncremented(uint256 newValue, address indexed by)
+func (_Counter *CounterFilterer) ParseCountIncremented(log types.Log) (*CounterCountIncremented, error) {
+	event := new(CounterCountIncremented)
+	if err := _Counter.contract.UnpackLog(event, "CountIncremented", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
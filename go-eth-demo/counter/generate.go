@@ -0,0 +1,6 @@
+// Package counter 是由 Counter.sol 生成的合约绑定：先用固定版本的 solc 编译出 build/Counter.abi 和 build/Counter.bin，
+// 再用 abigen 生成 counter.go。修改合约后运行 go generate 重新生成，不要手动编辑生成的文件。
+package counter
+
+//go:generate solc --abi --bin --overwrite -o build Counter.sol
+//go:generate go tool abigen --abi build/Counter.abi --bin build/Counter.bin --pkg counter --type Counter --out counter.go
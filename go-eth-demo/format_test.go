@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/local/go-eth-demo/internal/golden"
+)
+
+func TestUnitFormattersGolden(t *testing.T) {
+	values := []string{
+		"0",
+		"1",
+		"999999999",
+		"1000000000",
+		"1500000000",
+		"21000000000000",
+		"1000000000000000",
+		"159396525300000000",
+		"1000000000000000000",
+		"123456789012345678901234567890",
+	}
+	var b strings.Builder
+	for _, v := range values {
+		wei, _ := new(big.Int).SetString(v, 10)
+		fmt.Fprintf(&b, "%s wei = %s ETH = %s Gwei\n", v, weiToEth(wei), weiToGwei(wei))
+	}
+	golden.Assert(t, "units", []byte(b.String()))
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/local/go-eth-demo/pkg/redact"
+)
+
+// redactor 遮盖写到标准错误的秘密，setupRedaction 登记环境中的秘密，loadWallet 登记运行时读取的私钥
+var redactor = redact.New()
+
+// stderr 是经过 redactor 的标准错误：日志（包括 fatal 的错误）、命令行帮助和 panic 都写到这里，
+// 私钥、助记词、API key 和 RPC URL 中的凭据被替换为 REDACTED
+var stderr io.Writer = redactor.Writer(os.Stderr)
+
+// secretEnv 是值为秘密的环境变量
+var secretEnv = []string{
+	"PRIVATE_KEY", "MNEMONIC", "MNEMONIC_PASSPHRASE", "KEYSTORE_PASSWORD", "FLASHBOTS_KEY",
+	"ETHERSCAN_API_KEY", "EXPLORER_API_KEY", "COINGECKO_API_KEY", "SAFE_API_KEY", "API_TOKEN",
+}
+
+// setupRedaction 登记 secretEnv 的值，以及 OTEL_EXPORTER_OTLP_HEADERS 中的头部值（如 Authorization 的令牌）
+func setupRedaction() {
+	for _, key := range secretEnv {
+		redactor.Add(os.Getenv(key))
+	}
+	for _, h := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if _, v, ok := strings.Cut(h, "="); ok {
+			redactor.Add(v)
+		}
+	}
+}
+
+// setupLogging 按 LOG_LEVEL（debug、info、warn、error，默认 warn）和 LOG_FORMAT（text 或 json）
+// 配置写到标准错误的 slog 日志。命令的结果写到标准输出，日志默认只显示警告和错误，-v 显示全部调试信息。
+// log 包的输出（fatal 的错误）也经过同一个 handler，按 error 级别记录
+func setupLogging() error {
+	level := slog.LevelWarn
+	if s := os.Getenv("LOG_LEVEL"); s != "" {
+		if err := level.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("invalid LOG_LEVEL %q, want debug, info, warn or error", s)
+		}
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch format := envOr("LOG_FORMAT", "text"); format {
+	case "text":
+		h = slog.NewTextHandler(stderr, opts)
+	case "json":
+		h = slog.NewJSONHandler(stderr, opts)
+	default:
+		return fmt.Errorf("invalid LOG_FORMAT %q, want text or json", format)
+	}
+	slog.SetDefault(slog.New(h))
+	log.SetFlags(0)
+	log.SetOutput(slog.NewLogLogger(h, slog.LevelError).Writer())
+	return nil
+}
+
+// logger 返回带 component 字段（rpc、wallet、tx、config 等）的日志记录器
+func logger(component string) *slog.Logger {
+	return slog.With("component", component)
+}
+
+// loadDotEnv 加载当前目录的 .env 文件，已设置的环境变量优先
+func loadDotEnv() {
+	if err := godotenv.Load(); err != nil {
+		logger("config").Debug("no .env file, using environment variables")
+	}
+}
+
+// endpointHosts 返回逗号分隔的 RPC 端点的主机名，日志中不出现路径或查询参数里的 API key
+func endpointHosts(endpoints string) []string {
+	var hosts []string
+	for _, e := range strings.Split(endpoints, ",") {
+		if u, err := url.Parse(e); err == nil && u.Host != "" {
+			hosts = append(hosts, u.Host)
+		} else {
+			hosts = append(hosts, "ipc")
+		}
+	}
+	return hosts
+}
@@ -1,6 +1,846 @@
 package main
 
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/devnet"
+	"github.com/local/go-eth-demo/pkg/ens"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/failover"
+	"github.com/local/go-eth-demo/pkg/flashbots"
+	"github.com/local/go-eth-demo/pkg/multicall"
+	"github.com/local/go-eth-demo/pkg/networks"
+	"github.com/local/go-eth-demo/pkg/ratelimit"
+	"github.com/local/go-eth-demo/pkg/retry"
+	"github.com/local/go-eth-demo/pkg/rpccache"
+	"github.com/local/go-eth-demo/pkg/secret"
+	"github.com/local/go-eth-demo/pkg/tracing"
+	"github.com/local/go-eth-demo/pkg/txhistory"
+	"github.com/local/go-eth-demo/pkg/wallet"
+	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+)
+
+// commands 注册所有子命令的构造函数，key 为 "组 名称" 形式，newRootCmd 按 key 组装命令树
+var commands = map[string]func() *cobra.Command{
+	"transfer":          transfer,
+	"disburse":          disburseCmd,
+	"counter increment": counterIncrement,
+	"counter deploy":    counterDeploy,
+	"counter get":       counterGet,
+	"counter history":   counterHistory,
+	"erc20 transfer":    erc20Transfer,
+	"erc20 permit":      erc20Permit,
+	"erc20 permit-send": erc20PermitSend,
+	"token info":        tokenInfo,
+	"nft info":          nftInfo,
+	"nft transfer":      nftTransfer,
+	"erc1155 transfer":  erc1155Transfer,
+	"erc1155 balances":  erc1155Balances,
+	"call":              contractCall,
+	"send":              contractSend,
+	"block get":         blockGet,
+	"blocks fetch":      blocksFetch,
+	"export blocks":     exportBlocks,
+	"export txs":        exportTxs,
+	"tx build":          txBuild,
+	"tx sign":           txSign,
+	"tx broadcast":      txBroadcast,
+	"tx decode":         txDecode,
+	"tx resign":         txResign,
+	"tx speedup":        txSpeedUp,
+	"tx cancel":         txCancel,
+	"tx receipts":       txReceipts,
+	"tx list":           txList,
+	"account history":   accountHistory,
+	"tx show":           txShow,
+	"tx inspect":        txInspect,
+	"flashbots bundle":  flashbotsBundle,
+	"flashbots cancel":  flashbotsCancel,
+	"wallet import":     walletImport,
+	"wallet derive":     walletDerive,
+	"wallet keychain":   walletKeychain,
+	"sign message":      signMessage,
+	"verify message":    verifyMessage,
+	"rpc compare":       rpcCompare,
+	"bench rpc":         benchRPC,
+	"balance":           balance,
+	"balance history":   balanceHistory,
+	"storage":           storage,
+	"serve":             serve,
+	"notify test":       notifyTest,
+	"devnet up":         devnetUp,
+	"devnet down":       devnetDown,
+
+	"devnet snapshot save":   devnetSnapshotSave,
+	"devnet snapshot revert": devnetSnapshotRevert,
+	"devnet snapshot list":   devnetSnapshotList,
+	"devnet time increase":   devnetTimeIncrease,
+	"devnet time set":        devnetTimeSet,
+	"devnet mine":            devnetMine,
+	"devnet fork":            devnetFork,
+	"devnet impersonate":     devnetImpersonate,
+	"devnet send-as":         devnetSendAs,
+	"devnet fund":            devnetFund,
+	"l2 deposit":             l2Deposit,
+	"l2 withdraw":            l2Withdraw,
+	"l2 status":              l2Status,
+	"l2 prove":               l2Prove,
+	"l2 finalize":            l2Finalize,
+	"arb retryable":          arbRetryable,
+	"arb status":             arbStatus,
+	"arb redeem":             arbRedeem,
+	"bridge status":          bridgeStatus,
+	"networks list":          networksList,
+	"addressbook add":        addressBookAdd,
+	"addressbook list":       addressBookList,
+	"addressbook remove":     addressBookRemove,
+	"feed price":             feedPrice,
+	"watch balances":         watchBalances,
+	"watch gas":              watchGas,
+	"watch heads":            watchHeads,
+	"watch logs":             watchLogs,
+	"watch pending":          watchPending,
+	"watch stuck":            watchStuck,
+	"safe info":              safeInfo,
+	"safe propose":           safePropose,
+	"safe sign":              safeSign,
+	"safe exec":              safeExec,
+	"aa address":             aaAddress,
+	"aa send":                aaSend,
+	"aa receipt":             aaReceipt,
+	"zksync send":            zksyncSend,
+	"config check":           configCheck,
+}
+
+// groups 是只用来组织子命令的中间命令的说明
+var groups = map[string]string{
+	"aa":              "ERC-4337 smart accounts",
+	"account":         "Account activity from a block explorer",
+	"addressbook":     "Named addresses",
+	"arb":             "Arbitrum retryable tickets",
+	"bench":           "Benchmarks",
+	"block":           "Blocks",
+	"blocks":          "Block ranges",
+	"bridge":          "Cross-chain bridge messages",
+	"config":          "Configuration",
+	"counter":         "The Counter contract",
+	"devnet":          "Local development chain",
+	"devnet snapshot": "Named chain state snapshots",
+	"devnet time":     "Chain time",
+	"erc1155":         "ERC-1155 tokens",
+	"erc20":           "ERC-20 transfers and permits",
+	"export":          "Export chain data",
+	"feed":            "Chainlink price feeds",
+	"flashbots":       "Flashbots bundles and private transactions",
+	"l2":              "OP Stack deposits and withdrawals",
+	"networks":        "Network presets",
+	"nft":             "ERC-721 tokens",
+	"notify":          "Alert notifications",
+	"rpc":             "RPC endpoints",
+	"safe":            "Safe multisig transactions",
+	"sign":            "Signing",
+	"token":           "ERC-20 token queries",
+	"tx":              "Build, sign, send and inspect transactions",
+	"verify":          "Signature verification",
+	"wallet":          "Keys, keystores and mnemonics",
+	"watch":           "Follow the chain and alert",
+	"zksync":          "zkSync Era",
+}
+
+// 全局参数的值。rpcFlag 为空时使用 rpcEndpoint 的默认端点，其余由 applyGlobalFlags 转换为等价的环境变量
+var (
+	rpcFlag           string
+	chainFlag         string
+	verboseFlag       bool
+	allowMismatchFlag bool
+)
+
+// rpcEndpoint 返回命令连接的 RPC 端点：优先全局参数 --rpc。NETWORK（或 --chain）选择了网络时，依次使用：
+// local 网络上 devnet up 启动的节点、$<NETWORK>_RPC、链配置中的 rpc；否则优先 RPC_URL，其次 SEPOLIA_RPC
+func rpcEndpoint() string {
+	if rpcFlag != "" {
+		return rpcFlag
+	}
+	if network := os.Getenv("NETWORK"); network != "" {
+		if network == "local" {
+			if st, err := devnet.Load(devnet.DefaultDir); err == nil {
+				return st.RPCURL
+			}
+		}
+		if url := networkRPCURL(network); url != "" {
+			return url
+		}
+		if p, ok := selectedNetwork(); ok && p.RPCURL() != "" {
+			return p.RPCURL()
+		}
+		logger("rpc").Warn("network has no RPC endpoint configured", "network", network, "env", networkRPCEnv(network))
+	}
+	if url := os.Getenv("RPC_URL"); url != "" {
+		return url
+	}
+	if url := os.Getenv("SEPOLIA_RPC"); url != "" {
+		return url
+	}
+	return "https://eth-sepolia.g.alchemy.com/v2/5kxZJaABVsl6R8LWJEcDvkapc6nwG8ik" // 默认值
+}
+
+// selectedNetwork 返回 NETWORK 选择的链配置，未设置 NETWORK 或网络未知时返回 false
+func selectedNetwork() (networks.Preset, bool) {
+	name := os.Getenv("NETWORK")
+	if name == "" {
+		return networks.Preset{}, false
+	}
+	registry, err := loadNetworks()
+	if err != nil {
+		logger("config").Warn("using built-in network presets", "err", err)
+		registry = networks.Default()
+	}
+	p, err := registry.Lookup(name)
+	return p, err == nil
+}
+
+// dial 连接 RPC 端点。url 可以是逗号分隔的多个 http(s) 端点，此时请求在它们之间自动故障切换
+// （见 pkg/failover），后台健康探测随进程结束。HTTP 端点的请求经过 rpcTransport，限制速率、重试暂时性失败并记录交易历史和追踪。选择了链配置时检查节点的链 ID，
+// 避免把交易发到错误的链上；local 网络的链 ID 可以在 devnet up 时自定义，不检查
+func dial(ctx context.Context, url string) (*ethclient.Client, error) {
+	var client *ethclient.Client
+	var err error
+	logger("rpc").Debug("dialing", "endpoints", endpointHosts(url))
+	if urls := strings.Split(url, ","); len(urls) > 1 {
+		// 多个端点时失败的请求立即切换到下一个端点，不在单个端点上重试
+		client, _, err = failover.Dial(ctx, urls, failover.Options{
+			Transport: recordingTransport(privateTransport(cacheTransport(limitTransport(nil)))),
+			OnFailure: func(endpoint string, err error) {
+				logger("rpc").Warn("RPC endpoint failed, failing over", "endpoint", endpoint, "err", err)
+			},
+		})
+	} else if t := rpcTransport(); t != nil {
+		var c *rpc.Client
+		if c, err = rpc.DialOptions(ctx, url, rpc.WithHTTPClient(&http.Client{Transport: t})); err == nil {
+			client = ethclient.NewClient(c)
+		}
+	} else {
+		client, err = ethclient.DialContext(ctx, url)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the Ethereum client: %w", err)
+	}
+	p, ok := selectedNetwork()
+	if !ok || p.Name == "local" {
+		return client, nil
+	}
+	logger("rpc").Debug("checking chain ID", "network", p.Name, "want", p.ChainID)
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if err := checkChainID(p, id); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// checkChainID 检查节点的 eth_chainId 与链配置一致。交易按节点的链 ID 做 EIP-155 签名，不一致时拒绝继续，
+// 除非设置了 ALLOW_CHAIN_MISMATCH（或全局参数 --allow-chain-mismatch），此时只给出警告
+func checkChainID(p networks.Preset, id *big.Int) error {
+	if id.IsUint64() && id.Uint64() == p.ChainID {
+		return nil
+	}
+	if envBool("ALLOW_CHAIN_MISMATCH") {
+		logger("rpc").Warn("RPC endpoint is on a different chain than the network, signing for the endpoint's chain", "network", p.Name, "want", p.ChainID, "got", id)
+		return nil
+	}
+	return fmt.Errorf("RPC endpoint is on chain %s, but network %s has chain ID %d (use --allow-chain-mismatch to continue anyway)", id, p.Name, p.ChainID)
+}
+
+// rpcTransport 返回 dial 使用的 HTTP transport：RPC_CACHE 中已有的结果直接返回，请求速率不超过 RPC_RATE_LIMIT，
+// 暂时性失败按 RPC_RETRIES 重试，发送的交易记录到 TX_HISTORY（见 pkg/txhistory），启用追踪时每个请求记录一个 span，
+// 设置了 PRIVATE_TX 时交易私下提交而不发给节点。全部关闭时返回 nil，使用默认 transport
+func rpcTransport() http.RoundTripper {
+	return recordingTransport(privateTransport(cacheTransport(retryTransport(limitTransport(nil)))))
+}
+
+// rpcCache 是进程内共用的 RPC 结果缓存（见 pkg/rpccache）。RPC_CACHE 为 memory 时只缓存在内存中，
+// 为目录时同时持久化到该目录，未设置或为 off 时返回 nil。latest 类数据的缓存时间取自 RPC_CACHE_TTL，默认 1 秒
+var rpcCache = sync.OnceValue(func() *rpccache.Cache {
+	opts := rpccache.Options{TTL: time.Second}
+	switch v := os.Getenv("RPC_CACHE"); v {
+	case "", "off":
+		return nil
+	case "memory":
+	default:
+		opts.Dir = v
+	}
+	if v := os.Getenv("RPC_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			fatal(fmt.Errorf("invalid RPC_CACHE_TTL %q: %w", v, err))
+		}
+		opts.TTL = d
+	}
+	opts.OnHit = func(method string) {
+		logger("rpc").Debug("cache hit", "method", method)
+	}
+	return rpccache.New(opts)
+})
+
+// cacheTransport 在 next 之前查询 rpcCache，命中的请求不经过限速和重试；未启用缓存时返回 next
+func cacheTransport(next http.RoundTripper) http.RoundTripper {
+	c := rpcCache()
+	if c == nil {
+		return next
+	}
+	return c.Transport(next)
+}
+
+// rpcLimiter 是进程内所有 RPC 连接共用的令牌桶，速率取自 RPC_RATE_LIMIT（每秒请求数，可以是小数），
+// 容量取自 RPC_BURST（默认为速率向上取整）。未设置或为 0 时返回 nil，不限速
+var rpcLimiter = sync.OnceValue(func() *rate.Limiter {
+	limit, err := strconv.ParseFloat(os.Getenv("RPC_RATE_LIMIT"), 64)
+	if err != nil || limit <= 0 {
+		return nil
+	}
+	burst := envUint("RPC_BURST", uint64(math.Ceil(limit)))
+	return rate.NewLimiter(rate.Limit(limit), max(int(burst), 1))
+})
+
+// limitTransport 在 next 之前按 rpcLimiter 等待（见 pkg/ratelimit），放在重试之内使每次重试也消耗配额；
+// 未启用限速时返回 next
+func limitTransport(next http.RoundTripper) http.RoundTripper {
+	l := rpcLimiter()
+	if l == nil {
+		return next
+	}
+	return ratelimit.Transport(next, l, func(calls int, delay time.Duration) {
+		logger("rpc").Debug("rate limited, waiting", "calls", calls, "delay", delay.Round(time.Millisecond))
+	})
+}
+
+// retryTransport 对连接错误、限流和网关错误按指数退避重试（见 pkg/retry），重试次数取自 RPC_RETRIES
+// （默认 4），为 0 时直接返回 next
+func retryTransport(next http.RoundTripper) http.RoundTripper {
+	retries := envUint("RPC_RETRIES", 4)
+	if retries == 0 {
+		return next
+	}
+	return retry.Transport(next, retry.Policy{
+		Attempts: int(retries) + 1,
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			logger("rpc").Warn("RPC request failed, retrying", "attempt", attempt, "delay", delay.Round(time.Millisecond), "err", err)
+		},
+	})
+}
+
+// recordingTransport 在 next 之上记录交易历史和追踪 span，两者都关闭时返回 next
+func recordingTransport(next http.RoundTripper) http.RoundTripper {
+	t := next
+	if tracing.Enabled() {
+		t = tracing.Transport(t)
+	}
+	if store := txHistory(); store != nil {
+		t = txhistory.Transport(store, t, func(err error) {
+			logger("tx").Warn("failed to record transaction history", "err", err)
+		})
+	}
+	return t
+}
+
+// privateSender 按 PRIVATE_TX 选择私下提交交易的方式（见 pkg/flashbots）：protect 使用 Flashbots Protect RPC，
+// flashbots 用 eth_sendPrivateTransaction 发给 Flashbots 中继，URL 为兼容的中继或构建者端点。
+// 中继请求用 FLASHBOTS_KEY 签名，未设置时每次运行生成临时身份。未设置或为 off 时返回 nil
+var privateSender = sync.OnceValue(func() flashbots.Sender {
+	switch v := os.Getenv("PRIVATE_TX"); v {
+	case "", "off":
+		return nil
+	case "protect":
+		return &flashbots.Protect{}
+	default:
+		relay := &flashbots.Relay{Identity: flashbotsIdentity()}
+		if v != "flashbots" {
+			if !strings.HasPrefix(v, "http://") && !strings.HasPrefix(v, "https://") {
+				fatal(fmt.Errorf("invalid PRIVATE_TX %q: want off, protect, flashbots or a relay URL", v))
+			}
+			relay.URL = v
+		}
+		return relay
+	}
+})
+
+// flashbotsIdentity 返回签名中继请求的身份私钥（$FLASHBOTS_KEY）。它与签名交易的账户无关，
+// 未设置时生成一个临时身份，中继不会为它积累信誉
+func flashbotsIdentity() *ecdsa.PrivateKey {
+	if v := os.Getenv("FLASHBOTS_KEY"); v != "" {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(v, "0x"))
+		if err != nil {
+			fatal(fmt.Errorf("invalid FLASHBOTS_KEY: %w", err))
+		}
+		return key
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		fatal(fmt.Errorf("failed to generate a Flashbots identity: %w", err))
+	}
+	logger("tx").Debug("FLASHBOTS_KEY not set, using a temporary relay identity", "identity", crypto.PubkeyToAddress(key.PublicKey))
+	return key
+}
+
+// privateTransport 把 eth_sendRawTransaction 交给 privateSender，放在历史记录之内使私下提交的交易同样被记录；
+// 未设置 PRIVATE_TX 时返回 next
+func privateTransport(next http.RoundTripper) http.RoundTripper {
+	s := privateSender()
+	if s == nil {
+		return next
+	}
+	return flashbots.Transport(s, next)
+}
+
+// loadPrivateKeyHex 返回签名用的私钥：优先 PRIVATE_KEY，本地网络上缺省使用第一个预充值账户
+func loadPrivateKeyHex() string {
+	if key := os.Getenv("PRIVATE_KEY"); key != "" {
+		return key
+	}
+	if os.Getenv("NETWORK") == "local" {
+		if st, err := devnet.Load(devnet.DefaultDir); err == nil && len(st.Accounts) > 0 {
+			return strings.TrimPrefix(st.Accounts[0].PrivateKey, "0x")
+		}
+	}
+	return ""
+}
+
+// loadKeySecret 按 KEY_SOURCE 读取私钥，使私钥不必写在 .env 中：prompt 在终端隐藏输入，
+// keychain 取自操作系统钥匙串中 KEYCHAIN_SERVICE/KEYCHAIN_ACCOUNT 的条目（见 wallet keychain），
+// file 取自只有所有者可读的 PRIVATE_KEY_FILE
+func loadKeySecret(source string) (string, error) {
+	switch source {
+	case "prompt":
+		return readPassphrase("Private key (hex): ")
+	case "keychain":
+		key, err := keychainEntry().Get()
+		if errors.Is(err, secret.ErrNotFound) {
+			return "", fmt.Errorf("%w (store the key with wallet keychain)", err)
+		}
+		return key, err
+	case "file":
+		path := os.Getenv("PRIVATE_KEY_FILE")
+		if path == "" {
+			return "", fmt.Errorf("KEY_SOURCE=file requires PRIVATE_KEY_FILE")
+		}
+		return secret.ReadFile(path)
+	default:
+		return "", fmt.Errorf("unknown KEY_SOURCE %q, want env, prompt, keychain or file", source)
+	}
+}
+
+// keychainEntry 返回 KEY_SOURCE=keychain 使用的钥匙串条目
+func keychainEntry() secret.Keychain {
+	return secret.Keychain{Service: envOr("KEYCHAIN_SERVICE", secret.DefaultService), Account: envOr("KEYCHAIN_ACCOUNT", "default")}
+}
+
+// networkRPCURL 返回命名网络的 RPC 端点，从 $<NETWORK>_RPC 读取（如 op-sepolia 对应 OP_SEPOLIA_RPC）
+func networkRPCURL(network string) string {
+	return os.Getenv(networkRPCEnv(network))
+}
+
+func networkRPCEnv(network string) string {
+	return strings.ToUpper(strings.ReplaceAll(network, "-", "_")) + "_RPC"
+}
+
+// loadNetworks 返回内置链预设加上 $NETWORKS_CONFIG（默认 networks.json）中的用户配置
+func loadNetworks() (*networks.Registry, error) {
+	return networks.Load(envOr("NETWORKS_CONFIG", "networks.json"))
+}
+
+// presetFor 返回客户端所连链的预设，未知的链按 ETH 显示
+func presetFor(ctx context.Context, client chain.Client) networks.Preset {
+	registry, err := loadNetworks()
+	if err != nil {
+		logger("config").Warn("using built-in network presets", "err", err)
+		registry = networks.Default()
+	}
+	if id, err := client.ChainID(ctx); err == nil {
+		if p, ok := registry.ByChainID(id); ok {
+			return p
+		}
+	}
+	return networks.Preset{Name: "unknown", Currency: networks.Currency{Symbol: "ETH", Decimals: 18}}
+}
+
+// addressLabels 返回显示地址的函数：地址簿中有名称时为 0xAbCd…1234 (alice)，否则用链预设中的 ENS
+// 反向解析为 0xAbCd…1234 (alice.eth)，都没有时为完整地址。addrs 中不在地址簿里的地址一次批量解析，
+// 链上没有 ENS 时不发出请求，其他地址在第一次显示时解析并缓存
+func addressLabels(ctx context.Context, client chain.Client, preset networks.Preset, addrs ...common.Address) func(common.Address) string {
+	names := bookNames(ctx, client)
+	r := ens.New(client, preset.ENS, multicall.New(client, preset.Multicall))
+	var unnamed []common.Address
+	for _, addr := range addrs {
+		if names(addr) == "" {
+			unnamed = append(unnamed, addr)
+		}
+	}
+	r.Names(ctx, unnamed...)
+	return func(addr common.Address) string {
+		if name := names(addr); name != "" {
+			return ens.Short(addr) + " (" + name + ")"
+		}
+		return r.Label(ctx, addr)
+	}
+}
+
+// loadWallet 返回签名用的 Wallet，依次尝试：KEY_SOURCE 选择的 prompt、keychain 或 file（见 loadKeySecret）；PRIVATE_KEY；$KEYSTORE 指定的 keystore 文件
+// （口令取自 KEYSTORE_PASSWORD 或终端输入）；MNEMONIC 助记词在 HD_PATH/HD_INDEX 上派生的账户；
+// 最后退回 loadPrivateKeyHex 的本地默认账户
+func loadWallet() (*wallet.Wallet, error) {
+	if source := os.Getenv("KEY_SOURCE"); source != "" && source != "env" {
+		key, err := loadKeySecret(source)
+		if err != nil {
+			return nil, err
+		}
+		redactor.Add(key)
+		return wallet.FromHex(key)
+	}
+	if os.Getenv("PRIVATE_KEY") != "" {
+		return wallet.FromHex(os.Getenv("PRIVATE_KEY"))
+	}
+	if mnemonic := os.Getenv("MNEMONIC"); mnemonic != "" && os.Getenv("KEYSTORE") == "" {
+		index, err := strconv.ParseUint(envOr("HD_INDEX", "0"), 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HD_INDEX: %w", err)
+		}
+		h, err := wallet.NewHDWallet(mnemonic, os.Getenv("MNEMONIC_PASSPHRASE"))
+		if err != nil {
+			return nil, err
+		}
+		return h.DeriveIndex(envOr("HD_PATH", wallet.DefaultHDBase), uint32(index))
+	}
+	if path := os.Getenv("KEYSTORE"); path != "" {
+		pass, ok := os.LookupEnv("KEYSTORE_PASSWORD")
+		if !ok {
+			var err error
+			if pass, err = readPassphrase("Keystore passphrase: "); err != nil {
+				return nil, err
+			}
+		}
+		return wallet.FromKeystore(path, pass)
+	}
+	w, err := wallet.FromHex(loadPrivateKeyHex())
+	if errors.Is(err, wallet.ErrNoKey) {
+		return nil, fmt.Errorf("PRIVATE_KEY, KEYSTORE or MNEMONIC environment variable is required")
+	}
+	return w, err
+}
+
+// loadSigner 返回发送交易用的签名器：SIGNER=clef 时由 CLEF_URL 上的 Clef 签名
+// （CLEF_ACCOUNT 选择账户，默认为链配置的 account 或 Clef 的第一个账户），私钥不进入本进程；
+// 否则使用 loadWallet，签名账户与链配置的 account 不同时给出警告
+func loadSigner() (wallet.Signer, error) {
+	switch kind := os.Getenv("SIGNER"); kind {
+	case "", "local":
+		w, err := loadWallet()
+		if err != nil {
+			return nil, err
+		}
+		if p, ok := selectedNetwork(); ok && p.Account != (common.Address{}) && p.Account != w.Address() {
+			logger("wallet").Warn("signer differs from the network's default account", "signer", w.Address().Hex(), "network", p.Name, "account", p.Account.Hex())
+		}
+		return w, nil
+	case "clef":
+		url := envOr("CLEF_URL", "http://localhost:8550")
+		var account common.Address
+		if p, ok := selectedNetwork(); ok {
+			account = p.Account
+		}
+		if a := os.Getenv("CLEF_ACCOUNT"); a != "" {
+			var err error
+			if account, err = address.ParseHex(a); err != nil {
+				return nil, fmt.Errorf("CLEF_ACCOUNT: %w", err)
+			}
+		}
+		return wallet.DialClef(url, account)
+	default:
+		return nil, fmt.Errorf("unknown SIGNER %q, want local or clef", kind)
+	}
+}
+
+// loadPrivateKey 返回 loadWallet 的私钥，供需要直接使用私钥的命令（如 UserOperation 签名）
+func loadPrivateKey() (*ecdsa.PrivateKey, error) {
+	if os.Getenv("SIGNER") == "clef" {
+		return nil, fmt.Errorf("this command needs a local private key and does not support SIGNER=clef")
+	}
+	w, err := loadWallet()
+	if err != nil {
+		return nil, err
+	}
+	return w.PrivateKey(), nil
+}
+
+// loadTransactor 用 loadSigner 的签名器创建交易签名器，链 ID 从节点读取
+func loadTransactor(ctx context.Context, client *ethclient.Client) (*bind.TransactOpts, error) {
+	s, err := loadSigner()
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	return wallet.NewTransactor(s, chainID), nil
+}
+
 func main() {
-	task01()
-	task02()
+	// 先加载 .env，其中的 LOG_LEVEL/LOG_FORMAT 同样生效，参数的默认值也可以取自 .env
+	loadDotEnv()
+	setupRedaction()
+	// panic 的值和调用栈可能含有 RPC URL 等秘密，遮盖后再输出；其他 goroutine 中的 panic 不经过这里
+	defer func() {
+		if v := recover(); v != nil {
+			fmt.Fprintf(stderr, "panic: %v\n\n%s", v, debug.Stack())
+			os.Exit(2)
+		}
+	}()
+	root := newRootCmd()
+	root.SetArgs(normalizeArgs(root, os.Args[1:]))
+	// 命令自己的错误由 run 处理，这里只剩参数和子命令的用法错误，cobra 已经输出了错误和用法
+	if err := root.Execute(); err != nil {
+		os.Exit(2)
+	}
+}
+
+// newRootCmd 按 commands 组装命令树，生成帮助和 completion 命令。全局参数 --rpc、--chain、-v 和
+// --allow-chain-mismatch 在子命令前后都可以使用；不带子命令时运行任务（见 runTasks）
+func newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "go-eth-demo",
+		Short: "Ethereum examples and command-line tools",
+		Long: `Without a command, task01 and task02 are run in order (see transfer and counter increment),
+followed by task03 (erc20 transfer) when TOKEN_ADDR is set and task04 (feed price) when PRICE_FEED is set.
+Flags can also be written with a single dash, e.g. -rpc or -amount=1eth.`,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if builtinCommand(cmd) {
+				return
+			}
+			applyGlobalFlags()
+			setup(cmd.CommandPath() != "go-eth-demo config check", !cmd.HasParent())
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			runTasks()
+		},
+	}
+	root.SetOut(redactor.Writer(os.Stdout))
+	root.SetErr(stderr)
+	pf := root.PersistentFlags()
+	pf.StringVar(&rpcFlag, "rpc", "", "RPC endpoint, or comma-separated endpoints to fail over between (default: $<NETWORK>_RPC, RPC_URL or SEPOLIA_RPC)")
+	pf.StringVar(&chainFlag, "chain", "", "network profile (see networks list), the same as setting NETWORK")
+	pf.BoolVarP(&verboseFlag, "verbose", "v", false, "show debug logs on stderr, the same as LOG_LEVEL=debug (LOG_FORMAT=json logs JSON lines)")
+	pf.BoolVar(&allowMismatchFlag, "allow-chain-mismatch", false, "continue when the RPC endpoint is on another chain than the network, the same as ALLOW_CHAIN_MISMATCH=true")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	// 排序后 "balance" 在 "balance history" 之前加入，既是命令也是上级命令
+	sort.Strings(names)
+	for _, name := range names {
+		words := strings.Fields(name)
+		parent := root
+		for i := range words[:len(words)-1] {
+			parent = groupCmd(parent, strings.Join(words[:i+1], " "))
+		}
+		cmd := commands[name]()
+		runE := cmd.RunE
+		cmd.RunE = nil
+		cmd.Run = func(cmd *cobra.Command, args []string) {
+			run(name, func() error { return runE(cmd, args) })
+			// 自己处理中断的命令正常返回时，仍提醒本次运行中还未确认的交易
+			if interrupted() {
+				printResume(stderr)
+			}
+		}
+		parent.AddCommand(cmd)
+	}
+	return root
+}
+
+// groupCmd 返回 parent 下名为 path 最后一个词的子命令，不存在时按 groups 中的说明创建
+func groupCmd(parent *cobra.Command, path string) *cobra.Command {
+	name := path[strings.LastIndex(path, " ")+1:]
+	if cmd := subcommand(parent, name); cmd != nil {
+		return cmd
+	}
+	cmd := &cobra.Command{Use: name, Short: groups[path]}
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+// subcommand 返回 cmd 下名为 name 的子命令
+func subcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// builtinCommand 报告 cmd 是否为 cobra 生成的 help 或 completion 命令，它们不需要日志、配置校验等准备工作
+func builtinCommand(cmd *cobra.Command) bool {
+	for ; cmd.HasParent(); cmd = cmd.Parent() {
+		if !cmd.Parent().HasParent() {
+			switch cmd.Name() {
+			case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeArgs 把 Go flag 风格的单横线长参数（--rpc url、--amount=1eth）改写为 --rpc url、--amount=1eth，
+// 原来的脚本和文档中的命令行继续可用。参数按 args 中已经出现的子命令查找，-v 等单字母简写和 "--" 之后的内容不变
+func normalizeArgs(root *cobra.Command, args []string) []string {
+	out := make([]string, 0, len(args))
+	cmd, positional := root, false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(out, args[i:]...)
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if sub := subcommand(cmd, arg); sub != nil && !positional {
+				cmd = sub
+			} else {
+				positional = true
+			}
+			out = append(out, arg)
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		cmd.InitDefaultHelpFlag()
+		f := cmd.Flag(name)
+		if f != nil && !strings.HasPrefix(arg, "--") {
+			arg = "-" + arg
+		}
+		out = append(out, arg)
+		// 值在下一个参数中时一起跳过，不把它当作子命令
+		if f != nil && !hasValue && f.NoOptDefVal == "" && i+1 < len(args) {
+			i++
+			out = append(out, args[i])
+		}
+	}
+	return out
+}
+
+// applyGlobalFlags 把全局参数转换为等价的环境变量：--chain 为 NETWORK，-v 为 LOG_LEVEL=debug，
+// --allow-chain-mismatch 为 ALLOW_CHAIN_MISMATCH=true（见 checkChainID）
+func applyGlobalFlags() {
+	if chainFlag != "" {
+		os.Setenv("NETWORK", chainFlag)
+	}
+	if verboseFlag {
+		os.Setenv("LOG_LEVEL", "debug")
+	}
+	if allowMismatchFlag {
+		os.Setenv("ALLOW_CHAIN_MISMATCH", "true")
+	}
+}
+
+// setup 在运行命令之前配置日志、追踪、通知和中断处理，并一次校验所有环境变量、列出全部问题。
+// checkConfig 为 false 时（config check 自己报告结果）不校验，tasks 表示不带子命令运行任务
+func setup(checkConfig, tasks bool) {
+	if err := setupLogging(); err != nil {
+		fatal(err)
+	}
+	if err := setupTracing(); err != nil {
+		fatal(err)
+	}
+	if err := setupNotify(); err != nil {
+		fatal(err)
+	}
+	setupShutdown()
+	if checkConfig {
+		if _, err := loadConfig(tasks); err != nil {
+			// 多行的问题列表直接写到标准错误，不经过日志格式化
+			fmt.Fprintln(stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// runTasks 保持不带参数时原来的行为：依次运行两个任务，设置了 TOKEN_ADDR 时再运行 task03，
+// 设置了 PRICE_FEED 时再运行 task04
+func runTasks() {
+	run("task01", task01)
+	run("task02", task02)
+	if os.Getenv("TOKEN_ADDR") != "" {
+		run("task03", task03)
+	}
+	if os.Getenv("PRICE_FEED") != "" {
+		run("task04", task04)
+	}
+	if interrupted() {
+		printResume(stderr)
+	}
+}
+
+// 退出码：1 为其他错误，2 为用法错误，130 为中断（见 exitInterrupted）。
+// 以下类别的错误有各自的退出码，脚本可以据此决定重试、等待还是放弃
+const (
+	exitInsufficientFunds = 3
+	exitNonceTooLow       = 4
+	exitReverted          = 5
+	exitRPCUnavailable    = 6
+)
+
+// exitCode 按 ethtx 的错误类别（ethtx.Classify）返回退出码
+func exitCode(err error) int {
+	err = ethtx.Classify(err)
+	switch {
+	case errors.Is(err, ethtx.ErrInsufficientFunds):
+		return exitInsufficientFunds
+	case errors.Is(err, ethtx.ErrNonceTooLow):
+		return exitNonceTooLow
+	case errors.Is(err, ethtx.ErrReverted):
+		return exitReverted
+	case errors.Is(err, ethtx.ErrRPCUnavailable):
+		return exitRPCUnavailable
+	}
+	return 1
+}
+
+// fatal 记录 err 后按错误类别退出。错误经 log 包输出，追踪（fatalWriter）和中断（interruptWriter）的钩子同样生效
+func fatal(err error) {
+	log.Print(err)
+	os.Exit(exitCode(err))
+}
+
+// run 在名为 name 的根 span 中运行任务或命令，返回错误时调用 fatal
+func run(name string, fn func() error) {
+	traced(name, func() {
+		if err := fn(); err != nil {
+			fatal(err)
+		}
+	})
 }
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/networks"
+	"github.com/local/go-eth-demo/pkg/rpccompare"
+	"github.com/local/go-eth-demo/pkg/txhistory"
+	"github.com/spf13/cobra"
+)
+
+// parseArgs 按 main 的方式解析命令行，返回找到的子命令和它的位置参数，不运行命令
+func parseArgs(t *testing.T, args ...string) (*cobra.Command, []string) {
+	t.Helper()
+	root := newRootCmd()
+	cmd, rest, err := root.Find(normalizeArgs(root, args))
+	if err != nil {
+		t.Fatalf("Find(%q): %v", args, err)
+	}
+	if err := cmd.ParseFlags(rest); err != nil {
+		t.Fatalf("ParseFlags(%q): %v", args, err)
+	}
+	return cmd, cmd.Flags().Args()
+}
+
+func TestCommandTree(t *testing.T) {
+	root := newRootCmd()
+	for name := range commands {
+		cmd, _, err := root.Find(strings.Fields(name))
+		if err != nil || cmd.CommandPath() != "go-eth-demo "+name {
+			t.Errorf("Find(%q) = %v, %v", name, cmd.CommandPath(), err)
+		}
+	}
+	var walk func(*cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if cmd.Short == "" {
+			t.Errorf("%s has no description", cmd.CommandPath())
+		}
+		for _, c := range cmd.Commands() {
+			walk(c)
+		}
+	}
+	walk(root)
+}
+
+func TestNormalizeArgs(t *testing.T) {
+	root := newRootCmd()
+	tests := []struct {
+		args, want []string
+	}{
+		{[]string{"-chain", "base-sepolia", "transfer", "-to", "0x1", "-amount=1eth"}, []string{"--chain", "base-sepolia", "transfer", "--to", "0x1", "--amount=1eth"}},
+		{[]string{"-v", "balance", "-rpc", "http://x", "0x1", "-verify"}, []string{"-v", "balance", "--rpc", "http://x", "0x1", "--verify"}},
+		{[]string{"tx", "list", "-n", "5", "--status", "pending"}, []string{"tx", "list", "-n", "5", "--status", "pending"}},
+		{[]string{"call", "0xabc", "get", "-abi", "c.json", "--", "-1", "-abi"}, []string{"call", "0xabc", "get", "--abi", "c.json", "--", "-1", "-abi"}},
+		// 值恰好是子命令名时不进入子命令
+		{[]string{"-chain", "tx", "balance", "-help"}, []string{"--chain", "tx", "balance", "--help"}},
+		{nil, []string{}},
+	}
+	for _, tt := range tests {
+		if got := normalizeArgs(root, tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("normalizeArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestGlobalFlags(t *testing.T) {
+	t.Setenv("NETWORK", "")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("ALLOW_CHAIN_MISMATCH", "")
+	cmd, args := parseArgs(t, "-chain", "sepolia", "balance", "0x1", "-v", "--rpc=http://127.0.0.1:8545")
+	applyGlobalFlags()
+	if cmd.CommandPath() != "go-eth-demo balance" || !reflect.DeepEqual(args, []string{"0x1"}) {
+		t.Errorf("parsed %s %q", cmd.CommandPath(), args)
+	}
+	if os.Getenv("NETWORK") != "sepolia" || os.Getenv("LOG_LEVEL") != "debug" || os.Getenv("ALLOW_CHAIN_MISMATCH") != "" {
+		t.Errorf("NETWORK=%q, LOG_LEVEL=%q, ALLOW_CHAIN_MISMATCH=%q", os.Getenv("NETWORK"), os.Getenv("LOG_LEVEL"), os.Getenv("ALLOW_CHAIN_MISMATCH"))
+	}
+	if got := rpcEndpoint(); got != "http://127.0.0.1:8545" {
+		t.Errorf("rpcEndpoint() = %q, want the --rpc value", got)
+	}
+	root := newRootCmd()
+	cmd, rest, err := root.Find([]string{"balance", "--chain"})
+	if err == nil {
+		err = cmd.ParseFlags(rest)
+	}
+	if err == nil {
+		t.Error("--chain without a name accepted")
+	}
+}
+
+func TestCheckChainID(t *testing.T) {
+	p := networks.Preset{Name: "sepolia", ChainID: 11155111}
+	t.Setenv("ALLOW_CHAIN_MISMATCH", "")
+	if err := checkChainID(p, big.NewInt(11155111)); err != nil {
+		t.Errorf("matching chain ID rejected: %v", err)
+	}
+	if err := checkChainID(p, big.NewInt(1)); err == nil {
+		t.Error("mismatched chain ID accepted")
+	}
+	parseArgs(t, "-allow-chain-mismatch", "balance")
+	applyGlobalFlags()
+	if err := checkChainID(p, big.NewInt(1)); err != nil {
+		t.Errorf("mismatched chain ID rejected with -allow-chain-mismatch: %v", err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	for _, key := range []string{"NETWORK", "PRIVATE_KEY", "MNEMONIC", "KEYSTORE", "SIGNER", "RECIPIENT_ADDR", "CONTRACT_ADDR", "TRANSFER_AMOUNT", "GAS_BUFFER", "RPC_URL", "FEE_STRATEGY", "WAIT_FINALITY"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("NETWORKS_CONFIG", filepath.Join(t.TempDir(), "networks.json"))
+
+	// 所有问题一起报告，私钥不出现在错误中
+	t.Setenv("NETWORK", "nowhere")
+	t.Setenv("RPC_URL", "ftp://example.com")
+	t.Setenv("PRIVATE_KEY", "0xdeadbeef")
+	t.Setenv("CONTRACT_ADDR", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	t.Setenv("TRANSFER_AMOUNT", "1 bitcoin")
+	t.Setenv("GAS_BUFFER", "-5")
+	t.Setenv("WAIT_FINALITY", "final")
+	_, err := loadConfig(true)
+	var problems configError
+	if !errors.As(err, &problems) {
+		t.Fatalf("loadConfig error = %v, want configError", err)
+	}
+	var keys []string
+	for _, p := range problems {
+		keys = append(keys, p.Key)
+	}
+	want := []string{"NETWORK", "RPC_URL", "PRIVATE_KEY", "CONTRACT_ADDR", "TRANSFER_AMOUNT", "GAS_BUFFER", "WAIT_FINALITY", "RECIPIENT_ADDR"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("problems for %q, want %q\n%v", keys, want, err)
+	}
+	if strings.Contains(err.Error(), "deadbeef") {
+		t.Errorf("error leaks the private key: %v", err)
+	}
+
+	t.Setenv("NETWORK", "anvil")
+	t.Setenv("RPC_URL", "http://127.0.0.1:8545,https://rpc.example.com/v2/key")
+	t.Setenv("PRIVATE_KEY", "0x0000000000000000000000000000000000000000000000000000000000000001")
+	t.Setenv("CONTRACT_ADDR", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	t.Setenv("RECIPIENT_ADDR", "alice")
+	t.Setenv("TRANSFER_AMOUNT", "0.5 eth")
+	t.Setenv("GAS_BUFFER", "30")
+	t.Setenv("WAIT_FINALITY", "safe")
+	cfg, err := loadConfig(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ChainID != 31337 || cfg.Account != common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf") ||
+		cfg.TransferAmount.String() != "500000000000000000" || cfg.GasBuffer != 30 || cfg.Contract.Hex() != "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" {
+		t.Errorf("config = %+v", cfg)
+	}
+}
+
+func TestLoadConfigKeySource(t *testing.T) {
+	for _, key := range []string{"NETWORK", "MNEMONIC", "KEYSTORE", "SIGNER", "RPC_URL"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("NETWORKS_CONFIG", filepath.Join(t.TempDir(), "networks.json"))
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("0000000000000000000000000000000000000000000000000000000000000001\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KEY_SOURCE", "file")
+	t.Setenv("PRIVATE_KEY_FILE", path)
+	t.Setenv("PRIVATE_KEY", "")
+	cfg, err := loadConfig(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.KeySource != "PRIVATE_KEY_FILE" || cfg.Account != common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf") {
+		t.Errorf("signer = %s from %q", cfg.Account.Hex(), cfg.KeySource)
+	}
+	w, err := loadWallet()
+	if err != nil || w.Address() != cfg.Account {
+		t.Fatalf("loadWallet = %v, %v", w, err)
+	}
+
+	// 设置了 KEY_SOURCE 时 PRIVATE_KEY 不应再留在环境中
+	t.Setenv("PRIVATE_KEY", "0x0000000000000000000000000000000000000000000000000000000000000002")
+	var problems configError
+	if _, err := loadConfig(false); !errors.As(err, &problems) || len(problems) != 1 || problems[0].Key != "PRIVATE_KEY" {
+		t.Errorf("PRIVATE_KEY with KEY_SOURCE: %v", err)
+	}
+	t.Setenv("PRIVATE_KEY", "")
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(false); !errors.As(err, &problems) || problems[0].Key != "PRIVATE_KEY_FILE" {
+		t.Errorf("world-readable key file: %v", err)
+	}
+}
+
+func TestPrintResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	t.Setenv("TX_HISTORY", path)
+	store := txhistory.NewStore(path)
+	defer store.Close()
+	// 本次运行之前的交易不列出
+	old := txhistory.Record{Hash: common.Hash{1}, Nonce: 4, Status: txhistory.StatusPending, SentAt: started.Add(-time.Minute)}
+	sent := txhistory.Record{Hash: common.Hash{2}, Nonce: 5, Status: txhistory.StatusPending, SentAt: time.Now()}
+	for _, r := range []txhistory.Record{old, sent} {
+		if err := store.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	printResume(&buf)
+	out := buf.String()
+	if !strings.Contains(out, sent.Hash.Hex()+"  nonce 5") || strings.Contains(out, old.Hash.Hex()) || !strings.Contains(out, "tx receipts "+sent.Hash.Hex()) {
+		t.Errorf("resume instructions:\n%s", out)
+	}
+
+	// 没有未确认的交易时不输出
+	t.Setenv("TX_HISTORY", filepath.Join(t.TempDir(), "empty.db"))
+	buf.Reset()
+	if printResume(&buf); buf.Len() != 0 {
+		t.Errorf("resume without pending transactions:\n%s", buf.String())
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{errors.New("usage: balance <address>"), 1},
+		{fmt.Errorf("failed to send transaction: %w", errors.New("nonce too low: next nonce 5, tx nonce 3")), exitNonceTooLow},
+		{fmt.Errorf("%w: need 1 ETH but only have 0 ETH", ethtx.ErrInsufficientFunds), exitInsufficientFunds},
+		{&ethtx.RevertError{Reason: "not owner"}, exitReverted},
+		{fmt.Errorf("failed to get chain ID: %w", &url.Error{Op: "Post", URL: "http://127.0.0.1:8545", Err: syscall.ECONNREFUSED}), exitRPCUnavailable},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestInterspersedArgs(t *testing.T) {
+	cmd, args := parseArgs(t, "call", "0xabc", "get", "-abi", "c.json", "7", "--", "-1", "-abi")
+	if want := []string{"0xabc", "get", "7", "-1", "-abi"}; !reflect.DeepEqual(args, want) || cmd.Flag("abi").Value.String() != "c.json" {
+		t.Errorf("call args = %q, --abi %q, want %q, c.json", args, cmd.Flag("abi").Value, want)
+	}
+}
+
+func TestSetEnvVar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := setEnvVar(path, "CONTRACT_ADDR", "0x1"); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(path, []byte("# keys\nPRIVATE_KEY=abc\nexport CONTRACT_ADDR = 0x1\nCONTRACT_ADDRESS=keep"), 0o600)
+	if err := setEnvVar(path, "CONTRACT_ADDR", "0x2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := setEnvVar(path, "RECIPIENT_ADDR", "0x3"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(path)
+	want := "# keys\nPRIVATE_KEY=abc\nexport CONTRACT_ADDR=0x2\nCONTRACT_ADDRESS=keep\nRECIPIENT_ADDR=0x3\n"
+	if string(got) != want {
+		t.Errorf(".env = %q, want %q", got, want)
+	}
+}
+
+func TestInspectReport(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := common.HexToAddress("0x1c7D4B196Cb0C7B01d743Fbc6116a902379C7238")
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	data, err := erc20.ABI.Pack("transfer", to, big.NewInt(2_500_000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(11155111)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(11155111), Nonce: 3, GasTipCap: big.NewInt(2e9), GasFeeCap: big.NewInt(40e9), Gas: 60000, To: &token, Data: data,
+	})
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	preset := networks.Preset{Name: "sepolia", Explorer: "https://sepolia.etherscan.io", Currency: networks.Currency{Symbol: "ETH", Decimals: 18}}
+
+	pending := newInspectReport(preset, tx, nil, nil, knownABIs())
+	if pending.Status != "pending" || pending.Call == nil || pending.Call.Method != "transfer" || pending.Fee != "" {
+		t.Errorf("pending report = %+v", pending)
+	}
+
+	receipt := &types.Receipt{
+		Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100), GasUsed: 51000, EffectiveGasPrice: big.NewInt(12e9),
+		Logs: []*types.Log{
+			{Index: 7, Address: token, Topics: []common.Hash{erc20.ABI.Events["Transfer"].ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data: common.LeftPadBytes(big.NewInt(2_500_000).Bytes(), 32)},
+			{Index: 8, Address: token, Topics: []common.Hash{{0xab}}, Data: []byte{1, 2}},
+		},
+	}
+	r := newInspectReport(preset, tx, receipt, big.NewInt(10e9), knownABIs())
+	if r.Status != "success" || r.From != from.Hex() || r.Fee != "612000000000000" || r.PriorityFee != "2000000000" || r.BaseFee != "10000000000" {
+		t.Errorf("report = %+v", r)
+	}
+	if len(r.Logs) != 2 || r.Logs[0].Event == nil || r.Logs[0].Event.Name != "Transfer" || r.Logs[1].Event != nil || r.Logs[1].Data != "0x0102" {
+		t.Errorf("logs = %+v", r.Logs)
+	}
+
+	var buf bytes.Buffer
+	printInspect(&buf, preset, r)
+	out := buf.String()
+	for _, want := range []string{
+		"Status:               success",
+		"Gas:                  51000 used of 60000 (85.0%)",
+		"Priority Fee Paid:    2.00 Gwei",
+		"Fee:                  0.000612 ETH",
+		"Call:                 transfer(to=" + to.Hex() + ", amount=2500000)",
+		"Log #7:               " + token.Hex() + " Transfer(from=" + from.Hex(),
+		"Log #8:               " + token.Hex() + " topic0 0xab00",
+		"Explorer:             https://sepolia.etherscan.io/tx/" + tx.Hash().Hex(),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRPCCompareHidesKeys(t *testing.T) {
+	alchemy := "https://eth-sepolia.g.alchemy.com/v2/abcDEF1234567890ghiJKL"
+	infura := "https://sepolia.infura.io/v3/0123456789abcdef0123456789abcdef"
+	urls := []string{alchemy, infura, "https://eth-sepolia.g.alchemy.com/v2/zyxWVU9876543210tsrQPO"}
+	names := providerNames(urls)
+	if want := []string{"eth-sepolia.g.alchemy.com", "sepolia.infura.io", "eth-sepolia.g.alchemy.com#2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("providerNames = %q, want %q", names, want)
+	}
+
+	providers := make([]rpccompare.Provider, len(names))
+	report := &rpccompare.Report{Heads: make(map[string]uint64), CompareAt: 98}
+	check := rpccompare.Check{Name: "block 98 hash", Values: make(map[string]string)}
+	for i, name := range names {
+		providers[i] = rpccompare.Provider{Name: name}
+		report.Heads[name] = 100
+		check.Values[name] = "0x01"
+	}
+	check.Values[names[1]] = fmt.Sprintf("error: Post %q: 401 Unauthorized", infura)
+	report.Checks = []rpccompare.Check{check}
+
+	var buf bytes.Buffer
+	if err := printComparison(redactor.Writer(&buf), providers, report, 3); err == nil {
+		t.Error("printComparison with a mismatch returned nil")
+	}
+	out := buf.String()
+	for _, key := range []string{"abcDEF1234567890ghiJKL", "0123456789abcdef0123456789abcdef", "zyxWVU9876543210tsrQPO"} {
+		if strings.Contains(out, key) {
+			t.Errorf("rpc compare output contains the API key %s:\n%s", key, out)
+		}
+	}
+	if !strings.Contains(out, "sepolia.infura.io") {
+		t.Errorf("rpc compare output does not name the providers:\n%s", out)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/counterflow"
+	"github.com/local/go-eth-demo/pkg/networks"
+	"github.com/spf13/pflag"
+)
+
+// jsonStdout 是 --output json 时保存的原标准输出。之后的文字输出改写到标准错误，
+// 标准输出上只有 emit 写出的 JSON 文档，可以直接交给 jq 等工具处理
+var jsonStdout *os.File
+
+// outputFlag 注册 --output 参数，默认 $OUTPUT 或 text
+func outputFlag(fs *pflag.FlagSet) *string {
+	return fs.String("output", envOr("OUTPUT", "text"), "output format: text or json (default $OUTPUT or text)")
+}
+
+// setOutput 按 format 选择输出格式，json 时把文字输出改到标准错误，可以重复调用
+func setOutput(format string) error {
+	switch format {
+	case "", "text":
+		return nil
+	case "json":
+	default:
+		return fmt.Errorf("invalid output format %q, want text or json", format)
+	}
+	if jsonStdout == nil {
+		jsonStdout, os.Stdout = os.Stdout, os.Stderr
+	}
+	return nil
+}
+
+// emit 在 json 输出时把 v 作为一个 JSON 文档写到标准输出，text 输出时什么也不做
+func emit(v interface{}) error {
+	if jsonStdout == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = jsonStdout.Write(append(data, '\n'))
+	return err
+}
+
+// txReport 是转账和 Counter 任务的 JSON 输出。数量都是十进制的 wei 字符串，避免 JSON 数字丢失精度；
+// 预演时只有交易本身的字段和 raw，没有等待确认时没有收据字段
+type txReport struct {
+	Network  string `json:"network"`
+	ChainID  uint64 `json:"chainId"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Value    string `json:"value"`
+	Hash     string `json:"hash"`
+	Explorer string `json:"explorer,omitempty"`
+	Nonce    uint64 `json:"nonce"`
+	GasLimit uint64 `json:"gasLimit"`
+	// EIP-1559 交易有 maxFeePerGas 和 maxPriorityFeePerGas，传统交易有 gasPrice
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+	GasPrice             string `json:"gasPrice,omitempty"`
+	MaxCost              string `json:"maxCost"`
+	DryRun               bool   `json:"dryRun,omitempty"`
+	Raw                  string `json:"raw,omitempty"` // 预演时未广播的已签名交易
+
+	Block             uint64 `json:"block,omitempty"`
+	Status            string `json:"status,omitempty"` // success 或 reverted
+	GasUsed           uint64 `json:"gasUsed,omitempty"`
+	EffectiveGasPrice string `json:"effectiveGasPrice,omitempty"`
+	Fee               string `json:"fee,omitempty"` // gasUsed × effectiveGasPrice
+
+	// 转账时为发送方的余额，Counter 任务时为调用前后的计数
+	BalanceBefore string        `json:"balanceBefore,omitempty"`
+	BalanceAfter  string        `json:"balanceAfter,omitempty"`
+	CountBefore   string        `json:"countBefore,omitempty"`
+	CountAfter    string        `json:"countAfter,omitempty"`
+	Events        []eventReport `json:"events,omitempty"`
+}
+
+// eventReport 是收据中的 CountIncremented 事件
+type eventReport struct {
+	NewValue string `json:"newValue"`
+	By       string `json:"by"`
+}
+
+// newTxReport 从已签名的交易填写报告的交易字段
+func newTxReport(preset networks.Preset, from common.Address, tx *types.Transaction) *txReport {
+	r := &txReport{
+		Network:  preset.Name,
+		ChainID:  tx.ChainId().Uint64(),
+		From:     from.Hex(),
+		Value:    tx.Value().String(),
+		Hash:     tx.Hash().Hex(),
+		Explorer: preset.TxURL(tx.Hash()),
+		Nonce:    tx.Nonce(),
+		GasLimit: tx.Gas(),
+		MaxCost:  tx.Cost().String(),
+	}
+	if tx.To() != nil {
+		r.To = tx.To().Hex()
+	}
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		r.GasPrice = tx.GasPrice().String()
+	} else {
+		r.MaxFeePerGas = tx.GasFeeCap().String()
+		r.MaxPriorityFeePerGas = tx.GasTipCap().String()
+	}
+	return r
+}
+
+// setRaw 把报告标记为预演并附上已签名交易的编码
+func (r *txReport) setRaw(tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("encode transaction: %w", err)
+	}
+	r.DryRun, r.Raw = true, hexutil.Encode(raw)
+	return nil
+}
+
+// setReceipt 填写收据中的区块、状态和实际费用
+func (r *txReport) setReceipt(receipt *types.Receipt) {
+	r.Block = receipt.BlockNumber.Uint64()
+	r.Status = "success"
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		r.Status = "reverted"
+	}
+	r.GasUsed = receipt.GasUsed
+	if receipt.EffectiveGasPrice != nil {
+		r.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+		r.Fee = new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)).String()
+	}
+}
+
+// setIncrement 填写 Counter 调用前后的计数和收据中的事件
+func (r *txReport) setIncrement(result *counterflow.Result) {
+	r.setReceipt(result.Receipt)
+	r.CountBefore, r.CountAfter = result.Before.String(), result.After.String()
+	for _, ev := range result.Events {
+		r.Events = append(r.Events, eventReport{NewValue: ev.NewValue.String(), By: ev.By.Hex()})
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/networks"
+)
+
+func TestTxReport(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	signer := types.LatestSignerForChainID(big.NewInt(11155111))
+	tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID:   big.NewInt(11155111),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1_000_000_000_000_000),
+	})
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	r := newTxReport(networks.Preset{Name: "sepolia"}, from, tx)
+	r.setReceipt(&types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		BlockNumber:       big.NewInt(123),
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(12_000_000_000),
+	})
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"network":              "sepolia",
+		"chainId":              float64(11155111),
+		"from":                 from.Hex(),
+		"to":                   to.Hex(),
+		"value":                "1000000000000000",
+		"hash":                 tx.Hash().Hex(),
+		"nonce":                float64(7),
+		"gasLimit":             float64(21000),
+		"maxFeePerGas":         "30000000000",
+		"maxPriorityFeePerGas": "1000000000",
+		"maxCost":              "1630000000000000",
+		"block":                float64(123),
+		"status":               "success",
+		"gasUsed":              float64(21000),
+		"effectiveGasPrice":    "12000000000",
+		"fee":                  "252000000000000",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+	for _, k := range []string{"gasPrice", "raw", "dryRun", "countBefore", "explorer"} {
+		if _, ok := got[k]; ok {
+			t.Errorf("unexpected field %s in %s", k, data)
+		}
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	if err := setOutput("text"); err != nil || jsonStdout != nil {
+		t.Errorf("setOutput(text) = %v, redirected %v", err, jsonStdout != nil)
+	}
+	if err := setOutput("yaml"); err == nil {
+		t.Error("setOutput(yaml) succeeded")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// rootCtx 是命令使用的根 context，收到 SIGINT 或 SIGTERM 时取消
+var rootCtx, cancelRoot = context.WithCancel(context.Background())
+
+// started 是本次运行的开始时间，中断时用来找出本次运行广播的交易
+var started = time.Now()
+
+// shutdownGrace 是第一次中断后等待命令收尾的时间，超时或再次收到信号时直接退出
+const shutdownGrace = 10 * time.Second
+
+// setupShutdown 处理 SIGINT/SIGTERM：取消 rootCtx，正在进行的 RPC 请求和等待确认随之返回，
+// 已广播的交易在交易历史中（由 RPC 层在节点接受时写入）。命令因中断失败时打印本次运行中还未确认的交易和后续操作，
+// 以 130 退出。自己处理中断的命令（watch、serve 等）正常返回时照常退出
+func setupShutdown() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		logger("main").Warn("interrupted, stopping; interrupt again to exit immediately", "signal", sig.String())
+		cancelRoot()
+		select {
+		case <-signals:
+		case <-time.After(shutdownGrace):
+			logger("main").Warn("command did not stop in time", "grace", shutdownGrace)
+		}
+		exitInterrupted()
+	}()
+
+	// 中断后 fatal 报告的错误是取消引起的，写出后按中断退出并给出恢复说明
+	log.SetOutput(interruptWriter{log.Writer()})
+}
+
+type interruptWriter struct {
+	io.Writer
+}
+
+func (w interruptWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if interrupted() {
+		exitInterrupted()
+	}
+	return n, err
+}
+
+// interrupted 报告是否收到过 SIGINT 或 SIGTERM
+func interrupted() bool {
+	return rootCtx.Err() != nil
+}
+
+var exitOnce sync.Once
+
+// exitInterrupted 打印恢复说明后以 130（128 + SIGINT）退出，多个 goroutine 同时调用时只执行一次
+func exitInterrupted() {
+	exitOnce.Do(func() {
+		printResume(stderr)
+		os.Exit(130)
+	})
+	select {}
+}
+
+// printResume 列出本次运行广播但还没有看到收据的交易：它们已经在节点的交易池中，中断不会撤回，
+// 之后仍可能被打包。没有这样的交易时不输出
+func printResume(w io.Writer) {
+	store := txHistory()
+	if store == nil {
+		fmt.Fprintln(w, "Transaction history is off (TX_HISTORY=off); transactions broadcast before the interrupt may still be mined, check the account's nonce before resending.")
+		return
+	}
+	defer store.Close()
+	pending, err := store.Pending(started)
+	if err != nil {
+		logger("tx").Warn("failed to read transaction history", "err", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nInterrupted with %d unconfirmed transaction(s) broadcast by this run; they may still be mined:\n", len(pending))
+	var hashes []string
+	for _, r := range pending {
+		fmt.Fprintf(w, "  %s  nonce %d  from %s\n", r.Hash.Hex(), r.Nonce, r.From.Hex())
+		hashes = append(hashes, r.Hash.Hex())
+	}
+	fmt.Fprintln(w, "\nTo resume:")
+	fmt.Fprintf(w, "  go-eth-demo tx receipts %s\n", strings.Join(hashes, " "))
+	fmt.Fprintln(w, "      check whether they were mined (tx list shows the updated history)")
+	fmt.Fprintln(w, "  go-eth-demo tx speedup <hash>   resend with higher fees")
+	fmt.Fprintln(w, "  go-eth-demo tx cancel <hash>    replace with an empty transaction to the same nonce")
+	fmt.Fprintln(w, "Do not rerun the command before they are mined or replaced, or the transfer may be sent twice.")
+}
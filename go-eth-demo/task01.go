@@ -1,70 +1,70 @@
 package main
 
 import (
-	"context"
+	"errors"
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/joho/godotenv"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/units"
 )
 
 // 辅助函数：将 Wei 转换为 ETH (更易读)
 func weiToEth(wei *big.Int) string {
-	eth := new(big.Float).SetInt(wei)
-	eth.Quo(eth, big.NewFloat(1e18))
-	return fmt.Sprintf("%.6f", eth)
+	return units.FormatEther(wei, 6)
 }
 
 // 辅助函数：将 Wei 转换为 Gwei (Gas 价格常用)
 func weiToGwei(wei *big.Int) string {
-	gwei := new(big.Float).SetInt(wei)
-	gwei.Quo(gwei, big.NewFloat(1e9))
-	return fmt.Sprintf("%.2f", gwei)
+	return units.FormatGwei(wei, 2)
 }
 
-func task01() {
-	ctx := context.Background()
+func task01() error {
+	ctx := rootCtx
 
-	// 加载 .env 文件
-	err := godotenv.Load()
+	// 从环境变量获取配置
+	sepoliaRPC := rpcEndpoint()
+
+	w, err := loadSigner()
 	if err != nil {
-		log.Println("Warning: .env file not found, using system environment variables")
+		return err
 	}
-
-	// 从环境变量获取配置
-	sepoliaRPC := os.Getenv("SEPOLIA_RPC")
-	if sepoliaRPC == "" {
-		sepoliaRPC = "https://eth-sepolia.g.alchemy.com/v2/5kxZJaABVsl6R8LWJEcDvkapc6nwG8ik" // 默认值
+	feeStrategy, err := ethtx.ParseFeeStrategy(os.Getenv("FEE_STRATEGY"))
+	if err != nil {
+		return err
 	}
-
-	privateKeyHex := os.Getenv("PRIVATE_KEY")
-	if privateKeyHex == "" {
-		log.Fatal("PRIVATE_KEY environment variable is required")
+	// OUTPUT=json 时文字输出改到标准错误，结束时在标准输出写出一个 JSON 文档
+	if err := setOutput(os.Getenv("OUTPUT")); err != nil {
+		return err
 	}
 
 	recipientAddr := os.Getenv("RECIPIENT_ADDR")
 	if recipientAddr == "" {
-		log.Fatal("RECIPIENT_ADDR environment variable is required")
+		return errors.New("RECIPIENT_ADDR environment variable is required")
+	}
+	// 转账金额可带单位，如 "0.5 eth"、"300 gwei"、"1e15 wei"，按十进制精确换算为 wei
+	value, err := units.ParseAmount(envOr("TRANSFER_AMOUNT", "0.001 eth"))
+	if err != nil {
+		return fmt.Errorf("invalid TRANSFER_AMOUNT: %w", err)
 	}
 
-	// connect to Sepolia network
-	client, err := ethclient.DialContext(ctx, sepoliaRPC)
+	// 连接到 NETWORK 选择的网络，默认 Sepolia
+	client, err := dial(ctx, sepoliaRPC)
 	if err != nil {
-		log.Fatalf("Failed to connect to the Ethereum client: %v", err)
+		return err
 	}
 	defer client.Close()
-	fmt.Println("Connected to Sepolia network")
+	preset := presetFor(ctx, client)
+	fmt.Printf("Connected to %s network\n", preset.Name)
+	// 金额按链的原生代币显示（Polygon 上是 POL，BSC 上是 BNB）
+	symbol := preset.Currency.Symbol
+	native := func(wei *big.Int) string { return units.FormatFixed(wei, preset.Currency.Decimals, 6) }
 
 	// 首先检查连接是否正常
 	latestBlock, err := client.BlockByNumber(ctx, nil)
 	if err != nil {
-		log.Fatalf("Failed to get latest block (connection issue): %v", err)
+		return fmt.Errorf("failed to get latest block (connection issue): %w", err)
 	}
 	fmt.Printf("Latest Block Number: %d\n", latestBlock.Number().Uint64())
 
@@ -72,7 +72,7 @@ func task01() {
 	blockNumber := big.NewInt(5671744)
 	block, err := client.BlockByNumber(ctx, blockNumber)
 	if err != nil {
-		log.Fatalf("Failed to retrieve block: %v", err)
+		return fmt.Errorf("failed to retrieve block: %w", err)
 	}
 	fmt.Printf("Block Number: %d\n", block.Number().Uint64())
 	fmt.Printf("Block Hash: %s\n", block.Hash().Hex())
@@ -81,69 +81,105 @@ func task01() {
 
 	// prepare and send a transaction
 	fmt.Println("\n=== Preparing Transaction ===")
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	fromAddress := w.Address()
+	// RECIPIENT_ADDR 可以是地址簿中的名称
+	toAddress, err := resolveAddress(ctx, client, recipientAddr)
 	if err != nil {
-		log.Fatalf("Failed to parse private key: %v", err)
+		return fmt.Errorf("invalid RECIPIENT_ADDR: %w", err)
 	}
-	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
-	fmt.Printf("From Address: %s\n", fromAddress.Hex())
-	fmt.Printf("To Address: %s\n", recipientAddr)
-
-	// 检查账户余额
-	balance, err := client.BalanceAt(ctx, fromAddress, nil)
-	if err != nil {
-		log.Fatalf("Failed to get balance: %v", err)
+	// 链上有 ENS 时一次解析两个地址的主名称，之后的输出使用缓存
+	label := addressLabels(ctx, client, preset, fromAddress, toAddress)
+	fmt.Printf("From Address: %s\n", label(fromAddress))
+	fmt.Printf("To Address: %s\n", label(toAddress))
+
+	// 链支持 EIP-1559 时构造 DynamicFeeTx，否则退回传统的 gas 价格
+	transfer, err := ethtx.Prepare(ctx, client, fromAddress, toAddress, value)
+	// FEE_STRATEGY 选择 slow/fast 等策略时按该策略重新估算费用
+	if transfer != nil && feeStrategy != ethtx.StandardFees {
+		if err := transfer.SetFees(ctx, client, feeStrategy); err != nil {
+			return err
+		}
+		err = transfer.Check()
 	}
-	fmt.Printf("Account Balance: %s ETH\n", weiToEth(balance))
-
-	nonce, err := client.PendingNonceAt(ctx, fromAddress)
-	if err != nil {
-		log.Fatalf("Failed to get nonce: %v", err)
+	if transfer != nil {
+		// 在 Optimism、Base 等 L2 上总费用还包括 L1 数据费用，不计入时余额检查会低估；Arbitrum 上按 L1/L2 拆分估算 gas
+		if err := adjustForL2(ctx, client, transfer, int(envUint("GAS_BUFFER", ethtx.DefaultGasBuffer)), false); err != nil {
+			return err
+		}
+		err = transfer.Check()
+		// 检查账户余额并计算总费用 (包括gas费)，设置了 FIAT 时同时显示法币价值
+		fiat := fiatValues(ctx, os.Getenv("FIAT"), preset.Currency)
+		fmt.Printf("Account Balance: %s %s%s\n", native(transfer.Balance), symbol, fiat(transfer.Balance))
+		fmt.Printf("Nonce: %d\n", transfer.Nonce)
+		fmt.Printf("Transfer Amount: %s %s%s\n", units.FormatUnits(value, preset.Currency.Decimals), symbol, fiat(value))
+		printTransferFees(transfer)
+		fmt.Printf("Gas Limit: %d\n", transfer.GasLimit)
+		fmt.Printf("Total Cost (including gas): %s %s%s\n", native(transfer.Cost()), symbol, fiat(transfer.Cost()))
+	}
+	if errors.Is(err, ethtx.ErrInsufficientFunds) {
+		return fmt.Errorf("%w: need %s %s but only have %s %s", ethtx.ErrInsufficientFunds,
+			native(transfer.Cost()), symbol, native(transfer.Balance), symbol)
 	}
-	fmt.Printf("Nonce: %d\n", nonce)
-	value := big.NewInt(1e15) // 0.001 ETH
-	gasLimit := uint64(21000) // standard gas limit for ETH transfer
-	gasPrice, err := client.SuggestGasPrice(ctx)
 	if err != nil {
-		log.Fatalf("Failed to suggest gas price: %v", err)
+		return err
 	}
 
-	fmt.Printf("Transfer Amount: %s ETH\n", weiToEth(value))
-	fmt.Printf("Gas Price: %s Gwei\n", weiToGwei(gasPrice))
-	fmt.Printf("Gas Limit: %d\n", gasLimit)
-
-	// 计算总费用 (包括gas费)
-	totalCost := new(big.Int).Add(value, new(big.Int).Mul(gasPrice, big.NewInt(int64(gasLimit))))
-	fmt.Printf("Total Cost (including gas): %s ETH\n", weiToEth(totalCost))
-
-	// 检查余额是否足够
-	if balance.Cmp(totalCost) < 0 {
-		log.Fatalf("Insufficient balance! Need %s ETH but only have %s ETH",
-			weiToEth(totalCost), weiToEth(balance))
+	// 设置了 DRY_RUN 时只签名，不广播
+	if envBool("DRY_RUN") {
+		signedTx, err := ethtx.Sign(w, transfer)
+		if err == nil {
+			err = printDryRun(signedTx)
+		}
+		if err != nil {
+			return err
+		}
+		report := newTxReport(preset, fromAddress, signedTx)
+		report.BalanceBefore = transfer.Balance.String()
+		if err := report.setRaw(signedTx); err != nil {
+			return err
+		}
+		if err := emit(report); err != nil {
+			return err
+		}
+		return nil
 	}
 
-	toAddress := common.HexToAddress(recipientAddr)
-	tx := types.NewTransaction(nonce, toAddress, value, gasLimit, gasPrice, nil)
-	chainID, err := client.NetworkID(ctx)
-	if err != nil {
-		log.Fatalf("Failed to get network ID: %v", err)
-	}
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+	signedTx, err := ethtx.Send(ctx, client, w, transfer)
 	if err != nil {
-		log.Fatalf("Failed to sign transaction: %v", err)
-	}
-	err = client.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Fatalf("Failed to send transaction: %v", err)
+		return err
 	}
 
 	fmt.Println("\n=== Transaction Sent Successfully ===")
 	fmt.Printf("Transaction Hash: %s\n", signedTx.Hash().Hex())
-	fmt.Printf("View on Etherscan: https://sepolia.etherscan.io/tx/%s\n", signedTx.Hash().Hex())
-	fmt.Printf("From: %s\n", fromAddress.Hex())
-	fmt.Printf("To: %s\n", toAddress.Hex())
-	fmt.Printf("Amount: %s ETH\n", weiToEth(value))
-	fmt.Printf("Gas Price: %s Gwei\n", weiToGwei(gasPrice))
-	fmt.Println("\nNote: It may take 15-30 seconds for the transaction to be confirmed on the network.")
-	fmt.Println("Check the Etherscan link above to monitor the transaction status.")
+	if url := preset.TxURL(signedTx.Hash()); url != "" {
+		fmt.Printf("View on Explorer: %s\n", url)
+	}
+	fmt.Printf("From: %s\n", label(fromAddress))
+	fmt.Printf("To: %s\n", label(toAddress))
+	fmt.Printf("Amount: %s %s\n", native(value), symbol)
+	printTransferFees(transfer)
+	report := newTxReport(preset, fromAddress, signedTx)
+	report.BalanceBefore = transfer.Balance.String()
+
+	// 设置了 WAIT_CONFIRMATIONS 或 WAIT_FINALITY 时等待确认，否则立即返回
+	if wf := waitFromEnv(); wf.enabled() {
+		fmt.Println("\n=== Waiting for Confirmation ===")
+		receipt, err := wf.wait(ctx, client, signedTx)
+		if err != nil {
+			return err
+		}
+		report.setReceipt(receipt)
+		balanceAfter, err := client.BalanceAt(ctx, fromAddress, receipt.BlockNumber)
+		if err != nil {
+			return err
+		}
+		report.BalanceAfter = balanceAfter.String()
+	} else {
+		fmt.Println("\nNote: It may take 15-30 seconds for the transaction to be confirmed on the network.")
+		fmt.Println("Check the Etherscan link above to monitor the transaction status.")
+	}
+	if err := emit(report); err != nil {
+		return err
+	}
+	return nil
 }
@@ -2,132 +2,222 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
 	"os"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/joho/godotenv"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/local/go-eth-demo/go-eth-demo/counter"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/counterflow"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/wallet"
+	"github.com/local/go-eth-demo/pkg/watch"
 )
 
-func task02() {
-	ctx := context.Background()
-	err := godotenv.Load()
+func task02() error {
+	ctx := rootCtx
+	// 从环境变量获取配置
+	rpcURL := rpcEndpoint()
+	w, err := loadSigner()
 	if err != nil {
-		log.Println("Warning: .env file not found, using system environment variables")
+		return err
 	}
-	// 从环境变量获取配置
-	rpcURL := os.Getenv("RPC_URL")
-	if rpcURL == "" {
-		rpcURL = "https://eth-sepolia.g.alchemy.com/v2/5kxZJaABVsl6R8LWJEcDvkapc6nwG8ik" // 默认值
+	feeStrategy, err := ethtx.ParseFeeStrategy(os.Getenv("FEE_STRATEGY"))
+	if err != nil {
+		return err
 	}
-	privateKeyHex := os.Getenv("PRIVATE_KEY")
-	if privateKeyHex == "" {
-		log.Fatal("PRIVATE_KEY environment variable is required")
+	if err := setOutput(os.Getenv("OUTPUT")); err != nil {
+		return err
 	}
 	recipientAddr := os.Getenv("RECIPIENT_ADDR")
 	if recipientAddr == "" {
-		log.Fatal("RECIPIENT_ADDR environment variable is required")
+		return errors.New("RECIPIENT_ADDR environment variable is required")
 	}
 	contractAddr := os.Getenv("CONTRACT_ADDR")
 	if contractAddr == "" {
-		log.Fatal("CONTRACT_ADDR environment variable is required")
+		return errors.New("CONTRACT_ADDR environment variable is required")
 	}
-	// 连接到以太坊客户端
-	client, err := ethclient.DialContext(ctx, rpcURL)
+	recipientAddress, err := address.ParseHex(recipientAddr)
 	if err != nil {
-		log.Fatalf("Failed to connect to the Ethereum client: %v", err)
+		return fmt.Errorf("invalid RECIPIENT_ADDR: %w", err)
 	}
-	defer client.Close()
-	log.Println("Connected to Sepolia successfully")
-	// 加载私钥
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	contractAddress, err := address.ParseHex(contractAddr)
 	if err != nil {
-		log.Fatalf("Failed to parse private key: %v", err)
+		return fmt.Errorf("invalid CONTRACT_ADDR: %w", err)
 	}
-	log.Println("Private key loaded successfully")
-	// 获取网络 ID
-	chainID, err := client.NetworkID(ctx)
+	// 连接到以太坊客户端
+	client, err := dial(ctx, rpcURL)
 	if err != nil {
-		log.Fatalf("Failed to get network ID: %v", err)
+		return err
 	}
-	log.Printf("Connected to Sepolia network: %s", chainID.String())
-	log.Println("Recipient address:", recipientAddr)
-	log.Println("Contract address:", contractAddr)
-	// 创建授权的交易发送者
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	defer client.Close()
+	preset := presetFor(ctx, client)
+	logger("rpc").Info("connected", "network", preset.Name)
+	logger("wallet").Info("signer loaded", "address", w.Address().Hex())
+	// EIP-155 签名使用 eth_chainId，它在某些链上与 net_version 返回的网络 ID 不同；dial 已核对过链配置
+	chainID, err := client.ChainID(ctx)
 	if err != nil {
-		log.Fatalf("Failed to create authorized transactor: %v", err)
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	logger("rpc").Debug("chain ID", "network", preset.Name, "id", chainID)
+	fmt.Printf("Connected to %s network: %s\n", preset.Name, chainID)
+	fmt.Println("Recipient address:", recipientAddress.Hex())
+	fmt.Println("Contract address:", contractAddress.Hex())
+	// 创建授权的交易发送者
+	auth := wallet.NewTransactor(w, chainID)
+	if err := applyFeeStrategy(ctx, client, auth, feeStrategy); err != nil {
+		return fmt.Errorf("failed to suggest fees: %w", err)
 	}
-	log.Println("Authorized transactor created successfully")
+	logger("wallet").Debug("transactor created", "from", auth.From.Hex(), "chainId", chainID)
 	// 创建合约实例
-	address := common.HexToAddress(contractAddr)
-	contract, err := counter.NewCounter(address, client)
+	contract, err := counter.NewCounter(contractAddress, client)
 	if err != nil {
-		log.Fatalf("Failed to create contract instance: %v", err)
+		return fmt.Errorf("failed to create contract instance: %w", err)
 	}
-	log.Println("Contract instance created successfully")
+	logger("tx").Debug("contract bound", "address", contractAddress.Hex())
 
-	// 先查询当前值（交易前）
-	countBefore, err := contract.GetCount(&bind.CallOpts{Context: ctx})
-	if err != nil {
-		log.Fatalf("Failed to get counter value before increment: %v", err)
+	// 模拟调用并估算 gas（加上 GAS_BUFFER 的余量），会回滚时不发送交易
+	if err := prepareIncrement(ctx, client, contractAddress, auth, int(envUint("GAS_BUFFER", ethtx.DefaultGasBuffer))); err != nil {
+		return fmt.Errorf("counter increment would fail: %w", err)
 	}
-	log.Printf("Counter value BEFORE increment: %d", countBefore)
-	// 发送交易以递增计数器
-	tx, err := contract.Increment(auth)
-	if err != nil {
-		log.Fatalf("Failed to increment counter: %v", err)
+	fmt.Printf("Estimated gas limit: %d\n", auth.GasLimit)
+
+	// 设置了 DRY_RUN 时只签名，不广播
+	if envBool("DRY_RUN") {
+		tx, err := dryRunIncrement(client, contractAddress, auth)
+		if err != nil {
+			return err
+		}
+		report := newTxReport(preset, auth.From, tx)
+		if err := report.setRaw(tx); err != nil {
+			return err
+		}
+		if err := emit(report); err != nil {
+			return err
+		}
+		return nil
 	}
-	log.Printf("Counter increment transaction sent: %s", tx.Hash().Hex())
-	log.Println("Waiting for transaction to be confirmed...")
 
-	// 等待交易确认
-	receipt, err := bind.WaitMined(ctx, client, tx)
-	if err != nil {
-		log.Fatalf("Failed to wait for transaction confirmation: %v", err)
+	// 设置了 WS_RPC 时在后台订阅 CountIncremented 事件，交易执行期间实时显示
+	var live <-chan common.Hash
+	if wsURL := os.Getenv("WS_RPC"); wsURL != "" {
+		watchCtx, stopWatch := context.WithCancel(ctx)
+		defer stopWatch()
+		if live, err = watchCounterEvents(watchCtx, wsURL, contractAddress, contract); err != nil {
+			return err
+		}
+		logger("rpc").Info("subscribed to CountIncremented events", "endpoint", endpointHosts(wsURL))
 	}
 
-	if receipt.Status == 1 {
-		log.Printf("Transaction confirmed successfully in block: %d", receipt.BlockNumber.Uint64())
-		log.Printf("Gas used: %d", receipt.GasUsed)
-	} else {
-		log.Fatalf("Transaction failed with status: %d", receipt.Status)
+	// 查询当前值、发送递增交易并等待确认
+	fmt.Println("Sending increment transaction and waiting for confirmation...")
+	result, err := counterflow.Increment(ctx, client, contractAddress, auth)
+	if err != nil {
+		return fmt.Errorf("counter increment failed: %w", err)
+	}
+	logger("tx").Debug("increment confirmed", "hash", result.Tx.Hash().Hex(), "block", result.Receipt.BlockNumber, "gasUsed", result.Receipt.GasUsed)
+	fmt.Printf("Counter value BEFORE increment: %d\n", result.Before)
+	fmt.Printf("Counter increment transaction sent: %s\n", result.Tx.Hash().Hex())
+	fmt.Printf("Transaction confirmed successfully in block: %d\n", result.Receipt.BlockNumber.Uint64())
+	fmt.Printf("Gas used: %d\n", result.Receipt.GasUsed)
+	fmt.Printf("Current counter value after confirmation: %d\n", result.After)
+	for _, ev := range result.Events {
+		fmt.Printf("Event in receipt: CountIncremented(newValue=%d, by=%s)\n", ev.NewValue, ev.By.Hex())
+	}
+	if live != nil {
+		waitLiveEvent(live, result.Tx.Hash(), 10*time.Second)
 	}
 
-	// 等待一点时间让状态同步
-	log.Println("Waiting for state synchronization...")
-	time.Sleep(2 * time.Second)
+	report := newTxReport(preset, auth.From, result.Tx)
+	report.setIncrement(result)
 
-	// 现在查询计数器值（交易已确认）
-	count, err := contract.GetCount(&bind.CallOpts{Context: ctx})
-	if err != nil {
-		log.Fatalf("Failed to get counter value: %v", err)
+	// 设置了 WAIT_CONFIRMATIONS 或 WAIT_FINALITY 时继续等待确认
+	if wf := waitFromEnv(); wf.enabled() {
+		receipt, err := wf.wait(ctx, client, result.Tx)
+		if err != nil {
+			return err
+		}
+		report.setReceipt(receipt)
 	}
-	log.Printf("Current counter value after confirmation: %d", count)
 
 	// 验证是否真的递增了
-	if count.Cmp(countBefore) > 0 {
-		log.Printf("✅ SUCCESS: Counter incremented from %d to %d", countBefore, count)
+	if result.Incremented() {
+		fmt.Printf("✅ SUCCESS: Counter incremented from %d to %d\n", result.Before, result.After)
 	} else {
-		log.Printf("❌ WARNING: Counter did not increment! Before: %d, After: %d", countBefore, count)
-		log.Printf("Check transaction details on Etherscan: https://sepolia.etherscan.io/tx/%s", tx.Hash().Hex())
+		fmt.Printf("❌ WARNING: Counter did not increment! Before: %d, After: %d\n", result.Before, result.After)
+		if url := preset.TxURL(result.Tx.Hash()); url != "" {
+			fmt.Printf("Check transaction details on the explorer: %s\n", url)
+		}
 
 		// 再次查询，使用最新区块
-		log.Println("Retrying query with latest block...")
+		fmt.Println("Retrying query with latest block...")
 		time.Sleep(1 * time.Second)
 		countRetry, err := contract.GetCount(&bind.CallOpts{
 			Context:     ctx,
 			BlockNumber: nil, // 使用最新区块
 		})
 		if err != nil {
-			log.Printf("Retry query failed: %v", err)
+			logger("rpc").Warn("retry query failed", "err", err)
 		} else {
-			log.Printf("Retry result: %d", countRetry)
+			fmt.Printf("Retry result: %d\n", countRetry)
+			report.CountAfter = countRetry.String()
+		}
+	}
+	if err := emit(report); err != nil {
+		return err
+	}
+	return nil
+}
+
+// watchCounterEvents 在后台订阅合约的 CountIncremented 事件并逐条打印，断线后自动重连并补齐，
+// 返回的通道传出收到事件的交易哈希。ctx 结束时停止订阅。
+func watchCounterEvents(ctx context.Context, wsURL string, address common.Address, contract *counter.Counter) (<-chan common.Hash, error) {
+	parsed, err := counter.CounterMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse counter ABI: %w", err)
+	}
+	q := ethereum.FilterQuery{
+		Addresses: []common.Address{address},
+		Topics:    [][]common.Hash{{parsed.Events["CountIncremented"].ID}},
+	}
+	seen := make(chan common.Hash, 16)
+	go func() {
+		err := watch.Logs(ctx, wsURL, q, watch.Options{}, func(l types.Log) error {
+			ev, err := contract.ParseCountIncremented(l)
+			if err != nil {
+				return nil
+			}
+			fmt.Printf("📡 Live event: CountIncremented(newValue=%d, by=%s) in block %d\n", ev.NewValue, ev.By.Hex(), l.BlockNumber)
+			select {
+			case seen <- l.TxHash:
+			default:
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger("rpc").Warn("event subscription stopped", "err", err)
+		}
+	}()
+	return seen, nil
+}
+
+// waitLiveEvent 等待订阅推送 txHash 产生的事件，超时只打印提示
+func waitLiveEvent(live <-chan common.Hash, txHash common.Hash, timeout time.Duration) {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case h := <-live:
+			if h == txHash {
+				return
+			}
+		case <-deadline:
+			logger("rpc").Warn("no live event received", "tx", txHash.Hex(), "timeout", timeout)
+			return
 		}
 	}
 }
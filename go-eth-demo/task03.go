@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+// task03 向 RECIPIENT_ADDR 转账 TOKEN_ADDR 代币，数量取自 TOKEN_AMOUNT（如 "12.5 USDC"），
+// 流程与 task01 的 ETH 转账相同
+func task03() error {
+	ctx := rootCtx
+
+	w, err := loadSigner()
+	if err != nil {
+		return err
+	}
+	feeStrategy, err := ethtx.ParseFeeStrategy(os.Getenv("FEE_STRATEGY"))
+	if err != nil {
+		return err
+	}
+
+	if os.Getenv("TOKEN_ADDR") == "" {
+		return errors.New("TOKEN_ADDR environment variable is required")
+	}
+	tokenAddr, err := address.ParseHex(os.Getenv("TOKEN_ADDR"))
+	if err != nil {
+		return fmt.Errorf("invalid TOKEN_ADDR: %w", err)
+	}
+	recipientAddr := os.Getenv("RECIPIENT_ADDR")
+	if recipientAddr == "" {
+		return errors.New("RECIPIENT_ADDR environment variable is required")
+	}
+	amountStr := envOr("TOKEN_AMOUNT", "1")
+
+	client, err := dial(ctx, rpcEndpoint())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	preset := presetFor(ctx, client)
+	fmt.Printf("Connected to %s network\n", preset.Name)
+
+	// 读取代币的 symbol 和 decimals，按代币精度解析数量
+	fmt.Println("\n=== Loading Token ===")
+	token, err := erc20.Load(ctx, client, tokenAddr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Token: %s\n", token.Address.Hex())
+	fmt.Printf("Symbol: %s\n", token.Symbol)
+	fmt.Printf("Decimals: %d\n", token.Decimals)
+	amount, err := token.ParseAmount(amountStr)
+	if err != nil {
+		return fmt.Errorf("invalid TOKEN_AMOUNT: %w", err)
+	}
+	tokenBalance, err := token.BalanceOf(ctx, client, w.Address())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Token Balance: %s\n", token.Format(tokenBalance))
+
+	// 构造 transfer(to, amount) 调用，gas 按该调用估算
+	fmt.Println("\n=== Preparing Token Transfer ===")
+	toAddress, err := address.ParseHex(recipientAddr)
+	if err != nil {
+		return fmt.Errorf("invalid RECIPIENT_ADDR: %w", err)
+	}
+	fmt.Printf("From Address: %s\n", w.Address().Hex())
+	fmt.Printf("To Address: %s\n", toAddress.Hex())
+	fmt.Printf("Transfer Amount: %s\n", token.Format(amount))
+	transfer, err := token.Prepare(ctx, client, w.Address(), toAddress, amount)
+	if transfer != nil && feeStrategy != ethtx.StandardFees {
+		if err := transfer.SetFees(ctx, client, feeStrategy); err != nil {
+			return err
+		}
+		err = transfer.Check()
+	}
+	if transfer != nil {
+		fmt.Printf("Account Balance: %s ETH\n", weiToEth(transfer.Balance))
+		fmt.Printf("Nonce: %d\n", transfer.Nonce)
+		printTransferFees(transfer)
+		fmt.Printf("Gas Limit: %d\n", transfer.GasLimit)
+		fmt.Printf("Max Gas Cost: %s ETH\n", weiToEth(transfer.Cost()))
+	}
+	if errors.Is(err, ethtx.ErrInsufficientFunds) {
+		return fmt.Errorf("%w for gas: need %s ETH but only have %s ETH", ethtx.ErrInsufficientFunds,
+			weiToEth(transfer.Cost()), weiToEth(transfer.Balance))
+	}
+	if err != nil {
+		return err
+	}
+
+	// 设置了 DRY_RUN 时只签名，不广播
+	if envBool("DRY_RUN") {
+		signedTx, err := ethtx.Sign(w, transfer)
+		if err == nil {
+			err = printDryRun(signedTx)
+		}
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	signedTx, err := ethtx.Send(ctx, client, w, transfer)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\n=== Token Transfer Sent Successfully ===")
+	fmt.Printf("Transaction Hash: %s\n", signedTx.Hash().Hex())
+	if url := preset.TxURL(signedTx.Hash()); url != "" {
+		fmt.Printf("View on Explorer: %s\n", url)
+	}
+
+	// 等待打包并确认余额变化，WAIT_CONFIRMATIONS/WAIT_FINALITY 可要求更多确认
+	fmt.Println("\n=== Waiting for Confirmation ===")
+	if _, err := waitFromEnv().wait(ctx, client, signedTx); err != nil {
+		return err
+	}
+	after, err := token.BalanceOf(ctx, client, w.Address())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Token Balance: %s -> %s\n", token.Format(tokenBalance), token.Format(after))
+	return nil
+}
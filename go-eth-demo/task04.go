@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/local/go-eth-demo/pkg/pricefeed"
+)
+
+// task04 读取 Chainlink 价格喂价：PRICE_FEED 是喂价地址或交易对（如 ETH/USD，按所连链查找地址），
+// 通过 abigen 绑定调用 latestRoundData，按喂价的 decimals 换算价格，并检查答案有效且在 FEED_MAX_AGE 内更新过
+func task04() error {
+	ctx := rootCtx
+
+	client, err := dial(ctx, rpcEndpoint())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	preset := presetFor(ctx, client)
+	fmt.Printf("\n=== Reading Chainlink price feed on %s ===\n", preset.Name)
+
+	feed, err := resolveFeed(ctx, client, envOr("PRICE_FEED", "ETH/USD"))
+	if err != nil {
+		return fmt.Errorf("invalid PRICE_FEED: %w", err)
+	}
+	round, err := pricefeed.Latest(ctx, client, feed)
+	if err != nil {
+		return err
+	}
+	printRound(preset, round)
+
+	// 预言机按心跳或价格偏离阈值更新答案，过期或无效的答案不能用于计算
+	if err := round.Check(time.Now(), feedMaxAge()); err != nil {
+		return err
+	}
+	fmt.Println("✅ Answer is valid and fresh")
+	return nil
+}
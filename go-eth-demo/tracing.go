@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/local/go-eth-demo/pkg/tracing"
+)
+
+// traceRoot 是当前任务或命令的根 span，未启用追踪时为 nil
+var traceRoot *tracing.Span
+
+// setupTracing 在设置了 OTEL_EXPORTER_OTLP_ENDPOINT 时启用追踪：每个任务或命令是一个 trace，
+// 交易的各个步骤和每个 RPC 请求是其中的 span，结束时通过 OTLP/HTTP JSON 导出。
+// 服务名取自 OTEL_SERVICE_NAME，托管服务的认证头取自 OTEL_EXPORTER_OTLP_HEADERS
+func setupTracing() error {
+	endpoint := envOr("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", envOr("OTEL_EXPORTER_OTLP_ENDPOINT", ""))
+	if endpoint == "" {
+		return nil
+	}
+	endpoint = strings.TrimSuffix(strings.TrimRight(endpoint, "/"), "/v1/traces")
+	headers, err := tracing.ParseHeaders(envOr("OTEL_EXPORTER_OTLP_HEADERS", ""))
+	if err != nil {
+		return err
+	}
+	t := tracing.New(&tracing.OTLP{Endpoint: endpoint, Headers: headers, ServiceName: envOr("OTEL_SERVICE_NAME", "go-eth-demo")})
+	t.OnError = func(err error) {
+		logger("trace").Warn("failed to export trace", "endpoint", endpointHosts(endpoint), "err", err)
+	}
+	tracing.SetDefault(t)
+	logger("trace").Debug("tracing enabled", "endpoint", endpointHosts(endpoint))
+
+	// 标准 log 包只剩 fatal 在用：退出前把错误记到根 span 上并导出，失败的任务同样留下 trace
+	log.SetOutput(fatalWriter{log.Writer()})
+	return nil
+}
+
+type fatalWriter struct {
+	io.Writer
+}
+
+func (w fatalWriter) Write(p []byte) (int, error) {
+	traceRoot.End(errors.New(strings.TrimSpace(string(p))))
+	return w.Writer.Write(p)
+}
+
+// traced 在名为 name 的根 span 中运行任务
+func traced(name string, run func()) {
+	traceRoot = tracing.StartRoot(name)
+	run()
+	traceRoot.End(nil)
+}
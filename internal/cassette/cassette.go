@@ -0,0 +1,282 @@
+// Package cassette 提供录制/回放 JSON-RPC 交互的 HTTP transport（类似 VCR）：第一次运行时把
+// 请求和响应录制到 testdata/cassettes 下的文件，之后在没有网络的情况下按请求内容回放，
+// 使依赖节点数据的功能可以进行确定性的单元测试。运行 `go test -record` 重新录制。
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var record = flag.Bool("record", false, "re-record RPC cassettes in testdata/cassettes/")
+
+// Mode 决定 Recorder 是转发并录制，还是只从文件回放
+type Mode int
+
+const (
+	Replay Mode = iota
+	Record
+)
+
+// Interaction 是一次 HTTP JSON-RPC 往返（单个请求或批量请求）
+type Interaction struct {
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response"`
+}
+
+// Recorder 是录制或回放 JSON-RPC 交互的 http.RoundTripper。文件中不保存 URL 和请求头，
+// 因为其中通常包含 API key。
+type Recorder struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New 创建 Recorder。回放模式下读取 path；录制模式下把请求转发给 next（nil 表示
+// http.DefaultTransport），并在 Save 时写入 path。
+func New(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, next: next, interactions: []Interaction{}}
+	if mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+		}
+		// 文件中的请求是缩进格式，重新编码后才能与 normalize 的结果逐字节比较
+		for i := range r.interactions {
+			if r.interactions[i].Request, err = normalize(r.interactions[i].Request); err != nil {
+				return nil, fmt.Errorf("parse cassette %s: interaction %d: %w", path, i, err)
+			}
+		}
+		r.used = make([]bool, len(r.interactions))
+	}
+	return r, nil
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	key, err := normalize(body)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: request is not JSON-RPC: %w", err)
+	}
+
+	if r.mode == Record {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		resp, err := r.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.mu.Lock()
+		r.interactions = append(r.interactions, Interaction{Request: key, Response: inRequestOrder(body, bytes.TrimSpace(respBody))})
+		r.mu.Unlock()
+		return response(req, resp.StatusCode, respBody), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || !bytes.Equal(in.Request, key) {
+			continue
+		}
+		r.used[i] = true
+		respBody, err := withIDs(in.Response, body)
+		if err != nil {
+			return nil, err
+		}
+		return response(req, http.StatusOK, respBody), nil
+	}
+	return nil, fmt.Errorf("cassette %s: no recorded interaction for request %s (run with -record to re-record)", r.path, key)
+}
+
+// Save 把录制的交互写入文件，回放模式下不做任何事
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// Unused 返回回放模式下没有被请求过的交互数量，可用于检查测试是否按预期发出了所有请求
+func (r *Recorder) Unused() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, used := range r.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+// Dial 返回通过 cassette 访问节点的 RPC 客户端，cassette 文件为 testdata/cassettes/<name>.json。
+// 文件存在时回放；文件不存在或使用 -record 时从 url 录制，url 为空则跳过测试。
+func Dial(t testing.TB, name, url string) *rpc.Client {
+	t.Helper()
+	path := filepath.Join("testdata", "cassettes", name+".json")
+	mode := Replay
+	if _, err := os.Stat(path); *record || errors.Is(err, os.ErrNotExist) {
+		if url == "" {
+			t.Skipf("cassette %s not recorded and no RPC URL to record from", path)
+		}
+		mode = Record
+	}
+	rec, err := New(path, mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode == Replay {
+		// 回放时 URL 不会被访问，使用一个固定的占位地址
+		url = "http://cassette.invalid"
+	}
+	client, err := rpc.DialOptions(t.Context(), url, rpc.WithHTTPClient(&http.Client{Transport: rec}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		if err := rec.Save(); err != nil {
+			t.Errorf("save cassette: %v", err)
+		}
+	})
+	return client
+}
+
+// normalize 去掉请求中的 id 字段并重新编码，使同一查询总能匹配同一录制，与请求顺序无关
+func normalize(body []byte) (json.RawMessage, error) {
+	var batch []map[string]json.RawMessage
+	if err := json.Unmarshal(body, &batch); err == nil {
+		for _, msg := range batch {
+			delete(msg, "id")
+		}
+		return json.Marshal(batch)
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+	delete(msg, "id")
+	return json.Marshal(msg)
+}
+
+// withIDs 把录制响应中的 id 替换为本次请求的 id。批量响应按位置对应。
+func withIDs(recorded json.RawMessage, request []byte) ([]byte, error) {
+	var reqBatch []struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(request, &reqBatch) == nil {
+		var respBatch []map[string]json.RawMessage
+		if err := json.Unmarshal(recorded, &respBatch); err != nil {
+			return nil, fmt.Errorf("cassette: recorded response is not a batch: %w", err)
+		}
+		if len(respBatch) != len(reqBatch) {
+			return nil, fmt.Errorf("cassette: recorded batch has %d responses for %d requests", len(respBatch), len(reqBatch))
+		}
+		for i := range respBatch {
+			respBatch[i]["id"] = reqBatch[i].ID
+		}
+		return json.Marshal(respBatch)
+	}
+	var req struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(request, &req); err != nil {
+		return nil, err
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(recorded, &resp); err != nil {
+		return nil, fmt.Errorf("cassette: recorded response is not JSON: %w", err)
+	}
+	resp["id"] = req.ID
+	return json.Marshal(resp)
+}
+
+// inRequestOrder 把批量响应按请求顺序排列（节点可以按任意顺序返回），回放时按位置替换 id
+func inRequestOrder(request, resp []byte) json.RawMessage {
+	var reqBatch []struct {
+		ID json.RawMessage `json:"id"`
+	}
+	var respBatch []json.RawMessage
+	if json.Unmarshal(request, &reqBatch) != nil || json.Unmarshal(resp, &respBatch) != nil || len(reqBatch) != len(respBatch) {
+		return resp
+	}
+	byID := make(map[string]json.RawMessage, len(respBatch))
+	for _, msg := range respBatch {
+		var m struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if json.Unmarshal(msg, &m) != nil {
+			return resp
+		}
+		byID[string(m.ID)] = msg
+	}
+	ordered := make([]json.RawMessage, len(reqBatch))
+	for i, req := range reqBatch {
+		msg, ok := byID[string(req.ID)]
+		if !ok {
+			return resp
+		}
+		ordered[i] = msg
+	}
+	data, err := json.Marshal(ordered)
+	if err != nil {
+		return resp
+	}
+	return data
+}
+
+func response(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		Status:        http.StatusText(status),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,127 @@
+package cassette
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/internal/rpctest"
+)
+
+// fakeEth 是录制时使用的节点，记录被调用的次数以确认回放不会访问网络
+type fakeEth struct{ calls *int }
+
+func (f fakeEth) ChainId() *hexutil.Big {
+	*f.calls++
+	return (*hexutil.Big)(big.NewInt(11155111))
+}
+
+func (f fakeEth) GetBalance(addr common.Address, block string) *hexutil.Big {
+	*f.calls++
+	return (*hexutil.Big)(new(big.Int).SetBytes(addr[18:]))
+}
+
+// exercise 发出单个请求和批量请求，返回读取到的值
+func exercise(t *testing.T, client *rpc.Client) []string {
+	t.Helper()
+	ctx := context.Background()
+	ec := ethclient.NewClient(client)
+	chainID, err := ec.ChainID(ctx)
+	if err != nil {
+		t.Fatalf("chain id: %v", err)
+	}
+	balance, err := ec.BalanceAt(ctx, common.HexToAddress("0x0102"), nil)
+	if err != nil {
+		t.Fatalf("balance: %v", err)
+	}
+	results := make([]hexutil.Big, 2)
+	batch := []rpc.BatchElem{
+		{Method: "eth_getBalance", Args: []interface{}{common.HexToAddress("0x01"), "latest"}, Result: &results[0]},
+		{Method: "eth_getBalance", Args: []interface{}{common.HexToAddress("0x0203"), "latest"}, Result: &results[1]},
+	}
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			t.Fatalf("batch element: %v", elem.Error)
+		}
+	}
+	return []string{chainID.String(), balance.String(), results[0].String(), results[1].String()}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	_, node := rpctest.NewNode(t, "eth", fakeEth{&calls})
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := New(path, Record, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := exercise(t, rpctest.Dial(t, node.URL, rec))
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 4 {
+		t.Fatalf("node served %d calls while recording, want 4", calls)
+	}
+
+	// 关闭节点后回放，请求 id 与录制时不同
+	node.Close()
+	replay, err := New(path, Replay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := rpctest.Dial(t, "http://cassette.invalid", replay)
+	client.Call(new(string), "web3_clientVersion") // 让请求 id 错开
+	replayed := exercise(t, client)
+	if strings.Join(replayed, ",") != strings.Join(recorded, ",") {
+		t.Errorf("replayed %v, recorded %v", replayed, recorded)
+	}
+	if calls != 4 {
+		t.Errorf("replay reached the node (%d calls)", calls)
+	}
+	if n := replay.Unused(); n != 0 {
+		t.Errorf("%d recorded interactions unused", n)
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	rec, err := New(path, Record, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := New(path, Replay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ethclient.NewClient(rpctest.Dial(t, "http://cassette.invalid", replay)).ChainID(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Fatalf("error = %v, want no recorded interaction", err)
+	}
+}
+
+func TestNormalizeIgnoresID(t *testing.T) {
+	a, err := normalize([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := normalize([]byte(`{"id":7,"method":"eth_chainId","params":[],"jsonrpc":"2.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) != string(b) {
+		t.Errorf("normalize differs: %s vs %s", a, b)
+	}
+}
@@ -0,0 +1,133 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/internal/rpctest"
+)
+
+type fakeEth struct{}
+
+func (fakeEth) ChainId() *hexutil.Big { return (*hexutil.Big)(big.NewInt(11155111)) }
+
+func chainID(ctx context.Context, client *rpc.Client) error {
+	var id hexutil.Big
+	return client.CallContext(ctx, &id, "eth_chainId")
+}
+
+func TestTransportFaults(t *testing.T) {
+	_, node := rpctest.NewNode(t, "eth", fakeEth{})
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr func(error) bool
+	}{
+		{"none", Config{}, func(err error) bool { return err == nil }},
+		{"rate limit", Config{RateLimitRate: 1}, func(err error) bool {
+			var httpErr rpc.HTTPError
+			return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests
+		}},
+		{"bad gateway", Config{ErrorRate: 1}, func(err error) bool {
+			var httpErr rpc.HTTPError
+			return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusBadGateway
+		}},
+		{"malformed", Config{MalformedRate: 1}, func(err error) bool {
+			return err != nil && !strings.Contains(err.Error(), "429")
+		}},
+		{"timeout", Config{TimeoutRate: 1, Timeout: 10 * time.Millisecond}, func(err error) bool {
+			var netErr net.Error
+			return errors.As(err, &netErr) && netErr.Timeout()
+		}},
+		{"context deadline", Config{TimeoutRate: 1}, func(err error) bool {
+			return errors.Is(err, context.DeadlineExceeded)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := chainID(ctx, rpctest.Dial(t, node.URL, NewTransport(tt.cfg, nil)))
+			if !tt.wantErr(err) {
+				t.Fatalf("unexpected result: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransportRatesAreSeeded(t *testing.T) {
+	_, node := rpctest.NewNode(t, "eth", fakeEth{})
+	cfg := Config{Seed: 42, RateLimitRate: 0.2, ErrorRate: 0.1}
+	run := func() (Stats, int) {
+		transport := NewTransport(cfg, nil)
+		client := rpctest.Dial(t, node.URL, transport)
+		failed := 0
+		for range 200 {
+			if chainID(context.Background(), client) != nil {
+				failed++
+			}
+		}
+		return transport.Stats(), failed
+	}
+	first, failed := run()
+	if first.Requests != 200 || failed != first.RateLimited+first.Errors {
+		t.Fatalf("stats %+v do not match %d failures", first, failed)
+	}
+	if first.RateLimited < 20 || first.RateLimited > 60 || first.Errors < 5 || first.Errors > 40 {
+		t.Errorf("fault counts %+v far from configured rates", first)
+	}
+	if second, _ := run(); second != first {
+		t.Errorf("same seed produced different faults: %+v vs %+v", first, second)
+	}
+}
+
+func TestProxyDrop(t *testing.T) {
+	server, _ := rpctest.NewNode(t, "eth", fakeEth{})
+	ws := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer ws.Close()
+
+	proxy, err := NewProxy(strings.TrimPrefix(ws.URL, "http://"), 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+
+	ctx := context.Background()
+	client, err := rpc.DialContext(ctx, "ws://"+proxy.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := chainID(ctx, client); err != nil {
+		t.Fatalf("call through proxy: %v", err)
+	}
+
+	proxy.DropAll()
+	if proxy.Drops() != 1 {
+		t.Errorf("Drops = %d, want 1", proxy.Drops())
+	}
+	callCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := chainID(callCtx, client); err == nil {
+		t.Error("call on dropped connection succeeded")
+	}
+
+	// 重新连接仍然可以通过代理
+	again, err := rpc.DialContext(ctx, "ws://"+proxy.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if err := chainID(ctx, again); err != nil {
+		t.Fatalf("call after reconnect: %v", err)
+	}
+}
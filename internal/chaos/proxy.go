@@ -0,0 +1,115 @@
+package chaos
+
+import (
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// Proxy 是把本地端口转发到 target 的 TCP 代理，可以主动或随机地断开连接，
+// 用于模拟 WebSocket 订阅中途断线
+type Proxy struct {
+	target string
+	ln     net.Listener
+
+	maxLifetime time.Duration
+	mu          sync.Mutex
+	rng         *rand.Rand
+	conns       map[net.Conn]struct{}
+	drops       int
+}
+
+// NewProxy 在 127.0.0.1 的随机端口上启动代理。maxLifetime 大于 0 时，每个连接在
+// [0, maxLifetime) 内的随机时间后被断开。
+func NewProxy(target string, seed int64, maxLifetime time.Duration) (*Proxy, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &Proxy{
+		target:      target,
+		ln:          ln,
+		maxLifetime: maxLifetime,
+		rng:         rand.New(rand.NewSource(seed)),
+		conns:       make(map[net.Conn]struct{}),
+	}
+	go p.serve()
+	return p, nil
+}
+
+// Addr 返回代理监听的地址（host:port）
+func (p *Proxy) Addr() string { return p.ln.Addr().String() }
+
+// Drops 返回被代理断开的连接数
+func (p *Proxy) Drops() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.drops
+}
+
+// DropAll 立即断开所有活动连接，新的连接仍然可以建立
+func (p *Proxy) DropAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for c := range p.conns {
+		c.Close()
+		delete(p.conns, c)
+		p.drops++
+	}
+}
+
+// Close 停止监听并断开所有连接
+func (p *Proxy) Close() error {
+	err := p.ln.Close()
+	p.DropAll()
+	return err
+}
+
+func (p *Proxy) serve() {
+	for {
+		client, err := p.ln.Accept()
+		if err != nil {
+			return
+		}
+		upstream, err := net.Dial("tcp", p.target)
+		if err != nil {
+			client.Close()
+			continue
+		}
+		p.mu.Lock()
+		p.conns[client] = struct{}{}
+		var lifetime time.Duration
+		if p.maxLifetime > 0 {
+			lifetime = time.Duration(p.rng.Int63n(int64(p.maxLifetime)))
+		}
+		p.mu.Unlock()
+		if lifetime > 0 {
+			time.AfterFunc(lifetime, func() { p.drop(client) })
+		}
+
+		go func() {
+			io.Copy(upstream, client)
+			upstream.Close()
+		}()
+		go func() {
+			io.Copy(client, upstream)
+			client.Close()
+			p.mu.Lock()
+			delete(p.conns, client)
+			p.mu.Unlock()
+		}()
+	}
+}
+
+// drop 断开一个仍然活动的连接
+func (p *Proxy) drop(c net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.conns[c]; ok {
+		c.Close()
+		delete(p.conns, c)
+		p.drops++
+	}
+}
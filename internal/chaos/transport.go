@@ -0,0 +1,177 @@
+// Package chaos 为测试提供故障注入：Transport 按配置的概率让 HTTP JSON-RPC 请求超时、
+// 返回 429、返回损坏的响应或 5xx 错误；Proxy 是可以随时断开连接的 TCP 代理，用于模拟
+// WebSocket 连接中途断开。随机数使用固定种子，同样的配置产生同样的故障序列。
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Config 是各类故障的发生概率（0 到 1，总和不超过 1）
+type Config struct {
+	Seed int64
+
+	TimeoutRate   float64 // 请求挂起直到 context 结束，或等待 Timeout 后返回超时错误
+	RateLimitRate float64 // 返回 429 Too Many Requests
+	MalformedRate float64 // 转发请求，但把响应截断为无效的 JSON
+	ErrorRate     float64 // 返回 502 Bad Gateway
+
+	Timeout time.Duration // 模拟超时的等待时间，0 表示一直等到请求的 context 结束
+	Latency time.Duration // 每个请求额外增加的延迟
+}
+
+// Stats 统计注入的故障
+type Stats struct {
+	Requests    int
+	Timeouts    int
+	RateLimited int
+	Malformed   int
+	Errors      int
+}
+
+// Transport 是注入故障的 http.RoundTripper
+type Transport struct {
+	cfg  Config
+	next http.RoundTripper
+
+	mu    sync.Mutex
+	rng   *rand.Rand
+	stats Stats
+}
+
+// NewTransport 创建 Transport，正常的请求转发给 next（nil 表示 http.DefaultTransport）
+func NewTransport(cfg Config, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{cfg: cfg, next: next, rng: rand.New(rand.NewSource(cfg.Seed))}
+}
+
+// Stats 返回到目前为止注入的故障统计
+func (t *Transport) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+type fault int
+
+const (
+	none fault = iota
+	timeout
+	rateLimit
+	malformed
+	badGateway
+)
+
+// roll 按概率选择本次请求的故障并更新统计
+func (t *Transport) roll() fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stats.Requests++
+	r := t.rng.Float64()
+	for _, f := range []struct {
+		rate  float64
+		fault fault
+		count *int
+	}{
+		{t.cfg.TimeoutRate, timeout, &t.stats.Timeouts},
+		{t.cfg.RateLimitRate, rateLimit, &t.stats.RateLimited},
+		{t.cfg.MalformedRate, malformed, &t.stats.Malformed},
+		{t.cfg.ErrorRate, badGateway, &t.stats.Errors},
+	} {
+		if r < f.rate {
+			*f.count++
+			return f.fault
+		}
+		r -= f.rate
+	}
+	return none
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cfg.Latency > 0 {
+		if err := sleep(req.Context(), t.cfg.Latency); err != nil {
+			return nil, err
+		}
+	}
+
+	switch t.roll() {
+	case timeout:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		if t.cfg.Timeout == 0 {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		if err := sleep(req.Context(), t.cfg.Timeout); err != nil {
+			return nil, err
+		}
+		return nil, &timeoutError{after: t.cfg.Timeout}
+	case rateLimit:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		resp := response(req, http.StatusTooManyRequests, []byte("Too Many Requests"))
+		resp.Header.Set("Retry-After", "1")
+		return resp, nil
+	case badGateway:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return response(req, http.StatusBadGateway, []byte("Bad Gateway")), nil
+	case malformed:
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return response(req, resp.StatusCode, body[:len(body)/2]), nil
+	}
+	return t.next.RoundTrip(req)
+}
+
+// timeoutError 模拟网络超时，实现 net.Error
+type timeoutError struct{ after time.Duration }
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("chaos: request timed out after %s", e.after)
+}
+func (e *timeoutError) Timeout() bool   { return true }
+func (e *timeoutError) Temporary() bool { return true }
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+func response(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,143 @@
+// Package devnettest 为集成测试准备本地 Anvil 节点：设置 DEVNET_RPC_URL 时连接已有的 anvil 或 hardhat 节点，
+// 否则在临时目录中启动一个新的 anvil（PATH 中没有 anvil 时跳过测试）。提供充值、快照和部署 Counter 的辅助函数，
+// 使任务可以在没有测试币的情况下端到端运行。
+package devnettest
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/counterflow"
+	"github.com/local/go-eth-demo/pkg/devnet"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+// startTimeout 是启动或连接节点的最长时间
+const startTimeout = time.Minute
+
+// Node 是一个供测试使用的本地节点
+type Node struct {
+	State  *devnet.State
+	RPC    *rpc.Client
+	Client *ethclient.Client
+}
+
+// Start 连接 DEVNET_RPC_URL 上的节点或启动一个新的 anvil，测试结束时关闭连接和自己启动的节点。
+// 连接已有节点时先保存快照、结束时回滚，使多个测试共用一个节点时互不影响
+func Start(t testing.TB) *Node {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	var st *devnet.State
+	var err error
+	if url := os.Getenv("DEVNET_RPC_URL"); url != "" {
+		if st, err = devnet.Attach(ctx, url); err != nil {
+			t.Fatalf("attach to %s: %v", url, err)
+		}
+	} else {
+		if _, err := exec.LookPath("anvil"); err != nil {
+			t.Skip("anvil not found in PATH; install Foundry or set DEVNET_RPC_URL")
+		}
+		dir := t.TempDir()
+		st, err = devnet.Up(ctx, devnet.Config{Kind: devnet.KindAnvil, Port: freePort(t), ChainID: 31337, Accounts: 3, Balance: 10000, Dir: dir})
+		if err != nil {
+			t.Fatalf("start anvil: %v", err)
+		}
+		t.Cleanup(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := devnet.Down(ctx, dir); err != nil {
+				t.Errorf("stop anvil: %v", err)
+			}
+		})
+	}
+
+	client, err := rpc.DialContext(ctx, st.RPCURL)
+	if err != nil {
+		t.Fatalf("dial %s: %v", st.RPCURL, err)
+	}
+	t.Cleanup(client.Close)
+	n := &Node{State: st, RPC: client, Client: ethclient.NewClient(client)}
+	if st.PID == 0 {
+		t.Cleanup(n.Snapshot(t))
+	}
+	return n
+}
+
+// freePort 返回一个当前空闲的本地端口
+func freePort(t testing.TB) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// ChainID 返回节点的链 ID
+func (n *Node) ChainID() *big.Int {
+	return new(big.Int).SetUint64(n.State.ChainID)
+}
+
+// SetBalance 把 addr 的余额设为 wei（anvil_setBalance），不产生交易
+func (n *Node) SetBalance(t testing.TB, addr common.Address, wei *big.Int) {
+	t.Helper()
+	if err := devnet.SetBalance(context.Background(), n.RPC, n.State.Kind, addr, wei); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// NewAccount 创建一个新账户并把余额设为 eth ETH。每个测试使用自己的账户，nonce 和余额不受其他测试影响
+func (n *Node) NewAccount(t testing.TB, eth int64) *wallet.Wallet {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := wallet.New(key)
+	n.SetBalance(t, w.Address(), new(big.Int).Mul(big.NewInt(eth), big.NewInt(params.Ether)))
+	return w
+}
+
+// Snapshot 保存当前链状态（evm_snapshot），返回回滚到该状态的函数
+func (n *Node) Snapshot(t testing.TB) (revert func()) {
+	t.Helper()
+	id, err := devnet.EVMSnapshot(context.Background(), n.RPC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		t.Helper()
+		if err := devnet.EVMRevert(context.Background(), n.RPC, id); err != nil {
+			t.Errorf("revert devnet snapshot: %v", err)
+		}
+	}
+}
+
+// DeployCounter 以 w 的身份部署一个新的 Counter 合约并等待部署完成
+func (n *Node) DeployCounter(t testing.TB, w *wallet.Wallet) common.Address {
+	t.Helper()
+	auth, err := w.Transactor(n.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	address, _, err := counterflow.Deploy(ctx, n.Client, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
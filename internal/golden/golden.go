@@ -0,0 +1,51 @@
+// Package golden 提供 golden 文件比较：测试输出与 testdata 下的 .golden 文件逐字节比较，
+// 运行 `go test ./... -update` 重新生成期望输出。
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata/")
+
+// Path 返回 name 对应的 golden 文件路径
+func Path(name string) string {
+	return filepath.Join("testdata", name+".golden")
+}
+
+// Assert 比较 got 与 golden 文件内容，-update 时改为写入
+func Assert(t testing.TB, name string, got []byte) {
+	t.Helper()
+	path := Path(name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output does not match %s (run with -update if the change is intended)\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+// AssertJSON 把 v 编码为缩进的 JSON 后与 golden 文件比较
+func AssertJSON(t testing.TB, name string, v interface{}) {
+	t.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal %s: %v", name, err)
+	}
+	Assert(t, name, append(data, '\n'))
+}
@@ -0,0 +1,45 @@
+// Package multicalltest 在 chain.ClientMock 上模拟部署在 multicall.DefaultAddress 的 Multicall3，
+// 使依赖聚合调用的库代码可以复用逐个调用的模拟合约进行测试。
+package multicalltest
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/multicall"
+)
+
+// Call3 对应 Multicall3 的 Call3 结构体，用于解码 aggregate3 的参数
+type Call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// Wrap 返回的客户端把发往 multicall.DefaultAddress 的 aggregate3 拆成逐个调用交给 inner.CallContract，
+// 返回错误的调用记为失败；其他 CallContract 和 CodeAt 原样转发。batches 记录每次 aggregate3 包含的调用数。
+func Wrap(inner chain.Client) (client *chain.ClientMock, batches *[]int) {
+	batches = new([]int)
+	return &chain.ClientMock{CodeAtFunc: inner.CodeAt, CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+		if call.To == nil || *call.To != multicall.DefaultAddress {
+			return inner.CallContract(ctx, call, blockNumber)
+		}
+		method := multicall.ABI.Methods["aggregate3"]
+		values, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		calls := *abi.ConvertType(values[0], new([]Call3)).(*[]Call3)
+		*batches = append(*batches, len(calls))
+		results := make([]multicall.Result, len(calls))
+		for i, c := range calls {
+			out, err := inner.CallContract(ctx, ethereum.CallMsg{From: multicall.DefaultAddress, To: &c.Target, Data: c.CallData}, blockNumber)
+			results[i] = multicall.Result{Success: err == nil, ReturnData: out}
+		}
+		return method.Outputs.Pack(results)
+	}}, batches
+}
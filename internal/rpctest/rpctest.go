@@ -0,0 +1,42 @@
+// Package rpctest 提供测试 JSON-RPC 传输层（录制、故障注入、重试、限流、缓存）时共用的假节点和拨号辅助函数，
+// 所有资源都注册到 t.Cleanup，测试结束时自动关闭。
+package rpctest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Serve 用 handler 启动 HTTP 假节点
+func Serve(t testing.TB, handler http.Handler) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// NewNode 把 service 注册到 namespace 命名空间（如 "eth"），返回 RPC 服务和它的 HTTP 端点
+func NewNode(t testing.TB, namespace string, service any) (*rpc.Server, *httptest.Server) {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName(namespace, service); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	return server, Serve(t, server)
+}
+
+// Dial 连接 url，所有 HTTP 请求都经过 transport
+func Dial(t testing.TB, url string, transport http.RoundTripper) *rpc.Client {
+	t.Helper()
+	client, err := rpc.DialOptions(context.Background(), url, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
@@ -0,0 +1,195 @@
+package simchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/go-eth-demo/counter"
+	"github.com/local/go-eth-demo/pkg/counterflow"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// 连续发送多笔转账不等待确认：nonce 从待处理状态递增，全部确认后余额精确等于转出金额加 gas 费
+func TestTransferPipeline(t *testing.T) {
+	c := New(t, 2)
+	ctx := testContext(t)
+	client := c.Client()
+	from, to := c.Wallets[0], c.Wallets[1].Address()
+
+	var txs []*types.Transaction
+	sent := new(big.Int)
+	for i := range 3 {
+		value := big.NewInt(int64(i+1) * 1e15)
+		tr, err := ethtx.Prepare(ctx, client, from.Address(), to, value)
+		if err != nil {
+			t.Fatalf("Prepare #%d: %v", i, err)
+		}
+		if tr.Nonce != uint64(i) || tr.GasLimit != ethtx.TransferGas || !tr.Dynamic() {
+			t.Fatalf("Prepare #%d = nonce %d gas %d dynamic %v, want nonce %d with %d gas", i, tr.Nonce, tr.GasLimit, tr.Dynamic(), i, ethtx.TransferGas)
+		}
+		tx, err := ethtx.Send(ctx, client, from, tr)
+		if err != nil {
+			t.Fatalf("Send #%d: %v", i, err)
+		}
+		if sender, err := types.Sender(types.LatestSignerForChainID(c.ChainID), tx); err != nil || sender != from.Address() {
+			t.Fatalf("Send #%d signed by %s, %v", i, sender, err)
+		}
+		txs = append(txs, tx)
+		sent.Add(sent, value)
+	}
+
+	spent := new(big.Int).Set(sent)
+	for i, tx := range txs {
+		receipt, err := ethtx.WaitConfirmed(ctx, client, tx, 2)
+		if err != nil {
+			t.Fatalf("WaitConfirmed #%d: %v", i, err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful || receipt.GasUsed != ethtx.TransferGas {
+			t.Errorf("receipt #%d: status %d gas %d", i, receipt.Status, receipt.GasUsed)
+		}
+		spent.Add(spent, new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)))
+	}
+
+	if nonce, err := client.NonceAt(ctx, from.Address(), nil); err != nil || nonce != 3 {
+		t.Errorf("sender nonce = %d, %v, want 3", nonce, err)
+	}
+	want := new(big.Int).Sub(c.Balance, spent)
+	if balance, err := client.BalanceAt(ctx, from.Address(), nil); err != nil || balance.Cmp(want) != 0 {
+		t.Errorf("sender balance = %v, %v, want %v", balance, err, want)
+	}
+	want = new(big.Int).Add(c.Balance, sent)
+	if balance, err := client.BalanceAt(ctx, to, nil); err != nil || balance.Cmp(want) != 0 {
+		t.Errorf("recipient balance = %v, %v, want %v", balance, err, want)
+	}
+}
+
+// Counter 的两条发送路径：ethtx 手工构造调用交易，以及 counterflow 通过 abigen 绑定发送；
+// 事件从收据日志和 eth_getLogs 两种方式解码
+func TestCounterPipeline(t *testing.T) {
+	c := New(t, 2)
+	ctx := testContext(t)
+	client := c.Client()
+
+	address, contract, err := counterflow.Deploy(ctx, client, c.Transactor(t, 0))
+	if err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+
+	msg, err := counterflow.IncrementCall(address, c.Wallets[0].Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := ethtx.PrepareCall(ctx, client, msg.From, address, big.NewInt(0), msg.Data)
+	if err != nil {
+		t.Fatalf("PrepareCall: %v", err)
+	}
+	// 部署用掉了 nonce 0；increment 写存储并触发事件，估算值远高于普通转账
+	if tr.Nonce != 1 || tr.GasLimit <= ethtx.TransferGas {
+		t.Errorf("PrepareCall = nonce %d gas %d, want nonce 1 and more than %d gas", tr.Nonce, tr.GasLimit, ethtx.TransferGas)
+	}
+	tx, err := ethtx.Send(ctx, client, c.Wallets[0], tr)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	receipt, err := ethtx.WaitConfirmed(ctx, client, tx, 1)
+	if err != nil {
+		t.Fatalf("WaitConfirmed: %v", err)
+	}
+	if receipt.GasUsed > tr.GasLimit || len(receipt.Logs) != 1 {
+		t.Fatalf("receipt used %d of %d gas with %d logs", receipt.GasUsed, tr.GasLimit, len(receipt.Logs))
+	}
+	ev, err := contract.ParseCountIncremented(*receipt.Logs[0])
+	if err != nil {
+		t.Fatalf("ParseCountIncremented: %v", err)
+	}
+	if ev.NewValue.Int64() != 1 || ev.By != msg.From || ev.Raw.TxHash != tx.Hash() {
+		t.Errorf("event = newValue %d by %s in %s", ev.NewValue, ev.By, ev.Raw.TxHash.Hex())
+	}
+
+	result, err := counterflow.Increment(ctx, client, address, c.Transactor(t, 1))
+	if err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	if result.Before.Int64() != 1 || result.After.Int64() != 2 || len(result.Events) != 1 || result.Events[0].By != c.Wallets[1].Address() {
+		t.Errorf("Increment = %d -> %d, events %+v", result.Before, result.After, result.Events)
+	}
+
+	events, err := counterflow.History(ctx, client, address, 0, result.Receipt.BlockNumber.Uint64(), nil)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(events) != 2 || events[0].By != c.Wallets[0].Address() || events[1].By != c.Wallets[1].Address() {
+		t.Errorf("History = %+v", events)
+	}
+	filterer, err := counter.NewCounterFilterer(address, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it, err := filterer.FilterCountIncremented(nil, []common.Address{c.Wallets[1].Address()}); err != nil || !it.Next() || it.Event.NewValue.Int64() != 2 {
+		t.Errorf("FilterCountIncremented(by account 1) found no event, %v", err)
+	}
+}
+
+// 节点拒绝和执行失败归类为 ethtx 的错误类型，命令据此决定退出码
+func TestPipelineErrors(t *testing.T) {
+	c := New(t, 1)
+	ctx := testContext(t)
+	client := c.Client()
+	w := c.Wallets[0]
+	address, _, err := counterflow.Deploy(ctx, client, c.Transactor(t, 0))
+	if err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+
+	t.Run("reverted", func(t *testing.T) {
+		// increment 不是 payable，附带 ETH 的调用在估算 gas 时就会回滚
+		msg, err := counterflow.IncrementCall(address, w.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ethtx.PrepareCall(ctx, client, w.Address(), address, big.NewInt(1), msg.Data); !errors.Is(err, ethtx.ErrReverted) {
+			t.Errorf("PrepareCall with value = %v, want ErrReverted", err)
+		}
+	})
+
+	t.Run("nonce too low", func(t *testing.T) {
+		tr, err := ethtx.Prepare(ctx, client, w.Address(), w.Address(), big.NewInt(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// nonce 0 已被部署交易使用
+		tr.Nonce = 0
+		if _, err := ethtx.Send(ctx, client, w, tr); !errors.Is(err, ethtx.ErrNonceTooLow) {
+			t.Errorf("Send with used nonce = %v, want ErrNonceTooLow", err)
+		}
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		empty := wallet.New(key)
+		tr, err := ethtx.Prepare(ctx, client, empty.Address(), w.Address(), big.NewInt(1))
+		if !errors.Is(err, ethtx.ErrInsufficientFunds) || tr == nil {
+			t.Fatalf("Prepare from empty account = %v, %v, want ErrInsufficientFunds with the transfer", tr, err)
+		}
+		// 跳过本地检查直接广播，节点同样拒绝
+		if _, err := ethtx.Send(ctx, client, empty, tr); !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			t.Errorf("Send from empty account = %v, want ErrInsufficientFunds", err)
+		}
+	})
+}
@@ -0,0 +1,275 @@
+package aa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAccount = common.HexToAddress("0xacc0000000000000000000000000000000000001")
+	recipient   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	chainID     = big.NewInt(11155111)
+)
+
+// nodeMock 模拟工厂、EntryPoint 和区块费用；deployed 控制账户是否已有代码
+func nodeMock(t *testing.T, deployed bool) *chain.ClientMock {
+	return &chain.ClientMock{
+		ChainIDFunc: func(ctx context.Context) (*big.Int, error) { return chainID, nil },
+		CallContractFunc: func(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+			switch *msg.To {
+			case SimpleAccountFactoryV07:
+				return factoryABI.Methods["getAddress"].Outputs.Pack(testAccount)
+			case EntryPointV07:
+				return entryPointABI.Methods["getNonce"].Outputs.Pack(big.NewInt(5))
+			}
+			t.Fatalf("unexpected call to %s", msg.To.Hex())
+			return nil, nil
+		},
+		CodeAtFunc: func(ctx context.Context, account common.Address, block *big.Int) ([]byte, error) {
+			if deployed {
+				return []byte{0x60}, nil
+			}
+			return nil, nil
+		},
+		SuggestGasTipCapFunc: func(ctx context.Context) (*big.Int, error) { return big.NewInt(1e9), nil },
+		HeaderByNumberFunc: func(ctx context.Context, number *big.Int) (*types.Header, error) {
+			return &types.Header{BaseFee: big.NewInt(10e9)}, nil
+		},
+	}
+}
+
+func TestBuildUserOp(t *testing.T) {
+	ctx := context.Background()
+	for _, deployed := range []bool{false, true} {
+		client := nodeMock(t, deployed)
+		a, err := NewSimpleAccount(ctx, client, testKey, big.NewInt(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		op, err := a.BuildUserOp(ctx, client, Call{To: recipient, Value: big.NewInt(1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Sender != testAccount || bigOf(op.Nonce).Int64() != 5 || bigOf(op.MaxFeePerGas).Int64() != 21e9 {
+			t.Errorf("unexpected op %+v", op)
+		}
+		// 未部署的账户通过工厂在第一笔操作中创建
+		if (op.Factory != nil) == deployed {
+			t.Errorf("deployed=%v but factory=%v", deployed, op.Factory)
+		}
+		method, err := accountABI.MethodById(op.CallData)
+		if err != nil || method.Name != "execute" {
+			t.Errorf("call data does not call execute: %v", err)
+		}
+	}
+
+	batch, err := encodeCalls([]Call{{To: recipient}, {To: recipient, Data: []byte{1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method, _ := accountABI.MethodById(batch); method.Name != "executeBatch" {
+		t.Errorf("multiple calls encoded with %s", method.Name)
+	}
+}
+
+func TestHashAndSign(t *testing.T) {
+	op := &UserOperation{
+		Sender: testAccount, Nonce: newBig(1), CallData: []byte{1, 2, 3},
+		CallGasLimit: newBig(50_000), VerificationGasLimit: newBig(100_000), PreVerificationGas: newBig(45_000),
+		MaxFeePerGas: newBig(2e9), MaxPriorityFeePerGas: newBig(1e9),
+	}
+	hash := op.Hash(EntryPointV07, chainID)
+	if hash == op.Hash(EntryPointV07, big.NewInt(1)) {
+		t.Error("hash does not depend on the chain ID")
+	}
+	op.Signature = []byte{1}
+	if op.Hash(EntryPointV07, chainID) != hash {
+		t.Error("hash depends on the signature")
+	}
+	op.CallGasLimit = newBig(50_001)
+	if op.Hash(EntryPointV07, chainID) == hash {
+		t.Error("hash does not depend on the gas limits")
+	}
+
+	a := &SimpleAccount{Owner: crypto.PubkeyToAddress(testKey.PublicKey), EntryPoint: EntryPointV07, key: testKey}
+	if err := a.Sign(op, chainID); err != nil {
+		t.Fatal(err)
+	}
+	if len(op.Signature) != 65 || op.Signature[64] < 27 {
+		t.Fatalf("bad signature %x", op.Signature)
+	}
+	if signer := recoverSigner(t, op); signer != a.Owner {
+		t.Errorf("signature recovers %s, want owner %s", signer.Hex(), a.Owner.Hex())
+	}
+}
+
+func recoverSigner(t *testing.T, op *UserOperation) common.Address {
+	t.Helper()
+	sig := common.CopyBytes(op.Signature)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(accounts.TextHash(op.Hash(EntryPointV07, chainID).Bytes()), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.PubkeyToAddress(*pub)
+}
+
+func TestPackedFields(t *testing.T) {
+	pm := common.HexToAddress("0x9a")
+	op := &UserOperation{Paymaster: &pm, PaymasterVerificationGasLimit: newBig(1), PaymasterPostOpGasLimit: newBig(2), PaymasterData: []byte{0xab}}
+	got := op.PaymasterAndData()
+	if len(got) != 20+32+1 || got[20+15] != 1 || got[20+31] != 2 || got[52] != 0xab {
+		t.Errorf("paymasterAndData = %x", got)
+	}
+	if len(dummySignature) != 65 {
+		t.Errorf("dummy signature has %d bytes, want 65", len(dummySignature))
+	}
+	// 账户已部署、没有 paymaster 时这些字段不出现在 JSON 中
+	enc, _ := json.Marshal(&UserOperation{Sender: testAccount, Nonce: newBig(0)})
+	if strings.Contains(string(enc), "factory") || strings.Contains(string(enc), "paymaster") {
+		t.Errorf("unexpected optional fields in %s", enc)
+	}
+}
+
+// fakeBundler 是进程内的 bundler，只接受所有者签名有效的操作
+type fakeBundler struct {
+	t     *testing.T
+	owner common.Address
+	sent  *UserOperation
+	polls int
+}
+
+func (f *fakeBundler) EstimateUserOperationGas(op UserOperation, entryPoint common.Address) (*GasEstimate, error) {
+	if !strings.HasPrefix(hexutil.Encode(op.Signature), "0xffffffff") {
+		return nil, fmt.Errorf("expected the dummy signature, got %s", hexutil.Encode(op.Signature))
+	}
+	return &GasEstimate{PreVerificationGas: newBig(45_000), VerificationGasLimit: newBig(300_000), CallGasLimit: newBig(30_000)}, nil
+}
+
+func (f *fakeBundler) SendUserOperation(op UserOperation, entryPoint common.Address) (common.Hash, error) {
+	if recoverSigner(f.t, &op) != f.owner {
+		return common.Hash{}, errors.New("AA24 signature error")
+	}
+	f.sent = &op
+	return op.Hash(entryPoint, chainID), nil
+}
+
+func (f *fakeBundler) GetUserOperationReceipt(hash common.Hash) *Receipt {
+	if f.polls++; f.polls < 2 {
+		return nil
+	}
+	return &Receipt{UserOpHash: hash, Sender: f.sent.Sender, Success: true, ActualGasCost: newBig(1)}
+}
+
+func TestSendCalls(t *testing.T) {
+	ctx := context.Background()
+	client := nodeMock(t, true)
+	a, err := NewSimpleAccount(ctx, client, testKey, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeBundler{t: t, owner: a.Owner}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", fake); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	b := NewBundler(rpc.DialInProc(server))
+	defer b.Close()
+
+	op, hash, err := SendCalls(ctx, client, b, a, nil, Call{To: recipient, Value: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bigOf(op.VerificationGasLimit).Int64() != 300_000 || fake.sent == nil {
+		t.Errorf("estimate not applied: %+v", op)
+	}
+	receipt, err := b.WaitForReceipt(ctx, hash, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !receipt.Success || receipt.UserOpHash != hash {
+		t.Errorf("unexpected receipt %+v", receipt)
+	}
+}
+
+// fakePaymaster 是进程内的 ERC-7677 服务，签名数据只在估算后才发放
+type fakePaymaster struct {
+	address common.Address
+	context map[string]interface{}
+	stubbed bool
+}
+
+func (f *fakePaymaster) GetPaymasterStubData(op UserOperation, entryPoint common.Address, chainID hexutil.Big, ctx map[string]interface{}) *PaymasterData {
+	f.stubbed, f.context = true, ctx
+	return &PaymasterData{Paymaster: f.address, PaymasterData: []byte{0}, PaymasterPostOpGasLimit: newBig(40_000)}
+}
+
+func (f *fakePaymaster) GetPaymasterData(op UserOperation, entryPoint common.Address, chainID hexutil.Big, ctx map[string]interface{}) (*PaymasterData, error) {
+	if bigOf(op.CallGasLimit).Sign() == 0 {
+		return nil, errors.New("gas limits must be estimated before signing")
+	}
+	return &PaymasterData{Paymaster: f.address, PaymasterData: []byte("signed")}, nil
+}
+
+func TestSendCallsWithPaymaster(t *testing.T) {
+	ctx := context.Background()
+	client := nodeMock(t, true)
+	a, err := NewSimpleAccount(ctx, client, testKey, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	fake := &fakeBundler{t: t, owner: a.Owner}
+	pmService := &fakePaymaster{address: common.HexToAddress("0x9a")}
+	if err := server.RegisterName("eth", fake); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("pm", pmService); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	rpcClient := rpc.DialInProc(server)
+	defer rpcClient.Close()
+
+	token := common.HexToAddress("0x70ce")
+	pm := NewERC20Paymaster(rpcClient, token, pmService.address)
+	op, _, err := SendCalls(ctx, client, NewBundler(rpcClient), a, pm, Call{To: recipient, Value: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pmService.stubbed || pmService.context["token"] != strings.ToLower(token.Hex()) {
+		t.Errorf("stub data not requested with the token context: %v", pmService.context)
+	}
+	if op.Paymaster == nil || string(op.PaymasterData) != "signed" || bigOf(op.PaymasterPostOpGasLimit).Int64() != 40_000 {
+		t.Errorf("paymaster fields not applied: %+v", op)
+	}
+	if fake.sent == nil || !strings.HasSuffix(hexutil.Encode(fake.sent.PaymasterAndData()), hexutil.Encode([]byte("signed"))[2:]) {
+		t.Error("bundler did not receive the signed paymaster data")
+	}
+	// ERC-20 paymaster 需要先授权扣款
+	method, err := accountABI.MethodById(op.CallData)
+	if err != nil || method.Name != "executeBatch" {
+		t.Fatalf("call data does not batch the approve: %v", err)
+	}
+	args, _ := method.Inputs.Unpack(op.CallData[4:])
+	if dests := args[0].([]common.Address); len(dests) != 2 || dests[0] != token {
+		t.Errorf("first call goes to %v, want the token", dests)
+	}
+}
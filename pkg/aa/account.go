@@ -0,0 +1,199 @@
+package aa
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// SimpleAccountFactoryV07 是 eth-infinitism SimpleAccountFactory（EntryPoint v0.7）的部署地址
+var SimpleAccountFactoryV07 = common.HexToAddress("0x91E60e0613810449d098b0b5Ec8b51A0FE8c8985")
+
+var (
+	factoryABI = mustABI(`[
+{"type":"function","name":"createAccount","stateMutability":"nonpayable","inputs":[
+	{"name":"owner","type":"address"},{"name":"salt","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"getAddress","stateMutability":"view","inputs":[
+	{"name":"owner","type":"address"},{"name":"salt","type":"uint256"}],"outputs":[{"name":"","type":"address"}]}
+]`)
+
+	accountABI = mustABI(`[
+{"type":"function","name":"execute","stateMutability":"nonpayable","inputs":[
+	{"name":"dest","type":"address"},{"name":"value","type":"uint256"},{"name":"func","type":"bytes"}],"outputs":[]},
+{"type":"function","name":"executeBatch","stateMutability":"nonpayable","inputs":[
+	{"name":"dest","type":"address[]"},{"name":"value","type":"uint256[]"},{"name":"func","type":"bytes[]"}],"outputs":[]}
+]`)
+
+	entryPointABI = mustABI(`[
+{"type":"function","name":"getNonce","stateMutability":"view","inputs":[
+	{"name":"sender","type":"address"},{"name":"key","type":"uint192"}],"outputs":[{"name":"nonce","type":"uint256"}]},
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`)
+)
+
+// dummySignature 是估算 gas 时使用的占位签名：格式合法（65 字节、低 s 值），但不会通过验证，
+// 使 bundler 能按真实签名的长度和验证路径估算 gas
+var dummySignature = common.FromHex("0xfffffffffffffffffffffffffffffff0000000000000000000000000000000007aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa1c")
+
+// Call 是智能账户执行的一次调用
+type Call struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+// SimpleAccount 是由一个 EOA 私钥控制的 eth-infinitism SimpleAccount，
+// 地址由工厂合约根据 (owner, salt) 用 CREATE2 确定，首次使用时随 UserOperation 部署
+type SimpleAccount struct {
+	Address    common.Address
+	Owner      common.Address
+	Salt       *big.Int
+	Factory    common.Address
+	EntryPoint common.Address
+	key        *ecdsa.PrivateKey
+}
+
+// NewSimpleAccount 从工厂合约查询 key 对应的账户地址，账户不需要已经部署
+func NewSimpleAccount(ctx context.Context, client chain.Client, key *ecdsa.PrivateKey, salt *big.Int) (*SimpleAccount, error) {
+	a := &SimpleAccount{
+		Owner:      crypto.PubkeyToAddress(key.PublicKey),
+		Salt:       salt,
+		Factory:    SimpleAccountFactoryV07,
+		EntryPoint: EntryPointV07,
+		key:        key,
+	}
+	out, err := call(ctx, client, a.Factory, factoryABI, "getAddress", a.Owner, salt)
+	if err != nil {
+		return nil, err
+	}
+	a.Address = out[0].(common.Address)
+	return a, nil
+}
+
+// Deployed 报告账户合约是否已经部署
+func (a *SimpleAccount) Deployed(ctx context.Context, client chain.Client) (bool, error) {
+	code, err := client.CodeAt(ctx, a.Address, nil)
+	if err != nil {
+		return false, fmt.Errorf("get account code: %w", err)
+	}
+	return len(code) > 0, nil
+}
+
+// Deposit 返回账户在 EntryPoint 中预存的 gas 费用
+func (a *SimpleAccount) Deposit(ctx context.Context, client chain.Client) (*big.Int, error) {
+	out, err := call(ctx, client, a.EntryPoint, entryPointABI, "balanceOf", a.Address)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// encodeCalls 把调用编码为 execute（单个）或 executeBatch（多个）的调用数据
+func encodeCalls(calls []Call) ([]byte, error) {
+	if len(calls) == 1 {
+		c := calls[0]
+		return accountABI.Pack("execute", c.To, orZero(c.Value), nonNil(c.Data))
+	}
+	dests := make([]common.Address, len(calls))
+	values := make([]*big.Int, len(calls))
+	datas := make([][]byte, len(calls))
+	for i, c := range calls {
+		dests[i], values[i], datas[i] = c.To, orZero(c.Value), nonNil(c.Data)
+	}
+	return accountABI.Pack("executeBatch", dests, values, datas)
+}
+
+// BuildUserOp 构造执行 calls 的 UserOperation：读取 EntryPoint 中的 nonce，账户未部署时附带
+// 工厂的 createAccount 调用，按当前区块设置费用，并填入占位签名。gas 上限为零，由
+// Bundler.EstimateGas 填入。
+func (a *SimpleAccount) BuildUserOp(ctx context.Context, client chain.Client, calls ...Call) (*UserOperation, error) {
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("no calls to execute")
+	}
+	callData, err := encodeCalls(calls)
+	if err != nil {
+		return nil, err
+	}
+	out, err := call(ctx, client, a.EntryPoint, entryPointABI, "getNonce", a.Address, new(big.Int))
+	if err != nil {
+		return nil, err
+	}
+	op := &UserOperation{
+		Sender:               a.Address,
+		Nonce:                (*hexutil.Big)(out[0].(*big.Int)),
+		CallData:             callData,
+		CallGasLimit:         newBig(0),
+		VerificationGasLimit: newBig(0),
+		PreVerificationGas:   newBig(0),
+		Signature:            dummySignature,
+	}
+
+	deployed, err := a.Deployed(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if !deployed {
+		factory := a.Factory
+		op.Factory = &factory
+		if op.FactoryData, err = factoryABI.Pack("createAccount", a.Owner, a.Salt); err != nil {
+			return nil, err
+		}
+	}
+
+	tip, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get gas tip cap: %w", err)
+	}
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get latest header: %w", err)
+	}
+	// 与 EOA 交易相同的策略：两倍基础费加小费，应对接下来几个区块的基础费上涨
+	maxFee := new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), tip)
+	op.MaxFeePerGas, op.MaxPriorityFeePerGas = (*hexutil.Big)(maxFee), (*hexutil.Big)(tip)
+	return op, nil
+}
+
+// Sign 用所有者私钥签名：SimpleAccount 验证的是 userOpHash 的 EIP-191 个人消息签名
+func (a *SimpleAccount) Sign(op *UserOperation, chainID *big.Int) error {
+	sig, err := crypto.Sign(accounts.TextHash(op.Hash(a.EntryPoint, chainID).Bytes()), a.key)
+	if err != nil {
+		return fmt.Errorf("sign user operation: %w", err)
+	}
+	sig[64] += 27 // ECDSA.recover 要求 v 为 27 或 28
+	op.Signature = sig
+	return nil
+}
+
+// call 调用只读方法并返回解码后的结果
+func call(ctx context.Context, client chain.Client, addr common.Address, contractABI abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	var out []interface{}
+	contract := bind.NewBoundContract(addr, contractABI, client, nil, nil)
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, method, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return out, nil
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+func nonNil(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
@@ -0,0 +1,125 @@
+package aa
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Bundler 是 ERC-4337 bundler 的 JSON-RPC 客户端
+type Bundler struct {
+	client     *rpc.Client
+	EntryPoint common.Address
+}
+
+// NewBundler 创建使用 EntryPoint v0.7 的 bundler 客户端
+func NewBundler(client *rpc.Client) *Bundler {
+	return &Bundler{client: client, EntryPoint: EntryPointV07}
+}
+
+// DialBundler 连接 bundler 端点
+func DialBundler(ctx context.Context, url string) (*Bundler, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("connect to bundler: %w", err)
+	}
+	return NewBundler(client), nil
+}
+
+// Close 关闭连接
+func (b *Bundler) Close() {
+	b.client.Close()
+}
+
+// SupportedEntryPoints 返回 bundler 支持的 EntryPoint 地址
+func (b *Bundler) SupportedEntryPoints(ctx context.Context) ([]common.Address, error) {
+	var out []common.Address
+	if err := b.client.CallContext(ctx, &out, "eth_supportedEntryPoints"); err != nil {
+		return nil, fmt.Errorf("eth_supportedEntryPoints: %w", err)
+	}
+	return out, nil
+}
+
+// GasEstimate 是 eth_estimateUserOperationGas 的结果
+type GasEstimate struct {
+	PreVerificationGas            *hexutil.Big `json:"preVerificationGas"`
+	VerificationGasLimit          *hexutil.Big `json:"verificationGasLimit"`
+	CallGasLimit                  *hexutil.Big `json:"callGasLimit"`
+	PaymasterVerificationGasLimit *hexutil.Big `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       *hexutil.Big `json:"paymasterPostOpGasLimit,omitempty"`
+}
+
+// EstimateGas 调用 eth_estimateUserOperationGas 并把结果写入 op 的 gas 字段。
+// op 应带有占位签名，估算时不校验签名是否有效。
+func (b *Bundler) EstimateGas(ctx context.Context, op *UserOperation) (*GasEstimate, error) {
+	var est GasEstimate
+	if err := b.client.CallContext(ctx, &est, "eth_estimateUserOperationGas", op, b.EntryPoint); err != nil {
+		return nil, fmt.Errorf("eth_estimateUserOperationGas: %w", err)
+	}
+	op.PreVerificationGas = est.PreVerificationGas
+	op.VerificationGasLimit = est.VerificationGasLimit
+	op.CallGasLimit = est.CallGasLimit
+	if op.Paymaster != nil {
+		if est.PaymasterVerificationGasLimit != nil {
+			op.PaymasterVerificationGasLimit = est.PaymasterVerificationGasLimit
+		}
+		if est.PaymasterPostOpGasLimit != nil {
+			op.PaymasterPostOpGasLimit = est.PaymasterPostOpGasLimit
+		}
+	}
+	return &est, nil
+}
+
+// Send 调用 eth_sendUserOperation 提交已签名的 op，返回 userOpHash
+func (b *Bundler) Send(ctx context.Context, op *UserOperation) (common.Hash, error) {
+	var hash common.Hash
+	if err := b.client.CallContext(ctx, &hash, "eth_sendUserOperation", op, b.EntryPoint); err != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendUserOperation: %w", err)
+	}
+	return hash, nil
+}
+
+// Receipt 是 eth_getUserOperationReceipt 的结果
+type Receipt struct {
+	UserOpHash    common.Hash    `json:"userOpHash"`
+	Sender        common.Address `json:"sender"`
+	Nonce         *hexutil.Big   `json:"nonce"`
+	Success       bool           `json:"success"`
+	Reason        string         `json:"reason,omitempty"` // 执行失败时的回滚原因
+	ActualGasCost *hexutil.Big   `json:"actualGasCost"`
+	ActualGasUsed *hexutil.Big   `json:"actualGasUsed"`
+	Logs          []*types.Log   `json:"logs"`
+	Receipt       *types.Receipt `json:"receipt"` // 包含该操作的 handleOps 交易收据
+}
+
+// GetReceipt 查询 userOpHash 的收据，尚未被打包时返回 nil
+func (b *Bundler) GetReceipt(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	var receipt *Receipt
+	if err := b.client.CallContext(ctx, &receipt, "eth_getUserOperationReceipt", hash); err != nil {
+		return nil, fmt.Errorf("eth_getUserOperationReceipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// WaitForReceipt 轮询直到 op 被打包上链
+func (b *Bundler) WaitForReceipt(ctx context.Context, hash common.Hash, interval time.Duration) (*Receipt, error) {
+	for {
+		receipt, err := b.GetReceipt(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
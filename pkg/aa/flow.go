@@ -0,0 +1,55 @@
+package aa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// SendCalls 完成一次账户抽象交易：构造 UserOperation、通过 bundler 估算 gas、签名并提交，
+// 返回提交的操作和 userOpHash。pm 不为 nil 时由它提供 paymaster 数据，否则账户自己支付 gas。
+// 用 Bundler.WaitForReceipt 等待其上链。
+func SendCalls(ctx context.Context, client chain.Client, b *Bundler, a *SimpleAccount, pm Paymaster, calls ...Call) (*UserOperation, common.Hash, error) {
+	if a.EntryPoint != b.EntryPoint {
+		return nil, common.Hash{}, fmt.Errorf("account uses EntryPoint %s but bundler uses %s", a.EntryPoint.Hex(), b.EntryPoint.Hex())
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("get chain ID: %w", err)
+	}
+	if p, ok := pm.(CallPreparer); ok {
+		calls = p.PrepareCalls(calls)
+	}
+	op, err := a.BuildUserOp(ctx, client, calls...)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	var stub *PaymasterData
+	if pm != nil {
+		if stub, err = pm.StubData(ctx, op, b.EntryPoint, chainID); err != nil {
+			return nil, common.Hash{}, err
+		}
+		stub.apply(op)
+	}
+	if _, err := b.EstimateGas(ctx, op); err != nil {
+		return nil, common.Hash{}, err
+	}
+	// paymaster 的签名覆盖 gas 字段，必须在估算之后获取
+	if pm != nil && !stub.IsFinal {
+		data, err := pm.Data(ctx, op, b.EntryPoint, chainID)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		data.apply(op)
+	}
+	if err := a.Sign(op, chainID); err != nil {
+		return nil, common.Hash{}, err
+	}
+	hash, err := b.Send(ctx, op)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	return op, hash, nil
+}
@@ -0,0 +1,133 @@
+package aa
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Paymaster 为 UserOperation 提供 paymaster 字段，由 paymaster 代付 gas 或以代币收取费用。
+// SendCalls 在估算 gas 前调用 StubData，估算之后、签名之前调用 Data。
+type Paymaster interface {
+	// StubData 返回结构合法但未签名的占位数据，使估算包含 paymaster 的验证开销
+	StubData(ctx context.Context, op *UserOperation, entryPoint common.Address, chainID *big.Int) (*PaymasterData, error)
+	// Data 返回 paymaster 对最终操作的签名数据
+	Data(ctx context.Context, op *UserOperation, entryPoint common.Address, chainID *big.Int) (*PaymasterData, error)
+}
+
+// CallPreparer 由需要在操作中附加调用的 paymaster 实现，例如 ERC-20 paymaster 需要先授权扣款
+type CallPreparer interface {
+	PrepareCalls(calls []Call) []Call
+}
+
+// PaymasterData 是 ERC-7677 pm_getPaymasterStubData / pm_getPaymasterData 的结果
+type PaymasterData struct {
+	Paymaster                     common.Address `json:"paymaster"`
+	PaymasterData                 hexutil.Bytes  `json:"paymasterData"`
+	PaymasterVerificationGasLimit *hexutil.Big   `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       *hexutil.Big   `json:"paymasterPostOpGasLimit,omitempty"`
+	Sponsor                       *struct {
+		Name string `json:"name"`
+		Icon string `json:"icon,omitempty"`
+	} `json:"sponsor,omitempty"`
+	// IsFinal 表示占位数据已经是最终数据，不需要再调用 Data
+	IsFinal bool `json:"isFinal,omitempty"`
+}
+
+// apply 把 paymaster 字段写入 op，未返回的 gas 上限保持原值
+func (d *PaymasterData) apply(op *UserOperation) {
+	pm := d.Paymaster
+	op.Paymaster = &pm
+	op.PaymasterData = d.PaymasterData
+	if d.PaymasterVerificationGasLimit != nil {
+		op.PaymasterVerificationGasLimit = d.PaymasterVerificationGasLimit
+	}
+	if d.PaymasterPostOpGasLimit != nil {
+		op.PaymasterPostOpGasLimit = d.PaymasterPostOpGasLimit
+	}
+	if op.PaymasterVerificationGasLimit == nil {
+		op.PaymasterVerificationGasLimit = newBig(0)
+	}
+	if op.PaymasterPostOpGasLimit == nil {
+		op.PaymasterPostOpGasLimit = newBig(0)
+	}
+}
+
+// ERC7677Paymaster 通过 ERC-7677 的 pm_ RPC 方法获取 paymaster 数据。Context 是服务商
+// 定义的附加参数，例如赞助策略 ID 或支付用的代币。
+type ERC7677Paymaster struct {
+	client  *rpc.Client
+	Context map[string]interface{}
+}
+
+// NewVerifyingPaymaster 创建代付 gas 的 paymaster。policyID 非空时作为 sponsorshipPolicyId
+// 传给服务商，用于选择赞助策略。
+func NewVerifyingPaymaster(client *rpc.Client, policyID string) *ERC7677Paymaster {
+	pm := &ERC7677Paymaster{client: client, Context: map[string]interface{}{}}
+	if policyID != "" {
+		pm.Context["sponsorshipPolicyId"] = policyID
+	}
+	return pm
+}
+
+// StubData 调用 pm_getPaymasterStubData
+func (p *ERC7677Paymaster) StubData(ctx context.Context, op *UserOperation, entryPoint common.Address, chainID *big.Int) (*PaymasterData, error) {
+	return p.request(ctx, "pm_getPaymasterStubData", op, entryPoint, chainID)
+}
+
+// Data 调用 pm_getPaymasterData
+func (p *ERC7677Paymaster) Data(ctx context.Context, op *UserOperation, entryPoint common.Address, chainID *big.Int) (*PaymasterData, error) {
+	return p.request(ctx, "pm_getPaymasterData", op, entryPoint, chainID)
+}
+
+func (p *ERC7677Paymaster) request(ctx context.Context, method string, op *UserOperation, entryPoint common.Address, chainID *big.Int) (*PaymasterData, error) {
+	var out PaymasterData
+	if err := p.client.CallContext(ctx, &out, method, op, entryPoint, (*hexutil.Big)(chainID), p.Context); err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	if out.Paymaster == (common.Address{}) {
+		return nil, fmt.Errorf("%s returned no paymaster address", method)
+	}
+	return &out, nil
+}
+
+// ERC20Paymaster 以 ERC-20 代币支付 gas：操作中先调用 approve 授权 paymaster 扣款，
+// paymaster 在执行后按报价收取代币
+type ERC20Paymaster struct {
+	*ERC7677Paymaster
+	Token   common.Address
+	Address common.Address // paymaster 合约地址，授权的对象
+	// Allowance 是授权的代币数量，nil 表示无限授权
+	Allowance *big.Int
+}
+
+// NewERC20Paymaster 创建用 token 支付 gas 的 paymaster，address 是服务商的 paymaster 合约
+func NewERC20Paymaster(client *rpc.Client, token, address common.Address) *ERC20Paymaster {
+	return &ERC20Paymaster{
+		ERC7677Paymaster: &ERC7677Paymaster{client: client, Context: map[string]interface{}{"token": token}},
+		Token:            token,
+		Address:          address,
+	}
+}
+
+// maxUint256 是无限授权的数量
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// PrepareCalls 在调用前插入对 paymaster 的 approve
+func (p *ERC20Paymaster) PrepareCalls(calls []Call) []Call {
+	amount := p.Allowance
+	if amount == nil {
+		amount = maxUint256
+	}
+	data, _ := erc20ABI.Pack("approve", p.Address, amount)
+	return append([]Call{{To: p.Token, Data: data}}, calls...)
+}
+
+var erc20ABI = mustABI(`[
+{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[
+	{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`)
@@ -0,0 +1,131 @@
+// Package aa 实现 ERC-4337 账户抽象的交易路径：为 SimpleAccount 智能账户构造 UserOperation，
+// 通过 bundler 的 eth_estimateUserOperationGas 估算 gas，用账户所有者的私钥签名，
+// 以 eth_sendUserOperation 提交，并轮询 eth_getUserOperationReceipt 直到被打包。
+// 实现针对 EntryPoint v0.7。
+package aa
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EntryPointV07 是 EntryPoint v0.7 在所有链上的确定性部署地址
+var EntryPointV07 = common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032")
+
+// UserOperation 是 EntryPoint v0.7 的用户操作，JSON 编码为 bundler RPC 使用的非打包格式。
+// Factory 为 nil 表示账户已部署；Paymaster 为 nil 表示由账户自己支付 gas。
+type UserOperation struct {
+	Sender               common.Address  `json:"sender"`
+	Nonce                *hexutil.Big    `json:"nonce"`
+	Factory              *common.Address `json:"factory,omitempty"`
+	FactoryData          hexutil.Bytes   `json:"factoryData,omitempty"`
+	CallData             hexutil.Bytes   `json:"callData"`
+	CallGasLimit         *hexutil.Big    `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big    `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big    `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+
+	Paymaster                     *common.Address `json:"paymaster,omitempty"`
+	PaymasterVerificationGasLimit *hexutil.Big    `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       *hexutil.Big    `json:"paymasterPostOpGasLimit,omitempty"`
+	PaymasterData                 hexutil.Bytes   `json:"paymasterData,omitempty"`
+
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// InitCode 返回 factory || factoryData，账户已部署时为空
+func (op *UserOperation) InitCode() []byte {
+	if op.Factory == nil {
+		return nil
+	}
+	return append(op.Factory.Bytes(), op.FactoryData...)
+}
+
+// PaymasterAndData 返回 paymaster || verificationGasLimit(16) || postOpGasLimit(16) || paymasterData
+func (op *UserOperation) PaymasterAndData() []byte {
+	if op.Paymaster == nil {
+		return nil
+	}
+	out := op.Paymaster.Bytes()
+	out = append(out, pack128(op.PaymasterVerificationGasLimit, op.PaymasterPostOpGasLimit)...)
+	return append(out, op.PaymasterData...)
+}
+
+// Hash 计算 EntryPoint.getUserOpHash：keccak256(abi.encode(keccak256(pack(op)), entryPoint, chainId))。
+// 签名字段不参与哈希。
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	packed, _ := packedOpArgs.Pack(
+		op.Sender, bigOf(op.Nonce),
+		crypto.Keccak256Hash(op.InitCode()), crypto.Keccak256Hash(op.CallData),
+		toBytes32(pack128(op.VerificationGasLimit, op.CallGasLimit)),
+		bigOf(op.PreVerificationGas),
+		toBytes32(pack128(op.MaxPriorityFeePerGas, op.MaxFeePerGas)),
+		crypto.Keccak256Hash(op.PaymasterAndData()),
+	)
+	enc, _ := hashArgs.Pack(crypto.Keccak256Hash(packed), entryPoint, chainID)
+	return crypto.Keccak256Hash(enc)
+}
+
+// RequiredPrefund 返回 EntryPoint 在验证阶段要求预存的最大 gas 费用
+func (op *UserOperation) RequiredPrefund() *big.Int {
+	gas := new(big.Int).Add(bigOf(op.CallGasLimit), bigOf(op.VerificationGasLimit))
+	gas.Add(gas, bigOf(op.PreVerificationGas))
+	gas.Add(gas, bigOf(op.PaymasterVerificationGasLimit))
+	gas.Add(gas, bigOf(op.PaymasterPostOpGasLimit))
+	return gas.Mul(gas, bigOf(op.MaxFeePerGas))
+}
+
+// pack128 把两个值各编码为 16 字节并拼接，对应 v0.7 打包的 gas 字段
+func pack128(hi, lo *hexutil.Big) []byte {
+	out := make([]byte, 32)
+	bigOf(hi).FillBytes(out[:16])
+	bigOf(lo).FillBytes(out[16:])
+	return out
+}
+
+func toBytes32(b []byte) [32]byte {
+	var out [32]byte
+	copy(out[:], b)
+	return out
+}
+
+func bigOf(v *hexutil.Big) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v.ToInt()
+}
+
+func newBig(v uint64) *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).SetUint64(v))
+}
+
+var (
+	packedOpArgs = abi.Arguments{
+		{Type: mustType("address")}, {Type: mustType("uint256")}, {Type: mustType("bytes32")}, {Type: mustType("bytes32")},
+		{Type: mustType("bytes32")}, {Type: mustType("uint256")}, {Type: mustType("bytes32")}, {Type: mustType("bytes32")},
+	}
+	hashArgs = abi.Arguments{{Type: mustType("bytes32")}, {Type: mustType("address")}, {Type: mustType("uint256")}}
+)
+
+func mustType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+func mustABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,139 @@
+// Package abicall 根据 ABI JSON 文件调用任意合约，不需要为每个合约生成 abigen 绑定：
+// 把命令行参数按方法的输入类型打包，执行 eth_call 并解码返回值。
+package abicall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/assertions"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/decode"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+// LoadABI 读取 ABI 文件，支持纯 ABI 数组和 Hardhat/Foundry 编译产物（带 "abi" 字段的对象）
+func LoadABI(path string) (*abi.ABI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if json.Unmarshal(data, &artifact) == nil && len(artifact.ABI) > 0 {
+		data = artifact.ABI
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("parse ABI %s: %w", path, err)
+	}
+	return &parsed, nil
+}
+
+// Method 按名称或完整签名（如 "transfer(address,uint256)"）查找方法。
+// 重载的方法只给名称时按参数个数选择，仍有歧义时要求使用签名。
+func Method(contract *abi.ABI, name string, nargs int) (*abi.Method, error) {
+	var candidates []abi.Method
+	for _, m := range contract.Methods {
+		if m.Sig == name {
+			return &m, nil
+		}
+		if m.RawName == name {
+			candidates = append(candidates, m)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("method %q not found in ABI", name)
+	}
+	if len(candidates) == 1 {
+		return &candidates[0], nil
+	}
+	var matched []abi.Method
+	sigs := make([]string, len(candidates))
+	for i, m := range candidates {
+		sigs[i] = m.Sig
+		if len(m.Inputs) == nargs {
+			matched = append(matched, m)
+		}
+	}
+	if len(matched) == 1 {
+		return &matched[0], nil
+	}
+	sort.Strings(sigs)
+	return nil, fmt.Errorf("method %q is overloaded, use one of: %s", name, strings.Join(sigs, ", "))
+}
+
+// Pack 查找方法并把字符串参数打包为调用数据
+func Pack(contract *abi.ABI, name string, args []string) (*abi.Method, []byte, error) {
+	method, err := Method(contract, name, len(args))
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := ParseArgs(method.Inputs, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	input, err := method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pack %s: %w", method.Sig, err)
+	}
+	return method, append(append([]byte{}, method.ID...), input...), nil
+}
+
+// Call 用 eth_call 在最新区块上执行 method 并解码返回值。调用回滚时返回 *ethtx.RevertError，
+// 回滚数据是 ABI 中定义的自定义错误时 Reason 为解码后的错误。
+func Call(ctx context.Context, client chain.Client, contract *abi.ABI, method *abi.Method, msg ethereum.CallMsg) ([]decode.Arg, error) {
+	result, err := client.CallContract(ctx, msg, nil)
+	if err != nil {
+		return nil, RevertError(contract, err)
+	}
+	if len(result) == 0 && len(method.Outputs) > 0 {
+		return nil, errors.New("call returned no data; is the address a contract with this method?")
+	}
+	values, err := method.Outputs.Unpack(result)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s output: %w", method.Name, err)
+	}
+	out := make([]decode.Arg, len(values))
+	for i, v := range values {
+		out[i] = decode.Arg{Name: method.Outputs[i].Name, Type: method.Outputs[i].Type.String(), Value: decode.FormatValue(v)}
+	}
+	return out, nil
+}
+
+// RevertError 把 eth_call/eth_estimateGas 的回滚错误转换为 *ethtx.RevertError，
+// 并用 contract 中的自定义错误解码回滚数据；不是回滚的错误原样返回
+func RevertError(contract *abi.ABI, err error) error {
+	reason, ok := assertions.RevertReason(err)
+	if !ok {
+		return err
+	}
+	if data := common.FromHex(reason); strings.HasPrefix(reason, "0x") && len(data) >= 4 {
+		for _, e := range contract.Errors {
+			if string(e.ID[:4]) != string(data[:4]) {
+				continue
+			}
+			if values, uerr := e.Inputs.Unpack(data[4:]); uerr == nil {
+				args := make([]string, len(values))
+				for i, v := range values {
+					args[i] = fmt.Sprint(decode.FormatValue(v))
+				}
+				reason = fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", "))
+			}
+			break
+		}
+	}
+	if reason == "0x" {
+		reason = ""
+	}
+	return &ethtx.RevertError{Reason: reason, Err: err}
+}
@@ -0,0 +1,137 @@
+package abicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+const testABI = `[
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]},
+{"type":"function","name":"get","stateMutability":"view","inputs":[{"name":"id","type":"uint8"}],"outputs":[{"name":"","type":"bytes32"},{"name":"ok","type":"bool"}]},
+{"type":"function","name":"get","stateMutability":"view","inputs":[{"name":"id","type":"uint8"},{"name":"key","type":"string"}],"outputs":[]},
+{"type":"function","name":"submit","stateMutability":"nonpayable","inputs":[
+	{"name":"order","type":"tuple","components":[{"name":"maker","type":"address"},{"name":"amounts","type":"int64[]"}]},
+	{"name":"salt","type":"bytes4"},{"name":"flags","type":"bool[2]"}],"outputs":[]},
+{"type":"error","name":"Unauthorized","inputs":[{"name":"caller","type":"address"}]}
+]`
+
+func loadTestABI(t *testing.T) *abi.ABI {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "artifact.json")
+	// 以 Foundry 编译产物的形式保存，LoadABI 应取出其中的 abi 字段
+	if err := os.WriteFile(path, []byte(`{"abi":`+testABI+`,"bytecode":"0x"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	contract, err := LoadABI(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contract
+}
+
+func TestPackRoundTrip(t *testing.T) {
+	contract := loadTestABI(t)
+	maker := "0x1111111111111111111111111111111111111111"
+	method, data, err := Pack(contract, "submit", []string{`["` + maker + `",[-1,"0x10"]]`, "0xdeadbeef", "[true,false]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := values[0].(struct {
+		Maker   common.Address `json:"maker"`
+		Amounts []int64        `json:"amounts"`
+	})
+	if order.Maker != common.HexToAddress(maker) || len(order.Amounts) != 2 || order.Amounts[0] != -1 || order.Amounts[1] != 16 {
+		t.Errorf("order = %+v", order)
+	}
+	if values[1].([4]byte) != [4]byte{0xde, 0xad, 0xbe, 0xef} || values[2].([2]bool) != [2]bool{true, false} {
+		t.Errorf("salt/flags = %v %v", values[1], values[2])
+	}
+}
+
+func TestParseArgErrors(t *testing.T) {
+	contract := loadTestABI(t)
+	for _, tt := range []struct {
+		method string
+		args   []string
+	}{
+		{"balanceOf", []string{"0x123"}},
+		{"balanceOf", []string{}},
+		{"get(uint8)", []string{"256"}},
+		{"get(uint8)", []string{"-1"}},
+		{"submit", []string{`["0x1111111111111111111111111111111111111111"]`, "0x00", "[true,false]"}},
+		{"submit", []string{`["0x1111111111111111111111111111111111111111",[]]`, "0x0000000000", "[true,false]"}},
+		{"submit", []string{`["0x1111111111111111111111111111111111111111",[]]`, "0x00", "[true]"}},
+		{"transfer", nil},
+	} {
+		if _, _, err := Pack(contract, tt.method, tt.args); err == nil {
+			t.Errorf("Pack(%s, %q) succeeded", tt.method, tt.args)
+		}
+	}
+}
+
+func TestMethodOverloads(t *testing.T) {
+	contract := loadTestABI(t)
+	if m, err := Method(contract, "get", 2); err != nil || m.Sig != "get(uint8,string)" {
+		t.Errorf("Method(get, 2) = %v, %v", m, err)
+	}
+	if m, err := Method(contract, "get(uint8)", 0); err != nil || len(m.Inputs) != 1 {
+		t.Errorf("Method(get(uint8)) = %v, %v", m, err)
+	}
+	if _, err := Method(contract, "get", 3); err == nil || !strings.Contains(err.Error(), "get(uint8,string)") {
+		t.Errorf("ambiguous Method error = %v, want it to list the signatures", err)
+	}
+}
+
+// rpcError 模拟节点返回的带回滚数据的错误
+type rpcError struct{ data string }
+
+func (e rpcError) Error() string          { return "execution reverted" }
+func (e rpcError) ErrorData() interface{} { return e.data }
+
+func TestCall(t *testing.T) {
+	contract := loadTestABI(t)
+	caller := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	client := &chain.ClientMock{
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			if call.From == caller {
+				e := contract.Errors["Unauthorized"]
+				data, _ := e.Inputs.Pack(caller)
+				return nil, rpcError{hexutil.Encode(append(e.ID[:4:4], data...))}
+			}
+			return contract.Methods["get"].Outputs.Pack([32]byte{1}, true)
+		},
+	}
+	method, data, err := Pack(contract, "get", []string{"7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Call(context.Background(), client, contract, method, ethereum.CallMsg{Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0].Value != "0x01"+strings.Repeat("00", 31) || out[1].Name != "ok" || out[1].Value != true {
+		t.Errorf("Call = %+v", out)
+	}
+
+	_, err = Call(context.Background(), client, contract, method, ethereum.CallMsg{From: caller, Data: data})
+	var revert *ethtx.RevertError
+	if !errors.As(err, &revert) || revert.Reason != "Unauthorized("+caller.Hex()+")" {
+		t.Errorf("Call error = %v, want the decoded custom error", err)
+	}
+}
@@ -0,0 +1,174 @@
+package abicall
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/local/go-eth-demo/pkg/address"
+)
+
+// ParseArgs 按 inputs 的类型把命令行参数转换为 abi.Pack 需要的 Go 值
+func ParseArgs(inputs abi.Arguments, args []string) ([]interface{}, error) {
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("got %d arguments, want %d (%s)", len(args), len(inputs), typeList(inputs))
+	}
+	values := make([]interface{}, len(args))
+	for i, input := range inputs {
+		v, err := ParseArg(input.Type, args[i])
+		if err != nil {
+			name := input.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("argument %s (%s): %w", name, input.Type, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// ParseArg 把字符串转换为 t 类型的值：
+//   - 整数支持十进制和 0x 十六进制，地址和字节使用十六进制，布尔使用 true/false
+//   - 数组和 tuple 使用 JSON 数组，例如 [1,2,3] 或 ["0xabc...",[1,2]]，元素可以写成字符串
+func ParseArg(t abi.Type, s string) (interface{}, error) {
+	v, err := parseValue(t, s)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+func parseValue(t abi.Type, s string) (reflect.Value, error) {
+	s = strings.TrimSpace(s)
+	switch t.T {
+	case abi.AddressTy:
+		addr, err := address.ParseHex(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(addr), nil
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool %q", s)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		return reflect.ValueOf(s), nil
+	case abi.IntTy, abi.UintTy:
+		return parseInt(t, s)
+	case abi.BytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes %q: %w", s, err)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes %q: %w", s, err)
+		}
+		if len(b) > t.Size {
+			return reflect.Value{}, fmt.Errorf("%d bytes do not fit in bytes%d", len(b), t.Size)
+		}
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+	case abi.SliceTy, abi.ArrayTy:
+		elems, err := splitJSON(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if t.T == abi.ArrayTy && len(elems) != t.Size {
+			return reflect.Value{}, fmt.Errorf("got %d elements, want %d", len(elems), t.Size)
+		}
+		v := reflect.New(t.GetType()).Elem()
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(t.GetType(), len(elems), len(elems))
+		}
+		for i, e := range elems {
+			ev, err := parseValue(*t.Elem, e)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, nil
+	case abi.TupleTy:
+		elems, err := splitJSON(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(elems) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("got %d tuple fields, want %d", len(elems), len(t.TupleElems))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		for i, e := range elems {
+			ev, err := parseValue(*t.TupleElems[i], e)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %w", t.TupleRawNames[i], err)
+			}
+			v.Field(i).Set(ev)
+		}
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+}
+
+// parseInt 解析整数并检查范围；8/16/32/64 位的类型返回对应的 Go 整数，其他返回 *big.Int
+func parseInt(t abi.Type, s string) (reflect.Value, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("invalid integer %q", s)
+	}
+	if t.T == abi.UintTy {
+		if n.Sign() < 0 || n.BitLen() > t.Size {
+			return reflect.Value{}, fmt.Errorf("%s out of range for uint%d", s, t.Size)
+		}
+	} else {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+		if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return reflect.Value{}, fmt.Errorf("%s out of range for int%d", s, t.Size)
+		}
+	}
+	goType := t.GetType()
+	switch goType.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.ValueOf(n.Uint64()).Convert(goType), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.ValueOf(n.Int64()).Convert(goType), nil
+	}
+	return reflect.ValueOf(n), nil
+}
+
+// splitJSON 把 JSON 数组拆成元素的文本：字符串元素去掉引号，其他元素（数字、布尔、嵌套数组）保留原文
+func splitJSON(s string) ([]string, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, fmt.Errorf("want a JSON array such as [1,2], got %q", s)
+	}
+	out := make([]string, len(raw))
+	for i, r := range raw {
+		var str string
+		if json.Unmarshal(r, &str) == nil {
+			out[i] = str
+		} else {
+			out[i] = string(r)
+		}
+	}
+	return out, nil
+}
+
+func typeList(args abi.Arguments) string {
+	types := make([]string, len(args))
+	for i, a := range args {
+		types[i] = a.Type.String()
+	}
+	return strings.Join(types, ", ")
+}
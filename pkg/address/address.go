@@ -0,0 +1,88 @@
+// Package address 解析用户输入的地址：0x 开头的十六进制地址或 ENS 名称。
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Input 是解析后的地址输入，Address 与 ENSName 二者之一非空
+type Input struct {
+	Address *common.Address
+	ENSName string // 已规范化（小写）的 ENS 名称，需要通过解析器查询地址
+}
+
+// ErrChecksum 表示大小写混合的地址不符合 EIP-55 校验和，通常是抄写时改错了字符
+var ErrChecksum = errors.New("bad EIP-55 checksum")
+
+// Parse 解析地址输入。十六进制地址按 ParseHex 校验；
+// 其余输入按 ENS 名称处理，要求由点分隔的非空标签组成（例如 vitalik.eth）。
+func Parse(s string) (Input, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		addr, err := ParseHex(s)
+		if err != nil {
+			return Input{}, err
+		}
+		return Input{Address: &addr}, nil
+	}
+	name, err := NormalizeName(s)
+	if err != nil {
+		return Input{}, err
+	}
+	return Input{ENSName: name}, nil
+}
+
+// ParseHex 严格解析十六进制地址：必须是 0x 加 40 个十六进制字符。全小写或全大写的地址不带校验和，直接接受；
+// 大小写混合时必须与 EIP-55 校验和一致，否则返回 ErrChecksum。common.HexToAddress 会静默截断或补齐长度错误的输入，
+// 也不检查校验和，用户输入的地址都应经过这里
+func ParseHex(s string) (common.Address, error) {
+	s = strings.TrimSpace(s)
+	if len(s) != 42 || !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") || !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid address %q: want 0x followed by 40 hex characters", s)
+	}
+	addr := common.HexToAddress(s)
+	if hex := s[2:]; hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && hex != addr.Hex()[2:] {
+		return common.Address{}, fmt.Errorf("invalid address %q: %w", s, ErrChecksum)
+	}
+	return addr, nil
+}
+
+// NormalizeName 对 ENS 名称做基本的规范化和校验：转小写，只允许字母、数字、连字符和下划线，
+// 且至少包含两个标签。完整的 ENSIP-15 Unicode 规范化不在此实现。
+func NormalizeName(s string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("invalid address or ENS name %q", s)
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 255 {
+			return "", fmt.Errorf("invalid ENS name %q: empty or oversized label", s)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return "", fmt.Errorf("invalid ENS name %q: unsupported character %q", s, r)
+			}
+		}
+	}
+	return name, nil
+}
+
+// NameHash 计算 ENS namehash（EIP-137）
+func NameHash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = crypto.Keccak256Hash(node.Bytes(), labelHash)
+	}
+	return node
+}
@@ -0,0 +1,88 @@
+package address
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNameHash(t *testing.T) {
+	// EIP-137 给出的测试向量
+	tests := map[string]string{
+		"":            "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"eth":         "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae",
+		"foo.eth":     "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f",
+		"vitalik.eth": "0xee6c4522aab0003e8d14cd40a6af439055fd2577951148c14b6cea9a53475835",
+	}
+	for name, want := range tests {
+		if got := NameHash(name).Hex(); got != want {
+			t.Errorf("NameHash(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	in, err := Parse("0xf92f0e5adb38f15a1e8514ea49de3f6028b8ff7d")
+	if err != nil || in.Address == nil || in.Address.Hex() != "0xF92F0E5AdB38f15a1E8514EA49De3f6028B8FF7D" {
+		t.Errorf("Parse(hex) = %+v, %v", in, err)
+	}
+	in, err = Parse("Vitalik.ETH")
+	if err != nil || in.ENSName != "vitalik.eth" {
+		t.Errorf("Parse(ens) = %+v, %v", in, err)
+	}
+	for _, bad := range []string{"", "0x1234", "0xzz2f0e5adb38f15a1e8514ea49de3f6028b8ff7d", "vitalik", "a..eth", ".eth", "bad name.eth"} {
+		if in, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) = %+v, want error", bad, in)
+		}
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	// EIP-55 给出的测试向量，以及同一地址的全小写和全大写形式
+	for _, s := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0X5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+	} {
+		addr, err := ParseHex(s)
+		if err != nil {
+			t.Errorf("ParseHex(%q): %v", s, err)
+		} else if !strings.EqualFold(addr.Hex(), s) {
+			t.Errorf("ParseHex(%q) = %s", s, addr.Hex())
+		}
+	}
+	// 改动一个字母的大小写后校验和不再匹配
+	if _, err := ParseHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("ParseHex(bad checksum) error = %v, want ErrChecksum", err)
+	}
+	for _, bad := range []string{"", "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00"} {
+		if _, err := ParseHex(bad); err == nil || errors.Is(err, ErrChecksum) {
+			t.Errorf("ParseHex(%q) error = %v, want length error", bad, err)
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"0xf92f0e5adb38f15a1e8514ea49de3f6028b8ff7d", "vitalik.eth", "0x", "a.b.c", "..", "ÿ.eth"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		in, err := Parse(s)
+		if err != nil {
+			return
+		}
+		if (in.Address == nil) == (in.ENSName == "") {
+			t.Fatalf("Parse(%q) = %+v, want exactly one of address or ENS name", s, in)
+		}
+		if in.ENSName != "" {
+			// 规范化必须是幂等的
+			again, err := NormalizeName(in.ENSName)
+			if err != nil || again != in.ENSName {
+				t.Fatalf("NormalizeName not idempotent for %q: %q, %v", in.ENSName, again, err)
+			}
+			NameHash(in.ENSName)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+// Package addressbook 是保存在 JSON 文件中的地址簿：按链把名称映射到地址，
+// 命令可以用 -to alice 代替完整地址，输出时也用名称标注已知地址。
+package addressbook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/address"
+)
+
+// ErrNotFound 表示地址簿中没有该名称
+var ErrNotFound = errors.New("not in the address book")
+
+// Entry 是一个名称。ChainID 为 0 表示在所有链上通用（如同一个 EOA 在各链上的地址相同）
+type Entry struct {
+	Name    string         `json:"name"`
+	Address common.Address `json:"address"`
+	ChainID uint64         `json:"chainId,omitempty"`
+}
+
+// Book 是从文件加载的地址簿，修改后调用 Save 写回
+type Book struct {
+	path    string
+	Entries []Entry
+}
+
+// Load 读取 path 中的地址簿，文件不存在时返回空地址簿
+func Load(path string) (*Book, error) {
+	b := &Book{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &b.Entries); err != nil {
+		return nil, fmt.Errorf("parse address book %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Save 按链和名称排序后写回文件
+func (b *Book) Save() error {
+	sort.Slice(b.Entries, func(i, j int) bool {
+		if b.Entries[i].ChainID != b.Entries[j].ChainID {
+			return b.Entries[i].ChainID < b.Entries[j].ChainID
+		}
+		return b.Entries[i].Name < b.Entries[j].Name
+	})
+	data, err := json.MarshalIndent(b.Entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(b.path, append(data, '\n'), 0o600)
+}
+
+// ValidName 检查名称：不能为空、不能包含空白，也不能是十六进制地址
+func ValidName(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n,") {
+		return fmt.Errorf("invalid name %q: must be non-empty without spaces or commas", name)
+	}
+	if common.IsHexAddress(name) || strings.HasPrefix(name, "0x") {
+		return fmt.Errorf("invalid name %q: must not look like an address", name)
+	}
+	return nil
+}
+
+// Add 在 chainID 上添加或更新名称，返回是否替换了已有的地址
+func (b *Book) Add(name string, addr common.Address, chainID uint64) (replaced bool, err error) {
+	if err := ValidName(name); err != nil {
+		return false, err
+	}
+	for i, e := range b.Entries {
+		if e.ChainID == chainID && strings.EqualFold(e.Name, name) {
+			b.Entries[i] = Entry{Name: name, Address: addr, ChainID: chainID}
+			return e.Address != addr, nil
+		}
+	}
+	b.Entries = append(b.Entries, Entry{Name: name, Address: addr, ChainID: chainID})
+	return false, nil
+}
+
+// Remove 删除 chainID 上的名称
+func (b *Book) Remove(name string, chainID uint64) error {
+	for i, e := range b.Entries {
+		if e.ChainID == chainID && strings.EqualFold(e.Name, name) {
+			b.Entries = append(b.Entries[:i], b.Entries[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%q on chain %d: %w", name, chainID, ErrNotFound)
+}
+
+// Lookup 返回名称在 chainID 上的地址，名称不区分大小写；该链上没有时使用所有链通用的条目
+func (b *Book) Lookup(name string, chainID uint64) (common.Address, error) {
+	var fallback *Entry
+	for i, e := range b.Entries {
+		if !strings.EqualFold(e.Name, name) {
+			continue
+		}
+		if e.ChainID == chainID {
+			return e.Address, nil
+		}
+		if e.ChainID == 0 {
+			fallback = &b.Entries[i]
+		}
+	}
+	if fallback != nil {
+		return fallback.Address, nil
+	}
+	return common.Address{}, fmt.Errorf("%q: %w", name, ErrNotFound)
+}
+
+// Resolve 把命令行参数解析为地址：十六进制地址直接返回，否则按名称查找
+func (b *Book) Resolve(s string, chainID uint64) (common.Address, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") || common.IsHexAddress(s) {
+		return address.ParseHex(s)
+	}
+	if s == "" {
+		return common.Address{}, fmt.Errorf("invalid address %q", s)
+	}
+	return b.Lookup(s, chainID)
+}
+
+// Name 返回地址在 chainID 上的名称（优先该链的条目），没有时返回空字符串
+func (b *Book) Name(addr common.Address, chainID uint64) string {
+	name := ""
+	for _, e := range b.Entries {
+		if e.Address != addr {
+			continue
+		}
+		if e.ChainID == chainID {
+			return e.Name
+		}
+		if e.ChainID == 0 {
+			name = e.Name
+		}
+	}
+	return name
+}
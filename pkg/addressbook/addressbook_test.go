@@ -0,0 +1,86 @@
+package addressbook
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/address"
+)
+
+var (
+	alice      = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	aliceOnOP  = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	sepolia    = uint64(11155111)
+	opSepolia  = uint64(11155420)
+	unknownNet = uint64(31337)
+)
+
+func TestAddLookupSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addressbook.json")
+	b, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Add("alice", alice, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Add("alice", aliceOnOP, opSepolia); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		chainID uint64
+		want    common.Address
+	}{{sepolia, alice}, {unknownNet, alice}, {opSepolia, aliceOnOP}} {
+		if got, err := b.Resolve("Alice", tt.chainID); err != nil || got != tt.want {
+			t.Errorf("Resolve(Alice, %d) = %s, %v; want %s", tt.chainID, got.Hex(), err, tt.want.Hex())
+		}
+	}
+	if got, _ := b.Resolve(aliceOnOP.Hex(), sepolia); got != aliceOnOP {
+		t.Errorf("Resolve(hex) = %s", got.Hex())
+	}
+	// 大小写混合但校验和不对的地址是输错了，不能当作普通地址接受
+	if _, err := b.Resolve("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", sepolia); !errors.Is(err, address.ErrChecksum) {
+		t.Errorf("Resolve(bad checksum) error = %v, want ErrChecksum", err)
+	}
+	if _, err := b.Resolve("bob", sepolia); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Resolve(bob) error = %v, want ErrNotFound", err)
+	}
+	if b.Name(aliceOnOP, opSepolia) != "alice" || b.Name(aliceOnOP, sepolia) != "" {
+		t.Errorf("Name(aliceOnOP) = %q on OP, %q on Sepolia", b.Name(aliceOnOP, opSepolia), b.Name(aliceOnOP, sepolia))
+	}
+
+	replaced, err := b.Add("ALICE", aliceOnOP, 0)
+	if err != nil || !replaced {
+		t.Errorf("Add over an existing name = %v, %v; want replaced", replaced, err)
+	}
+	if err := b.Remove("alice", opSepolia); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := b.Lookup("alice", opSepolia); got != aliceOnOP {
+		t.Errorf("after Remove, Lookup falls back to %s, want the all-chain entry", got.Hex())
+	}
+	if err := b.Remove("alice", opSepolia); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Remove = %v, want ErrNotFound", err)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	for _, name := range []string{"", "a b", "0x12", alice.Hex(), "a,b"} {
+		if ValidName(name) == nil {
+			t.Errorf("ValidName(%q) succeeded", name)
+		}
+	}
+	if err := ValidName("treasury-multisig"); err != nil {
+		t.Error(err)
+	}
+}
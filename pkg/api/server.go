@@ -0,0 +1,376 @@
+// Package api 把本项目的链上读写能力以 HTTP/JSON API 的形式提供给前端或其他服务：
+// 余额、ERC-20 代币余额、原始交易提交、合约只读调用和事件查询。所有请求都需要
+// Authorization: Bearer <token>。
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/decode"
+	"github.com/local/go-eth-demo/pkg/units"
+)
+
+// maxBodyBytes 限制请求体大小，足够容纳带大量 calldata 的交易
+const maxBodyBytes = 1 << 20
+
+// requestTimeout 是每个请求访问节点的最长时间
+const requestTimeout = 30 * time.Second
+
+// erc20ABI 只包含读取余额所需的方法
+var erc20ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// Server 是 API 的 http.Handler
+type Server struct {
+	client chain.Client
+	token  string
+	mux    *http.ServeMux
+
+	// OnRequest 在每个请求完成后调用（可选），route 为匹配到的路由模式，用于收集指标
+	OnRequest func(route string, status int, elapsed time.Duration)
+}
+
+// NewServer 创建 API 服务。token 不能为空，所有请求都必须携带该 token。
+func NewServer(client chain.Client, token string) (*Server, error) {
+	if token == "" {
+		return nil, errors.New("api token must not be empty")
+	}
+	s := &Server{client: client, token: token, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /v1/balance/{address}", s.balance)
+	s.mux.HandleFunc("GET /v1/token/{token}/balance/{address}", s.tokenBalance)
+	s.mux.HandleFunc("POST /v1/tx", s.sendTx)
+	s.mux.HandleFunc("POST /v1/call", s.call)
+	s.mux.HandleFunc("GET /v1/logs", s.logs)
+	return s, nil
+}
+
+// ServeHTTP 校验 token 后分发请求
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	req := r
+	defer func() {
+		if s.OnRequest != nil {
+			route := req.Pattern
+			if route == "" {
+				route = "unmatched"
+			}
+			s.OnRequest(route, sw.status, time.Since(start))
+		}
+	}()
+
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+		sw.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(sw, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+	req = r.WithContext(ctx)
+	s.mux.ServeHTTP(sw, req)
+}
+
+// statusWriter 记录响应状态码
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// BalanceResponse 是 GET /v1/balance/{address} 的响应
+type BalanceResponse struct {
+	Address string `json:"address"`
+	Block   string `json:"block"`
+	Wei     string `json:"wei"`
+	Eth     string `json:"eth"`
+}
+
+func (s *Server) balance(w http.ResponseWriter, r *http.Request) {
+	addr, err := pathAddress(r, "address")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	block, err := ParseBlock(r.URL.Query().Get("block"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	wei, err := s.client.BalanceAt(r.Context(), addr, block)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("get balance: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, BalanceResponse{
+		Address: addr.Hex(),
+		Block:   blockString(block),
+		Wei:     wei.String(),
+		Eth:     units.FormatUnits(wei, 18),
+	})
+}
+
+// TokenBalanceResponse 是 GET /v1/token/{token}/balance/{address} 的响应
+type TokenBalanceResponse struct {
+	Token     string `json:"token"`
+	Symbol    string `json:"symbol,omitempty"`
+	Address   string `json:"address"`
+	Decimals  uint8  `json:"decimals"`
+	Raw       string `json:"raw"`
+	Formatted string `json:"formatted"`
+}
+
+func (s *Server) tokenBalance(w http.ResponseWriter, r *http.Request) {
+	token, err := pathAddress(r, "token")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	addr, err := pathAddress(r, "address")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var raw *big.Int
+	if err := s.callERC20(r.Context(), token, &raw, "balanceOf", addr); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	var decimals uint8
+	if err := s.callERC20(r.Context(), token, &decimals, "decimals"); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	// symbol 是可选的，部分老代币（如 MKR）返回 bytes32，这里忽略失败
+	var symbol string
+	s.callERC20(r.Context(), token, &symbol, "symbol")
+
+	writeJSON(w, http.StatusOK, TokenBalanceResponse{
+		Token:     token.Hex(),
+		Symbol:    symbol,
+		Address:   addr.Hex(),
+		Decimals:  decimals,
+		Raw:       raw.String(),
+		Formatted: units.FormatUnits(raw, int(decimals)),
+	})
+}
+
+// callERC20 调用代币合约的只读方法并把唯一的返回值写入 out
+func (s *Server) callERC20(ctx context.Context, token common.Address, out interface{}, method string, args ...interface{}) error {
+	data, err := erc20ABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	result, err := s.client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", method, err)
+	}
+	values, err := erc20ABI.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("%s is not an ERC-20 token (%s): %w", token.Hex(), method, err)
+	}
+	abi.ConvertType(values[0], out)
+	return nil
+}
+
+// SendTxRequest 是 POST /v1/tx 的请求体，raw 为已签名交易的十六进制编码
+type SendTxRequest struct {
+	Raw string `json:"raw"`
+}
+
+// SendTxResponse 是 POST /v1/tx 的响应
+type SendTxResponse struct {
+	Hash string `json:"hash"`
+	From string `json:"from,omitempty"`
+}
+
+func (s *Server) sendTx(w http.ResponseWriter, r *http.Request) {
+	var req SendTxRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	tx, err := decode.ParseRawTransaction(req.Raw)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.client.SendTransaction(r.Context(), tx); err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("send transaction: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusAccepted, SendTxResponse{Hash: tx.Hash().Hex(), From: decode.Transaction(tx).From})
+}
+
+// CallRequest 是 POST /v1/call 的请求体，data 为完整的 calldata（选择器 + 参数）
+type CallRequest struct {
+	From  string `json:"from,omitempty"`
+	To    string `json:"to"`
+	Data  string `json:"data"`
+	Block string `json:"block,omitempty"`
+}
+
+// CallResponse 是 POST /v1/call 的响应
+type CallResponse struct {
+	Result string `json:"result"`
+}
+
+func (s *Server) call(w http.ResponseWriter, r *http.Request) {
+	var req CallRequest
+	if err := readJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	to, err := address.ParseHex(req.To)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("to: %w", err))
+		return
+	}
+	data, err := hexutil.Decode(req.Data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid data: %w", err))
+		return
+	}
+	block, err := ParseBlock(req.Block)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	msg := ethereum.CallMsg{To: &to, Data: data}
+	if req.From != "" {
+		if msg.From, err = address.ParseHex(req.From); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("from: %w", err))
+			return
+		}
+	}
+	result, err := s.client.CallContract(r.Context(), msg, block)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("call contract: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, CallResponse{Result: hexutil.Encode(result)})
+}
+
+// LogsResponse 是 GET /v1/logs 的响应
+type LogsResponse struct {
+	Logs []types.Log `json:"logs"`
+}
+
+// logs 查询事件日志：address（必填）、fromBlock、toBlock、topic0
+func (s *Server) logs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	addr, err := address.ParseHex(q.Get("address"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	query := ethereum.FilterQuery{Addresses: []common.Address{addr}}
+	if query.FromBlock, err = ParseBlock(q.Get("fromBlock")); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if query.ToBlock, err = ParseBlock(q.Get("toBlock")); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if topic := q.Get("topic0"); topic != "" {
+		hash, err := hexutil.Decode(topic)
+		if err != nil || len(hash) != common.HashLength {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid topic0: %q", topic))
+			return
+		}
+		query.Topics = [][]common.Hash{{common.BytesToHash(hash)}}
+	}
+	logs, err := s.client.FilterLogs(r.Context(), query)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("filter logs: %w", err))
+		return
+	}
+	if logs == nil {
+		logs = []types.Log{}
+	}
+	writeJSON(w, http.StatusOK, LogsResponse{Logs: logs})
+}
+
+func pathAddress(r *http.Request, name string) (common.Address, error) {
+	v := r.PathValue(name)
+	addr, err := address.ParseHex(v)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return addr, nil
+}
+
+// ParseBlock 解析区块参数：空或 "latest" 表示最新区块，否则为十进制或 0x 开头的十六进制区块号
+func ParseBlock(s string) (*big.Int, error) {
+	if s == "" || s == "latest" {
+		return nil, nil
+	}
+	if strings.HasPrefix(s, "0x") {
+		n, err := hexutil.DecodeBig(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block %q: %w", s, err)
+		}
+		return n, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block %q", s)
+	}
+	return new(big.Int).SetUint64(n), nil
+}
+
+func blockString(block *big.Int) string {
+	if block == nil {
+		return "latest"
+	}
+	return block.String()
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+const testToken = "secret"
+
+var (
+	holder = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	usdc   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// newTestServer 创建使用模拟节点的服务：holder 有 1.5 ETH，usdc 是 6 位小数的代币
+func newTestServer(t *testing.T) (*Server, *chain.ClientMock) {
+	t.Helper()
+	client := &chain.ClientMock{
+		BalanceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+			return big.NewInt(1_500_000_000_000_000_000), nil
+		},
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			method, err := erc20ABI.MethodById(call.Data[:4])
+			if err != nil {
+				return []byte{0xde, 0xad}, nil
+			}
+			switch method.Name {
+			case "balanceOf":
+				return method.Outputs.Pack(big.NewInt(2_500_000))
+			case "decimals":
+				return method.Outputs.Pack(uint8(6))
+			default:
+				return method.Outputs.Pack("USDC")
+			}
+		},
+		SendTransactionFunc: func(ctx context.Context, tx *types.Transaction) error { return nil },
+		FilterLogsFunc: func(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+			return []types.Log{{Address: q.Addresses[0], Topics: []common.Hash{{1}}, Data: []byte{}}}, nil
+		},
+	}
+	s, err := NewServer(client, testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, client
+}
+
+func do(t *testing.T, s *Server, method, path, body string, out interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode response %q: %v", method, path, rec.Body, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestAuth(t *testing.T) {
+	s, _ := newTestServer(t)
+	for _, header := range []string{"", "Bearer wrong", "secret"} {
+		req := httptest.NewRequest("GET", "/v1/balance/"+holder.Hex(), nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status %d, want 401", header, rec.Code)
+		}
+	}
+	if _, err := NewServer(nil, ""); err == nil {
+		t.Error("NewServer accepted an empty token")
+	}
+}
+
+func TestBalance(t *testing.T) {
+	s, client := newTestServer(t)
+	var resp BalanceResponse
+	if code := do(t, s, "GET", "/v1/balance/"+holder.Hex()+"?block=100", "", &resp); code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+	if resp.Eth != "1.5" || resp.Wei != "1500000000000000000" || resp.Block != "100" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if got := client.BalanceAtCalls()[0].BlockNumber; got.Int64() != 100 {
+		t.Errorf("queried block %s, want 100", got)
+	}
+	if code := do(t, s, "GET", "/v1/balance/0x123", "", nil); code != http.StatusBadRequest {
+		t.Errorf("invalid address: status %d, want 400", code)
+	}
+}
+
+func TestTokenBalance(t *testing.T) {
+	s, _ := newTestServer(t)
+	var resp TokenBalanceResponse
+	if code := do(t, s, "GET", "/v1/token/"+usdc.Hex()+"/balance/"+holder.Hex(), "", &resp); code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+	if resp.Symbol != "USDC" || resp.Decimals != 6 || resp.Raw != "2500000" || resp.Formatted != "2.5" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+func TestSendTx(t *testing.T) {
+	s, client := newTestServer(t)
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSignerForChainID(big.NewInt(11155111))
+	tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID: big.NewInt(11155111), Gas: 21000, To: &holder, Value: big.NewInt(1),
+		GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2),
+	})
+	raw, _ := tx.MarshalBinary()
+
+	var resp SendTxResponse
+	body := `{"raw":"` + hexutil.Encode(raw) + `"}`
+	if code := do(t, s, "POST", "/v1/tx", body, &resp); code != http.StatusAccepted {
+		t.Fatalf("status %d", code)
+	}
+	if resp.Hash != tx.Hash().Hex() || resp.From != crypto.PubkeyToAddress(key.PublicKey).Hex() {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if len(client.SendTransactionCalls()) != 1 {
+		t.Error("transaction was not sent")
+	}
+	if code := do(t, s, "POST", "/v1/tx", `{"raw":"0x1234"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("invalid raw tx: status %d, want 400", code)
+	}
+	if code := do(t, s, "POST", "/v1/tx", `{"raw":"0x","extra":1}`, nil); code != http.StatusBadRequest {
+		t.Errorf("unknown field: status %d, want 400", code)
+	}
+}
+
+func TestCall(t *testing.T) {
+	s, _ := newTestServer(t)
+	var resp CallResponse
+	body, _ := json.Marshal(CallRequest{To: usdc.Hex(), Data: "0x12345678"})
+	if code := do(t, s, "POST", "/v1/call", string(body), &resp); code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+	if resp.Result != "0xdead" {
+		t.Errorf("result %s, want 0xdead", resp.Result)
+	}
+}
+
+func TestLogs(t *testing.T) {
+	s, client := newTestServer(t)
+	var resp LogsResponse
+	topic := common.Hash{1}.Hex()
+	path := "/v1/logs?address=" + usdc.Hex() + "&fromBlock=10&toBlock=0x14&topic0=" + topic
+	if code := do(t, s, "GET", path, "", &resp); code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+	if len(resp.Logs) != 1 || resp.Logs[0].Address != usdc {
+		t.Errorf("unexpected logs %+v", resp.Logs)
+	}
+	q := client.FilterLogsCalls()[0].Q
+	if q.FromBlock.Int64() != 10 || q.ToBlock.Int64() != 20 || q.Topics[0][0] != (common.Hash{1}) {
+		t.Errorf("unexpected filter %+v", q)
+	}
+	var errResp map[string]string
+	if code := do(t, s, "GET", "/v1/logs", "", &errResp); code != http.StatusBadRequest || errResp["error"] == "" {
+		t.Errorf("missing address: status %d, body %v", code, errResp)
+	}
+}
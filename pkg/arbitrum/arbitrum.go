@@ -0,0 +1,149 @@
+// Package arbitrum 实现 Arbitrum 的 L1→L2 可重试票据（retryable ticket）：估算提交费用和
+// L2 gas，通过 Inbox 创建票据，从 L1 收据推导 L2 上的票据 ID 并跟踪其兑现状态；同时提供
+// 考虑 Arbitrum gas 模型（L1 数据费用折算为 L2 gas）的 gas 估算。
+package arbitrum
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Network 是一条 Arbitrum 链在 L1 上的合约地址
+type Network struct {
+	Name      string
+	L1ChainID uint64
+	L2ChainID uint64
+	Inbox     common.Address
+	Bridge    common.Address
+	Outbox    common.Address
+	// ConfirmPeriod 是 L2→L1 消息在 L1 上可执行前的大致等待时间（rollup 的确认期）
+	ConfirmPeriod time.Duration
+}
+
+// Networks 是内置的网络，地址来自 Arbitrum 官方文档
+var Networks = map[string]Network{
+	"arbitrum-one": {
+		Name: "arbitrum-one", L1ChainID: 1, L2ChainID: 42161,
+		Inbox:  common.HexToAddress("0x4Dbd4fc535Ac27206064B68FfCf827b0A60BAB3f"),
+		Bridge: common.HexToAddress("0x8315177aB297bA92A06054cE80a67Ed4DBd7ed3a"),
+		Outbox: common.HexToAddress("0x0B9857ae2D4A3DBe74ffE1d7DF045bb7F96E4840"),
+		// 45818 个 L1 区块
+		ConfirmPeriod: 45818 * 12 * time.Second,
+	},
+	"arbitrum-nova": {
+		Name: "arbitrum-nova", L1ChainID: 1, L2ChainID: 42170,
+		Inbox:         common.HexToAddress("0xc4448b71118c9071Bcb9734A0EAc55D18A153949"),
+		Bridge:        common.HexToAddress("0xC1Ebd02f738644983b6C4B2d440b8e77DdE276Bd"),
+		Outbox:        common.HexToAddress("0xD4B80C3D7240325D18E645B49e6535A3Bf95cc58"),
+		ConfirmPeriod: 45818 * 12 * time.Second,
+	},
+	"arbitrum-sepolia": {
+		Name: "arbitrum-sepolia", L1ChainID: 11155111, L2ChainID: 421614,
+		Inbox:         common.HexToAddress("0xaAe29B0366299461418F5324a79Afc425BE5ae21"),
+		Bridge:        common.HexToAddress("0x38f918D0E9F1b721EDaA41302E399fa1B79333a9"),
+		Outbox:        common.HexToAddress("0x65f07C7D521164a4d5DaC6eB8Fac8DA067A3B78F"),
+		ConfirmPeriod: 20 * 12 * time.Second,
+	},
+}
+
+// LookupNetwork 按名称查找内置网络
+func LookupNetwork(name string) (Network, error) {
+	if n, ok := Networks[name]; ok {
+		return n, nil
+	}
+	names := make([]string, 0, len(Networks))
+	for n := range Networks {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return Network{}, fmt.Errorf("unknown Arbitrum network %q (known: %s)", name, strings.Join(names, ", "))
+}
+
+var (
+	// NodeInterface 是只能通过 eth_call/eth_estimateGas 调用的虚拟合约，提供估算辅助方法
+	NodeInterface = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+	// ArbRetryableTx 是管理可重试票据的预编译合约
+	ArbRetryableTx = common.HexToAddress("0x000000000000000000000000000000000000006E")
+	// ArbSys 是发送 L2→L1 消息的预编译合约
+	ArbSys = common.HexToAddress("0x0000000000000000000000000000000000000064")
+)
+
+// aliasOffset 是 L1 合约地址在 L2 上的别名偏移量
+var aliasOffset = new(big.Int).SetBytes(common.FromHex("0x1111000000000000000000000000000000001111"))
+
+// ApplyL1ToL2Alias 返回 L1 合约地址在 L2 上作为 msg.sender 出现时的别名地址
+func ApplyL1ToL2Alias(addr common.Address) common.Address {
+	sum := new(big.Int).Add(new(big.Int).SetBytes(addr.Bytes()), aliasOffset)
+	return common.BigToAddress(sum) // BigToAddress 只保留低 20 字节，相当于对 2^160 取模
+}
+
+var (
+	inboxABI = mustABI(`[
+{"type":"function","name":"createRetryableTicket","stateMutability":"payable","inputs":[
+	{"name":"to","type":"address"},{"name":"l2CallValue","type":"uint256"},{"name":"maxSubmissionCost","type":"uint256"},
+	{"name":"excessFeeRefundAddress","type":"address"},{"name":"callValueRefundAddress","type":"address"},
+	{"name":"gasLimit","type":"uint256"},{"name":"maxFeePerGas","type":"uint256"},{"name":"data","type":"bytes"}],
+	"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"calculateRetryableSubmissionFee","stateMutability":"view","inputs":[
+	{"name":"dataLength","type":"uint256"},{"name":"baseFee","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"event","name":"InboxMessageDelivered","anonymous":false,"inputs":[
+	{"name":"messageNum","type":"uint256","indexed":true},{"name":"data","type":"bytes","indexed":false}]}
+]`)
+
+	bridgeABI = mustABI(`[
+{"type":"event","name":"MessageDelivered","anonymous":false,"inputs":[
+	{"name":"messageIndex","type":"uint256","indexed":true},{"name":"beforeInboxAcc","type":"bytes32","indexed":true},
+	{"name":"inbox","type":"address","indexed":false},{"name":"kind","type":"uint8","indexed":false},
+	{"name":"sender","type":"address","indexed":false},{"name":"messageDataHash","type":"bytes32","indexed":false},
+	{"name":"baseFeeL1","type":"uint256","indexed":false},{"name":"timestamp","type":"uint64","indexed":false}]}
+]`)
+
+	nodeInterfaceABI = mustABI(`[
+{"type":"function","name":"estimateRetryableTicket","stateMutability":"nonpayable","inputs":[
+	{"name":"sender","type":"address"},{"name":"deposit","type":"uint256"},{"name":"to","type":"address"},
+	{"name":"l2CallValue","type":"uint256"},{"name":"excessFeeRefundAddress","type":"address"},
+	{"name":"callValueRefundAddress","type":"address"},{"name":"data","type":"bytes"}],"outputs":[]},
+{"type":"function","name":"gasEstimateComponents","stateMutability":"payable","inputs":[
+	{"name":"to","type":"address"},{"name":"contractCreation","type":"bool"},{"name":"data","type":"bytes"}],
+	"outputs":[{"name":"gasEstimate","type":"uint64"},{"name":"gasEstimateForL1","type":"uint64"},
+	{"name":"baseFee","type":"uint256"},{"name":"l1BaseFeeEstimate","type":"uint256"}]}
+]`)
+
+	outboxABI = mustABI(`[
+{"type":"function","name":"isSpent","stateMutability":"view","inputs":[{"name":"index","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`)
+
+	arbSysABI = mustABI(`[
+{"type":"function","name":"arbChainID","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"event","name":"L2ToL1Tx","anonymous":false,"inputs":[
+	{"name":"caller","type":"address","indexed":false},{"name":"destination","type":"address","indexed":true},
+	{"name":"hash","type":"uint256","indexed":true},{"name":"position","type":"uint256","indexed":true},
+	{"name":"arbBlockNum","type":"uint256","indexed":false},{"name":"ethBlockNum","type":"uint256","indexed":false},
+	{"name":"timestamp","type":"uint256","indexed":false},{"name":"callvalue","type":"uint256","indexed":false},
+	{"name":"data","type":"bytes","indexed":false}]}
+]`)
+
+	retryableABI = mustABI(`[
+{"type":"function","name":"redeem","stateMutability":"nonpayable","inputs":[{"name":"ticketId","type":"bytes32"}],"outputs":[{"name":"","type":"bytes32"}]},
+{"type":"function","name":"getTimeout","stateMutability":"view","inputs":[{"name":"ticketId","type":"bytes32"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"event","name":"RedeemScheduled","anonymous":false,"inputs":[
+	{"name":"ticketId","type":"bytes32","indexed":true},{"name":"retryTxHash","type":"bytes32","indexed":true},
+	{"name":"sequenceNum","type":"uint64","indexed":true},{"name":"donatedGas","type":"uint64","indexed":false},
+	{"name":"gasDonor","type":"address","indexed":false},{"name":"maxRefund","type":"uint256","indexed":false},
+	{"name":"submissionFeeRefund","type":"uint256","indexed":false}]}
+]`)
+)
+
+func mustABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,239 @@
+package arbitrum
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+var (
+	alice = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bob   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func TestApplyL1ToL2Alias(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"0x0000000000000000000000000000000000000000", "0x1111000000000000000000000000000000001111"},
+		{"0xffffffffffffffffffffffffffffffffffffffff", "0x1111000000000000000000000000000000001110"}, // 溢出回绕
+	}
+	for _, tt := range tests {
+		if got := ApplyL1ToL2Alias(common.HexToAddress(tt.in)); got != common.HexToAddress(tt.want) {
+			t.Errorf("ApplyL1ToL2Alias(%s) = %s, want %s", tt.in, got.Hex(), tt.want)
+		}
+	}
+}
+
+// ticketReceipt 构造 createRetryableTicket 交易的 L1 收据
+func ticketReceipt(t *testing.T, net Network, messageNum int64, to common.Address, data []byte) *types.Receipt {
+	word := func(v *big.Int) []byte { return common.LeftPadBytes(v.Bytes(), 32) }
+	var packed []byte
+	for _, w := range [][]byte{
+		common.LeftPadBytes(to.Bytes(), 32), word(big.NewInt(1e15)), word(big.NewInt(2e15)), word(big.NewInt(1e14)),
+		common.LeftPadBytes(alice.Bytes(), 32), common.LeftPadBytes(bob.Bytes(), 32),
+		word(big.NewInt(50_000)), word(big.NewInt(2e7)), word(big.NewInt(int64(len(data)))), data,
+	} {
+		packed = append(packed, w...)
+	}
+	inboxData, err := inboxABI.Events["InboxMessageDelivered"].Inputs.NonIndexed().Pack(packed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bridgeData, err := bridgeABI.Events["MessageDelivered"].Inputs.NonIndexed().Pack(
+		net.Inbox, uint8(messageKindSubmitRetryable), alice, common.Hash{}, big.NewInt(1e9), uint64(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	num := common.BigToHash(big.NewInt(messageNum))
+	return &types.Receipt{Logs: []*types.Log{
+		{Address: net.Bridge, Topics: []common.Hash{bridgeABI.Events["MessageDelivered"].ID, num, {}}, Data: bridgeData},
+		{Address: net.Inbox, Topics: []common.Hash{inboxABI.Events["InboxMessageDelivered"].ID, num}, Data: inboxData},
+	}}
+}
+
+func TestParseTickets(t *testing.T) {
+	net := Networks["arbitrum-sepolia"]
+	tickets, err := ParseTickets(ticketReceipt(t, net, 42, bob, []byte{0xde, 0xad}), net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 1 {
+		t.Fatalf("got %d tickets, want 1", len(tickets))
+	}
+	tk := tickets[0]
+	if tk.From != alice || tk.To == nil || *tk.To != bob || tk.GasLimit != 50_000 || tk.Deposit.Int64() != 2e15 ||
+		tk.ExcessFeeRefund != alice || tk.CallValueRefund != bob || tk.L1BaseFee.Int64() != 1e9 ||
+		tk.L2ChainID.Uint64() != net.L2ChainID || common.Bytes2Hex(tk.Data) != "dead" {
+		t.Errorf("unexpected ticket %+v", tk)
+	}
+
+	// 票据 ID 取决于消息序号；目标为零地址时编码为空
+	other, _ := ParseTickets(ticketReceipt(t, net, 43, bob, []byte{0xde, 0xad}), net)
+	if other[0].ID() == tk.ID() {
+		t.Error("ticket ID does not depend on the message number")
+	}
+	creation, _ := ParseTickets(ticketReceipt(t, net, 42, common.Address{}, nil), net)
+	if creation[0].To != nil {
+		t.Errorf("zero target decoded as %s", creation[0].To.Hex())
+	}
+
+	// 其他网络的日志被忽略
+	if tickets, _ := ParseTickets(ticketReceipt(t, net, 42, bob, nil), Networks["arbitrum-one"]); len(tickets) != 0 {
+		t.Errorf("parsed %d tickets from another network", len(tickets))
+	}
+}
+
+// respond 按 ABI 编码方法的返回值
+func respond(t *testing.T, a abi.ABI, data []byte, values ...interface{}) []byte {
+	method, err := a.MethodById(data[:4])
+	if err != nil {
+		t.Fatalf("unexpected call %x", data[:4])
+	}
+	out, err := method.Outputs.Pack(values...)
+	if err != nil {
+		t.Fatalf("pack %s: %v", method.Name, err)
+	}
+	return out
+}
+
+func TestEstimate(t *testing.T) {
+	net := Networks["arbitrum-sepolia"]
+	client := &chain.ClientMock{
+		HeaderByNumberFunc: func(ctx context.Context, number *big.Int) (*types.Header, error) {
+			return &types.Header{BaseFee: big.NewInt(10)}, nil
+		},
+		CallContractFunc: func(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+			args, _ := inboxABI.Methods["calculateRetryableSubmissionFee"].Inputs.Unpack(msg.Data[4:])
+			// (1400 + 6 * dataLength) * baseFee
+			fee := new(big.Int).Mul(big.NewInt(1400+6*args[0].(*big.Int).Int64()), args[1].(*big.Int))
+			return respond(t, inboxABI, msg.Data, fee), nil
+		},
+		SuggestGasPriceFunc: func(ctx context.Context) (*big.Int, error) { return big.NewInt(1e7), nil },
+		EstimateGasFunc: func(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+			if *msg.To != NodeInterface {
+				t.Errorf("estimate sent to %s", msg.To.Hex())
+			}
+			return 30_000, nil
+		},
+	}
+	r := &Retryable{From: alice, To: bob, L2CallValue: big.NewInt(1000), Data: make([]byte, 100)}
+	if err := Estimate(context.Background(), client, client, net, r); err != nil {
+		t.Fatal(err)
+	}
+	if r.MaxSubmissionCost.Int64() != (1400+600)*10*submissionCostMultiplier || r.GasLimit != 30_000 || r.MaxFeePerGas.Int64() != 2e7 {
+		t.Errorf("unexpected estimate %+v", r)
+	}
+	if want := int64(1000 + 80_000 + 30_000*2e7); r.Deposit().Int64() != want {
+		t.Errorf("deposit = %s, want %d", r.Deposit(), want)
+	}
+
+	// 已设置的参数保持不变
+	r = &Retryable{From: alice, To: bob, GasLimit: 1, MaxFeePerGas: big.NewInt(1), MaxSubmissionCost: big.NewInt(1)}
+	if err := Estimate(context.Background(), &chain.ClientMock{}, &chain.ClientMock{}, net, r); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	ticket := common.HexToHash("0x01")
+	retry := common.HexToHash("0x02")
+	scheduled := &types.Log{
+		Address: ArbRetryableTx,
+		Topics:  []common.Hash{retryableABI.Events["RedeemScheduled"].ID, ticket, retry, {}},
+	}
+	tests := []struct {
+		name        string
+		created     bool
+		autoRedeem  bool // 创建交易中调度了自动兑现
+		retryStatus uint64
+		alive       bool // getTimeout 能查到票据
+		want        Status
+	}{
+		{"not created", false, false, 0, false, StatusNotCreated},
+		{"auto redeemed", true, true, types.ReceiptStatusSuccessful, false, StatusRedeemed},
+		{"auto redeem failed", true, true, types.ReceiptStatusFailed, true, StatusRedeemable},
+		{"expired", true, true, types.ReceiptStatusFailed, false, StatusExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &chain.ClientMock{
+				TransactionReceiptFunc: func(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+					switch {
+					case hash == ticket && tt.created:
+						r := &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)}
+						if tt.autoRedeem {
+							r.Logs = []*types.Log{scheduled}
+						}
+						return r, nil
+					case hash == retry:
+						return &types.Receipt{Status: tt.retryStatus}, nil
+					}
+					return nil, ethereum.NotFound
+				},
+				FilterLogsFunc: func(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+					return nil, nil
+				},
+				CallContractFunc: func(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+					if !tt.alive {
+						return nil, errors.New("execution reverted")
+					}
+					return respond(t, retryableABI, msg.Data, big.NewInt(1700000000)), nil
+				},
+			}
+			p, err := GetStatus(context.Background(), client, ticket)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Status != tt.want {
+				t.Errorf("status = %s, want %s", p.Status, tt.want)
+			}
+			if tt.want == StatusRedeemed && p.RedeemTx != retry {
+				t.Errorf("redeem tx = %s, want %s", p.RedeemTx, retry)
+			}
+		})
+	}
+}
+
+func TestEstimateGas(t *testing.T) {
+	client := &chain.ClientMock{CallContractFunc: func(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+		return respond(t, nodeInterfaceABI, msg.Data, uint64(400_000), uint64(379_000), big.NewInt(1e7), big.NewInt(3e10)), nil
+	}}
+	g, err := EstimateGas(context.Background(), client, ethereum.CallMsg{From: alice, To: &bob})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.L2() != 21_000 || g.Fee().Int64() != 400_000*1e7 {
+		t.Errorf("unexpected estimate %+v", g)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, tt := range []struct {
+		chainID int64
+		out     []byte
+		want    bool
+	}{
+		{42161, nil, true}, // 内置网络按链 ID 判断
+		{660279, common.LeftPadBytes(big.NewInt(660279).Bytes(), 32), true}, // Orbit 链
+		{1, nil, false}, // 没有 ArbSys，返回空结果
+	} {
+		client := &chain.ClientMock{
+			ChainIDFunc: func(ctx context.Context) (*big.Int, error) { return big.NewInt(tt.chainID), nil },
+			CallContractFunc: func(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+				if *msg.To != ArbSys {
+					t.Fatalf("call to %s", msg.To.Hex())
+				}
+				return tt.out, nil
+			},
+		}
+		if got, err := Detect(context.Background(), client); err != nil || got != tt.want {
+			t.Errorf("chain %d: Detect = %v, %v, want %v", tt.chainID, got, err, tt.want)
+		}
+	}
+}
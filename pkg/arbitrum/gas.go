@@ -0,0 +1,92 @@
+package arbitrum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// GasEstimate 是 Arbitrum 上一笔交易的 gas 估算。Arbitrum 把交易数据发布到 L1 的费用
+// 折算成额外的 L2 gas 计入 gas 用量，因此：
+//   - eth_estimateGas 的结果比 L1 上同样的调用大得多，且随 L1 gas 价格波动，
+//     应在发送前不久重新估算，不能复用旧值
+//   - 费用只由基础费决定，优先费会被忽略
+type GasEstimate struct {
+	Total             uint64   // 交易的 gas 上限，包含 L1 部分
+	ForL1             uint64   // 支付 L1 数据费用的部分
+	L2BaseFee         *big.Int // L2 基础费
+	L1BaseFeeEstimate *big.Int // 节点对 L1 基础费的估计
+}
+
+// L2 返回实际 L2 执行消耗的 gas
+func (g *GasEstimate) L2() uint64 {
+	return g.Total - g.ForL1
+}
+
+// Fee 返回按当前基础费计算的交易费用（wei）
+func (g *GasEstimate) Fee() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(g.Total), g.L2BaseFee)
+}
+
+// IsL2 报告 chainID 是否是内置网络中的 Arbitrum 链
+func IsL2(chainID uint64) bool {
+	for _, n := range Networks {
+		if n.L2ChainID == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect 报告 client 连接的链是否是 Arbitrum（Nitro）链：内置网络按链 ID 判断，
+// 其他链（如 Orbit 链）调用 ArbSys.arbChainID，只有 Arbitrum 上这个预编译合约会返回链 ID
+func Detect(ctx context.Context, client chain.Client) (bool, error) {
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if id.IsUint64() && IsL2(id.Uint64()) {
+		return true, nil
+	}
+	data, err := arbSysABI.Pack("arbChainID")
+	if err != nil {
+		return false, err
+	}
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &ArbSys, Data: data}, nil)
+	if err != nil {
+		// 其他链上调用没有代码的地址会成功并返回空结果，出错说明节点本身有问题
+		return false, fmt.Errorf("failed to call ArbSys: %w", err)
+	}
+	return len(out) == 32 && new(big.Int).SetBytes(out).Cmp(id) == 0, nil
+}
+
+// EstimateGas 用 NodeInterface.gasEstimateComponents 估算 msg 的 gas 并拆分出 L1 部分。
+// msg.To 为 nil 表示合约创建。
+func EstimateGas(ctx context.Context, l2 chain.Client, msg ethereum.CallMsg) (*GasEstimate, error) {
+	var to common.Address
+	if msg.To != nil {
+		to = *msg.To
+	}
+	data, err := nodeInterfaceABI.Pack("gasEstimateComponents", to, msg.To == nil, nonNil(msg.Data))
+	if err != nil {
+		return nil, err
+	}
+	out, err := l2.CallContract(ctx, ethereum.CallMsg{From: msg.From, To: &NodeInterface, Value: msg.Value, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gasEstimateComponents: %w", err)
+	}
+	values, err := nodeInterfaceABI.Unpack("gasEstimateComponents", out)
+	if err != nil {
+		return nil, fmt.Errorf("unpack gasEstimateComponents: %w", err)
+	}
+	return &GasEstimate{
+		Total:             values[0].(uint64),
+		ForL1:             values[1].(uint64),
+		L2BaseFee:         values[2].(*big.Int),
+		L1BaseFeeEstimate: values[3].(*big.Int),
+	}, nil
+}
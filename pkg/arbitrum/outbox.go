@@ -0,0 +1,55 @@
+package arbitrum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// L2ToL1Message 是 L2 交易通过 ArbSys 发往 L1 的消息（例如提款）
+type L2ToL1Message struct {
+	Caller      common.Address
+	Destination common.Address
+	Position    *big.Int // 在 Outbox 中的序号，执行后标记为已使用
+	Timestamp   time.Time
+	CallValue   *big.Int
+	Data        []byte
+}
+
+// ParseL2ToL1 解析 L2 收据中 ArbSys 发出的所有 L2ToL1Tx 事件
+func ParseL2ToL1(receipt *types.Receipt) ([]*L2ToL1Message, error) {
+	event := arbSysABI.Events["L2ToL1Tx"]
+	var msgs []*L2ToL1Message
+	for _, log := range receipt.Logs {
+		if log.Address != ArbSys || len(log.Topics) != 4 || log.Topics[0] != event.ID {
+			continue
+		}
+		values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unpack L2ToL1Tx: %w", err)
+		}
+		msgs = append(msgs, &L2ToL1Message{
+			Caller:      values[0].(common.Address),
+			Destination: common.BytesToAddress(log.Topics[1].Bytes()),
+			Position:    log.Topics[3].Big(),
+			Timestamp:   time.Unix(values[3].(*big.Int).Int64(), 0),
+			CallValue:   values[4].(*big.Int),
+			Data:        values[5].([]byte),
+		})
+	}
+	return msgs, nil
+}
+
+// Executed 查询消息是否已经在 L1 的 Outbox 上执行
+func Executed(ctx context.Context, l1 chain.Client, net Network, msg *L2ToL1Message) (bool, error) {
+	out, err := call(ctx, l1, net.Outbox, outboxABI, "isSpent", msg.Position)
+	if err != nil {
+		return false, err
+	}
+	return out[0].(bool), nil
+}
@@ -0,0 +1,370 @@
+package arbitrum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+const (
+	// submitRetryableTxType 是 L2 上创建票据的交易（ArbitrumSubmitRetryableTx）的类型字节
+	submitRetryableTxType = 0x69
+	// messageKindSubmitRetryable 是 Bridge MessageDelivered 事件中可重试票据消息的类型
+	messageKindSubmitRetryable = 9
+
+	// submissionCostMultiplier 放大提交费用以应对 L1 基础费上涨，多付部分退还给 ExcessFeeRefund
+	submissionCostMultiplier = 4
+	// maxFeeMultiplier 放大 L2 gas 价格，保证票据在 L2 基础费上涨时仍能自动兑现
+	maxFeeMultiplier = 2
+)
+
+// Retryable 是创建可重试票据的参数。GasLimit、MaxFeePerGas 和 MaxSubmissionCost 为 nil
+// 或零时由 Estimate 填入；GasLimit 为零的票据不会自动兑现，需要之后手动 Redeem。
+type Retryable struct {
+	From            common.Address // L1 上的发送者
+	To              common.Address // L2 上的调用目标
+	L2CallValue     *big.Int       // 随 L2 调用发送的 wei
+	Data            []byte
+	ExcessFeeRefund common.Address // 退还多付的 gas 和提交费用，零值时使用 From
+	CallValueRefund common.Address // 票据过期或被取消时退还 L2CallValue，零值时使用 From
+
+	GasLimit          uint64
+	MaxFeePerGas      *big.Int
+	MaxSubmissionCost *big.Int
+}
+
+// Deposit 返回创建票据需要随交易发送的 ETH：调用金额、提交费用和 L2 执行费用上限之和
+func (r *Retryable) Deposit() *big.Int {
+	d := new(big.Int).Mul(new(big.Int).SetUint64(r.GasLimit), orZero(r.MaxFeePerGas))
+	d.Add(d, orZero(r.L2CallValue))
+	return d.Add(d, orZero(r.MaxSubmissionCost))
+}
+
+func (r *Retryable) refundAddresses() (excess, callValue common.Address) {
+	excess, callValue = r.ExcessFeeRefund, r.CallValueRefund
+	if excess == (common.Address{}) {
+		excess = r.From
+	}
+	if callValue == (common.Address{}) {
+		callValue = r.From
+	}
+	return excess, callValue
+}
+
+// SubmissionCost 返回在 L2 上保存 dataLength 字节票据数据所需的费用，它按当前 L1 基础费计算
+func SubmissionCost(ctx context.Context, l1 chain.Client, net Network, dataLength int) (*big.Int, error) {
+	header, err := l1.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get L1 header: %w", err)
+	}
+	if header.BaseFee == nil {
+		return nil, errors.New("L1 block has no base fee")
+	}
+	out, err := call(ctx, l1, net.Inbox, inboxABI, "calculateRetryableSubmissionFee", big.NewInt(int64(dataLength)), header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// Estimate 填入 r 中未设置的费用参数：
+//   - MaxSubmissionCost：Inbox 计算的提交费用乘以 submissionCostMultiplier
+//   - GasLimit：NodeInterface.estimateRetryableTicket 模拟票据在 L2 上执行所需的 gas。
+//     票据交易来自 L1，不包含普通 L2 交易估算中折算的 L1 数据费用
+//   - MaxFeePerGas：L2 gas 价格乘以 maxFeeMultiplier。Arbitrum 忽略优先费，只需要费用上限
+func Estimate(ctx context.Context, l1, l2 chain.Client, net Network, r *Retryable) error {
+	if r.MaxSubmissionCost == nil || r.MaxSubmissionCost.Sign() == 0 {
+		cost, err := SubmissionCost(ctx, l1, net, len(r.Data))
+		if err != nil {
+			return err
+		}
+		r.MaxSubmissionCost = cost.Mul(cost, big.NewInt(submissionCostMultiplier))
+	}
+	if r.MaxFeePerGas == nil || r.MaxFeePerGas.Sign() == 0 {
+		price, err := l2.SuggestGasPrice(ctx)
+		if err != nil {
+			return fmt.Errorf("get L2 gas price: %w", err)
+		}
+		r.MaxFeePerGas = price.Mul(price, big.NewInt(maxFeeMultiplier))
+	}
+	if r.GasLimit == 0 {
+		excess, callValue := r.refundAddresses()
+		// deposit 只用于模拟时给发送者记账，多给 1 ETH 避免余额不足导致估算失败
+		deposit := new(big.Int).Add(orZero(r.L2CallValue), big.NewInt(1e18))
+		data, err := nodeInterfaceABI.Pack("estimateRetryableTicket", r.From, deposit, r.To, orZero(r.L2CallValue), excess, callValue, nonNil(r.Data))
+		if err != nil {
+			return err
+		}
+		gas, err := l2.EstimateGas(ctx, ethereum.CallMsg{From: r.From, To: &NodeInterface, Data: data})
+		if err != nil {
+			return fmt.Errorf("estimateRetryableTicket: %w", err)
+		}
+		r.GasLimit = gas
+	}
+	return nil
+}
+
+// CreateRetryableTicket 调用 Inbox.createRetryableTicket 创建票据，随交易发送 r.Deposit()
+func CreateRetryableTicket(ctx context.Context, l1 chain.Client, net Network, auth *bind.TransactOpts, r *Retryable) (*types.Transaction, error) {
+	if r.MaxSubmissionCost == nil || r.MaxFeePerGas == nil {
+		return nil, errors.New("retryable fees are not set, call Estimate first")
+	}
+	excess, callValue := r.refundAddresses()
+	inbox := bind.NewBoundContract(net.Inbox, inboxABI, l1, l1, l1)
+	opts := *auth
+	opts.Context = ctx
+	opts.Value = r.Deposit()
+	tx, err := inbox.Transact(&opts, "createRetryableTicket", r.To, orZero(r.L2CallValue), r.MaxSubmissionCost,
+		excess, callValue, new(big.Int).SetUint64(r.GasLimit), r.MaxFeePerGas, nonNil(r.Data))
+	if err != nil {
+		return nil, fmt.Errorf("createRetryableTicket: %w", err)
+	}
+	return tx, nil
+}
+
+// Ticket 是从 L1 收据中解析出的票据，字段顺序与 L2 上的 ArbitrumSubmitRetryableTx 一致
+type Ticket struct {
+	L2ChainID         *big.Int
+	MessageNum        common.Hash // Inbox 消息序号
+	From              common.Address
+	L1BaseFee         *big.Int
+	Deposit           *big.Int
+	MaxFeePerGas      *big.Int
+	GasLimit          uint64
+	To                *common.Address `rlp:"nil"` // 目标为零地址时为 nil
+	L2CallValue       *big.Int
+	CallValueRefund   common.Address
+	MaxSubmissionCost *big.Int
+	ExcessFeeRefund   common.Address
+	Data              []byte
+}
+
+// ID 返回票据 ID，也就是 L2 上创建票据的交易哈希：keccak256(0x69 || rlp(tx))
+func (t *Ticket) ID() common.Hash {
+	enc, _ := rlp.EncodeToBytes(t)
+	return crypto.Keccak256Hash(append([]byte{submitRetryableTxType}, enc...))
+}
+
+// ParseTickets 解析 L1 收据中通过 net 的 Inbox 创建的所有可重试票据
+func ParseTickets(receipt *types.Receipt, net Network) ([]*Ticket, error) {
+	delivered := bridgeABI.Events["MessageDelivered"]
+	inboxEvent := inboxABI.Events["InboxMessageDelivered"]
+
+	// Bridge 的 MessageDelivered 事件提供发送者（合约发送者已经别名化）和 L1 基础费
+	type message struct {
+		sender  common.Address
+		baseFee *big.Int
+	}
+	messages := make(map[common.Hash]message)
+	for _, log := range receipt.Logs {
+		if log.Address != net.Bridge || len(log.Topics) != 3 || log.Topics[0] != delivered.ID {
+			continue
+		}
+		values, err := delivered.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unpack MessageDelivered: %w", err)
+		}
+		if values[1].(uint8) != messageKindSubmitRetryable {
+			continue
+		}
+		messages[log.Topics[1]] = message{sender: values[2].(common.Address), baseFee: values[4].(*big.Int)}
+	}
+
+	var tickets []*Ticket
+	for _, log := range receipt.Logs {
+		if log.Address != net.Inbox || len(log.Topics) != 2 || log.Topics[0] != inboxEvent.ID {
+			continue
+		}
+		msg, ok := messages[log.Topics[1]]
+		if !ok {
+			continue // 不是可重试票据消息，例如 depositEth
+		}
+		values, err := inboxEvent.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unpack InboxMessageDelivered: %w", err)
+		}
+		t, err := decodeMessageData(values[0].([]byte))
+		if err != nil {
+			return nil, err
+		}
+		t.L2ChainID = new(big.Int).SetUint64(net.L2ChainID)
+		t.MessageNum = log.Topics[1]
+		t.From = msg.sender
+		t.L1BaseFee = msg.baseFee
+		tickets = append(tickets, t)
+	}
+	return tickets, nil
+}
+
+// decodeMessageData 解析 Inbox 打包的票据消息：
+// abi.encodePacked(to, l2CallValue, deposit, maxSubmissionCost, excessFeeRefund,
+// callValueRefund, gasLimit, maxFeePerGas, data.length, data)，每个字段 32 字节
+func decodeMessageData(b []byte) (*Ticket, error) {
+	const fixed = 9 * 32
+	if len(b) < fixed {
+		return nil, fmt.Errorf("retryable message too short: %d bytes", len(b))
+	}
+	word := func(i int) []byte { return b[i*32 : (i+1)*32] }
+	num := func(i int) *big.Int { return new(big.Int).SetBytes(word(i)) }
+	if n := num(8); !n.IsUint64() || n.Uint64() != uint64(len(b)-fixed) {
+		return nil, fmt.Errorf("retryable message data length %s does not match %d bytes", n, len(b)-fixed)
+	}
+	if !num(6).IsUint64() {
+		return nil, fmt.Errorf("retryable gas limit %s overflows uint64", num(6))
+	}
+	t := &Ticket{
+		L2CallValue:       num(1),
+		Deposit:           num(2),
+		MaxSubmissionCost: num(3),
+		ExcessFeeRefund:   common.BytesToAddress(word(4)),
+		CallValueRefund:   common.BytesToAddress(word(5)),
+		GasLimit:          num(6).Uint64(),
+		MaxFeePerGas:      num(7),
+		Data:              common.CopyBytes(b[fixed:]),
+	}
+	if to := common.BytesToAddress(word(0)); to != (common.Address{}) {
+		t.To = &to
+	}
+	return t, nil
+}
+
+// Status 是票据所处的阶段
+type Status string
+
+const (
+	StatusNotCreated     Status = "not-created"     // L2 上还没有创建票据的交易
+	StatusCreationFailed Status = "creation-failed" // 创建失败，通常是押金不足以支付提交费用
+	StatusRedeemable     Status = "redeemable"      // 票据存在但尚未成功执行，可以手动 Redeem
+	StatusRedeemed       Status = "redeemed"        // L2 调用已成功执行
+	StatusExpired        Status = "expired"         // 超过有效期未兑现，L2CallValue 已退还
+)
+
+// Progress 是票据的状态以及相关的 L2 交易
+type Progress struct {
+	Status   Status
+	TicketID common.Hash
+	RedeemTx common.Hash // 成功执行票据的 L2 交易
+	Timeout  time.Time   // 票据可兑现的截止时间，仅在 StatusRedeemable 时设置
+}
+
+// GetStatus 查询票据在 L2 上的状态。先检查创建交易中的自动兑现，失败时再查找之后的手动兑现
+func GetStatus(ctx context.Context, l2 chain.Client, ticketID common.Hash) (*Progress, error) {
+	p := &Progress{TicketID: ticketID}
+	receipt, err := l2.TransactionReceipt(ctx, ticketID)
+	if errors.Is(err, ethereum.NotFound) {
+		p.Status = StatusNotCreated
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get ticket receipt: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		p.Status = StatusCreationFailed
+		return p, nil
+	}
+
+	redeemed, err := findRedeem(ctx, l2, ticketID, receipt.Logs)
+	if err != nil {
+		return nil, err
+	}
+	if redeemed == (common.Hash{}) {
+		logs, err := l2.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: receipt.BlockNumber,
+			Addresses: []common.Address{ArbRetryableTx},
+			Topics:    [][]common.Hash{{retryableABI.Events["RedeemScheduled"].ID}, {ticketID}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get RedeemScheduled logs: %w", err)
+		}
+		ptrs := make([]*types.Log, len(logs))
+		for i := range logs {
+			ptrs[i] = &logs[i]
+		}
+		if redeemed, err = findRedeem(ctx, l2, ticketID, ptrs); err != nil {
+			return nil, err
+		}
+	}
+	if redeemed != (common.Hash{}) {
+		p.Status, p.RedeemTx = StatusRedeemed, redeemed
+		return p, nil
+	}
+
+	// 票据在成功兑现或过期后被删除，getTimeout 会回滚
+	out, err := call(ctx, l2, ArbRetryableTx, retryableABI, "getTimeout", ticketID)
+	if err != nil {
+		p.Status = StatusExpired
+		return p, nil
+	}
+	p.Status = StatusRedeemable
+	p.Timeout = time.Unix(out[0].(*big.Int).Int64(), 0)
+	return p, nil
+}
+
+// findRedeem 检查日志中调度的兑现交易，返回第一个执行成功的交易哈希
+func findRedeem(ctx context.Context, l2 chain.Client, ticketID common.Hash, logs []*types.Log) (common.Hash, error) {
+	event := retryableABI.Events["RedeemScheduled"]
+	for _, log := range logs {
+		if log.Address != ArbRetryableTx || len(log.Topics) != 4 || log.Topics[0] != event.ID || log.Topics[1] != ticketID {
+			continue
+		}
+		retryTx := log.Topics[2]
+		receipt, err := l2.TransactionReceipt(ctx, retryTx)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("get redeem receipt %s: %w", retryTx, err)
+		}
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			return retryTx, nil
+		}
+	}
+	return common.Hash{}, nil
+}
+
+// Redeem 在 L2 上手动兑现票据，gas 由调用者支付
+func Redeem(ctx context.Context, l2 chain.Client, auth *bind.TransactOpts, ticketID common.Hash) (*types.Transaction, error) {
+	contract := bind.NewBoundContract(ArbRetryableTx, retryableABI, l2, l2, l2)
+	opts := *auth
+	opts.Context = ctx
+	tx, err := contract.Transact(&opts, "redeem", ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("redeem: %w", err)
+	}
+	return tx, nil
+}
+
+// call 调用只读方法并返回解码后的结果
+func call(ctx context.Context, client chain.Client, addr common.Address, contractABI abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	var out []interface{}
+	contract := bind.NewBoundContract(addr, contractABI, client, nil, nil)
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, method, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return out, nil
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+func nonNil(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
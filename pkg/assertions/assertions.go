@@ -0,0 +1,133 @@
+// Package assertions 提供针对交易结果的断言：回滚原因、事件和余额变化。断言以 error
+// 返回失败原因而不依赖 testing.T，因此既可以在 Go 测试中使用，也可以作为脚本的检查步骤。
+package assertions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/decode"
+)
+
+// ExpectRevert 断言 err 是一次合约回滚，且回滚原因等于 reason。reason 为空时只要求发生回滚。
+// 优先解码节点返回的 Error(string) 回滚数据，没有数据时退回到匹配错误消息。
+func ExpectRevert(err error, reason string) error {
+	if err == nil {
+		return fmt.Errorf("expected revert %q, got success", reason)
+	}
+	got, ok := RevertReason(err)
+	if !ok {
+		return fmt.Errorf("expected revert %q, got error: %v", reason, err)
+	}
+	if reason != "" && got != reason {
+		return fmt.Errorf("expected revert %q, got revert %q", reason, got)
+	}
+	return nil
+}
+
+// RevertReason 从 eth_call / eth_estimateGas 的错误中提取回滚原因。
+// 第二个返回值表示 err 是否为回滚。
+func RevertReason(err error) (string, bool) {
+	var dataErr interface{ ErrorData() interface{} }
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if reason, unpackErr := abi.UnpackRevert(common.FromHex(data)); unpackErr == nil {
+				return reason, true
+			}
+			// 自定义错误或空回滚：返回原始数据
+			if strings.Contains(err.Error(), "revert") {
+				return data, true
+			}
+		}
+	}
+	msg := err.Error()
+	const prefix = "execution reverted"
+	i := strings.Index(msg, prefix)
+	if i < 0 {
+		return "", false
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(msg[i+len(prefix):], ":"), " "), true
+}
+
+// ExpectEvent 断言 receipt 中至少有一条名为 name 的事件，其参数依次等于 args。
+// args 为空时只检查事件存在；某个位置传 nil 表示不检查该参数。
+// 数值可以用 *big.Int 或任意 Go 整数，地址应使用 common.Address。
+func ExpectEvent(receipt *types.Receipt, contract *abi.ABI, name string, args ...interface{}) error {
+	event, ok := contract.Events[name]
+	if !ok {
+		return fmt.Errorf("event %s not found in ABI", name)
+	}
+	if len(args) > 0 && len(args) != len(event.Inputs) {
+		return fmt.Errorf("event %s has %d arguments, got %d expected values", name, len(event.Inputs), len(args))
+	}
+
+	var found []string
+	for _, log := range receipt.Logs {
+		if len(log.Topics) == 0 || log.Topics[0] != event.ID {
+			continue
+		}
+		decoded, err := decode.Log(contract, log)
+		if err != nil {
+			return fmt.Errorf("decode %s log %d: %w", name, log.Index, err)
+		}
+		mismatch := compareArgs(decoded.Args, args)
+		if mismatch == "" {
+			return nil
+		}
+		found = append(found, mismatch)
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("no %s event in transaction %s", name, receipt.TxHash.Hex())
+	}
+	return fmt.Errorf("no %s event with expected arguments in transaction %s: %s", name, receipt.TxHash.Hex(), strings.Join(found, "; "))
+}
+
+// compareArgs 返回第一个不匹配参数的描述，全部匹配时返回空字符串
+func compareArgs(got []decode.Arg, want []interface{}) string {
+	for i, w := range want {
+		if w == nil {
+			continue
+		}
+		if expected := decode.FormatValue(w); !reflect.DeepEqual(expected, got[i].Value) {
+			return fmt.Sprintf("%s = %v, want %v", got[i].Name, got[i].Value, expected)
+		}
+	}
+	return ""
+}
+
+// ExpectBalanceDelta 断言 account 的余额在 receipt 所在区块中变化了 want（负数表示减少）。
+// 余额变化包括同一区块中的所有交易；如果 account 是交易发送方，变化中包含手续费，见 Fee。
+func ExpectBalanceDelta(ctx context.Context, client chain.Client, account common.Address, receipt *types.Receipt, want *big.Int) error {
+	if receipt.BlockNumber == nil || receipt.BlockNumber.Sign() == 0 {
+		return errors.New("receipt has no block number")
+	}
+	prev := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	before, err := client.BalanceAt(ctx, account, prev)
+	if err != nil {
+		return fmt.Errorf("get balance at block %s: %w", prev, err)
+	}
+	after, err := client.BalanceAt(ctx, account, receipt.BlockNumber)
+	if err != nil {
+		return fmt.Errorf("get balance at block %s: %w", receipt.BlockNumber, err)
+	}
+	if got := new(big.Int).Sub(after, before); got.Cmp(want) != 0 {
+		return fmt.Errorf("balance of %s changed by %s wei in block %s, want %s", account.Hex(), got, receipt.BlockNumber, want)
+	}
+	return nil
+}
+
+// Fee 返回交易实际支付的手续费（gasUsed * effectiveGasPrice）
+func Fee(receipt *types.Receipt) *big.Int {
+	if receipt.EffectiveGasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+}
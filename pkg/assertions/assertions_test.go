@@ -0,0 +1,135 @@
+package assertions
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// rpcError 模拟节点返回的带回滚数据的 JSON-RPC 错误
+type rpcError struct {
+	msg  string
+	data string
+}
+
+func (e *rpcError) Error() string          { return e.msg }
+func (e *rpcError) ErrorData() interface{} { return e.data }
+
+// revertData 按 Error(string) 编码回滚原因
+func revertData(t *testing.T, reason string) string {
+	t.Helper()
+	typ, err := abi.NewType("string", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packed, err := abi.Arguments{{Type: typ}}.Pack(reason)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hexutil.Encode(append(common.FromHex("0x08c379a0"), packed...))
+}
+
+func TestExpectRevert(t *testing.T) {
+	withData := &rpcError{msg: "execution reverted: Counter: underflow", data: revertData(t, "Counter: underflow")}
+	tests := []struct {
+		name    string
+		err     error
+		reason  string
+		wantErr string
+	}{
+		{"revert data", withData, "Counter: underflow", ""},
+		{"wrapped revert data", errors.Join(errors.New("estimate gas"), withData), "Counter: underflow", ""},
+		{"message only", errors.New("execution reverted: Counter: underflow"), "Counter: underflow", ""},
+		{"any reason", withData, "", ""},
+		{"wrong reason", withData, "Counter: overflow", `got revert "Counter: underflow"`},
+		{"success", nil, "Counter: underflow", "got success"},
+		{"not a revert", errors.New("connection refused"), "Counter: underflow", "got error: connection refused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ExpectRevert(tt.err, tt.reason)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ExpectRevert: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ExpectRevert error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+const transferABI = `[{"type":"event","name":"Transfer","anonymous":false,"inputs":[
+	{"name":"from","type":"address","indexed":true},
+	{"name":"to","type":"address","indexed":true},
+	{"name":"value","type":"uint256","indexed":false}]}]`
+
+func TestExpectEvent(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(transferABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	data, err := parsed.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := &types.Receipt{Logs: []*types.Log{{
+		Topics: []common.Hash{parsed.Events["Transfer"].ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   data,
+	}}}
+
+	if err := ExpectEvent(receipt, &parsed, "Transfer"); err != nil {
+		t.Errorf("existence check: %v", err)
+	}
+	if err := ExpectEvent(receipt, &parsed, "Transfer", from, to, 42); err != nil {
+		t.Errorf("full match: %v", err)
+	}
+	if err := ExpectEvent(receipt, &parsed, "Transfer", nil, to, big.NewInt(42)); err != nil {
+		t.Errorf("wildcard match: %v", err)
+	}
+	if err := ExpectEvent(receipt, &parsed, "Transfer", from, to, 43); err == nil || !strings.Contains(err.Error(), "value = 42, want 43") {
+		t.Errorf("value mismatch error = %v", err)
+	}
+	if err := ExpectEvent(receipt, &parsed, "Transfer", from); err == nil {
+		t.Error("wrong argument count accepted")
+	}
+	if err := ExpectEvent(&types.Receipt{}, &parsed, "Transfer"); err == nil || !strings.Contains(err.Error(), "no Transfer event") {
+		t.Errorf("missing event error = %v", err)
+	}
+	if err := ExpectEvent(receipt, &parsed, "Approval"); err == nil {
+		t.Error("unknown event accepted")
+	}
+}
+
+func TestExpectBalanceDelta(t *testing.T) {
+	balances := map[uint64]int64{9: 1000, 10: 700}
+	client := &chain.ClientMock{
+		BalanceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+			return big.NewInt(balances[blockNumber.Uint64()]), nil
+		},
+	}
+	receipt := &types.Receipt{BlockNumber: big.NewInt(10), GasUsed: 21000, EffectiveGasPrice: big.NewInt(2)}
+	account := common.HexToAddress("0x01")
+
+	if err := ExpectBalanceDelta(context.Background(), client, account, receipt, big.NewInt(-300)); err != nil {
+		t.Errorf("ExpectBalanceDelta: %v", err)
+	}
+	if err := ExpectBalanceDelta(context.Background(), client, account, receipt, big.NewInt(-299)); err == nil {
+		t.Error("wrong delta accepted")
+	}
+	if got := Fee(receipt); got.Int64() != 42000 {
+		t.Errorf("Fee = %s, want 42000", got)
+	}
+}
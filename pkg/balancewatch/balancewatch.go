@@ -0,0 +1,171 @@
+// Package balancewatch 定期检查一组地址的 ETH 和 ERC-20 代币余额，在余额跌破配置的阈值以及
+// 重新回到阈值以上时报告，例如提醒给 Sepolia 水龙头账户补充余额。
+package balancewatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/units"
+)
+
+// Config 是余额监视配置，通常从 JSON 文件加载
+type Config struct {
+	Interval string    `json:"interval,omitempty"` // 检查间隔，如 "5m"，为空时由调用方决定
+	Accounts []Account `json:"accounts"`
+}
+
+// Account 是一个被监视的地址
+type Account struct {
+	Name    string      `json:"name,omitempty"`
+	Address string      `json:"address"`       // 地址或地址簿中的名称
+	Min     string      `json:"min,omitempty"` // ETH 余额阈值，如 "0.5eth"，为空表示不检查 ETH
+	Tokens  []TokenRule `json:"tokens,omitempty"`
+}
+
+// TokenRule 是一个代币的余额阈值
+type TokenRule struct {
+	Token string `json:"token"` // 代币合约地址或地址簿中的名称
+	Min   string `json:"min"`   // 按代币小数位数解析，如 "100" 或 "100 USDC"
+}
+
+// LoadConfig 读取 JSON 配置文件，文件中的 ${VAR} 会被替换为环境变量
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal([]byte(os.ExpandEnv(string(data))), &cfg); err != nil {
+		return nil, fmt.Errorf("parse balance watch config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// IntervalOr 返回配置的检查间隔，未配置时返回 def
+func (c *Config) IntervalOr(def time.Duration) (time.Duration, error) {
+	if c.Interval == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid interval %q", c.Interval)
+	}
+	return d, nil
+}
+
+// Check 是一项已解析的余额检查
+type Check struct {
+	Name    string
+	Account common.Address
+	Token   *erc20.Token // nil 表示原生代币 ETH
+	Min     *big.Int     // 最小单位
+}
+
+// Format 把最小单位的数量格式化为带单位的字符串
+func (c Check) Format(amount *big.Int) string {
+	if c.Token == nil {
+		return units.FormatEther(amount, 6) + " ETH"
+	}
+	return c.Token.Format(amount)
+}
+
+// Label 返回用于显示的名称，没有配置名称时为地址
+func (c Check) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Account.Hex()
+}
+
+// Checks 把配置解析为余额检查：resolve 把地址或名称解析为地址，代币的小数位数和 symbol 从链上读取
+func (c *Config) Checks(ctx context.Context, client chain.Client, resolve func(string) (common.Address, error)) ([]Check, error) {
+	var checks []Check
+	tokens := make(map[common.Address]*erc20.Token)
+	for _, a := range c.Accounts {
+		account, err := resolve(a.Address)
+		if err != nil {
+			return nil, fmt.Errorf("account %q: %w", a.Address, err)
+		}
+		if a.Min != "" {
+			min, err := units.ParseAmount(a.Min)
+			if err != nil {
+				return nil, fmt.Errorf("account %q: invalid min: %w", a.Address, err)
+			}
+			checks = append(checks, Check{Name: a.Name, Account: account, Min: min})
+		}
+		for _, r := range a.Tokens {
+			addr, err := resolve(r.Token)
+			if err != nil {
+				return nil, fmt.Errorf("account %q: token %q: %w", a.Address, r.Token, err)
+			}
+			token := tokens[addr]
+			if token == nil {
+				if token, err = erc20.Load(ctx, client, addr); err != nil {
+					return nil, err
+				}
+				tokens[addr] = token
+			}
+			min, err := token.ParseAmount(r.Min)
+			if err != nil {
+				return nil, fmt.Errorf("account %q: token %q: invalid min: %w", a.Address, r.Token, err)
+			}
+			checks = append(checks, Check{Name: a.Name, Account: account, Token: token, Min: min})
+		}
+	}
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("no balances to watch: set min or tokens for at least one account")
+	}
+	return checks, nil
+}
+
+// Status 是一项检查的结果
+type Status struct {
+	Check   Check
+	Balance *big.Int // 读取失败时为 nil
+	Err     error
+	// Changed 表示与上一次检查相比余额跌破或回到了阈值以上；第一次检查时余额不足也算作变化
+	Changed bool
+}
+
+// Low 报告余额是否低于阈值
+func (s Status) Low() bool { return s.Balance != nil && s.Balance.Cmp(s.Check.Min) < 0 }
+
+// Watcher 记录每项检查上一次是否低于阈值，以便只在状态变化时告警。不是并发安全的
+type Watcher struct {
+	checks []Check
+	low    []bool
+	seen   []bool
+}
+
+// New 创建检查 checks 的 Watcher
+func New(checks []Check) *Watcher {
+	return &Watcher{checks: checks, low: make([]bool, len(checks)), seen: make([]bool, len(checks))}
+}
+
+// Poll 读取所有余额，结果与检查一一对应。读取失败的检查保留之前的状态，不算作变化
+func (w *Watcher) Poll(ctx context.Context, client chain.Client) []Status {
+	statuses := make([]Status, len(w.checks))
+	for i, c := range w.checks {
+		s := Status{Check: c}
+		if c.Token == nil {
+			s.Balance, s.Err = client.BalanceAt(ctx, c.Account, nil)
+		} else {
+			s.Balance, s.Err = c.Token.BalanceOf(ctx, client, c.Account)
+		}
+		if s.Err == nil {
+			low := s.Low()
+			s.Changed = low != w.low[i] || (!w.seen[i] && low)
+			w.low[i], w.seen[i] = low, true
+		}
+		statuses[i] = s
+	}
+	return statuses
+}
@@ -0,0 +1,140 @@
+package balancewatch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/units"
+)
+
+var (
+	faucet = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	usdc   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// fakeChain 返回可修改的 ETH 余额和 6 位小数的 USDC 余额
+type fakeChain struct {
+	eth, tokens *big.Int
+	fail        bool
+}
+
+func (f *fakeChain) client() *chain.ClientMock {
+	return &chain.ClientMock{
+		BalanceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+			if f.fail {
+				return nil, errors.New("connection refused")
+			}
+			return f.eth, nil
+		},
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			method, err := erc20.ABI.MethodById(call.Data[:4])
+			if err != nil {
+				return nil, err
+			}
+			switch method.Name {
+			case "balanceOf":
+				return method.Outputs.Pack(f.tokens)
+			case "decimals":
+				return method.Outputs.Pack(uint8(6))
+			case "symbol":
+				return method.Outputs.Pack("USDC")
+			}
+			return nil, errors.New("execution reverted")
+		},
+	}
+}
+
+func eth(s string) *big.Int {
+	v, err := units.ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func resolve(s string) (common.Address, error) {
+	switch s {
+	case "faucet":
+		return faucet, nil
+	case "usdc":
+		return usdc, nil
+	}
+	if !common.IsHexAddress(s) {
+		return common.Address{}, errors.New("unknown name")
+	}
+	return common.HexToAddress(s), nil
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "balances.json")
+	t.Setenv("FAUCET_ADDR", faucet.Hex())
+	os.WriteFile(path, []byte(`{"interval":"10m","accounts":[{"name":"faucet","address":"${FAUCET_ADDR}","min":"0.5eth"}]}`), 0o644)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Accounts[0].Address != faucet.Hex() {
+		t.Errorf("address = %q, want %s", cfg.Accounts[0].Address, faucet.Hex())
+	}
+	if d, err := cfg.IntervalOr(0); err != nil || d.Minutes() != 10 {
+		t.Errorf("IntervalOr = %v, %v", d, err)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	f := &fakeChain{eth: eth("1eth"), tokens: big.NewInt(500_000_000)}
+	client := f.client()
+	cfg := &Config{Accounts: []Account{{
+		Name: "faucet", Address: "faucet", Min: "0.5eth",
+		Tokens: []TokenRule{{Token: "usdc", Min: "100 USDC"}},
+	}}}
+	checks, err := cfg.Checks(context.Background(), client, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 2 || checks[1].Token.Symbol != "USDC" || checks[1].Min.Int64() != 100_000_000 {
+		t.Fatalf("checks = %+v", checks)
+	}
+	w := New(checks)
+
+	steps := []struct {
+		eth     *big.Int
+		tokens  int64
+		fail    bool
+		changed [2]bool
+	}{
+		{eth("1eth"), 500_000_000, false, [2]bool{false, false}},
+		{eth("0.4eth"), 500_000_000, false, [2]bool{true, false}}, // ETH 跌破 0.5
+		{eth("0.3eth"), 50_000_000, false, [2]bool{false, true}},  // 仍然不足不重复报告，USDC 跌破
+		{nil, 50_000_000, true, [2]bool{false, false}},            // 读取失败保留状态
+		{eth("2eth"), 50_000_000, false, [2]bool{true, false}},    // ETH 恢复
+	}
+	for i, s := range steps {
+		f.eth, f.tokens, f.fail = s.eth, big.NewInt(s.tokens), s.fail
+		got := w.Poll(context.Background(), client)
+		for j, st := range got {
+			if st.Changed != s.changed[j] {
+				t.Errorf("step %d, check %d: Changed = %v, want %v (balance %v, err %v)", i, j, st.Changed, s.changed[j], st.Balance, st.Err)
+			}
+		}
+	}
+	if got := checks[0].Format(eth("0.4eth")); got != "0.400000 ETH" {
+		t.Errorf("Format = %q", got)
+	}
+}
+
+func TestFirstPollLow(t *testing.T) {
+	f := &fakeChain{eth: big.NewInt(1)}
+	w := New([]Check{{Account: faucet, Min: eth("1eth")}})
+	if s := w.Poll(context.Background(), f.client())[0]; !s.Changed || !s.Low() {
+		t.Errorf("first poll below threshold: %+v, want Changed and Low", s)
+	}
+}
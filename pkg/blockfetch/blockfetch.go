@@ -0,0 +1,283 @@
+// Package blockfetch 用有限个并发 worker 下载一段区块范围内的区块头、区块体和收据，按区块号顺序输出，
+// 是导出和索引功能的基础。单个区块失败时按指数退避重试（负载均衡的节点偶尔会对刚出的区块返回 not found），
+// 重试次数用完后整个下载停止并报告错误。为了限制内存，已下载但尚未按顺序输出的区块不超过 Window 个。
+package blockfetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/retry"
+)
+
+// Client 是下载用到的节点接口，*ethclient.Client 满足该接口。收据使用 eth_getBlockReceipts 按区块哈希读取
+type Client interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	BlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*types.Receipt, error)
+}
+
+// Options 是下载参数，零值使用默认值
+type Options struct {
+	Workers     int  // 并发 worker 数，默认 8
+	Window      int  // 已下载未输出的区块上限，默认 Workers 的 4 倍
+	Attempts    int  // 每个区块最多尝试的次数，默认 3
+	HeadersOnly bool // 只下载区块头，不下载交易
+	Receipts    bool // 同时下载收据
+
+	// Retry 控制重试间隔，默认从 500 毫秒起翻倍，最长 10 秒
+	Retry retry.Policy
+
+	// OnRetry 在某个区块重试前调用（可选）
+	OnRetry func(number uint64, attempt int, err error)
+	// OnProgress 在每个区块输出后调用（可选），在输出 goroutine 中执行，不应阻塞
+	OnProgress func(Progress)
+}
+
+func (o Options) withDefaults() Options {
+	if o.Workers <= 0 {
+		o.Workers = 8
+	}
+	if o.Window <= 0 {
+		o.Window = 4 * o.Workers
+	}
+	o.Window = max(o.Window, o.Workers)
+	if o.Attempts <= 0 {
+		o.Attempts = 3
+	}
+	if o.Retry.BaseDelay <= 0 {
+		o.Retry.BaseDelay = 500 * time.Millisecond
+	}
+	if o.Retry.MaxDelay <= 0 {
+		o.Retry.MaxDelay = 10 * time.Second
+	}
+	return o
+}
+
+// Result 是一个区块的下载结果。HeadersOnly 时 Block 为 nil，未要求收据时 Receipts 为 nil
+type Result struct {
+	Number   uint64
+	Header   *types.Header
+	Block    *types.Block
+	Receipts []*types.Receipt
+}
+
+// Progress 是下载进度
+type Progress struct {
+	Done, Total uint64
+	Elapsed     time.Duration
+}
+
+// Rate 返回每秒输出的区块数
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Done) / p.Elapsed.Seconds()
+}
+
+// Remaining 按当前速率估计剩余时间，还没有速率时返回 0
+func (p Progress) Remaining() time.Duration {
+	rate := p.Rate()
+	if rate == 0 {
+		return 0
+	}
+	return time.Duration(float64(p.Total-p.Done) / rate * float64(time.Second))
+}
+
+// Stream 是按区块号顺序输出的下载结果
+type Stream struct {
+	// C 按区块号从小到大输出结果，下载完成、出错或 ctx 结束时关闭
+	C <-chan Result
+
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	err    error
+}
+
+// Err 返回下载停止的原因，全部完成时为 nil。应在 C 关闭后调用
+func (s *Stream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Stop 停止下载，之后 C 会被关闭。调用方不再读取 C 时必须调用 Stop，否则后台 goroutine 不会退出
+func (s *Stream) Stop() { s.cancel() }
+
+func (s *Stream) fail(err error) {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	s.mu.Unlock()
+	s.cancel()
+}
+
+// Fetch 开始下载 [from, to] 范围内的区块
+func Fetch(ctx context.Context, client Client, from, to uint64, opts Options) *Stream {
+	opts = opts.withDefaults()
+	ctx, cancel := context.WithCancel(ctx)
+	out := make(chan Result)
+	s := &Stream{C: out, cancel: cancel}
+	if from > to {
+		close(out)
+		s.fail(fmt.Errorf("invalid block range %d-%d", from, to))
+		return s
+	}
+
+	// slots 限制已分配但尚未输出的区块数，输出一个区块后释放一个
+	slots := make(chan struct{}, opts.Window)
+	jobs := make(chan uint64)
+	done := make(chan Result, opts.Workers)
+
+	go func() {
+		defer close(jobs)
+		for n := from; ; n++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- n:
+			case <-ctx.Done():
+				return
+			}
+			if n == to {
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				r, err := fetchWithRetry(ctx, client, n, opts)
+				if err != nil {
+					if ctx.Err() == nil {
+						s.fail(err)
+					}
+					return
+				}
+				select {
+				case done <- r:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	go func() {
+		defer close(out)
+		defer cancel()
+		start := time.Now()
+		total := to - from + 1
+		pending := make(map[uint64]Result)
+		next := from
+		for r := range done {
+			pending[r.Number] = r
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				select {
+				case out <- r:
+				case <-ctx.Done():
+					s.fail(ctx.Err())
+					return
+				}
+				<-slots
+				if opts.OnProgress != nil {
+					opts.OnProgress(Progress{Done: next - from + 1, Total: total, Elapsed: time.Since(start)})
+				}
+				if next == to {
+					return
+				}
+				next++
+			}
+		}
+		// done 在全部输出前关闭，说明下载出错或 ctx 已结束
+		if ctx.Err() != nil {
+			s.fail(ctx.Err())
+		}
+	}()
+	return s
+}
+
+// fetchWithRetry 下载区块 n，失败时按 opts 重试
+func fetchWithRetry(ctx context.Context, client Client, n uint64, opts Options) (Result, error) {
+	for attempt := 1; ; attempt++ {
+		r, err := fetch(ctx, client, n, opts)
+		if err == nil || ctx.Err() != nil {
+			return r, err
+		}
+		if attempt >= opts.Attempts {
+			return Result{}, fmt.Errorf("block %d: %w (after %d attempts)", n, err, attempt)
+		}
+		if opts.OnRetry != nil {
+			opts.OnRetry(n, attempt+1, err)
+		}
+		timer := time.NewTimer(opts.Retry.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Result{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// errNotFound 表示客户端返回了空区块而没有报错（ethclient 此时返回 ethereum.NotFound，同样会重试）
+var errNotFound = errors.New("block not found")
+
+func fetch(ctx context.Context, client Client, n uint64, opts Options) (Result, error) {
+	r := Result{Number: n}
+	number := new(big.Int).SetUint64(n)
+	if opts.HeadersOnly {
+		header, err := client.HeaderByNumber(ctx, number)
+		if err != nil {
+			return r, err
+		}
+		if header == nil {
+			return r, errNotFound
+		}
+		r.Header = header
+	} else {
+		block, err := client.BlockByNumber(ctx, number)
+		if err != nil {
+			return r, err
+		}
+		if block == nil {
+			return r, errNotFound
+		}
+		r.Block, r.Header = block, block.Header()
+	}
+	if opts.Receipts {
+		receipts, err := client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(r.Header.Hash(), true))
+		if err != nil {
+			return r, fmt.Errorf("receipts: %w", err)
+		}
+		if r.Block != nil && len(receipts) != len(r.Block.Transactions()) {
+			return r, fmt.Errorf("got %d receipts for %d transactions", len(receipts), len(r.Block.Transactions()))
+		}
+		r.Receipts = receipts
+	}
+	return r, nil
+}
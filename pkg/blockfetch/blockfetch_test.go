@@ -0,0 +1,151 @@
+package blockfetch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/retry"
+)
+
+// fakeClient 以随机延迟返回区块，区块 n 含有 n%3 笔交易。failures 中的区块在前几次请求时返回错误，
+// active 记录同时进行的请求数
+type fakeClient struct {
+	mu       sync.Mutex
+	failures map[uint64]int
+	calls    map[uint64]int
+
+	active, peak atomic.Int32
+}
+
+func newFakeClient(failures map[uint64]int) *fakeClient {
+	return &fakeClient{failures: failures, calls: make(map[uint64]int)}
+}
+
+func (c *fakeClient) enter(n uint64) error {
+	if a := c.active.Add(1); a > c.peak.Load() {
+		c.peak.Store(a)
+	}
+	defer c.active.Add(-1)
+	time.Sleep(time.Duration(rand.IntN(2000)) * time.Microsecond)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[n]++
+	if c.calls[n] <= c.failures[n] {
+		return errors.New("header not found")
+	}
+	return nil
+}
+
+func header(n uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(n), Difficulty: new(big.Int), Extra: []byte{byte(n)}}
+}
+
+func (c *fakeClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := c.enter(number.Uint64()); err != nil {
+		return nil, err
+	}
+	return header(number.Uint64()), nil
+}
+
+func (c *fakeClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	n := number.Uint64()
+	if err := c.enter(n); err != nil {
+		return nil, err
+	}
+	txs := make([]*types.Transaction, n%3)
+	for i := range txs {
+		txs[i] = types.NewTx(&types.LegacyTx{Nonce: uint64(i)})
+	}
+	return types.NewBlockWithHeader(header(n)).WithBody(types.Body{Transactions: txs}), nil
+}
+
+func (c *fakeClient) BlockReceipts(ctx context.Context, ref rpc.BlockNumberOrHash) ([]*types.Receipt, error) {
+	hash, _ := ref.Hash()
+	for n := uint64(0); n < 1000; n++ {
+		if header(n).Hash() == hash {
+			return make([]*types.Receipt, n%3), nil
+		}
+	}
+	return nil, errors.New("unknown block")
+}
+
+var fast = retry.Policy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+func TestFetchInOrder(t *testing.T) {
+	client := newFakeClient(map[uint64]int{105: 1, 140: 2})
+	var retries atomic.Int32
+	var last Progress
+	s := Fetch(context.Background(), client, 100, 199, Options{
+		Workers:    6,
+		Receipts:   true,
+		Retry:      fast,
+		OnRetry:    func(uint64, int, error) { retries.Add(1) },
+		OnProgress: func(p Progress) { last = p },
+	})
+	want := uint64(100)
+	for r := range s.C {
+		if r.Number != want || r.Block.NumberU64() != want {
+			t.Fatalf("got block %d, want %d", r.Number, want)
+		}
+		if len(r.Receipts) != int(want%3) {
+			t.Errorf("block %d: %d receipts, want %d", want, len(r.Receipts), want%3)
+		}
+		want++
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want != 200 {
+		t.Errorf("stream ended at block %d, want 200", want)
+	}
+	if retries.Load() != 3 {
+		t.Errorf("%d retries, want 3", retries.Load())
+	}
+	if last.Done != 100 || last.Total != 100 {
+		t.Errorf("last progress = %+v", last)
+	}
+	if peak := client.peak.Load(); peak > 6 || peak < 2 {
+		t.Errorf("peak concurrency = %d, want between 2 and 6", peak)
+	}
+}
+
+func TestFetchHeadersOnly(t *testing.T) {
+	s := Fetch(context.Background(), newFakeClient(nil), 7, 7, Options{HeadersOnly: true})
+	var got []Result
+	for r := range s.C {
+		got = append(got, r)
+	}
+	if s.Err() != nil || len(got) != 1 || got[0].Block != nil || got[0].Header.Number.Uint64() != 7 {
+		t.Errorf("got %+v, %v", got, s.Err())
+	}
+}
+
+func TestFetchGivesUp(t *testing.T) {
+	s := Fetch(context.Background(), newFakeClient(map[uint64]int{15: 100}), 10, 50, Options{Workers: 4, Attempts: 2, Retry: fast})
+	var last uint64
+	for r := range s.C {
+		last = r.Number
+	}
+	if err := s.Err(); err == nil || last != 14 {
+		t.Errorf("stream stopped after block %d with %v, want block 14 and an error for block 15", last, err)
+	}
+}
+
+func TestFetchStop(t *testing.T) {
+	s := Fetch(context.Background(), newFakeClient(nil), 0, 999, Options{})
+	<-s.C
+	s.Stop()
+	for range s.C {
+	}
+	if !errors.Is(s.Err(), context.Canceled) {
+		t.Errorf("Err after Stop = %v, want context canceled", s.Err())
+	}
+}
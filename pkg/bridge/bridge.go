@@ -0,0 +1,197 @@
+// Package bridge 跟踪经过官方桥的跨链转账：给定 L1 或 L2 上的桥交易，找到对应的消息并
+// 报告它在 OP Stack 或 Arbitrum 桥上所处的阶段。
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/arbitrum"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/opstack"
+)
+
+// Direction 是转账方向
+type Direction string
+
+const (
+	Deposit    Direction = "deposit"    // L1 → L2
+	Withdrawal Direction = "withdrawal" // L2 → L1
+)
+
+// Stage 是转账所处的阶段
+type Stage string
+
+const (
+	StageInitiated       Stage = "initiated"        // 源链交易已确认，消息尚未送达或证明
+	StageProven          Stage = "proven"           // 提款已证明且挑战期结束，可以在 L1 上最终确认
+	StageChallengePeriod Stage = "challenge-period" // 提款在等待挑战期结束
+	StageFinalized       Stage = "finalized"        // 提款已在 L1 上执行
+	StageRelayed         Stage = "relayed"          // 存款已在 L2 上执行
+	StageFailed          Stage = "failed"           // 消息无法送达，见 Detail
+)
+
+// Transfer 是一次跨链转账的状态
+type Transfer struct {
+	Network   string
+	Direction Direction
+	Stage     Stage
+	Detail    string      // 对当前阶段和下一步操作的说明
+	SourceTx  common.Hash // 发起转账的交易
+	TargetTx  common.Hash // 目标链上执行消息的交易，未知时为零值
+	ReadyAt   time.Time   // 挑战期结束的时间，仅在 StageChallengePeriod 时设置
+}
+
+// ErrNotFound 表示交易在 L1 和 L2 上都不存在
+var ErrNotFound = errors.New("transaction not found on L1 or L2")
+
+// findReceipt 依次在 L1 和 L2 上查找交易收据，onL1 表示交易所在的链
+func findReceipt(ctx context.Context, l1, l2 chain.Client, tx common.Hash) (receipt *types.Receipt, onL1 bool, err error) {
+	for _, c := range []struct {
+		client chain.Client
+		onL1   bool
+	}{{l1, true}, {l2, false}} {
+		receipt, err := c.client.TransactionReceipt(ctx, tx)
+		if err == nil {
+			return receipt, c.onL1, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, false, fmt.Errorf("get receipt %s: %w", tx.Hex(), err)
+		}
+	}
+	return nil, false, ErrNotFound
+}
+
+// receiptOrNil 查询交易收据，交易尚不存在时返回 nil
+func receiptOrNil(ctx context.Context, client chain.Client, tx common.Hash) (*types.Receipt, error) {
+	receipt, err := client.TransactionReceipt(ctx, tx)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get receipt %s: %w", tx.Hex(), err)
+	}
+	return receipt, nil
+}
+
+// TrackOPStack 跟踪 OP Stack 桥交易：L1 上的 OptimismPortal 存款或 L2 上的 MessagePasser 提款
+func TrackOPStack(ctx context.Context, l1, l2 chain.Client, net opstack.Network, tx common.Hash) (*Transfer, error) {
+	receipt, onL1, err := findReceipt(ctx, l1, l2, tx)
+	if err != nil {
+		return nil, err
+	}
+	t := &Transfer{Network: net.Name, SourceTx: tx}
+	if onL1 {
+		deposits, err := opstack.ParseDeposits(receipt, net.Portal)
+		if err != nil {
+			return nil, err
+		}
+		if len(deposits) == 0 {
+			return nil, fmt.Errorf("%s is not an OptimismPortal deposit", tx.Hex())
+		}
+		t.Direction, t.TargetTx = Deposit, deposits[0].Hash()
+		l2Receipt, err := receiptOrNil(ctx, l2, t.TargetTx)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case l2Receipt == nil:
+			t.Stage, t.Detail = StageInitiated, "waiting for the L2 deposit transaction"
+		case l2Receipt.Status != types.ReceiptStatusSuccessful:
+			// 失败的存款仍然会把 mint 的 ETH 记到发送者账上
+			t.Stage, t.Detail = StageFailed, "deposit reverted on L2; the minted ETH stays with the sender"
+		default:
+			t.Stage = StageRelayed
+		}
+		return t, nil
+	}
+
+	w, err := opstack.ParseWithdrawal(receipt)
+	if err != nil {
+		return nil, err
+	}
+	t.Direction = Withdrawal
+	// 证明通常由提款发起人自己提交
+	p, err := opstack.GetStatus(ctx, l1, net, w, w.Sender)
+	if err != nil {
+		return nil, err
+	}
+	switch p.Status {
+	case opstack.StatusWaitingToProve:
+		t.Stage, t.Detail = StageInitiated, fmt.Sprintf("waiting for a dispute game covering L2 block %d", w.L2Block)
+	case opstack.StatusReadyToProve:
+		t.Stage, t.Detail = StageInitiated, "ready to prove on L1"
+	case opstack.StatusChallengePeriod:
+		t.Stage, t.ReadyAt = StageChallengePeriod, p.FinalizableAt
+	case opstack.StatusReadyToFinalize:
+		t.Stage, t.Detail = StageProven, "ready to finalize on L1"
+	case opstack.StatusFinalized:
+		t.Stage = StageFinalized
+	}
+	return t, nil
+}
+
+// TrackArbitrum 跟踪 Arbitrum 桥交易：L1 上创建的可重试票据或 L2 上通过 ArbSys 发出的消息
+func TrackArbitrum(ctx context.Context, l1, l2 chain.Client, net arbitrum.Network, tx common.Hash) (*Transfer, error) {
+	receipt, onL1, err := findReceipt(ctx, l1, l2, tx)
+	if err != nil {
+		return nil, err
+	}
+	t := &Transfer{Network: net.Name, SourceTx: tx}
+	if onL1 {
+		tickets, err := arbitrum.ParseTickets(receipt, net)
+		if err != nil {
+			return nil, err
+		}
+		if len(tickets) == 0 {
+			return nil, fmt.Errorf("%s does not create a retryable ticket", tx.Hex())
+		}
+		t.Direction = Deposit
+		p, err := arbitrum.GetStatus(ctx, l2, tickets[0].ID())
+		if err != nil {
+			return nil, err
+		}
+		switch p.Status {
+		case arbitrum.StatusNotCreated:
+			t.Stage, t.Detail = StageInitiated, "waiting for the ticket on L2"
+		case arbitrum.StatusRedeemable:
+			t.Stage, t.Detail = StageInitiated, fmt.Sprintf("auto-redeem failed; redeem ticket %s before %s", p.TicketID.Hex(), p.Timeout.Format(time.RFC3339))
+		case arbitrum.StatusRedeemed:
+			t.Stage, t.TargetTx = StageRelayed, p.RedeemTx
+		case arbitrum.StatusCreationFailed:
+			t.Stage, t.Detail = StageFailed, "ticket creation failed on L2"
+		case arbitrum.StatusExpired:
+			t.Stage, t.Detail = StageFailed, "ticket expired without being redeemed"
+		}
+		return t, nil
+	}
+
+	msgs, err := arbitrum.ParseL2ToL1(receipt)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("%s does not send an L2-to-L1 message", tx.Hex())
+	}
+	t.Direction = Withdrawal
+	executed, err := arbitrum.Executed(ctx, l1, net, msgs[0])
+	if err != nil {
+		return nil, err
+	}
+	readyAt := msgs[0].Timestamp.Add(net.ConfirmPeriod)
+	switch {
+	case executed:
+		t.Stage = StageFinalized
+	case time.Now().Before(readyAt):
+		t.Stage, t.ReadyAt = StageChallengePeriod, readyAt
+	default:
+		// Arbitrum 没有单独的证明步骤，包含消息的断言被确认后即可在 Outbox 上执行
+		t.Stage, t.Detail = StageProven, "executable through the L1 Outbox once its assertion is confirmed"
+	}
+	return t, nil
+}
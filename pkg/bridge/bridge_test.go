@@ -0,0 +1,145 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/arbitrum"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/opstack"
+)
+
+var (
+	alice = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	txA   = common.HexToHash("0xaa")
+)
+
+var eventsABI, _ = abi.JSON(strings.NewReader(`[
+{"type":"event","name":"TransactionDeposited","anonymous":false,"inputs":[
+	{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},
+	{"name":"version","type":"uint256","indexed":true},{"name":"opaqueData","type":"bytes","indexed":false}]},
+{"type":"event","name":"L2ToL1Tx","anonymous":false,"inputs":[
+	{"name":"caller","type":"address","indexed":false},{"name":"destination","type":"address","indexed":true},
+	{"name":"hash","type":"uint256","indexed":true},{"name":"position","type":"uint256","indexed":true},
+	{"name":"arbBlockNum","type":"uint256","indexed":false},{"name":"ethBlockNum","type":"uint256","indexed":false},
+	{"name":"timestamp","type":"uint256","indexed":false},{"name":"callvalue","type":"uint256","indexed":false},
+	{"name":"data","type":"bytes","indexed":false}]},
+{"type":"function","name":"isSpent","stateMutability":"view","inputs":[{"name":"index","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`))
+
+// receipts 是按交易哈希返回收据的模拟节点
+func receipts(byHash map[common.Hash]*types.Receipt) *chain.ClientMock {
+	return &chain.ClientMock{TransactionReceiptFunc: func(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+		if r, ok := byHash[hash]; ok {
+			return r, nil
+		}
+		return nil, ethereum.NotFound
+	}}
+}
+
+func TestTrackOPStackDeposit(t *testing.T) {
+	net := opstack.Networks["op-sepolia"]
+	opaque := append(make([]byte, 64), make([]byte, 9)...) // mint、value 为 0，gas 为 0，isCreation=false
+	data, err := eventsABI.Events["TransactionDeposited"].Inputs.NonIndexed().Pack(opaque)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l1Receipt := &types.Receipt{Logs: []*types.Log{{
+		Address: net.Portal,
+		Topics:  []common.Hash{eventsABI.Events["TransactionDeposited"].ID, common.BytesToHash(alice.Bytes()), common.BytesToHash(alice.Bytes()), {}},
+		Data:    data,
+	}}}
+	deposits, _ := opstack.ParseDeposits(l1Receipt, net.Portal)
+	l2Tx := deposits[0].Hash()
+
+	l1 := receipts(map[common.Hash]*types.Receipt{txA: l1Receipt})
+	transfer, err := TrackOPStack(context.Background(), l1, receipts(nil), net, txA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transfer.Direction != Deposit || transfer.Stage != StageInitiated || transfer.TargetTx != l2Tx {
+		t.Errorf("unexpected transfer %+v", transfer)
+	}
+
+	l2 := receipts(map[common.Hash]*types.Receipt{l2Tx: {Status: types.ReceiptStatusSuccessful}})
+	if transfer, err = TrackOPStack(context.Background(), l1, l2, net, txA); err != nil {
+		t.Fatal(err)
+	}
+	if transfer.Stage != StageRelayed {
+		t.Errorf("stage = %s, want %s", transfer.Stage, StageRelayed)
+	}
+}
+
+func TestTrackArbitrumWithdrawal(t *testing.T) {
+	net := arbitrum.Networks["arbitrum-one"]
+	event := eventsABI.Events["L2ToL1Tx"]
+	withdrawal := func(sent time.Time) *types.Receipt {
+		data, err := event.Inputs.NonIndexed().Pack(alice, big.NewInt(1), big.NewInt(2), big.NewInt(sent.Unix()), big.NewInt(1e15), []byte{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &types.Receipt{Logs: []*types.Log{{
+			Address: arbitrum.ArbSys,
+			Topics:  []common.Hash{event.ID, common.BytesToHash(alice.Bytes()), {}, common.BigToHash(big.NewInt(77))},
+			Data:    data,
+		}}}
+	}
+	outbox := func(spent bool) *chain.ClientMock {
+		m := receipts(nil)
+		m.CallContractFunc = func(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+			if *msg.To != net.Outbox {
+				t.Errorf("call to %s, want outbox", msg.To.Hex())
+			}
+			args, _ := eventsABI.Methods["isSpent"].Inputs.Unpack(msg.Data[4:])
+			if args[0].(*big.Int).Int64() != 77 {
+				t.Errorf("isSpent(%s), want position 77", args[0])
+			}
+			return eventsABI.Methods["isSpent"].Outputs.Pack(spent)
+		}
+		return m
+	}
+
+	tests := []struct {
+		name  string
+		sent  time.Time
+		spent bool
+		want  Stage
+		ready bool
+	}{
+		{"challenge period", time.Now().Add(-time.Hour), false, StageChallengePeriod, true},
+		{"confirmable", time.Now().Add(-8 * 24 * time.Hour), false, StageProven, false},
+		{"executed", time.Now().Add(-8 * 24 * time.Hour), true, StageFinalized, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l2 := receipts(map[common.Hash]*types.Receipt{txA: withdrawal(tt.sent)})
+			transfer, err := TrackArbitrum(context.Background(), outbox(tt.spent), l2, net, txA)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transfer.Direction != Withdrawal || transfer.Stage != tt.want || transfer.ReadyAt.IsZero() == tt.ready {
+				t.Errorf("unexpected transfer %+v", transfer)
+			}
+		})
+	}
+}
+
+func TestTrackNotFound(t *testing.T) {
+	_, err := TrackOPStack(context.Background(), receipts(nil), receipts(nil), opstack.Networks["optimism"], txA)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+	// 不是桥交易的 L1 交易
+	l1 := receipts(map[common.Hash]*types.Receipt{txA: {}})
+	if _, err := TrackArbitrum(context.Background(), l1, receipts(nil), arbitrum.Networks["arbitrum-one"], txA); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want a not-a-bridge-transaction error", err)
+	}
+}
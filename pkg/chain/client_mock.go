@@ -0,0 +1,1003 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package chain
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"sync"
+)
+
+// Ensure, that ClientMock does implement Client.
+// If this is not the case, regenerate this file with moq.
+var _ Client = &ClientMock{}
+
+// ClientMock is a mock implementation of Client.
+//
+//	func TestSomethingThatUsesClient(t *testing.T) {
+//
+//		// make and configure a mocked Client
+//		mockedClient := &ClientMock{
+//			BalanceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+//				panic("mock out the BalanceAt method")
+//			},
+//			BlockByNumberFunc: func(ctx context.Context, number *big.Int) (*types.Block, error) {
+//				panic("mock out the BlockByNumber method")
+//			},
+//			BlockNumberFunc: func(ctx context.Context) (uint64, error) {
+//				panic("mock out the BlockNumber method")
+//			},
+//			CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+//				panic("mock out the CallContract method")
+//			},
+//			ChainIDFunc: func(ctx context.Context) (*big.Int, error) {
+//				panic("mock out the ChainID method")
+//			},
+//			CodeAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+//				panic("mock out the CodeAt method")
+//			},
+//			EstimateGasFunc: func(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+//				panic("mock out the EstimateGas method")
+//			},
+//			FeeHistoryFunc: func(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+//				panic("mock out the FeeHistory method")
+//			},
+//			FilterLogsFunc: func(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+//				panic("mock out the FilterLogs method")
+//			},
+//			HeaderByNumberFunc: func(ctx context.Context, number *big.Int) (*types.Header, error) {
+//				panic("mock out the HeaderByNumber method")
+//			},
+//			NonceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+//				panic("mock out the NonceAt method")
+//			},
+//			PendingCodeAtFunc: func(ctx context.Context, account common.Address) ([]byte, error) {
+//				panic("mock out the PendingCodeAt method")
+//			},
+//			PendingNonceAtFunc: func(ctx context.Context, account common.Address) (uint64, error) {
+//				panic("mock out the PendingNonceAt method")
+//			},
+//			SendTransactionFunc: func(ctx context.Context, tx *types.Transaction) error {
+//				panic("mock out the SendTransaction method")
+//			},
+//			SubscribeFilterLogsFunc: func(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+//				panic("mock out the SubscribeFilterLogs method")
+//			},
+//			SuggestGasPriceFunc: func(ctx context.Context) (*big.Int, error) {
+//				panic("mock out the SuggestGasPrice method")
+//			},
+//			SuggestGasTipCapFunc: func(ctx context.Context) (*big.Int, error) {
+//				panic("mock out the SuggestGasTipCap method")
+//			},
+//			TransactionByHashFunc: func(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+//				panic("mock out the TransactionByHash method")
+//			},
+//			TransactionReceiptFunc: func(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+//				panic("mock out the TransactionReceipt method")
+//			},
+//		}
+//
+//		// use mockedClient in code that requires Client
+//		// and then make assertions.
+//
+//	}
+type ClientMock struct {
+	// BalanceAtFunc mocks the BalanceAt method.
+	BalanceAtFunc func(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+
+	// BlockByNumberFunc mocks the BlockByNumber method.
+	BlockByNumberFunc func(ctx context.Context, number *big.Int) (*types.Block, error)
+
+	// BlockNumberFunc mocks the BlockNumber method.
+	BlockNumberFunc func(ctx context.Context) (uint64, error)
+
+	// CallContractFunc mocks the CallContract method.
+	CallContractFunc func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+
+	// ChainIDFunc mocks the ChainID method.
+	ChainIDFunc func(ctx context.Context) (*big.Int, error)
+
+	// CodeAtFunc mocks the CodeAt method.
+	CodeAtFunc func(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+
+	// EstimateGasFunc mocks the EstimateGas method.
+	EstimateGasFunc func(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+
+	// FeeHistoryFunc mocks the FeeHistory method.
+	FeeHistoryFunc func(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+
+	// FilterLogsFunc mocks the FilterLogs method.
+	FilterLogsFunc func(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+
+	// HeaderByNumberFunc mocks the HeaderByNumber method.
+	HeaderByNumberFunc func(ctx context.Context, number *big.Int) (*types.Header, error)
+
+	// NonceAtFunc mocks the NonceAt method.
+	NonceAtFunc func(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+
+	// PendingCodeAtFunc mocks the PendingCodeAt method.
+	PendingCodeAtFunc func(ctx context.Context, account common.Address) ([]byte, error)
+
+	// PendingNonceAtFunc mocks the PendingNonceAt method.
+	PendingNonceAtFunc func(ctx context.Context, account common.Address) (uint64, error)
+
+	// SendTransactionFunc mocks the SendTransaction method.
+	SendTransactionFunc func(ctx context.Context, tx *types.Transaction) error
+
+	// SubscribeFilterLogsFunc mocks the SubscribeFilterLogs method.
+	SubscribeFilterLogsFunc func(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+
+	// SuggestGasPriceFunc mocks the SuggestGasPrice method.
+	SuggestGasPriceFunc func(ctx context.Context) (*big.Int, error)
+
+	// SuggestGasTipCapFunc mocks the SuggestGasTipCap method.
+	SuggestGasTipCapFunc func(ctx context.Context) (*big.Int, error)
+
+	// TransactionByHashFunc mocks the TransactionByHash method.
+	TransactionByHashFunc func(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
+
+	// TransactionReceiptFunc mocks the TransactionReceipt method.
+	TransactionReceiptFunc func(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// BalanceAt holds details about calls to the BalanceAt method.
+		BalanceAt []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Account is the account argument value.
+			Account common.Address
+			// BlockNumber is the blockNumber argument value.
+			BlockNumber *big.Int
+		}
+		// BlockByNumber holds details about calls to the BlockByNumber method.
+		BlockByNumber []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Number is the number argument value.
+			Number *big.Int
+		}
+		// BlockNumber holds details about calls to the BlockNumber method.
+		BlockNumber []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// CallContract holds details about calls to the CallContract method.
+		CallContract []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Call is the call argument value.
+			Call ethereum.CallMsg
+			// BlockNumber is the blockNumber argument value.
+			BlockNumber *big.Int
+		}
+		// ChainID holds details about calls to the ChainID method.
+		ChainID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// CodeAt holds details about calls to the CodeAt method.
+		CodeAt []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Account is the account argument value.
+			Account common.Address
+			// BlockNumber is the blockNumber argument value.
+			BlockNumber *big.Int
+		}
+		// EstimateGas holds details about calls to the EstimateGas method.
+		EstimateGas []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Call is the call argument value.
+			Call ethereum.CallMsg
+		}
+		// FeeHistory holds details about calls to the FeeHistory method.
+		FeeHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// BlockCount is the blockCount argument value.
+			BlockCount uint64
+			// LastBlock is the lastBlock argument value.
+			LastBlock *big.Int
+			// RewardPercentiles is the rewardPercentiles argument value.
+			RewardPercentiles []float64
+		}
+		// FilterLogs holds details about calls to the FilterLogs method.
+		FilterLogs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Q is the q argument value.
+			Q ethereum.FilterQuery
+		}
+		// HeaderByNumber holds details about calls to the HeaderByNumber method.
+		HeaderByNumber []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Number is the number argument value.
+			Number *big.Int
+		}
+		// NonceAt holds details about calls to the NonceAt method.
+		NonceAt []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Account is the account argument value.
+			Account common.Address
+			// BlockNumber is the blockNumber argument value.
+			BlockNumber *big.Int
+		}
+		// PendingCodeAt holds details about calls to the PendingCodeAt method.
+		PendingCodeAt []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Account is the account argument value.
+			Account common.Address
+		}
+		// PendingNonceAt holds details about calls to the PendingNonceAt method.
+		PendingNonceAt []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Account is the account argument value.
+			Account common.Address
+		}
+		// SendTransaction holds details about calls to the SendTransaction method.
+		SendTransaction []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Tx is the tx argument value.
+			Tx *types.Transaction
+		}
+		// SubscribeFilterLogs holds details about calls to the SubscribeFilterLogs method.
+		SubscribeFilterLogs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Q is the q argument value.
+			Q ethereum.FilterQuery
+			// Ch is the ch argument value.
+			Ch chan<- types.Log
+		}
+		// SuggestGasPrice holds details about calls to the SuggestGasPrice method.
+		SuggestGasPrice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// SuggestGasTipCap holds details about calls to the SuggestGasTipCap method.
+		SuggestGasTipCap []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// TransactionByHash holds details about calls to the TransactionByHash method.
+		TransactionByHash []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TxHash is the txHash argument value.
+			TxHash common.Hash
+		}
+		// TransactionReceipt holds details about calls to the TransactionReceipt method.
+		TransactionReceipt []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// TxHash is the txHash argument value.
+			TxHash common.Hash
+		}
+	}
+	lockBalanceAt           sync.RWMutex
+	lockBlockByNumber       sync.RWMutex
+	lockBlockNumber         sync.RWMutex
+	lockCallContract        sync.RWMutex
+	lockChainID             sync.RWMutex
+	lockCodeAt              sync.RWMutex
+	lockEstimateGas         sync.RWMutex
+	lockFeeHistory          sync.RWMutex
+	lockFilterLogs          sync.RWMutex
+	lockHeaderByNumber      sync.RWMutex
+	lockNonceAt             sync.RWMutex
+	lockPendingCodeAt       sync.RWMutex
+	lockPendingNonceAt      sync.RWMutex
+	lockSendTransaction     sync.RWMutex
+	lockSubscribeFilterLogs sync.RWMutex
+	lockSuggestGasPrice     sync.RWMutex
+	lockSuggestGasTipCap    sync.RWMutex
+	lockTransactionByHash   sync.RWMutex
+	lockTransactionReceipt  sync.RWMutex
+}
+
+// BalanceAt calls BalanceAtFunc.
+func (mock *ClientMock) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	if mock.BalanceAtFunc == nil {
+		panic("ClientMock.BalanceAtFunc: method is nil but Client.BalanceAt was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Account     common.Address
+		BlockNumber *big.Int
+	}{
+		Ctx:         ctx,
+		Account:     account,
+		BlockNumber: blockNumber,
+	}
+	mock.lockBalanceAt.Lock()
+	mock.calls.BalanceAt = append(mock.calls.BalanceAt, callInfo)
+	mock.lockBalanceAt.Unlock()
+	return mock.BalanceAtFunc(ctx, account, blockNumber)
+}
+
+// BalanceAtCalls gets all the calls that were made to BalanceAt.
+// Check the length with:
+//
+//	len(mockedClient.BalanceAtCalls())
+func (mock *ClientMock) BalanceAtCalls() []struct {
+	Ctx         context.Context
+	Account     common.Address
+	BlockNumber *big.Int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Account     common.Address
+		BlockNumber *big.Int
+	}
+	mock.lockBalanceAt.RLock()
+	calls = mock.calls.BalanceAt
+	mock.lockBalanceAt.RUnlock()
+	return calls
+}
+
+// BlockByNumber calls BlockByNumberFunc.
+func (mock *ClientMock) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	if mock.BlockByNumberFunc == nil {
+		panic("ClientMock.BlockByNumberFunc: method is nil but Client.BlockByNumber was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Number *big.Int
+	}{
+		Ctx:    ctx,
+		Number: number,
+	}
+	mock.lockBlockByNumber.Lock()
+	mock.calls.BlockByNumber = append(mock.calls.BlockByNumber, callInfo)
+	mock.lockBlockByNumber.Unlock()
+	return mock.BlockByNumberFunc(ctx, number)
+}
+
+// BlockByNumberCalls gets all the calls that were made to BlockByNumber.
+// Check the length with:
+//
+//	len(mockedClient.BlockByNumberCalls())
+func (mock *ClientMock) BlockByNumberCalls() []struct {
+	Ctx    context.Context
+	Number *big.Int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Number *big.Int
+	}
+	mock.lockBlockByNumber.RLock()
+	calls = mock.calls.BlockByNumber
+	mock.lockBlockByNumber.RUnlock()
+	return calls
+}
+
+// BlockNumber calls BlockNumberFunc.
+func (mock *ClientMock) BlockNumber(ctx context.Context) (uint64, error) {
+	if mock.BlockNumberFunc == nil {
+		panic("ClientMock.BlockNumberFunc: method is nil but Client.BlockNumber was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockBlockNumber.Lock()
+	mock.calls.BlockNumber = append(mock.calls.BlockNumber, callInfo)
+	mock.lockBlockNumber.Unlock()
+	return mock.BlockNumberFunc(ctx)
+}
+
+// BlockNumberCalls gets all the calls that were made to BlockNumber.
+// Check the length with:
+//
+//	len(mockedClient.BlockNumberCalls())
+func (mock *ClientMock) BlockNumberCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockBlockNumber.RLock()
+	calls = mock.calls.BlockNumber
+	mock.lockBlockNumber.RUnlock()
+	return calls
+}
+
+// CallContract calls CallContractFunc.
+func (mock *ClientMock) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if mock.CallContractFunc == nil {
+		panic("ClientMock.CallContractFunc: method is nil but Client.CallContract was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Call        ethereum.CallMsg
+		BlockNumber *big.Int
+	}{
+		Ctx:         ctx,
+		Call:        call,
+		BlockNumber: blockNumber,
+	}
+	mock.lockCallContract.Lock()
+	mock.calls.CallContract = append(mock.calls.CallContract, callInfo)
+	mock.lockCallContract.Unlock()
+	return mock.CallContractFunc(ctx, call, blockNumber)
+}
+
+// CallContractCalls gets all the calls that were made to CallContract.
+// Check the length with:
+//
+//	len(mockedClient.CallContractCalls())
+func (mock *ClientMock) CallContractCalls() []struct {
+	Ctx         context.Context
+	Call        ethereum.CallMsg
+	BlockNumber *big.Int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Call        ethereum.CallMsg
+		BlockNumber *big.Int
+	}
+	mock.lockCallContract.RLock()
+	calls = mock.calls.CallContract
+	mock.lockCallContract.RUnlock()
+	return calls
+}
+
+// ChainID calls ChainIDFunc.
+func (mock *ClientMock) ChainID(ctx context.Context) (*big.Int, error) {
+	if mock.ChainIDFunc == nil {
+		panic("ClientMock.ChainIDFunc: method is nil but Client.ChainID was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockChainID.Lock()
+	mock.calls.ChainID = append(mock.calls.ChainID, callInfo)
+	mock.lockChainID.Unlock()
+	return mock.ChainIDFunc(ctx)
+}
+
+// ChainIDCalls gets all the calls that were made to ChainID.
+// Check the length with:
+//
+//	len(mockedClient.ChainIDCalls())
+func (mock *ClientMock) ChainIDCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockChainID.RLock()
+	calls = mock.calls.ChainID
+	mock.lockChainID.RUnlock()
+	return calls
+}
+
+// CodeAt calls CodeAtFunc.
+func (mock *ClientMock) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	if mock.CodeAtFunc == nil {
+		panic("ClientMock.CodeAtFunc: method is nil but Client.CodeAt was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Account     common.Address
+		BlockNumber *big.Int
+	}{
+		Ctx:         ctx,
+		Account:     account,
+		BlockNumber: blockNumber,
+	}
+	mock.lockCodeAt.Lock()
+	mock.calls.CodeAt = append(mock.calls.CodeAt, callInfo)
+	mock.lockCodeAt.Unlock()
+	return mock.CodeAtFunc(ctx, account, blockNumber)
+}
+
+// CodeAtCalls gets all the calls that were made to CodeAt.
+// Check the length with:
+//
+//	len(mockedClient.CodeAtCalls())
+func (mock *ClientMock) CodeAtCalls() []struct {
+	Ctx         context.Context
+	Account     common.Address
+	BlockNumber *big.Int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Account     common.Address
+		BlockNumber *big.Int
+	}
+	mock.lockCodeAt.RLock()
+	calls = mock.calls.CodeAt
+	mock.lockCodeAt.RUnlock()
+	return calls
+}
+
+// EstimateGas calls EstimateGasFunc.
+func (mock *ClientMock) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	if mock.EstimateGasFunc == nil {
+		panic("ClientMock.EstimateGasFunc: method is nil but Client.EstimateGas was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Call ethereum.CallMsg
+	}{
+		Ctx:  ctx,
+		Call: call,
+	}
+	mock.lockEstimateGas.Lock()
+	mock.calls.EstimateGas = append(mock.calls.EstimateGas, callInfo)
+	mock.lockEstimateGas.Unlock()
+	return mock.EstimateGasFunc(ctx, call)
+}
+
+// EstimateGasCalls gets all the calls that were made to EstimateGas.
+// Check the length with:
+//
+//	len(mockedClient.EstimateGasCalls())
+func (mock *ClientMock) EstimateGasCalls() []struct {
+	Ctx  context.Context
+	Call ethereum.CallMsg
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Call ethereum.CallMsg
+	}
+	mock.lockEstimateGas.RLock()
+	calls = mock.calls.EstimateGas
+	mock.lockEstimateGas.RUnlock()
+	return calls
+}
+
+// FeeHistory calls FeeHistoryFunc.
+func (mock *ClientMock) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	if mock.FeeHistoryFunc == nil {
+		panic("ClientMock.FeeHistoryFunc: method is nil but Client.FeeHistory was just called")
+	}
+	callInfo := struct {
+		Ctx               context.Context
+		BlockCount        uint64
+		LastBlock         *big.Int
+		RewardPercentiles []float64
+	}{
+		Ctx:               ctx,
+		BlockCount:        blockCount,
+		LastBlock:         lastBlock,
+		RewardPercentiles: rewardPercentiles,
+	}
+	mock.lockFeeHistory.Lock()
+	mock.calls.FeeHistory = append(mock.calls.FeeHistory, callInfo)
+	mock.lockFeeHistory.Unlock()
+	return mock.FeeHistoryFunc(ctx, blockCount, lastBlock, rewardPercentiles)
+}
+
+// FeeHistoryCalls gets all the calls that were made to FeeHistory.
+// Check the length with:
+//
+//	len(mockedClient.FeeHistoryCalls())
+func (mock *ClientMock) FeeHistoryCalls() []struct {
+	Ctx               context.Context
+	BlockCount        uint64
+	LastBlock         *big.Int
+	RewardPercentiles []float64
+} {
+	var calls []struct {
+		Ctx               context.Context
+		BlockCount        uint64
+		LastBlock         *big.Int
+		RewardPercentiles []float64
+	}
+	mock.lockFeeHistory.RLock()
+	calls = mock.calls.FeeHistory
+	mock.lockFeeHistory.RUnlock()
+	return calls
+}
+
+// FilterLogs calls FilterLogsFunc.
+func (mock *ClientMock) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	if mock.FilterLogsFunc == nil {
+		panic("ClientMock.FilterLogsFunc: method is nil but Client.FilterLogs was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Q   ethereum.FilterQuery
+	}{
+		Ctx: ctx,
+		Q:   q,
+	}
+	mock.lockFilterLogs.Lock()
+	mock.calls.FilterLogs = append(mock.calls.FilterLogs, callInfo)
+	mock.lockFilterLogs.Unlock()
+	return mock.FilterLogsFunc(ctx, q)
+}
+
+// FilterLogsCalls gets all the calls that were made to FilterLogs.
+// Check the length with:
+//
+//	len(mockedClient.FilterLogsCalls())
+func (mock *ClientMock) FilterLogsCalls() []struct {
+	Ctx context.Context
+	Q   ethereum.FilterQuery
+} {
+	var calls []struct {
+		Ctx context.Context
+		Q   ethereum.FilterQuery
+	}
+	mock.lockFilterLogs.RLock()
+	calls = mock.calls.FilterLogs
+	mock.lockFilterLogs.RUnlock()
+	return calls
+}
+
+// HeaderByNumber calls HeaderByNumberFunc.
+func (mock *ClientMock) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if mock.HeaderByNumberFunc == nil {
+		panic("ClientMock.HeaderByNumberFunc: method is nil but Client.HeaderByNumber was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Number *big.Int
+	}{
+		Ctx:    ctx,
+		Number: number,
+	}
+	mock.lockHeaderByNumber.Lock()
+	mock.calls.HeaderByNumber = append(mock.calls.HeaderByNumber, callInfo)
+	mock.lockHeaderByNumber.Unlock()
+	return mock.HeaderByNumberFunc(ctx, number)
+}
+
+// HeaderByNumberCalls gets all the calls that were made to HeaderByNumber.
+// Check the length with:
+//
+//	len(mockedClient.HeaderByNumberCalls())
+func (mock *ClientMock) HeaderByNumberCalls() []struct {
+	Ctx    context.Context
+	Number *big.Int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Number *big.Int
+	}
+	mock.lockHeaderByNumber.RLock()
+	calls = mock.calls.HeaderByNumber
+	mock.lockHeaderByNumber.RUnlock()
+	return calls
+}
+
+// NonceAt calls NonceAtFunc.
+func (mock *ClientMock) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	if mock.NonceAtFunc == nil {
+		panic("ClientMock.NonceAtFunc: method is nil but Client.NonceAt was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Account     common.Address
+		BlockNumber *big.Int
+	}{
+		Ctx:         ctx,
+		Account:     account,
+		BlockNumber: blockNumber,
+	}
+	mock.lockNonceAt.Lock()
+	mock.calls.NonceAt = append(mock.calls.NonceAt, callInfo)
+	mock.lockNonceAt.Unlock()
+	return mock.NonceAtFunc(ctx, account, blockNumber)
+}
+
+// NonceAtCalls gets all the calls that were made to NonceAt.
+// Check the length with:
+//
+//	len(mockedClient.NonceAtCalls())
+func (mock *ClientMock) NonceAtCalls() []struct {
+	Ctx         context.Context
+	Account     common.Address
+	BlockNumber *big.Int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Account     common.Address
+		BlockNumber *big.Int
+	}
+	mock.lockNonceAt.RLock()
+	calls = mock.calls.NonceAt
+	mock.lockNonceAt.RUnlock()
+	return calls
+}
+
+// PendingCodeAt calls PendingCodeAtFunc.
+func (mock *ClientMock) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	if mock.PendingCodeAtFunc == nil {
+		panic("ClientMock.PendingCodeAtFunc: method is nil but Client.PendingCodeAt was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Account common.Address
+	}{
+		Ctx:     ctx,
+		Account: account,
+	}
+	mock.lockPendingCodeAt.Lock()
+	mock.calls.PendingCodeAt = append(mock.calls.PendingCodeAt, callInfo)
+	mock.lockPendingCodeAt.Unlock()
+	return mock.PendingCodeAtFunc(ctx, account)
+}
+
+// PendingCodeAtCalls gets all the calls that were made to PendingCodeAt.
+// Check the length with:
+//
+//	len(mockedClient.PendingCodeAtCalls())
+func (mock *ClientMock) PendingCodeAtCalls() []struct {
+	Ctx     context.Context
+	Account common.Address
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Account common.Address
+	}
+	mock.lockPendingCodeAt.RLock()
+	calls = mock.calls.PendingCodeAt
+	mock.lockPendingCodeAt.RUnlock()
+	return calls
+}
+
+// PendingNonceAt calls PendingNonceAtFunc.
+func (mock *ClientMock) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if mock.PendingNonceAtFunc == nil {
+		panic("ClientMock.PendingNonceAtFunc: method is nil but Client.PendingNonceAt was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Account common.Address
+	}{
+		Ctx:     ctx,
+		Account: account,
+	}
+	mock.lockPendingNonceAt.Lock()
+	mock.calls.PendingNonceAt = append(mock.calls.PendingNonceAt, callInfo)
+	mock.lockPendingNonceAt.Unlock()
+	return mock.PendingNonceAtFunc(ctx, account)
+}
+
+// PendingNonceAtCalls gets all the calls that were made to PendingNonceAt.
+// Check the length with:
+//
+//	len(mockedClient.PendingNonceAtCalls())
+func (mock *ClientMock) PendingNonceAtCalls() []struct {
+	Ctx     context.Context
+	Account common.Address
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Account common.Address
+	}
+	mock.lockPendingNonceAt.RLock()
+	calls = mock.calls.PendingNonceAt
+	mock.lockPendingNonceAt.RUnlock()
+	return calls
+}
+
+// SendTransaction calls SendTransactionFunc.
+func (mock *ClientMock) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if mock.SendTransactionFunc == nil {
+		panic("ClientMock.SendTransactionFunc: method is nil but Client.SendTransaction was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Tx  *types.Transaction
+	}{
+		Ctx: ctx,
+		Tx:  tx,
+	}
+	mock.lockSendTransaction.Lock()
+	mock.calls.SendTransaction = append(mock.calls.SendTransaction, callInfo)
+	mock.lockSendTransaction.Unlock()
+	return mock.SendTransactionFunc(ctx, tx)
+}
+
+// SendTransactionCalls gets all the calls that were made to SendTransaction.
+// Check the length with:
+//
+//	len(mockedClient.SendTransactionCalls())
+func (mock *ClientMock) SendTransactionCalls() []struct {
+	Ctx context.Context
+	Tx  *types.Transaction
+} {
+	var calls []struct {
+		Ctx context.Context
+		Tx  *types.Transaction
+	}
+	mock.lockSendTransaction.RLock()
+	calls = mock.calls.SendTransaction
+	mock.lockSendTransaction.RUnlock()
+	return calls
+}
+
+// SubscribeFilterLogs calls SubscribeFilterLogsFunc.
+func (mock *ClientMock) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if mock.SubscribeFilterLogsFunc == nil {
+		panic("ClientMock.SubscribeFilterLogsFunc: method is nil but Client.SubscribeFilterLogs was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Q   ethereum.FilterQuery
+		Ch  chan<- types.Log
+	}{
+		Ctx: ctx,
+		Q:   q,
+		Ch:  ch,
+	}
+	mock.lockSubscribeFilterLogs.Lock()
+	mock.calls.SubscribeFilterLogs = append(mock.calls.SubscribeFilterLogs, callInfo)
+	mock.lockSubscribeFilterLogs.Unlock()
+	return mock.SubscribeFilterLogsFunc(ctx, q, ch)
+}
+
+// SubscribeFilterLogsCalls gets all the calls that were made to SubscribeFilterLogs.
+// Check the length with:
+//
+//	len(mockedClient.SubscribeFilterLogsCalls())
+func (mock *ClientMock) SubscribeFilterLogsCalls() []struct {
+	Ctx context.Context
+	Q   ethereum.FilterQuery
+	Ch  chan<- types.Log
+} {
+	var calls []struct {
+		Ctx context.Context
+		Q   ethereum.FilterQuery
+		Ch  chan<- types.Log
+	}
+	mock.lockSubscribeFilterLogs.RLock()
+	calls = mock.calls.SubscribeFilterLogs
+	mock.lockSubscribeFilterLogs.RUnlock()
+	return calls
+}
+
+// SuggestGasPrice calls SuggestGasPriceFunc.
+func (mock *ClientMock) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if mock.SuggestGasPriceFunc == nil {
+		panic("ClientMock.SuggestGasPriceFunc: method is nil but Client.SuggestGasPrice was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockSuggestGasPrice.Lock()
+	mock.calls.SuggestGasPrice = append(mock.calls.SuggestGasPrice, callInfo)
+	mock.lockSuggestGasPrice.Unlock()
+	return mock.SuggestGasPriceFunc(ctx)
+}
+
+// SuggestGasPriceCalls gets all the calls that were made to SuggestGasPrice.
+// Check the length with:
+//
+//	len(mockedClient.SuggestGasPriceCalls())
+func (mock *ClientMock) SuggestGasPriceCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockSuggestGasPrice.RLock()
+	calls = mock.calls.SuggestGasPrice
+	mock.lockSuggestGasPrice.RUnlock()
+	return calls
+}
+
+// SuggestGasTipCap calls SuggestGasTipCapFunc.
+func (mock *ClientMock) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	if mock.SuggestGasTipCapFunc == nil {
+		panic("ClientMock.SuggestGasTipCapFunc: method is nil but Client.SuggestGasTipCap was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockSuggestGasTipCap.Lock()
+	mock.calls.SuggestGasTipCap = append(mock.calls.SuggestGasTipCap, callInfo)
+	mock.lockSuggestGasTipCap.Unlock()
+	return mock.SuggestGasTipCapFunc(ctx)
+}
+
+// SuggestGasTipCapCalls gets all the calls that were made to SuggestGasTipCap.
+// Check the length with:
+//
+//	len(mockedClient.SuggestGasTipCapCalls())
+func (mock *ClientMock) SuggestGasTipCapCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockSuggestGasTipCap.RLock()
+	calls = mock.calls.SuggestGasTipCap
+	mock.lockSuggestGasTipCap.RUnlock()
+	return calls
+}
+
+// TransactionByHash calls TransactionByHashFunc.
+func (mock *ClientMock) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	if mock.TransactionByHashFunc == nil {
+		panic("ClientMock.TransactionByHashFunc: method is nil but Client.TransactionByHash was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		TxHash common.Hash
+	}{
+		Ctx:    ctx,
+		TxHash: txHash,
+	}
+	mock.lockTransactionByHash.Lock()
+	mock.calls.TransactionByHash = append(mock.calls.TransactionByHash, callInfo)
+	mock.lockTransactionByHash.Unlock()
+	return mock.TransactionByHashFunc(ctx, txHash)
+}
+
+// TransactionByHashCalls gets all the calls that were made to TransactionByHash.
+// Check the length with:
+//
+//	len(mockedClient.TransactionByHashCalls())
+func (mock *ClientMock) TransactionByHashCalls() []struct {
+	Ctx    context.Context
+	TxHash common.Hash
+} {
+	var calls []struct {
+		Ctx    context.Context
+		TxHash common.Hash
+	}
+	mock.lockTransactionByHash.RLock()
+	calls = mock.calls.TransactionByHash
+	mock.lockTransactionByHash.RUnlock()
+	return calls
+}
+
+// TransactionReceipt calls TransactionReceiptFunc.
+func (mock *ClientMock) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if mock.TransactionReceiptFunc == nil {
+		panic("ClientMock.TransactionReceiptFunc: method is nil but Client.TransactionReceipt was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		TxHash common.Hash
+	}{
+		Ctx:    ctx,
+		TxHash: txHash,
+	}
+	mock.lockTransactionReceipt.Lock()
+	mock.calls.TransactionReceipt = append(mock.calls.TransactionReceipt, callInfo)
+	mock.lockTransactionReceipt.Unlock()
+	return mock.TransactionReceiptFunc(ctx, txHash)
+}
+
+// TransactionReceiptCalls gets all the calls that were made to TransactionReceipt.
+// Check the length with:
+//
+//	len(mockedClient.TransactionReceiptCalls())
+func (mock *ClientMock) TransactionReceiptCalls() []struct {
+	Ctx    context.Context
+	TxHash common.Hash
+} {
+	var calls []struct {
+		Ctx    context.Context
+		TxHash common.Hash
+	}
+	mock.lockTransactionReceipt.RLock()
+	calls = mock.calls.TransactionReceipt
+	mock.lockTransactionReceipt.RUnlock()
+	return calls
+}
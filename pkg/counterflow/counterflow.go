@@ -0,0 +1,162 @@
+// Package counterflow 封装 Counter 合约的部署、递增和事件查询流程，供 task02 和其他程序复用。
+package counterflow
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/go-eth-demo/counter"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/tracing"
+)
+
+// Result 是一次递增的完整结果
+type Result struct {
+	Before  *big.Int // 交易前的计数
+	After   *big.Int // 交易确认后的计数
+	Tx      *types.Transaction
+	Receipt *types.Receipt
+	Events  []*counter.CounterCountIncremented // 收据中解析出的 CountIncremented 事件
+}
+
+// Incremented 判断计数是否真的增加了
+func (r *Result) Incremented() bool {
+	return r.After.Cmp(r.Before) > 0
+}
+
+// Deploy 部署一个新的 Counter 合约并等待部署完成
+func Deploy(ctx context.Context, backend chain.Client, auth *bind.TransactOpts) (common.Address, *counter.Counter, error) {
+	_, tx, contract, err := counter.DeployCounter(auth, backend)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("deploy counter: %w", err)
+	}
+	address, err := bind.WaitDeployed(ctx, backend, tx)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("wait for counter deployment: %w", err)
+	}
+	return address, contract, nil
+}
+
+// IncrementCall 返回 from 调用 increment 的消息，用于模拟执行和估算 gas
+func IncrementCall(address, from common.Address) (ethereum.CallMsg, error) {
+	parsed, err := counter.CounterMetaData.GetAbi()
+	if err != nil {
+		return ethereum.CallMsg{}, fmt.Errorf("parse counter ABI: %w", err)
+	}
+	data, err := parsed.Pack("increment")
+	if err != nil {
+		return ethereum.CallMsg{}, fmt.Errorf("pack increment: %w", err)
+	}
+	return ethereum.CallMsg{From: from, To: &address, Data: data}, nil
+}
+
+// SimulateIncrement 以 from 的身份用 eth_call 模拟 increment，调用会回滚时返回 *ethtx.RevertError
+func SimulateIncrement(ctx context.Context, backend chain.Client, address, from common.Address) error {
+	msg, err := IncrementCall(address, from)
+	if err != nil {
+		return err
+	}
+	return ethtx.Simulate(ctx, backend, msg)
+}
+
+// Increment 查询当前计数，先模拟再发送 increment 交易，等待确认后再次查询计数。
+// 模拟执行会回滚时不发送交易，直接返回 *ethtx.RevertError。
+// auth.GasLimit 为 0 时用估算值加 ethtx.DefaultGasBuffer 的余量。
+func Increment(ctx context.Context, backend chain.Client, address common.Address, auth *bind.TransactOpts) (*Result, error) {
+	contract, err := counter.NewCounter(address, backend)
+	if err != nil {
+		return nil, fmt.Errorf("bind counter: %w", err)
+	}
+	before, err := contract.GetCount(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("get counter value before increment: %w", err)
+	}
+
+	msg, err := IncrementCall(address, auth.From)
+	if err != nil {
+		return nil, err
+	}
+	if err := ethtx.Simulate(ctx, backend, msg); err != nil {
+		return nil, err
+	}
+	opts := *auth
+	if opts.GasLimit == 0 {
+		if opts.GasLimit, err = ethtx.EstimateGas(ctx, backend, msg, ethtx.DefaultGasBuffer); err != nil {
+			return nil, err
+		}
+	}
+	// 绑定在一次调用中读取 nonce 和费用、签名并广播
+	if opts.Context == nil {
+		opts.Context = ctx
+	}
+	var span *tracing.Span
+	opts.Context, span = tracing.Start(opts.Context, "send increment", tracing.KindInternal)
+	tx, err := contract.Increment(&opts)
+	span.End(err)
+	if err != nil {
+		return nil, fmt.Errorf("increment counter: %w", err)
+	}
+
+	waitCtx, span := tracing.Start(ctx, "wait mined", tracing.KindInternal)
+	span.SetAttr("tx.hash", tx.Hash().Hex())
+	receipt, err := bind.WaitMined(waitCtx, backend, tx)
+	span.End(err)
+	if err != nil {
+		return nil, fmt.Errorf("wait for transaction confirmation: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %s failed with status: %d", tx.Hash().Hex(), receipt.Status)
+	}
+
+	// 在交易所在区块上查询，避免节点状态尚未同步到最新区块
+	after, err := contract.GetCount(&bind.CallOpts{Context: ctx, BlockNumber: receipt.BlockNumber})
+	if err != nil {
+		return nil, fmt.Errorf("get counter value after increment: %w", err)
+	}
+
+	var events []*counter.CounterCountIncremented
+	for _, l := range receipt.Logs {
+		if ev, err := contract.ParseCountIncremented(*l); err == nil && l.Address == address {
+			events = append(events, ev)
+		}
+	}
+	return &Result{Before: before, After: after, Tx: tx, Receipt: receipt, Events: events}, nil
+}
+
+// historyChunk 是查询历史事件时单次 eth_getLogs 的区块数，多数提供商限制在几千个区块以内
+const historyChunk = 2000
+
+// History 按区块范围 [from, to] 查询合约的 CountIncremented 事件，by 非空时只返回这些地址触发的事件。
+// 范围较大时分段查询，结果按区块顺序返回。
+func History(ctx context.Context, backend chain.Client, address common.Address, from, to uint64, by []common.Address) ([]*counter.CounterCountIncremented, error) {
+	filterer, err := counter.NewCounterFilterer(address, backend)
+	if err != nil {
+		return nil, fmt.Errorf("bind counter: %w", err)
+	}
+	var events []*counter.CounterCountIncremented
+	for start := from; start <= to; start += historyChunk {
+		end := min(start+historyChunk-1, to)
+		it, err := filterer.FilterCountIncremented(&bind.FilterOpts{Start: start, End: &end, Context: ctx}, by)
+		if err != nil {
+			return nil, fmt.Errorf("filter CountIncremented in blocks %d-%d: %w", start, end, err)
+		}
+		for it.Next() {
+			events = append(events, it.Event)
+		}
+		err = it.Error()
+		it.Close()
+		if err != nil {
+			return nil, fmt.Errorf("filter CountIncremented in blocks %d-%d: %w", start, end, err)
+		}
+		if end == to {
+			break
+		}
+	}
+	return events, nil
+}
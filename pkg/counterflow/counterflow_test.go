@@ -0,0 +1,161 @@
+package counterflow
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/local/go-eth-demo/go-eth-demo/counter"
+	"github.com/local/go-eth-demo/internal/simchain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+// newTestBackend 用 internal/simchain 创建一个预先充值的模拟链，链在后台持续出块，
+// 这样 WaitMined/WaitDeployed 可以像在真实网络上一样返回。
+func newTestBackend(t *testing.T) (*simulated.Backend, *bind.TransactOpts) {
+	t.Helper()
+	c := simchain.New(t, 1)
+	return c.Backend, c.Transactor(t, 0)
+}
+
+func TestDeployAndIncrement(t *testing.T) {
+	backend, auth := newTestBackend(t)
+	client := backend.Client()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	address, contract, err := Deploy(ctx, client, auth)
+	if err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		t.Fatalf("CodeAt: %v", err)
+	}
+	if len(code) == 0 {
+		t.Fatalf("no code at deployed address %s", address.Hex())
+	}
+
+	for i := int64(0); i < 3; i++ {
+		result, err := Increment(ctx, client, address, auth)
+		if err != nil {
+			t.Fatalf("Increment #%d: %v", i+1, err)
+		}
+		if result.Before.Int64() != i {
+			t.Errorf("Increment #%d: before = %d, want %d", i+1, result.Before, i)
+		}
+		if result.After.Int64() != i+1 {
+			t.Errorf("Increment #%d: after = %d, want %d", i+1, result.After, i+1)
+		}
+		if !result.Incremented() {
+			t.Errorf("Increment #%d: Incremented() = false", i+1)
+		}
+		if result.Receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("Increment #%d: receipt status = %d", i+1, result.Receipt.Status)
+		}
+		if result.Receipt.TxHash != result.Tx.Hash() {
+			t.Errorf("Increment #%d: receipt is for %s, want %s", i+1, result.Receipt.TxHash.Hex(), result.Tx.Hash().Hex())
+		}
+	}
+
+	count, err := contract.Count(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count.Int64() != 3 {
+		t.Errorf("public count = %d, want 3", count)
+	}
+}
+
+func TestIncrementCanceledContext(t *testing.T) {
+	backend, auth := newTestBackend(t)
+	client := backend.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	address, _, err := Deploy(ctx, client, auth)
+	if err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := Increment(canceled, client, address, auth); err == nil {
+		t.Fatal("Increment with canceled context succeeded, want error")
+	}
+}
+
+func TestSimulateRevert(t *testing.T) {
+	backend, auth := newTestBackend(t)
+	client := backend.Client()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	address, _, err := Deploy(ctx, client, auth)
+	if err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+
+	if err := SimulateIncrement(ctx, client, address, auth.From); err != nil {
+		t.Fatalf("SimulateIncrement: %v", err)
+	}
+	// increment 不是 payable，附带 ETH 的调用会回滚
+	parsed, err := counter.CounterMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := parsed.Pack("increment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ethtx.Simulate(ctx, client, ethereum.CallMsg{From: auth.From, To: &address, Value: big.NewInt(1), Data: data})
+	var revert *ethtx.RevertError
+	if !errors.As(err, &revert) {
+		t.Fatalf("Simulate with value = %v, want *RevertError", err)
+	}
+	if revert.Reason != "" {
+		t.Errorf("reason = %q, want empty", revert.Reason)
+	}
+}
+
+func TestIncrementEventsAndHistory(t *testing.T) {
+	backend, auth := newTestBackend(t)
+	client := backend.Client()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	address, _, err := Deploy(ctx, client, auth)
+	if err != nil {
+		t.Fatalf("Deploy: %v", err)
+	}
+
+	var last uint64
+	for i := int64(1); i <= 2; i++ {
+		result, err := Increment(ctx, client, address, auth)
+		if err != nil {
+			t.Fatalf("Increment #%d: %v", i, err)
+		}
+		if len(result.Events) != 1 || result.Events[0].NewValue.Int64() != i || result.Events[0].By != auth.From {
+			t.Fatalf("Increment #%d: events = %+v", i, result.Events)
+		}
+		last = result.Receipt.BlockNumber.Uint64()
+	}
+
+	events, err := History(ctx, client, address, 0, last, nil)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(events) != 2 || events[0].NewValue.Int64() != 1 || events[1].NewValue.Int64() != 2 {
+		t.Errorf("History = %+v, want newValue 1 and 2", events)
+	}
+	// 按 by 过滤，其他地址没有触发过事件
+	other := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	if events, err := History(ctx, client, address, 0, last, []common.Address{other}); err != nil || len(events) != 0 {
+		t.Errorf("History(by other) = %d events, %v; want none", len(events), err)
+	}
+}
@@ -0,0 +1,175 @@
+package decode
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Arg 是一个解码后的参数
+type Arg struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Indexed bool        `json:"indexed,omitempty"`
+	Value   interface{} `json:"value"`
+}
+
+// Call 是解码后的合约调用
+type Call struct {
+	Method    string `json:"method"`
+	Signature string `json:"signature"`
+	Selector  string `json:"selector"`
+	Args      []Arg  `json:"args"`
+}
+
+// Event 是解码后的事件日志
+type Event struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	Address   string `json:"address"`
+	LogIndex  uint   `json:"logIndex"`
+	Args      []Arg  `json:"args"`
+}
+
+// CallData 根据 ABI 解码调用数据（4 字节选择器 + 参数）
+func CallData(contract *abi.ABI, data []byte) (*Call, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata too short: %d bytes", len(data))
+	}
+	method, err := contract.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("unpack %s arguments: %w", method.Name, err)
+	}
+	call := &Call{
+		Method:    method.Name,
+		Signature: method.Sig,
+		Selector:  hexutil.Encode(method.ID),
+		Args:      make([]Arg, len(values)),
+	}
+	for i, v := range values {
+		call.Args[i] = Arg{Name: method.Inputs[i].Name, Type: method.Inputs[i].Type.String(), Value: FormatValue(v)}
+	}
+	return call, nil
+}
+
+// Log 根据 ABI 解码事件日志，indexed 参数来自 topics，其余参数来自 data
+func Log(contract *abi.ABI, log *types.Log) (*Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, errors.New("anonymous log has no topics")
+	}
+	event, err := contract.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	if len(log.Data) > 0 {
+		if err := event.Inputs.UnpackIntoMap(values, log.Data); err != nil {
+			return nil, fmt.Errorf("unpack %s data: %w", event.Name, err)
+		}
+	}
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("parse %s topics: %w", event.Name, err)
+	}
+
+	out := &Event{
+		Name:      event.Name,
+		Signature: event.Sig,
+		Address:   log.Address.Hex(),
+		LogIndex:  log.Index,
+		Args:      make([]Arg, len(event.Inputs)),
+	}
+	for i, input := range event.Inputs {
+		out.Args[i] = Arg{Name: input.Name, Type: input.Type.String(), Indexed: input.Indexed, Value: FormatValue(values[input.Name])}
+	}
+	return out, nil
+}
+
+// ABIs 是依次尝试的一组 ABI，用于解码来源未知的调用数据和日志（例如按哈希查到的任意交易）
+type ABIs []*abi.ABI
+
+// CallData 用第一个能解码 data 的 ABI 解码，都不能解码时返回最后一个错误
+func (a ABIs) CallData(data []byte) (*Call, error) {
+	err := errors.New("no ABI to decode calldata")
+	for _, contract := range a {
+		var call *Call
+		if call, err = CallData(contract, data); err == nil {
+			return call, nil
+		}
+	}
+	return nil, err
+}
+
+// Log 用第一个能解码 log 的 ABI 解码。同一签名的事件可能因 indexed 参数不同而不兼容
+// （如 ERC-20 和 ERC-721 的 Transfer），解码失败时继续尝试下一个 ABI
+func (a ABIs) Log(log *types.Log) (*Event, error) {
+	err := errors.New("no ABI to decode log")
+	for _, contract := range a {
+		var event *Event
+		if event, err = Log(contract, log); err == nil {
+			return event, nil
+		}
+	}
+	return nil, err
+}
+
+// FormatValue 把 ABI 解码出的 Go 值转换为适合 JSON/文本展示的形式：
+// 大整数转十进制字符串，地址转校验和格式，字节转十六进制。
+func FormatValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case *big.Int:
+		return x.String()
+	case common.Address:
+		return x.Hex()
+	case common.Hash:
+		return x.Hex()
+	case []byte:
+		return hexutil.Encode(x)
+	case string, bool:
+		return x
+	case uint8, uint16, uint32, uint64, int8, int16, int32, int64:
+		return fmt.Sprint(x)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array:
+		// 定长字节数组（bytes32 等）
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = FormatValue(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Struct:
+		// tuple：按字段名展开
+		out := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			out[rv.Type().Field(i).Name] = FormatValue(rv.Field(i).Interface())
+		}
+		return out
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,265 @@
+package decode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/internal/golden"
+)
+
+var fetch = flag.Bool("fetch", false, "download missing fixtures listed in testdata/sepolia.json from SEPOLIA_RPC_URL")
+
+// fixtures 返回 testdata/dir 下匹配 pattern 的文件名（不含扩展名）
+func fixtures(t *testing.T, dir, pattern string) map[string]string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", dir, pattern))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no fixtures in testdata/%s", dir)
+	}
+	out := make(map[string]string, len(paths))
+	for _, p := range paths {
+		name := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		out[name] = p
+	}
+	return out
+}
+
+func loadABI(t testing.TB, name string) *abi.ABI {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "abi", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	parsed, err := abi.JSON(f)
+	if err != nil {
+		t.Fatalf("parse %s ABI: %v", name, err)
+	}
+	return &parsed
+}
+
+func TestTransactionGolden(t *testing.T) {
+	for name, path := range fixtures(t, "tx", "*.hex") {
+		t.Run(name, func(t *testing.T) {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tx, err := ParseRawTransaction(string(raw))
+			if err != nil {
+				t.Fatalf("ParseRawTransaction: %v", err)
+			}
+			golden.AssertJSON(t, "tx/"+name, Transaction(tx))
+		})
+	}
+}
+
+func TestCallDataGolden(t *testing.T) {
+	for name, path := range fixtures(t, "calls", "*.json") {
+		t.Run(name, func(t *testing.T) {
+			blob, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fixture struct {
+				ABI  string `json:"abi"`
+				Data string `json:"data"`
+			}
+			if err := json.Unmarshal(blob, &fixture); err != nil {
+				t.Fatal(err)
+			}
+			call, err := CallData(loadABI(t, fixture.ABI), common.FromHex(fixture.Data))
+			if err != nil {
+				t.Fatalf("CallData: %v", err)
+			}
+			golden.AssertJSON(t, "calls/"+name, call)
+		})
+	}
+}
+
+func TestLogGolden(t *testing.T) {
+	abis := ABIs{loadABI(t, "erc20"), loadABI(t, "counter")}
+	for name, path := range fixtures(t, "logs", "*_receipt.json") {
+		t.Run(name, func(t *testing.T) {
+			blob, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var receipt types.Receipt
+			if err := json.Unmarshal(blob, &receipt); err != nil {
+				t.Fatalf("unmarshal receipt: %v", err)
+			}
+			events := make([]*Event, len(receipt.Logs))
+			for i, l := range receipt.Logs {
+				if events[i], err = abis.Log(l); err != nil {
+					t.Fatalf("Log #%d: %v", i, err)
+				}
+			}
+			golden.AssertJSON(t, "logs/"+name, events)
+		})
+	}
+}
+
+// TestSepoliaFixtures 检查 testdata/sepolia.json 登记的真实 Sepolia 交易：tx/<name>.hex 的哈希和链 ID、
+// logs/<name>_receipt.json 的交易哈希都必须与登记的一致。-fetch 时先从 SEPOLIA_RPC_URL 下载缺少的文件
+func TestSepoliaFixtures(t *testing.T) {
+	blob, err := os.ReadFile(filepath.Join("testdata", "sepolia.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hashes map[string]common.Hash
+	if err := json.Unmarshal(blob, &hashes); err != nil {
+		t.Fatalf("parse sepolia.json: %v", err)
+	}
+	if *fetch {
+		fetchSepolia(t, hashes)
+	}
+	for name, hash := range hashes {
+		t.Run(name, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join("testdata", "tx", name+".hex"))
+			if err != nil {
+				t.Fatalf("%v (run with -fetch to download it)", err)
+			}
+			tx, err := ParseRawTransaction(string(raw))
+			if err != nil {
+				t.Fatalf("ParseRawTransaction: %v", err)
+			}
+			if tx.Hash() != hash {
+				t.Errorf("tx/%s.hex hashes to %s, want %s", name, tx.Hash(), hash)
+			}
+			if tx.ChainId().Cmp(params.SepoliaChainConfig.ChainID) != 0 {
+				t.Errorf("tx/%s.hex chain ID = %s, want Sepolia", name, tx.ChainId())
+			}
+
+			blob, err := os.ReadFile(filepath.Join("testdata", "logs", name+"_receipt.json"))
+			if err != nil {
+				t.Fatalf("%v (run with -fetch to download it)", err)
+			}
+			var receipt types.Receipt
+			if err := json.Unmarshal(blob, &receipt); err != nil {
+				t.Fatalf("unmarshal receipt: %v", err)
+			}
+			if receipt.TxHash != hash {
+				t.Errorf("logs/%s_receipt.json is for %s, want %s", name, receipt.TxHash, hash)
+			}
+		})
+	}
+}
+
+// fetchSepolia 用 eth_getRawTransactionByHash 和 eth_getTransactionReceipt 下载 hashes 中还没有的夹具文件
+func fetchSepolia(t *testing.T, hashes map[string]common.Hash) {
+	t.Helper()
+	url := os.Getenv("SEPOLIA_RPC_URL")
+	if url == "" {
+		t.Fatal("-fetch requires SEPOLIA_RPC_URL")
+	}
+	ctx := context.Background()
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for name, hash := range hashes {
+		txPath := filepath.Join("testdata", "tx", name+".hex")
+		if _, err := os.Stat(txPath); err != nil {
+			var raw hexutil.Bytes
+			if err := client.CallContext(ctx, &raw, "eth_getRawTransactionByHash", hash); err != nil {
+				t.Fatalf("fetch %s: %v", name, err)
+			}
+			if len(raw) == 0 {
+				t.Fatalf("fetch %s: transaction %s not found", name, hash)
+			}
+			if err := os.WriteFile(txPath, []byte(raw.String()+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		receiptPath := filepath.Join("testdata", "logs", name+"_receipt.json")
+		if _, err := os.Stat(receiptPath); err != nil {
+			var receipt json.RawMessage
+			if err := client.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+				t.Fatalf("fetch %s receipt: %v", name, err)
+			}
+			if len(receipt) == 0 || string(receipt) == "null" {
+				t.Fatalf("fetch %s receipt: transaction %s not mined", name, hash)
+			}
+			var out bytes.Buffer
+			if err := json.Indent(&out, receipt, "", "  "); err != nil {
+				t.Fatal(err)
+			}
+			out.WriteByte('\n')
+			if err := os.WriteFile(receiptPath, out.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	erc20 := loadABI(t, "erc20")
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{"raw tx not hex", func() error { _, err := ParseRawTransaction("0xzz"); return err }},
+		{"raw tx truncated", func() error { _, err := ParseRawTransaction("0x02f8"); return err }},
+		{"calldata too short", func() error { _, err := CallData(erc20, []byte{0xa9, 0x05}); return err }},
+		{"unknown selector", func() error { _, err := CallData(erc20, common.FromHex("0xdeadbeef")); return err }},
+		{"truncated arguments", func() error { _, err := CallData(erc20, common.FromHex("0xa9059cbb0000")); return err }},
+		{"log without topics", func() error { _, err := Log(erc20, &types.Log{}); return err }},
+		{"unknown event", func() error { _, err := Log(erc20, &types.Log{Topics: []common.Hash{{}}}); return err }},
+	}
+	for _, tt := range tests {
+		if err := tt.fn(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestABIs(t *testing.T) {
+	erc721, err := abi.JSON(strings.NewReader(`[{"type":"event","name":"Transfer","inputs":[
+		{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	abis := ABIs{loadABI(t, "erc20"), &erc721, loadABI(t, "counter")}
+
+	// 同一个 Transfer 签名：两个 indexed 参数按 ERC-20 解码，三个按 ERC-721 解码
+	topic := erc721.Events["Transfer"].ID
+	from, to := common.HexToHash("0x01"), common.HexToHash("0x02")
+	amount := common.LeftPadBytes([]byte{5}, 32)
+	event, err := abis.Log(&types.Log{Topics: []common.Hash{topic, from, to}, Data: amount})
+	if err != nil || len(event.Args) != 3 || event.Args[2].Name != "value" {
+		t.Errorf("ERC-20 Transfer = %+v, %v", event, err)
+	}
+	event, err = abis.Log(&types.Log{Topics: []common.Hash{topic, from, to, common.BytesToHash(amount)}})
+	if err != nil || len(event.Args) != 3 || event.Args[2].Name != "tokenId" || event.Args[2].Value != "5" {
+		t.Errorf("ERC-721 Transfer = %+v, %v", event, err)
+	}
+
+	call, err := abis.CallData(loadABI(t, "counter").Methods["increment"].ID)
+	if err != nil || call.Method != "increment" {
+		t.Errorf("CallData(increment) = %+v, %v", call, err)
+	}
+	if _, err := abis.CallData(common.FromHex("0xdeadbeef")); err == nil {
+		t.Error("CallData with unknown selector succeeded")
+	}
+	if _, err := (ABIs{}).Log(&types.Log{Topics: []common.Hash{topic}}); err == nil {
+		t.Error("empty ABIs decoded a log")
+	}
+}
@@ -0,0 +1,84 @@
+package decode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 运行单个模糊测试：go test ./pkg/decode -fuzz FuzzParseRawTransaction
+
+func FuzzParseRawTransaction(f *testing.F) {
+	paths, _ := filepath.Glob(filepath.Join("testdata", "tx", "*.hex"))
+	for _, p := range paths {
+		raw, err := os.ReadFile(p)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(raw))
+	}
+	f.Add("0x")
+	f.Add("0x02")
+	f.Add("0xc0")
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		tx, err := ParseRawTransaction(raw)
+		if err != nil {
+			return
+		}
+		// 能解析的交易必须能被展示，并且重新编码后得到同一个哈希
+		view := Transaction(tx)
+		if view.Hash != tx.Hash().Hex() {
+			t.Fatalf("hash mismatch: %s vs %s", view.Hash, tx.Hash().Hex())
+		}
+		enc, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("re-encode parsed tx: %v", err)
+		}
+		var again types.Transaction
+		if err := again.UnmarshalBinary(enc); err != nil {
+			t.Fatalf("decode re-encoded tx: %v", err)
+		}
+		if again.Hash() != tx.Hash() {
+			t.Fatalf("round trip changed hash")
+		}
+	})
+}
+
+func FuzzCallData(f *testing.F) {
+	erc20 := loadABI(f, "erc20")
+	counter := loadABI(f, "counter")
+	f.Add(common.FromHex("0xa9059cbb000000000000000000000000f92f0e5adb38f15a1e8514ea49de3f6028b8ff7d0000000000000000000000000000000000000000000000000000000000bebc20"))
+	f.Add(common.FromHex("0x23b872dd"))
+	f.Add(common.FromHex("0xd09de08a"))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// 只要求不 panic；无效输入返回错误即可
+		CallData(erc20, data)
+		CallData(counter, data)
+	})
+}
+
+func FuzzLog(f *testing.F) {
+	erc20 := loadABI(f, "erc20")
+	transfer := erc20.Events["Transfer"].ID
+	f.Add(transfer.Bytes(), make([]byte, 64), make([]byte, 32))
+	f.Add(transfer.Bytes(), []byte{}, []byte{1})
+	f.Add([]byte{}, []byte{}, []byte{})
+
+	f.Fuzz(func(t *testing.T, topic0, extraTopics, data []byte) {
+		log := &types.Log{Data: data}
+		if len(topic0) > 0 {
+			log.Topics = append(log.Topics, common.BytesToHash(topic0))
+		}
+		for len(extraTopics) >= 32 {
+			log.Topics = append(log.Topics, common.BytesToHash(extraTopics[:32]))
+			extraTopics = extraTopics[32:]
+		}
+		Log(erc20, log)
+	})
+}
@@ -0,0 +1,117 @@
+// Package decode 把原始交易、合约调用数据和事件日志解码为便于展示的结构。
+package decode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tx 是解码后的交易，所有大数都以十进制字符串表示
+type Tx struct {
+	Hash       string           `json:"hash"`
+	Type       string           `json:"type"`
+	ChainID    string           `json:"chainId,omitempty"`
+	Nonce      uint64           `json:"nonce"`
+	From       string           `json:"from,omitempty"`
+	To         string           `json:"to,omitempty"` // 合约创建时为空
+	Value      string           `json:"value"`
+	Gas        uint64           `json:"gas"`
+	GasPrice   string           `json:"gasPrice,omitempty"`
+	GasTipCap  string           `json:"maxPriorityFeePerGas,omitempty"`
+	GasFeeCap  string           `json:"maxFeePerGas,omitempty"`
+	BlobFeeCap string           `json:"maxFeePerBlobGas,omitempty"`
+	AccessList types.AccessList `json:"accessList,omitempty"`
+	BlobHashes []string         `json:"blobHashes,omitempty"`
+	Data       string           `json:"data,omitempty"`
+	Selector   string           `json:"selector,omitempty"`
+	Signature  *Signature       `json:"signature,omitempty"` // 未签名时为空
+}
+
+// Signature 是交易的签名值。传统交易的 V 包含 EIP-155 的链 ID，其他类型的 V 是 y parity（0 或 1）
+type Signature struct {
+	V string `json:"v"`
+	R string `json:"r"`
+	S string `json:"s"`
+}
+
+// txTypeNames 是交易类型的可读名称
+var txTypeNames = map[uint8]string{
+	types.LegacyTxType:     "legacy",
+	types.AccessListTxType: "access-list (EIP-2930)",
+	types.DynamicFeeTxType: "dynamic-fee (EIP-1559)",
+	types.BlobTxType:       "blob (EIP-4844)",
+	types.SetCodeTxType:    "set-code (EIP-7702)",
+}
+
+// ParseRawTransaction 解析十六进制编码的已签名交易（任意类型）
+func ParseRawTransaction(rawHex string) (*types.Transaction, error) {
+	raw, err := hexutil.Decode(strings.TrimSpace(rawHex))
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction hex: %w", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("decode raw transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// Transaction 解码交易字段。已签名的交易会恢复发送者地址；
+// 未签名或签名无效时 From 为空。
+func Transaction(tx *types.Transaction) *Tx {
+	out := &Tx{
+		Hash:  tx.Hash().Hex(),
+		Type:  txTypeNames[tx.Type()],
+		Nonce: tx.Nonce(),
+		Value: tx.Value().String(),
+		Gas:   tx.Gas(),
+	}
+	if out.Type == "" {
+		out.Type = fmt.Sprintf("unknown (0x%02x)", tx.Type())
+	}
+	if tx.Protected() || tx.Type() != types.LegacyTxType {
+		out.ChainID = tx.ChainId().String()
+	}
+	if to := tx.To(); to != nil {
+		out.To = to.Hex()
+	}
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		out.GasPrice = tx.GasPrice().String()
+	} else {
+		out.GasTipCap = tx.GasTipCap().String()
+		out.GasFeeCap = tx.GasFeeCap().String()
+	}
+	if tx.Type() == types.BlobTxType {
+		out.BlobFeeCap = tx.BlobGasFeeCap().String()
+	}
+	out.AccessList = tx.AccessList()
+	for _, h := range tx.BlobHashes() {
+		out.BlobHashes = append(out.BlobHashes, h.Hex())
+	}
+	if data := tx.Data(); len(data) > 0 {
+		out.Data = hexutil.Encode(data)
+		if tx.To() != nil && len(data) >= 4 {
+			out.Selector = hexutil.Encode(data[:4])
+		}
+	}
+	if v, r, s := tx.RawSignatureValues(); r.Sign() != 0 || s.Sign() != 0 {
+		out.Signature = &Signature{V: v.String(), R: hexutil.EncodeBig(r), S: hexutil.EncodeBig(s)}
+		if from, err := Sender(tx); err == nil {
+			out.From = from.Hex()
+		}
+	}
+	return out
+}
+
+// Sender 恢复已签名交易 tx 的发送者，支持所有交易类型，包括没有 EIP-155 重放保护的传统交易
+func Sender(tx *types.Transaction) (common.Address, error) {
+	signer := types.LatestSignerForChainID(tx.ChainId())
+	if !tx.Protected() {
+		signer = types.HomesteadSigner{}
+	}
+	return types.Sender(signer, tx)
+}
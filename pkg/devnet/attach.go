@@ -0,0 +1,58 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultDevAccount 是 DefaultDevKey 对应的地址
+const defaultDevAccount = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+// Attach 连接一个已经在运行、但不是由 Up 启动的本地节点（例如 CI 中的 anvil 服务或手动启动的 npx hardhat node），
+// 从 web3_clientVersion 判断节点实现，从 eth_accounts 读取预充值账户。节点只返回地址，
+// 使用默认助记词时第一个账户的私钥为 DefaultDevKey。返回的 State 的 PID 为 0，不保存到状态目录
+func Attach(ctx context.Context, url string) (*State, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var version string
+	if err := client.CallContext(ctx, &version, "web3_clientVersion"); err != nil {
+		return nil, fmt.Errorf("web3_clientVersion: %w", err)
+	}
+	st := &State{RPCURL: url, StartedAt: time.Now()}
+	switch v := strings.ToLower(version); {
+	case strings.HasPrefix(v, "anvil"):
+		st.Kind = KindAnvil
+	case strings.HasPrefix(v, "hardhat"):
+		st.Kind = KindHardhat
+	default:
+		return nil, fmt.Errorf("%s is not an anvil or hardhat node (client version %q)", url, version)
+	}
+
+	var chainID hexutil.Uint64
+	if err := client.CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+		return nil, fmt.Errorf("eth_chainId: %w", err)
+	}
+	st.ChainID = uint64(chainID)
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		return nil, fmt.Errorf("eth_accounts: %w", err)
+	}
+	for _, a := range accounts {
+		acc := Account{Address: a.Hex()}
+		if a == common.HexToAddress(defaultDevAccount) {
+			acc.PrivateKey = "0x" + DefaultDevKey
+		}
+		st.Accounts = append(st.Accounts, acc)
+	}
+	return st, nil
+}
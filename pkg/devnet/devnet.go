@@ -0,0 +1,247 @@
+// Package devnet 管理本地开发链（Anvil 或 Hardhat）的生命周期：启动一个带预充值账户的节点
+// （可选地从真实网络分叉），把连接信息保存到状态目录，并在不需要时干净地关闭它。
+package devnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// 支持的节点实现
+const (
+	KindAnvil   = "anvil"
+	KindHardhat = "hardhat"
+)
+
+// DefaultDir 是默认的状态目录（相对于当前工作目录）
+const DefaultDir = ".devnet"
+
+// DefaultDevKey 是 Anvil 和 Hardhat 默认助记词派生的第一个账户私钥，
+// 仅用于本地开发链，绝不能在真实网络上使用。
+const DefaultDevKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// ErrNotRunning 表示状态目录中没有正在运行的节点
+var ErrNotRunning = errors.New("devnet is not running")
+
+// Config 描述要启动的本地节点
+type Config struct {
+	Kind     string // anvil 或 hardhat
+	Port     int
+	ChainID  uint64 // 仅 anvil 支持自定义
+	Accounts int    // 预充值账户数量，仅 anvil 支持自定义
+	Balance  uint64 // 每个账户的 ETH 余额，仅 anvil 支持自定义
+	Dir      string // 状态目录
+
+	ForkURL     string // 非空时从该 RPC 分叉
+	ForkNetwork string // 分叉的网络名称，仅用于展示
+	ForkBlock   uint64 // 分叉的区块高度，0 表示最新区块
+}
+
+// Fork 记录分叉来源。不保存 RPC URL，因为其中通常包含 API key。
+type Fork struct {
+	Network string `json:"network"`
+	Block   uint64 `json:"block,omitempty"`
+}
+
+// Account 是一个预充值的开发账户
+type Account struct {
+	Address    string `json:"address"`
+	PrivateKey string `json:"privateKey,omitempty"`
+}
+
+// State 是保存到状态目录的运行信息
+type State struct {
+	Kind      string    `json:"kind"`
+	PID       int       `json:"pid"`
+	RPCURL    string    `json:"rpcUrl"`
+	ChainID   uint64    `json:"chainId"`
+	Accounts  []Account `json:"accounts"`
+	StartedAt time.Time `json:"startedAt"`
+
+	Fork         *Fork    `json:"fork,omitempty"`
+	Impersonated []string `json:"impersonated,omitempty"`
+}
+
+func statePath(dir string) string { return filepath.Join(dir, "state.json") }
+
+// Save 把状态写回状态目录
+func (st *State) Save(dir string) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(dir), data, 0o600)
+}
+
+// Load 读取正在运行的节点状态
+func Load(dir string) (*State, error) {
+	data, err := os.ReadFile(statePath(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parse devnet state: %w", err)
+	}
+	if !processAlive(st.PID) {
+		return nil, ErrNotRunning
+	}
+	return &st, nil
+}
+
+// Up 启动本地节点并等待其 RPC 可用。节点在独立的进程组中运行，
+// 本进程退出后继续存在，直到调用 Down。
+func Up(ctx context.Context, cfg Config) (*State, error) {
+	if st, err := Load(cfg.Dir); err == nil {
+		return nil, fmt.Errorf("devnet already running (pid %d, %s)", st.PID, st.RPCURL)
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	var cmd *exec.Cmd
+	configOut := filepath.Join(cfg.Dir, "anvil.json")
+	switch cfg.Kind {
+	case KindAnvil:
+		args := []string{
+			"--port", strconv.Itoa(cfg.Port),
+			"--chain-id", strconv.FormatUint(cfg.ChainID, 10),
+			"--accounts", strconv.Itoa(cfg.Accounts),
+			"--balance", strconv.FormatUint(cfg.Balance, 10),
+			"--config-out", configOut,
+		}
+		if cfg.ForkURL != "" {
+			args = append(args, "--fork-url", cfg.ForkURL)
+			if cfg.ForkBlock > 0 {
+				args = append(args, "--fork-block-number", strconv.FormatUint(cfg.ForkBlock, 10))
+			}
+		}
+		cmd = exec.Command("anvil", args...)
+	case KindHardhat:
+		args := []string{"hardhat", "node", "--port", strconv.Itoa(cfg.Port)}
+		if cfg.ForkURL != "" {
+			args = append(args, "--fork", cfg.ForkURL)
+			if cfg.ForkBlock > 0 {
+				args = append(args, "--fork-block-number", strconv.FormatUint(cfg.ForkBlock, 10))
+			}
+		}
+		cmd = exec.Command("npx", args...)
+	default:
+		return nil, fmt.Errorf("unknown devnet kind %q (want %s or %s)", cfg.Kind, KindAnvil, KindHardhat)
+	}
+
+	logFile, err := os.Create(filepath.Join(cfg.Dir, "node.log"))
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", cfg.Kind, err)
+	}
+
+	st := &State{
+		Kind:      cfg.Kind,
+		PID:       cmd.Process.Pid,
+		RPCURL:    fmt.Sprintf("http://127.0.0.1:%d", cfg.Port),
+		StartedAt: time.Now(),
+	}
+	if cfg.ForkURL != "" {
+		st.Fork = &Fork{Network: cfg.ForkNetwork, Block: cfg.ForkBlock}
+	}
+	// 等待 RPC 可用（分叉节点需要先拉取远端状态）；失败时清理掉已启动的进程
+	chainID, err := waitReady(ctx, st.RPCURL)
+	if err != nil {
+		terminate(st.PID)
+		return nil, fmt.Errorf("%s did not become ready (see %s): %w", cfg.Kind, logFile.Name(), err)
+	}
+	st.ChainID = chainID
+	st.Accounts = readAccounts(cfg.Kind, configOut)
+
+	if err := st.Save(cfg.Dir); err != nil {
+		terminate(st.PID)
+		return nil, err
+	}
+	return st, nil
+}
+
+// Down 停止正在运行的节点并删除状态文件
+func Down(ctx context.Context, dir string) error {
+	st, err := Load(dir)
+	if err != nil {
+		// 进程已经不在了，仍然清理残留的状态文件
+		os.Remove(statePath(dir))
+		ClearSnapshots(dir)
+		return err
+	}
+	terminate(st.PID)
+	for processAlive(st.PID) {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("devnet (pid %d) did not exit: %w", st.PID, ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	// 快照只在节点进程内有效
+	if err := ClearSnapshots(dir); err != nil {
+		return err
+	}
+	return os.Remove(statePath(dir))
+}
+
+// waitReady 轮询节点直到 eth_chainId 返回
+func waitReady(ctx context.Context, url string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+	for {
+		client, err := ethclient.DialContext(ctx, url)
+		if err == nil {
+			chainID, err := client.ChainID(ctx)
+			client.Close()
+			if err == nil {
+				return chainID.Uint64(), nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// readAccounts 读取预充值账户。Anvil 通过 --config-out 输出账户和私钥；
+// Hardhat 使用与 Anvil 相同的默认助记词，这里只记录第一个账户。
+func readAccounts(kind, configOut string) []Account {
+	if kind == KindAnvil {
+		data, err := os.ReadFile(configOut)
+		if err == nil {
+			var out struct {
+				AvailableAccounts []string `json:"available_accounts"`
+				PrivateKeys       []string `json:"private_keys"`
+			}
+			if json.Unmarshal(data, &out) == nil && len(out.AvailableAccounts) == len(out.PrivateKeys) {
+				accounts := make([]Account, len(out.AvailableAccounts))
+				for i := range out.AvailableAccounts {
+					accounts[i] = Account{Address: out.AvailableAccounts[i], PrivateKey: out.PrivateKeys[i]}
+				}
+				return accounts
+			}
+		}
+	}
+	return []Account{{Address: defaultDevAccount, PrivateKey: "0x" + DefaultDevKey}}
+}
@@ -0,0 +1,117 @@
+package devnet
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeAnvil 实现 Attach、SetBalance 和快照用到的 anvil 方法
+type fakeAnvil struct {
+	version   string
+	balances  map[common.Address]*big.Int
+	snapshots int
+}
+
+type web3API struct{ n *fakeAnvil }
+
+func (a web3API) ClientVersion() string { return a.n.version }
+
+type ethAPI struct{ n *fakeAnvil }
+
+func (ethAPI) ChainId() hexutil.Uint64 { return 31337 }
+
+func (ethAPI) Accounts() []common.Address {
+	return []common.Address{common.HexToAddress(defaultDevAccount), common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")}
+}
+
+type anvilAPI struct{ n *fakeAnvil }
+
+func (a anvilAPI) SetBalance(addr common.Address, balance hexutil.Big) {
+	a.n.balances[addr] = balance.ToInt()
+}
+
+type evmAPI struct{ n *fakeAnvil }
+
+func (a evmAPI) Snapshot() hexutil.Uint64 {
+	a.n.snapshots++
+	return hexutil.Uint64(a.n.snapshots)
+}
+
+func (a evmAPI) Revert(id hexutil.Uint64) bool {
+	if int(id) > a.n.snapshots {
+		return false
+	}
+	a.n.snapshots = int(id) - 1
+	return true
+}
+
+func startFake(t *testing.T, version string) (*fakeAnvil, string) {
+	t.Helper()
+	n := &fakeAnvil{version: version, balances: map[common.Address]*big.Int{}}
+	server := rpc.NewServer()
+	for name, api := range map[string]any{"web3": web3API{n}, "eth": ethAPI{n}, "anvil": anvilAPI{n}, "evm": evmAPI{n}} {
+		if err := server.RegisterName(name, api); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts := httptest.NewServer(server)
+	t.Cleanup(func() {
+		ts.Close()
+		server.Stop()
+	})
+	return n, ts.URL
+}
+
+func TestAttach(t *testing.T) {
+	ctx := context.Background()
+	n, url := startFake(t, "anvil/v1.2.3")
+
+	st, err := Attach(ctx, url)
+	if err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+	if st.Kind != KindAnvil || st.ChainID != 31337 || st.PID != 0 || len(st.Accounts) != 2 {
+		t.Fatalf("Attach = %+v", st)
+	}
+	if st.Accounts[0].PrivateKey != "0x"+DefaultDevKey || st.Accounts[1].PrivateKey != "" {
+		t.Errorf("accounts = %+v, want only the default account's key", st.Accounts)
+	}
+
+	client, err := rpc.Dial(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	addr := common.HexToAddress("0x1234")
+	if err := SetBalance(ctx, client, st.Kind, addr, big.NewInt(5e18)); err != nil {
+		t.Fatalf("SetBalance: %v", err)
+	}
+	if got := n.balances[addr]; got == nil || got.Cmp(big.NewInt(5e18)) != 0 {
+		t.Errorf("balance = %v, want 5e18", got)
+	}
+
+	id, err := EVMSnapshot(ctx, client)
+	if err != nil {
+		t.Fatalf("EVMSnapshot: %v", err)
+	}
+	if err := EVMRevert(ctx, client, id); err != nil {
+		t.Fatalf("EVMRevert: %v", err)
+	}
+	// 回滚后快照失效，再次回滚被节点拒绝
+	if err := EVMRevert(ctx, client, id); err == nil {
+		t.Error("second EVMRevert succeeded, want rejection")
+	}
+}
+
+func TestAttachRejectsOtherNodes(t *testing.T) {
+	_, url := startFake(t, "Geth/v1.16.1-stable/linux-amd64/go1.24.4")
+	if _, err := Attach(context.Background(), url); err == nil {
+		t.Fatal("Attach to geth succeeded, want error")
+	}
+}
@@ -0,0 +1,45 @@
+package devnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Fund 给 addr 增加 amount wei 的余额。transfer 为 false 时直接改写余额
+// （anvil_/hardhat_setBalance，立即生效且不产生交易）；为 true 时从第一个预充值账户转账，
+// 返回转账交易哈希。
+func Fund(ctx context.Context, client *rpc.Client, st *State, addr common.Address, amount *big.Int, transfer bool) (common.Hash, error) {
+	if amount.Sign() <= 0 {
+		return common.Hash{}, fmt.Errorf("fund amount must be positive, got %s", amount)
+	}
+	if transfer {
+		if len(st.Accounts) == 0 {
+			return common.Hash{}, errors.New("devnet has no pre-funded accounts to transfer from")
+		}
+		return SendAs(ctx, client, common.HexToAddress(st.Accounts[0].Address), addr, amount, nil)
+	}
+
+	var current hexutil.Big
+	if err := client.CallContext(ctx, &current, "eth_getBalance", addr, "latest"); err != nil {
+		return common.Hash{}, fmt.Errorf("get balance of %s: %w", addr.Hex(), err)
+	}
+	return common.Hash{}, SetBalance(ctx, client, st.Kind, addr, new(big.Int).Add(current.ToInt(), amount))
+}
+
+// SetBalance 把 addr 的余额直接设为 balance wei（anvil_/hardhat_setBalance），不产生交易
+func SetBalance(ctx context.Context, client *rpc.Client, kind string, addr common.Address, balance *big.Int) error {
+	method := "hardhat_setBalance"
+	if kind == KindAnvil {
+		method = "anvil_setBalance"
+	}
+	if err := client.CallContext(ctx, nil, method, addr, (*hexutil.Big)(balance)); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return nil
+}
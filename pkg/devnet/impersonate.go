@@ -0,0 +1,46 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Impersonate 让节点接受以 addr 身份发送的未签名交易（anvil_/hardhat_impersonateAccount），
+// 并把地址记录到状态中
+func Impersonate(ctx context.Context, dir string, client *rpc.Client, st *State, addr common.Address) error {
+	method := "hardhat_impersonateAccount"
+	if st.Kind == KindAnvil {
+		method = "anvil_impersonateAccount"
+	}
+	if err := client.CallContext(ctx, nil, method, addr); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if !slices.Contains(st.Impersonated, addr.Hex()) {
+		st.Impersonated = append(st.Impersonated, addr.Hex())
+	}
+	return st.Save(dir)
+}
+
+// SendAs 以被模拟的账户发送交易（eth_sendTransaction，由节点代为"签名"）。
+// 只能在本地开发链上对已经 Impersonate 的地址使用。
+func SendAs(ctx context.Context, client *rpc.Client, from, to common.Address, value *big.Int, data []byte) (common.Hash, error) {
+	args := map[string]interface{}{
+		"from":  from,
+		"to":    to,
+		"value": (*hexutil.Big)(value),
+	}
+	if len(data) > 0 {
+		args["input"] = hexutil.Bytes(data)
+	}
+	var hash common.Hash
+	if err := client.CallContext(ctx, &hash, "eth_sendTransaction", args); err != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendTransaction from %s: %w", from.Hex(), err)
+	}
+	return hash, nil
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package devnet
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach 让节点运行在独立的进程组中，CLI 退出后不会被一并终止
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminate 向整个进程组发送 SIGTERM（npx 会启动子进程）
+func terminate(pid int) {
+	syscall.Kill(-pid, syscall.SIGTERM)
+}
+
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
@@ -0,0 +1,136 @@
+package devnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Snapshot 是一个命名的链状态检查点
+type Snapshot struct {
+	Name    string    `json:"name"`
+	ID      string    `json:"id"` // evm_snapshot 返回的 ID
+	Block   uint64    `json:"block"`
+	TakenAt time.Time `json:"takenAt"`
+}
+
+func snapshotsPath(dir string) string { return filepath.Join(dir, "snapshots.json") }
+
+// Snapshots 按创建顺序返回已保存的快照
+func Snapshots(dir string) ([]Snapshot, error) {
+	data, err := os.ReadFile(snapshotsPath(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snaps []Snapshot
+	if err := json.Unmarshal(data, &snaps); err != nil {
+		return nil, fmt.Errorf("parse snapshots: %w", err)
+	}
+	return snaps, nil
+}
+
+func saveSnapshots(dir string, snaps []Snapshot) error {
+	data, err := json.MarshalIndent(snaps, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(snapshotsPath(dir), data, 0o600)
+}
+
+// TakeSnapshot 调用 evm_snapshot 并以 name 保存快照
+func TakeSnapshot(ctx context.Context, dir string, client *rpc.Client, name string) (*Snapshot, error) {
+	snaps, err := Snapshots(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range snaps {
+		if s.Name == name {
+			return nil, fmt.Errorf("snapshot %q already exists", name)
+		}
+	}
+
+	id, err := EVMSnapshot(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var block hexutil.Uint64
+	if err := client.CallContext(ctx, &block, "eth_blockNumber"); err != nil {
+		return nil, fmt.Errorf("eth_blockNumber: %w", err)
+	}
+
+	snap := Snapshot{Name: name, ID: id, Block: uint64(block), TakenAt: time.Now()}
+	if err := saveSnapshots(dir, append(snaps, snap)); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// RevertSnapshot 调用 evm_revert 回滚到指定快照。节点在回滚后会使该快照及其之后的
+// 所有快照失效，因此它们也会从列表中删除。
+func RevertSnapshot(ctx context.Context, dir string, client *rpc.Client, name string) (*Snapshot, error) {
+	snaps, err := Snapshots(dir)
+	if err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i, s := range snaps {
+		if s.Name == name {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("snapshot %q not found", name)
+	}
+
+	if err := EVMRevert(ctx, client, snaps[idx].ID); err != nil {
+		return nil, fmt.Errorf("revert to snapshot %q: %w", name, err)
+	}
+
+	snap := snaps[idx]
+	if err := saveSnapshots(dir, snaps[:idx]); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// EVMSnapshot 调用 evm_snapshot 保存当前链状态，返回快照 ID。与 TakeSnapshot 不同，不在状态目录中记录名称，
+// 适合测试在开始时保存、结束时回滚
+func EVMSnapshot(ctx context.Context, client *rpc.Client) (string, error) {
+	var id string
+	if err := client.CallContext(ctx, &id, "evm_snapshot"); err != nil {
+		return "", fmt.Errorf("evm_snapshot: %w", err)
+	}
+	return id, nil
+}
+
+// EVMRevert 调用 evm_revert 回滚到快照 id，该快照及其之后的快照随之失效
+func EVMRevert(ctx context.Context, client *rpc.Client, id string) error {
+	var ok bool
+	if err := client.CallContext(ctx, &ok, "evm_revert", id); err != nil {
+		return fmt.Errorf("evm_revert: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("node rejected revert to snapshot id %s", id)
+	}
+	return nil
+}
+
+// ClearSnapshots 删除所有保存的快照记录（例如节点重启之后）
+func ClearSnapshots(dir string) error {
+	err := os.Remove(snapshotsPath(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package devnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// IncreaseTime 调用 evm_increaseTime 把链上时间向前推进，返回节点报告的累计偏移秒数。
+// 新时间在下一个区块被挖出时才生效。
+func IncreaseTime(ctx context.Context, client *rpc.Client, d time.Duration) (int64, error) {
+	var raw json.RawMessage
+	if err := client.CallContext(ctx, &raw, "evm_increaseTime", int64(d/time.Second)); err != nil {
+		return 0, fmt.Errorf("evm_increaseTime: %w", err)
+	}
+	// Anvil 和 Hardhat 的返回格式不同：数字、十进制字符串或十六进制字符串
+	var n int64
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if v, ok := new(big.Int).SetString(s, 0); ok {
+			return v.Int64(), nil
+		}
+	}
+	return 0, fmt.Errorf("unexpected evm_increaseTime result %s", raw)
+}
+
+// SetNextBlockTimestamp 指定下一个区块的时间戳。Anvil 使用 anvil_ 前缀，
+// Hardhat 使用 evm_ 前缀。
+func SetNextBlockTimestamp(ctx context.Context, client *rpc.Client, kind string, ts time.Time) error {
+	method := "evm_setNextBlockTimestamp"
+	if kind == KindAnvil {
+		method = "anvil_setNextBlockTimestamp"
+	}
+	if err := client.CallContext(ctx, nil, method, ts.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return nil
+}
+
+// Mine 立即挖出 n 个区块，返回最新区块的高度和时间戳
+func Mine(ctx context.Context, client *rpc.Client, n int) (uint64, time.Time, error) {
+	for i := 0; i < n; i++ {
+		if err := client.CallContext(ctx, nil, "evm_mine"); err != nil {
+			return 0, time.Time{}, fmt.Errorf("evm_mine: %w", err)
+		}
+	}
+	var head struct {
+		Number    hexutil.Uint64 `json:"number"`
+		Timestamp hexutil.Uint64 `json:"timestamp"`
+	}
+	if err := client.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return 0, time.Time{}, fmt.Errorf("eth_getBlockByNumber: %w", err)
+	}
+	return uint64(head.Number), time.Unix(int64(head.Timestamp), 0), nil
+}
@@ -0,0 +1,435 @@
+// Package disburse 按 CSV 清单向多个地址批量转账 ETH：先校验所有行并估算总费用，再逐笔发送并等待确认。
+// 每一行的状态（已发送的交易哈希、nonce、确认结果）记录在 JSON 状态文件中，中断或失败后重新运行会跳过
+// 已完成的行，已发送但未确认的行会先查询原交易，不会重复付款。
+package disburse
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/units"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+// Payout 是清单中的一行
+type Payout struct {
+	Line   int // CSV 中的行号，从 1 开始
+	To     common.Address
+	Amount *big.Int // wei
+	Memo   string
+}
+
+// key 在状态文件中标识一行。清单中改动过的行对应新的 key，按新行处理
+func (p Payout) key() string {
+	return fmt.Sprintf("%d:%s:%s", p.Line, p.To.Hex(), p.Amount)
+}
+
+// LoadCSV 读取并校验清单文件，见 ParseCSV
+func LoadCSV(path string, resolve func(string) (common.Address, error), amount func(string) (*big.Int, error)) ([]Payout, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseCSV(f, resolve, amount)
+}
+
+// ParseCSV 解析每行 "address,amount[,memo]" 的 CSV，第一列为 "address" 的第一行视为表头，# 开头的行是注释。
+// 地址交给 resolve 解析，可以支持地址簿名称；金额交给 amount 解析，amount 为 nil 时按 ETH 金额解析：没有单位时
+// 单位是 ETH（"0.05" 即 0.05 ETH），也可以写 "50000gwei"。所有行都会检查，有错误时返回列出每个错误行的合并错误
+func ParseCSV(r io.Reader, resolve func(string) (common.Address, error), amount func(string) (*big.Int, error)) ([]Payout, error) {
+	if amount == nil {
+		amount = parseAmount
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+	var payouts []Payout
+	var errs []error
+	for first := true; ; first = false {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse payouts: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		if first && strings.EqualFold(strings.TrimSpace(rec[0]), "address") {
+			continue
+		}
+		p, err := parseRow(rec, resolve, amount)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		p.Line = line
+		payouts = append(payouts, p)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(payouts) == 0 {
+		return nil, errors.New("no payouts in file")
+	}
+	return payouts, nil
+}
+
+func parseRow(rec []string, resolve func(string) (common.Address, error), amount func(string) (*big.Int, error)) (Payout, error) {
+	if len(rec) < 2 || len(rec) > 3 {
+		return Payout{}, fmt.Errorf("want address,amount[,memo], got %d fields", len(rec))
+	}
+	var p Payout
+	to, err := resolve(strings.TrimSpace(rec[0]))
+	if err != nil {
+		return Payout{}, fmt.Errorf("address %q: %w", rec[0], err)
+	}
+	if to == (common.Address{}) {
+		return Payout{}, errors.New("zero address")
+	}
+	p.To = to
+	if p.Amount, err = amount(strings.TrimSpace(rec[1])); err != nil {
+		return Payout{}, err
+	}
+	if p.Amount.Sign() == 0 {
+		return Payout{}, errors.New("amount is zero")
+	}
+	if len(rec) == 3 {
+		p.Memo = strings.TrimSpace(rec[2])
+	}
+	return p, nil
+}
+
+// parseAmount 与 units.ParseAmount 相同，但没有单位时按 ETH 而不是 wei 解析
+func parseAmount(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s != "" && s[len(s)-1] >= '0' && s[len(s)-1] <= '9' {
+		s += " eth"
+	}
+	return units.ParseAmount(s)
+}
+
+// Total 返回 payouts 的总金额
+func Total(payouts []Payout) *big.Int {
+	total := new(big.Int)
+	for _, p := range payouts {
+		total.Add(total, p.Amount)
+	}
+	return total
+}
+
+// 行的状态
+const (
+	StatusPending   = "pending"   // 还没有发送
+	StatusSent      = "sent"      // 已广播，尚未确认
+	StatusConfirmed = "confirmed" // 已打包且成功
+	StatusFailed    = "failed"    // 发送失败或交易执行失败，重新运行时会重试
+)
+
+// Row 是一行的发送状态
+type Row struct {
+	Line    int            `json:"line"`
+	To      common.Address `json:"to"`
+	Amount  string         `json:"amount"` // wei
+	Memo    string         `json:"memo,omitempty"`
+	Status  string         `json:"status"`
+	Hash    common.Hash    `json:"hash,omitzero"`
+	Nonce   uint64         `json:"nonce,omitempty"`
+	Block   uint64         `json:"block,omitempty"`
+	GasUsed uint64         `json:"gasUsed,omitempty"`
+	Fee     string         `json:"fee,omitempty"` // 实际支付的 gas 费（wei）
+	Error   string         `json:"error,omitempty"`
+}
+
+// State 是保存在 JSON 文件中的各行状态，只属于一条链
+type State struct {
+	path string
+	file stateFile
+}
+
+type stateFile struct {
+	ChainID uint64          `json:"chainId"`
+	Rows    map[string]*Row `json:"rows"`
+}
+
+// LoadState 读取链 chainID 的状态文件，文件不存在时返回空状态，第一次保存时创建。
+// 文件属于另一条链时返回错误，避免把一条链上的完成记录当成另一条链的
+func LoadState(path string, chainID uint64) (*State, error) {
+	s := &State{path: path, file: stateFile{ChainID: chainID, Rows: make(map[string]*Row)}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.file); err != nil {
+		return nil, fmt.Errorf("parse disbursement state %s: %w", path, err)
+	}
+	if s.file.ChainID != chainID {
+		return nil, fmt.Errorf("disbursement state %s belongs to chain %d, not %d", path, s.file.ChainID, chainID)
+	}
+	if s.file.Rows == nil {
+		s.file.Rows = make(map[string]*Row)
+	}
+	return s, nil
+}
+
+// Row 返回 p 的状态，没有记录时创建 pending 状态
+func (s *State) Row(p Payout) *Row {
+	r := s.file.Rows[p.key()]
+	if r == nil {
+		r = &Row{Line: p.Line, To: p.To, Amount: p.Amount.String(), Memo: p.Memo, Status: StatusPending}
+		s.file.Rows[p.key()] = r
+	}
+	return r
+}
+
+// Save 写入状态文件。先写临时文件再改名，写到一半中断不会损坏已有的状态
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Remaining 返回还没有确认的行
+func (s *State) Remaining(payouts []Payout) []Payout {
+	var out []Payout
+	for _, p := range payouts {
+		if s.Row(p).Status != StatusConfirmed {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Plan 是发送前的费用估算
+type Plan struct {
+	Payouts []Payout // 还没有确认的行
+	Amount  *big.Int // 这些行的总金额
+	Gas     uint64   // 估算的总 gas 上限
+	GasFee  *big.Int // 按当前 maxFeePerGas（或 gas 价格）计算的最大 gas 费
+	Balance *big.Int // 发送方当前余额
+	// 分发代币时 Token 为该代币，Amount 以代币最小单位计，TokenBalance 是发送方的代币余额
+	Token        *erc20.Token
+	TokenBalance *big.Int
+}
+
+// Cost 返回需要的 ETH：总金额加最大 gas 费，分发代币时只有 gas 费
+func (p *Plan) Cost() *big.Int {
+	if p.Token != nil {
+		return new(big.Int).Set(p.GasFee)
+	}
+	return new(big.Int).Add(p.Amount, p.GasFee)
+}
+
+// Check 检查余额是否足以支付 Cost，分发代币时还检查代币余额
+func (p *Plan) Check() error {
+	if p.Token != nil && p.TokenBalance.Cmp(p.Amount) < 0 {
+		return fmt.Errorf("%w: need %s but only have %s", erc20.ErrInsufficientTokens, p.Token.Format(p.Amount), p.Token.Format(p.TokenBalance))
+	}
+	if p.Balance.Cmp(p.Cost()) < 0 {
+		return fmt.Errorf("%w: need %s wei but only have %s wei", ethtx.ErrInsufficientFunds, p.Cost(), p.Balance)
+	}
+	return nil
+}
+
+// NewPlan 为 payouts 中还没有确认的行逐个估算 gas（同时检查接收方能否接收 ETH），按 strategy 的
+// maxFeePerGas（零值为 ethtx.StandardFees）计算最大 gas 费。估算失败的行全部列在返回的错误中
+func NewPlan(ctx context.Context, client chain.Client, from common.Address, payouts []Payout, state *State, strategy ethtx.FeeStrategy) (*Plan, error) {
+	balance, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	price, err := maxGasPrice(ctx, client, strategy)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{Payouts: state.Remaining(payouts), Balance: balance}
+	plan.Amount = Total(plan.Payouts)
+	var errs []error
+	for _, p := range plan.Payouts {
+		to := p.To
+		gas, err := ethtx.EstimateGas(ctx, client, ethereum.CallMsg{From: from, To: &to, Value: p.Amount}, ethtx.DefaultGasBuffer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d (%s): %w", p.Line, p.To.Hex(), err))
+			continue
+		}
+		plan.Gas += gas
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	plan.GasFee = new(big.Int).Mul(price, new(big.Int).SetUint64(plan.Gas))
+	return plan, nil
+}
+
+// maxGasPrice 返回按 strategy（零值为 ethtx.StandardFees）估算的 maxFeePerGas，链不支持 EIP-1559 时返回 eth_gasPrice
+func maxGasPrice(ctx context.Context, client chain.Client, strategy ethtx.FeeStrategy) (*big.Int, error) {
+	if strategy == (ethtx.FeeStrategy{}) {
+		strategy = ethtx.StandardFees
+	}
+	fees, err := ethtx.SuggestFeesWith(ctx, client, strategy)
+	if err == nil {
+		return fees.GasFeeCap, nil
+	}
+	if !errors.Is(err, ethtx.ErrNoDynamicFees) {
+		return nil, err
+	}
+	price, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+	}
+	return price, nil
+}
+
+// Options 控制 Run
+type Options struct {
+	Strategy      ethtx.FeeStrategy // 零值表示 ethtx.StandardFees
+	Confirmations uint64            // 每笔交易等待的确认数，0 与 1 相同
+	// OnSent 在交易广播后调用，OnDone 在一行确认或失败后调用，可以为 nil
+	OnSent func(Payout, *Row)
+	OnDone func(Payout, *Row)
+}
+
+// Run 依次处理 payouts 中还没有确认的行：发送转账、等待确认，每一步都保存状态。遇到错误时停止并返回，
+// 已完成的行保留在状态中，重新运行时从出错的行继续。状态为 sent 的行先查询原交易：仍在等待就继续等待，
+// 已经打包就直接记录结果；节点上找不到时重新发送，并尽量复用原来的 nonce，保证新旧交易最多只有一笔上链。
+func Run(ctx context.Context, client chain.Client, w wallet.Signer, payouts []Payout, state *State, opts Options) error {
+	if opts.Strategy == (ethtx.FeeStrategy{}) {
+		opts.Strategy = ethtx.StandardFees
+	}
+	for _, p := range payouts {
+		r := state.Row(p)
+		if r.Status == StatusConfirmed {
+			continue
+		}
+		var tx *types.Transaction
+		var err error
+		if r.Status == StatusSent {
+			if tx, err = resume(ctx, client, r); err != nil {
+				return fmt.Errorf("line %d: %w", p.Line, err)
+			}
+		}
+		if tx == nil {
+			if tx, err = send(ctx, client, w, p, r, opts.Strategy); err != nil {
+				r.Status, r.Error = StatusFailed, err.Error()
+				return errors.Join(fmt.Errorf("line %d: %w", p.Line, err), state.Save())
+			}
+			if err := state.Save(); err != nil {
+				return err
+			}
+			if opts.OnSent != nil {
+				opts.OnSent(p, r)
+			}
+		}
+
+		receipt, err := ethtx.WaitConfirmed(ctx, client, tx, max(opts.Confirmations, 1))
+		if receipt == nil {
+			// 等待被中断时保持 sent 状态，下次运行继续等待
+			return fmt.Errorf("line %d: %w", p.Line, err)
+		}
+		r.Block, r.GasUsed = receipt.BlockNumber.Uint64(), receipt.GasUsed
+		if receipt.EffectiveGasPrice != nil {
+			r.Fee = new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)).String()
+		}
+		r.Status, r.Error = StatusConfirmed, ""
+		if err != nil {
+			r.Status, r.Error = StatusFailed, err.Error()
+		}
+		if serr := state.Save(); serr != nil {
+			return serr
+		}
+		if opts.OnDone != nil {
+			opts.OnDone(p, r)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", p.Line, err)
+		}
+	}
+	return nil
+}
+
+// send 构造并广播一行的转账，把交易哈希和 nonce 记录到 r
+func send(ctx context.Context, client chain.Client, w wallet.Signer, p Payout, r *Row, strategy ethtx.FeeStrategy) (*types.Transaction, error) {
+	t, err := ethtx.Prepare(ctx, client, w.Address(), p.To, p.Amount)
+	if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+		return nil, err
+	}
+	if strategy != ethtx.StandardFees {
+		if err := t.SetFees(ctx, client, strategy); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkNonce(ctx, client, w.Address(), r, t.Nonce); err != nil {
+		return nil, err
+	}
+	if err := t.Check(); err != nil {
+		return nil, err
+	}
+	tx, err := ethtx.Send(ctx, client, w, t)
+	if err != nil {
+		return nil, err
+	}
+	r.Status, r.Hash, r.Nonce, r.Error = StatusSent, tx.Hash(), tx.Nonce(), ""
+	return tx, nil
+}
+
+// checkNonce 检查重新发送 r 时下一笔交易的 nonce。原交易的 nonce 还没有被使用时，下一笔交易必须使用同一个
+// nonce，这样原交易即使之后重新出现也只能与新交易二选一，不会重复付款
+func checkNonce(ctx context.Context, client chain.Client, from common.Address, r *Row, next uint64) error {
+	if r.Hash == (common.Hash{}) {
+		return nil
+	}
+	latest, err := client.NonceAt(ctx, from, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	if latest <= r.Nonce && next != r.Nonce {
+		return fmt.Errorf("previous transaction %s with nonce %d is unknown to the node but its nonce is unused and the next nonce is %d, settle the pending transactions and retry",
+			r.Hash.Hex(), r.Nonce, next)
+	}
+	return nil
+}
+
+// resume 查询状态为 sent 的行的原交易，返回仍需等待的交易；节点上找不到原交易时返回 nil，由调用方重新发送
+func resume(ctx context.Context, client chain.Client, r *Row) (*types.Transaction, error) {
+	tx, _, err := client.TransactionByHash(ctx, r.Hash)
+	if err == nil {
+		return tx, nil
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return nil, fmt.Errorf("look up transaction %s: %w", r.Hash.Hex(), err)
+	}
+	return nil, nil
+}
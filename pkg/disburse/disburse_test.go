@@ -0,0 +1,110 @@
+package disburse
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/internal/simchain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+func hexOnly(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, errors.New("not an address")
+	}
+	return common.HexToAddress(s), nil
+}
+
+func TestParseCSV(t *testing.T) {
+	payouts, err := ParseCSV(strings.NewReader(`address,amount,memo
+# 注释行
+0x1111111111111111111111111111111111111111,0.5,October
+0x2222222222222222222222222222222222222222, 20000gwei
+`), hexOnly, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payouts) != 2 || payouts[0].Line != 3 || payouts[0].Memo != "October" || payouts[1].Line != 4 {
+		t.Fatalf("payouts = %+v", payouts)
+	}
+	if got := Total(payouts).String(); got != "500020000000000000" {
+		t.Errorf("Total = %s", got)
+	}
+
+	_, err = ParseCSV(strings.NewReader(`0x1111111111111111111111111111111111111111,1
+alice,1
+0x2222222222222222222222222222222222222222,0
+0x3333333333333333333333333333333333333333,1,memo,extra
+`), hexOnly, nil)
+	for _, want := range []string{"line 2:", "line 3: amount is zero", "line 4:"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("error %v does not mention %q", err, want)
+		}
+	}
+}
+
+func TestRunResume(t *testing.T) {
+	c := simchain.New(t, 1)
+	client, w := c.Client(), c.Wallets[0]
+	payouts, err := ParseCSV(strings.NewReader(`0x1111111111111111111111111111111111111111,0.1
+0x2222222222222222222222222222222222222222,0.2
+0x3333333333333333333333333333333333333333,0.3
+`), hexOnly, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "payouts.state.json")
+	state, err := LoadState(path, c.ChainID.Uint64())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := NewPlan(context.Background(), client, w.Address(), payouts, state, ethtx.FeeStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Gas != 3*21000 || plan.Amount.String() != "600000000000000000" || plan.Check() != nil {
+		t.Errorf("plan = %+v", plan)
+	}
+
+	// 第二行广播后中断，模拟进程在等待确认时退出
+	ctx, cancel := context.WithCancel(context.Background())
+	err = Run(ctx, client, w, payouts, state, Options{OnSent: func(p Payout, r *Row) {
+		if p.Line == 2 {
+			cancel()
+		}
+	}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted Run = %v, want context canceled", err)
+	}
+
+	state, err = LoadState(path, c.ChainID.Uint64())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := state.Row(payouts[1]); r.Status != StatusSent || r.Hash == (common.Hash{}) {
+		t.Fatalf("row 2 after interruption = %+v, want sent", r)
+	}
+	if err := Run(context.Background(), client, w, payouts, state, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range payouts {
+		if r := state.Row(p); r.Status != StatusConfirmed || r.Block == 0 || r.Fee == "" {
+			t.Errorf("row %d = %+v, want confirmed", i+1, r)
+		}
+		if got, _ := client.BalanceAt(context.Background(), p.To, nil); got.Cmp(p.Amount) != 0 {
+			t.Errorf("%s received %s, want %s", p.To.Hex(), got, p.Amount)
+		}
+	}
+	if nonce, _ := client.NonceAt(context.Background(), w.Address(), nil); nonce != 3 {
+		t.Errorf("sender nonce = %d, want 3 transactions", nonce)
+	}
+
+	if _, err := LoadState(path, 1); err == nil {
+		t.Error("LoadState accepted a state file from another chain")
+	}
+}
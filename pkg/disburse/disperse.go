@@ -0,0 +1,430 @@
+package disburse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+// DefaultDisperseAddress 是 disperse.app 的 Disperse 合约地址，以太坊主网和许多测试网、L2 上都部署在这个地址
+var DefaultDisperseAddress = common.HexToAddress("0xD152f549545093347A162Dce210e7293f1452150")
+
+// DefaultBatchSize 是每笔 Disperse 交易默认包含的接收方数
+const DefaultBatchSize = 100
+
+// tokenGasPerRecipient 是还没有授权、无法估算时按每个接收方预留的代币转账 gas，approveGas 是 approve 的 gas
+const (
+	tokenGasPerRecipient = 40000
+	approveGas           = 60000
+)
+
+// DisperseABI 是 Disperse 合约的接口。合约在一笔交易中逐个转账，任何一笔失败整个交易回滚；
+// disperseEther 多付的 ETH 退回发送方；disperseToken 先把总额 transferFrom 到合约，再由合约转给每个接收方。
+// 合约本身不发出事件
+var DisperseABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"disperseEther","stateMutability":"payable","inputs":[{"name":"recipients","type":"address[]"},{"name":"values","type":"uint256[]"}],"outputs":[]},
+{"type":"function","name":"disperseToken","stateMutability":"nonpayable","inputs":[{"name":"token","type":"address"},{"name":"recipients","type":"address[]"},{"name":"values","type":"uint256[]"}],"outputs":[]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+func recipients(payouts []Payout) ([]common.Address, []*big.Int) {
+	to := make([]common.Address, len(payouts))
+	values := make([]*big.Int, len(payouts))
+	for i, p := range payouts {
+		to[i], values[i] = p.To, p.Amount
+	}
+	return to, values
+}
+
+// DisperseEtherData 返回 disperseEther(recipients, values) 的调用数据，交易的 value 应为 Total(payouts)
+func DisperseEtherData(payouts []Payout) []byte {
+	to, values := recipients(payouts)
+	data, err := DisperseABI.Pack("disperseEther", to, values)
+	if err != nil {
+		panic(err) // 参数类型固定，不会失败
+	}
+	return data
+}
+
+// DisperseTokenData 返回 disperseToken(token, recipients, values) 的调用数据，发送前需要授权合约使用 Total(payouts)
+func DisperseTokenData(token common.Address, payouts []Payout) []byte {
+	to, values := recipients(payouts)
+	data, err := DisperseABI.Pack("disperseToken", token, to, values)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// DisperseOptions 控制 RunDisperse
+type DisperseOptions struct {
+	Options
+	Contract  common.Address // 零值为 DefaultDisperseAddress
+	Token     *erc20.Token   // 分发的代币，nil 表示 ETH
+	BatchSize int            // 每笔交易的接收方数，不大于 0 时为 DefaultBatchSize
+	// OnApprove 在发送代币 approve 交易后调用，可以为 nil
+	OnApprove func(tx *types.Transaction)
+}
+
+func (o DisperseOptions) withDefaults() DisperseOptions {
+	if o.Contract == (common.Address{}) {
+		o.Contract = DefaultDisperseAddress
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.Strategy == (ethtx.FeeStrategy{}) {
+		o.Strategy = ethtx.StandardFees
+	}
+	return o
+}
+
+// batches 把 payouts 分成最多 size 行一组
+func batches(payouts []Payout, size int) [][]Payout {
+	var out [][]Payout
+	for len(payouts) > 0 {
+		n := min(size, len(payouts))
+		out = append(out, payouts[:n])
+		payouts = payouts[n:]
+	}
+	return out
+}
+
+// checkContract 确认地址上部署了合约
+func checkContract(ctx context.Context, client chain.Client, address common.Address) error {
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get code: %w", err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no Disperse contract at %s on this chain", address.Hex())
+	}
+	return nil
+}
+
+// NewDispersePlan 与 NewPlan 相同，但按 Disperse 批次估算 gas。代币还没有授权足够的额度时 transferFrom
+// 会失败、无法估算，gas 按每个接收方 tokenGasPerRecipient 加一笔 approve 粗略计算
+func NewDispersePlan(ctx context.Context, client chain.Client, from common.Address, payouts []Payout, state *State, opts DisperseOptions) (*Plan, error) {
+	opts = opts.withDefaults()
+	if err := checkContract(ctx, client, opts.Contract); err != nil {
+		return nil, err
+	}
+	balance, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	price, err := maxGasPrice(ctx, client, opts.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{Payouts: state.Remaining(payouts), Balance: balance, Token: opts.Token}
+	plan.Amount = Total(plan.Payouts)
+
+	estimate := true
+	if opts.Token != nil {
+		if plan.TokenBalance, err = opts.Token.BalanceOf(ctx, client, from); err != nil {
+			return nil, err
+		}
+		allowance, err := opts.Token.Allowance(ctx, client, from, opts.Contract)
+		if err != nil {
+			return nil, err
+		}
+		if allowance.Cmp(plan.Amount) < 0 {
+			estimate = false
+			plan.Gas = approveGas + uint64(len(plan.Payouts))*tokenGasPerRecipient
+		}
+	}
+	for _, batch := range batches(plan.Payouts, opts.BatchSize) {
+		if !estimate {
+			break
+		}
+		msg := ethereum.CallMsg{From: from, To: &opts.Contract, Value: Total(batch), Data: DisperseEtherData(batch)}
+		if opts.Token != nil {
+			msg.Value, msg.Data = nil, DisperseTokenData(opts.Token.Address, batch)
+		}
+		gas, err := ethtx.EstimateGas(ctx, client, msg, ethtx.DefaultGasBuffer)
+		if err != nil {
+			return nil, fmt.Errorf("batch starting at line %d: %w", batch[0].Line, err)
+		}
+		plan.Gas += gas
+	}
+	plan.GasFee = new(big.Int).Mul(price, new(big.Int).SetUint64(plan.Gas))
+	return plan, nil
+}
+
+// RunDisperse 与 Run 相同，但通过 Disperse 合约每笔交易支付 BatchSize 个接收方。先处理上次已发送但未确认的批次：
+// 原交易还在就等待它，节点上找不到时按原来的批次和 nonce 顺序重新发送；再把其余未完成的行分批发送。
+// 分发代币时如果授权额度不够，先发送一笔 approve(合约, 剩余总额) 并等待确认。
+// 交易打包后逐个核对每个接收方是否收到：代币按收据中合约发出的 Transfer 事件核对；合约转账 ETH 不产生事件，
+// 按接收方在该区块前后的余额变化核对。核对不上的行标记为失败
+func RunDisperse(ctx context.Context, client chain.Client, w wallet.Signer, payouts []Payout, state *State, opts DisperseOptions) error {
+	opts = opts.withDefaults()
+	if err := checkContract(ctx, client, opts.Contract); err != nil {
+		return err
+	}
+
+	// 上次已发送的批次按 nonce 排序，其余的行重新分批
+	sent := make(map[common.Hash][]Payout)
+	var fresh []Payout
+	for _, p := range state.Remaining(payouts) {
+		if r := state.Row(p); r.Status == StatusSent {
+			sent[r.Hash] = append(sent[r.Hash], p)
+		} else {
+			fresh = append(fresh, p)
+		}
+	}
+	var resend [][]Payout
+	for _, batch := range sortedBatches(sent, state) {
+		tx, err := resume(ctx, client, state.Row(batch[0]))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", batch[0].Line, err)
+		}
+		if tx == nil {
+			resend = append(resend, batch)
+			continue
+		}
+		if err := finishBatch(ctx, client, tx, batch, state, opts); err != nil {
+			return err
+		}
+	}
+	toSend := append(resend, batches(fresh, opts.BatchSize)...)
+	if len(toSend) == 0 {
+		return nil
+	}
+
+	if opts.Token != nil {
+		var total []Payout
+		for _, batch := range toSend {
+			total = append(total, batch...)
+		}
+		if err := approve(ctx, client, w, opts, Total(total)); err != nil {
+			return err
+		}
+	}
+	for _, batch := range toSend {
+		tx, err := sendBatch(ctx, client, w, batch, state, opts)
+		if err != nil {
+			return err
+		}
+		if err := finishBatch(ctx, client, tx, batch, state, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedBatches 按原交易的 nonce 排序已发送的批次
+func sortedBatches(sent map[common.Hash][]Payout, state *State) [][]Payout {
+	out := make([][]Payout, 0, len(sent))
+	for _, batch := range sent {
+		out = append(out, batch)
+	}
+	sort.Slice(out, func(i, j int) bool { return state.Row(out[i][0]).Nonce < state.Row(out[j][0]).Nonce })
+	return out
+}
+
+// approve 在授权额度小于 amount 时授权 Disperse 合约使用 amount 个代币，并等待确认
+func approve(ctx context.Context, client chain.Client, w wallet.Signer, opts DisperseOptions, amount *big.Int) error {
+	allowance, err := opts.Token.Allowance(ctx, client, w.Address(), opts.Contract)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return nil
+	}
+	t, err := ethtx.PrepareCall(ctx, client, w.Address(), opts.Token.Address, new(big.Int), erc20.ApproveData(opts.Contract, amount))
+	if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+		return err
+	}
+	if err := setFees(ctx, client, t, opts.Strategy); err != nil {
+		return err
+	}
+	tx, err := ethtx.Send(ctx, client, w, t)
+	if err != nil {
+		return fmt.Errorf("approve: %w", err)
+	}
+	if opts.OnApprove != nil {
+		opts.OnApprove(tx)
+	}
+	if _, err := ethtx.WaitConfirmed(ctx, client, tx, max(opts.Confirmations, 1)); err != nil {
+		return fmt.Errorf("approve: %w", err)
+	}
+	return nil
+}
+
+// setFees 按策略调整费用并检查余额
+func setFees(ctx context.Context, client chain.Client, t *ethtx.Transfer, strategy ethtx.FeeStrategy) error {
+	if strategy != ethtx.StandardFees {
+		if err := t.SetFees(ctx, client, strategy); err != nil {
+			return err
+		}
+	}
+	return t.Check()
+}
+
+// sendBatch 广播一个批次的 Disperse 调用，把交易哈希和 nonce 记录到批次的每一行
+func sendBatch(ctx context.Context, client chain.Client, w wallet.Signer, batch []Payout, state *State, opts DisperseOptions) (*types.Transaction, error) {
+	value, data := Total(batch), DisperseEtherData(batch)
+	if opts.Token != nil {
+		value, data = new(big.Int), DisperseTokenData(opts.Token.Address, batch)
+	}
+	tx, err := func() (*types.Transaction, error) {
+		t, err := ethtx.PrepareCall(ctx, client, w.Address(), opts.Contract, value, data)
+		if err != nil && !errors.Is(err, ethtx.ErrInsufficientFunds) {
+			return nil, err
+		}
+		if err := checkNonce(ctx, client, w.Address(), state.Row(batch[0]), t.Nonce); err != nil {
+			return nil, err
+		}
+		if err := setFees(ctx, client, t, opts.Strategy); err != nil {
+			return nil, err
+		}
+		return ethtx.Send(ctx, client, w, t)
+	}()
+	for _, p := range batch {
+		r := state.Row(p)
+		if err != nil {
+			r.Status, r.Error = StatusFailed, err.Error()
+		} else {
+			r.Status, r.Hash, r.Nonce, r.Error = StatusSent, tx.Hash(), tx.Nonce(), ""
+		}
+	}
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("batch starting at line %d: %w", batch[0].Line, err), state.Save())
+	}
+	if err := state.Save(); err != nil {
+		return nil, err
+	}
+	if opts.OnSent != nil {
+		for _, p := range batch {
+			opts.OnSent(p, state.Row(p))
+		}
+	}
+	return tx, nil
+}
+
+// finishBatch 等待批次交易确认，核对每个接收方，记录每一行的结果
+func finishBatch(ctx context.Context, client chain.Client, tx *types.Transaction, batch []Payout, state *State, opts DisperseOptions) error {
+	receipt, err := ethtx.WaitConfirmed(ctx, client, tx, max(opts.Confirmations, 1))
+	if receipt == nil {
+		return fmt.Errorf("batch starting at line %d: %w", batch[0].Line, err)
+	}
+	problems := make([]error, len(batch))
+	var verifyErr error
+	switch {
+	case err != nil:
+		for i := range problems {
+			problems[i] = err
+		}
+	case opts.Token != nil:
+		problems = verifyTokens(receipt, batch, opts.Token, opts.Contract)
+	default:
+		problems, verifyErr = verifyEther(ctx, client, receipt, batch, tx)
+	}
+
+	fee := ""
+	if receipt.EffectiveGasPrice != nil {
+		fee = new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed)).String()
+	}
+	failed := 0
+	for i, p := range batch {
+		r := state.Row(p)
+		r.Block, r.GasUsed, r.Fee = receipt.BlockNumber.Uint64(), receipt.GasUsed, fee
+		r.Status, r.Error = StatusConfirmed, ""
+		if problems[i] != nil {
+			r.Status, r.Error = StatusFailed, problems[i].Error()
+			failed++
+		}
+	}
+	if err := state.Save(); err != nil {
+		return err
+	}
+	if opts.OnDone != nil {
+		for _, p := range batch {
+			opts.OnDone(p, state.Row(p))
+		}
+	}
+	switch {
+	case err != nil:
+		return fmt.Errorf("batch starting at line %d: %w", batch[0].Line, err)
+	case failed > 0:
+		return fmt.Errorf("%d payout(s) in transaction %s could not be verified", failed, tx.Hash().Hex())
+	case verifyErr != nil:
+		return fmt.Errorf("transaction %s succeeded but balances could not be checked: %w", tx.Hash().Hex(), verifyErr)
+	}
+	return nil
+}
+
+// verifyTokens 在收据中为每一行找一个由合约转给接收方、金额相同的 Transfer 事件，每个事件只能对应一行
+func verifyTokens(receipt *types.Receipt, batch []Payout, token *erc20.Token, contract common.Address) []error {
+	transfers := token.Transfers(receipt.Logs)
+	used := make([]bool, len(transfers))
+	problems := make([]error, len(batch))
+	for i, p := range batch {
+		found := false
+		for j, t := range transfers {
+			if !used[j] && t.From == contract && t.To == p.To && t.Value.Cmp(p.Amount) == 0 {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			problems[i] = fmt.Errorf("no Transfer of %s to %s in the receipt", token.Format(p.Amount), p.To.Hex())
+		}
+	}
+	return problems
+}
+
+// verifyEther 比较每个接收方在交易所在区块和前一个区块的余额，增加的数量应不少于该批次付给它的总额。
+// 同一区块中接收方的其他交易也会改变余额，所以只检查下限；发送方自己和合约不检查。
+// 节点无法提供前一个区块的状态时返回错误，此时交易成功已经说明每一笔转账都完成了
+func verifyEther(ctx context.Context, client chain.Client, receipt *types.Receipt, batch []Payout, tx *types.Transaction) ([]error, error) {
+	problems := make([]error, len(batch))
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return problems, err
+	}
+	want := make(map[common.Address]*big.Int)
+	for _, p := range batch {
+		if want[p.To] == nil {
+			want[p.To] = new(big.Int)
+		}
+		want[p.To].Add(want[p.To], p.Amount)
+	}
+	block := receipt.BlockNumber
+	prev := new(big.Int).Sub(block, big.NewInt(1))
+	for i, p := range batch {
+		if p.To == from || p.To == *tx.To() {
+			continue
+		}
+		before, err := client.BalanceAt(ctx, p.To, prev)
+		if err != nil {
+			return problems, err
+		}
+		after, err := client.BalanceAt(ctx, p.To, block)
+		if err != nil {
+			return problems, err
+		}
+		if got := new(big.Int).Sub(after, before); got.Cmp(want[p.To]) < 0 {
+			problems[i] = fmt.Errorf("%s received %s wei in block %s, want %s wei", p.To.Hex(), got, block, want[p.To])
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,68 @@
+package disburse
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/erc20"
+)
+
+func TestDisperseData(t *testing.T) {
+	payouts, err := ParseCSV(strings.NewReader(`0x1111111111111111111111111111111111111111,1
+0x2222222222222222222222222222222222222222,2
+0x3333333333333333333333333333333333333333,3
+`), hexOnly, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := batches(payouts, 2); len(got) != 2 || len(got[0]) != 2 || got[1][0].Line != 3 {
+		t.Errorf("batches = %+v", got)
+	}
+
+	data := DisperseTokenData(common.Address{0xaa}, payouts)
+	method, err := DisperseABI.MethodById(data[:4])
+	if err != nil || method.Name != "disperseToken" {
+		t.Fatalf("method = %v, %v", method, err)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, values := args[1].([]common.Address), args[2].([]*big.Int)
+	if args[0].(common.Address) != (common.Address{0xaa}) || len(to) != 3 || to[2] != payouts[2].To || values[1].Cmp(payouts[1].Amount) != 0 {
+		t.Errorf("disperseToken args = %v", args)
+	}
+}
+
+func TestVerifyTokens(t *testing.T) {
+	token := &erc20.Token{Address: common.Address{0xaa}, Symbol: "USDC", Decimals: 6}
+	contract := DefaultDisperseAddress
+	event := erc20.ABI.Events["Transfer"]
+	transfer := func(from, to common.Address, value int64) *types.Log {
+		data, _ := event.Inputs.NonIndexed().Pack(big.NewInt(value))
+		return &types.Log{
+			Address: token.Address,
+			Topics:  []common.Hash{event.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:    data,
+		}
+	}
+	alice, bob := common.Address{1}, common.Address{2}
+	batch := []Payout{
+		{Line: 1, To: alice, Amount: big.NewInt(5)},
+		{Line: 2, To: alice, Amount: big.NewInt(5)},
+		{Line: 3, To: bob, Amount: big.NewInt(7)},
+	}
+	receipt := &types.Receipt{Logs: []*types.Log{
+		transfer(common.Address{9}, contract, 17), // transferFrom 到合约
+		transfer(contract, alice, 5),
+		transfer(contract, bob, 6),
+	}}
+	problems := verifyTokens(receipt, batch, token, contract)
+	// 第二笔给 alice 的 5 没有单独的事件，bob 的金额不对
+	if problems[0] != nil || problems[1] == nil || problems[2] == nil {
+		t.Errorf("problems = %v", problems)
+	}
+}
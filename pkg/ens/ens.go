@@ -0,0 +1,248 @@
+// Package ens 通过 ENS 反向解析查找地址的主名称（primary name），输出时显示为 0xAbCd…1234 (alice.eth)。
+// 反向解析得到的名称还要正向解析回同一地址才被接受，防止任何人为他人地址设置误导性的反向记录。
+// 结果（包括没有名称）会被缓存，同一地址只查询一次；一次查询多个地址时通过 Multicall3 分阶段聚合。
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/multicall"
+)
+
+// Registry 是 ENS 注册表在主网和 Sepolia、Holesky 测试网上的地址
+var Registry = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+// ABI 包含注册表的 resolver 和解析器的 name、addr 方法
+var ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"resolver","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"name","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"addr","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// NameHash 按 EIP-137 计算名称的 namehash，名称按小写处理
+func NameHash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node[:], crypto.Keccak256([]byte(labels[i])))
+	}
+	return node
+}
+
+// ReverseNode 返回地址的反向记录节点 <小写十六进制地址>.addr.reverse
+func ReverseNode(addr common.Address) common.Hash {
+	return NameHash(strings.ToLower(addr.Hex()[2:]) + ".addr.reverse")
+}
+
+// Resolver 反向解析地址并缓存结果。nil 的 *Resolver 表示链上没有 ENS，所有地址都没有名称
+type Resolver struct {
+	client   chain.Client
+	registry common.Address
+	mc       *multicall.Caller // 非 nil 时一次查询多个地址用 Multicall3 聚合
+
+	mu    sync.Mutex
+	cache map[common.Address]string
+}
+
+// New 返回使用 registry 注册表的 Resolver，registry 为零地址（链上没有 ENS）时返回 nil。
+// mc 可以为 nil，此时多个地址逐个查询
+func New(client chain.Client, registry common.Address, mc *multicall.Caller) *Resolver {
+	if registry == (common.Address{}) {
+		return nil
+	}
+	return &Resolver{client: client, registry: registry, mc: mc, cache: make(map[common.Address]string)}
+}
+
+// Name 返回地址经过正向验证的主名称，没有名称时返回空字符串
+func (r *Resolver) Name(ctx context.Context, addr common.Address) (string, error) {
+	names, err := r.Names(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+	return names[0], nil
+}
+
+// Names 一次查询多个地址的主名称，结果与 addrs 一一对应。已缓存的地址不再查询，
+// 其余地址分四个阶段（解析器、反向名称、名称的解析器、正向地址）各用一次聚合调用完成
+func (r *Resolver) Names(ctx context.Context, addrs ...common.Address) ([]string, error) {
+	names := make([]string, len(addrs))
+	if r == nil {
+		return names, nil
+	}
+	r.mu.Lock()
+	var pending []common.Address
+	for i, addr := range addrs {
+		name, ok := r.cache[addr]
+		names[i] = name
+		if !ok && !slices.Contains(pending, addr) {
+			pending = append(pending, addr)
+		}
+	}
+	r.mu.Unlock()
+	if len(pending) == 0 {
+		return names, nil
+	}
+
+	found, err := r.lookup(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	for i, addr := range pending {
+		r.cache[addr] = found[i]
+	}
+	for i, addr := range addrs {
+		names[i] = r.cache[addr]
+	}
+	r.mu.Unlock()
+	return names, nil
+}
+
+// Label 返回用于显示的地址：有主名称时为 0xAbCd…1234 (alice.eth)，否则（包括查询失败）为完整地址
+func (r *Resolver) Label(ctx context.Context, addr common.Address) string {
+	name, err := r.Name(ctx, addr)
+	if err != nil || name == "" {
+		return addr.Hex()
+	}
+	return Short(addr) + " (" + name + ")"
+}
+
+// Short 返回缩写的地址，如 0xAbCd…1234
+func Short(addr common.Address) string {
+	hex := addr.Hex()
+	return hex[:6] + "…" + hex[len(hex)-4:]
+}
+
+func (r *Resolver) lookup(ctx context.Context, addrs []common.Address) ([]string, error) {
+	nodes := make([]common.Hash, len(addrs))
+	for i, addr := range addrs {
+		nodes[i] = ReverseNode(addr)
+	}
+	// 1. 反向节点的解析器
+	resolvers, err := r.resolvers(ctx, nodes)
+	if err != nil {
+		return nil, err
+	}
+	// 2. 解析器上记录的名称
+	calls := make([]target, len(addrs))
+	for i := range addrs {
+		calls[i] = target{resolvers[i], "name", nodes[i]}
+	}
+	results, err := r.call(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(addrs))
+	forward := make([]common.Hash, len(addrs))
+	for i, out := range results {
+		if len(out) > 0 {
+			names[i] = out[0].(string)
+			forward[i] = NameHash(names[i])
+		}
+	}
+	// 3 和 4. 正向解析名称，只接受解析回同一地址的名称
+	resolvers, err = r.resolvers(ctx, forward)
+	if err != nil {
+		return nil, err
+	}
+	for i := range addrs {
+		calls[i] = target{resolvers[i], "addr", forward[i]}
+	}
+	if results, err = r.call(ctx, calls); err != nil {
+		return nil, err
+	}
+	for i, out := range results {
+		if len(out) == 0 || out[0].(common.Address) != addrs[i] {
+			names[i] = ""
+		}
+	}
+	return names, nil
+}
+
+// resolvers 查询各节点的解析器，零节点（上一阶段没有结果）不查询
+func (r *Resolver) resolvers(ctx context.Context, nodes []common.Hash) ([]common.Address, error) {
+	calls := make([]target, len(nodes))
+	for i, node := range nodes {
+		if node != (common.Hash{}) {
+			calls[i] = target{r.registry, "resolver", node}
+		}
+	}
+	results, err := r.call(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]common.Address, len(nodes))
+	for i, out := range results {
+		if len(out) > 0 {
+			resolvers[i] = out[0].(common.Address)
+		}
+	}
+	return resolvers, nil
+}
+
+// target 是对 to 调用 method(node)，to 为零地址表示跳过
+type target struct {
+	to     common.Address
+	method string
+	node   common.Hash
+}
+
+// call 执行一批调用，返回解码后的结果；跳过、回滚或无法解码的调用结果为 nil。
+// 有 Multicall3 时合并为一次 eth_call，链上没有部署时退回逐个调用
+func (r *Resolver) call(ctx context.Context, targets []target) ([][]interface{}, error) {
+	out := make([][]interface{}, len(targets))
+	var calls []multicall.Call
+	var index []int
+	for i, t := range targets {
+		if t.to == (common.Address{}) {
+			continue
+		}
+		call, err := multicall.Pack(t.to, &ABI, t.method, t.node)
+		if err != nil {
+			return nil, err
+		}
+		calls, index = append(calls, call), append(index, i)
+	}
+	if len(calls) == 0 {
+		return out, nil
+	}
+	var results []multicall.Result
+	var err error
+	if r.mc != nil {
+		results, err = r.mc.Aggregate(ctx, calls, nil)
+	}
+	if r.mc == nil || errors.Is(err, multicall.ErrNotDeployed) {
+		results = make([]multicall.Result, len(calls))
+		for i, c := range calls {
+			data, err := r.client.CallContract(ctx, ethereum.CallMsg{To: &c.Target, Data: c.Data}, nil)
+			results[i] = multicall.Result{Success: err == nil, ReturnData: data}
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("ens lookup: %w", err)
+	}
+	for i, res := range results {
+		if values, err := res.Unpack(&ABI, targets[index[i]].method); err == nil {
+			out[index[i]] = values
+		}
+	}
+	return out, nil
+}
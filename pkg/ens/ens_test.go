@@ -0,0 +1,104 @@
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/internal/multicalltest"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/multicall"
+)
+
+var (
+	resolverAddr = common.HexToAddress("0x4444444444444444444444444444444444444444")
+	alice        = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	mallory      = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	nobody       = common.HexToAddress("0x3333333333333333333333333333333333333333")
+)
+
+// newENS 模拟注册表和一个解析器：alice 的反向记录是 alice.eth，mallory 也把反向记录设为 alice.eth
+// （正向解析不会指回 mallory），nobody 没有反向记录。calls 统计 eth_call 次数
+func newENS(calls *int) *chain.ClientMock {
+	records := map[common.Hash]string{ReverseNode(alice): "alice.eth", ReverseNode(mallory): "alice.eth"}
+	return &chain.ClientMock{
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			*calls++
+			method, err := ABI.MethodById(call.Data[:4])
+			if err != nil {
+				return nil, err
+			}
+			args, err := method.Inputs.Unpack(call.Data[4:])
+			if err != nil {
+				return nil, err
+			}
+			node := common.Hash(args[0].([32]byte))
+			switch {
+			case *call.To == Registry && method.Name == "resolver":
+				if _, ok := records[node]; ok || node == NameHash("alice.eth") {
+					return method.Outputs.Pack(resolverAddr)
+				}
+				return method.Outputs.Pack(common.Address{})
+			case *call.To == resolverAddr && method.Name == "name":
+				return method.Outputs.Pack(records[node])
+			case *call.To == resolverAddr && method.Name == "addr" && node == NameHash("alice.eth"):
+				return method.Outputs.Pack(alice)
+			}
+			return nil, errors.New("execution reverted")
+		},
+	}
+}
+
+func TestNameHash(t *testing.T) {
+	// EIP-137 中的示例
+	if got := NameHash("eth").Hex(); got != "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae" {
+		t.Errorf("NameHash(eth) = %s", got)
+	}
+	if got := NameHash("foo.eth").Hex(); got != "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f" {
+		t.Errorf("NameHash(foo.eth) = %s", got)
+	}
+}
+
+func TestLabelVerifiesAndCaches(t *testing.T) {
+	var calls int
+	r := New(newENS(&calls), Registry, nil)
+	ctx := context.Background()
+	if got, want := r.Label(ctx, alice), "0x1111…1111 (alice.eth)"; got != want {
+		t.Errorf("Label(alice) = %q, want %q", got, want)
+	}
+	before := calls
+	r.Label(ctx, alice)
+	if calls != before {
+		t.Errorf("second Label(alice) made %d calls, want cached", calls-before)
+	}
+	if got := r.Label(ctx, mallory); got != mallory.Hex() {
+		t.Errorf("Label(mallory) = %q, want the plain address (forward check fails)", got)
+	}
+	if got := r.Label(ctx, nobody); got != nobody.Hex() {
+		t.Errorf("Label(nobody) = %q, want the plain address", got)
+	}
+	var none *Resolver
+	if got := none.Label(ctx, alice); got != alice.Hex() {
+		t.Errorf("nil Resolver Label = %q", got)
+	}
+}
+
+func TestNamesMulticall(t *testing.T) {
+	var calls int
+	client, batches := multicalltest.Wrap(newENS(&calls))
+	r := New(client, Registry, multicall.New(client, common.Address{}))
+	names, err := r.Names(context.Background(), alice, mallory, nobody, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names[0] != "alice.eth" || names[1] != "" || names[2] != "" || names[3] != "alice.eth" {
+		t.Errorf("Names = %q", names)
+	}
+	// 四个阶段各一次聚合调用，重复的地址只查询一次
+	if len(*batches) != 4 || (*batches)[0] != 3 {
+		t.Errorf("aggregate3 batches = %v, want 4 starting with 3 calls", *batches)
+	}
+}
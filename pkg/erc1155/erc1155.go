@@ -0,0 +1,208 @@
+// Package erc1155 批量查询 ERC-1155 余额，并构造 safeBatchTransferFrom 交易。
+// 转账清单可以从 JSON 或 CSV 文件读取。
+package erc1155
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+// ErrInsufficientBalance 表示发送方某个 ID 的余额不足
+var ErrInsufficientBalance = errors.New("insufficient ERC-1155 balance")
+
+// ABI 包含批量查询和转账所需的 ERC-1155 方法，以及 TransferBatch 事件
+var ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"balanceOfBatch","stateMutability":"view","inputs":[{"name":"accounts","type":"address[]"},{"name":"ids","type":"uint256[]"}],"outputs":[{"name":"","type":"uint256[]"}]},
+{"type":"function","name":"uri","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"safeBatchTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"ids","type":"uint256[]"},{"name":"amounts","type":"uint256[]"},{"name":"data","type":"bytes"}],"outputs":[]},
+{"type":"event","name":"TransferBatch","anonymous":false,"inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"ids","type":"uint256[]","indexed":false},{"name":"values","type":"uint256[]","indexed":false}]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// Item 是批量转账中的一项
+type Item struct {
+	ID     *big.Int
+	Amount *big.Int
+}
+
+// Collection 是一个 ERC-1155 合约
+type Collection struct {
+	Address common.Address
+	client  chain.Client
+}
+
+// New 返回 address 上的 ERC-1155 合约
+func New(client chain.Client, address common.Address) *Collection {
+	return &Collection{Address: address, client: client}
+}
+
+// BalanceOfBatch 返回 accounts[i] 持有的 ids[i] 的数量，两个切片长度必须相同
+func (c *Collection) BalanceOfBatch(ctx context.Context, accounts []common.Address, ids []*big.Int) ([]*big.Int, error) {
+	if len(accounts) != len(ids) {
+		return nil, fmt.Errorf("%d accounts but %d ids", len(accounts), len(ids))
+	}
+	data, err := ABI.Pack("balanceOfBatch", accounts, ids)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.client.CallContract(ctx, ethereum.CallMsg{To: &c.Address, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call balanceOfBatch: %w", err)
+	}
+	values, err := ABI.Unpack("balanceOfBatch", result)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an ERC-1155 contract: %w", c.Address.Hex(), err)
+	}
+	balances := values[0].([]*big.Int)
+	if len(balances) != len(ids) {
+		return nil, fmt.Errorf("balanceOfBatch returned %d balances for %d ids", len(balances), len(ids))
+	}
+	return balances, nil
+}
+
+// BatchTransferData 返回 safeBatchTransferFrom(from, to, ids, amounts, "") 的调用数据
+func BatchTransferData(from, to common.Address, items []Item) []byte {
+	ids := make([]*big.Int, len(items))
+	amounts := make([]*big.Int, len(items))
+	for i, item := range items {
+		ids[i], amounts[i] = item.ID, item.Amount
+	}
+	data, err := ABI.Pack("safeBatchTransferFrom", from, to, ids, amounts, []byte{})
+	if err != nil {
+		panic(err) // 参数类型固定，不会失败
+	}
+	return data
+}
+
+// Prepare 用 balanceOfBatch 检查 from 持有足够的每个 ID（同一 ID 出现多次时按总数计算），
+// 然后构造 safeBatchTransferFrom 交易，gas 和费用与 ethtx.PrepareCall 相同。
+func (c *Collection) Prepare(ctx context.Context, from, to common.Address, items []Item) (*ethtx.Transfer, error) {
+	if len(items) == 0 {
+		return nil, errors.New("no items to transfer")
+	}
+	need := make(map[string]*big.Int)
+	var ids []*big.Int
+	for _, item := range items {
+		key := item.ID.String()
+		if need[key] == nil {
+			need[key] = new(big.Int)
+			ids = append(ids, item.ID)
+		}
+		need[key].Add(need[key], item.Amount)
+	}
+	accounts := make([]common.Address, len(ids))
+	for i := range accounts {
+		accounts[i] = from
+	}
+	balances, err := c.BalanceOfBatch(ctx, accounts, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range ids {
+		if balances[i].Cmp(need[id.String()]) < 0 {
+			return nil, fmt.Errorf("%w: id %s needs %s but %s holds %s", ErrInsufficientBalance, id, need[id.String()], from.Hex(), balances[i])
+		}
+	}
+	return ethtx.PrepareCall(ctx, c.client, from, c.Address, new(big.Int), BatchTransferData(from, to, items))
+}
+
+// LoadItems 读取转账清单，按扩展名选择格式：.json 使用 ParseJSON，其他使用 ParseCSV
+func LoadItems(path string) ([]Item, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return ParseJSON(f)
+	}
+	return ParseCSV(f)
+}
+
+// ParseJSON 解析 [{"id": 1, "amount": "10"}, ...]，数字可以写成 JSON 数字或十进制/0x 字符串
+func ParseJSON(r io.Reader) ([]Item, error) {
+	var raw []struct {
+		ID     number `json:"id"`
+		Amount number `json:"amount"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("parse items: %w", err)
+	}
+	items := make([]Item, len(raw))
+	for i, r := range raw {
+		item, err := parseItem(string(r.ID), string(r.Amount))
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// number 保留 JSON 数字或字符串的原文，交给 big.Int 解析以免超过 float64 精度
+type number string
+
+func (n *number) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*n = number(s)
+		return nil
+	}
+	*n = number(b)
+	return nil
+}
+
+// ParseCSV 解析每行 "id,amount" 的 CSV，第一行不是数字时视为表头跳过
+func ParseCSV(r io.Reader) ([]Item, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse items: %w", err)
+	}
+	var items []Item
+	for i, rec := range records {
+		if _, ok := new(big.Int).SetString(strings.TrimSpace(rec[0]), 0); i == 0 && !ok {
+			continue // 表头
+		}
+		item, err := parseItem(rec[0], rec[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func parseItem(id, amount string) (Item, error) {
+	var item Item
+	var ok bool
+	if item.ID, ok = new(big.Int).SetString(strings.TrimSpace(id), 0); !ok || item.ID.Sign() < 0 {
+		return Item{}, fmt.Errorf("invalid id %q", id)
+	}
+	if item.Amount, ok = new(big.Int).SetString(strings.TrimSpace(amount), 0); !ok || item.Amount.Sign() <= 0 {
+		return Item{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	return item, nil
+}
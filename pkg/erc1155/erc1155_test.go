@@ -0,0 +1,101 @@
+package erc1155
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+var holder = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+// newCollection 返回模拟的 ERC-1155 合约：holder 持有每个 ID 各 id×10 个
+func newCollection() *Collection {
+	client := &chain.ClientMock{
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			method, err := ABI.MethodById(call.Data[:4])
+			if err != nil || method.Name != "balanceOfBatch" {
+				return nil, errors.New("execution reverted")
+			}
+			args, err := method.Inputs.Unpack(call.Data[4:])
+			if err != nil {
+				return nil, err
+			}
+			accounts, ids := args[0].([]common.Address), args[1].([]*big.Int)
+			balances := make([]*big.Int, len(ids))
+			for i, id := range ids {
+				balances[i] = new(big.Int)
+				if accounts[i] == holder {
+					balances[i].Mul(id, big.NewInt(10))
+				}
+			}
+			return method.Outputs.Pack(balances)
+		},
+	}
+	return New(client, common.Address{1})
+}
+
+func TestBalanceOfBatch(t *testing.T) {
+	c := newCollection()
+	got, err := c.BalanceOfBatch(context.Background(), []common.Address{holder, {2}}, []*big.Int{big.NewInt(3), big.NewInt(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Int64() != 30 || got[1].Sign() != 0 {
+		t.Errorf("BalanceOfBatch = %v, want [30 0]", got)
+	}
+	if _, err := c.BalanceOfBatch(context.Background(), []common.Address{holder}, nil); err == nil {
+		t.Error("BalanceOfBatch accepted mismatched lengths")
+	}
+}
+
+func TestPrepareInsufficientBalance(t *testing.T) {
+	// ID 1 出现两次，共 12 个，超过持有的 10 个
+	items := []Item{{big.NewInt(1), big.NewInt(6)}, {big.NewInt(2), big.NewInt(1)}, {big.NewInt(1), big.NewInt(6)}}
+	_, err := newCollection().Prepare(context.Background(), holder, common.Address{2}, items)
+	if !errors.Is(err, ErrInsufficientBalance) || !strings.Contains(err.Error(), "id 1 needs 12") {
+		t.Fatalf("Prepare = %v, want ErrInsufficientBalance for id 1", err)
+	}
+}
+
+func TestBatchTransferData(t *testing.T) {
+	items := []Item{{big.NewInt(1), big.NewInt(5)}, {big.NewInt(7), big.NewInt(2)}}
+	data := BatchTransferData(holder, common.Address{2}, items)
+	args, err := ABI.Methods["safeBatchTransferFrom"].Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, amounts := args[2].([]*big.Int), args[3].([]*big.Int)
+	if len(ids) != 2 || ids[1].Int64() != 7 || amounts[1].Int64() != 2 {
+		t.Errorf("ids %v amounts %v", ids, amounts)
+	}
+}
+
+func TestParseItems(t *testing.T) {
+	fromJSON, err := ParseJSON(strings.NewReader(`[{"id": 1, "amount": "10"}, {"id": "0x2", "amount": 3}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCSV, err := ParseCSV(strings.NewReader("id,amount\n1, 10\n0x2,3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, items := range [][]Item{fromJSON, fromCSV} {
+		if len(items) != 2 || items[0].Amount.Int64() != 10 || items[1].ID.Int64() != 2 || items[1].Amount.Int64() != 3 {
+			t.Errorf("items = %v", items)
+		}
+	}
+	for _, in := range []string{"1,0\n", "1,-5\n", "1,10\nx,1\n", "1\n"} {
+		if _, err := ParseCSV(strings.NewReader(in)); err == nil {
+			t.Errorf("ParseCSV(%q) succeeded", in)
+		}
+	}
+	if _, err := ParseJSON(strings.NewReader(`[{"id": 1.5, "amount": 1}]`)); err == nil {
+		t.Error("ParseJSON accepted a fractional id")
+	}
+}
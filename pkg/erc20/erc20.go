@@ -0,0 +1,284 @@
+// Package erc20 读取 ERC-20 代币的元数据和余额，并构造 transfer 交易（task03 的转账流程）。
+package erc20
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+	"github.com/local/go-eth-demo/pkg/multicall"
+	"github.com/local/go-eth-demo/pkg/units"
+)
+
+// ErrInsufficientTokens 表示代币余额不足以支付转账数量
+var ErrInsufficientTokens = errors.New("insufficient token balance")
+
+// ABI 包含读取代币信息、转账和授权所需的方法，以及 Transfer 事件
+var ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// Token 是一个 ERC-20 代币合约及其元数据
+type Token struct {
+	Address  common.Address
+	Symbol   string // 合约没有 symbol 时为空
+	Decimals int
+}
+
+// Load 读取转账所需的 decimals 和 symbol。decimals 是必需的，symbol 读取失败时为空
+func Load(ctx context.Context, client chain.Client, address common.Address) (*Token, error) {
+	t := &Token{Address: address}
+	var decimals uint8
+	if err := t.call(ctx, client, &decimals, "decimals"); err != nil {
+		return nil, fmt.Errorf("token %s: %w", address.Hex(), err)
+	}
+	t.Decimals = int(decimals)
+	t.Symbol, _ = t.readString(ctx, client, "symbol")
+	return t, nil
+}
+
+// Info 是代币的完整信息。非标准代币缺少的可选方法对应字段为零值
+type Info struct {
+	Token
+	Name        string
+	TotalSupply *big.Int // 合约没有 totalSupply() 时为 nil
+	NoDecimals  bool     // 合约没有 decimals()，Decimals 按 0 处理
+}
+
+// Inspect 读取代币的 name、symbol、decimals 和 totalSupply。所有方法都是可选的，
+// 只有地址上没有合约代码或者这些方法全部调用失败时才返回错误。
+func Inspect(ctx context.Context, client chain.Client, address common.Address) (*Info, error) {
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("no contract at %s", address.Hex())
+	}
+	info := &Info{Token: Token{Address: address}}
+	var decimals uint8
+	decErr := info.call(ctx, client, &decimals, "decimals")
+	info.Decimals, info.NoDecimals = int(decimals), decErr != nil
+	info.Symbol, _ = info.readString(ctx, client, "symbol")
+	info.Name, _ = info.readString(ctx, client, "name")
+	if err := info.call(ctx, client, &info.TotalSupply, "totalSupply"); err != nil {
+		info.TotalSupply = nil
+	}
+	if info.NoDecimals && info.Symbol == "" && info.Name == "" && info.TotalSupply == nil {
+		return nil, fmt.Errorf("%s does not look like an ERC-20 token: %w", address.Hex(), decErr)
+	}
+	return info, nil
+}
+
+// InspectBatch 用 Multicall3 在一次 eth_call 中读取多个代币的 name、symbol、decimals 和 totalSupply，
+// 结果与 addresses 一一对应。与 Inspect 一样所有方法都是可选的，某个代币的方法全部失败
+// （包括地址上没有合约）时对应的结果为 nil。
+func InspectBatch(ctx context.Context, mc *multicall.Caller, addresses []common.Address) ([]*Info, error) {
+	methods := []string{"decimals", "symbol", "name", "totalSupply"}
+	var calls []multicall.Call
+	for _, addr := range addresses {
+		for _, m := range methods {
+			call, err := multicall.Pack(addr, &ABI, m)
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, call)
+		}
+	}
+	results, err := mc.Aggregate(ctx, calls, nil)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]*Info, len(addresses))
+	for i, addr := range addresses {
+		r := results[i*len(methods):]
+		info := &Info{Token: Token{Address: addr}}
+		if values, err := r[0].Unpack(&ABI, "decimals"); err == nil {
+			info.Decimals = int(values[0].(uint8))
+		} else {
+			info.NoDecimals = true
+		}
+		info.Symbol = unpackString(r[1], "symbol")
+		info.Name = unpackString(r[2], "name")
+		if values, err := r[3].Unpack(&ABI, "totalSupply"); err == nil {
+			info.TotalSupply = values[0].(*big.Int)
+		}
+		if !info.NoDecimals || info.Symbol != "" || info.Name != "" || info.TotalSupply != nil {
+			infos[i] = info
+		}
+	}
+	return infos, nil
+}
+
+// unpackString 解码返回字符串的方法结果，同样兼容返回 bytes32 的老代币，失败时返回空字符串
+func unpackString(r multicall.Result, method string) string {
+	if values, err := r.Unpack(&ABI, method); err == nil {
+		return values[0].(string)
+	}
+	if r.Success && len(r.ReturnData) == 32 {
+		return string(bytes.TrimRight(r.ReturnData, "\x00"))
+	}
+	return ""
+}
+
+// readString 读取返回字符串的方法。部分老代币（如 MKR）的 name/symbol 返回 bytes32，两种格式都能识别
+func (t *Token) readString(ctx context.Context, client chain.Client, method string) (string, error) {
+	var s string
+	err := t.call(ctx, client, &s, method)
+	if err == nil {
+		return s, nil
+	}
+	data, _ := ABI.Pack(method)
+	result, callErr := client.CallContract(ctx, ethereum.CallMsg{To: &t.Address, Data: data}, nil)
+	if callErr != nil || len(result) != 32 {
+		return "", err
+	}
+	return string(bytes.TrimRight(result, "\x00")), nil
+}
+
+// call 调用代币合约的只读方法并把唯一的返回值写入 out
+func (t *Token) call(ctx context.Context, client chain.Client, out interface{}, method string, args ...interface{}) error {
+	data, err := ABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &t.Address, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", method, err)
+	}
+	values, err := ABI.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("not an ERC-20 token (%s): %w", method, err)
+	}
+	abi.ConvertType(values[0], out)
+	return nil
+}
+
+// BalanceOf 返回 owner 持有的代币数量（最小单位）
+func (t *Token) BalanceOf(ctx context.Context, client chain.Client, owner common.Address) (*big.Int, error) {
+	var balance *big.Int
+	if err := t.call(ctx, client, &balance, "balanceOf", owner); err != nil {
+		return nil, fmt.Errorf("token %s: %w", t.Address.Hex(), err)
+	}
+	return balance, nil
+}
+
+// ParseAmount 把人类可读的数量转换为最小单位，例如 6 位小数的 USDC 上 "12.5 USDC" 或 "12.5"
+// 返回 12500000。带单位时必须与代币的 symbol 一致（不区分大小写）。
+func (t *Token) ParseAmount(s string) (*big.Int, error) {
+	num, symbol, _ := strings.Cut(strings.TrimSpace(s), " ")
+	if symbol = strings.TrimSpace(symbol); symbol != "" && !strings.EqualFold(symbol, t.Symbol) {
+		return nil, fmt.Errorf("amount %q is in %s, but the token is %s", s, symbol, t.display())
+	}
+	amount, err := units.ParseUnits(num, t.Decimals)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() < 0 {
+		return nil, fmt.Errorf("amount %q is negative", s)
+	}
+	return amount, nil
+}
+
+// Format 把最小单位的数量格式化为 "12.5 USDC"
+func (t *Token) Format(amount *big.Int) string {
+	return units.FormatUnits(amount, t.Decimals) + " " + t.display()
+}
+
+func (t *Token) display() string {
+	if t.Symbol == "" {
+		return "tokens"
+	}
+	return t.Symbol
+}
+
+// TransferData 返回 transfer(to, amount) 的调用数据
+func TransferData(to common.Address, amount *big.Int) []byte {
+	data, err := ABI.Pack("transfer", to, amount)
+	if err != nil {
+		panic(err) // 参数类型固定，不会失败
+	}
+	return data
+}
+
+// Allowance 返回 owner 授权 spender 使用的代币数量（最小单位）
+func (t *Token) Allowance(ctx context.Context, client chain.Client, owner, spender common.Address) (*big.Int, error) {
+	var allowance *big.Int
+	if err := t.call(ctx, client, &allowance, "allowance", owner, spender); err != nil {
+		return nil, fmt.Errorf("token %s: %w", t.Address.Hex(), err)
+	}
+	return allowance, nil
+}
+
+// ApproveData 返回 approve(spender, amount) 的调用数据
+func ApproveData(spender common.Address, amount *big.Int) []byte {
+	data, err := ABI.Pack("approve", spender, amount)
+	if err != nil {
+		panic(err) // 参数类型固定，不会失败
+	}
+	return data
+}
+
+// Transfer 是一个解码后的 Transfer 事件
+type Transfer struct {
+	From, To common.Address
+	Value    *big.Int
+}
+
+// Transfers 返回 logs 中由代币合约 t 发出的 Transfer 事件，例如交易收据中的日志
+func (t *Token) Transfers(logs []*types.Log) []Transfer {
+	event := ABI.Events["Transfer"]
+	var out []Transfer
+	for _, l := range logs {
+		if l.Address != t.Address || len(l.Topics) != 3 || l.Topics[0] != event.ID {
+			continue
+		}
+		values, err := event.Inputs.NonIndexed().Unpack(l.Data)
+		if err != nil {
+			continue
+		}
+		out = append(out, Transfer{
+			From:  common.BytesToAddress(l.Topics[1].Bytes()),
+			To:    common.BytesToAddress(l.Topics[2].Bytes()),
+			Value: values[0].(*big.Int),
+		})
+	}
+	return out
+}
+
+// Prepare 检查 from 的代币余额，然后构造调用代币合约 transfer(to, amount) 的交易，
+// gas 按该调用估算，费用与 ethtx.Prepare 相同。代币余额不足时返回 ErrInsufficientTokens；
+// ETH 不足以支付 gas 时返回 ethtx.ErrInsufficientFunds，此时 Transfer 仍然返回以便显示费用。
+func (t *Token) Prepare(ctx context.Context, client chain.Client, from, to common.Address, amount *big.Int) (*ethtx.Transfer, error) {
+	balance, err := t.BalanceOf(ctx, client, from)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("%w: need %s but only have %s", ErrInsufficientTokens, t.Format(amount), t.Format(balance))
+	}
+	return ethtx.PrepareCall(ctx, client, from, t.Address, new(big.Int), TransferData(to, amount))
+}
@@ -0,0 +1,163 @@
+package erc20
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/internal/multicalltest"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/multicall"
+)
+
+var holder = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+// newToken 返回模拟的代币合约：6 位小数，holder 持有 25 个代币，symbol 按 bytes32 返回时 legacy 为 true
+func newToken(symbol string, legacy bool) *chain.ClientMock {
+	return &chain.ClientMock{
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			method, err := ABI.MethodById(call.Data[:4])
+			if err != nil {
+				return nil, err
+			}
+			switch method.Name {
+			case "balanceOf":
+				return method.Outputs.Pack(big.NewInt(25_000_000))
+			case "decimals":
+				return method.Outputs.Pack(uint8(6))
+			case "symbol":
+				if legacy {
+					return common.RightPadBytes([]byte(symbol), 32), nil
+				}
+				return method.Outputs.Pack(symbol)
+			}
+			return nil, errors.New("execution reverted")
+		},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		token, err := Load(context.Background(), newToken("USDC", legacy), common.Address{1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.Symbol != "USDC" || token.Decimals != 6 {
+			t.Errorf("legacy=%v: Load = %+v, want USDC with 6 decimals", legacy, token)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	token := &Token{Symbol: "USDC", Decimals: 6}
+	for _, tt := range []struct {
+		in   string
+		want int64
+	}{
+		{"12.5 USDC", 12_500_000},
+		{"12.5 usdc", 12_500_000},
+		{" 0.000001 ", 1},
+		{"3", 3_000_000},
+	} {
+		got, err := token.ParseAmount(tt.in)
+		if err != nil || got.Int64() != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"1 DAI", "0.0000001", "-1", "abc USDC", ""} {
+		if got, err := token.ParseAmount(in); err == nil {
+			t.Errorf("ParseAmount(%q) = %v, want an error", in, got)
+		}
+	}
+	if got := token.Format(big.NewInt(12_500_000)); got != "12.5 USDC" {
+		t.Errorf("Format = %q", got)
+	}
+}
+
+func TestPrepareInsufficientTokens(t *testing.T) {
+	token := &Token{Address: common.Address{1}, Symbol: "USDC", Decimals: 6}
+	_, err := token.Prepare(context.Background(), newToken("USDC", false), holder, common.Address{2}, big.NewInt(30_000_000))
+	if !errors.Is(err, ErrInsufficientTokens) {
+		t.Fatalf("Prepare = %v, want ErrInsufficientTokens", err)
+	}
+}
+
+func TestTransferData(t *testing.T) {
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	data := TransferData(to, big.NewInt(42))
+	args, err := ABI.Methods["transfer"].Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0].(common.Address) != to || args[1].(*big.Int).Int64() != 42 {
+		t.Errorf("transfer arguments = %v", args)
+	}
+}
+
+func TestTransfers(t *testing.T) {
+	token := &Token{Address: common.Address{0xaa}}
+	event := ABI.Events["Transfer"]
+	data, _ := event.Inputs.NonIndexed().Pack(big.NewInt(7))
+	transfer := &types.Log{
+		Address: token.Address,
+		Topics:  []common.Hash{event.ID, common.BytesToHash(holder.Bytes()), common.BytesToHash(common.Address{2}.Bytes())},
+		Data:    data,
+	}
+	other := *transfer
+	other.Address = common.Address{0xbb}
+	got := token.Transfers([]*types.Log{transfer, &other})
+	if len(got) != 1 || got[0].From != holder || got[0].To != (common.Address{2}) || got[0].Value.Int64() != 7 {
+		t.Errorf("Transfers = %+v", got)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	client := newToken("MKR", true)
+	client.CodeAtFunc = func(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+		if account == (common.Address{}) {
+			return nil, nil
+		}
+		return []byte{0x60}, nil
+	}
+	// newToken 没有实现 name 和 totalSupply，相当于缺少这两个可选方法的非标准代币
+	info, err := Inspect(context.Background(), client, common.Address{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Symbol != "MKR" || info.Decimals != 6 || info.NoDecimals || info.Name != "" || info.TotalSupply != nil {
+		t.Errorf("Inspect = %+v", info)
+	}
+	if _, err := Inspect(context.Background(), client, common.Address{}); err == nil {
+		t.Error("Inspect succeeded for an address without code")
+	}
+}
+
+func TestInspectBatch(t *testing.T) {
+	// 第二个地址上的调用全部失败，相当于没有合约
+	token := newToken("MKR", true)
+	client, batches := multicalltest.Wrap(&chain.ClientMock{
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			if *call.To == (common.Address{2}) {
+				return nil, errors.New("execution reverted")
+			}
+			return token.CallContract(ctx, call, blockNumber)
+		},
+	})
+	infos, err := InspectBatch(context.Background(), multicall.New(client, common.Address{}), []common.Address{{1}, {2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*batches) != 1 || (*batches)[0] != 8 {
+		t.Errorf("aggregate3 batches = %v, want one batch of 8 calls", *batches)
+	}
+	if info := infos[0]; info == nil || info.Symbol != "MKR" || info.Decimals != 6 || info.Name != "" || info.TotalSupply != nil {
+		t.Errorf("InspectBatch[0] = %+v", info)
+	}
+	if infos[1] != nil {
+		t.Errorf("InspectBatch[1] = %+v, want nil", infos[1])
+	}
+}
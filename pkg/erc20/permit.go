@@ -0,0 +1,264 @@
+package erc20
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// ErrNoPermit 表示代币不支持 EIP-2612 permit，或者无法确定它的 EIP-712 域
+var ErrNoPermit = errors.New("token does not support EIP-2612 permit")
+
+// PermitABI 是 EIP-2612 的 permit、nonces、DOMAIN_SEPARATOR，以及多数实现提供的 version
+var PermitABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"nonces","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"DOMAIN_SEPARATOR","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]},
+{"type":"function","name":"version","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"permit","stateMutability":"nonpayable","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"},{"name":"value","type":"uint256"},{"name":"deadline","type":"uint256"},{"name":"v","type":"uint8"},{"name":"r","type":"bytes32"},{"name":"s","type":"bytes32"}],"outputs":[]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// Permit 是一个 EIP-2612 授权：Owner 签名后，任何人都可以提交 permit 交易，让 Spender 获得 Value 的额度，
+// Owner 不需要为授权支付 gas。Name、Version、ChainID 和 Token 组成签名的 EIP-712 域
+type Permit struct {
+	Token    common.Address
+	ChainID  *big.Int
+	Name     string
+	Version  string
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int // Unix 秒
+
+	// Signature 是 65 字节的 EIP-712 签名（r || s || v，v 为 27 或 28）
+	Signature []byte
+}
+
+// NewPermit 从代币读取 name、version（没有该方法时为 "1"）和 owner 当前的 nonce，构造未签名的 Permit，
+// 并用代币的 DOMAIN_SEPARATOR() 核对域。域对不上（如 DAI 的非标准 permit）时返回 ErrNoPermit，
+// 因为按错误的域签名的 permit 会被合约拒绝
+func NewPermit(ctx context.Context, client chain.Client, token, owner, spender common.Address, value, deadline *big.Int) (*Permit, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	p := &Permit{Token: token, ChainID: chainID, Owner: owner, Spender: spender, Value: value, Deadline: deadline, Version: "1"}
+	var separator [32]byte
+	if err := permitCall(ctx, client, token, &separator, "DOMAIN_SEPARATOR"); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoPermit, err)
+	}
+	if err := permitCall(ctx, client, token, &p.Nonce, "nonces", owner); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoPermit, err)
+	}
+	t := &Token{Address: token}
+	if p.Name, err = t.readString(ctx, client, "name"); err != nil {
+		return nil, fmt.Errorf("token %s: %w", token.Hex(), err)
+	}
+	var version string
+	if permitCall(ctx, client, token, &version, "version") == nil {
+		p.Version = version
+	}
+	got, err := p.DomainSeparator()
+	if err != nil {
+		return nil, err
+	}
+	if got != separator {
+		return nil, fmt.Errorf("%w: DOMAIN_SEPARATOR %s does not match name %q, version %q, chain %s",
+			ErrNoPermit, common.Hash(separator).Hex(), p.Name, p.Version, chainID)
+	}
+	return p, nil
+}
+
+// permitCall 调用 PermitABI 中的只读方法并把唯一的返回值写入 out
+func permitCall(ctx context.Context, client chain.Client, token common.Address, out interface{}, method string, args ...interface{}) error {
+	data, err := PermitABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", method, err)
+	}
+	values, err := PermitABI.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("decode %s: %w", method, err)
+	}
+	abi.ConvertType(values[0], out)
+	return nil
+}
+
+// TypedData 返回 permit 的 EIP-712 结构化数据
+func (p *Permit) TypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Permit": {
+				{Name: "owner", Type: "address"},
+				{Name: "spender", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Permit",
+		Domain: apitypes.TypedDataDomain{
+			Name:              p.Name,
+			Version:           p.Version,
+			ChainId:           (*math.HexOrDecimal256)(p.ChainID),
+			VerifyingContract: p.Token.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"owner":    p.Owner.Hex(),
+			"spender":  p.Spender.Hex(),
+			"value":    p.Value.String(),
+			"nonce":    p.Nonce.String(),
+			"deadline": p.Deadline.String(),
+		},
+	}
+}
+
+// DomainSeparator 按 Name、Version、ChainID 和 Token 计算 EIP-712 域分隔符
+func (p *Permit) DomainSeparator() (common.Hash, error) {
+	typed := p.TypedData()
+	hash, err := typed.HashStruct("EIP712Domain", typed.Domain.Map())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("hash EIP-712 domain: %w", err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// SigningHash 返回需要签名的 EIP-712 摘要
+func (p *Permit) SigningHash() (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(p.TypedData())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("hash EIP-712 permit: %w", err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// Sign 用 Owner 的私钥签名，key 与 Owner 不符时返回错误
+func (p *Permit) Sign(key *ecdsa.PrivateKey) error {
+	if signer := crypto.PubkeyToAddress(key.PublicKey); signer != p.Owner {
+		return fmt.Errorf("permit owner is %s but the key is for %s", p.Owner.Hex(), signer.Hex())
+	}
+	hash, err := p.SigningHash()
+	if err != nil {
+		return err
+	}
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		return fmt.Errorf("sign permit: %w", err)
+	}
+	sig[64] += 27
+	p.Signature = sig
+	return nil
+}
+
+// Verify 检查签名来自 Owner
+func (p *Permit) Verify() error {
+	if len(p.Signature) != crypto.SignatureLength {
+		return errors.New("permit is not signed")
+	}
+	hash, err := p.SigningHash()
+	if err != nil {
+		return err
+	}
+	sig := common.CopyBytes(p.Signature)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return fmt.Errorf("invalid permit signature: %w", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != p.Owner {
+		return fmt.Errorf("permit is signed by %s, not the owner %s", signer.Hex(), p.Owner.Hex())
+	}
+	return nil
+}
+
+// CallData 返回在代币合约上提交 permit(owner, spender, value, deadline, v, r, s) 的调用数据
+func (p *Permit) CallData() ([]byte, error) {
+	if len(p.Signature) != crypto.SignatureLength {
+		return nil, errors.New("permit is not signed")
+	}
+	var r, s [32]byte
+	copy(r[:], p.Signature[:32])
+	copy(s[:], p.Signature[32:64])
+	return PermitABI.Pack("permit", p.Owner, p.Spender, p.Value, p.Deadline, p.Signature[64], r, s)
+}
+
+// permitJSON 是 Permit 的 JSON 形式，数量用十进制字符串，并单独列出 v、r、s 供其他工具使用
+type permitJSON struct {
+	Token     common.Address `json:"token"`
+	ChainID   string         `json:"chainId"`
+	Name      string         `json:"name"`
+	Version   string         `json:"version"`
+	Owner     common.Address `json:"owner"`
+	Spender   common.Address `json:"spender"`
+	Value     string         `json:"value"`
+	Nonce     string         `json:"nonce"`
+	Deadline  string         `json:"deadline"`
+	Signature hexutil.Bytes  `json:"signature,omitempty"`
+	V         uint8          `json:"v,omitempty"`
+	R         *common.Hash   `json:"r,omitempty"`
+	S         *common.Hash   `json:"s,omitempty"`
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (p *Permit) MarshalJSON() ([]byte, error) {
+	out := permitJSON{
+		Token: p.Token, ChainID: p.ChainID.String(), Name: p.Name, Version: p.Version,
+		Owner: p.Owner, Spender: p.Spender,
+		Value: p.Value.String(), Nonce: p.Nonce.String(), Deadline: p.Deadline.String(),
+		Signature: p.Signature,
+	}
+	if len(p.Signature) == crypto.SignatureLength {
+		r, s := common.BytesToHash(p.Signature[:32]), common.BytesToHash(p.Signature[32:64])
+		out.V, out.R, out.S = p.Signature[64], &r, &s
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler，v、r、s 被忽略，只使用 signature
+func (p *Permit) UnmarshalJSON(data []byte) error {
+	var in permitJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	p.Token, p.Name, p.Version, p.Owner, p.Spender, p.Signature = in.Token, in.Name, in.Version, in.Owner, in.Spender, in.Signature
+	for _, f := range []struct {
+		name string
+		s    string
+		out  **big.Int
+	}{{"chainId", in.ChainID, &p.ChainID}, {"value", in.Value, &p.Value}, {"nonce", in.Nonce, &p.Nonce}, {"deadline", in.Deadline, &p.Deadline}} {
+		v, ok := new(big.Int).SetString(f.s, 10)
+		if !ok {
+			return fmt.Errorf("invalid %s %q", f.name, f.s)
+		}
+		*f.out = v
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package erc20
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// newPermitToken 返回模拟的 EIP-2612 代币，DOMAIN_SEPARATOR 按 name "USD Coin"、version "2" 计算
+func newPermitToken(address common.Address, chainID int64) *chain.ClientMock {
+	separator := crypto.Keccak256Hash(
+		crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte("USD Coin")),
+		crypto.Keccak256([]byte("2")),
+		common.BigToHash(big.NewInt(chainID)).Bytes(),
+		common.LeftPadBytes(address.Bytes(), 32),
+	)
+	return &chain.ClientMock{
+		ChainIDFunc: func(ctx context.Context) (*big.Int, error) { return big.NewInt(chainID), nil },
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			if method, err := ABI.MethodById(call.Data[:4]); err == nil && method.Name == "name" {
+				return method.Outputs.Pack("USD Coin")
+			}
+			method, err := PermitABI.MethodById(call.Data[:4])
+			if err != nil {
+				return nil, errors.New("execution reverted")
+			}
+			switch method.Name {
+			case "DOMAIN_SEPARATOR":
+				return method.Outputs.Pack([32]byte(separator))
+			case "nonces":
+				return method.Outputs.Pack(big.NewInt(3))
+			case "version":
+				return method.Outputs.Pack("2")
+			}
+			return nil, errors.New("execution reverted")
+		},
+	}
+}
+
+func TestPermit(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	token, spender := common.Address{0xaa}, common.Address{0xbb}
+	client := newPermitToken(token, 11155111)
+
+	p, err := NewPermit(context.Background(), client, token, owner, spender, big.NewInt(1_000_000), big.NewInt(1_900_000_000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "USD Coin" || p.Version != "2" || p.Nonce.Int64() != 3 {
+		t.Errorf("permit = %+v", p)
+	}
+	if _, err := p.CallData(); err == nil {
+		t.Error("CallData of an unsigned permit succeeded")
+	}
+	other, _ := crypto.GenerateKey()
+	if err := p.Sign(other); err == nil {
+		t.Error("Sign with a key other than the owner's succeeded")
+	}
+	if err := p.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	// JSON 往返后签名仍然有效，调用数据可以解码出相同的参数
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Permit
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Verify(); err != nil {
+		t.Errorf("decoded permit: %v", err)
+	}
+	calldata, err := decoded.CallData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := PermitABI.Methods["permit"].Inputs.Unpack(calldata[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0].(common.Address) != owner || args[3].(*big.Int).Int64() != 1_900_000_000 || args[4].(uint8) != p.Signature[64] {
+		t.Errorf("permit args = %v", args)
+	}
+
+	// 代币的域分隔符按另一个合约地址计算时对不上
+	if _, err := NewPermit(context.Background(), newPermitToken(common.Address{0xcc}, 11155111), token, owner, spender, big.NewInt(1), big.NewInt(1)); !errors.Is(err, ErrNoPermit) {
+		t.Errorf("NewPermit with a mismatched domain = %v, want ErrNoPermit", err)
+	}
+}
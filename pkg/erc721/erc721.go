@@ -0,0 +1,211 @@
+// Package erc721 查询 ERC-721 NFT 的所有者和元数据，并构造 safeTransferFrom 交易。
+package erc721
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+// DefaultGateway 是把 ipfs:// 地址转换为 HTTP 地址时使用的默认网关
+const DefaultGateway = "https://ipfs.io/ipfs/"
+
+// maxMetadataSize 限制元数据 JSON 的大小，防止恶意 tokenURI 返回超大响应
+const maxMetadataSize = 1 << 20
+
+// ErrNotOwner 表示发送方不是 NFT 的所有者
+var ErrNotOwner = errors.New("not the owner of the token")
+
+// ABI 包含查询和转账所需的 ERC-721 方法，以及 Transfer 事件
+var ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"ownerOf","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"tokenURI","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// Collection 是一个 ERC-721 合约
+type Collection struct {
+	Address common.Address
+	client  chain.Client
+}
+
+// New 返回 address 上的 NFT 合约
+func New(client chain.Client, address common.Address) *Collection {
+	return &Collection{Address: address, client: client}
+}
+
+// call 调用合约的只读方法并把唯一的返回值写入 out
+func (c *Collection) call(ctx context.Context, out interface{}, method string, args ...interface{}) error {
+	data, err := ABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	result, err := c.client.CallContract(ctx, ethereum.CallMsg{To: &c.Address, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", method, err)
+	}
+	values, err := ABI.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("%s is not an ERC-721 contract (%s): %w", c.Address.Hex(), method, err)
+	}
+	abi.ConvertType(values[0], out)
+	return nil
+}
+
+// Name 返回集合名称，合约没有实现可选的 name() 时返回空字符串
+func (c *Collection) Name(ctx context.Context) string {
+	var name string
+	c.call(ctx, &name, "name")
+	return name
+}
+
+// Symbol 返回集合符号，合约没有实现可选的 symbol() 时返回空字符串
+func (c *Collection) Symbol(ctx context.Context) string {
+	var symbol string
+	c.call(ctx, &symbol, "symbol")
+	return symbol
+}
+
+// OwnerOf 返回 id 的所有者，不存在的 token 会回滚并返回错误
+func (c *Collection) OwnerOf(ctx context.Context, id *big.Int) (common.Address, error) {
+	var owner common.Address
+	if err := c.call(ctx, &owner, "ownerOf", id); err != nil {
+		return common.Address{}, fmt.Errorf("token %s: %w", id, err)
+	}
+	return owner, nil
+}
+
+// BalanceOf 返回 owner 持有的 NFT 数量
+func (c *Collection) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+	var n *big.Int
+	if err := c.call(ctx, &n, "balanceOf", owner); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// TokenURI 返回 id 的元数据地址
+func (c *Collection) TokenURI(ctx context.Context, id *big.Int) (string, error) {
+	var uri string
+	if err := c.call(ctx, &uri, "tokenURI", id); err != nil {
+		return "", fmt.Errorf("token %s: %w", id, err)
+	}
+	return uri, nil
+}
+
+// TransferData 返回 safeTransferFrom(from, to, id) 的调用数据
+func TransferData(from, to common.Address, id *big.Int) []byte {
+	data, err := ABI.Pack("safeTransferFrom", from, to, id)
+	if err != nil {
+		panic(err) // 参数类型固定，不会失败
+	}
+	return data
+}
+
+// Prepare 确认 from 是 id 的所有者，然后构造 safeTransferFrom 交易，gas 和费用与 ethtx.PrepareCall 相同。
+// 接收方是没有实现 onERC721Received 的合约时 gas 估算会因回滚而失败。
+func (c *Collection) Prepare(ctx context.Context, from, to common.Address, id *big.Int) (*ethtx.Transfer, error) {
+	owner, err := c.OwnerOf(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if owner != from {
+		return nil, fmt.Errorf("%w: token %s is owned by %s", ErrNotOwner, id, owner.Hex())
+	}
+	return ethtx.PrepareCall(ctx, c.client, from, c.Address, new(big.Int), TransferData(from, to, id))
+}
+
+// Metadata 是 ERC-721 元数据 JSON 的常用字段，Raw 保留完整的原始内容
+type Metadata struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Image       string          `json:"image"`
+	Attributes  []Attribute     `json:"attributes"`
+	Raw         json.RawMessage `json:"-"`
+}
+
+// Attribute 是 OpenSea 格式的属性
+type Attribute struct {
+	TraitType string      `json:"trait_type"`
+	Value     interface{} `json:"value"`
+}
+
+// ResolveURI 把 ipfs:// 地址转换为经过 gateway 的 HTTP 地址，其他地址原样返回
+func ResolveURI(uri, gateway string) string {
+	if rest, ok := strings.CutPrefix(uri, "ipfs://"); ok {
+		rest = strings.TrimPrefix(rest, "ipfs/")
+		return strings.TrimRight(gateway, "/") + "/" + rest
+	}
+	return uri
+}
+
+// FetchMetadata 读取 tokenURI 指向的元数据 JSON，支持 http(s)、ipfs://（经 gateway）和
+// data:application/json 地址（链上元数据，可为 base64 编码）
+func FetchMetadata(ctx context.Context, client *http.Client, uri, gateway string) (*Metadata, error) {
+	var raw []byte
+	if rest, ok := strings.CutPrefix(uri, "data:"); ok {
+		mediaType, payload, ok := strings.Cut(rest, ",")
+		if !ok {
+			return nil, fmt.Errorf("malformed data URI")
+		}
+		var err error
+		if strings.HasSuffix(mediaType, ";base64") {
+			raw, err = base64.StdEncoding.DecodeString(payload)
+		} else {
+			var s string
+			s, err = url.PathUnescape(payload)
+			raw = []byte(s)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode data URI: %w", err)
+		}
+	} else {
+		u := ResolveURI(uri, gateway)
+		if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+			return nil, fmt.Errorf("unsupported token URI %q", uri)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetch metadata: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch metadata: %s returned %s", u, resp.Status)
+		}
+		if raw, err = io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize)); err != nil {
+			return nil, fmt.Errorf("fetch metadata: %w", err)
+		}
+	}
+	m := &Metadata{Raw: raw}
+	if err := json.Unmarshal(raw, m); err != nil {
+		return nil, fmt.Errorf("metadata is not valid JSON: %w", err)
+	}
+	return m, nil
+}
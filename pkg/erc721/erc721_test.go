@@ -0,0 +1,110 @@
+package erc721
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+var owner = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+// newCollection 返回模拟的 NFT 合约：只有 token 7 存在，属于 owner
+func newCollection(uri string) *Collection {
+	client := &chain.ClientMock{
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			method, err := ABI.MethodById(call.Data[:4])
+			if err != nil {
+				return nil, err
+			}
+			args, err := method.Inputs.Unpack(call.Data[4:])
+			if err != nil {
+				return nil, err
+			}
+			if len(args) == 1 && args[0].(*big.Int).Int64() != 7 {
+				return nil, errors.New("execution reverted: invalid token ID")
+			}
+			switch method.Name {
+			case "ownerOf":
+				return method.Outputs.Pack(owner)
+			case "tokenURI":
+				return method.Outputs.Pack(uri)
+			}
+			return nil, errors.New("execution reverted")
+		},
+	}
+	return New(client, common.Address{1})
+}
+
+func TestOwnerAndURI(t *testing.T) {
+	c := newCollection("ipfs://QmHash/7.json")
+	ctx := context.Background()
+	if got, err := c.OwnerOf(ctx, big.NewInt(7)); err != nil || got != owner {
+		t.Errorf("OwnerOf(7) = %s, %v", got.Hex(), err)
+	}
+	if _, err := c.OwnerOf(ctx, big.NewInt(8)); err == nil {
+		t.Error("OwnerOf(8) succeeded for a missing token")
+	}
+	if uri, err := c.TokenURI(ctx, big.NewInt(7)); err != nil || uri != "ipfs://QmHash/7.json" {
+		t.Errorf("TokenURI(7) = %q, %v", uri, err)
+	}
+	if c.Name(ctx) != "" {
+		t.Error("Name should be empty when name() is not implemented")
+	}
+	_, err := c.Prepare(ctx, common.Address{9}, common.Address{2}, big.NewInt(7))
+	if !errors.Is(err, ErrNotOwner) {
+		t.Errorf("Prepare from a non-owner = %v, want ErrNotOwner", err)
+	}
+}
+
+func TestResolveURI(t *testing.T) {
+	for in, want := range map[string]string{
+		"ipfs://QmHash/1.json":      "https://ipfs.io/ipfs/QmHash/1.json",
+		"ipfs://ipfs/QmHash/1.json": "https://ipfs.io/ipfs/QmHash/1.json",
+		"https://example.com/1":     "https://example.com/1",
+	} {
+		if got := ResolveURI(in, DefaultGateway); got != want {
+			t.Errorf("ResolveURI(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFetchMetadata(t *testing.T) {
+	const doc = `{"name":"Punk #7","image":"ipfs://QmImage","attributes":[{"trait_type":"Hat","value":"Cap"},{"trait_type":"Level","value":3}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/QmHash/7.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(doc))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, uri := range []string{
+		"ipfs://QmHash/7.json",
+		"data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(doc)),
+		"data:application/json," + doc,
+	} {
+		m, err := FetchMetadata(ctx, server.Client(), uri, server.URL+"/ipfs/")
+		if err != nil {
+			t.Fatalf("FetchMetadata(%.40q): %v", uri, err)
+		}
+		if m.Name != "Punk #7" || len(m.Attributes) != 2 || m.Attributes[0].TraitType != "Hat" || string(m.Raw) != doc {
+			t.Errorf("FetchMetadata(%.40q) = %+v", uri, m)
+		}
+	}
+	if _, err := FetchMetadata(ctx, server.Client(), "ipfs://QmMissing", server.URL+"/ipfs/"); err == nil {
+		t.Error("FetchMetadata succeeded for a 404")
+	}
+	if _, err := FetchMetadata(ctx, server.Client(), "ar://abc", DefaultGateway); err == nil {
+		t.Error("FetchMetadata accepted an unsupported scheme")
+	}
+}
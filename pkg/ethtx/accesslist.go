@@ -0,0 +1,52 @@
+package ethtx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// AccessListEstimate 是 eth_createAccessList 为一次调用生成的 EIP-2930 访问列表及其对 gas 的影响
+type AccessListEstimate struct {
+	AccessList types.AccessList
+	GasWithout uint64 // 不带访问列表时 eth_estimateGas 的结果
+	GasWith    uint64 // 带访问列表时的结果，已包含列表本身的 intrinsic gas
+}
+
+// Saving 返回访问列表节省的 gas，为负数时说明列表的成本超过了它节省的冷访问费用
+func (e *AccessListEstimate) Saving() int64 {
+	return int64(e.GasWithout) - int64(e.GasWith)
+}
+
+// EstimateAccessList 用 rc 调用 eth_createAccessList 生成 msg 访问的地址和存储槽，
+// 再用 client 分别估算不带和带访问列表的 gas。节点不会把发送方、接收方和预编译合约放进列表，
+// 因此只访问接收方自身存储的调用得到空列表。调用会失败时返回错误。
+func EstimateAccessList(ctx context.Context, client chain.Client, rc *rpc.Client, msg ethereum.CallMsg) (*AccessListEstimate, error) {
+	msg.AccessList = nil
+	list, _, vmErr, err := gethclient.New(rc).CreateAccessList(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access list: %w", err)
+	}
+	if vmErr != "" {
+		return nil, fmt.Errorf("failed to create access list: call would fail: %s", vmErr)
+	}
+	without, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	e := &AccessListEstimate{AccessList: types.AccessList{}, GasWithout: without, GasWith: without}
+	if list == nil || len(*list) == 0 {
+		return e, nil
+	}
+	e.AccessList = *list
+	msg.AccessList = e.AccessList
+	if e.GasWith, err = client.EstimateGas(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to estimate gas with the access list: %w", err)
+	}
+	return e, nil
+}
@@ -0,0 +1,74 @@
+package ethtx
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// accessListNode 实现测试用的 eth_createAccessList 和 eth_estimateGas：
+// 调用访问一个外部合约的两个存储槽，带上列表后少用 200 gas
+type accessListNode struct {
+	list types.AccessList
+}
+
+type callArgs struct {
+	AccessList *types.AccessList `json:"accessList"`
+}
+
+func (n *accessListNode) CreateAccessList(args callArgs) map[string]interface{} {
+	return map[string]interface{}{"accessList": n.list, "gasUsed": hexutil.Uint64(50000)}
+}
+
+func (n *accessListNode) EstimateGas(args callArgs) hexutil.Uint64 {
+	if args.AccessList != nil && len(*args.AccessList) > 0 {
+		return 52000
+	}
+	return 52200
+}
+
+func TestEstimateAccessList(t *testing.T) {
+	pool := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	node := &accessListNode{list: types.AccessList{{Address: pool, StorageKeys: []common.Hash{{1}, {2}}}}}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", node); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	rc := rpc.DialInProc(server)
+	defer rc.Close()
+
+	tr := &Transfer{From: common.HexToAddress("0x01"), To: common.HexToAddress("0x02"), Value: new(big.Int), Data: []byte{1}}
+	e, err := EstimateAccessList(context.Background(), ethclient.NewClient(rc), rc, tr.CallMsg())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.GasWithout != 52200 || e.GasWith != 52000 || e.Saving() != 200 {
+		t.Fatalf("estimate = %+v, saving %d", e, e.Saving())
+	}
+	if len(e.AccessList) != 1 || e.AccessList.StorageKeys() != 2 {
+		t.Fatalf("access list = %+v", e.AccessList)
+	}
+
+	// 带访问列表的传统交易构造为 EIP-2930 交易
+	tr.AccessList, tr.GasPrice, tr.ChainID = e.AccessList, big.NewInt(1e9), big.NewInt(1)
+	if tx := tr.Tx(); tx.Type() != types.AccessListTxType || len(tx.AccessList()) != 1 {
+		t.Fatalf("legacy transfer with access list built type %d", tx.Type())
+	}
+	tr.GasFeeCap, tr.GasTipCap = big.NewInt(2e9), big.NewInt(1e9)
+	if tx := tr.Tx(); tx.Type() != types.DynamicFeeTxType || len(tx.AccessList()) != 1 {
+		t.Fatalf("dynamic transfer with access list built type %d", tx.Type())
+	}
+
+	// 节点给出空列表时不再估算，节省为 0
+	node.list = nil
+	if e, err = EstimateAccessList(context.Background(), ethclient.NewClient(rc), rc, tr.CallMsg()); err != nil || e.Saving() != 0 {
+		t.Fatalf("empty list: %+v, %v", e, err)
+	}
+}
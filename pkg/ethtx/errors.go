@@ -0,0 +1,107 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/assertions"
+)
+
+// 发送交易时可以用 errors.Is 区分的错误类别。Send、Simulate 和 EstimateGas 返回的回滚和节点拒绝已经归类，
+// 其他来源的错误（如 abigen 绑定和读取状态的 RPC 调用）可以先用 Classify 归类
+var (
+	// ErrInsufficientFunds 表示余额不足以支付转账金额加 gas 费
+	ErrInsufficientFunds = errors.New("insufficient balance")
+	// ErrNonceTooLow 表示交易的 nonce 已被使用，通常是同一账户的另一笔交易先被打包了
+	ErrNonceTooLow = errors.New("nonce too low")
+	// ErrRPCUnavailable 表示无法连接 RPC 端点，或端点返回 5xx、429 等暂时性错误
+	ErrRPCUnavailable = errors.New("RPC endpoint unavailable")
+	// ErrReverted 表示合约调用回滚，具体原因见 *RevertError
+	ErrReverted = errors.New("execution reverted")
+)
+
+// classified 把 kind 附加到 err 上，错误消息保持不变
+type classified struct {
+	kind error
+	err  error
+}
+
+func (e *classified) Error() string   { return e.err.Error() }
+func (e *classified) Unwrap() []error { return []error{e.kind, e.err} }
+
+// Classify 把节点和网络返回的错误归入上面的类别：回滚转换为带解码原因的 *RevertError，
+// 交易池的 nonce too low 和 insufficient funds 消息、连接失败和 5xx 响应分别附加对应的错误。
+// 已经归类的错误、context 取消和无法归类的错误原样返回
+func Classify(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	for _, kind := range []error{ErrInsufficientFunds, ErrNonceTooLow, ErrRPCUnavailable, ErrReverted} {
+		if errors.Is(err, kind) {
+			return err
+		}
+	}
+	if reason, ok := assertions.RevertReason(err); ok {
+		if reason == "0x" {
+			reason = ""
+		}
+		return &RevertError{Reason: reason, Err: err}
+	}
+	// 节点通过 JSON-RPC 错误消息返回交易池的拒绝原因（core.ErrNonceTooLow 等），无法用 errors.Is 匹配
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "nonce too low"):
+		return &classified{ErrNonceTooLow, err}
+	case strings.Contains(msg, "insufficient funds"):
+		return &classified{ErrInsufficientFunds, err}
+	case unavailable(err):
+		return &classified{ErrRPCUnavailable, err}
+	}
+	return err
+}
+
+// unavailable 报告 err 是否是网络层的失败：连接被拒绝、DNS 或超时（net.Error，包括 *url.Error）、
+// 连接中途断开，或 HTTP 5xx 和 429 响应
+func unavailable(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// RevertError 表示合约调用回滚：模拟执行时会回滚，或已上链的交易执行失败（Hash 非零）
+type RevertError struct {
+	Reason string      // 解码后的回滚原因；自定义错误时为原始回滚数据
+	Hash   common.Hash // 执行失败的已上链交易，模拟执行时为零值
+	Err    error       // 节点返回的原始错误
+}
+
+func (e *RevertError) Error() string {
+	prefix := "transaction would revert"
+	if e.Hash != (common.Hash{}) {
+		prefix = "transaction " + e.Hash.Hex() + " reverted"
+	}
+	if e.Reason == "" {
+		return prefix
+	}
+	return prefix + ": " + e.Reason
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 errors.Is(err, ErrReverted) 对所有 *RevertError 成立
+func (e *RevertError) Is(target error) bool {
+	return target == ErrReverted
+}
@@ -0,0 +1,89 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+func TestClassify(t *testing.T) {
+	refused := &url.Error{Op: "Post", URL: "http://127.0.0.1:8545", Err: syscall.ECONNREFUSED}
+	tests := []struct {
+		name string
+		err  error
+		want error // nil 表示不归类
+	}{
+		{"nonce", errors.New("nonce too low: address 0x01, tx: 3 state: 5"), ErrNonceTooLow},
+		{"funds", errors.New("insufficient funds for gas * price + value: balance 0"), ErrInsufficientFunds},
+		{"revert", &rpcError{msg: "execution reverted: not owner"}, ErrReverted},
+		{"refused", fmt.Errorf("failed to get chain ID: %w", refused), ErrRPCUnavailable},
+		{"503", rpc.HTTPError{StatusCode: 503, Status: "503 Service Unavailable"}, ErrRPCUnavailable},
+		{"400", rpc.HTTPError{StatusCode: 400, Status: "400 Bad Request"}, nil},
+		{"canceled", &url.Error{Op: "Post", URL: "http://127.0.0.1:8545", Err: context.Canceled}, nil},
+		{"other", errors.New("unknown method"), nil},
+	}
+	kinds := []error{ErrInsufficientFunds, ErrNonceTooLow, ErrRPCUnavailable, ErrReverted}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Classify(tt.err)
+			for _, kind := range kinds {
+				if errors.Is(err, kind) != (kind == tt.want) {
+					t.Errorf("errors.Is(Classify(%v), %v) = %v", tt.err, kind, !(kind == tt.want))
+				}
+			}
+			// 归类不改变消息，原始错误仍可取出
+			if tt.want != nil && tt.want != ErrReverted && err.Error() != tt.err.Error() {
+				t.Errorf("message changed to %q", err)
+			}
+			if !errors.Is(err, tt.err) && tt.name != "503" && tt.name != "400" {
+				t.Errorf("Classify(%v) lost the original error", tt.err)
+			}
+		})
+	}
+	if Classify(nil) != nil {
+		t.Error("Classify(nil) != nil")
+	}
+}
+
+func TestWaitReverted(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	w := wallet.New(key)
+	to := common.HexToAddress("0xc0ffee")
+	tx, err := w.SignTx(types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), To: &to, Gas: 50000, GasFeeCap: big.NewInt(1), Data: []byte{1}}), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replayed ethereum.CallMsg
+	client := &chain.ClientMock{
+		TransactionReceiptFunc: func(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+			return &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: hash, BlockNumber: big.NewInt(7)}, nil
+		},
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, block *big.Int) ([]byte, error) {
+			replayed = call
+			return nil, errors.New("execution reverted: not owner")
+		},
+	}
+	receipt, err := WaitConfirmed(context.Background(), client, tx, 1)
+	var revert *RevertError
+	if receipt == nil || !errors.As(err, &revert) || !errors.Is(err, ErrReverted) {
+		t.Fatalf("WaitConfirmed = %v, %v, want a RevertError", receipt, err)
+	}
+	if revert.Reason != "not owner" || revert.Hash != tx.Hash() || replayed.From != w.Address() {
+		t.Errorf("revert = %+v, replayed from %s", revert, replayed.From)
+	}
+	if want := "transaction " + tx.Hash().Hex() + " reverted: not owner"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,104 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// feeHistoryBlocks 是估算小费时参考的最近区块数
+const feeHistoryBlocks = 10
+
+// ErrNoDynamicFees 表示链不支持 EIP-1559（eth_feeHistory 没有返回 base fee）
+var ErrNoDynamicFees = errors.New("chain does not support EIP-1559 dynamic fees")
+
+// Fees 是 EIP-1559 交易的费用参数
+type Fees struct {
+	BaseFee   *big.Int // 下一个区块的 base fee
+	GasTipCap *big.Int // maxPriorityFeePerGas
+	GasFeeCap *big.Int // maxFeePerGas
+}
+
+// FeeStrategy 决定如何从 eth_feeHistory 估算费用：小费取最近区块第 Percentile 百分位小费的中位数，
+// maxFeePerGas 为下一区块 base fee 的 BaseFeePercent% 加小费。
+type FeeStrategy struct {
+	Name           string
+	Percentile     float64 // 每个区块中取的小费百分位，0 到 100
+	BaseFeePercent int64   // 为 base fee 上涨预留的空间，200 可以承受连续约 6 个满区块
+}
+
+// 预置的费用策略：slow 出价低、可能要等几个区块，fast 争取尽快被打包
+var (
+	SlowFees     = FeeStrategy{Name: "slow", Percentile: 10, BaseFeePercent: 125}
+	StandardFees = FeeStrategy{Name: "standard", Percentile: 50, BaseFeePercent: 200}
+	FastFees     = FeeStrategy{Name: "fast", Percentile: 90, BaseFeePercent: 200}
+)
+
+// ParseFeeStrategy 解析 slow、standard、fast 或自定义百分位（如 p75），空字符串表示 standard
+func ParseFeeStrategy(name string) (FeeStrategy, error) {
+	switch name {
+	case "", StandardFees.Name:
+		return StandardFees, nil
+	case SlowFees.Name:
+		return SlowFees, nil
+	case FastFees.Name:
+		return FastFees, nil
+	}
+	p, err := strconv.ParseFloat(strings.TrimPrefix(name, "p"), 64)
+	if err != nil || math.IsNaN(p) || p < 0 || p > 100 {
+		return FeeStrategy{}, fmt.Errorf("invalid fee strategy %q, want slow, standard, fast or a percentile such as p75", name)
+	}
+	return FeeStrategy{Name: name, Percentile: p, BaseFeePercent: StandardFees.BaseFeePercent}, nil
+}
+
+// SuggestFees 用 StandardFees 策略估算 EIP-1559 费用，见 SuggestFeesWith
+func SuggestFees(ctx context.Context, client chain.Client) (*Fees, error) {
+	return SuggestFeesWith(ctx, client, StandardFees)
+}
+
+// SuggestFeesWith 按策略 s 用 eth_feeHistory 估算 EIP-1559 费用，不依赖节点的 eth_gasPrice。
+// 链不支持 EIP-1559 时返回 ErrNoDynamicFees。
+func SuggestFeesWith(ctx context.Context, client chain.Client, s FeeStrategy) (*Fees, error) {
+	history, err := client.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{s.Percentile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	// BaseFee 比请求的区块多一项，最后一项是下一个区块的 base fee
+	if len(history.BaseFee) == 0 {
+		return nil, ErrNoDynamicFees
+	}
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	if baseFee == nil || baseFee.Sign() == 0 {
+		return nil, ErrNoDynamicFees
+	}
+
+	var tips []*big.Int
+	for _, rewards := range history.Reward {
+		// 空区块的小费报告为 0，不参与统计
+		if len(rewards) > 0 && rewards[0] != nil && rewards[0].Sign() > 0 {
+			tips = append(tips, rewards[0])
+		}
+	}
+	var tip *big.Int
+	if len(tips) > 0 {
+		sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+		tip = new(big.Int).Set(tips[len(tips)/2])
+	} else {
+		// 最近区块都没有带小费的交易时退回节点建议的小费
+		if tip, err = client.SuggestGasTipCap(ctx); err != nil {
+			return nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+		}
+	}
+
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(s.BaseFeePercent))
+	feeCap.Div(feeCap, big.NewInt(100))
+	feeCap.Add(feeCap, tip)
+	return &Fees{BaseFee: baseFee, GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
@@ -0,0 +1,112 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9))
+}
+
+func feeHistoryMock(history *ethereum.FeeHistory) *chain.ClientMock {
+	return &chain.ClientMock{
+		FeeHistoryFunc: func(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+			return history, nil
+		},
+		SuggestGasTipCapFunc: func(ctx context.Context) (*big.Int, error) {
+			return gwei(1), nil
+		},
+	}
+}
+
+func TestSuggestFees(t *testing.T) {
+	client := feeHistoryMock(&ethereum.FeeHistory{
+		Reward:  [][]*big.Int{{gwei(3)}, {gwei(1)}, {gwei(2)}},
+		BaseFee: []*big.Int{gwei(10), gwei(11), gwei(12), gwei(20)},
+	})
+	fees, err := SuggestFees(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 小费取 1、2、3 的中位数，maxFee = 2 × 20 + 2
+	if fees.BaseFee.Cmp(gwei(20)) != 0 || fees.GasTipCap.Cmp(gwei(2)) != 0 || fees.GasFeeCap.Cmp(gwei(42)) != 0 {
+		t.Errorf("SuggestFees = base %s tip %s cap %s, want 20/2/42 gwei", fees.BaseFee, fees.GasTipCap, fees.GasFeeCap)
+	}
+	if got := client.FeeHistoryCalls()[0].RewardPercentiles; len(got) != 1 || got[0] != StandardFees.Percentile {
+		t.Errorf("reward percentiles = %v", got)
+	}
+}
+
+func TestSuggestFeesEmptyBlocks(t *testing.T) {
+	client := feeHistoryMock(&ethereum.FeeHistory{
+		Reward:  [][]*big.Int{{}, {big.NewInt(0)}},
+		BaseFee: []*big.Int{gwei(5), gwei(5), gwei(5)},
+	})
+	fees, err := SuggestFees(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fees.GasTipCap.Cmp(gwei(1)) != 0 || fees.GasFeeCap.Cmp(gwei(11)) != 0 {
+		t.Errorf("SuggestFees = tip %s cap %s, want node tip 1 gwei and cap 11 gwei", fees.GasTipCap, fees.GasFeeCap)
+	}
+}
+
+func TestSuggestFeesLegacyChain(t *testing.T) {
+	for _, baseFees := range [][]*big.Int{nil, {big.NewInt(0), big.NewInt(0)}} {
+		client := feeHistoryMock(&ethereum.FeeHistory{BaseFee: baseFees})
+		if _, err := SuggestFees(context.Background(), client); !errors.Is(err, ErrNoDynamicFees) {
+			t.Errorf("base fees %v: error = %v, want ErrNoDynamicFees", baseFees, err)
+		}
+	}
+}
+
+func TestSuggestFeesWith(t *testing.T) {
+	client := feeHistoryMock(&ethereum.FeeHistory{
+		Reward:  [][]*big.Int{{gwei(1)}},
+		BaseFee: []*big.Int{gwei(10), gwei(20)},
+	})
+	fees, err := SuggestFeesWith(context.Background(), client, SlowFees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// maxFee = 1.25 × 20 + 1
+	if fees.GasFeeCap.Cmp(gwei(26)) != 0 {
+		t.Errorf("slow fee cap = %s, want 26 gwei", fees.GasFeeCap)
+	}
+	if got := client.FeeHistoryCalls()[0].RewardPercentiles; len(got) != 1 || got[0] != SlowFees.Percentile {
+		t.Errorf("reward percentiles = %v, want [%v]", got, SlowFees.Percentile)
+	}
+}
+
+func TestParseFeeStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    float64
+		wantErr bool
+	}{
+		{"", 50, false},
+		{"slow", 10, false},
+		{"fast", 90, false},
+		{"p75", 75, false},
+		{"33.3", 33.3, false},
+		{"p101", 0, true},
+		{"pNaN", 0, true},
+		{"turbo", 0, true},
+	}
+	for _, tt := range tests {
+		s, err := ParseFeeStrategy(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFeeStrategy(%q) error = %v", tt.name, err)
+			continue
+		}
+		if !tt.wantErr && s.Percentile != tt.want {
+			t.Errorf("ParseFeeStrategy(%q) percentile = %v, want %v", tt.name, s.Percentile, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ethtx
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/decode"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+// Unsigned 是离线签名流程中在联网机器和离线机器之间传递的未签名交易，序列化为 JSON。
+// Raw 是交易的规范编码，Tx 只是便于人工核对的解码结果，签名时以 Raw 为准。
+type Unsigned struct {
+	From    common.Address `json:"from"`
+	ChainID *hexutil.Big   `json:"chainId"`
+	Raw     hexutil.Bytes  `json:"raw"`
+	Tx      *decode.Tx     `json:"tx,omitempty"`
+}
+
+// NewUnsigned 把未签名交易包装为 Unsigned
+func NewUnsigned(from common.Address, chainID *big.Int, tx *types.Transaction) (*Unsigned, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encode transaction: %w", err)
+	}
+	return &Unsigned{From: from, ChainID: (*hexutil.Big)(chainID), Raw: raw, Tx: decode.Transaction(tx)}, nil
+}
+
+// Unsigned 返回转账的未签名交易，供离线签名
+func (t *Transfer) Unsigned() (*Unsigned, error) {
+	return NewUnsigned(t.From, t.ChainID, t.Tx())
+}
+
+// Transaction 解码 Raw 中的交易
+func (u *Unsigned) Transaction() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(u.Raw); err != nil {
+		return nil, fmt.Errorf("decode unsigned transaction: %w", err)
+	}
+	if tx.Type() != types.LegacyTxType && tx.ChainId().Cmp(u.ChainID.ToInt()) != 0 {
+		return nil, fmt.Errorf("transaction chain ID %s does not match %s", tx.ChainId(), u.ChainID.ToInt())
+	}
+	return tx, nil
+}
+
+// SignOffline 用 w 签名未签名交易，w 必须是交易指定的发送方，不需要联网
+func SignOffline(u *Unsigned, w wallet.Signer) (*types.Transaction, error) {
+	if u.ChainID == nil {
+		return nil, fmt.Errorf("unsigned transaction has no chain ID")
+	}
+	if w.Address() != u.From {
+		return nil, fmt.Errorf("signer %s cannot sign for %s", w.Address(), u.From)
+	}
+	tx, err := u.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	return w.SignTx(tx, u.ChainID.ToInt())
+}
+
+// Resign 用 w 重新签名已签名或未签名的交易 tx，保留所有字段只替换签名，支持所有交易类型。
+// chainID 为 nil 时使用交易自身的链 ID；没有重放保护的传统交易必须指定 chainID，签名后带上 EIP-155 保护。
+// 带类型的交易链 ID 是签名内容的一部分，与 chainID 不同时返回错误
+func Resign(tx *types.Transaction, w wallet.Signer, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil {
+		// 传统交易的链 ID 编码在签名的 V 中，未签名或签名不带 EIP-155 时无从得知
+		if _, r, s := tx.RawSignatureValues(); tx.Type() == types.LegacyTxType && (r.Sign() == 0 && s.Sign() == 0 || !tx.Protected()) {
+			return nil, fmt.Errorf("legacy transaction has no chain ID, specify one to sign it with replay protection")
+		}
+		chainID = tx.ChainId()
+	}
+	if tx.Type() != types.LegacyTxType && tx.ChainId().Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("transaction chain ID %s does not match %s", tx.ChainId(), chainID)
+	}
+	return w.SignTx(tx, chainID)
+}
+
+// Broadcast 解析十六进制编码的已签名交易并发送，返回解析出的交易
+func Broadcast(ctx context.Context, client chain.Client, rawHex string) (*types.Transaction, error) {
+	tx, err := decode.ParseRawTransaction(rawHex)
+	if err != nil {
+		return nil, err
+	}
+	if err := broadcast(ctx, client, tx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,118 @@
+package ethtx
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+func TestOfflineSigning(t *testing.T) {
+	backend, w := newTestWallet(t)
+	ctx := context.Background()
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	for _, prepare := range []func(context.Context, chain.Client, common.Address, common.Address, *big.Int) (*Transfer, error){Prepare, PrepareLegacy} {
+		// 联网机器：构造未签名交易并序列化
+		tr, err := prepare(ctx, backend.Client(), w.Address(), to, big.NewInt(1000))
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := tr.Unsigned()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 离线机器：只凭 JSON 和私钥签名
+		var offline Unsigned
+		if err := json.Unmarshal(data, &offline); err != nil {
+			t.Fatal(err)
+		}
+		signed, err := SignOffline(&offline, w)
+		if err != nil {
+			t.Fatalf("SignOffline: %v", err)
+		}
+		raw, err := signed.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 联网机器：广播已签名的十六进制交易
+		tx, err := Broadcast(ctx, backend.Client(), hexutil.Encode(raw))
+		if err != nil {
+			t.Fatalf("Broadcast type %d: %v", tr.Tx().Type(), err)
+		}
+		backend.Commit()
+		receipt, err := backend.Client().TransactionReceipt(ctx, tx.Hash())
+		if err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("type %d receipt = %v, %v", tx.Type(), receipt, err)
+		}
+	}
+}
+
+func TestSignOfflineWrongKey(t *testing.T) {
+	backend, w := newTestWallet(t)
+	tr, err := Prepare(context.Background(), backend.Client(), w.Address(), w.Address(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := tr.Unsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := crypto.GenerateKey()
+	if _, err := SignOffline(u, wallet.New(other)); err == nil {
+		t.Error("SignOffline with a different key succeeded, want error")
+	}
+	u.ChainID = (*hexutil.Big)(big.NewInt(1))
+	if _, err := SignOffline(u, w); err == nil {
+		t.Error("SignOffline with a mismatched chain ID succeeded, want error")
+	}
+}
+
+func TestResign(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	w := wallet.New(key)
+	chainID := big.NewInt(11155111)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	txs := []types.TxData{
+		&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(1)},
+		&types.AccessListTx{ChainID: chainID, Nonce: 2, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, AccessList: types.AccessList{{Address: to}}},
+		&types.DynamicFeeTx{ChainID: chainID, Nonce: 3, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(2e9), Gas: 21000, To: &to},
+		&types.BlobTx{ChainID: uint256.MustFromBig(chainID), Nonce: 4, GasTipCap: uint256.NewInt(1e9), GasFeeCap: uint256.NewInt(2e9), Gas: 21000, To: to,
+			BlobFeeCap: uint256.NewInt(1e9), BlobHashes: []common.Hash{{1}}},
+	}
+	for _, data := range txs {
+		tx := types.NewTx(data)
+		signed, err := Resign(tx, w, chainID)
+		if err != nil {
+			t.Fatalf("type %d: %v", tx.Type(), err)
+		}
+		if from, err := types.Sender(types.LatestSignerForChainID(chainID), signed); err != nil || from != w.Address() {
+			t.Errorf("type %d sender = %s, %v", tx.Type(), from, err)
+		}
+		if signed.Nonce() != tx.Nonce() || signed.ChainId().Cmp(chainID) != 0 {
+			t.Errorf("type %d: nonce %d chain %s", tx.Type(), signed.Nonce(), signed.ChainId())
+		}
+	}
+
+	// 没有重放保护的传统交易必须指定链 ID；带类型的交易不能换链
+	if _, err := Resign(types.NewTx(txs[0]), w, nil); err == nil {
+		t.Error("Resign of an unprotected legacy tx without a chain ID succeeded, want error")
+	}
+	if _, err := Resign(types.NewTx(txs[2]), w, big.NewInt(1)); err == nil {
+		t.Error("Resign with a mismatched chain ID succeeded, want error")
+	}
+}
@@ -0,0 +1,133 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// MinBumpPercent 是节点接受同一 nonce 的替换交易所需的最低加价比例（geth 交易池默认 10%）
+const MinBumpPercent = 10
+
+// ErrNotPending 表示交易已经被打包或其 nonce 已被其他交易使用，无法再替换
+var ErrNotPending = errors.New("transaction is no longer pending")
+
+// Pending 查询仍在交易池中的交易及其发送方
+func Pending(ctx context.Context, client chain.Client, hash common.Hash) (*types.Transaction, common.Address, error) {
+	tx, isPending, err := client.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get transaction %s: %w", hash.Hex(), err)
+	}
+	if !isPending {
+		return nil, common.Address{}, fmt.Errorf("%s: %w", hash.Hex(), ErrNotPending)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("recover sender of %s: %w", hash.Hex(), err)
+	}
+	// 交易池中可能残留已被同 nonce 交易取代的交易
+	mined, err := client.NonceAt(ctx, from, nil)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	if mined > tx.Nonce() {
+		return nil, common.Address{}, fmt.Errorf("%s: nonce %d already used: %w", hash.Hex(), tx.Nonce(), ErrNotPending)
+	}
+	return tx, from, nil
+}
+
+// SpeedUp 构造与 tx 内容相同、费用提高 percent% 的替换交易（未签名）。
+// 新费用不低于节点当前的建议值，percent 不能小于 MinBumpPercent。
+func SpeedUp(ctx context.Context, client chain.Client, tx *types.Transaction, percent int) (*types.Transaction, error) {
+	return replace(ctx, client, tx, percent, tx.To(), tx.Value(), tx.Data(), tx.Gas())
+}
+
+// Cancel 构造用同一 nonce 向 from 自己转 0 ETH 的替换交易（未签名），费用规则与 SpeedUp 相同。
+// 取消交易被打包后原交易就不会再执行。
+func Cancel(ctx context.Context, client chain.Client, tx *types.Transaction, from common.Address, percent int) (*types.Transaction, error) {
+	return replace(ctx, client, tx, percent, &from, new(big.Int), nil, TransferGas)
+}
+
+// WaitNonce 等待 from 的 nonce 被某笔交易使用，返回 candidates 中被打包的那一笔的收据。
+// 如果 nonce 被其他交易使用（例如从别的钱包发出的替换），返回 nil 收据。
+func WaitNonce(ctx context.Context, client chain.Client, from common.Address, nonce uint64, candidates ...common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		mined, err := client.NonceAt(ctx, from, nil)
+		if err == nil && mined > nonce {
+			for _, hash := range candidates {
+				if receipt, err := client.TransactionReceipt(ctx, hash); err == nil {
+					return receipt, nil
+				}
+			}
+			return nil, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for nonce %d of %s: %w", nonce, from.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// replace 构造与 tx 同一 nonce、费用提高 percent% 的交易，内容由 to、value、data、gas 指定
+func replace(ctx context.Context, client chain.Client, tx *types.Transaction, percent int, to *common.Address, value *big.Int, data []byte, gas uint64) (*types.Transaction, error) {
+	if percent < MinBumpPercent {
+		return nil, fmt.Errorf("fee bump %d%% is below the %d%% nodes require for replacement", percent, MinBumpPercent)
+	}
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		price, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+		price = maxBig(bump(tx.GasPrice(), percent), price)
+		if tx.Type() == types.AccessListTxType {
+			return types.NewTx(&types.AccessListTx{
+				ChainID: tx.ChainId(), Nonce: tx.Nonce(), GasPrice: price, Gas: gas,
+				To: to, Value: value, Data: data, AccessList: tx.AccessList(),
+			}), nil
+		}
+		return types.NewTx(&types.LegacyTx{
+			Nonce: tx.Nonce(), GasPrice: price, Gas: gas, To: to, Value: value, Data: data,
+		}), nil
+	case types.DynamicFeeTxType:
+		tip, feeCap := bump(tx.GasTipCap(), percent), bump(tx.GasFeeCap(), percent)
+		if fees, err := SuggestFees(ctx, client); err == nil {
+			tip, feeCap = maxBig(tip, fees.GasTipCap), maxBig(feeCap, fees.GasFeeCap)
+		} else if !errors.Is(err, ErrNoDynamicFees) {
+			return nil, err
+		}
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID: tx.ChainId(), Nonce: tx.Nonce(), GasTipCap: tip, GasFeeCap: maxBig(feeCap, tip), Gas: gas,
+			To: to, Value: value, Data: data, AccessList: tx.AccessList(),
+		}), nil
+	default:
+		return nil, fmt.Errorf("replacing transaction type %d is not supported", tx.Type())
+	}
+}
+
+// bump 返回 x 提高 percent% 后的值（向上取整）
+func bump(x *big.Int, percent int) *big.Int {
+	n := new(big.Int).Mul(x, big.NewInt(int64(100+percent)))
+	n.Add(n, big.NewInt(99))
+	return n.Div(n, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
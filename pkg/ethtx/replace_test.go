@@ -0,0 +1,119 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+func TestBump(t *testing.T) {
+	tests := []struct{ x, percent, want int64 }{
+		{100, 10, 110},
+		{1000000001, 10, 1100000002}, // 向上取整，保证严格达到加价比例
+		{0, 10, 0},
+		{7, 50, 11},
+	}
+	for _, tt := range tests {
+		if got := bump(big.NewInt(tt.x), int(tt.percent)); got.Int64() != tt.want {
+			t.Errorf("bump(%d, %d) = %s, want %d", tt.x, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestSpeedUp(t *testing.T) {
+	backend, w := newTestWallet(t)
+	ctx := context.Background()
+	client := backend.Client()
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	for _, prepare := range []func(context.Context, chain.Client, common.Address, common.Address, *big.Int) (*Transfer, error){Prepare, PrepareLegacy} {
+		tr, err := prepare(ctx, client, w.Address(), to, big.NewInt(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		original, err := Send(ctx, client, w, tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pending, from, err := Pending(ctx, client, original.Hash())
+		if err != nil {
+			t.Fatalf("Pending: %v", err)
+		}
+		if from != w.Address() {
+			t.Errorf("sender = %s, want %s", from, w.Address())
+		}
+		if _, err := SpeedUp(ctx, client, pending, 5); err == nil {
+			t.Error("SpeedUp with a 5% bump succeeded, want error")
+		}
+		replacement, err := SpeedUp(ctx, client, pending, 20)
+		if err != nil {
+			t.Fatalf("SpeedUp: %v", err)
+		}
+		if replacement.Nonce() != original.Nonce() || *replacement.To() != to || replacement.Type() != original.Type() {
+			t.Errorf("replacement = %+v, want same nonce, recipient and type", replacement)
+		}
+		signed, err := w.SignTx(replacement, tr.ChainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.SendTransaction(ctx, signed); err != nil {
+			t.Fatalf("send replacement: %v", err)
+		}
+		backend.Commit()
+
+		if receipt, err := client.TransactionReceipt(ctx, signed.Hash()); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("replacement receipt = %v, %v", receipt, err)
+		}
+		if _, err := client.TransactionReceipt(ctx, original.Hash()); err == nil {
+			t.Error("original transaction was mined, want it replaced")
+		}
+		if _, _, err := Pending(ctx, client, signed.Hash()); !errors.Is(err, ErrNotPending) {
+			t.Errorf("Pending after mining = %v, want ErrNotPending", err)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	backend, w := newTestWallet(t)
+	ctx := context.Background()
+	client := backend.Client()
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	original, err := SendETH(ctx, client, w, to, big.NewInt(1e15))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel, err := Cancel(ctx, client, original, w.Address(), MinBumpPercent)
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if *cancel.To() != w.Address() || cancel.Value().Sign() != 0 || cancel.Nonce() != original.Nonce() || cancel.Gas() != TransferGas {
+		t.Errorf("cancel tx = to %s value %s nonce %d gas %d, want 0-value self-transfer with nonce %d", cancel.To(), cancel.Value(), cancel.Nonce(), cancel.Gas(), original.Nonce())
+	}
+	chainID, _ := client.ChainID(ctx)
+	signed, err := w.SignTx(cancel, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(ctx, signed); err != nil {
+		t.Fatalf("send cancel: %v", err)
+	}
+	backend.Commit()
+
+	receipt, err := WaitNonce(ctx, client, w.Address(), original.Nonce(), original.Hash(), signed.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt == nil || receipt.TxHash != signed.Hash() {
+		t.Errorf("WaitNonce = %v, want the cancel transaction", receipt)
+	}
+	if balance, _ := client.BalanceAt(ctx, to, nil); balance.Sign() != 0 {
+		t.Errorf("recipient balance = %s, want 0 after cancel", balance)
+	}
+}
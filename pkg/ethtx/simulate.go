@@ -0,0 +1,33 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// Simulate 用 eth_call 在最新区块上执行 msg，调用会回滚时返回 *RevertError。
+// 发送合约调用前先模拟，可以避免为注定失败的交易支付 gas。
+func Simulate(ctx context.Context, client chain.Client, msg ethereum.CallMsg) error {
+	if _, err := client.CallContract(ctx, msg, nil); err != nil {
+		// 回滚时返回 *RevertError，没有回滚数据（如 require 不带消息或向非 payable 函数转账）时 Reason 为空
+		err = Classify(err)
+		if errors.Is(err, ErrReverted) {
+			return err
+		}
+		return fmt.Errorf("failed to simulate transaction: %w", err)
+	}
+	return nil
+}
+
+// SimulateTx 以 from 的身份模拟执行 tx 的调用（接收方、金额、数据和 gas 上限）
+func SimulateTx(ctx context.Context, client chain.Client, from common.Address, tx *types.Transaction) error {
+	return Simulate(ctx, client, ethereum.CallMsg{
+		From: from, To: tx.To(), Gas: tx.Gas(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList(),
+	})
+}
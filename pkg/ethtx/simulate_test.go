@@ -0,0 +1,59 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// rpcError 模拟节点返回的带回滚数据的 JSON-RPC 错误
+type rpcError struct {
+	msg  string
+	data string
+}
+
+func (e *rpcError) Error() string          { return e.msg }
+func (e *rpcError) ErrorData() interface{} { return e.data }
+
+func TestSimulate(t *testing.T) {
+	// Error(string) 编码的 "Counter: overflow"
+	const data = "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000011" +
+		"436f756e7465723a206f766572666c6f77000000000000000000000000000000"
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+		wantRevert bool
+	}{
+		{"success", nil, "", false},
+		{"revert data", &rpcError{msg: "execution reverted", data: data}, "Counter: overflow", true},
+		{"message only", errors.New("execution reverted: not owner"), "not owner", true},
+		{"not a revert", errors.New("connection refused"), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &chain.ClientMock{
+				CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+					return nil, tt.err
+				},
+			}
+			err := Simulate(context.Background(), client, ethereum.CallMsg{})
+			var revert *RevertError
+			if errors.As(err, &revert) != tt.wantRevert {
+				t.Fatalf("Simulate error = %v, want revert %v", err, tt.wantRevert)
+			}
+			if tt.wantRevert && revert.Reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", revert.Reason, tt.wantReason)
+			}
+			if (err == nil) != (tt.err == nil) {
+				t.Errorf("Simulate error = %v, want error %v", err, tt.err != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+// Package ethtx 构造、签名并发送 ETH 转账交易（task01 的转账流程），所有错误都返回给调用方。
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/tracing"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+// TransferGas 是向普通账户转账 ETH 的固定 gas 用量
+const TransferGas = 21000
+
+// DefaultGasBuffer 是在 eth_estimateGas 结果上增加的默认安全余量（百分比）
+const DefaultGasBuffer = 20
+
+// Transfer 是一笔待发送的转账及其费用明细。GasFeeCap 非空时构造 EIP-1559 交易
+// （DynamicFeeTx），否则构造使用 GasPrice 的传统交易，带有 AccessList 时为 EIP-2930 交易。
+type Transfer struct {
+	From      common.Address
+	To        common.Address
+	Value     *big.Int
+	Data      []byte // 调用数据，普通转账为空
+	Nonce     uint64
+	GasLimit  uint64
+	GasPrice  *big.Int // 传统交易的 gas 价格
+	GasTipCap *big.Int // EIP-1559 maxPriorityFeePerGas
+	GasFeeCap *big.Int // EIP-1559 maxFeePerGas
+	ChainID   *big.Int
+	Balance   *big.Int // 发送方当前余额
+
+	AccessList types.AccessList // EIP-2930 访问列表，通常来自 EstimateAccessList
+	L1Fee      *big.Int         // L2 上另外收取的 L1 数据费用（见 SetL1Fee），nil 表示没有
+}
+
+// Dynamic 报告是否构造 EIP-1559 交易
+func (t *Transfer) Dynamic() bool {
+	return t.GasFeeCap != nil
+}
+
+// Cost 返回转账金额加最大 gas 费（EIP-1559 交易按 maxFeePerGas 计算），以及 L2 上的 L1 数据费用
+func (t *Transfer) Cost() *big.Int {
+	fee := t.maxGasFee()
+	if t.L1Fee != nil {
+		fee.Add(fee, t.L1Fee)
+	}
+	return fee.Add(fee, t.Value)
+}
+
+// maxGasFee 返回按 GasLimit 计算的最大执行 gas 费
+func (t *Transfer) maxGasFee() *big.Int {
+	price := t.GasPrice
+	if t.Dynamic() {
+		price = t.GasFeeCap
+	}
+	return new(big.Int).Mul(price, new(big.Int).SetUint64(t.GasLimit))
+}
+
+// Tx 返回未签名的交易
+func (t *Transfer) Tx() *types.Transaction {
+	to := t.To
+	if t.Dynamic() {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    t.ChainID,
+			Nonce:      t.Nonce,
+			To:         &to,
+			Value:      t.Value,
+			Data:       t.Data,
+			Gas:        t.GasLimit,
+			GasTipCap:  t.GasTipCap,
+			GasFeeCap:  t.GasFeeCap,
+			AccessList: t.AccessList,
+		})
+	}
+	if t.AccessList != nil {
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    t.ChainID,
+			Nonce:      t.Nonce,
+			To:         &to,
+			Value:      t.Value,
+			Data:       t.Data,
+			Gas:        t.GasLimit,
+			GasPrice:   t.GasPrice,
+			AccessList: t.AccessList,
+		})
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    t.Nonce,
+		To:       &to,
+		Value:    t.Value,
+		Data:     t.Data,
+		Gas:      t.GasLimit,
+		GasPrice: t.GasPrice,
+	})
+}
+
+// Prepare 从节点读取 nonce、链 ID、余额和费用参数，填好一笔从 from 到 to 的转账。
+// gas 上限用 EstimateGas 估算并加上 DefaultGasBuffer 的余量，
+// 费用优先用 SuggestFees 构造 EIP-1559 交易，链不支持时退回传统的 gas 价格。
+// 余额不足以支付 Cost 时返回 ErrInsufficientFunds，此时 Transfer 仍然返回以便显示费用。
+func Prepare(ctx context.Context, client chain.Client, from, to common.Address, value *big.Int) (*Transfer, error) {
+	return prepare(ctx, client, from, to, value, nil, false)
+}
+
+// PrepareLegacy 与 Prepare 相同，但总是构造使用 eth_gasPrice 的传统交易
+func PrepareLegacy(ctx context.Context, client chain.Client, from, to common.Address, value *big.Int) (*Transfer, error) {
+	return prepare(ctx, client, from, to, value, nil, true)
+}
+
+// PrepareCall 与 Prepare 相同，但交易带有调用数据 data（如 ERC-20 transfer），gas 按该调用估算
+func PrepareCall(ctx context.Context, client chain.Client, from, to common.Address, value *big.Int, data []byte) (*Transfer, error) {
+	return prepare(ctx, client, from, to, value, data, false)
+}
+
+func prepare(ctx context.Context, client chain.Client, from, to common.Address, value *big.Int, data []byte, legacy bool) (*Transfer, error) {
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	balance, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	nonceCtx, span := tracing.Start(ctx, "fetch nonce", tracing.KindInternal)
+	nonce, err := client.PendingNonceAt(nonceCtx, from)
+	span.SetAttr("tx.nonce", nonce)
+	span.End(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	t := &Transfer{
+		From:     from,
+		To:       to,
+		Value:    value,
+		Data:     data,
+		Nonce:    nonce,
+		GasLimit: TransferGas,
+		ChainID:  chainID,
+		Balance:  balance,
+	}
+	// 余额不足时估算会失败，保留 TransferGas 以便显示费用并返回 ErrInsufficientFunds
+	if balance.Cmp(value) >= 0 {
+		if err := t.EstimateGas(ctx, client, DefaultGasBuffer); err != nil {
+			return nil, err
+		}
+	}
+	if !legacy {
+		fees, err := SuggestFees(ctx, client)
+		switch {
+		case err == nil:
+			t.GasTipCap, t.GasFeeCap = fees.GasTipCap, fees.GasFeeCap
+			return t, t.Check()
+		case !errors.Is(err, ErrNoDynamicFees):
+			return nil, err
+		}
+	}
+	if t.GasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+	}
+	return t, t.Check()
+}
+
+// SetFees 按策略 s 重新估算 EIP-1559 费用，传统交易保持不变。修改后应再次调用 Check。
+func (t *Transfer) SetFees(ctx context.Context, client chain.Client, s FeeStrategy) error {
+	if !t.Dynamic() {
+		return nil
+	}
+	fees, err := SuggestFeesWith(ctx, client, s)
+	if err != nil {
+		return err
+	}
+	t.GasTipCap, t.GasFeeCap = fees.GasTipCap, fees.GasFeeCap
+	return nil
+}
+
+// L1FeeOracle 是 L2 链的适配器，估算交易在执行 gas 之外需要支付的 L1 数据费用，如 opstack.GasPriceOracle
+type L1FeeOracle interface {
+	L1Fee(ctx context.Context, tx *types.Transaction) (*big.Int, error)
+}
+
+// L1FeeBuffer 是在估算的 L1 数据费用上增加的余量（百分比）。费用随 L1 的 base fee 变化，
+// 余额只够估算值时，交易可能因为打包前 L1 费用上涨而无法执行
+const L1FeeBuffer = 25
+
+// SetL1Fee 用 o 估算当前交易的 L1 数据费用，加上 L1FeeBuffer 的余量计入 Cost。费用取决于交易的编码长度，
+// 修改调用数据或 gas 参数后应再次调用，然后调用 Check
+func (t *Transfer) SetL1Fee(ctx context.Context, o L1FeeOracle) error {
+	fee, err := o.L1Fee(ctx, t.Tx())
+	if err != nil {
+		return fmt.Errorf("failed to estimate L1 data fee: %w", err)
+	}
+	t.L1Fee = bump(fee, L1FeeBuffer)
+	return nil
+}
+
+// CallMsg 返回执行这笔转账的调用，用于估算 gas 和访问列表
+func (t *Transfer) CallMsg() ethereum.CallMsg {
+	to := t.To
+	return ethereum.CallMsg{From: t.From, To: &to, Value: t.Value, Data: t.Data, AccessList: t.AccessList}
+}
+
+// EstimateGas 按当前的接收方、金额、调用数据和访问列表重新估算 GasLimit，加上 buffer% 的余量
+func (t *Transfer) EstimateGas(ctx context.Context, client chain.Client, buffer int) error {
+	gas, err := EstimateGas(ctx, client, t.CallMsg(), buffer)
+	if err != nil {
+		return err
+	}
+	t.GasLimit = gas
+	return nil
+}
+
+// EstimateGas 调用 eth_estimateGas 并加上 buffer% 的余量（向上取整）。
+// 结果恰好是 TransferGas 时说明是向普通账户转账，用量固定，不加余量。
+func EstimateGas(ctx context.Context, client chain.Client, msg ethereum.CallMsg, buffer int) (uint64, error) {
+	ctx, span := tracing.Start(ctx, "estimate gas", tracing.KindInternal)
+	gas, err := client.EstimateGas(ctx, msg)
+	span.SetAttr("tx.gas_estimate", gas)
+	span.End(err)
+	if err != nil {
+		// 估算失败时只归类回滚：Prepare 等函数返回 ErrInsufficientFunds 时总会同时返回 Transfer 以便显示费用
+		if r := Classify(err); errors.Is(r, ErrReverted) {
+			err = r
+		}
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	if gas == TransferGas || buffer <= 0 {
+		return gas, nil
+	}
+	return bump(new(big.Int).SetUint64(gas), buffer).Uint64(), nil
+}
+
+// Check 检查余额是否足以支付 Cost，修改 gas 参数后可以再次调用
+func (t *Transfer) Check() error {
+	if t.Balance.Cmp(t.Cost()) < 0 {
+		return fmt.Errorf("%w: need %s wei but only have %s wei", ErrInsufficientFunds, t.Cost(), t.Balance)
+	}
+	return nil
+}
+
+// SendAll 把 Value 设为余额减去最大 gas 费（和 L1 数据费用），转出账户的全部余额，应在 gas 参数确定之后调用。
+// EIP-1559 交易的 GasTipCap 同时设为 GasFeeCap，这样实际 gas 价格恰好是 GasFeeCap，费用不会部分退回、
+// 账户不留零头，代价是 base fee 以外的部分都作为小费付给出块者。只有 gas 用量等于 GasLimit
+// （向普通账户转账时为 TransferGas）且没有 L1 数据费用的余量时余额才会正好归零。余额不足以支付 gas 费时返回 ErrInsufficientFunds
+func (t *Transfer) SendAll() error {
+	if t.Dynamic() {
+		t.GasTipCap = t.GasFeeCap
+	}
+	fee := t.maxGasFee()
+	if t.L1Fee != nil {
+		fee.Add(fee, t.L1Fee)
+	}
+	if t.Balance.Cmp(fee) <= 0 {
+		return fmt.Errorf("%w: gas costs up to %s wei but only have %s wei", ErrInsufficientFunds, fee, t.Balance)
+	}
+	t.Value = fee.Sub(t.Balance, fee)
+	return nil
+}
+
+// Sign 用 w 签名转账但不广播，可用于预演或在别处广播
+func Sign(w wallet.Signer, t *Transfer) (*types.Transaction, error) {
+	if w.Address() != t.From {
+		return nil, fmt.Errorf("signer %s cannot sign for %s", w.Address(), t.From)
+	}
+	return w.SignTx(t.Tx(), t.ChainID)
+}
+
+// Send 用 w 签名转账并广播，返回已签名的交易
+func Send(ctx context.Context, client chain.Client, w wallet.Signer, t *Transfer) (*types.Transaction, error) {
+	// 外部签名器（如 Clef）需要用户确认，签名可能是最慢的一步
+	_, span := tracing.Start(ctx, "sign", tracing.KindInternal)
+	signed, err := Sign(w, t)
+	span.End(err)
+	if err != nil {
+		return nil, err
+	}
+	if err := broadcast(ctx, client, signed); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return signed, nil
+}
+
+// broadcast 调用 eth_sendRawTransaction，节点拒绝的原因按 Classify 归类
+func broadcast(ctx context.Context, client chain.Client, tx *types.Transaction) error {
+	ctx, span := tracing.Start(ctx, "broadcast", tracing.KindInternal)
+	span.SetAttr("tx.hash", tx.Hash().Hex())
+	err := Classify(client.SendTransaction(ctx, tx))
+	span.End(err)
+	return err
+}
+
+// SendETH 是 Prepare 加 Send 的便捷写法
+func SendETH(ctx context.Context, client chain.Client, w wallet.Signer, to common.Address, value *big.Int) (*types.Transaction, error) {
+	t, err := Prepare(ctx, client, w.Address(), to, value)
+	if err != nil {
+		return nil, err
+	}
+	return Send(ctx, client, w, t)
+}
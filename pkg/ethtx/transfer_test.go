@@ -0,0 +1,228 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/wallet"
+)
+
+func newTestWallet(t *testing.T) (*simulated.Backend, *wallet.Wallet) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	w := wallet.New(key)
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		w.Address(): {Balance: big.NewInt(params.Ether)},
+	})
+	t.Cleanup(func() { backend.Close() })
+	return backend, w
+}
+
+func TestSendETH(t *testing.T) {
+	backend, w := newTestWallet(t)
+	ctx := context.Background()
+	client := backend.Client()
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	tx, err := SendETH(ctx, client, w, to, big.NewInt(1e15))
+	if err != nil {
+		t.Fatalf("SendETH: %v", err)
+	}
+	if tx.Type() != types.DynamicFeeTxType {
+		t.Errorf("transaction type = %d, want EIP-1559", tx.Type())
+	}
+	backend.Commit()
+
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful || receipt.GasUsed != TransferGas {
+		t.Errorf("receipt status %d gas %d, want success with %d gas", receipt.Status, receipt.GasUsed, TransferGas)
+	}
+	balance, err := client.BalanceAt(ctx, to, nil)
+	if err != nil || balance.Cmp(big.NewInt(1e15)) != 0 {
+		t.Errorf("recipient balance = %v, %v, want 1e15", balance, err)
+	}
+}
+
+func TestPrepareLegacy(t *testing.T) {
+	backend, w := newTestWallet(t)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	tr, err := PrepareLegacy(context.Background(), backend.Client(), w.Address(), to, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Dynamic() || tr.GasPrice == nil || tr.Tx().Type() != types.LegacyTxType {
+		t.Errorf("PrepareLegacy built %+v, want a legacy transaction", tr)
+	}
+	tx, err := Send(context.Background(), backend.Client(), w, tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if receipt, err := backend.Client().TransactionReceipt(context.Background(), tx.Hash()); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Errorf("legacy transfer receipt = %v, %v", receipt, err)
+	}
+}
+
+func TestPrepareInsufficientFunds(t *testing.T) {
+	backend, w := newTestWallet(t)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	tr, err := Prepare(context.Background(), backend.Client(), w.Address(), to, big.NewInt(params.Ether))
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Prepare error = %v, want ErrInsufficientFunds", err)
+	}
+	if tr == nil || tr.Cost().Cmp(tr.Balance) <= 0 {
+		t.Errorf("Prepare should still return the transfer with cost above balance, got %+v", tr)
+	}
+}
+
+func TestSendAll(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		backend, w := newTestWallet(t)
+		ctx := context.Background()
+		client := backend.Client()
+		to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+		prepare := Prepare
+		if legacy {
+			prepare = PrepareLegacy
+		}
+		tr, err := prepare(ctx, client, w.Address(), to, new(big.Int))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.SendAll(); err != nil {
+			t.Fatal(err)
+		}
+		if tr.Cost().Cmp(tr.Balance) != 0 {
+			t.Errorf("legacy=%v: cost %s != balance %s", legacy, tr.Cost(), tr.Balance)
+		}
+		if _, err := Send(ctx, client, w, tr); err != nil {
+			t.Fatal(err)
+		}
+		backend.Commit()
+		if left, err := client.BalanceAt(ctx, w.Address(), nil); err != nil || left.Sign() != 0 {
+			t.Errorf("legacy=%v: balance after sweep = %v, %v, want 0", legacy, left, err)
+		}
+		if got, _ := client.BalanceAt(ctx, to, nil); got.Cmp(tr.Value) != 0 {
+			t.Errorf("legacy=%v: recipient got %v, want %v", legacy, got, tr.Value)
+		}
+	}
+
+	empty := &Transfer{Balance: big.NewInt(1000), GasLimit: TransferGas, GasPrice: big.NewInt(1)}
+	if err := empty.SendAll(); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("SendAll with balance below the fee = %v, want ErrInsufficientFunds", err)
+	}
+}
+
+func TestSendWrongWallet(t *testing.T) {
+	backend, w := newTestWallet(t)
+	other, _ := crypto.GenerateKey()
+	tr, err := Prepare(context.Background(), backend.Client(), w.Address(), w.Address(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Send(context.Background(), backend.Client(), wallet.New(other), tr); err == nil {
+		t.Error("Send with a different wallet succeeded, want error")
+	}
+}
+
+func TestEstimateGas(t *testing.T) {
+	tests := []struct {
+		estimate uint64
+		buffer   int
+		want     uint64
+	}{
+		{TransferGas, DefaultGasBuffer, TransferGas}, // 普通转账不加余量
+		{50000, DefaultGasBuffer, 60000},
+		{43251, 20, 51902}, // 向上取整
+		{50000, 0, 50000},
+	}
+	for _, tt := range tests {
+		client := &chain.ClientMock{
+			EstimateGasFunc: func(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+				return tt.estimate, nil
+			},
+		}
+		got, err := EstimateGas(context.Background(), client, ethereum.CallMsg{}, tt.buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("EstimateGas(%d, %d%%) = %d, want %d", tt.estimate, tt.buffer, got, tt.want)
+		}
+	}
+}
+
+func TestPrepareWithData(t *testing.T) {
+	backend, w := newTestWallet(t)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	tr, err := Prepare(context.Background(), backend.Client(), w.Address(), to, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.GasLimit != TransferGas {
+		t.Errorf("plain transfer gas = %d, want %d", tr.GasLimit, TransferGas)
+	}
+	// 带调用数据时用量超过 TransferGas，估算结果要加上余量
+	tr.Data = []byte{1, 2, 3, 4}
+	if err := tr.EstimateGas(context.Background(), backend.Client(), DefaultGasBuffer); err != nil {
+		t.Fatal(err)
+	}
+	if tr.GasLimit <= TransferGas*12/10 {
+		t.Errorf("gas with data = %d, want more than %d", tr.GasLimit, TransferGas*12/10)
+	}
+	tx, err := Send(context.Background(), backend.Client(), w, tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if receipt, err := backend.Client().TransactionReceipt(context.Background(), tx.Hash()); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Errorf("receipt = %v, %v", receipt, err)
+	}
+}
+
+// fixedL1Fee 是返回固定 L1 数据费用的测试适配器
+type fixedL1Fee int64
+
+func (f fixedL1Fee) L1Fee(ctx context.Context, tx *types.Transaction) (*big.Int, error) {
+	return big.NewInt(int64(f)), nil
+}
+
+func TestSetL1Fee(t *testing.T) {
+	tr := &Transfer{Value: big.NewInt(1000), Balance: big.NewInt(60_000), GasLimit: TransferGas, GasPrice: big.NewInt(1), ChainID: big.NewInt(10)}
+	if err := tr.SetL1Fee(context.Background(), fixedL1Fee(40_000)); err != nil {
+		t.Fatal(err)
+	}
+	// 40000 加 25% 余量
+	if tr.L1Fee.Int64() != 50_000 || tr.Cost().Int64() != 1000+TransferGas+50_000 {
+		t.Fatalf("L1 fee %s, cost %s", tr.L1Fee, tr.Cost())
+	}
+	if err := tr.Check(); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Check = %v, want ErrInsufficientFunds", err)
+	}
+	tr.Balance = big.NewInt(80_000)
+	if err := tr.SendAll(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Value.Int64() != 80_000-TransferGas-50_000 || tr.Cost().Cmp(tr.Balance) != 0 {
+		t.Fatalf("SendAll value %s, cost %s", tr.Value, tr.Cost())
+	}
+}
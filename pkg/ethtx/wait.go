@@ -0,0 +1,109 @@
+package ethtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/tracing"
+)
+
+// pollInterval 是等待确认时轮询节点的间隔
+var pollInterval = time.Second
+
+// WaitConfirmed 等待交易被打包且成功，并且其所在区块之上已有足够的区块，
+// confirmations 为 1 表示只要被打包。等待期间如果交易因重组离开了原区块，会重新等待。
+func WaitConfirmed(ctx context.Context, client chain.Client, tx *types.Transaction, confirmations uint64) (receipt *types.Receipt, err error) {
+	ctx, span := tracing.Start(ctx, "wait mined", tracing.KindInternal)
+	span.SetAttr("tx.hash", tx.Hash().Hex())
+	span.SetAttr("tx.confirmations", confirmations)
+	defer func() { span.End(err) }()
+	return waitUntil(ctx, client, tx, func(receipt *types.Receipt) (bool, error) {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to get block number: %w", err)
+		}
+		return head+1 >= receipt.BlockNumber.Uint64()+confirmations, nil
+	})
+}
+
+// WaitFinalized 等待交易所在区块不晚于 tag 标记的区块，tag 为 rpc.SafeBlockNumber 或
+// rpc.FinalizedBlockNumber。在以太坊主网上 finalized 通常需要约 15 分钟。
+func WaitFinalized(ctx context.Context, client chain.Client, tx *types.Transaction, tag rpc.BlockNumber) (receipt *types.Receipt, err error) {
+	if tag != rpc.SafeBlockNumber && tag != rpc.FinalizedBlockNumber {
+		return nil, fmt.Errorf("unsupported block tag %s, want safe or finalized", tag)
+	}
+	ctx, span := tracing.Start(ctx, "wait "+tag.String(), tracing.KindInternal)
+	span.SetAttr("tx.hash", tx.Hash().Hex())
+	defer func() { span.End(err) }()
+	return waitUntil(ctx, client, tx, func(receipt *types.Receipt) (bool, error) {
+		header, err := client.HeaderByNumber(ctx, big.NewInt(tag.Int64()))
+		if errors.Is(err, ethereum.NotFound) {
+			// 链刚启动时还没有 safe/finalized 区块
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to get %s block: %w", tag, err)
+		}
+		return header.Number.Cmp(receipt.BlockNumber) >= 0, nil
+	})
+}
+
+// waitUntil 轮询交易收据直到 done 返回 true，确认最终的收据仍在同一个区块
+func waitUntil(ctx context.Context, client chain.Client, tx *types.Transaction, done func(*types.Receipt) (bool, error)) (*types.Receipt, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		// 与 bind.WaitMined 一样，查询收据出错（如节点仍在建立交易索引）时继续重试
+		receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+		switch {
+		case err != nil:
+		case receipt.Status != types.ReceiptStatusSuccessful:
+			return receipt, &RevertError{
+				Reason: revertReason(ctx, client, tx, receipt.BlockNumber),
+				Hash:   tx.Hash(),
+				Err:    fmt.Errorf("transaction %s failed with status: %d", tx.Hash().Hex(), receipt.Status),
+			}
+		default:
+			ok, err := done(receipt)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				// 再取一次收据，防止在检查期间发生重组
+				again, err := client.TransactionReceipt(ctx, tx.Hash())
+				if err == nil && again.BlockHash == receipt.BlockHash {
+					return receipt, nil
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for transaction %s: %w", tx.Hash().Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// revertReason 在交易所在的区块上用 eth_call 重放 tx 的调用，取得已上链失败交易的回滚原因。
+// 无法恢复发送方、重放成功（失败依赖区块内之前的交易）或不是回滚（如 gas 耗尽）时返回空字符串
+func revertReason(ctx context.Context, client chain.Client, tx *types.Transaction, block *big.Int) string {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return ""
+	}
+	_, err = client.CallContract(ctx, ethereum.CallMsg{
+		From: from, To: tx.To(), Gas: tx.Gas(), Value: tx.Value(), Data: tx.Data(), AccessList: tx.AccessList(),
+	}, block)
+	var revert *RevertError
+	if errors.As(Classify(err), &revert) {
+		return revert.Reason
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package ethtx
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestWaitConfirmed(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	backend, w := newTestWallet(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := SendETH(ctx, backend.Client(), w, common.HexToAddress("0xdead"), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := WaitConfirmed(ctx, backend.Client(), tx, 3)
+		done <- err
+	}()
+
+	// 打包后还需要再出两个区块
+	for i := 0; i < 2; i++ {
+		backend.Commit()
+		select {
+		case err := <-done:
+			t.Fatalf("WaitConfirmed returned after %d blocks: %v", i+1, err)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	backend.Commit()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WaitConfirmed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitConfirmed did not return after 3 blocks")
+	}
+}
+
+func TestWaitFinalized(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	backend, w := newTestWallet(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := SendETH(ctx, backend.Client(), w, common.HexToAddress("0xdead"), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	// 模拟后端把最新区块标记为 safe，每 32 个区块标记一次 finalized
+	receipt, err := WaitFinalized(ctx, backend.Client(), tx, rpc.SafeBlockNumber)
+	if err != nil {
+		t.Fatalf("WaitFinalized(safe): %v", err)
+	}
+	if receipt.BlockNumber.Uint64() != 1 {
+		t.Errorf("receipt block = %d, want 1", receipt.BlockNumber)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := WaitFinalized(ctx, backend.Client(), tx, rpc.FinalizedBlockNumber)
+		done <- err
+	}()
+	for i := 2; i < 32; i++ {
+		backend.Commit()
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("WaitFinalized(finalized) returned before block 32: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	backend.Commit()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WaitFinalized(finalized): %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitFinalized(finalized) did not return after block 32")
+	}
+
+	if _, err := WaitFinalized(ctx, backend.Client(), tx, rpc.LatestBlockNumber); err == nil {
+		t.Error("WaitFinalized(latest) succeeded, want error")
+	}
+}
@@ -0,0 +1,296 @@
+// Package explorer 通过区块浏览器的 Etherscan 兼容 API（module=account）查询地址的交易历史：普通交易、
+// 内部交易（合约调用中转出的原生代币）和 ERC-20 代币转账。节点的 JSON-RPC 没有按地址查询交易的接口，
+// 这类数据只能从索引服务获得。Etherscan 使用 V2 API（一个 API key 覆盖所有链，通过 chainid 参数选择链），
+// Blockscout 提供同样格式的 /api 端点，不需要 API key。
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Kind 是交易历史的类别，值与命令行的 -kinds 参数相同
+type Kind string
+
+const (
+	KindNormal   Kind = "normal"   // 地址发送或接收的交易（action=txlist）
+	KindInternal Kind = "internal" // 合约执行中的原生代币转账（action=txlistinternal）
+	KindToken    Kind = "token"    // ERC-20 Transfer 事件（action=tokentx）
+)
+
+// Kinds 是所有类别，按 History 默认查询的顺序
+var Kinds = []Kind{KindNormal, KindInternal, KindToken}
+
+var actions = map[Kind]string{KindNormal: "txlist", KindInternal: "txlistinternal", KindToken: "tokentx"}
+
+// ParseKinds 解析逗号分隔的类别列表，空字符串表示全部
+func ParseKinds(s string) ([]Kind, error) {
+	if strings.TrimSpace(s) == "" {
+		return Kinds, nil
+	}
+	var kinds []Kind
+	for _, k := range strings.Split(s, ",") {
+		kind := Kind(strings.TrimSpace(k))
+		if _, ok := actions[kind]; !ok {
+			return nil, fmt.Errorf("invalid history kind %q, want normal, internal or token", k)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+// Transfer 是历史中的一条记录。普通交易的 Value 是交易金额（可能为 0 的合约调用），
+// 代币转账的 Value 按 Token.Decimals 位小数计
+type Transfer struct {
+	Kind   Kind
+	Hash   common.Hash
+	Block  uint64
+	Time   time.Time
+	From   common.Address
+	To     *common.Address // 部署合约的交易为空，此时 Contract 为新合约地址
+	Value  *big.Int
+	Failed bool
+	Method string // 普通交易调用的方法，如 "transfer(address _to, uint256 _value)"，没有时为空
+
+	// 以下只在普通交易中有
+	Nonce    uint64
+	GasUsed  uint64
+	GasPrice *big.Int
+
+	Contract common.Address // 代币合约或新部署的合约
+	Token    *Token         // 代币转账的代币信息
+}
+
+// Token 是代币转账记录中附带的代币元数据
+type Token struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// Client 是 Etherscan 兼容 API 的客户端
+type Client struct {
+	APIBase string // 如 https://api.etherscan.io/v2/api 或 https://eth.blockscout.com/api
+	APIKey  string // Blockscout 可以为空
+	ChainID uint64 // Etherscan V2 的 chainid 参数，为 0 时不发送（Blockscout 每条链有自己的端点）
+	HTTP    *http.Client
+}
+
+// NewEtherscan 返回查询 chainID 上数据的 Etherscan V2 客户端
+func NewEtherscan(apiKey string, chainID uint64) *Client {
+	return &Client{APIBase: "https://api.etherscan.io/v2/api", APIKey: apiKey, ChainID: chainID}
+}
+
+// NewBlockscout 返回使用某条链的 Blockscout 实例的客户端，apiBase 如 https://eth-sepolia.blockscout.com/api
+func NewBlockscout(apiBase string) *Client {
+	return &Client{APIBase: apiBase}
+}
+
+// Options 是分页和区块范围，零值查询最近的 DefaultLimit 条
+type Options struct {
+	Page       int    // 从 1 开始
+	Limit      int    // 每页条数（API 的 offset 参数），Etherscan 最多 10000
+	StartBlock uint64 // 为 0 时从创世区块开始
+	EndBlock   uint64 // 为 0 时到最新区块
+	Ascending  bool   // 默认最新的在前
+}
+
+// DefaultLimit 是 Options.Limit 为 0 时每类查询的条数
+const DefaultLimit = 25
+
+// APIError 是 API 以 status "0" 返回的错误，如无效的 API key 或超出调用频率
+type APIError struct {
+	Message string
+	Result  string
+}
+
+func (e *APIError) Error() string {
+	if e.Result == "" {
+		return "explorer API: " + e.Message
+	}
+	return "explorer API: " + e.Message + ": " + e.Result
+}
+
+// History 依次查询 kinds 中各类记录，合并后按区块排序（同一区块内保持查询顺序）
+func (c *Client) History(ctx context.Context, addr common.Address, kinds []Kind, opts Options) ([]Transfer, error) {
+	var all []Transfer
+	for _, kind := range kinds {
+		transfers, err := c.List(ctx, kind, addr, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s transactions: %w", kind, err)
+		}
+		all = append(all, transfers...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if opts.Ascending {
+			return all[i].Block < all[j].Block
+		}
+		return all[i].Block > all[j].Block
+	})
+	return all, nil
+}
+
+// List 查询地址的一类记录
+func (c *Client) List(ctx context.Context, kind Kind, addr common.Address, opts Options) ([]Transfer, error) {
+	action, ok := actions[kind]
+	if !ok {
+		return nil, fmt.Errorf("invalid history kind %q", kind)
+	}
+	q := url.Values{"module": {"account"}, "action": {action}, "address": {addr.Hex()}}
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.Limit == 0 {
+		opts.Limit = DefaultLimit
+	}
+	q.Set("page", strconv.Itoa(opts.Page))
+	q.Set("offset", strconv.Itoa(opts.Limit))
+	q.Set("startblock", strconv.FormatUint(opts.StartBlock, 10))
+	if opts.EndBlock != 0 {
+		q.Set("endblock", strconv.FormatUint(opts.EndBlock, 10))
+	} else {
+		q.Set("endblock", "99999999999")
+	}
+	q.Set("sort", "desc")
+	if opts.Ascending {
+		q.Set("sort", "asc")
+	}
+	if c.ChainID != 0 {
+		q.Set("chainid", strconv.FormatUint(c.ChainID, 10))
+	}
+	if c.APIKey != "" {
+		q.Set("apikey", c.APIKey)
+	}
+
+	var rows []row
+	if err := c.get(ctx, q, &rows); err != nil {
+		return nil, err
+	}
+	transfers := make([]Transfer, 0, len(rows))
+	for _, r := range rows {
+		t, err := r.transfer(kind)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, nil
+}
+
+// get 发送查询并把 result 解码到 out。status 为 "0" 时 "No transactions found" 是空结果，其他为 APIError
+func (c *Client) get(ctx context.Context, q url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.APIBase+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// 错误信息中的 URL 带有 API key
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("explorer API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("explorer API: HTTP %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	var body struct {
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("explorer API: %w", err)
+	}
+	if body.Status != "1" {
+		if strings.HasPrefix(body.Message, "No ") {
+			return nil
+		}
+		var result string
+		json.Unmarshal(body.Result, &result)
+		return &APIError{Message: body.Message, Result: result}
+	}
+	return json.Unmarshal(body.Result, out)
+}
+
+// row 是 API 返回的一条记录，所有数字都是十进制字符串
+type row struct {
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	Nonce           string `json:"nonce"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	GasUsed         string `json:"gasUsed"`
+	GasPrice        string `json:"gasPrice"`
+	IsError         string `json:"isError"`
+	ContractAddress string `json:"contractAddress"`
+	FunctionName    string `json:"functionName"`
+	TokenName       string `json:"tokenName"`
+	TokenSymbol     string `json:"tokenSymbol"`
+	TokenDecimal    string `json:"tokenDecimal"`
+}
+
+func (r row) transfer(kind Kind) (Transfer, error) {
+	block, err := strconv.ParseUint(r.BlockNumber, 10, 64)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("invalid block number %q", r.BlockNumber)
+	}
+	ts, err := strconv.ParseInt(r.TimeStamp, 10, 64)
+	if err != nil {
+		return Transfer{}, fmt.Errorf("invalid timestamp %q", r.TimeStamp)
+	}
+	value, ok := new(big.Int).SetString(r.Value, 10)
+	if !ok {
+		return Transfer{}, fmt.Errorf("invalid value %q", r.Value)
+	}
+	t := Transfer{
+		Kind:     kind,
+		Hash:     common.HexToHash(r.Hash),
+		Block:    block,
+		Time:     time.Unix(ts, 0),
+		From:     common.HexToAddress(r.From),
+		Value:    value,
+		Failed:   r.IsError == "1",
+		Method:   r.FunctionName,
+		Contract: common.HexToAddress(r.ContractAddress),
+	}
+	if r.To != "" {
+		to := common.HexToAddress(r.To)
+		t.To = &to
+	}
+	if kind == KindNormal {
+		t.Nonce, _ = strconv.ParseUint(r.Nonce, 10, 64)
+		t.GasUsed, _ = strconv.ParseUint(r.GasUsed, 10, 64)
+		t.GasPrice, _ = new(big.Int).SetString(r.GasPrice, 10)
+	}
+	if kind == KindToken {
+		decimals, err := strconv.Atoi(r.TokenDecimal)
+		if err != nil {
+			return Transfer{}, fmt.Errorf("invalid token decimals %q", r.TokenDecimal)
+		}
+		t.Token = &Token{Name: r.TokenName, Symbol: r.TokenSymbol, Decimals: decimals}
+	}
+	return t, nil
+}
@@ -0,0 +1,89 @@
+package explorer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var addr = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+// responses 是 Etherscan 对各 action 的示例响应
+var responses = map[string]string{
+	"txlist": `{"status":"1","message":"OK","result":[
+		{"blockNumber":"120","timeStamp":"1700000000","hash":"0x01","nonce":"7","from":"0x00000000000000000000000000000000000000aa",
+		 "to":"0x00000000000000000000000000000000000000bb","value":"1000000000000000","gas":"21000","gasPrice":"2000000000",
+		 "isError":"0","contractAddress":"","gasUsed":"21000","functionName":""},
+		{"blockNumber":"100","timeStamp":"1699990000","hash":"0x02","nonce":"6","from":"0x00000000000000000000000000000000000000aa",
+		 "to":"","value":"0","gasPrice":"1000000000","isError":"1","contractAddress":"0x00000000000000000000000000000000000000cc","gasUsed":"90000"}]}`,
+	"txlistinternal": `{"status":"0","message":"No transactions found","result":[]}`,
+	"tokentx": `{"status":"1","message":"OK","result":[
+		{"blockNumber":"110","timeStamp":"1699995000","hash":"0x03","from":"0x00000000000000000000000000000000000000dd",
+		 "to":"0x00000000000000000000000000000000000000aa","value":"12500000","contractAddress":"0x00000000000000000000000000000000000000ee",
+		 "tokenName":"USD Coin","tokenSymbol":"USDC","tokenDecimal":"6"}]}`,
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("apikey") == "bad" {
+			io.WriteString(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+			return
+		}
+		if q.Get("module") != "account" || q.Get("address") != addr.Hex() || q.Get("chainid") != "11155111" ||
+			q.Get("apikey") != "key" || q.Get("offset") != "25" || q.Get("sort") != "desc" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		io.WriteString(w, responses[q.Get("action")])
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHistory(t *testing.T) {
+	srv := newServer(t)
+	c := &Client{APIBase: srv.URL, APIKey: "key", ChainID: 11155111}
+	got, err := c.History(context.Background(), addr, Kinds, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].Block != 120 || got[1].Block != 110 || got[2].Block != 100 {
+		t.Fatalf("History = %+v, want 3 records newest first", got)
+	}
+	normal, token, deploy := got[0], got[1], got[2]
+	if normal.Kind != KindNormal || normal.Nonce != 7 || normal.Value.String() != "1000000000000000" ||
+		normal.GasUsed != 21000 || normal.GasPrice.Int64() != 2e9 || normal.Failed || *normal.To != common.HexToAddress("0xbb") {
+		t.Errorf("normal transaction = %+v", normal)
+	}
+	if token.Kind != KindToken || token.Token == nil || *token.Token != (Token{"USD Coin", "USDC", 6}) ||
+		token.Value.Int64() != 12_500_000 || token.Contract != common.HexToAddress("0xee") {
+		t.Errorf("token transfer = %+v", token)
+	}
+	if deploy.To != nil || !deploy.Failed || deploy.Contract != common.HexToAddress("0xcc") || deploy.Time.Unix() != 1699990000 {
+		t.Errorf("failed deployment = %+v", deploy)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	srv := newServer(t)
+	c := &Client{APIBase: srv.URL, APIKey: "bad"}
+	_, err := c.List(context.Background(), KindNormal, addr, Options{})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Result != "Invalid API Key" {
+		t.Errorf("List with a bad key = %v, want APIError", err)
+	}
+}
+
+func TestParseKinds(t *testing.T) {
+	if kinds, err := ParseKinds("token, normal"); err != nil || len(kinds) != 2 || kinds[0] != KindToken {
+		t.Errorf("ParseKinds = %v, %v", kinds, err)
+	}
+	if _, err := ParseKinds("nft"); err == nil {
+		t.Error("ParseKinds accepted nft")
+	}
+}
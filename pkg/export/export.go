@@ -0,0 +1,232 @@
+// Package export 把区块和交易整理成规范化的行，写成 CSV 或 JSON Lines，供 pandas、Excel 等工具分析。
+// 两种格式的列相同：CSV 第一行是列名，JSON Lines 每行一个按列顺序输出的对象。wei 数量写成十进制字符串，
+// 避免 JSON 数字和电子表格丢失精度；时间是 UTC 的 RFC 3339 字符串。
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Format 是输出格式
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+)
+
+// ParseFormat 解析格式名，s 为空时按文件扩展名推断（.jsonl/.ndjson 为 JSON Lines，其他为 CSV）
+func ParseFormat(s, path string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "csv":
+		return CSV, nil
+	case "jsonl", "ndjson", "json":
+		return JSONL, nil
+	case "":
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".jsonl", ".ndjson", ".json":
+			return JSONL, nil
+		}
+		return CSV, nil
+	}
+	return "", fmt.Errorf("invalid export format %q, want csv or jsonl", s)
+}
+
+// Writer 按固定的列写出行
+type Writer struct {
+	columns []string
+	buf     *bufio.Writer
+	csv     *csv.Writer
+	rows    int
+}
+
+// NewWriter 创建写到 w 的 Writer，CSV 格式立即写出列名
+func NewWriter(w io.Writer, format Format, columns []string) (*Writer, error) {
+	buf := bufio.NewWriter(w)
+	out := &Writer{columns: columns, buf: buf}
+	switch format {
+	case CSV:
+		out.csv = csv.NewWriter(buf)
+		if err := out.csv.Write(columns); err != nil {
+			return nil, err
+		}
+	case JSONL:
+	default:
+		return nil, fmt.Errorf("invalid export format %q", format)
+	}
+	return out, nil
+}
+
+// Write 写出一行，values 与列一一对应。值可以是 string、uint64、int、bool、*big.Int、time.Time、
+// common.Address（校验和格式）、*common.Address、common.Hash 或 nil（CSV 中为空，JSON 中为 null）
+func (w *Writer) Write(values []any) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("row has %d values for %d columns", len(values), len(w.columns))
+	}
+	w.rows++
+	if w.csv != nil {
+		record := make([]string, len(values))
+		for i, v := range values {
+			record[i] = text(v)
+		}
+		return w.csv.Write(record)
+	}
+	w.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			w.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(w.columns[i])
+		w.buf.Write(key)
+		w.buf.WriteByte(':')
+		data, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return fmt.Errorf("column %s: %w", w.columns[i], err)
+		}
+		w.buf.Write(data)
+	}
+	_, err := w.buf.WriteString("}\n")
+	return err
+}
+
+// Rows 返回已写出的行数（不含 CSV 的列名）
+func (w *Writer) Rows() int { return w.rows }
+
+// Flush 把缓冲的数据写到底层的 io.Writer
+func (w *Writer) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return w.buf.Flush()
+}
+
+func text(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *big.Int:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *common.Address:
+		if v == nil {
+			return ""
+		}
+		return v.Hex()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	}
+	return fmt.Sprint(v)
+}
+
+// jsonValue 把大整数转成十进制字符串，其他值交给 encoding/json
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil
+		}
+		return v.String()
+	case *common.Address:
+		if v == nil {
+			return nil
+		}
+		return v.Hex()
+	case common.Address, common.Hash, time.Time:
+		return text(v)
+	}
+	return v
+}
+
+// BlockColumns 是 BlockRow 的列
+var BlockColumns = []string{
+	"number", "hash", "parent_hash", "timestamp", "miner", "tx_count",
+	"gas_used", "gas_limit", "base_fee_per_gas", "fee_burned",
+}
+
+// BlockRow 返回区块的一行。txCount 为 -1 时表示只有区块头，交易数为空。
+// fee_burned 是 EIP-1559 销毁的费用 base fee × gas used，伦敦升级之前的区块为空
+func BlockRow(h *types.Header, txCount int) []any {
+	var count any
+	if txCount >= 0 {
+		count = txCount
+	}
+	var burned any
+	if h.BaseFee != nil {
+		burned = new(big.Int).Mul(h.BaseFee, new(big.Int).SetUint64(h.GasUsed))
+	}
+	return []any{
+		h.Number.Uint64(), h.Hash(), h.ParentHash, time.Unix(int64(h.Time), 0), h.Coinbase, count,
+		h.GasUsed, h.GasLimit, h.BaseFee, burned,
+	}
+}
+
+// TxColumns 是 TxRows 的列
+var TxColumns = []string{
+	"block_number", "block_hash", "timestamp", "index", "hash", "type", "from", "to", "value", "nonce",
+	"gas_limit", "gas_used", "effective_gas_price", "fee", "fee_burned", "status", "contract_address",
+}
+
+// TxRows 返回区块中每笔交易的一行。receipts 与交易一一对应，为 nil 时 gas_used、费用和状态为空。
+// fee 是交易支付的 gas used × effective gas price，fee_burned 是其中销毁的 base fee 部分；
+// status 为 success 或 reverted。无法恢复发送方的交易 from 为空
+func TxRows(block *types.Block, receipts []*types.Receipt, signer types.Signer) [][]any {
+	txs := block.Transactions()
+	rows := make([][]any, len(txs))
+	for i, tx := range txs {
+		var from any
+		if addr, err := types.Sender(signer, tx); err == nil {
+			from = addr
+		}
+		var gasUsed, price, fee, burned, status, contract any
+		if receipts != nil && i < len(receipts) && receipts[i] != nil {
+			r := receipts[i]
+			gasUsed = r.GasUsed
+			used := new(big.Int).SetUint64(r.GasUsed)
+			if r.EffectiveGasPrice != nil {
+				price = r.EffectiveGasPrice
+				fee = new(big.Int).Mul(r.EffectiveGasPrice, used)
+			}
+			if base := block.BaseFee(); base != nil {
+				burned = new(big.Int).Mul(base, used)
+			}
+			status = "success"
+			if r.Status == types.ReceiptStatusFailed {
+				status = "reverted"
+			}
+			if r.ContractAddress != (common.Address{}) {
+				contract = r.ContractAddress
+			}
+		}
+		rows[i] = []any{
+			block.NumberU64(), block.Hash(), time.Unix(int64(block.Time()), 0), i, tx.Hash(), int(tx.Type()),
+			from, tx.To(), tx.Value(), tx.Nonce(),
+			tx.Gas(), gasUsed, price, fee, burned, status, contract,
+		}
+	}
+	return rows
+}
@@ -0,0 +1,137 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testBlock 返回 base fee 为 10 wei 的区块，含一笔成功的转账和一笔回滚的合约创建
+func testBlock(t *testing.T) (*types.Block, []*types.Receipt, types.Signer, common.Address) {
+	t.Helper()
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	transfer := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID: big.NewInt(1), Nonce: 7, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(20), Gas: 21000,
+		To: &to, Value: new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil),
+	})
+	create := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+		ChainID: big.NewInt(1), Nonce: 8, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(20), Gas: 100000,
+	})
+	header := &types.Header{
+		Number: big.NewInt(42), Time: 1700000000, GasUsed: 71000, GasLimit: 30000000,
+		BaseFee: big.NewInt(10), Difficulty: new(big.Int),
+	}
+	block := types.NewBlockWithHeader(header).WithBody(types.Body{Transactions: types.Transactions{transfer, create}})
+	receipts := []*types.Receipt{
+		{Status: types.ReceiptStatusSuccessful, GasUsed: 21000, EffectiveGasPrice: big.NewInt(12)},
+		{Status: types.ReceiptStatusFailed, GasUsed: 50000, EffectiveGasPrice: big.NewInt(12), ContractAddress: common.HexToAddress("0xbb")},
+	}
+	return block, receipts, signer, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestCSV(t *testing.T) {
+	block, receipts, signer, from := testBlock(t)
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, CSV, TxColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range TxRows(block, receipts, signer) {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(TxColumns, ",") {
+		t.Fatalf("records = %v", records)
+	}
+	get := func(row int, col string) string {
+		for i, c := range TxColumns {
+			if c == col {
+				return records[row][i]
+			}
+		}
+		t.Fatalf("no column %s", col)
+		return ""
+	}
+	for col, want := range map[string]string{
+		"block_number": "42", "timestamp": "2023-11-14T22:13:20Z", "from": from.Hex(),
+		"to": "0x00000000000000000000000000000000000000AA", "value": "100000000000000000000", "nonce": "7",
+		"fee": "252000", "fee_burned": "210000", "status": "success", "contract_address": "", "type": "2",
+	} {
+		if got := get(1, col); got != want {
+			t.Errorf("row 1 %s = %q, want %q", col, got, want)
+		}
+	}
+	if get(2, "to") != "" || get(2, "status") != "reverted" || get(2, "contract_address") == "" {
+		t.Errorf("contract creation row = %v", records[2])
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	block, _, signer, _ := testBlock(t)
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, JSONL, BlockColumns)
+	w.Write(BlockRow(block.Header(), len(block.Transactions())))
+	w.Write(BlockRow(&types.Header{Number: big.NewInt(1), Difficulty: new(big.Int)}, -1))
+	// 没有收据时费用和状态为 null
+	tw, _ := NewWriter(&buf, JSONL, TxColumns)
+	tw.Write(TxRows(block, nil, signer)[0])
+	w.Flush()
+	tw.Flush()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	// 列按顺序输出
+	if !strings.HasPrefix(lines[0], `{"number":42,"hash":"0x`) {
+		t.Errorf("line 0 = %s", lines[0])
+	}
+	var rows [3]map[string]any
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &rows[i]); err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+	}
+	if rows[0]["fee_burned"] != "710000" || rows[0]["tx_count"] != 2.0 || rows[0]["base_fee_per_gas"] != "10" {
+		t.Errorf("block row = %v", rows[0])
+	}
+	if rows[1]["tx_count"] != nil || rows[1]["fee_burned"] != nil {
+		t.Errorf("header-only pre-London row = %v", rows[1])
+	}
+	if rows[2]["status"] != nil || rows[2]["fee"] != nil || rows[2]["value"] != "100000000000000000000" {
+		t.Errorf("tx row without receipts = %v", rows[2])
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, c := range []struct {
+		s, path string
+		want    Format
+	}{
+		{"", "out.csv", CSV}, {"", "", CSV}, {"", "txs.jsonl", JSONL}, {"", "txs.NDJSON", JSONL}, {"jsonl", "x.csv", JSONL},
+	} {
+		if got, err := ParseFormat(c.s, c.path); err != nil || got != c.want {
+			t.Errorf("ParseFormat(%q, %q) = %q, %v, want %q", c.s, c.path, got, err, c.want)
+		}
+	}
+	if _, err := ParseFormat("xlsx", ""); err == nil {
+		t.Error("ParseFormat accepted xlsx")
+	}
+}
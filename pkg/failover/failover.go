@@ -0,0 +1,263 @@
+// Package failover 把同一条链的多个 HTTP JSON-RPC 端点组合成一个 ethclient。请求按配置顺序
+// 发往第一个健康的端点，遇到连接错误、超时、限流（HTTP 429 或 JSON-RPC -32005）或 5xx 错误时
+// 把该端点标记为不健康并立即改用下一个；后台定期探测不健康的端点，恢复后重新按顺序使用。
+// 故障切换在 HTTP 传输层完成，因此只支持 http(s) 端点，不支持 WebSocket 订阅。
+package failover
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// limitExceeded 是 EIP-1474 定义的 "limit exceeded" 错误码，提供商常用它表示限流
+const limitExceeded = -32005
+
+// Options 是故障切换的参数，零值使用默认值
+type Options struct {
+	Timeout       time.Duration     // 单个端点单次请求的超时，默认 10 秒
+	ProbeInterval time.Duration     // 探测不健康端点的间隔，默认 30 秒
+	Transport     http.RoundTripper // 实际发送请求的传输层，nil 表示 http.DefaultTransport
+
+	// OnFailure 在端点请求失败、即将切换到下一个端点时调用，可用于记录日志
+	OnFailure func(endpoint string, err error)
+}
+
+// Status 是一个端点的健康状态
+type Status struct {
+	Endpoint string // 端点的主机名，不含路径中可能带有的 API key
+	Healthy  bool
+	LastErr  error
+}
+
+type endpoint struct {
+	url     *url.URL
+	healthy bool
+	lastErr error
+}
+
+// Transport 是在多个端点之间故障切换的 http.RoundTripper
+type Transport struct {
+	opts      Options
+	endpoints []*endpoint
+
+	mu   sync.Mutex
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewTransport 为 urls 创建 Transport 并启动后台健康探测，用完后应调用 Close
+func NewTransport(urls []string, opts Options) (*Transport, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no RPC endpoints")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.ProbeInterval == 0 {
+		opts.ProbeInterval = 30 * time.Second
+	}
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+	t := &Transport{opts: opts, stop: make(chan struct{})}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RPC endpoint: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("failover supports only http(s) endpoints, got %s://%s", u.Scheme, u.Host)
+		}
+		t.endpoints = append(t.endpoints, &endpoint{url: u, healthy: true})
+	}
+	go t.probeLoop()
+	return t, nil
+}
+
+// Dial 创建在 urls 之间故障切换的 ethclient，关闭客户端后应调用返回的 Transport 的 Close
+func Dial(ctx context.Context, urls []string, opts Options) (*ethclient.Client, *Transport, error) {
+	t, err := NewTransport(urls, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := rpc.DialOptions(ctx, urls[0], rpc.WithHTTPClient(&http.Client{Transport: t}))
+	if err != nil {
+		t.Close()
+		return nil, nil, err
+	}
+	return ethclient.NewClient(client), t, nil
+}
+
+// Close 停止后台健康探测
+func (t *Transport) Close() {
+	t.once.Do(func() { close(t.stop) })
+}
+
+// Status 返回所有端点的健康状态，顺序与配置相同
+func (t *Transport) Status() []Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Status, len(t.endpoints))
+	for i, ep := range t.endpoints {
+		out[i] = Status{Endpoint: ep.url.Host, Healthy: ep.healthy, LastErr: ep.lastErr}
+	}
+	return out
+}
+
+// RoundTrip 实现 http.RoundTripper：依次尝试健康的端点，全部失败后再尝试不健康的端点
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	var lastErr error
+	for _, ep := range t.order() {
+		resp, err := t.try(req, ep, body)
+		if err == nil {
+			t.mark(ep, nil)
+			return resp, nil
+		}
+		// 调用方取消或超时，不再尝试其他端点
+		if req.Context().Err() != nil {
+			return nil, req.Context().Err()
+		}
+		t.mark(ep, err)
+		if t.opts.OnFailure != nil {
+			t.opts.OnFailure(ep.url.Host, err)
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("all RPC endpoints failed, last error: %w", lastErr)
+}
+
+// order 返回本次请求尝试端点的顺序：先按配置顺序的健康端点，再是不健康的端点
+func (t *Transport) order() []*endpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var healthy, unhealthy []*endpoint
+	for _, ep := range t.endpoints {
+		if ep.healthy {
+			healthy = append(healthy, ep)
+		} else {
+			unhealthy = append(unhealthy, ep)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (t *Transport) mark(ep *endpoint, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ep.healthy, ep.lastErr = err == nil, err
+}
+
+// try 把请求发往 ep，读取完整的响应；需要切换端点的失败以错误返回
+func (t *Transport) try(req *http.Request, ep *endpoint, body []byte) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.opts.Timeout)
+	defer cancel()
+	r := req.Clone(ctx)
+	r.URL, r.Host = ep.url, ep.url.Host
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+
+	resp, err := t.opts.Transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	// 读完响应后 ctx 才能取消，同时可以检查 JSON-RPC 层的限流错误
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, fmt.Errorf("HTTP %s", resp.Status)
+	}
+	if rateLimited(data) {
+		return nil, errors.New("rate limited (JSON-RPC error -32005)")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	resp.Request = req
+	return resp, nil
+}
+
+// rateLimited 报告 JSON-RPC 响应（单个或批量）中是否有限流错误
+func rateLimited(data []byte) bool {
+	type message struct {
+		Error *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	var batch []message
+	if err := json.Unmarshal(data, &batch); err != nil {
+		var single message
+		if json.Unmarshal(data, &single) != nil {
+			return false
+		}
+		batch = []message{single}
+	}
+	for _, m := range batch {
+		if m.Error != nil && m.Error.Code == limitExceeded {
+			return true
+		}
+	}
+	return false
+}
+
+// probeLoop 每隔 ProbeInterval 用 eth_blockNumber 探测不健康的端点
+func (t *Transport) probeLoop() {
+	ticker := time.NewTicker(t.opts.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+		}
+		for _, ep := range t.order() {
+			t.mu.Lock()
+			healthy := ep.healthy
+			t.mu.Unlock()
+			if !healthy {
+				t.probe(ep)
+			}
+		}
+	}
+}
+
+func (t *Transport) probe(ep *endpoint) {
+	const body = `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`
+	req, err := http.NewRequest(http.MethodPost, ep.url.String(), nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.try(req, ep, []byte(body))
+	if err == nil {
+		var msg struct {
+			Result string `json:"result"`
+		}
+		if err = json.NewDecoder(resp.Body).Decode(&msg); err == nil && msg.Result == "" {
+			err = errors.New("eth_blockNumber returned no result")
+		}
+	}
+	t.mark(ep, err)
+}
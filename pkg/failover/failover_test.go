@@ -0,0 +1,133 @@
+package failover
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type fakeEth struct{}
+
+func (fakeEth) ChainId() *hexutil.Big       { return (*hexutil.Big)(big.NewInt(11155111)) }
+func (fakeEth) BlockNumber() hexutil.Uint64 { return 42 }
+
+// 端点的故障模式
+const (
+	up int32 = iota
+	down
+	limited
+)
+
+// node 是一个可以切换故障模式的 JSON-RPC 端点，统计收到的请求数
+type node struct {
+	*httptest.Server
+	mode atomic.Int32
+	hits atomic.Int32
+}
+
+func newNode(t *testing.T) *node {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", fakeEth{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	n := new(node)
+	n.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n.hits.Add(1)
+		switch n.mode.Load() {
+		case down:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case limited:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit exceeded"}}`))
+		default:
+			server.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(n.Close)
+	return n
+}
+
+func TestFailover(t *testing.T) {
+	for _, mode := range []int32{down, limited} {
+		primary, backup := newNode(t), newNode(t)
+		primary.mode.Store(mode)
+		var failures atomic.Int32
+		client, transport, err := Dial(context.Background(), []string{primary.URL, backup.URL}, Options{
+			ProbeInterval: time.Hour,
+			OnFailure:     func(string, error) { failures.Add(1) },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer transport.Close()
+
+		for range 3 {
+			if id, err := client.ChainID(context.Background()); err != nil || id.Int64() != 11155111 {
+				t.Fatalf("mode %d: ChainID = %v, %v", mode, id, err)
+			}
+		}
+		// 主端点失败一次后被标记为不健康，之后的请求直接发往备用端点
+		if primary.hits.Load() != 1 || backup.hits.Load() != 3 || failures.Load() != 1 {
+			t.Errorf("mode %d: primary hits %d, backup hits %d, failures %d, want 1/3/1", mode, primary.hits.Load(), backup.hits.Load(), failures.Load())
+		}
+		if status := transport.Status(); status[0].Healthy || !status[1].Healthy || status[0].LastErr == nil {
+			t.Errorf("mode %d: status = %+v", mode, status)
+		}
+	}
+}
+
+func TestProbeRecovery(t *testing.T) {
+	primary, backup := newNode(t), newNode(t)
+	primary.mode.Store(down)
+	client, transport, err := Dial(context.Background(), []string{primary.URL, backup.URL}, Options{ProbeInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer transport.Close()
+	if _, err := client.BlockNumber(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	primary.mode.Store(up)
+	deadline := time.Now().Add(5 * time.Second)
+	for !transport.Status()[0].Healthy {
+		if time.Now().After(deadline) {
+			t.Fatal("primary not marked healthy after it recovered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// 恢复后请求重新发往主端点
+	before := backup.hits.Load()
+	if _, err := client.BlockNumber(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if backup.hits.Load() != before {
+		t.Error("request went to the backup after the primary recovered")
+	}
+}
+
+func TestAllEndpointsDown(t *testing.T) {
+	a, b := newNode(t), newNode(t)
+	a.mode.Store(down)
+	b.mode.Store(limited)
+	client, transport, err := Dial(context.Background(), []string{a.URL, b.URL}, Options{ProbeInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer transport.Close()
+	if _, err := client.ChainID(context.Background()); err == nil {
+		t.Fatal("ChainID succeeded with all endpoints down")
+	}
+	if _, _, err := Dial(context.Background(), []string{"ws://localhost:8546"}, Options{}); err == nil {
+		t.Error("WebSocket endpoint accepted")
+	}
+}
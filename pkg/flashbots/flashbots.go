@@ -0,0 +1,239 @@
+// Package flashbots 把交易发送到 Flashbots Protect 或私有中继，而不是公开的交易池。
+// 中继的请求需要用单独的身份私钥签名（X-Flashbots-Signature），这个身份只用于中继的信誉统计，
+// 不需要持有资金，也不应该是发送交易的账户
+package flashbots
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SignatureHeader 是中继验证请求身份的 HTTP 头
+const SignatureHeader = "X-Flashbots-Signature"
+
+// ErrUnsupportedChain 表示没有链的默认端点，需要显式指定 URL
+var ErrUnsupportedChain = errors.New("flashbots does not support this chain")
+
+// protectURLs 和 relayURLs 是各链的 Flashbots Protect RPC 和中继地址
+var (
+	protectURLs = map[uint64]string{
+		1:        "https://rpc.flashbots.net",
+		11155111: "https://rpc-sepolia.flashbots.net",
+	}
+	relayURLs = map[uint64]string{
+		1:        "https://relay.flashbots.net",
+		11155111: "https://relay-sepolia.flashbots.net",
+	}
+)
+
+// ProtectURL 返回链的 Flashbots Protect RPC 地址，不支持的链返回空字符串
+func ProtectURL(chainID uint64) string {
+	return protectURLs[chainID]
+}
+
+// RelayURL 返回链的 Flashbots 中继地址，不支持的链返回空字符串
+func RelayURL(chainID uint64) string {
+	return relayURLs[chainID]
+}
+
+// Sender 把已签名的交易私下提交，返回交易哈希
+type Sender interface {
+	SendRawTransaction(ctx context.Context, raw []byte) (common.Hash, error)
+}
+
+// Protect 通过 Flashbots Protect RPC 发送交易：它接受普通的 eth_sendRawTransaction，不需要签名请求，
+// 交易只转给区块构建者，失败的交易不会上链
+type Protect struct {
+	URL  string // 为空时按交易的链 ID 使用 ProtectURL
+	HTTP *http.Client
+}
+
+// SendRawTransaction 实现 Sender
+func (p *Protect) SendRawTransaction(ctx context.Context, raw []byte) (common.Hash, error) {
+	url, err := endpoint(p.URL, raw, ProtectURL)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var hash common.Hash
+	err = call(ctx, p.HTTP, url, nil, "eth_sendRawTransaction", []interface{}{hexutil.Bytes(raw)}, &hash)
+	return hash, err
+}
+
+// Relay 是 Flashbots 中继或兼容的构建者端点的客户端，每个请求都用 Identity 签名
+type Relay struct {
+	URL      string // 为空时按交易的链 ID 使用 RelayURL
+	Identity *ecdsa.PrivateKey
+	HTTP     *http.Client
+}
+
+// SendRawTransaction 实现 Sender，用 eth_sendPrivateTransaction 提交，中继在之后的 25 个区块内尝试打包
+func (r *Relay) SendRawTransaction(ctx context.Context, raw []byte) (common.Hash, error) {
+	return r.SendPrivateTransaction(ctx, raw, 0)
+}
+
+// SendPrivateTransaction 用 eth_sendPrivateTransaction 提交交易，maxBlock 不为 0 时中继在该区块之后放弃
+func (r *Relay) SendPrivateTransaction(ctx context.Context, raw []byte, maxBlock uint64) (common.Hash, error) {
+	url, err := endpoint(r.URL, raw, RelayURL)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	param := struct {
+		Tx             hexutil.Bytes   `json:"tx"`
+		MaxBlockNumber *hexutil.Uint64 `json:"maxBlockNumber,omitempty"`
+	}{Tx: raw}
+	if maxBlock != 0 {
+		param.MaxBlockNumber = (*hexutil.Uint64)(&maxBlock)
+	}
+	var hash common.Hash
+	err = call(ctx, r.HTTP, url, r.Identity, "eth_sendPrivateTransaction", []interface{}{param}, &hash)
+	return hash, err
+}
+
+// CancelPrivateTransaction 撤回还没有打包的私有交易，返回中继是否撤回成功
+func (r *Relay) CancelPrivateTransaction(ctx context.Context, chainID uint64, hash common.Hash) (bool, error) {
+	url := r.URL
+	if url == "" {
+		if url = RelayURL(chainID); url == "" {
+			return false, fmt.Errorf("%w: chain %d", ErrUnsupportedChain, chainID)
+		}
+	}
+	param := struct {
+		TxHash common.Hash `json:"txHash"`
+	}{hash}
+	var ok bool
+	err := call(ctx, r.HTTP, url, r.Identity, "eth_cancelPrivateTransaction", []interface{}{param}, &ok)
+	return ok, err
+}
+
+// SendBundle 用 eth_sendBundle 提交按顺序全部打包在 block 中、否则都不打包的一组交易，返回 bundle 哈希。
+// 交易必须属于同一条链
+func (r *Relay) SendBundle(ctx context.Context, txs [][]byte, block uint64) (common.Hash, error) {
+	if len(txs) == 0 {
+		return common.Hash{}, errors.New("bundle has no transactions")
+	}
+	url, err := endpoint(r.URL, txs[0], RelayURL)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	param := struct {
+		Txs         []hexutil.Bytes `json:"txs"`
+		BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	}{BlockNumber: hexutil.Uint64(block)}
+	for _, raw := range txs {
+		param.Txs = append(param.Txs, raw)
+	}
+	var result struct {
+		BundleHash common.Hash `json:"bundleHash"`
+	}
+	if err := call(ctx, r.HTTP, url, r.Identity, "eth_sendBundle", []interface{}{param}, &result); err != nil {
+		return common.Hash{}, err
+	}
+	return result.BundleHash, nil
+}
+
+// Sign 返回 body 的 X-Flashbots-Signature 值：身份地址和对 keccak256(body) 十六进制字符串的 personal_sign 签名
+func Sign(identity *ecdsa.PrivateKey, body []byte) (string, error) {
+	digest := hexutil.Encode(crypto.Keccak256(body))
+	sig, err := crypto.Sign(accounts.TextHash([]byte(digest)), identity)
+	if err != nil {
+		return "", fmt.Errorf("sign flashbots request: %w", err)
+	}
+	sig[64] += 27
+	return crypto.PubkeyToAddress(identity.PublicKey).Hex() + ":" + hexutil.Encode(sig), nil
+}
+
+// endpoint 返回 url，为空时按 raw 交易的链 ID 查找默认地址
+func endpoint(url string, raw []byte, byChain func(uint64) string) (string, error) {
+	if url != "" {
+		return url, nil
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return "", fmt.Errorf("decode transaction: %w", err)
+	}
+	id := tx.ChainId()
+	if !id.IsUint64() || byChain(id.Uint64()) == "" {
+		return "", fmt.Errorf("%w: chain %s", ErrUnsupportedChain, id)
+	}
+	return byChain(id.Uint64()), nil
+}
+
+// rpcError 是 JSON-RPC 错误
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// call 发送一个 JSON-RPC 请求，identity 不为 nil 时签名请求体
+func call(ctx context.Context, client *http.Client, url string, identity *ecdsa.PrivateKey, method string, params []interface{}, out interface{}) error {
+	body, err := json.Marshal(struct {
+		JSONRPC string        `json:"jsonrpc"`
+		ID      int           `json:"id"`
+		Method  string        `json:"method"`
+		Params  []interface{} `json:"params"`
+	}{"2.0", 1, method, params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if identity != nil {
+		sig, err := Sign(identity, body)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(SignatureHeader, sig)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	var msg struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("%s: %s: %s", method, resp.Status, strings.TrimSpace(string(data)))
+		}
+		return fmt.Errorf("%s: decode response: %w", method, err)
+	}
+	if msg.Error != nil {
+		return fmt.Errorf("%s: %w", method, msg.Error)
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: %s", method, resp.Status)
+	}
+	if err := json.Unmarshal(msg.Result, out); err != nil {
+		return fmt.Errorf("%s: decode result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package flashbots
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// verifySignature 按中继的规则检查请求的 X-Flashbots-Signature，返回签名的身份地址
+func verifySignature(t *testing.T, header string, body []byte) common.Address {
+	t.Helper()
+	addr, sigHex, ok := strings.Cut(header, ":")
+	if !ok {
+		t.Fatalf("malformed signature header %q", header)
+	}
+	sig, err := hexutil.Decode(sigHex)
+	if err != nil || len(sig) != 65 {
+		t.Fatalf("malformed signature %q", sigHex)
+	}
+	sig[64] -= 27
+	digest := hexutil.Encode(crypto.Keccak256(body))
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(digest)), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != common.HexToAddress(addr) {
+		t.Fatalf("signature is from %s, header claims %s", signer.Hex(), addr)
+	}
+	return common.HexToAddress(addr)
+}
+
+func signedTx(t *testing.T, chainID int64) (*types.Transaction, []byte) {
+	t.Helper()
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(chainID)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(chainID), Nonce: 3, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(30e9), Gas: 21000, To: &to, Value: big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := tx.MarshalBinary()
+	return tx, raw
+}
+
+func TestTransportRelay(t *testing.T) {
+	identity, _ := crypto.GenerateKey()
+	var got struct {
+		Method string `json:"method"`
+		Params []struct {
+			Tx hexutil.Bytes `json:"tx"`
+		} `json:"params"`
+	}
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if signer := verifySignature(t, r.Header.Get(SignatureHeader), body); signer != crypto.PubkeyToAddress(identity.PublicKey) {
+			t.Errorf("request signed by %s, want the identity", signer.Hex())
+		}
+		json.Unmarshal(body, &got)
+		tx := new(types.Transaction)
+		tx.UnmarshalBinary(got.Params[0].Tx)
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": tx.Hash()})
+	}))
+	defer relay.Close()
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m rpcRequest
+		json.NewDecoder(r.Body).Decode(&m)
+		if m.Method != "eth_chainId" {
+			t.Errorf("node received %s", m.Method)
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(m.ID) + `,"result":"0x1"}`))
+	}))
+	defer node.Close()
+
+	c, err := rpc.DialOptions(context.Background(), node.URL, rpc.WithHTTPClient(&http.Client{
+		Transport: Transport(&Relay{URL: relay.URL, Identity: identity}, nil),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ethclient.NewClient(c)
+	defer client.Close()
+	if id, err := client.ChainID(context.Background()); err != nil || id.Int64() != 1 {
+		t.Fatalf("ChainID = %v, %v", id, err)
+	}
+	tx, raw := signedTx(t, 1)
+	if err := client.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != "eth_sendPrivateTransaction" || len(got.Params) != 1 || !bytes.Equal(got.Params[0].Tx, raw) {
+		t.Fatalf("relay received %+v", got)
+	}
+}
+
+func TestRelayError(t *testing.T) {
+	identity, _ := crypto.GenerateKey()
+	relay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"invalid flashbots signature"}}`))
+	}))
+	defer relay.Close()
+	_, raw := signedTx(t, 1)
+	r := &Relay{URL: relay.URL, Identity: identity}
+	if _, err := r.SendBundle(context.Background(), [][]byte{raw}, 100); err == nil || !strings.Contains(err.Error(), "invalid flashbots signature") {
+		t.Fatalf("SendBundle error = %v", err)
+	}
+	// 没有 URL 时按交易的链 ID 选择端点，不支持的链不发送请求
+	_, raw = signedTx(t, 31337)
+	if _, err := (&Relay{Identity: identity}).SendRawTransaction(context.Background(), raw); err == nil || !strings.Contains(err.Error(), "chain 31337") {
+		t.Fatalf("SendRawTransaction error = %v", err)
+	}
+}
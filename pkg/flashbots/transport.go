@@ -0,0 +1,84 @@
+package flashbots
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Transport 返回把 eth_sendRawTransaction 交给 s 私下提交的 http.RoundTripper，其余请求照常发给 next，
+// 因此经过它的客户端发送的每笔交易都不会进入节点的公开交易池。
+// 含有 eth_sendRawTransaction 的批量请求返回错误而不是转发给节点。next 为 nil 时使用 http.DefaultTransport。
+func Transport(s Sender, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{sender: s, next: next}
+}
+
+type transport struct {
+	sender Sender
+	next   http.RoundTripper
+}
+
+// rpcRequest 是 JSON-RPC 请求中用到的字段
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	var batch []rpcRequest
+	if json.Unmarshal(body, &batch) == nil {
+		for _, m := range batch {
+			if m.Method == "eth_sendRawTransaction" {
+				return nil, errors.New("private transaction mode does not support eth_sendRawTransaction in a batch")
+			}
+		}
+		return t.next.RoundTrip(req)
+	}
+	var m rpcRequest
+	if json.Unmarshal(body, &m) != nil || m.Method != "eth_sendRawTransaction" {
+		return t.next.RoundTrip(req)
+	}
+
+	reply := map[string]interface{}{"jsonrpc": "2.0", "id": m.ID}
+	var raw hexutil.Bytes
+	if len(m.Params) == 0 || json.Unmarshal(m.Params[0], &raw) != nil {
+		reply["error"] = rpcError{Code: -32602, Message: "invalid raw transaction"}
+	} else if hash, err := t.sender.SendRawTransaction(req.Context(), raw); err != nil {
+		reply["error"] = rpcError{Code: -32000, Message: err.Error()}
+	} else {
+		reply["result"] = hash
+	}
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,28 @@
+package gateway
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// bearerToken 在每个调用的 authorization 元数据中携带 token
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity 返回 false：网关默认以明文 HTTP/2 监听本机地址，对外暴露时放在 TLS 代理之后
+func (bearerToken) RequireTransportSecurity() bool { return false }
+
+// Dial 以明文 HTTP/2 连接 target（如 127.0.0.1:9090）上的网关，每个调用携带 token。
+// opts 追加在默认选项之后，可以用 grpc.WithTransportCredentials 改为 TLS
+func Dial(target, token string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(bearerToken(token)),
+	}, opts...)
+	return grpc.NewClient(target, opts...)
+}
@@ -0,0 +1,334 @@
+// Package gateway 以 gRPC 服务（proto/gateway/v1/gateway.proto）提供与 pkg/api 相同的余额、代币余额、
+// 交易提交、合约调用和事件查询，外加推送交易确认进度的流式方法 WatchConfirmations，
+// 供其他服务把本项目作为以太坊网关嵌入。服务端和客户端都基于 grpc-go，Go 客户端用 Dial 连接。
+package gateway
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/address"
+	"github.com/local/go-eth-demo/pkg/api"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/decode"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/gateway/gatewaypb"
+	"github.com/local/go-eth-demo/pkg/units"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestTimeout 是一元调用访问节点的最长时间，与 HTTP API 相同
+const requestTimeout = 30 * time.Second
+
+// DefaultPollInterval 是 WatchConfirmations 查询收据和最新区块的默认间隔
+const DefaultPollInterval = 2 * time.Second
+
+// Server 是 Gateway 服务的实现，用 GRPCServer 创建注册了该服务的 grpc.Server
+type Server struct {
+	gatewaypb.UnimplementedGatewayServer
+	client chain.Client
+	token  string
+
+	// PollInterval 是 WatchConfirmations 的轮询间隔
+	PollInterval time.Duration
+	// OnRequest 在每个调用完成后调用（可选），method 为完整路径，用于日志和指标
+	OnRequest func(method string, code codes.Code, elapsed time.Duration)
+}
+
+var _ gatewaypb.GatewayServer = (*Server)(nil)
+
+// New 创建 Gateway 服务。token 不能为空，所有调用都必须在 authorization 元数据中携带该 token
+func New(client chain.Client, token string) (*Server, error) {
+	if token == "" {
+		return nil, errors.New("gateway token must not be empty")
+	}
+	return &Server{client: client, token: token, PollInterval: DefaultPollInterval}, nil
+}
+
+// GRPCServer 返回注册了 Gateway 服务的 grpc.Server。拦截器校验 bearer token、
+// 限制一元调用的时长（客户端的截止时间更短时以客户端为准，流式调用不受限制）并调用 OnRequest
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	)
+	srv := grpc.NewServer(opts...)
+	gatewaypb.RegisterGatewayServer(srv, s)
+	return srv
+}
+
+func (s *Server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer s.observe(info.FullMethod, time.Now(), &err)
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	return handler(ctx, req)
+}
+
+func (s *Server) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer s.observe(info.FullMethod, time.Now(), &err)
+	if err := s.authorize(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize 检查 authorization 元数据中的 bearer token
+func (s *Server) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if got, ok := strings.CutPrefix(v, "Bearer "); ok && subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+func (s *Server) observe(method string, start time.Time, err *error) {
+	if s.OnRequest != nil {
+		s.OnRequest(method, codeOf(*err), time.Since(start))
+	}
+}
+
+// codeOf 返回错误对应的状态码，与 grpc-go 发送给客户端的相同：context 的取消和超时对应 Canceled 和 DeadlineExceeded
+func codeOf(err error) codes.Code {
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+	return status.FromContextError(err).Code()
+}
+
+// GetBalance 返回地址的 ETH 余额
+func (s *Server) GetBalance(ctx context.Context, req *gatewaypb.GetBalanceRequest) (*gatewaypb.GetBalanceResponse, error) {
+	addr, err := parseAddress("address", req.Address)
+	if err != nil {
+		return nil, err
+	}
+	block, err := api.ParseBlock(req.Block)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	wei, err := s.client.BalanceAt(ctx, addr, block)
+	if err != nil {
+		return nil, unavailable("get balance", err)
+	}
+	return &gatewaypb.GetBalanceResponse{
+		Address: addr.Hex(),
+		Block:   blockString(block),
+		Wei:     wei.String(),
+		Eth:     units.FormatUnits(wei, 18),
+	}, nil
+}
+
+// GetTokenBalance 返回地址持有的 ERC-20 代币数量
+func (s *Server) GetTokenBalance(ctx context.Context, req *gatewaypb.GetTokenBalanceRequest) (*gatewaypb.GetTokenBalanceResponse, error) {
+	tokenAddr, err := parseAddress("token", req.Token)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := parseAddress("address", req.Address)
+	if err != nil {
+		return nil, err
+	}
+	token, err := erc20.Load(ctx, s.client, tokenAddr)
+	if err != nil {
+		return nil, unavailable("load token", err)
+	}
+	raw, err := token.BalanceOf(ctx, s.client, addr)
+	if err != nil {
+		return nil, unavailable("get token balance", err)
+	}
+	return &gatewaypb.GetTokenBalanceResponse{
+		Token:     tokenAddr.Hex(),
+		Symbol:    token.Symbol,
+		Address:   addr.Hex(),
+		Decimals:  uint32(token.Decimals),
+		Raw:       raw.String(),
+		Formatted: units.FormatUnits(raw, token.Decimals),
+	}, nil
+}
+
+// SendTransfer 广播已签名的交易
+func (s *Server) SendTransfer(ctx context.Context, req *gatewaypb.SendTransferRequest) (*gatewaypb.SendTransferResponse, error) {
+	tx, err := decode.ParseRawTransaction(req.Raw)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	if err := s.client.SendTransaction(ctx, tx); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "send transaction: %v", err)
+	}
+	return &gatewaypb.SendTransferResponse{Hash: tx.Hash().Hex(), From: decode.Transaction(tx).From}, nil
+}
+
+// Call 执行合约只读调用
+func (s *Server) Call(ctx context.Context, req *gatewaypb.CallRequest) (*gatewaypb.CallResponse, error) {
+	to, err := parseAddress("to address", req.To)
+	if err != nil {
+		return nil, err
+	}
+	data, err := hexutil.Decode(req.Data)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid data: %v", err)
+	}
+	block, err := api.ParseBlock(req.Block)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	msg := ethereum.CallMsg{To: &to, Data: data}
+	if req.From != "" {
+		if msg.From, err = parseAddress("from address", req.From); err != nil {
+			return nil, err
+		}
+	}
+	result, err := s.client.CallContract(ctx, msg, block)
+	if err != nil {
+		return nil, unavailable("call contract", err)
+	}
+	return &gatewaypb.CallResponse{Result: hexutil.Encode(result)}, nil
+}
+
+// GetLogs 查询合约的事件日志
+func (s *Server) GetLogs(ctx context.Context, req *gatewaypb.GetLogsRequest) (*gatewaypb.GetLogsResponse, error) {
+	addr, err := parseAddress("address", req.Address)
+	if err != nil {
+		return nil, err
+	}
+	query := ethereum.FilterQuery{Addresses: []common.Address{addr}}
+	if query.FromBlock, err = api.ParseBlock(req.FromBlock); err != nil {
+		return nil, invalid(err)
+	}
+	if query.ToBlock, err = api.ParseBlock(req.ToBlock); err != nil {
+		return nil, invalid(err)
+	}
+	if req.Topic0 != "" {
+		hash, err := hexutil.Decode(req.Topic0)
+		if err != nil || len(hash) != common.HashLength {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid topic0: %q", req.Topic0)
+		}
+		query.Topics = [][]common.Hash{{common.BytesToHash(hash)}}
+	}
+	logs, err := s.client.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, unavailable("filter logs", err)
+	}
+	resp := &gatewaypb.GetLogsResponse{Logs: make([]*gatewaypb.Log, len(logs))}
+	for i, l := range logs {
+		topics := make([]string, len(l.Topics))
+		for j, t := range l.Topics {
+			topics[j] = t.Hex()
+		}
+		resp.Logs[i] = &gatewaypb.Log{
+			Address: l.Address.Hex(), Topics: topics, Data: hexutil.Encode(l.Data),
+			BlockNumber: l.BlockNumber, BlockHash: l.BlockHash.Hex(), TxHash: l.TxHash.Hex(),
+			TxIndex: uint32(l.TxIndex), LogIndex: uint32(l.Index), Removed: l.Removed,
+		}
+	}
+	return resp, nil
+}
+
+// WatchConfirmations 每个轮询间隔查询一次收据和最新区块，状态或确认数变化时推送事件：
+// 未打包时为 PENDING，之后每个新区块一条 MINED，达到要求的确认数时发送 CONFIRMED 并结束。
+// 交易回滚时发送 FAILED 并结束；重组导致收据消失时重新回到 PENDING。流一直持续到客户端取消或超时
+func (s *Server) WatchConfirmations(req *gatewaypb.WatchConfirmationsRequest, stream gatewaypb.Gateway_WatchConfirmationsServer) error {
+	raw, err := hexutil.Decode(req.Hash)
+	if err != nil || len(raw) != common.HashLength {
+		return status.Errorf(codes.InvalidArgument, "invalid transaction hash: %q", req.Hash)
+	}
+	hash := common.BytesToHash(raw)
+	want := max(req.Confirmations, 1)
+	ctx := stream.Context()
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	var last *gatewaypb.ConfirmationEvent
+	for {
+		ev, err := s.confirmation(ctx, hash, want)
+		if err != nil {
+			return err
+		}
+		if last == nil || ev.Status != last.Status || ev.Confirmations != last.Confirmations || ev.BlockHash != last.BlockHash {
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+			last = ev
+		}
+		if ev.Status == gatewaypb.ConfirmationStatus_CONFIRMATION_STATUS_CONFIRMED ||
+			ev.Status == gatewaypb.ConfirmationStatus_CONFIRMATION_STATUS_FAILED {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// confirmation 返回交易当前的确认状态
+func (s *Server) confirmation(ctx context.Context, hash common.Hash, want uint64) (*gatewaypb.ConfirmationEvent, error) {
+	ev := &gatewaypb.ConfirmationEvent{Hash: hash.Hex(), Status: gatewaypb.ConfirmationStatus_CONFIRMATION_STATUS_PENDING}
+	receipt, err := s.client.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return ev, nil
+	}
+	if err != nil {
+		return nil, unavailable("get receipt", err)
+	}
+	ev.BlockNumber = receipt.BlockNumber.Uint64()
+	ev.BlockHash = receipt.BlockHash.Hex()
+	ev.GasUsed = receipt.GasUsed
+	ev.Confirmations = 1
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		ev.Status = gatewaypb.ConfirmationStatus_CONFIRMATION_STATUS_FAILED
+		return ev, nil
+	}
+	head, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, unavailable("get block number", err)
+	}
+	if head > ev.BlockNumber {
+		ev.Confirmations = head - ev.BlockNumber + 1
+	}
+	ev.Status = gatewaypb.ConfirmationStatus_CONFIRMATION_STATUS_MINED
+	if ev.Confirmations >= want {
+		ev.Status = gatewaypb.ConfirmationStatus_CONFIRMATION_STATUS_CONFIRMED
+	}
+	return ev, nil
+}
+
+func parseAddress(name, v string) (common.Address, error) {
+	addr, err := address.ParseHex(v)
+	if err != nil {
+		return common.Address{}, status.Errorf(codes.InvalidArgument, "%s: %v", name, err)
+	}
+	return addr, nil
+}
+
+func blockString(block *big.Int) string {
+	if block == nil {
+		return "latest"
+	}
+	return block.String()
+}
+
+func invalid(err error) error {
+	return status.Errorf(codes.InvalidArgument, "%v", err)
+}
+
+// unavailable 是访问节点失败的错误，对应 HTTP API 的 502
+func unavailable(op string, err error) error {
+	return status.Errorf(codes.Unavailable, "%s: %v", op, err)
+}
@@ -0,0 +1,171 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/erc20"
+	"github.com/local/go-eth-demo/pkg/gateway/gatewaypb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testToken = "secret"
+
+var (
+	holder = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	usdc   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// start 在本地端口上启动使用 client 的服务，返回带 token 的客户端
+func start(t *testing.T, client chain.Client, token string) gatewaypb.GatewayClient {
+	t.Helper()
+	s, err := New(client, testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.PollInterval = 10 * time.Millisecond
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := s.GRPCServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	conn, err := Dial(lis.Addr().String(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return gatewaypb.NewGatewayClient(conn)
+}
+
+func TestUnary(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	var sent *types.Transaction
+	client := &chain.ClientMock{
+		BalanceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+			return big.NewInt(1_500_000_000_000_000_000), nil
+		},
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			method, err := erc20.ABI.MethodById(call.Data[:4])
+			if err != nil {
+				return []byte{0xde, 0xad}, nil
+			}
+			switch method.Name {
+			case "balanceOf":
+				return method.Outputs.Pack(big.NewInt(2_500_000))
+			case "decimals":
+				return method.Outputs.Pack(uint8(6))
+			default:
+				return method.Outputs.Pack("USDC")
+			}
+		},
+		SendTransactionFunc: func(ctx context.Context, tx *types.Transaction) error {
+			sent = tx
+			return nil
+		},
+	}
+	c := start(t, client, testToken)
+	ctx := context.Background()
+
+	bal, err := c.GetBalance(ctx, &gatewaypb.GetBalanceRequest{Address: holder.Hex()})
+	if err != nil || bal.Wei != "1500000000000000000" || bal.Eth != "1.5" || bal.Block != "latest" {
+		t.Errorf("GetBalance = %v, %v", bal, err)
+	}
+	tok, err := c.GetTokenBalance(ctx, &gatewaypb.GetTokenBalanceRequest{Token: usdc.Hex(), Address: holder.Hex()})
+	if err != nil || tok.Symbol != "USDC" || tok.Decimals != 6 || tok.Formatted != "2.5" {
+		t.Errorf("GetTokenBalance = %v, %v", tok, err)
+	}
+	call, err := c.Call(ctx, &gatewaypb.CallRequest{To: usdc.Hex(), Data: "0x12345678"})
+	if err != nil || call.Result != "0xdead" {
+		t.Errorf("Call = %v, %v", call, err)
+	}
+
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &holder,
+	})
+	raw, _ := tx.MarshalBinary()
+	resp, err := c.SendTransfer(ctx, &gatewaypb.SendTransferRequest{Raw: hexutil.Encode(raw)})
+	if err != nil || resp.Hash != tx.Hash().Hex() || resp.From != crypto.PubkeyToAddress(key.PublicKey).Hex() || sent == nil {
+		t.Errorf("SendTransfer = %v, %v", resp, err)
+	}
+
+	_, err = c.GetBalance(ctx, &gatewaypb.GetBalanceRequest{Address: "0x1234"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetBalance with a bad address = %v, want InvalidArgument", err)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	c := start(t, &chain.ClientMock{}, "wrong")
+	_, err := c.GetBalance(context.Background(), &gatewaypb.GetBalanceRequest{Address: holder.Hex()})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetBalance with a wrong token = %v, want Unauthenticated", err)
+	}
+	stream, err := c.WatchConfirmations(context.Background(), &gatewaypb.WatchConfirmationsRequest{Hash: common.Hash{1}.Hex()})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("WatchConfirmations with a wrong token = %v, want Unauthenticated", err)
+	}
+}
+
+func TestWatchConfirmations(t *testing.T) {
+	hash := common.Hash{0xab}
+	var head atomic.Uint64
+	head.Store(99)
+	client := &chain.ClientMock{
+		TransactionReceiptFunc: func(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+			if head.Load() < 100 {
+				head.Add(1)
+				return nil, ethereum.NotFound
+			}
+			return &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: txHash, BlockNumber: big.NewInt(100), GasUsed: 21000}, nil
+		},
+		BlockNumberFunc: func(ctx context.Context) (uint64, error) {
+			return head.Add(1) - 1, nil
+		},
+	}
+	c := start(t, client, testToken)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := c.WatchConfirmations(ctx, &gatewaypb.WatchConfirmationsRequest{Hash: hash.Hex(), Confirmations: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for {
+		ev, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, ev.Status.String()+"/"+string(rune('0'+ev.Confirmations)))
+	}
+	want := []string{"CONFIRMATION_STATUS_PENDING/0", "CONFIRMATION_STATUS_MINED/1", "CONFIRMATION_STATUS_MINED/2", "CONFIRMATION_STATUS_CONFIRMED/3"}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,5 @@
+// Package gatewaypb 是由 proto/gateway/v1/gateway.proto 生成的消息类型和 gRPC 客户端/服务端桩，
+// 修改 proto 后运行 go generate 重新生成，不要手动编辑生成的文件。
+package gatewaypb
+
+//go:generate go -C ../../.. run github.com/bufbuild/buf/cmd/buf@v1.57.0 generate
@@ -0,0 +1,201 @@
+// Package metrics 为常驻运行的命令（serve、watch、index）收集运行指标，并以 Prometheus 文本格式
+// 在 /metrics 上暴露：RPC 调用统计、订阅延迟、索引吞吐量、待处理交易数和钱包余额。
+// 指标基于 go-ethereum 的 metrics 包，名称中的 "/" 在导出时转换为 "_"。
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/units"
+)
+
+// Metrics 持有一个进程的所有指标
+type Metrics struct {
+	reg gethmetrics.Registry
+}
+
+// New 创建空的指标集合
+func New() *Metrics {
+	return &Metrics{reg: gethmetrics.NewRegistry()}
+}
+
+// Handler 返回以 Prometheus 文本格式输出所有指标的 http.Handler
+func (m *Metrics) Handler() http.Handler {
+	return prometheus.Handler(m.reg)
+}
+
+// ListenAndServe 在 addr 上提供 /metrics，直到 ctx 结束
+func (m *Metrics) ListenAndServe(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", m.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// ObserveHead 记录最新区块高度，以及区块时间戳到现在的延迟（订阅或轮询落后了多久）
+func (m *Metrics) ObserveHead(head *types.Header) {
+	gethmetrics.GetOrRegisterGauge("chain/head", m.reg).Update(head.Number.Int64())
+	lag := time.Since(time.Unix(int64(head.Time), 0)).Seconds()
+	gethmetrics.GetOrRegisterGaugeFloat64("subscription/lag_seconds", m.reg).Update(lag)
+}
+
+// IndexedBlocks 累加索引器处理的区块和日志数量，吞吐量由 Prometheus 的 rate() 计算
+func (m *Metrics) IndexedBlocks(blocks, logs int) {
+	gethmetrics.GetOrRegisterCounter("indexer/blocks", m.reg).Inc(int64(blocks))
+	gethmetrics.GetOrRegisterCounter("indexer/logs", m.reg).Inc(int64(logs))
+}
+
+// SetPending 记录某个地址已广播但尚未打包的交易数
+func (m *Metrics) SetPending(addr common.Address, n uint64) {
+	gethmetrics.GetOrRegisterGauge("wallet/pending/"+strings.ToLower(addr.Hex()), m.reg).Update(int64(n))
+}
+
+// SetBalance 以 ETH 为单位记录钱包余额
+func (m *Metrics) SetBalance(addr common.Address, wei *big.Int) {
+	eth, _ := strconv.ParseFloat(units.FormatUnits(wei, 18), 64)
+	gethmetrics.GetOrRegisterGaugeFloat64("wallet/balance_eth/"+strings.ToLower(addr.Hex()), m.reg).Update(eth)
+}
+
+// Request 记录一次 API 请求
+func (m *Metrics) Request(route string, status int, elapsed time.Duration) {
+	name := "api/requests/" + metricName(route) + "/" + strconv.Itoa(status)
+	gethmetrics.GetOrRegisterCounter(name, m.reg).Inc(1)
+	gethmetrics.GetOrRegisterCounterFloat64("api/seconds/"+metricName(route), m.reg).Inc(elapsed.Seconds())
+}
+
+// Poll 每隔 interval 读取最新区块头以及 wallets 的余额和待处理交易数，直到 ctx 结束。
+// 轮询失败计入 poll/errors，不会中止。
+func (m *Metrics) Poll(ctx context.Context, client chain.Client, wallets []common.Address, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.pollOnce(ctx, client, wallets)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Metrics) pollOnce(ctx context.Context, client chain.Client, wallets []common.Address) {
+	failed := gethmetrics.GetOrRegisterCounter("poll/errors", m.reg)
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		failed.Inc(1)
+		return
+	}
+	m.ObserveHead(head)
+	for _, addr := range wallets {
+		balance, err := client.BalanceAt(ctx, addr, head.Number)
+		if err != nil {
+			failed.Inc(1)
+			continue
+		}
+		m.SetBalance(addr, balance)
+		pending, err := client.PendingNonceAt(ctx, addr)
+		if err != nil {
+			failed.Inc(1)
+			continue
+		}
+		mined, err := client.NonceAt(ctx, addr, head.Number)
+		if err != nil {
+			failed.Inc(1)
+			continue
+		}
+		if pending >= mined {
+			m.SetPending(addr, pending-mined)
+		}
+	}
+}
+
+// Transport 返回统计 JSON-RPC 调用的 http.RoundTripper：每个方法的调用次数、错误次数和累计耗时
+// （平均延迟 = rate(rpc_seconds) / rate(rpc_calls)）。批量请求中的每个方法分别计数。next 为 nil 时使用 http.DefaultTransport。
+func (m *Metrics) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{m: m, next: next}
+}
+
+type transport struct {
+	m    *Metrics
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var methods []string
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		methods = rpcMethods(body)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+	for _, method := range methods {
+		gethmetrics.GetOrRegisterCounter("rpc/calls/"+method, t.m.reg).Inc(1)
+		gethmetrics.GetOrRegisterCounterFloat64("rpc/seconds/"+method, t.m.reg).Inc(elapsed.Seconds())
+		if err != nil || resp.StatusCode >= 300 {
+			gethmetrics.GetOrRegisterCounter("rpc/errors/"+method, t.m.reg).Inc(1)
+		}
+	}
+	return resp, err
+}
+
+// rpcMethods 提取单个或批量 JSON-RPC 请求中的方法名
+func rpcMethods(body []byte) []string {
+	type call struct {
+		Method string `json:"method"`
+	}
+	var batch []call
+	if json.Unmarshal(body, &batch) != nil {
+		var single call
+		if json.Unmarshal(body, &single) != nil {
+			return nil
+		}
+		batch = []call{single}
+	}
+	methods := make([]string, 0, len(batch))
+	for _, c := range batch {
+		if c.Method != "" {
+			methods = append(methods, metricName(c.Method))
+		}
+	}
+	return methods
+}
+
+// metricName 把任意字符串转换为 Prometheus 指标名允许的字符
+func metricName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.Trim(s, "/ "))
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+type fakeEth struct{}
+
+func (fakeEth) ChainId() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1)) }
+
+// scrape 返回 /metrics 的输出
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	return rec.Body.String()
+}
+
+func TestTransportCountsCalls(t *testing.T) {
+	server := rpc.NewServer()
+	server.RegisterName("eth", fakeEth{})
+	node := httptest.NewServer(server)
+	defer node.Close()
+
+	m := New()
+	client, err := rpc.DialOptions(context.Background(), node.URL, rpc.WithHTTPClient(&http.Client{Transport: m.Transport(nil)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var id hexutil.Big
+	client.Call(&id, "eth_chainId")
+	client.BatchCall([]rpc.BatchElem{
+		{Method: "eth_chainId", Result: new(hexutil.Big)},
+		{Method: "eth_blockNumber", Result: new(hexutil.Uint64)},
+	})
+
+	out := scrape(t, m)
+	for _, want := range []string{"rpc_calls_eth_chainId 2", "rpc_calls_eth_blockNumber 1", "rpc_seconds_eth_chainId "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPollOnce(t *testing.T) {
+	wallet := common.HexToAddress("0xAbC0000000000000000000000000000000000001")
+	client := &chain.ClientMock{
+		HeaderByNumberFunc: func(ctx context.Context, number *big.Int) (*types.Header, error) {
+			return &types.Header{Number: big.NewInt(100), Time: uint64(time.Now().Add(-12 * time.Second).Unix())}, nil
+		},
+		BalanceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+			return big.NewInt(2_500_000_000_000_000_000), nil
+		},
+		PendingNonceAtFunc: func(ctx context.Context, account common.Address) (uint64, error) { return 7, nil },
+		NonceAtFunc:        func(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) { return 5, nil },
+	}
+	m := New()
+	m.pollOnce(context.Background(), client, []common.Address{wallet})
+	m.IndexedBlocks(10, 42)
+	m.Request("GET /v1/balance/{address}", 200, time.Millisecond)
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		"chain_head 100",
+		"wallet_balance_eth_0xabc0000000000000000000000000000000000001 2.5",
+		"wallet_pending_0xabc0000000000000000000000000000000000001 2",
+		"indexer_blocks 10",
+		"indexer_logs 42",
+		"api_requests_GET__v1_balance__address__200 1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, "subscription_lag_seconds 1") {
+		t.Errorf("lag gauge not around 12s:\n%s", out)
+	}
+}
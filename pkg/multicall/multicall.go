@@ -0,0 +1,177 @@
+// Package multicall 通过 Multicall3 的 aggregate3 把多个只读调用合并为一次 eth_call，
+// 读取大量余额、计数或代币信息时只需要一次 RPC 往返，适合有请求频率限制的提供商。
+// 每个调用都允许单独失败，失败的调用不影响同一批中的其他结果。
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+// DefaultAddress 是 Multicall3 在主网、Sepolia 等绝大多数链上的确定性部署地址
+var DefaultAddress = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// DefaultBatchSize 是单次 eth_call 中最多聚合的调用数，过大的批次可能超过节点的 gas 或返回大小限制
+const DefaultBatchSize = 500
+
+// ErrNotDeployed 表示 Multicall3 地址上没有合约代码，调用方可以退回逐个调用
+var ErrNotDeployed = errors.New("multicall3 is not deployed on this chain")
+
+// ABI 包含 aggregate3 和 getEthBalance
+var ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"aggregate3","stateMutability":"payable",
+ "inputs":[{"name":"calls","type":"tuple[]","components":[
+   {"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],
+ "outputs":[{"name":"returnData","type":"tuple[]","components":[
+   {"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]},
+{"type":"function","name":"getEthBalance","stateMutability":"view",
+ "inputs":[{"name":"addr","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// Call 是一个待聚合的只读调用
+type Call struct {
+	Target common.Address
+	Data   []byte
+}
+
+// Result 是一个调用的结果，Success 为 false 时 ReturnData 是回滚数据
+type Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// call3 对应 Multicall3 的 Call3 结构体
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// Pack 按 contract 中的 method 打包参数，生成对 target 的调用
+func Pack(target common.Address, contract *abi.ABI, method string, args ...interface{}) (Call, error) {
+	data, err := contract.Pack(method, args...)
+	if err != nil {
+		return Call{}, fmt.Errorf("pack %s: %w", method, err)
+	}
+	return Call{Target: target, Data: data}, nil
+}
+
+// Unpack 按 contract 中的 method 解码返回值。调用失败时返回 *ethtx.RevertError，
+// Reason 为解码后的 Error(string)/Panic 原因，无法解码时为回滚数据的十六进制
+func (r Result) Unpack(contract *abi.ABI, method string) ([]interface{}, error) {
+	if !r.Success {
+		reason, err := abi.UnpackRevert(r.ReturnData)
+		if err != nil && len(r.ReturnData) > 0 {
+			reason = hexutil.Encode(r.ReturnData)
+		}
+		return nil, &ethtx.RevertError{Reason: reason}
+	}
+	values, err := contract.Unpack(method, r.ReturnData)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s output: %w", method, err)
+	}
+	return values, nil
+}
+
+// Caller 通过指定地址上的 Multicall3 执行聚合调用
+type Caller struct {
+	Address   common.Address
+	BatchSize int // 每次 eth_call 最多聚合的调用数，0 表示 DefaultBatchSize
+
+	client chain.Client
+}
+
+// New 返回使用 address 上 Multicall3 的 Caller，address 为零地址时使用 DefaultAddress
+func New(client chain.Client, address common.Address) *Caller {
+	if address == (common.Address{}) {
+		address = DefaultAddress
+	}
+	return &Caller{Address: address, client: client}
+}
+
+// Aggregate 在 block 区块（nil 为最新区块）上执行所有调用，结果与 calls 一一对应。
+// 单个调用回滚只体现在对应 Result 的 Success 上；调用数超过 BatchSize 时分成多次 eth_call，
+// 同一区块上查询保证各批次读到的状态一致。
+func (c *Caller) Aggregate(ctx context.Context, calls []Call, block *big.Int) ([]Result, error) {
+	size := c.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	results := make([]Result, 0, len(calls))
+	for start := 0; start < len(calls); start += size {
+		batch, err := c.aggregate(ctx, calls[start:min(start+size, len(calls))], block)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+func (c *Caller) aggregate(ctx context.Context, calls []Call, block *big.Int) ([]Result, error) {
+	packed := make([]call3, len(calls))
+	for i, call := range calls {
+		packed[i] = call3{Target: call.Target, AllowFailure: true, CallData: call.Data}
+	}
+	input, err := ABI.Pack("aggregate3", packed)
+	if err != nil {
+		return nil, fmt.Errorf("pack aggregate3: %w", err)
+	}
+	output, err := c.client.CallContract(ctx, ethereum.CallMsg{To: &c.Address, Data: input}, block)
+	if err != nil {
+		return nil, fmt.Errorf("call multicall3 aggregate3: %w", err)
+	}
+	if len(output) == 0 {
+		return nil, fmt.Errorf("%w (no code at %s)", ErrNotDeployed, c.Address.Hex())
+	}
+	unpacked, err := ABI.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, fmt.Errorf("unpack aggregate3: %w", err)
+	}
+	results := *abi.ConvertType(unpacked[0], new([]Result)).(*[]Result)
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("multicall3 returned %d results for %d calls", len(results), len(calls))
+	}
+	return results, nil
+}
+
+// Balances 用 Multicall3.getEthBalance 读取 addrs 在 block 区块的原生代币余额，结果与 addrs 一一对应
+func (c *Caller) Balances(ctx context.Context, addrs []common.Address, block *big.Int) ([]*big.Int, error) {
+	calls := make([]Call, len(addrs))
+	for i, addr := range addrs {
+		call, err := Pack(c.Address, &ABI, "getEthBalance", addr)
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = call
+	}
+	results, err := c.Aggregate(ctx, calls, block)
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(addrs))
+	for i, r := range results {
+		values, err := r.Unpack(&ABI, "getEthBalance")
+		if err != nil {
+			return nil, fmt.Errorf("getEthBalance(%s): %w", addrs[i].Hex(), err)
+		}
+		balances[i] = values[0].(*big.Int)
+	}
+	return balances, nil
+}
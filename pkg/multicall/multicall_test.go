@@ -0,0 +1,118 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/ethtx"
+)
+
+var counterABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"function","name":"getCount","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// revertData 编码 Error(string) 回滚数据
+func revertData(reason string) []byte {
+	s, _ := abi.NewType("string", "", nil)
+	data, _ := abi.Arguments{{Type: s}}.Pack(reason)
+	return append(crypto.Keccak256([]byte("Error(string)"))[:4], data...)
+}
+
+// newMulticall 模拟部署在 DefaultAddress 上的 Multicall3：地址最后一个字节为 0xff 的目标总是回滚，
+// 其他目标的 getCount 返回地址最后一个字节，getEthBalance 返回地址最后一个字节乘以 1 gwei。
+// batches 记录每次 aggregate3 的调用数。
+func newMulticall(batches *[]int) *chain.ClientMock {
+	return &chain.ClientMock{
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			if *call.To != DefaultAddress {
+				return nil, nil
+			}
+			values, err := ABI.Methods["aggregate3"].Inputs.Unpack(call.Data[4:])
+			if err != nil {
+				return nil, err
+			}
+			calls := *abi.ConvertType(values[0], new([]call3)).(*[]call3)
+			*batches = append(*batches, len(calls))
+			results := make([]Result, len(calls))
+			for i, c := range calls {
+				switch {
+				case c.Target[19] == 0xff:
+					results[i] = Result{ReturnData: revertData("nope")}
+				case string(c.CallData[:4]) == string(ABI.Methods["getEthBalance"].ID):
+					addr := common.BytesToAddress(c.CallData[4:])
+					out, _ := ABI.Methods["getEthBalance"].Outputs.Pack(new(big.Int).Mul(big.NewInt(int64(addr[19])), big.NewInt(1e9)))
+					results[i] = Result{Success: true, ReturnData: out}
+				default:
+					out, _ := counterABI.Methods["getCount"].Outputs.Pack(big.NewInt(int64(c.Target[19])))
+					results[i] = Result{Success: true, ReturnData: out}
+				}
+			}
+			return ABI.Methods["aggregate3"].Outputs.Pack(results)
+		},
+	}
+}
+
+func TestAggregateAllowsFailures(t *testing.T) {
+	var batches []int
+	c := New(newMulticall(&batches), common.Address{})
+	c.BatchSize = 2
+	var calls []Call
+	for _, last := range []byte{1, 0xff, 3} {
+		call, err := Pack(common.Address{19: last}, &counterABI, "getCount")
+		if err != nil {
+			t.Fatal(err)
+		}
+		calls = append(calls, call)
+	}
+	results, err := c.Aggregate(context.Background(), calls, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || batches[0] != 2 || batches[1] != 1 {
+		t.Errorf("batches = %v, want [2 1]", batches)
+	}
+	for i, want := range []int64{1, -1, 3} {
+		values, err := results[i].Unpack(&counterABI, "getCount")
+		if want < 0 {
+			var revert *ethtx.RevertError
+			if !errors.As(err, &revert) || revert.Reason != "nope" {
+				t.Errorf("result %d: err = %v, want revert with reason nope", i, err)
+			}
+			continue
+		}
+		if err != nil || values[0].(*big.Int).Int64() != want {
+			t.Errorf("result %d = %v, %v; want %d", i, values, err, want)
+		}
+	}
+}
+
+func TestBalances(t *testing.T) {
+	var batches []int
+	balances, err := New(newMulticall(&batches), DefaultAddress).Balances(context.Background(), []common.Address{{19: 2}, {19: 5}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 1 || balances[0].Int64() != 2e9 || balances[1].Int64() != 5e9 {
+		t.Errorf("Balances = %v in %v batches", balances, batches)
+	}
+}
+
+func TestNotDeployed(t *testing.T) {
+	var batches []int
+	_, err := New(newMulticall(&batches), common.Address{1}).Balances(context.Background(), []common.Address{{2}}, nil)
+	if !errors.Is(err, ErrNotDeployed) {
+		t.Errorf("err = %v, want ErrNotDeployed", err)
+	}
+}
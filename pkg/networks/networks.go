@@ -0,0 +1,189 @@
+// Package networks 是常用链的预设：链 ID、原生代币、Multicall3 和包装原生代币（WETH）
+// 地址以及区块浏览器，命令据此在不同链上正确显示金额和链接，而不必到处硬编码常量。
+// 内置预设可以用 JSON 配置文件覆盖或扩展，每个预设也可以带上默认的 RPC 端点和签名账户，
+// 作为命令行 -chain 选择的链配置。
+package networks
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Currency 是链的原生代币
+type Currency struct {
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// Preset 描述一条链。Multicall、WETH 和 ENS 为零地址表示该链上没有部署
+type Preset struct {
+	Name      string         `json:"-"`
+	ChainID   uint64         `json:"chainId"`
+	Currency  Currency       `json:"currency"`
+	Multicall common.Address `json:"multicall,omitempty"`
+	WETH      common.Address `json:"weth,omitempty"` // 包装原生代币，如 Polygon 上的 WPOL、BSC 上的 WBNB
+	ENS       common.Address `json:"ens,omitempty"`  // ENS 注册表，用于显示地址的主名称
+	Explorer  string         `json:"explorer,omitempty"`
+	RPC       string         `json:"rpc,omitempty"`     // 默认 RPC 端点，可以包含 ${VAR} 形式的环境变量（如 API key）
+	Account   common.Address `json:"account,omitempty"` // 默认签名账户，零地址表示不指定
+}
+
+// RPCURL 返回展开环境变量后的默认 RPC 端点，没有配置时返回空字符串
+func (p Preset) RPCURL() string {
+	return os.ExpandEnv(p.RPC)
+}
+
+// TxURL 返回交易在区块浏览器上的链接，没有浏览器时返回空字符串
+func (p Preset) TxURL(hash common.Hash) string {
+	if p.Explorer == "" {
+		return ""
+	}
+	return strings.TrimSuffix(p.Explorer, "/") + "/tx/" + hash.Hex()
+}
+
+// AddressURL 返回地址在区块浏览器上的链接，没有浏览器时返回空字符串
+func (p Preset) AddressURL(addr common.Address) string {
+	if p.Explorer == "" {
+		return ""
+	}
+	return strings.TrimSuffix(p.Explorer, "/") + "/address/" + addr.Hex()
+}
+
+// multicall3 是 Multicall3 在绝大多数链上的确定性部署地址
+var multicall3 = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// ensRegistry 是 ENS 注册表在主网和以太坊测试网上的地址
+var ensRegistry = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+var eth = Currency{Symbol: "ETH", Decimals: 18}
+
+// builtin 是内置预设。L2 的 WETH 是 OP Stack 的预部署合约或各链的官方 WETH；
+// RPC 是各链的免费公共端点，只适合演示，生产环境应在配置文件或 $<NETWORK>_RPC 中替换
+var builtin = map[string]Preset{
+	"mainnet": {ChainID: 1, Currency: eth, Multicall: multicall3, ENS: ensRegistry,
+		WETH: common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"), Explorer: "https://etherscan.io",
+		RPC: "https://ethereum-rpc.publicnode.com"},
+	"sepolia": {ChainID: 11155111, Currency: eth, Multicall: multicall3, ENS: ensRegistry,
+		WETH: common.HexToAddress("0xfFf9976782d46CC05630D1f6eBAb18b2324d6B14"), Explorer: "https://sepolia.etherscan.io",
+		RPC: "https://ethereum-sepolia-rpc.publicnode.com"},
+	"holesky": {ChainID: 17000, Currency: eth, Multicall: multicall3, ENS: ensRegistry,
+		WETH: common.HexToAddress("0x94373a4919B3240D86eA41593D5eBa789FEF3848"), Explorer: "https://holesky.etherscan.io",
+		RPC: "https://ethereum-holesky-rpc.publicnode.com"},
+	"optimism": {ChainID: 10, Currency: eth, Multicall: multicall3,
+		WETH: common.HexToAddress("0x4200000000000000000000000000000000000006"), Explorer: "https://optimistic.etherscan.io"},
+	"op-sepolia": {ChainID: 11155420, Currency: eth, Multicall: multicall3,
+		WETH: common.HexToAddress("0x4200000000000000000000000000000000000006"), Explorer: "https://sepolia-optimism.etherscan.io"},
+	"base": {ChainID: 8453, Currency: eth, Multicall: multicall3,
+		WETH: common.HexToAddress("0x4200000000000000000000000000000000000006"), Explorer: "https://basescan.org",
+		RPC: "https://mainnet.base.org"},
+	"base-sepolia": {ChainID: 84532, Currency: eth, Multicall: multicall3,
+		WETH: common.HexToAddress("0x4200000000000000000000000000000000000006"), Explorer: "https://sepolia.basescan.org",
+		RPC: "https://sepolia.base.org"},
+	"arbitrum-one": {ChainID: 42161, Currency: eth, Multicall: multicall3,
+		WETH: common.HexToAddress("0x82aF49447D8a07e3bd95BD0d56f35241523fBab1"), Explorer: "https://arbiscan.io"},
+	"arbitrum-sepolia": {ChainID: 421614, Currency: eth, Multicall: multicall3,
+		WETH: common.HexToAddress("0x980B62Da83eFf3D4576C647993b0c1D7faf17c73"), Explorer: "https://sepolia.arbiscan.io"},
+	// zkSync Era 的 CREATE2 地址推导与以太坊不同，Multicall3 部署在其他地址
+	"zksync": {ChainID: 324, Currency: eth, Multicall: common.HexToAddress("0xF9cda624FBC7e059355ce98a31693d299FACd963"),
+		WETH: common.HexToAddress("0x5AEa5775959fBC2557Cc8789bC1bf90A239D9a91"), Explorer: "https://explorer.zksync.io"},
+	"zksync-sepolia": {ChainID: 300, Currency: eth, Multicall: common.HexToAddress("0xF9cda624FBC7e059355ce98a31693d299FACd963"),
+		Explorer: "https://sepolia.explorer.zksync.io"},
+	"polygon": {ChainID: 137, Currency: Currency{Symbol: "POL", Decimals: 18}, Multicall: multicall3,
+		WETH: common.HexToAddress("0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"), Explorer: "https://polygonscan.com"},
+	"bsc": {ChainID: 56, Currency: Currency{Symbol: "BNB", Decimals: 18}, Multicall: multicall3,
+		WETH: common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"), Explorer: "https://bscscan.com"},
+	// devnet up 或手动启动的 Anvil/Hardhat 节点，默认没有部署 Multicall3 和 WETH，
+	// 默认账户是节点的第一个预充值账户
+	"local": {ChainID: 31337, Currency: eth, RPC: "http://127.0.0.1:8545",
+		Account: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")},
+}
+
+// aliases 是预设的别名，Lookup 时解析为对应的预设
+var aliases = map[string]string{
+	"anvil":    "local",
+	"hardhat":  "local",
+	"ethereum": "mainnet",
+}
+
+// Registry 是按名称索引的链预设
+type Registry struct {
+	presets map[string]Preset
+}
+
+// Default 返回只包含内置预设的注册表
+func Default() *Registry {
+	r := &Registry{presets: make(map[string]Preset, len(builtin))}
+	for name, p := range builtin {
+		p.Name = name
+		r.presets[name] = p
+	}
+	return r
+}
+
+// Load 返回内置预设加上 path 中的用户配置。配置文件是以链名称为键的 JSON 对象，
+// 同名条目整体替换内置预设；path 为空或文件不存在时只返回内置预设。
+func Load(path string) (*Registry, error) {
+	r := Default()
+	if path == "" {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var user map[string]Preset
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil, fmt.Errorf("parse networks config %s: %w", path, err)
+	}
+	for name, p := range user {
+		if p.ChainID == 0 {
+			return nil, fmt.Errorf("network %q in %s has no chainId", name, path)
+		}
+		if p.Currency.Symbol == "" {
+			p.Currency = eth
+		}
+		p.Name = name
+		r.presets[name] = p
+	}
+	return r, nil
+}
+
+// Lookup 按名称或别名（如 anvil 对应 local）查找预设
+func (r *Registry) Lookup(name string) (Preset, error) {
+	if p, ok := r.presets[name]; ok {
+		return p, nil
+	}
+	if p, ok := r.presets[aliases[name]]; ok {
+		return p, nil
+	}
+	return Preset{}, fmt.Errorf("unknown network %q (known: %s)", name, strings.Join(r.Names(), ", "))
+}
+
+// ByChainID 按链 ID 查找预设；多个预设使用同一链 ID 时返回名称排序最靠前的一个
+func (r *Registry) ByChainID(id *big.Int) (Preset, bool) {
+	for _, name := range r.Names() {
+		if p := r.presets[name]; id.IsUint64() && p.ChainID == id.Uint64() {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
+
+// Names 返回所有预设的名称，按字母排序
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.presets))
+	for name := range r.presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,88 @@
+package networks
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDefault(t *testing.T) {
+	r := Default()
+	for _, name := range []string{"mainnet", "sepolia", "holesky", "optimism", "base", "arbitrum-one", "polygon", "bsc", "local"} {
+		p, err := r.Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name != name || p.ChainID == 0 || p.Currency.Symbol == "" || p.Currency.Decimals != 18 {
+			t.Errorf("incomplete preset %+v", p)
+		}
+		if got, ok := r.ByChainID(new(big.Int).SetUint64(p.ChainID)); !ok || got.Name != name {
+			t.Errorf("ByChainID(%d) = %q, %v", p.ChainID, got.Name, ok)
+		}
+	}
+	if p, err := r.Lookup("anvil"); err != nil || p.Name != "local" || p.RPCURL() == "" {
+		t.Errorf("Lookup(anvil) = %+v, %v, want the local preset", p, err)
+	}
+	if _, err := r.Lookup("nope"); err == nil {
+		t.Error("unknown network accepted")
+	}
+	if _, ok := r.ByChainID(big.NewInt(999999)); ok {
+		t.Error("unknown chain ID found")
+	}
+}
+
+func TestExplorerURLs(t *testing.T) {
+	p, _ := Default().Lookup("sepolia")
+	hash := common.HexToHash("0x01")
+	if got, want := p.TxURL(hash), "https://sepolia.etherscan.io/tx/"+hash.Hex(); got != want {
+		t.Errorf("TxURL = %s, want %s", got, want)
+	}
+	local, _ := Default().Lookup("local")
+	if local.AddressURL(common.Address{}) != "" {
+		t.Error("local network has an explorer URL")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "networks.json")
+	config := `{
+		"gnosis": {"chainId": 100, "currency": {"symbol": "xDAI", "decimals": 18}, "explorer": "https://gnosisscan.io",
+			"rpc": "https://gnosis.example/${GNOSIS_KEY}", "account": "0x000000000000000000000000000000000000dEaD"},
+		"local": {"chainId": 1337}
+	}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gnosis, err := r.Lookup("gnosis")
+	if err != nil || gnosis.Currency.Symbol != "xDAI" || gnosis.Account != common.HexToAddress("0xdead") {
+		t.Errorf("gnosis = %+v, %v", gnosis, err)
+	}
+	t.Setenv("GNOSIS_KEY", "secret")
+	if got := gnosis.RPCURL(); got != "https://gnosis.example/secret" {
+		t.Errorf("RPCURL = %s, want the key expanded", got)
+	}
+	// 用户条目覆盖内置预设，未设置的代币默认为 ETH
+	if local, _ := r.Lookup("local"); local.ChainID != 1337 || local.Currency.Symbol != "ETH" {
+		t.Errorf("local = %+v", local)
+	}
+	if _, err := r.Lookup("mainnet"); err != nil {
+		t.Error("built-in presets dropped after loading config")
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("missing config: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"x": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("preset without chain ID accepted")
+	}
+}
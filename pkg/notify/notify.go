@@ -0,0 +1,264 @@
+// Package notify 把告警（交易确认、卡住的交易、Gas 价格、地址活动等）按类型渲染成文本并发送到 Telegram
+// 机器人、Discord 或 Slack webhook，或者以 JSON 发送到通用 webhook 供外部系统处理。
+// 每种告警类型可以单独配置发送目标和消息模板。
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// 内置的告警类型，对应的模板数据见各自的默认模板
+const (
+	AlertTx      = "tx"      // 交易确认或失败
+	AlertGas     = "gas"     // Gas 价格越过阈值
+	AlertAddress = "address" // 被监视地址的余额或活动变化
+	AlertStuck   = "stuck"   // 交易发送后长时间没有打包
+)
+
+// defaultTemplates 是未配置模板时使用的消息格式
+var defaultTemplates = map[string]string{
+	AlertTx:      `Transaction {{.Hash}} {{.Status}}{{with .Block}} in block {{.}}{{end}}`,
+	AlertGas:     `Gas price {{.Price}} gwei is {{.Direction}} threshold {{.Threshold}} gwei`,
+	AlertAddress: `{{.Address}}: {{.Message}}`,
+	AlertStuck:   `Transaction {{.Hash}} (nonce {{.Nonce}}) is stuck: {{.Message}}`,
+}
+
+// 交易事件（TxEvent.Status），按发生顺序
+const (
+	TxSubmitted = "submitted" // 已广播
+	TxMined     = "mined"     // 已打包（第一个确认）
+	TxConfirmed = "confirmed" // 达到要求的确认数
+	TxFailed    = "failed"    // 已打包但执行失败
+)
+
+// TxEvent 是 tx 告警的数据，字段同时供模板和 webhook 的 JSON 使用
+type TxEvent struct {
+	Status        string `json:"status"`
+	Hash          string `json:"hash"`
+	ChainID       uint64 `json:"chainId"`
+	From          string `json:"from"`
+	To            string `json:"to,omitempty"` // 部署合约时为空
+	Nonce         uint64 `json:"nonce"`
+	Block         uint64 `json:"block,omitempty"`
+	Confirmations uint64 `json:"confirmations,omitempty"`
+	GasUsed       uint64 `json:"gasUsed,omitempty"`
+	Explorer      string `json:"explorer,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// GasEvent 是 gas 告警的数据，价格是以 gwei 为单位的十进制字符串
+type GasEvent struct {
+	Price     string `json:"price"`
+	Direction string `json:"direction"` // below 或 above
+	Threshold string `json:"threshold"`
+	ChainID   uint64 `json:"chainId"`
+	Block     uint64 `json:"block,omitempty"`
+}
+
+// AddressEvent 是 address 告警的数据，Message 是给人看的说明，其余字段供模板和 webhook 使用
+type AddressEvent struct {
+	Address   string `json:"address"`
+	Name      string `json:"name,omitempty"`
+	Message   string `json:"message"`
+	ChainID   uint64 `json:"chainId"`
+	Balance   string `json:"balance,omitempty"`   // 带单位，如 "0.3 ETH"
+	Threshold string `json:"threshold,omitempty"` // 带单位
+	Low       bool   `json:"low,omitempty"`       // 余额低于阈值
+}
+
+// StuckEvent 是 stuck 告警的数据，Message 是给人看的说明，其余字段供模板和 webhook 使用
+type StuckEvent struct {
+	Hash     string    `json:"hash"`
+	ChainID  uint64    `json:"chainId"`
+	From     string    `json:"from"`
+	Nonce    uint64    `json:"nonce"`
+	SentAt   time.Time `json:"sentAt"`
+	Pending  string    `json:"pending"` // 已等待的时间，如 "15m0s"
+	State    string    `json:"state"`   // pending、dropped 或 replaced，见 pkg/stuckwatch
+	Message  string    `json:"message"`
+	Explorer string    `json:"explorer,omitempty"`
+}
+
+// Sink 是一个消息发送目标
+type Sink interface {
+	Send(ctx context.Context, text string) error
+}
+
+// AlertSink 是需要告警类型和原始数据的目标，如发送结构化 JSON 的 Webhook。
+// Notify 对实现了它的目标调用 SendAlert 而不是 Send
+type AlertSink interface {
+	Sink
+	SendAlert(ctx context.Context, alert, text string, data interface{}) error
+}
+
+// Config 是通知配置，通常从 JSON 文件加载
+type Config struct {
+	Telegram *TelegramConfig        `json:"telegram,omitempty"`
+	Discord  *DiscordConfig         `json:"discord,omitempty"`
+	Slack    *SlackConfig           `json:"slack,omitempty"`
+	Webhook  *WebhookConfig         `json:"webhook,omitempty"`
+	Alerts   map[string]AlertConfig `json:"alerts"`
+}
+
+// AlertConfig 配置一种告警类型：发送到哪些目标（"telegram"、"discord"、"slack"、"webhook"）以及消息模板
+type AlertConfig struct {
+	Sinks    []string `json:"sinks"`
+	Template string   `json:"template,omitempty"`
+}
+
+// LoadConfig 读取 JSON 配置文件，文件中的 ${VAR} 会被替换为环境变量，
+// 以便把机器人 token 和 webhook URL 放在环境变量里
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal([]byte(os.ExpandEnv(string(data))), &cfg); err != nil {
+		return nil, fmt.Errorf("parse notify config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+type route struct {
+	sinks []Sink
+	tmpl  *template.Template
+}
+
+// Notifier 按告警类型把消息分发到配置的目标
+type Notifier struct {
+	routes map[string]route
+}
+
+// New 根据配置创建 Notifier，会校验所有模板和目标名称
+func New(cfg *Config) (*Notifier, error) {
+	sinks := make(map[string]Sink)
+	if cfg.Telegram != nil {
+		sinks["telegram"] = NewTelegram(*cfg.Telegram)
+	}
+	if cfg.Discord != nil {
+		sinks["discord"] = NewDiscord(*cfg.Discord)
+	}
+	if cfg.Slack != nil {
+		sinks["slack"] = NewSlack(*cfg.Slack)
+	}
+	if cfg.Webhook != nil {
+		if len(cfg.Webhook.URLs) == 0 {
+			return nil, errors.New("webhook has no urls")
+		}
+		sinks["webhook"] = NewWebhook(*cfg.Webhook)
+	}
+	return newNotifier(cfg.Alerts, sinks)
+}
+
+func newNotifier(alerts map[string]AlertConfig, sinks map[string]Sink) (*Notifier, error) {
+	n := &Notifier{routes: make(map[string]route)}
+	for alert, ac := range alerts {
+		text := ac.Template
+		if text == "" {
+			text = defaultTemplates[alert]
+		}
+		if text == "" {
+			return nil, fmt.Errorf("alert %q has no template", alert)
+		}
+		tmpl, err := template.New(alert).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("alert %q template: %w", alert, err)
+		}
+		r := route{tmpl: tmpl}
+		for _, name := range ac.Sinks {
+			sink, ok := sinks[name]
+			if !ok {
+				return nil, fmt.Errorf("alert %q uses sink %q, which is not configured", alert, name)
+			}
+			r.sinks = append(r.sinks, sink)
+		}
+		n.routes[alert] = r
+	}
+	return n, nil
+}
+
+// Enabled 报告某种告警是否配置了发送目标
+func (n *Notifier) Enabled(alert string) bool {
+	return n != nil && len(n.routes[alert].sinks) > 0
+}
+
+// Notify 用告警类型的模板渲染 data，并发送到该类型的所有目标。
+// 未配置的告警类型会被忽略；部分目标失败时返回合并的错误。
+func (n *Notifier) Notify(ctx context.Context, alert string, data interface{}) error {
+	if !n.Enabled(alert) {
+		return nil
+	}
+	r := n.routes[alert]
+	var buf strings.Builder
+	if err := r.tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("render %s alert: %w", alert, err)
+	}
+	var errs []error
+	for _, sink := range r.sinks {
+		var err error
+		if as, ok := sink.(AlertSink); ok {
+			err = as.SendAlert(ctx, alert, buf.String(), data)
+		} else {
+			err = sink.Send(ctx, buf.String())
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// httpClient 是发送通知使用的 HTTP 客户端，通知不应阻塞调用方太久
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// unwrapURLError 去掉 *url.Error 中带有密钥的 URL，只保留底层错误
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// postJSON 发送 JSON 请求，非 2xx 响应作为错误返回
+func postJSON(ctx context.Context, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return post(ctx, url, data, nil)
+}
+
+// post 发送已编码的 JSON 请求体，header 中的字段附加到请求上
+func post(ctx context.Context, url string, data []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recorder 是模拟 Telegram/Discord 的 HTTP 服务，记录收到的请求
+type recorder struct {
+	mu     sync.Mutex
+	paths  []string
+	bodies []map[string]interface{}
+	status int
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body map[string]interface{}
+	json.NewDecoder(req.Body).Decode(&body)
+	r.mu.Lock()
+	r.paths = append(r.paths, req.URL.Path)
+	r.bodies = append(r.bodies, body)
+	r.mu.Unlock()
+	if r.status != 0 {
+		http.Error(w, "nope", r.status)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n, err := New(&Config{
+		Telegram: &TelegramConfig{BotToken: "123:abc", ChatID: "42", APIBase: srv.URL},
+		Discord:  &DiscordConfig{WebhookURL: srv.URL + "/webhook"},
+		Alerts: map[string]AlertConfig{
+			AlertTx:    {Sinks: []string{"telegram", "discord"}},
+			AlertGas:   {Sinks: []string{"discord"}, Template: "⛽ {{.Price}} gwei"},
+			AlertStuck: {Sinks: []string{"telegram"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	err = n.Notify(ctx, AlertTx, map[string]interface{}{"Hash": "0xabc", "Status": "confirmed", "Block": 7})
+	if err != nil {
+		t.Fatalf("Notify tx: %v", err)
+	}
+	if err := n.Notify(ctx, AlertGas, struct{ Price string }{"42"}); err != nil {
+		t.Fatalf("Notify gas: %v", err)
+	}
+	// 未配置的告警类型被忽略
+	if err := n.Notify(ctx, AlertAddress, nil); err != nil || n.Enabled(AlertAddress) {
+		t.Fatalf("unconfigured alert: %v", err)
+	}
+
+	err = n.Notify(ctx, AlertStuck, StuckEvent{Hash: "0xdef", Nonce: 4, Message: "pending for 15m0s, still in the mempool"})
+	if err != nil {
+		t.Fatalf("Notify stuck: %v", err)
+	}
+
+	if len(rec.bodies) != 4 {
+		t.Fatalf("got %d requests, want 4", len(rec.bodies))
+	}
+	if rec.paths[0] != "/bot123:abc/sendMessage" || rec.bodies[0]["chat_id"] != "42" {
+		t.Errorf("unexpected telegram request %s %v", rec.paths[0], rec.bodies[0])
+	}
+	if got := rec.bodies[0]["text"]; got != "Transaction 0xabc confirmed in block 7" {
+		t.Errorf("telegram text = %q", got)
+	}
+	if rec.paths[1] != "/webhook" || rec.bodies[1]["content"] != "Transaction 0xabc confirmed in block 7" {
+		t.Errorf("unexpected discord request %s %v", rec.paths[1], rec.bodies[1])
+	}
+	if got := rec.bodies[2]["content"]; got != "⛽ 42 gwei" {
+		t.Errorf("gas alert content = %q", got)
+	}
+	if got := rec.bodies[3]["text"]; got != "Transaction 0xdef (nonce 4) is stuck: pending for 15m0s, still in the mempool" {
+		t.Errorf("stuck alert text = %q", got)
+	}
+}
+
+func TestNotifyErrors(t *testing.T) {
+	rec := &recorder{status: http.StatusUnauthorized}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n, err := New(&Config{
+		Telegram: &TelegramConfig{BotToken: "secret-token", ChatID: "1", APIBase: srv.URL},
+		Alerts:   map[string]AlertConfig{AlertAddress: {Sinks: []string{"telegram"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = n.Notify(context.Background(), AlertAddress, map[string]string{"Address": "0x1", "Message": "hi"})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("error = %v, want 401", err)
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error leaks bot token: %v", err)
+	}
+	// 模板缺少字段
+	if err := n.Notify(context.Background(), AlertAddress, map[string]string{"Address": "0x1"}); err == nil {
+		t.Error("missing template field accepted")
+	}
+
+	if _, err := New(&Config{Alerts: map[string]AlertConfig{AlertTx: {Sinks: []string{"discord"}}}}); err == nil {
+		t.Error("unconfigured sink accepted")
+	}
+	if _, err := New(&Config{Alerts: map[string]AlertConfig{"custom": {}}}); err == nil {
+		t.Error("custom alert without template accepted")
+	}
+	if _, err := New(&Config{Alerts: map[string]AlertConfig{AlertTx: {Template: "{{.Hash"}}}); err == nil {
+		t.Error("invalid template accepted")
+	}
+}
+
+func TestLoadConfigExpandsEnv(t *testing.T) {
+	t.Setenv("TEST_DISCORD_WEBHOOK", "https://discord.example/webhook")
+	path := filepath.Join(t.TempDir(), "notify.json")
+	os.WriteFile(path, []byte(`{"discord":{"webhookUrl":"${TEST_DISCORD_WEBHOOK}"},"alerts":{"gas":{"sinks":["discord"]}}}`), 0o600)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Discord.WebhookURL != "https://discord.example/webhook" {
+		t.Errorf("webhook URL = %q", cfg.Discord.WebhookURL)
+	}
+}
+
+func TestDiscordTruncates(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+	if err := NewDiscord(DiscordConfig{WebhookURL: srv.URL}).Send(context.Background(), strings.Repeat("x", 3000)); err != nil {
+		t.Fatal(err)
+	}
+	if got := len([]rune(rec.bodies[0]["content"].(string))); got != discordMaxContent {
+		t.Errorf("content length %d, want %d", got, discordMaxContent)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var body []byte
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer srv.Close()
+
+	n, err := New(&Config{
+		Webhook: &WebhookConfig{URLs: []string{srv.URL}, Headers: map[string]string{"X-Api-Key": "k"}, Secret: "s3cret"},
+		Alerts:  map[string]AlertConfig{AlertTx: {Sinks: []string{"webhook"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := TxEvent{Status: TxMined, Hash: "0xabc", ChainID: 11155111, From: "0x1", Block: 7, Confirmations: 1}
+	if err := n.Notify(context.Background(), AlertTx, ev); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		Alert string
+		Text  string
+		Data  TxEvent
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Alert != AlertTx || payload.Text != "Transaction 0xabc mined in block 7" || payload.Data != ev {
+		t.Errorf("payload = %+v", payload)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if got := header.Get("X-Signature-256"); got != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("signature = %q", got)
+	}
+	if header.Get("X-Api-Key") != "k" {
+		t.Errorf("custom header missing: %v", header)
+	}
+
+	if _, err := New(&Config{Webhook: &WebhookConfig{}}); err == nil {
+		t.Error("webhook without urls accepted")
+	}
+}
+
+func TestSlack(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n, err := New(&Config{
+		Slack:  &SlackConfig{WebhookURL: srv.URL + "/services/T0/B0/x"},
+		Alerts: map[string]AlertConfig{AlertAddress: {Sinks: []string{"slack"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = n.Notify(context.Background(), AlertAddress, map[string]string{"Address": "0x1", "Message": "balance < 0.1 ETH"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.paths[0] != "/services/T0/B0/x" || rec.bodies[0]["text"] != "0x1: balance &lt; 0.1 ETH" {
+		t.Errorf("unexpected slack request %s %v", rec.paths[0], rec.bodies[0])
+	}
+
+	rec.status = http.StatusNotFound
+	if err := NewSlack(SlackConfig{WebhookURL: srv.URL + "/services/secret"}).Send(context.Background(), "hi"); err == nil ||
+		strings.Contains(err.Error(), "secret") {
+		t.Errorf("error = %v, want 404 without the webhook URL", err)
+	}
+}
@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TelegramConfig 配置 Telegram 机器人
+type TelegramConfig struct {
+	BotToken string `json:"botToken"`
+	ChatID   string `json:"chatId"`
+	APIBase  string `json:"apiBase,omitempty"` // 默认 https://api.telegram.org
+}
+
+// Telegram 通过 Bot API 的 sendMessage 发送消息
+type Telegram struct {
+	cfg TelegramConfig
+}
+
+// NewTelegram 创建 Telegram 目标
+func NewTelegram(cfg TelegramConfig) *Telegram {
+	if cfg.APIBase == "" {
+		cfg.APIBase = "https://api.telegram.org"
+	}
+	return &Telegram{cfg: cfg}
+}
+
+// Send 实现 Sink
+func (t *Telegram) Send(ctx context.Context, text string) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", t.cfg.APIBase, t.cfg.BotToken)
+	err := postJSON(ctx, url, map[string]interface{}{
+		"chat_id":                  t.cfg.ChatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		// 错误中不包含 URL，避免泄露机器人 token
+		return fmt.Errorf("telegram sendMessage: %w", unwrapURLError(err))
+	}
+	return nil
+}
+
+// DiscordConfig 配置 Discord webhook
+type DiscordConfig struct {
+	WebhookURL string `json:"webhookUrl"`
+	Username   string `json:"username,omitempty"`
+}
+
+// Discord 通过频道 webhook 发送消息
+type Discord struct {
+	cfg DiscordConfig
+}
+
+// NewDiscord 创建 Discord 目标
+func NewDiscord(cfg DiscordConfig) *Discord {
+	return &Discord{cfg: cfg}
+}
+
+// discordMaxContent 是 Discord 消息内容的长度上限
+const discordMaxContent = 2000
+
+// Send 实现 Sink
+func (d *Discord) Send(ctx context.Context, text string) error {
+	if runes := []rune(text); len(runes) > discordMaxContent {
+		text = string(runes[:discordMaxContent-1]) + "…"
+	}
+	body := map[string]interface{}{"content": text}
+	if d.cfg.Username != "" {
+		body["username"] = d.cfg.Username
+	}
+	if err := postJSON(ctx, d.cfg.WebhookURL, body); err != nil {
+		return fmt.Errorf("discord webhook: %w", unwrapURLError(err))
+	}
+	return nil
+}
+
+// SlackConfig 配置 Slack incoming webhook（在 Slack 应用的 Incoming Webhooks 中为频道创建）
+type SlackConfig struct {
+	WebhookURL string `json:"webhookUrl"`
+}
+
+// Slack 通过 incoming webhook 发送消息
+type Slack struct {
+	cfg SlackConfig
+}
+
+// NewSlack 创建 Slack 目标
+func NewSlack(cfg SlackConfig) *Slack {
+	return &Slack{cfg: cfg}
+}
+
+// slackMaxText 是 Slack 消息文本的长度上限，超出部分会被 Slack 截断
+const slackMaxText = 40000
+
+// slackEscaper 转义 Slack mrkdwn 中的控制字符，避免地址等文本被当作链接或提及
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Send 实现 Sink
+func (s *Slack) Send(ctx context.Context, text string) error {
+	if runes := []rune(text); len(runes) > slackMaxText {
+		text = string(runes[:slackMaxText-1]) + "…"
+	}
+	if err := postJSON(ctx, s.cfg.WebhookURL, map[string]interface{}{"text": slackEscaper.Replace(text)}); err != nil {
+		// webhook URL 本身就是密钥
+		return fmt.Errorf("slack webhook: %w", unwrapURLError(err))
+	}
+	return nil
+}
+
+// WebhookConfig 配置通用 webhook：每条告警以 JSON（WebhookPayload）POST 到所有 URL
+type WebhookConfig struct {
+	URLs    []string          `json:"urls"`
+	Headers map[string]string `json:"headers,omitempty"` // 附加的请求头，如认证头
+	// Secret 不为空时用 HMAC-SHA256 对请求体签名，放在 X-Signature-256: sha256=<hex> 头中，
+	// 接收方可以据此确认请求来自本工具
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookPayload 是 webhook 请求体
+type WebhookPayload struct {
+	Alert string      `json:"alert,omitempty"` // 告警类型，如 "tx"
+	Text  string      `json:"text"`            // 按模板渲染的消息
+	Data  interface{} `json:"data,omitempty"`  // 告警的原始数据，如 TxEvent
+	Time  time.Time   `json:"time"`
+}
+
+// Webhook 把告警以 JSON 发送到一组 URL
+type Webhook struct {
+	cfg WebhookConfig
+}
+
+// NewWebhook 创建 webhook 目标
+func NewWebhook(cfg WebhookConfig) *Webhook {
+	return &Webhook{cfg: cfg}
+}
+
+// Send 实现 Sink，只发送文本
+func (w *Webhook) Send(ctx context.Context, text string) error {
+	return w.SendAlert(ctx, "", text, nil)
+}
+
+// SendAlert 实现 AlertSink，依次发送到每个 URL，部分失败时返回合并的错误
+func (w *Webhook) SendAlert(ctx context.Context, alert, text string, data interface{}) error {
+	body, err := json.Marshal(WebhookPayload{Alert: alert, Text: text, Data: data, Time: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	header := make(http.Header)
+	for k, v := range w.cfg.Headers {
+		header.Set(k, v)
+	}
+	if w.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.cfg.Secret))
+		mac.Write(body)
+		header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	var errs []error
+	for i, u := range w.cfg.URLs {
+		if err := post(ctx, u, body, header); err != nil {
+			// URL 中可能带有 token，错误中只给出序号
+			errs = append(errs, fmt.Errorf("webhook %d: %w", i+1, unwrapURLError(err)))
+		}
+	}
+	return errors.Join(errs...)
+}
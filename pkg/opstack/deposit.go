@@ -0,0 +1,123 @@
+package opstack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// DefaultDepositGasLimit 是 L2 上执行存款的 gas 上限，足够一次普通 ETH 转账
+const DefaultDepositGasLimit = 100_000
+
+// depositTxType 是 OP Stack 存款交易的类型字节
+const depositTxType = 0x7e
+
+// Deposit 调用 OptimismPortal.depositTransaction，把 amount wei 存入 L2 上的 to 地址
+func Deposit(ctx context.Context, l1 chain.Client, net Network, auth *bind.TransactOpts, to common.Address, amount *big.Int, gasLimit uint64) (*types.Transaction, error) {
+	portal := bind.NewBoundContract(net.Portal, portalABI, l1, l1, l1)
+	opts := *auth
+	opts.Context = ctx
+	opts.Value = amount
+	tx, err := portal.Transact(&opts, "depositTransaction", to, amount, gasLimit, false, []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("depositTransaction: %w", err)
+	}
+	return tx, nil
+}
+
+// DepositTx 是从 L1 TransactionDeposited 事件推导出的 L2 存款交易
+type DepositTx struct {
+	SourceHash common.Hash
+	From       common.Address
+	To         *common.Address `rlp:"nil"` // 合约创建时为 nil
+	Mint       *big.Int
+	Value      *big.Int
+	Gas        uint64
+	IsSystemTx bool
+	Data       []byte
+}
+
+// Hash 返回存款交易在 L2 上的交易哈希：keccak256(0x7e || rlp(tx))
+func (d *DepositTx) Hash() common.Hash {
+	enc, _ := rlp.EncodeToBytes(d)
+	return crypto.Keccak256Hash(append([]byte{depositTxType}, enc...))
+}
+
+// ParseDeposits 解析 L1 收据中 OptimismPortal 发出的所有 TransactionDeposited 事件
+func ParseDeposits(receipt *types.Receipt, portal common.Address) ([]*DepositTx, error) {
+	event := portalABI.Events["TransactionDeposited"]
+	var deposits []*DepositTx
+	for _, log := range receipt.Logs {
+		if log.Address != portal || len(log.Topics) != 4 || log.Topics[0] != event.ID {
+			continue
+		}
+		if version := log.Topics[3].Big(); version.Sign() != 0 {
+			return nil, fmt.Errorf("unsupported deposit version %s", version)
+		}
+		values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unpack TransactionDeposited: %w", err)
+		}
+		d, err := decodeOpaqueData(values[0].([]byte))
+		if err != nil {
+			return nil, err
+		}
+		d.From = common.BytesToAddress(log.Topics[1].Bytes())
+		if d.To != nil {
+			to := common.BytesToAddress(log.Topics[2].Bytes())
+			d.To = &to
+		}
+		// 用户存款的 sourceHash = keccak256(bytes32(0) || keccak256(l1BlockHash || bytes32(logIndex)))
+		depositID := crypto.Keccak256(log.BlockHash.Bytes(), common.BigToHash(new(big.Int).SetUint64(uint64(log.Index))).Bytes())
+		d.SourceHash = crypto.Keccak256Hash(make([]byte, 32), depositID)
+		deposits = append(deposits, d)
+	}
+	return deposits, nil
+}
+
+// decodeOpaqueData 解析 abi.encodePacked(mint, value, gasLimit, isCreation, data)
+func decodeOpaqueData(b []byte) (*DepositTx, error) {
+	const fixed = 32 + 32 + 8 + 1
+	if len(b) < fixed {
+		return nil, fmt.Errorf("deposit opaque data too short: %d bytes", len(b))
+	}
+	d := &DepositTx{
+		Mint:  new(big.Int).SetBytes(b[0:32]),
+		Value: new(big.Int).SetBytes(b[32:64]),
+		Gas:   new(big.Int).SetBytes(b[64:72]).Uint64(),
+		Data:  common.CopyBytes(b[fixed:]),
+	}
+	if b[72] == 0 {
+		d.To = &common.Address{} // 占位，由调用方填入事件中的 to
+	}
+	return d, nil
+}
+
+// WaitForReceipt 轮询节点直到交易被打包，用于等待 L2 上的存款交易（ethclient 无法解码
+// 0x7e 类型的交易本身，但可以读取其收据）
+func WaitForReceipt(ctx context.Context, client chain.Client, hash common.Hash, interval time.Duration) (*types.Receipt, error) {
+	for {
+		receipt, err := client.TransactionReceipt(ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package opstack
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// GasPriceOracleAddress 是 GasPriceOracle 预部署合约在所有 OP Stack 链上的地址
+var GasPriceOracleAddress = common.HexToAddress("0x420000000000000000000000000000000000000F")
+
+var gasPriceOracleABI = mustABI(`[
+{"type":"function","name":"getL1Fee","stateMutability":"view","inputs":[{"name":"_data","type":"bytes"}],"outputs":[{"name":"","type":"uint256"}]}
+]`)
+
+// IsL2 报告 chainID 是否是内置网络中的 OP Stack L2
+func IsL2(chainID uint64) bool {
+	for _, n := range Networks {
+		if n.L2ChainID == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect 报告 client 连接的链是否是 OP Stack L2：内置网络按链 ID 判断，其他链检查 GasPriceOracle 预部署合约是否存在
+func Detect(ctx context.Context, client chain.Client) (bool, error) {
+	id, err := client.ChainID(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if id.IsUint64() && IsL2(id.Uint64()) {
+		return true, nil
+	}
+	code, err := client.CodeAt(ctx, GasPriceOracleAddress, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to check GasPriceOracle: %w", err)
+	}
+	return len(code) > 0, nil
+}
+
+// GasPriceOracle 估算 OP Stack 交易的 L1 数据费用：交易除 L2 执行的 gas 外，还要为发布到 L1 的数据付费，
+// 费用随 L1 的 base fee 和 blob base fee 变化，在交易执行前从发送方余额中扣除
+type GasPriceOracle struct {
+	Client chain.Client
+}
+
+// L1Fee 调用 GasPriceOracle.getL1Fee 估算未签名交易 tx 的 L1 数据费用（合约会补上签名的长度）
+func (o *GasPriceOracle) L1Fee(ctx context.Context, tx *types.Transaction) (*big.Int, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	data, err := gasPriceOracleABI.Pack("getL1Fee", raw)
+	if err != nil {
+		return nil, err
+	}
+	out, err := o.Client.CallContract(ctx, ethereum.CallMsg{To: &GasPriceOracleAddress, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("GasPriceOracle.getL1Fee: %w", err)
+	}
+	values, err := gasPriceOracleABI.Unpack("getL1Fee", out)
+	if err != nil {
+		return nil, fmt.Errorf("decode getL1Fee: %w", err)
+	}
+	return values[0].(*big.Int), nil
+}
@@ -0,0 +1,54 @@
+package opstack
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+func TestL1Fee(t *testing.T) {
+	to := bob
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(10), To: &to, Gas: 21000, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1), Value: big.NewInt(1)})
+	want, _ := tx.MarshalBinary()
+	client := &chain.ClientMock{CallContractFunc: func(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+		if *msg.To != GasPriceOracleAddress {
+			t.Fatalf("call to %s", msg.To.Hex())
+		}
+		args, err := gasPriceOracleABI.Methods["getL1Fee"].Inputs.Unpack(msg.Data[4:])
+		if err != nil || string(args[0].([]byte)) != string(want) {
+			t.Fatalf("getL1Fee called with %x, %v", args, err)
+		}
+		return common.LeftPadBytes(big.NewInt(12345).Bytes(), 32), nil
+	}}
+	fee, err := (&GasPriceOracle{Client: client}).L1Fee(context.Background(), tx)
+	if err != nil || fee.Int64() != 12345 {
+		t.Fatalf("L1Fee = %v, %v", fee, err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, tt := range []struct {
+		chainID int64
+		code    []byte
+		want    bool
+	}{
+		{8453, nil, true},          // 内置网络按链 ID 判断
+		{7777777, []byte{1}, true}, // 其他链上有 GasPriceOracle
+		{1, nil, false},
+	} {
+		client := &chain.ClientMock{
+			ChainIDFunc: func(ctx context.Context) (*big.Int, error) { return big.NewInt(tt.chainID), nil },
+			CodeAtFunc: func(ctx context.Context, account common.Address, block *big.Int) ([]byte, error) {
+				return tt.code, nil
+			},
+		}
+		if got, err := Detect(context.Background(), client); err != nil || got != tt.want {
+			t.Errorf("chain %d: Detect = %v, %v, want %v", tt.chainID, got, err, tt.want)
+		}
+	}
+}
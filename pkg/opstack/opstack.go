@@ -0,0 +1,117 @@
+// Package opstack 实现 OP Stack 链（Optimism、Base）的标准桥流程：通过 OptimismPortal 把 ETH
+// 从 L1 存入 L2 并跟踪对应的 L2 存款交易；从 L2 发起提款，再在 L1 上完成证明和最终确认
+// 两个步骤，并可随时查询提款所处的阶段。
+package opstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Network 是一条 OP Stack 链在 L1 上的合约地址。DisputeGameFactory 等其他合约地址从
+// OptimismPortal 读取，不需要单独配置。
+type Network struct {
+	Name      string
+	L1ChainID uint64
+	L2ChainID uint64
+	Portal    common.Address // OptimismPortalProxy
+}
+
+// Networks 是内置的网络，地址来自 Optimism superchain-registry
+var Networks = map[string]Network{
+	"optimism": {
+		Name: "optimism", L1ChainID: 1, L2ChainID: 10,
+		Portal: common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed"),
+	},
+	"base": {
+		Name: "base", L1ChainID: 1, L2ChainID: 8453,
+		Portal: common.HexToAddress("0x49048044D57e1C92A77f79988d21Fa8fAF74E97e"),
+	},
+	"op-sepolia": {
+		Name: "op-sepolia", L1ChainID: 11155111, L2ChainID: 11155420,
+		Portal: common.HexToAddress("0x16Fc5058F25648194471939df75CF27A2fdC48BC"),
+	},
+	"base-sepolia": {
+		Name: "base-sepolia", L1ChainID: 11155111, L2ChainID: 84532,
+		Portal: common.HexToAddress("0x49f53e41452C74589E85cA1677426Ba426459e85"),
+	},
+}
+
+// LookupNetwork 按名称查找内置网络
+func LookupNetwork(name string) (Network, error) {
+	if n, ok := Networks[name]; ok {
+		return n, nil
+	}
+	names := make([]string, 0, len(Networks))
+	for n := range Networks {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return Network{}, fmt.Errorf("unknown OP Stack network %q (known: %s)", name, strings.Join(names, ", "))
+}
+
+// MessagePasser 是 L2ToL1MessagePasser 预部署合约在所有 OP Stack 链上的地址
+var MessagePasser = common.HexToAddress("0x4200000000000000000000000000000000000016")
+
+const withdrawalTuple = `{"name":"_tx","type":"tuple","components":[
+	{"name":"nonce","type":"uint256"},{"name":"sender","type":"address"},{"name":"target","type":"address"},
+	{"name":"value","type":"uint256"},{"name":"gasLimit","type":"uint256"},{"name":"data","type":"bytes"}]}`
+
+var (
+	portalABI = mustABI(`[
+{"type":"function","name":"depositTransaction","stateMutability":"payable","inputs":[
+	{"name":"_to","type":"address"},{"name":"_value","type":"uint256"},{"name":"_gasLimit","type":"uint64"},
+	{"name":"_isCreation","type":"bool"},{"name":"_data","type":"bytes"}],"outputs":[]},
+{"type":"function","name":"proveWithdrawalTransaction","stateMutability":"nonpayable","inputs":[` + withdrawalTuple + `,
+	{"name":"_disputeGameIndex","type":"uint256"},
+	{"name":"_outputRootProof","type":"tuple","components":[
+		{"name":"version","type":"bytes32"},{"name":"stateRoot","type":"bytes32"},
+		{"name":"messagePasserStorageRoot","type":"bytes32"},{"name":"latestBlockhash","type":"bytes32"}]},
+	{"name":"_withdrawalProof","type":"bytes[]"}],"outputs":[]},
+{"type":"function","name":"finalizeWithdrawalTransaction","stateMutability":"nonpayable","inputs":[` + withdrawalTuple + `],"outputs":[]},
+{"type":"function","name":"provenWithdrawals","stateMutability":"view","inputs":[
+	{"name":"","type":"bytes32"},{"name":"","type":"address"}],
+	"outputs":[{"name":"disputeGameProxy","type":"address"},{"name":"timestamp","type":"uint64"}]},
+{"type":"function","name":"finalizedWithdrawals","stateMutability":"view","inputs":[{"name":"","type":"bytes32"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"function","name":"proofMaturityDelaySeconds","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"respectedGameType","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint32"}]},
+{"type":"function","name":"disputeGameFactory","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+{"type":"event","name":"TransactionDeposited","anonymous":false,"inputs":[
+	{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},
+	{"name":"version","type":"uint256","indexed":true},{"name":"opaqueData","type":"bytes","indexed":false}]}
+]`)
+
+	factoryABI = mustABI(`[
+{"type":"function","name":"gameCount","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"gameAtIndex","stateMutability":"view","inputs":[{"name":"_index","type":"uint256"}],
+	"outputs":[{"name":"gameType","type":"uint32"},{"name":"timestamp","type":"uint64"},{"name":"proxy","type":"address"}]}
+]`)
+
+	gameABI = mustABI(`[
+{"type":"function","name":"l2BlockNumber","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"rootClaim","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]},
+{"type":"function","name":"status","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+]`)
+
+	messagePasserABI = mustABI(`[
+{"type":"function","name":"initiateWithdrawal","stateMutability":"payable","inputs":[
+	{"name":"_target","type":"address"},{"name":"_gasLimit","type":"uint256"},{"name":"_data","type":"bytes"}],"outputs":[]},
+{"type":"event","name":"MessagePassed","anonymous":false,"inputs":[
+	{"name":"nonce","type":"uint256","indexed":true},{"name":"sender","type":"address","indexed":true},
+	{"name":"target","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false},
+	{"name":"gasLimit","type":"uint256","indexed":false},{"name":"data","type":"bytes","indexed":false},
+	{"name":"withdrawalHash","type":"bytes32","indexed":false}]}
+]`)
+)
+
+func mustABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,170 @@
+package opstack
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+var (
+	alice = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bob   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func TestParseDeposits(t *testing.T) {
+	net := Networks["op-sepolia"]
+	// opaqueData = mint(32) || value(32) || gasLimit(8) || isCreation(1) || data
+	opaque := append(common.LeftPadBytes(big.NewInt(1e16).Bytes(), 32), common.LeftPadBytes(big.NewInt(1e16).Bytes(), 32)...)
+	opaque = append(opaque, common.LeftPadBytes(big.NewInt(DefaultDepositGasLimit).Bytes(), 8)...)
+	opaque = append(opaque, 0)
+	data, err := portalABI.Events["TransactionDeposited"].Inputs.NonIndexed().Pack(opaque)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := &types.Receipt{Logs: []*types.Log{
+		{Address: bob, Topics: []common.Hash{portalABI.Events["TransactionDeposited"].ID}}, // 其他合约的日志被忽略
+		{
+			Address:   net.Portal,
+			Topics:    []common.Hash{portalABI.Events["TransactionDeposited"].ID, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes()), {}},
+			Data:      data,
+			BlockHash: common.HexToHash("0xaa"),
+			Index:     3,
+		},
+	}}
+
+	deposits, err := ParseDeposits(receipt, net.Portal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 1 {
+		t.Fatalf("got %d deposits, want 1", len(deposits))
+	}
+	d := deposits[0]
+	if d.From != alice || d.To == nil || *d.To != bob || d.Mint.Cmp(big.NewInt(1e16)) != 0 || d.Gas != DefaultDepositGasLimit || len(d.Data) != 0 {
+		t.Errorf("unexpected deposit %+v", d)
+	}
+	// 不同的日志位置产生不同的 sourceHash 和 L2 交易哈希
+	receipt.Logs[1].Index = 4
+	other, _ := ParseDeposits(receipt, net.Portal)
+	if other[0].SourceHash == d.SourceHash || other[0].Hash() == d.Hash() {
+		t.Error("deposit hash does not depend on the log index")
+	}
+}
+
+func TestParseWithdrawal(t *testing.T) {
+	w := WithdrawalTx{Nonce: big.NewInt(7), Sender: alice, Target: alice, Value: big.NewInt(1e15), GasLimit: big.NewInt(DefaultWithdrawalGasLimit), Data: []byte{}}
+	event := messagePasserABI.Events["MessagePassed"]
+	data, err := event.Inputs.NonIndexed().Pack(w.Value, w.GasLimit, w.Data, w.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := &types.Receipt{BlockNumber: big.NewInt(1234), Logs: []*types.Log{{
+		Address: MessagePasser,
+		Topics:  []common.Hash{event.ID, common.BigToHash(w.Nonce), common.BytesToHash(alice.Bytes()), common.BytesToHash(alice.Bytes())},
+		Data:    data,
+	}}}
+	got, err := ParseWithdrawal(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Hash != w.Hash() || got.L2Block != 1234 || got.Nonce.Int64() != 7 {
+		t.Errorf("unexpected withdrawal %+v", got)
+	}
+
+	// 事件中的哈希与字段不一致时报错
+	bad, _ := event.Inputs.NonIndexed().Pack(w.Value, w.GasLimit, w.Data, [32]byte{1})
+	receipt.Logs[0].Data = bad
+	if _, err := ParseWithdrawal(receipt); err == nil {
+		t.Error("mismatched withdrawal hash accepted")
+	}
+	if _, err := ParseWithdrawal(&types.Receipt{}); err == nil {
+		t.Error("receipt without MessagePassed accepted")
+	}
+}
+
+// 结构体字段必须与 ABI 元组的组件一一对应，否则打包会失败
+func TestPackTuples(t *testing.T) {
+	w := WithdrawalTx{Nonce: big.NewInt(1), Value: big.NewInt(0), GasLimit: big.NewInt(1), Data: []byte{}}
+	if _, err := portalABI.Pack("proveWithdrawalTransaction", w, big.NewInt(0), outputRootProof{}, [][]byte{{1}}); err != nil {
+		t.Errorf("pack proveWithdrawalTransaction: %v", err)
+	}
+	if _, err := portalABI.Pack("finalizeWithdrawalTransaction", w); err != nil {
+		t.Errorf("pack finalizeWithdrawalTransaction: %v", err)
+	}
+}
+
+// portalMock 模拟 OptimismPortal、DisputeGameFactory 和争议游戏的只读方法
+func portalMock(t *testing.T, finalized bool, latestGameBlock int64) *chain.ClientMock {
+	factory := common.HexToAddress("0xfac")
+	game := common.HexToAddress("0x9a")
+	respond := func(a abi.ABI, data []byte, values ...interface{}) []byte {
+		method, err := a.MethodById(data[:4])
+		if err != nil {
+			t.Fatalf("unexpected call %x", data[:4])
+		}
+		out, err := method.Outputs.Pack(values...)
+		if err != nil {
+			t.Fatalf("pack %s: %v", method.Name, err)
+		}
+		return out
+	}
+	return &chain.ClientMock{CallContractFunc: func(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+		switch *msg.To {
+		case factory:
+			if method, _ := factoryABI.MethodById(msg.Data[:4]); method.Name == "gameCount" {
+				return respond(factoryABI, msg.Data, big.NewInt(5)), nil
+			}
+			return respond(factoryABI, msg.Data, uint32(0), uint64(0), game), nil
+		case game:
+			if method, _ := gameABI.MethodById(msg.Data[:4]); method.Name == "l2BlockNumber" {
+				return respond(gameABI, msg.Data, big.NewInt(latestGameBlock)), nil
+			}
+			return respond(gameABI, msg.Data, [32]byte{}), nil
+		}
+		method, _ := portalABI.MethodById(msg.Data[:4])
+		switch method.Name {
+		case "finalizedWithdrawals":
+			return respond(portalABI, msg.Data, finalized), nil
+		case "provenWithdrawals":
+			return respond(portalABI, msg.Data, common.Address{}, uint64(0)), nil
+		case "disputeGameFactory":
+			return respond(portalABI, msg.Data, factory), nil
+		case "respectedGameType":
+			return respond(portalABI, msg.Data, uint32(0)), nil
+		}
+		t.Fatalf("unexpected portal call %s", method.Name)
+		return nil, nil
+	}}
+}
+
+func TestGetStatus(t *testing.T) {
+	net := Networks["op-sepolia"]
+	w := &Withdrawal{Hash: common.HexToHash("0x01"), L2Block: 1000}
+	tests := []struct {
+		name      string
+		finalized bool
+		gameBlock int64
+		want      Status
+	}{
+		{"finalized", true, 2000, StatusFinalized},
+		{"no covering game", false, 999, StatusWaitingToProve},
+		{"ready to prove", false, 1000, StatusReadyToProve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := GetStatus(context.Background(), portalMock(t, tt.finalized, tt.gameBlock), net, w, alice)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Status != tt.want {
+				t.Errorf("status = %s, want %s", p.Status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package opstack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/proof"
+)
+
+// DefaultWithdrawalGasLimit 是提款在 L1 上执行的 gas 上限，足够一次普通 ETH 转账
+const DefaultWithdrawalGasLimit = 100_000
+
+// maxGameScan 是查找争议游戏时最多向前检查的游戏数量
+const maxGameScan = 100
+
+// gameStatusDefenderWins 是 FaultDisputeGame 中根声明被确认有效的状态值
+const (
+	gameStatusChallengerWins = 1
+	gameStatusDefenderWins   = 2
+)
+
+// ErrNotReadyToProve 表示还没有覆盖提款所在 L2 区块的争议游戏（通常需要等待约一小时）
+var ErrNotReadyToProve = errors.New("withdrawal is not ready to prove yet")
+
+// WithdrawalTx 对应 Types.WithdrawalTransaction 结构体
+type WithdrawalTx struct {
+	Nonce    *big.Int
+	Sender   common.Address
+	Target   common.Address
+	Value    *big.Int
+	GasLimit *big.Int
+	Data     []byte
+}
+
+// Hash 计算提款哈希：keccak256(abi.encode(nonce, sender, target, value, gasLimit, data))
+func (w *WithdrawalTx) Hash() common.Hash {
+	args := abi.Arguments{
+		{Type: mustType("uint256")}, {Type: mustType("address")}, {Type: mustType("address")},
+		{Type: mustType("uint256")}, {Type: mustType("uint256")}, {Type: mustType("bytes")},
+	}
+	enc, _ := args.Pack(w.Nonce, w.Sender, w.Target, w.Value, w.GasLimit, w.Data)
+	return crypto.Keccak256Hash(enc)
+}
+
+// Withdrawal 是从 L2 收据中解析出的提款
+type Withdrawal struct {
+	WithdrawalTx
+	Hash    common.Hash
+	L2Block uint64 // 发起提款的 L2 区块
+}
+
+// InitiateWithdrawal 在 L2 上调用 L2ToL1MessagePasser.initiateWithdrawal，把 amount wei 提到 L1 上的 to
+func InitiateWithdrawal(ctx context.Context, l2 chain.Client, auth *bind.TransactOpts, to common.Address, amount *big.Int, gasLimit uint64) (*types.Transaction, error) {
+	passer := bind.NewBoundContract(MessagePasser, messagePasserABI, l2, l2, l2)
+	opts := *auth
+	opts.Context = ctx
+	opts.Value = amount
+	tx, err := passer.Transact(&opts, "initiateWithdrawal", to, new(big.Int).SetUint64(gasLimit), []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("initiateWithdrawal: %w", err)
+	}
+	return tx, nil
+}
+
+// ParseWithdrawal 从 L2 收据的 MessagePassed 事件中解析提款
+func ParseWithdrawal(receipt *types.Receipt) (*Withdrawal, error) {
+	event := messagePasserABI.Events["MessagePassed"]
+	for _, log := range receipt.Logs {
+		if log.Address != MessagePasser || len(log.Topics) != 4 || log.Topics[0] != event.ID {
+			continue
+		}
+		values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unpack MessagePassed: %w", err)
+		}
+		w := &Withdrawal{
+			WithdrawalTx: WithdrawalTx{
+				Nonce:    log.Topics[1].Big(),
+				Sender:   common.BytesToAddress(log.Topics[2].Bytes()),
+				Target:   common.BytesToAddress(log.Topics[3].Bytes()),
+				Value:    values[0].(*big.Int),
+				GasLimit: values[1].(*big.Int),
+				Data:     values[2].([]byte),
+			},
+			Hash:    common.Hash(values[3].([32]byte)),
+			L2Block: receipt.BlockNumber.Uint64(),
+		}
+		if computed := w.WithdrawalTx.Hash(); computed != w.Hash {
+			return nil, fmt.Errorf("withdrawal hash mismatch: event %s, computed %s", w.Hash, computed)
+		}
+		return w, nil
+	}
+	return nil, fmt.Errorf("no MessagePassed event in transaction %s", receipt.TxHash.Hex())
+}
+
+// Status 是提款所处的阶段
+type Status string
+
+// 提款阶段，按先后顺序排列
+const (
+	StatusWaitingToProve  Status = "waiting-to-prove"  // 还没有覆盖该 L2 区块的争议游戏
+	StatusReadyToProve    Status = "ready-to-prove"    // 可以在 L1 上提交证明
+	StatusChallengePeriod Status = "challenge-period"  // 已证明，等待挑战期结束和争议游戏结算
+	StatusReadyToFinalize Status = "ready-to-finalize" // 可以在 L1 上最终确认
+	StatusFinalized       Status = "finalized"         // 资金已在 L1 上释放
+)
+
+// Progress 是提款的当前状态
+type Progress struct {
+	Status        Status
+	Game          common.Address // 证明所用（或可用）的争议游戏
+	ProvenAt      time.Time
+	FinalizableAt time.Time // 挑战期结束的时间
+}
+
+// GetStatus 查询提款的阶段。prover 是提交（或将要提交）证明的地址，
+// OptimismPortal 按 (提款哈希, 证明者) 记录证明。
+func GetStatus(ctx context.Context, l1 chain.Client, net Network, w *Withdrawal, prover common.Address) (*Progress, error) {
+	finalized, err := call(ctx, l1, net.Portal, portalABI, "finalizedWithdrawals", w.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if finalized[0].(bool) {
+		return &Progress{Status: StatusFinalized}, nil
+	}
+
+	proven, err := call(ctx, l1, net.Portal, portalABI, "provenWithdrawals", w.Hash, prover)
+	if err != nil {
+		return nil, err
+	}
+	if ts := proven[1].(uint64); ts != 0 {
+		p := &Progress{Status: StatusChallengePeriod, Game: proven[0].(common.Address), ProvenAt: time.Unix(int64(ts), 0)}
+		delay, err := call(ctx, l1, net.Portal, portalABI, "proofMaturityDelaySeconds")
+		if err != nil {
+			return nil, err
+		}
+		p.FinalizableAt = p.ProvenAt.Add(time.Duration(delay[0].(*big.Int).Int64()) * time.Second)
+		status, err := call(ctx, l1, p.Game, gameABI, "status")
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case status[0].(uint8) == gameStatusChallengerWins:
+			// 证明所用的输出根被推翻，需要基于新的游戏重新证明
+			p.Status = StatusReadyToProve
+		case status[0].(uint8) == gameStatusDefenderWins && !time.Now().Before(p.FinalizableAt):
+			p.Status = StatusReadyToFinalize
+		}
+		return p, nil
+	}
+
+	game, err := findGame(ctx, l1, net, w.L2Block)
+	if errors.Is(err, ErrNotReadyToProve) {
+		return &Progress{Status: StatusWaitingToProve}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Progress{Status: StatusReadyToProve, Game: game.Proxy}, nil
+}
+
+// disputeGame 是一个提交到 DisputeGameFactory 的输出根声明
+type disputeGame struct {
+	Index     *big.Int
+	Proxy     common.Address
+	L2Block   uint64
+	RootClaim common.Hash
+}
+
+// findGame 找到最新的、类型被 OptimismPortal 认可的争议游戏，并确认它覆盖了 l2Block
+func findGame(ctx context.Context, l1 chain.Client, net Network, l2Block uint64) (*disputeGame, error) {
+	out, err := call(ctx, l1, net.Portal, portalABI, "disputeGameFactory")
+	if err != nil {
+		return nil, err
+	}
+	factory := out[0].(common.Address)
+	if out, err = call(ctx, l1, net.Portal, portalABI, "respectedGameType"); err != nil {
+		return nil, err
+	}
+	gameType := out[0].(uint32)
+	if out, err = call(ctx, l1, factory, factoryABI, "gameCount"); err != nil {
+		return nil, err
+	}
+	count := out[0].(*big.Int).Int64()
+
+	for i := count - 1; i >= 0 && i >= count-maxGameScan; i-- {
+		index := big.NewInt(i)
+		g, err := call(ctx, l1, factory, factoryABI, "gameAtIndex", index)
+		if err != nil {
+			return nil, err
+		}
+		if g[0].(uint32) != gameType {
+			continue
+		}
+		proxy := g[2].(common.Address)
+		blockOut, err := call(ctx, l1, proxy, gameABI, "l2BlockNumber")
+		if err != nil {
+			return nil, err
+		}
+		if blockOut[0].(*big.Int).Uint64() < l2Block {
+			return nil, ErrNotReadyToProve
+		}
+		claim, err := call(ctx, l1, proxy, gameABI, "rootClaim")
+		if err != nil {
+			return nil, err
+		}
+		return &disputeGame{Index: index, Proxy: proxy, L2Block: blockOut[0].(*big.Int).Uint64(), RootClaim: claim[0].([32]byte)}, nil
+	}
+	return nil, ErrNotReadyToProve
+}
+
+// outputRootProof 对应 Types.OutputRootProof 结构体
+type outputRootProof struct {
+	Version                  [32]byte
+	StateRoot                [32]byte
+	MessagePasserStorageRoot [32]byte
+	LatestBlockhash          [32]byte
+}
+
+// Prove 在 L1 上提交提款证明：找到覆盖提款区块的争议游戏，读取该 L2 区块上 MessagePasser
+// 存储槽的 Merkle 证明，并在提交前确认重建的输出根与游戏的根声明一致。
+func Prove(ctx context.Context, l1, l2 chain.Client, proofs proof.ProofReader, net Network, auth *bind.TransactOpts, w *Withdrawal) (*types.Transaction, error) {
+	game, err := findGame(ctx, l1, net, w.L2Block)
+	if err != nil {
+		return nil, err
+	}
+	block := new(big.Int).SetUint64(game.L2Block)
+	header, err := l2.HeaderByNumber(ctx, block)
+	if err != nil {
+		return nil, fmt.Errorf("get L2 header %d: %w", game.L2Block, err)
+	}
+
+	// sentMessages[withdrawalHash] 的存储槽：keccak256(abi.encode(withdrawalHash, uint256(0)))
+	slot := crypto.Keccak256Hash(w.Hash.Bytes(), make([]byte, 32))
+	res, err := proofs.GetProof(ctx, MessagePasser, []string{slot.Hex()}, block)
+	if err != nil {
+		return nil, fmt.Errorf("get message passer proof: %w", err)
+	}
+	if len(res.StorageProof) != 1 || res.StorageProof[0].Value.Sign() == 0 {
+		return nil, fmt.Errorf("withdrawal %s not found in message passer at L2 block %d", w.Hash, game.L2Block)
+	}
+
+	orp := outputRootProof{StateRoot: header.Root, MessagePasserStorageRoot: res.StorageHash, LatestBlockhash: header.Hash()}
+	outputRoot := crypto.Keccak256Hash(orp.Version[:], orp.StateRoot[:], orp.MessagePasserStorageRoot[:], orp.LatestBlockhash[:])
+	if outputRoot != game.RootClaim {
+		return nil, fmt.Errorf("output root %s does not match dispute game %s root claim %s", outputRoot, game.Proxy, game.RootClaim)
+	}
+	withdrawalProof := make([][]byte, len(res.StorageProof[0].Proof))
+	for i, node := range res.StorageProof[0].Proof {
+		withdrawalProof[i] = common.FromHex(node)
+	}
+
+	portal := bind.NewBoundContract(net.Portal, portalABI, l1, l1, l1)
+	opts := *auth
+	opts.Context = ctx
+	tx, err := portal.Transact(&opts, "proveWithdrawalTransaction", w.WithdrawalTx, game.Index, orp, withdrawalProof)
+	if err != nil {
+		return nil, fmt.Errorf("proveWithdrawalTransaction: %w", err)
+	}
+	return tx, nil
+}
+
+// Finalize 在挑战期结束后于 L1 上最终确认提款，释放资金
+func Finalize(ctx context.Context, l1 chain.Client, net Network, auth *bind.TransactOpts, w *Withdrawal) (*types.Transaction, error) {
+	portal := bind.NewBoundContract(net.Portal, portalABI, l1, l1, l1)
+	opts := *auth
+	opts.Context = ctx
+	tx, err := portal.Transact(&opts, "finalizeWithdrawalTransaction", w.WithdrawalTx)
+	if err != nil {
+		return nil, fmt.Errorf("finalizeWithdrawalTransaction: %w", err)
+	}
+	return tx, nil
+}
+
+// call 调用只读方法并返回解码后的结果
+func call(ctx context.Context, client chain.Client, addr common.Address, contractABI abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	var out []interface{}
+	contract := bind.NewBoundContract(addr, contractABI, client, nil, nil)
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, method, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return out, nil
+}
+
+func mustType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
@@ -0,0 +1,80 @@
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL 是缓存价格的默认有效期
+const DefaultTTL = 5 * time.Minute
+
+// Cache 缓存 Provider 的价格。path 不为空时价格同时保存在该 JSON 文件中，之后运行的命令在有效期内
+// 不再请求 API（公共 API 的免费额度每分钟只有几十次请求）
+type Cache struct {
+	provider Provider
+	ttl      time.Duration
+	path     string
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	Price string    `json:"price"` // big.Rat 的精确十进制或分数形式
+	Time  time.Time `json:"time"`
+}
+
+// NewCache 返回缓存 provider 的 Cache，ttl 为 0 时使用 DefaultTTL，path 为空时只在内存中缓存。
+// 缓存文件无法读取时当作空缓存
+func NewCache(provider Provider, ttl time.Duration, path string) *Cache {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	c := &Cache{provider: provider, ttl: ttl, path: path, now: time.Now, entries: make(map[string]cacheEntry)}
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &c.entries)
+		}
+	}
+	return c
+}
+
+// Price 实现 Provider：有效期内的价格直接返回，否则向 provider 查询并写入缓存。
+// 写缓存文件失败不影响返回的价格
+func (c *Cache) Price(ctx context.Context, symbol, fiat string) (*big.Rat, error) {
+	key := strings.ToUpper(symbol) + "/" + strings.ToUpper(fiat)
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(e.Time) < c.ttl {
+		if r, ok := new(big.Rat).SetString(e.Price); ok {
+			return r, nil
+		}
+	}
+
+	r, err := c.provider.Price(ctx, symbol, fiat)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{Price: r.RatString(), Time: c.now()}
+	if c.path != "" {
+		c.save() // 写入失败只影响之后运行的命令
+	}
+	return r, nil
+}
+
+func (c *Cache) save() error {
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,170 @@
+// Package price 从 CoinGecko 或 Coinbase 查询原生代币的法币价格，用于在余额、转账金额和 Gas 费用旁
+// 显示等值的 USD/EUR/CNY 等。价格是精确的 big.Rat，经 Cache 缓存后同一价格在有效期内只查询一次。
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/local/go-eth-demo/pkg/units"
+)
+
+// ErrUnsupported 表示提供商不知道该代币或法币
+var ErrUnsupported = errors.New("price not available")
+
+// Provider 返回 1 个 symbol 代币（如 ETH）值多少 fiat 法币（如 USD），symbol 和 fiat 不区分大小写
+type Provider interface {
+	Price(ctx context.Context, symbol, fiat string) (*big.Rat, error)
+}
+
+// CoinGecko 使用 CoinGecko 的 /simple/price 接口，APIKey 为空时使用免费的公共额度
+type CoinGecko struct {
+	APIBase string
+	APIKey  string // Demo API key，通过 x-cg-demo-api-key 请求头发送
+	// IDs 把代币符号映射为 CoinGecko 的币种 ID，为 nil 时使用 DefaultCoinGeckoIDs
+	IDs map[string]string
+}
+
+// DefaultCoinGeckoIDs 是各链原生代币在 CoinGecko 上的 ID
+var DefaultCoinGeckoIDs = map[string]string{
+	"ETH":  "ethereum",
+	"POL":  "polygon-ecosystem-token",
+	"BNB":  "binancecoin",
+	"XDAI": "xdai",
+	"AVAX": "avalanche-2",
+}
+
+// NewCoinGecko 创建使用公共 API 端点的 CoinGecko 提供商
+func NewCoinGecko(apiKey string) *CoinGecko {
+	return &CoinGecko{APIBase: "https://api.coingecko.com/api/v3", APIKey: apiKey}
+}
+
+// Price 实现 Provider
+func (c *CoinGecko) Price(ctx context.Context, symbol, fiat string) (*big.Rat, error) {
+	ids := c.IDs
+	if ids == nil {
+		ids = DefaultCoinGeckoIDs
+	}
+	id, ok := ids[strings.ToUpper(symbol)]
+	if !ok {
+		return nil, fmt.Errorf("%s: no CoinGecko id: %w", symbol, ErrUnsupported)
+	}
+	fiat = strings.ToLower(fiat)
+	q := url.Values{"ids": {id}, "vs_currencies": {fiat}}
+	header := http.Header{}
+	if c.APIKey != "" {
+		header.Set("x-cg-demo-api-key", c.APIKey)
+	}
+	var out map[string]map[string]json.Number
+	if err := getJSON(ctx, c.APIBase+"/simple/price?"+q.Encode(), header, &out); err != nil {
+		return nil, fmt.Errorf("coingecko: %w", err)
+	}
+	n, ok := out[id][fiat]
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: %w", symbol, strings.ToUpper(fiat), ErrUnsupported)
+	}
+	return parseRat(n.String())
+}
+
+// Coinbase 使用 Coinbase 的公开现货价格接口，不需要 API key
+type Coinbase struct {
+	APIBase string
+}
+
+// NewCoinbase 创建使用公共 API 端点的 Coinbase 提供商
+func NewCoinbase() *Coinbase {
+	return &Coinbase{APIBase: "https://api.coinbase.com/v2"}
+}
+
+// Price 实现 Provider
+func (c *Coinbase) Price(ctx context.Context, symbol, fiat string) (*big.Rat, error) {
+	pair := strings.ToUpper(symbol) + "-" + strings.ToUpper(fiat)
+	var out struct {
+		Data struct {
+			Amount string `json:"amount"`
+		} `json:"data"`
+	}
+	err := getJSON(ctx, c.APIBase+"/prices/"+url.PathEscape(pair)+"/spot", nil, &out)
+	var status statusError
+	if errors.As(err, &status) && (status == http.StatusNotFound || status == http.StatusBadRequest) {
+		return nil, fmt.Errorf("%s: %w", pair, ErrUnsupported)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("coinbase: %w", err)
+	}
+	return parseRat(out.Data.Amount)
+}
+
+// statusError 是非 2xx 的 HTTP 状态码
+type statusError int
+
+func (s statusError) Error() string {
+	return fmt.Sprintf("HTTP %d %s", int(s), http.StatusText(int(s)))
+}
+
+func getJSON(ctx context.Context, u string, header http.Header, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		io.Copy(io.Discard, resp.Body)
+		return statusError(resp.StatusCode)
+	}
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	return dec.Decode(out)
+}
+
+func parseRat(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() < 0 {
+		return nil, fmt.Errorf("invalid price %q", s)
+	}
+	return r, nil
+}
+
+// Value 返回 decimals 位小数的数量 amount 按单价 price 换算的法币价值
+func Value(amount *big.Int, decimals int, price *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(units.ToRat(amount, decimals), price)
+}
+
+// fiatSymbols 是显示在金额前的货币符号，其他法币在金额后显示代码
+var fiatSymbols = map[string]string{"USD": "$", "EUR": "€", "GBP": "£", "CNY": "¥", "JPY": "¥"}
+
+// Format 把法币价值四舍五入到分并加上货币符号，如 "$1,234.56"、"¥35.00"、"12.30 CHF"；
+// 不为零但不足一分的价值显示为 "<$0.01"，避免小额 Gas 费用显示成零
+func Format(value *big.Rat, fiat string) string {
+	fiat = strings.ToUpper(fiat)
+	// 先精确到 18 位小数再交给 units.Format 舍入和分组
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)))
+	amount := new(big.Int).Quo(scaled.Num(), scaled.Denom())
+	s := units.Format(amount, 18, units.FormatOptions{Places: 2, Group: true})
+	prefix := ""
+	if value.Sign() > 0 && s == "0.00" {
+		s, prefix = "0.01", "<"
+	}
+	if strings.HasPrefix(s, "-") {
+		prefix, s = "-", s[1:]
+	}
+	if sym, ok := fiatSymbols[fiat]; ok {
+		return prefix + sym + s
+	}
+	return prefix + s + " " + fiat
+}
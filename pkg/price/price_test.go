@@ -0,0 +1,117 @@
+package price
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCoinGecko(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/simple/price" || r.URL.Query().Get("ids") != "ethereum" || r.Header.Get("x-cg-demo-api-key") != "key" {
+			t.Errorf("unexpected request %s with key %q", r.URL, r.Header.Get("x-cg-demo-api-key"))
+		}
+		// 价格按 JSON 数字原样解析，不经过 float64
+		w.Write([]byte(`{"ethereum":{"usd":3021.123456789012345}}`))
+	}))
+	defer srv.Close()
+	cg := &CoinGecko{APIBase: srv.URL, APIKey: "key"}
+
+	got, err := cg.Price(context.Background(), "eth", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := new(big.Rat).SetString("3021.123456789012345"); got.Cmp(want) != 0 {
+		t.Errorf("price = %s, want %s", got.FloatString(15), want.FloatString(15))
+	}
+	if _, err := cg.Price(context.Background(), "eth", "eur"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("missing currency: err = %v, want ErrUnsupported", err)
+	}
+	if _, err := cg.Price(context.Background(), "DOGE", "usd"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("unknown symbol: err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestCoinbase(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prices/ETH-EUR/spot" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data":{"amount":"2780.5","base":"ETH","currency":"EUR"}}`))
+	}))
+	defer srv.Close()
+	cb := &Coinbase{APIBase: srv.URL}
+
+	got, err := cb.Price(context.Background(), "eth", "eur")
+	if err != nil || got.FloatString(1) != "2780.5" {
+		t.Errorf("price = %v, %v", got, err)
+	}
+	if _, err := cb.Price(context.Background(), "eth", "xyz"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("unknown pair: err = %v, want ErrUnsupported", err)
+	}
+}
+
+// countingProvider 记录查询次数
+type countingProvider struct{ calls int }
+
+func (p *countingProvider) Price(ctx context.Context, symbol, fiat string) (*big.Rat, error) {
+	p.calls++
+	return big.NewRat(300012, 100), nil
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	p := &countingProvider{}
+	now := time.Unix(1700000000, 0)
+	c := NewCache(p, time.Minute, path)
+	c.now = func() time.Time { return now }
+
+	for range 3 {
+		if _, err := c.Price(context.Background(), "ETH", "usd"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.calls != 1 {
+		t.Errorf("provider called %d times, want 1", p.calls)
+	}
+
+	// 另一个进程从缓存文件读取
+	c2 := NewCache(p, time.Minute, path)
+	c2.now = func() time.Time { return now.Add(30 * time.Second) }
+	got, err := c2.Price(context.Background(), "eth", "USD")
+	if err != nil || got.Cmp(big.NewRat(300012, 100)) != 0 || p.calls != 1 {
+		t.Errorf("cached price from file = %v, %v after %d calls", got, err, p.calls)
+	}
+	c2.now = func() time.Time { return now.Add(2 * time.Minute) }
+	c2.Price(context.Background(), "eth", "USD")
+	if p.calls != 2 {
+		t.Errorf("expired entry: provider called %d times, want 2", p.calls)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	ethUSD := big.NewRat(300012, 100) // 3000.12
+	tests := []struct {
+		wei  string
+		fiat string
+		want string
+	}{
+		{"1500000000000000000", "usd", "$4,500.18"},
+		{"1000000000000000", "EUR", "€3.00"},
+		{"1000000000000", "usd", "<$0.01"},
+		{"0", "cny", "¥0.00"},
+		{"1000000000000000000", "chf", "3,000.12 CHF"},
+	}
+	for _, tt := range tests {
+		wei, _ := new(big.Int).SetString(tt.wei, 10)
+		if got := Format(Value(wei, 18, ethUSD), tt.fiat); got != tt.want {
+			t.Errorf("Format(%s wei, %s) = %q, want %q", tt.wei, tt.fiat, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package pricefeed 通过 abigen 绑定读取 Chainlink 价格喂价（AggregatorV3Interface），把 latestRoundData
+// 的整数答案按喂价的 decimals 换算为价格，并检查答案是否有效、是否过期。这是继 Counter 之后读取
+// 真实合约状态的练习：喂价由预言机节点定期更新，读取方必须自己判断数据是否还可信。
+package pricefeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/go-eth-demo/chainlink"
+	"github.com/local/go-eth-demo/pkg/units"
+)
+
+var (
+	// ErrInvalidAnswer 表示喂价的答案不是正数
+	ErrInvalidAnswer = errors.New("feed answer is not positive")
+	// ErrIncompleteRound 表示该轮还没有答案（updatedAt 为 0）或答案来自更早的轮次
+	ErrIncompleteRound = errors.New("feed round is incomplete")
+	// ErrStale 表示答案的更新时间超过了允许的最大间隔
+	ErrStale = errors.New("feed answer is stale")
+)
+
+// DefaultMaxAge 是默认允许的答案最大间隔。ETH/USD 等喂价的心跳是 1 小时，价格波动超过阈值时会更早更新
+const DefaultMaxAge = time.Hour + 10*time.Minute
+
+// Feeds 是常用喂价在各链上的代理合约地址，按链 ID 和交易对（如 "ETH/USD"）索引
+var Feeds = map[uint64]map[string]common.Address{
+	1: {
+		"ETH/USD":  common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"),
+		"BTC/USD":  common.HexToAddress("0xF4030086522a5bEEa4988F8cA5B36dbC97BeE88c"),
+		"LINK/USD": common.HexToAddress("0x2c1d072e956AFFC0D435Cb7AC38EF18d24d9127c"),
+	},
+	11155111: {
+		"ETH/USD":  common.HexToAddress("0x694AA1769357215DE4FAC081bf1f309aDC325306"),
+		"BTC/USD":  common.HexToAddress("0x1b44F3514812d835EB1BDB0acB33d3fA3351Ee43"),
+		"LINK/USD": common.HexToAddress("0xc59E3633BAAC79493d908e63626716e204A45EdF"),
+	},
+}
+
+// Lookup 返回链上交易对喂价的地址，pair 不区分大小写
+func Lookup(chainID uint64, pair string) (common.Address, bool) {
+	addr, ok := Feeds[chainID][strings.ToUpper(pair)]
+	return addr, ok
+}
+
+// Round 是喂价的一轮答案
+type Round struct {
+	Feed            common.Address
+	Description     string // 如 "ETH / USD"
+	Decimals        uint8
+	RoundID         *big.Int
+	Answer          *big.Int // 价格 × 10^Decimals
+	StartedAt       time.Time
+	UpdatedAt       time.Time
+	AnsweredInRound *big.Int
+}
+
+// Price 返回按 Decimals 换算的精确价格，如 "3021.12345678"
+func (r *Round) Price() string {
+	return units.FormatUnits(r.Answer, int(r.Decimals))
+}
+
+// Check 检查答案是否可用：必须为正数、所在轮次已完成，并且在 now 之前 maxAge 以内更新过
+// （maxAge 为 0 时不检查是否过期）
+func (r *Round) Check(now time.Time, maxAge time.Duration) error {
+	if r.Answer.Sign() <= 0 {
+		return fmt.Errorf("%s: %w: %s", r.Description, ErrInvalidAnswer, r.Answer)
+	}
+	if r.UpdatedAt.Unix() == 0 || r.AnsweredInRound.Cmp(r.RoundID) < 0 {
+		return fmt.Errorf("%s: %w: round %s", r.Description, ErrIncompleteRound, r.RoundID)
+	}
+	if age := now.Sub(r.UpdatedAt); maxAge > 0 && age > maxAge {
+		return fmt.Errorf("%s: %w: updated %s ago, max %s", r.Description, ErrStale, age.Round(time.Second), maxAge)
+	}
+	return nil
+}
+
+// Latest 读取喂价的描述、小数位数和最新一轮答案，不做有效性检查（见 Round.Check）
+func Latest(ctx context.Context, backend bind.ContractCaller, feed common.Address) (*Round, error) {
+	agg, err := chainlink.NewAggregatorV3Caller(feed, backend)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	decimals, err := agg.Decimals(opts)
+	if err != nil {
+		return nil, fmt.Errorf("read decimals of feed %s: %w", feed.Hex(), err)
+	}
+	description, err := agg.Description(opts)
+	if err != nil {
+		return nil, fmt.Errorf("read description of feed %s: %w", feed.Hex(), err)
+	}
+	data, err := agg.LatestRoundData(opts)
+	if err != nil {
+		return nil, fmt.Errorf("read latestRoundData of feed %s: %w", feed.Hex(), err)
+	}
+	return &Round{
+		Feed:            feed,
+		Description:     description,
+		Decimals:        decimals,
+		RoundID:         data.RoundId,
+		Answer:          data.Answer,
+		StartedAt:       time.Unix(data.StartedAt.Int64(), 0),
+		UpdatedAt:       time.Unix(data.UpdatedAt.Int64(), 0),
+		AnsweredInRound: data.AnsweredInRound,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package pricefeed
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/go-eth-demo/chainlink"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+var updated = time.Unix(1_700_000_000, 0)
+
+// newFeed 返回模拟的 ETH / USD 喂价：8 位小数，最新一轮答案为 answer，更新于 updated
+func newFeed(t *testing.T, answer int64, answeredInRound int64) *chain.ClientMock {
+	parsed, err := abi.JSON(strings.NewReader(chainlink.AggregatorV3MetaData.ABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &chain.ClientMock{
+		CallContractFunc: func(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+			method, err := parsed.MethodById(call.Data[:4])
+			if err != nil {
+				return nil, err
+			}
+			switch method.Name {
+			case "decimals":
+				return method.Outputs.Pack(uint8(8))
+			case "description":
+				return method.Outputs.Pack("ETH / USD")
+			case "latestRoundData":
+				return method.Outputs.Pack(big.NewInt(42), big.NewInt(answer), big.NewInt(updated.Unix()-12),
+					big.NewInt(updated.Unix()), big.NewInt(answeredInRound))
+			}
+			return nil, errors.New("execution reverted")
+		},
+	}
+}
+
+func TestLatest(t *testing.T) {
+	r, err := Latest(context.Background(), newFeed(t, 302112345678, 42), common.Address{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Description != "ETH / USD" || r.Decimals != 8 || r.RoundID.Int64() != 42 || !r.UpdatedAt.Equal(updated) {
+		t.Errorf("Latest = %+v", r)
+	}
+	if r.Price() != "3021.12345678" {
+		t.Errorf("Price = %s, want 3021.12345678", r.Price())
+	}
+	if err := r.Check(updated.Add(time.Minute), DefaultMaxAge); err != nil {
+		t.Errorf("Check on a fresh answer: %v", err)
+	}
+	if err := r.Check(updated.Add(2*time.Hour), DefaultMaxAge); !errors.Is(err, ErrStale) {
+		t.Errorf("Check two hours later = %v, want ErrStale", err)
+	}
+	if err := r.Check(updated.Add(48*time.Hour), 0); err != nil {
+		t.Errorf("Check with maxAge 0 = %v, want no staleness check", err)
+	}
+}
+
+func TestCheckInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		answer, answeredInRound int64
+		want                    error
+	}{
+		{0, 42, ErrInvalidAnswer},
+		{-5, 42, ErrInvalidAnswer},
+		{100, 41, ErrIncompleteRound},
+	} {
+		r, err := Latest(context.Background(), newFeed(t, tt.answer, tt.answeredInRound), common.Address{1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Check(updated, DefaultMaxAge); !errors.Is(err, tt.want) {
+			t.Errorf("answer %d in round %d: Check = %v, want %v", tt.answer, tt.answeredInRound, err, tt.want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if addr, ok := Lookup(11155111, "eth/usd"); !ok || addr != common.HexToAddress("0x694AA1769357215DE4FAC081bf1f309aDC325306") {
+		t.Errorf("Lookup(sepolia, eth/usd) = %s, %v", addr.Hex(), ok)
+	}
+	if _, ok := Lookup(31337, "ETH/USD"); ok {
+		t.Error("Lookup found a feed on a local chain")
+	}
+}
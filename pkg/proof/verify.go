@@ -0,0 +1,183 @@
+// Package proof 提供基于 eth_getProof 的信任最小化读取：余额和存储值由一个提供商的
+// Merkle 证明给出，再用从另一个独立提供商获取的区块头 stateRoot 验证。
+package proof
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// ErrMismatch 表示提供商返回的数据与证明或独立区块头不一致
+var ErrMismatch = errors.New("proof mismatch")
+
+// Account 是经过验证的账户状态
+type Account struct {
+	Address     common.Address
+	BlockNumber uint64
+	StateRoot   common.Hash
+	Nonce       uint64
+	Balance     *big.Int
+	StorageRoot common.Hash
+	CodeHash    common.Hash
+	Storage     map[common.Hash]common.Hash // 已验证的存储槽
+}
+
+// ProofReader 提供 eth_getProof，*gethclient.Client 实现了该接口
+type ProofReader interface {
+	GetProof(ctx context.Context, account common.Address, keys []string, blockNumber *big.Int) (*gethclient.AccountResult, error)
+}
+
+// Verifier 从 source 读取证明，并用 reference 提供的区块头进行验证
+type Verifier struct {
+	source    ProofReader
+	reference chain.Client
+}
+
+// NewVerifier 创建验证器。source 和 reference 应来自不同的提供商，否则验证没有意义。
+func NewVerifier(source ProofReader, reference chain.Client) *Verifier {
+	return &Verifier{source: source, reference: reference}
+}
+
+// Account 读取并验证账户在指定区块（nil 表示参考提供商的最新区块）的状态，
+// 同时验证 slots 中列出的存储槽。
+func (v *Verifier) Account(ctx context.Context, addr common.Address, slots []common.Hash, block *big.Int) (*Account, error) {
+	// 以参考提供商的区块头为准，确定具体的区块高度
+	header, err := v.reference.HeaderByNumber(ctx, block)
+	if err != nil {
+		return nil, fmt.Errorf("get reference header: %w", err)
+	}
+
+	keys := make([]string, len(slots))
+	for i, slot := range slots {
+		keys[i] = slot.Hex()
+	}
+	res, err := v.source.GetProof(ctx, addr, keys, header.Number)
+	if err != nil {
+		return nil, fmt.Errorf("get proof: %w", err)
+	}
+
+	// 验证账户证明：key 为 keccak(address)，value 为 RLP 编码的 StateAccount
+	value, err := trie.VerifyProof(header.Root, crypto.Keccak256(addr.Bytes()), proofDB(res.AccountProof))
+	if err != nil {
+		return nil, fmt.Errorf("%w: account proof invalid against block %d state root: %v", ErrMismatch, header.Number, err)
+	}
+	account := types.NewEmptyStateAccount()
+	if value != nil {
+		if err := rlp.DecodeBytes(value, account); err != nil {
+			return nil, fmt.Errorf("decode account: %w", err)
+		}
+	}
+
+	balance := account.Balance.ToBig()
+	if res.Balance.Cmp(balance) != 0 {
+		return nil, fmt.Errorf("%w: reported balance %s, proven balance %s", ErrMismatch, res.Balance, balance)
+	}
+	if res.Nonce != account.Nonce {
+		return nil, fmt.Errorf("%w: reported nonce %d, proven nonce %d", ErrMismatch, res.Nonce, account.Nonce)
+	}
+	if res.StorageHash != account.Root {
+		return nil, fmt.Errorf("%w: reported storage root %s, proven %s", ErrMismatch, res.StorageHash, account.Root)
+	}
+
+	result := &Account{
+		Address:     addr,
+		BlockNumber: header.Number.Uint64(),
+		StateRoot:   header.Root,
+		Nonce:       account.Nonce,
+		Balance:     balance,
+		StorageRoot: account.Root,
+		CodeHash:    common.BytesToHash(account.CodeHash),
+		Storage:     make(map[common.Hash]common.Hash),
+	}
+
+	// 按提供商返回的 key 建立索引：只接受请求过的槽，且每个槽只能有一份证明
+	requested := make(map[common.Hash]bool, len(slots))
+	for _, slot := range slots {
+		requested[slot] = true
+	}
+	proofs := make(map[common.Hash]gethclient.StorageResult, len(res.StorageProof))
+	for _, sp := range res.StorageProof {
+		slot, err := parseSlotKey(sp.Key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed storage proof key %q", ErrMismatch, sp.Key)
+		}
+		if !requested[slot] {
+			return nil, fmt.Errorf("%w: storage proof for unrequested slot %s", ErrMismatch, slot)
+		}
+		if _, dup := proofs[slot]; dup {
+			return nil, fmt.Errorf("%w: duplicate storage proof for slot %s", ErrMismatch, slot)
+		}
+		proofs[slot] = sp
+	}
+
+	// 逐个验证请求的槽，证明的 key 必须与请求一致，不能用其他槽的证明代替
+	for _, slot := range slots {
+		if _, done := result.Storage[slot]; done {
+			continue
+		}
+		sp, ok := proofs[slot]
+		if !ok {
+			return nil, fmt.Errorf("%w: no storage proof for slot %s", ErrMismatch, slot)
+		}
+		value, err := trie.VerifyProof(account.Root, crypto.Keccak256(slot.Bytes()), proofDB(sp.Proof))
+		if err != nil {
+			return nil, fmt.Errorf("%w: storage proof for slot %s invalid: %v", ErrMismatch, slot, err)
+		}
+		// 存储值以去掉前导零的 RLP 字节串保存
+		var proven []byte
+		if value != nil {
+			if err := rlp.DecodeBytes(value, &proven); err != nil {
+				return nil, fmt.Errorf("decode storage slot %s: %w", slot, err)
+			}
+		}
+		if sp.Value == nil {
+			return nil, fmt.Errorf("%w: storage proof for slot %s has no value", ErrMismatch, slot)
+		}
+		if !bytes.Equal(common.TrimLeftZeroes(sp.Value.Bytes()), proven) {
+			return nil, fmt.Errorf("%w: slot %s reported %s, proven %s", ErrMismatch, slot, hexutil.EncodeBig(sp.Value), hexutil.Encode(proven))
+		}
+		result.Storage[slot] = common.BytesToHash(proven)
+	}
+	return result, nil
+}
+
+// parseSlotKey 解析证明中的存储槽 key。提供商可能返回补齐到 32 字节的 key，也可能去掉前导零（如 "0x0"）
+func parseSlotKey(s string) (common.Hash, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(b) > common.HashLength {
+		return common.Hash{}, fmt.Errorf("key longer than %d bytes", common.HashLength)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// proofDB 把十六进制编码的证明节点放入以节点哈希为 key 的内存数据库
+func proofDB(nodes []string) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range nodes {
+		blob := common.FromHex(node)
+		db.Put(crypto.Keccak256(blob), blob)
+	}
+	return db
+}
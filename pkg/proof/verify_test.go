@@ -0,0 +1,197 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+var (
+	account = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	slot0   = common.Hash{}
+	slot1   = common.BigToHash(big.NewInt(1))
+	unset   = common.BigToHash(big.NewInt(5))
+)
+
+// testState 构造只含 account 一个合约账户的状态树：槽 0 为 42，槽 1 为 7
+type testState struct {
+	root    common.Hash
+	storage *trie.Trie
+	state   *trie.Trie
+	account *types.StateAccount
+}
+
+func newTestState(t *testing.T) *testState {
+	t.Helper()
+	db := triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil)
+	storage := trie.NewEmpty(db)
+	for slot, value := range map[common.Hash]int64{slot0: 42, slot1: 7} {
+		enc, err := rlp.EncodeToBytes(common.TrimLeftZeroes(common.BigToHash(big.NewInt(value)).Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := storage.Update(crypto.Keccak256(slot.Bytes()), enc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	acc := &types.StateAccount{Nonce: 1, Balance: uint256.NewInt(1e18), Root: storage.Hash(), CodeHash: crypto.Keccak256([]byte{0x60})}
+	enc, err := rlp.EncodeToBytes(acc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := trie.NewEmpty(db)
+	if err := state.Update(crypto.Keccak256(account.Bytes()), enc); err != nil {
+		t.Fatal(err)
+	}
+	// 再放入一个账户，使账户证明不止一个节点
+	if err := state.Update(crypto.Keccak256(common.Address{2}.Bytes()), enc); err != nil {
+		t.Fatal(err)
+	}
+	return &testState{root: state.Hash(), storage: storage, state: state, account: acc}
+}
+
+// proofNodes 收集 Prove 写入的证明节点
+type proofNodes []string
+
+func (p *proofNodes) Put(key, value []byte) error {
+	*p = append(*p, hexutil.Encode(value))
+	return nil
+}
+
+func (p *proofNodes) Delete(key []byte) error { return nil }
+
+// prove 生成 account 和 keys 中各存储槽的证明，key 原样放入结果
+func (s *testState) prove(t *testing.T, keys []string) *gethclient.AccountResult {
+	t.Helper()
+	var accountProof proofNodes
+	if err := s.state.Prove(crypto.Keccak256(account.Bytes()), &accountProof); err != nil {
+		t.Fatal(err)
+	}
+	res := &gethclient.AccountResult{
+		Address:      account,
+		AccountProof: accountProof,
+		Balance:      s.account.Balance.ToBig(),
+		CodeHash:     common.BytesToHash(s.account.CodeHash),
+		Nonce:        s.account.Nonce,
+		StorageHash:  s.account.Root,
+	}
+	for _, key := range keys {
+		slot := common.HexToHash(key)
+		var nodes proofNodes
+		if err := s.storage.Prove(crypto.Keccak256(slot.Bytes()), &nodes); err != nil {
+			t.Fatal(err)
+		}
+		value := new(big.Int)
+		if enc, err := s.storage.Get(crypto.Keccak256(slot.Bytes())); err != nil {
+			t.Fatal(err)
+		} else if enc != nil {
+			var b []byte
+			if err := rlp.DecodeBytes(enc, &b); err != nil {
+				t.Fatal(err)
+			}
+			value.SetBytes(b)
+		}
+		res.StorageProof = append(res.StorageProof, gethclient.StorageResult{Key: key, Value: value, Proof: nodes})
+	}
+	return res
+}
+
+type proofFunc func(keys []string) *gethclient.AccountResult
+
+func (f proofFunc) GetProof(ctx context.Context, addr common.Address, keys []string, block *big.Int) (*gethclient.AccountResult, error) {
+	return f(keys), nil
+}
+
+func (s *testState) verifier(source proofFunc) *Verifier {
+	reference := &chain.ClientMock{
+		HeaderByNumberFunc: func(ctx context.Context, number *big.Int) (*types.Header, error) {
+			return &types.Header{Number: big.NewInt(100), Root: s.root}, nil
+		},
+	}
+	return NewVerifier(source, reference)
+}
+
+func TestAccount(t *testing.T) {
+	s := newTestState(t)
+	v := s.verifier(func(keys []string) *gethclient.AccountResult {
+		// 提供商可以返回去掉前导零的 key
+		return s.prove(t, []string{"0x0", keys[1], keys[2]})
+	})
+	got, err := v.Account(context.Background(), account, []common.Hash{slot0, slot1, unset}, nil)
+	if err != nil {
+		t.Fatalf("Account: %v", err)
+	}
+	if got.BlockNumber != 100 || got.Nonce != 1 || got.Balance.Cmp(big.NewInt(1e18)) != 0 || got.StorageRoot != s.account.Root {
+		t.Errorf("Account = %+v", got)
+	}
+	want := map[common.Hash]common.Hash{slot0: common.BigToHash(big.NewInt(42)), slot1: common.BigToHash(big.NewInt(7)), unset: {}}
+	for slot, value := range want {
+		if got.Storage[slot] != value {
+			t.Errorf("slot %s = %s, want %s", slot, got.Storage[slot], value)
+		}
+	}
+}
+
+func TestAccountRejects(t *testing.T) {
+	s := newTestState(t)
+	slots := []common.Hash{slot0, slot1}
+	for _, tt := range []struct {
+		name   string
+		source proofFunc
+	}{
+		{"wrong key", func(keys []string) *gethclient.AccountResult {
+			// 用槽 5 的有效证明（值为 0）代替槽 1：数量与请求一致，每个证明本身也都有效
+			return s.prove(t, []string{keys[0], unset.Hex()})
+		}},
+		{"relabelled key", func(keys []string) *gethclient.AccountResult {
+			res := s.prove(t, keys)
+			res.StorageProof[1].Key = unset.Hex()
+			return res
+		}},
+		{"swapped keys", func(keys []string) *gethclient.AccountResult {
+			res := s.prove(t, keys)
+			res.StorageProof[0].Key, res.StorageProof[1].Key = res.StorageProof[1].Key, res.StorageProof[0].Key
+			return res
+		}},
+		{"tampered value", func(keys []string) *gethclient.AccountResult {
+			res := s.prove(t, keys)
+			res.StorageProof[0].Value = big.NewInt(43)
+			return res
+		}},
+		{"tampered balance", func(keys []string) *gethclient.AccountResult {
+			res := s.prove(t, keys)
+			res.Balance = big.NewInt(2e18)
+			return res
+		}},
+		{"missing slot", func(keys []string) *gethclient.AccountResult {
+			return s.prove(t, keys[:1])
+		}},
+		{"duplicate slot", func(keys []string) *gethclient.AccountResult {
+			// 数量与请求一致，但槽 1 没有证明
+			return s.prove(t, []string{keys[0], keys[0]})
+		}},
+		{"extra slot", func(keys []string) *gethclient.AccountResult {
+			return s.prove(t, append(keys, unset.Hex()))
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.verifier(tt.source).Account(context.Background(), account, slots, nil)
+			if !errors.Is(err, ErrMismatch) {
+				t.Errorf("Account = %v, want ErrMismatch", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Package ratelimit 在客户端用令牌桶限制 JSON-RPC 请求的速率，批量导出、日志回填等大量请求的操作
+// 不会超出 RPC 提供商的配额而被限流或封禁 API key。批量请求中的每个调用各消耗一个令牌，
+// 与提供商的计费方式一致。
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Transport 返回在发送前按 l 等待令牌的 http.RoundTripper，next 为 nil 时使用 http.DefaultTransport。
+// 多个客户端共用同一个 Limiter 时共享配额。onWait 在需要等待时调用（可以为 nil），可用于记录日志
+func Transport(next http.RoundTripper, l *rate.Limiter, onWait func(calls int, delay time.Duration)) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next, l: l, onWait: onWait}
+}
+
+type transport struct {
+	next   http.RoundTripper
+	l      *rate.Limiter
+	onWait func(calls int, delay time.Duration)
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	calls := 1
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		calls = countCalls(body)
+	}
+	if err := t.wait(req, calls); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// wait 为 calls 个调用取得令牌。超过桶容量的批量请求分几次取得，调用方取消时归还尚未使用的令牌
+func (t *transport) wait(req *http.Request, calls int) error {
+	burst := t.l.Burst()
+	if burst <= 0 {
+		return errors.New("rate limiter has zero burst")
+	}
+	for n := calls; n > 0; {
+		k := min(n, burst)
+		n -= k
+		r := t.l.ReserveN(time.Now(), k)
+		delay := r.Delay()
+		if delay == 0 {
+			continue
+		}
+		if t.onWait != nil {
+			t.onWait(calls, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			r.Cancel()
+			return req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// countCalls 返回请求体中 JSON-RPC 调用的个数：批量请求为数组长度，其他为 1
+func countCalls(body []byte) int {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		return 1
+	}
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil || len(batch) == 0 {
+		return 1
+	}
+	return len(batch)
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/internal/rpctest"
+	"golang.org/x/time/rate"
+)
+
+// node 返回对所有请求（包括批量请求中的每个调用）都回复 0x1 的端点，统计收到的 HTTP 请求数
+func node(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := rpctest.Serve(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if countCalls(readAll(r)) > 1 {
+			w.Write([]byte(`[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":2,"result":"0x1"},{"jsonrpc":"2.0","id":3,"result":"0x1"}]`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}))
+	return srv, &hits
+}
+
+func readAll(r *http.Request) []byte {
+	var buf [4096]byte
+	n, _ := r.Body.Read(buf[:])
+	return buf[:n]
+}
+
+func TestLimitsRate(t *testing.T) {
+	srv, hits := node(t)
+	var waits atomic.Int32
+	c := rpctest.Dial(t, srv.URL, Transport(nil, rate.NewLimiter(50, 1), func(int, time.Duration) { waits.Add(1) }))
+
+	start := time.Now()
+	for range 6 {
+		var out string
+		if err := c.Call(&out, "eth_blockNumber"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 第一个请求使用桶中的令牌，之后每个间隔 20 毫秒
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("6 calls at 50/s took %s, want at least 100ms", elapsed)
+	}
+	if hits.Load() != 6 || waits.Load() == 0 {
+		t.Errorf("hits = %d, waits = %d", hits.Load(), waits.Load())
+	}
+}
+
+func TestBatchCountsEachCall(t *testing.T) {
+	srv, _ := node(t)
+	l := rate.NewLimiter(1, 2)
+	c := rpctest.Dial(t, srv.URL, Transport(nil, l, nil))
+	batch := make([]rpc.BatchElem, 3)
+	for i := range batch {
+		batch[i] = rpc.BatchElem{Method: "eth_blockNumber", Result: new(string)}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.BatchCallContext(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	// 3 个调用超过容量 2：用完桶中的 2 个令牌后还要等 1 个，此时桶中没有剩余
+	if tokens := l.Tokens(); tokens > 0.5 {
+		t.Errorf("tokens left after a batch of 3 = %.2f, want about 0", tokens)
+	}
+}
+
+func TestContextCancelsWait(t *testing.T) {
+	srv, hits := node(t)
+	c := rpctest.Dial(t, srv.URL, Transport(nil, rate.NewLimiter(rate.Every(time.Hour), 1), nil))
+	var out string
+	if err := c.Call(&out, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.CallContext(ctx, &out, "eth_blockNumber"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second call = %v, want deadline exceeded", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("hits = %d, want 1", hits.Load())
+	}
+}
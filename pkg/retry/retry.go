@@ -0,0 +1,218 @@
+// Package retry 在 HTTP 传输层重试 JSON-RPC 请求中的暂时性失败：连接错误（连接被重置、拒绝、超时）、
+// 限流（HTTP 429、JSON-RPC -32005 或 429）和网关错误（HTTP 502/503/504），间隔按指数退避增长并加入
+// 随机抖动（full jitter），不超过 MaxDelay，服务端给出 Retry-After 时以它为准。等待期间调用方取消或超时
+// 会立即返回。重发的 eth_sendRawTransaction 如果因为前一次已经送达而返回 "already known"，
+// 会被当作成功，返回交易哈希。
+package retry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Policy 是重试参数，零值使用默认值
+type Policy struct {
+	Attempts  int           // 最多发送的次数（包括第一次），默认 5；为 1 时不重试
+	BaseDelay time.Duration // 第一次重试前等待时间的上限，之后每次翻倍，默认 250 毫秒
+	MaxDelay  time.Duration // 单次等待的上限，默认 10 秒
+
+	// OnRetry 在每次重试之前调用（可选），attempt 是即将进行的第几次尝试，err 是上一次失败的原因
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+// Transport 返回按 p 重试的 http.RoundTripper，next 为 nil 时使用 http.DefaultTransport
+func Transport(next http.RoundTripper, p Policy) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if p.Attempts <= 0 {
+		p.Attempts = 5
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 250 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = 10 * time.Second
+	}
+	return &transport{next: next, p: p}
+}
+
+type transport struct {
+	next http.RoundTripper
+	p    Policy
+}
+
+// Backoff 返回第 n 次重试（从 1 开始）前的等待时间：在 [0, min(MaxDelay, BaseDelay*2^(n-1))) 中均匀随机
+func (p Policy) Backoff(n int) time.Duration {
+	limit := p.MaxDelay
+	if n <= 30 {
+		limit = min(p.BaseDelay<<(n-1), p.MaxDelay)
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit)
+}
+
+// failure 是可以重试的失败，retryAfter 是服务端要求的等待时间（没有时为 0）
+type failure struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (f *failure) Error() string { return f.err.Error() }
+func (f *failure) Unwrap() error { return f.err }
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := t.try(req, body, attempt > 1)
+		var f *failure
+		if err == nil || !errors.As(err, &f) || attempt >= t.p.Attempts || ctx.Err() != nil {
+			return resp, err
+		}
+		delay := t.p.Backoff(attempt)
+		if f.retryAfter > 0 {
+			delay = min(f.retryAfter, t.p.MaxDelay)
+		}
+		if t.p.OnRetry != nil {
+			t.p.OnRetry(attempt+1, delay, f.err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("%w (last error: %v)", ctx.Err(), f.err)
+		case <-timer.C:
+		}
+	}
+}
+
+// try 发送一次请求。可以重试的失败以 *failure 返回，其他错误和响应原样返回
+func (t *transport) try(req *http.Request, body []byte, resend bool) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	if body != nil {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		return nil, &failure{err: err}
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		resp.Body.Close()
+		return nil, &failure{err: fmt.Errorf("HTTP %s", resp.Status), retryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	}
+	if resp.StatusCode/100 != 2 {
+		return resp, nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, &failure{err: err}
+	}
+	if code, ok := rateLimited(data); ok {
+		return nil, &failure{err: fmt.Errorf("rate limited (JSON-RPC error %d)", code)}
+	}
+	if resend {
+		data = alreadyKnown(body, data)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	return resp, nil
+}
+
+// retryAfter 解析以秒为单位的 Retry-After 头，不支持 HTTP 日期格式
+func retryAfter(v string) time.Duration {
+	secs, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// message 是 JSON-RPC 请求或响应中用到的字段
+type message struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method,omitempty"`
+	Params  []json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func decodeMessages(data []byte) []message {
+	var batch []message
+	if err := json.Unmarshal(data, &batch); err == nil {
+		return batch
+	}
+	var single message
+	if err := json.Unmarshal(data, &single); err != nil {
+		return nil
+	}
+	return []message{single}
+}
+
+// rateLimited 报告 JSON-RPC 响应中是否有限流错误：EIP-1474 的 -32005，或部分提供商（如 Alchemy）使用的 429
+func rateLimited(data []byte) (int, bool) {
+	for _, m := range decodeMessages(data) {
+		if m.Error != nil && (m.Error.Code == -32005 || m.Error.Code == 429) {
+			return m.Error.Code, true
+		}
+	}
+	return 0, false
+}
+
+// alreadyKnown 处理重发的单个 eth_sendRawTransaction：节点因为已经收到过该交易而返回错误时，
+// 说明前一次请求已经送达，改写为返回交易哈希的成功响应
+func alreadyKnown(reqBody, respBody []byte) []byte {
+	var req, resp message
+	if json.Unmarshal(reqBody, &req) != nil || req.Method != "eth_sendRawTransaction" || len(req.Params) != 1 ||
+		json.Unmarshal(respBody, &resp) != nil || resp.Error == nil {
+		return respBody
+	}
+	msg := strings.ToLower(resp.Error.Message)
+	if !strings.Contains(msg, "already known") && !strings.Contains(msg, "known transaction") {
+		return respBody
+	}
+	var raw hexutil.Bytes
+	var tx types.Transaction
+	if json.Unmarshal(req.Params[0], &raw) != nil || tx.UnmarshalBinary(raw) != nil {
+		return respBody
+	}
+	hash, _ := json.Marshal(tx.Hash())
+	out, err := json.Marshal(message{JSONRPC: "2.0", ID: resp.ID, Result: hash})
+	if err != nil {
+		return respBody
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/internal/rpctest"
+)
+
+// fast 是测试用的短间隔策略
+var fast = Policy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// flaky 返回前 failures 次请求用 fail 响应、之后返回 ok 的端点
+func flaky(t *testing.T, failures int32, fail, ok http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := rpctest.Serve(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= failures {
+			fail(w, r)
+			return
+		}
+		ok(w, r)
+	}))
+	return srv, &hits
+}
+
+func blockNumber(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x2a"}`))
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	failures := map[string]http.HandlerFunc{
+		"HTTP 429": func(w http.ResponseWriter, r *http.Request) { http.Error(w, "slow down", http.StatusTooManyRequests) },
+		"HTTP 503": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		},
+		"-32005": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit exceeded"}}`))
+		},
+		"connection reset": func(w http.ResponseWriter, r *http.Request) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		},
+	}
+	for name, fail := range failures {
+		t.Run(name, func(t *testing.T) {
+			srv, hits := flaky(t, 2, fail, blockNumber)
+			var retries []int
+			p := fast
+			p.OnRetry = func(attempt int, delay time.Duration, err error) { retries = append(retries, attempt) }
+			n, err := ethclient.NewClient(rpctest.Dial(t, srv.URL, Transport(nil, p))).BlockNumber(context.Background())
+			if err != nil || n != 42 {
+				t.Fatalf("BlockNumber = %d, %v", n, err)
+			}
+			if hits.Load() != 3 || len(retries) != 2 || retries[1] != 3 {
+				t.Errorf("hits = %d, retries = %v, want 3 hits and attempts [2 3]", hits.Load(), retries)
+			}
+		})
+	}
+}
+
+func TestGivesUp(t *testing.T) {
+	srv, hits := flaky(t, 100, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}, blockNumber)
+	p := fast
+	p.Attempts = 3
+	if _, err := ethclient.NewClient(rpctest.Dial(t, srv.URL, Transport(nil, p))).BlockNumber(context.Background()); err == nil {
+		t.Fatal("BlockNumber succeeded against a failing endpoint")
+	}
+	if hits.Load() != 3 {
+		t.Errorf("hits = %d, want 3", hits.Load())
+	}
+
+	// 不是暂时性的错误不重试
+	srv, hits = flaky(t, 100, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+	}, blockNumber)
+	if _, err := ethclient.NewClient(rpctest.Dial(t, srv.URL, Transport(nil, fast))).BlockNumber(context.Background()); err == nil || hits.Load() != 1 {
+		t.Errorf("BlockNumber = %v after %d requests, want the error after 1", err, hits.Load())
+	}
+}
+
+func TestContextCancelsBackoff(t *testing.T) {
+	srv, _ := flaky(t, 100, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}, blockNumber)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := ethclient.NewClient(rpctest.Dial(t, srv.URL, Transport(nil, Policy{MaxDelay: time.Minute}))).BlockNumber(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("BlockNumber = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %s after the context expired", elapsed)
+	}
+}
+
+// TestResentTransactionAlreadyKnown 模拟第一次发送已送达但响应丢失：重发时节点返回 already known，视为成功
+func TestResentTransactionAlreadyKnown(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0xaa")
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &to,
+	})
+	srv, _ := flaky(t, 1, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"already known"}}`))
+	})
+	if err := ethclient.NewClient(rpctest.Dial(t, srv.URL, Transport(nil, fast))).SendTransaction(context.Background(), tx); err != nil {
+		t.Errorf("SendTransaction after a lost response = %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for n, limit := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second, 100: time.Second} {
+		for range 20 {
+			if d := p.Backoff(n); d < 0 || d >= limit {
+				t.Fatalf("Backoff(%d) = %s, want [0, %s)", n, d, limit)
+			}
+		}
+	}
+}
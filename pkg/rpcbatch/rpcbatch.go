@@ -0,0 +1,75 @@
+// Package rpcbatch 把大量同类的 JSON-RPC 请求（如上百个交易收据、多个区块上的余额）放进
+// 批量请求（rpc.Client.BatchCallContext）中发送，用几次 HTTP 往返代替逐个请求。
+package rpcbatch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultSize 是单个批量请求中的最多请求数，多数提供商限制在 100 到 1000 之间
+const DefaultSize = 100
+
+// Caller 是发送批量请求的客户端，*rpc.Client 满足该接口
+type Caller interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// Do 按 size（0 表示 DefaultSize）把 elems 分成多个批量请求依次发送。
+// 返回的错误只表示传输失败；单个请求的错误记录在对应元素的 Error 中。
+func Do(ctx context.Context, client Caller, elems []rpc.BatchElem, size int) error {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	for start := 0; start < len(elems); start += size {
+		if err := client.BatchCallContext(ctx, elems[start:min(start+size, len(elems))]); err != nil {
+			return fmt.Errorf("batch request: %w", err)
+		}
+	}
+	return nil
+}
+
+// Receipts 批量读取交易收据，结果与 hashes 一一对应，尚未打包或不存在的交易为 nil
+func Receipts(ctx context.Context, client Caller, hashes []common.Hash) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{hash}, Result: &receipts[i]}
+	}
+	if err := Do(ctx, client, elems, 0); err != nil {
+		return nil, err
+	}
+	for i, elem := range elems {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("get receipt of %s: %w", hashes[i].Hex(), elem.Error)
+		}
+	}
+	return receipts, nil
+}
+
+// BalancesAt 批量读取 account 在 blocks 各区块上的余额，结果与 blocks 一一对应。
+// 较早的区块需要归档节点，普通节点会对这些请求返回错误。
+func BalancesAt(ctx context.Context, client Caller, account common.Address, blocks []uint64) ([]*big.Int, error) {
+	results := make([]hexutil.Big, len(blocks))
+	elems := make([]rpc.BatchElem, len(blocks))
+	for i, block := range blocks {
+		elems[i] = rpc.BatchElem{Method: "eth_getBalance", Args: []interface{}{account, hexutil.Uint64(block)}, Result: &results[i]}
+	}
+	if err := Do(ctx, client, elems, 0); err != nil {
+		return nil, err
+	}
+	balances := make([]*big.Int, len(blocks))
+	for i, elem := range elems {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("get balance at block %d: %w", blocks[i], elem.Error)
+		}
+		balances[i] = results[i].ToInt()
+	}
+	return balances, nil
+}
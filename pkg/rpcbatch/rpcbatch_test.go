@@ -0,0 +1,99 @@
+package rpcbatch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeEth 是进程内的 eth 命名空间实现：哈希第一个字节为 0 的交易不存在，
+// 余额等于区块号乘以 1 gwei，区块 999 以后返回 missing trie node（非归档节点）
+type fakeEth struct{}
+
+func (fakeEth) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	if hash[0] == 0 {
+		return nil
+	}
+	return &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      hash,
+		BlockNumber: big.NewInt(int64(hash[0])),
+		GasUsed:     21000,
+		Logs:        []*types.Log{},
+	}
+}
+
+func (fakeEth) GetBalance(addr common.Address, block hexutil.Uint64) (*hexutil.Big, error) {
+	if block >= 999 {
+		return nil, errors.New("missing trie node")
+	}
+	return (*hexutil.Big)(new(big.Int).Mul(big.NewInt(int64(block)), big.NewInt(1e9))), nil
+}
+
+// countingClient 记录发送的批量请求数
+type countingClient struct {
+	*rpc.Client
+	batches int
+}
+
+func (c *countingClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	c.batches++
+	return c.Client.BatchCallContext(ctx, b)
+}
+
+func newClient(t *testing.T) *countingClient {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", fakeEth{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	client := rpc.DialInProc(server)
+	t.Cleanup(client.Close)
+	return &countingClient{Client: client}
+}
+
+func TestReceipts(t *testing.T) {
+	client := newClient(t)
+	hashes := make([]common.Hash, 250)
+	for i := range hashes {
+		hashes[i] = common.Hash{byte(i % 256), 1}
+	}
+	receipts, err := Receipts(context.Background(), client, hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.batches != 3 {
+		t.Errorf("sent %d batches, want 3", client.batches)
+	}
+	if receipts[0] != nil {
+		t.Errorf("receipt of a missing transaction = %+v, want nil", receipts[0])
+	}
+	for i := 1; i < len(hashes); i++ {
+		if receipts[i] == nil || receipts[i].TxHash != hashes[i] || receipts[i].GasUsed != 21000 {
+			t.Fatalf("receipt %d = %+v", i, receipts[i])
+		}
+	}
+}
+
+func TestBalancesAt(t *testing.T) {
+	client := newClient(t)
+	balances, err := BalancesAt(context.Background(), client, common.Address{1}, []uint64{1, 10, 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int64{1e9, 10e9, 100e9} {
+		if balances[i].Int64() != want {
+			t.Errorf("balance %d = %s, want %d", i, balances[i], want)
+		}
+	}
+	if _, err := BalancesAt(context.Background(), client, common.Address{1}, []uint64{1, 1000}); err == nil {
+		t.Error("BalancesAt succeeded although one request failed")
+	}
+}
@@ -0,0 +1,156 @@
+// Package rpcbench 比较批量读取余额的几种策略：逐个请求、JSON-RPC 批量请求和 Multicall3
+// 合约聚合调用，用于量化不同查询方式在某个提供商上的延迟差异。
+package rpcbench
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/pkg/multicall"
+)
+
+// Strategy 是一种查询策略
+type Strategy string
+
+// 支持的查询策略
+const (
+	Sequential Strategy = "sequential" // 每个地址一次 eth_getBalance 请求
+	Batched    Strategy = "batched"    // 所有 eth_getBalance 放在一个 JSON-RPC 批量请求中
+	Multicall  Strategy = "multicall"  // 一次 eth_call 调用 Multicall3.getEthBalance 聚合
+)
+
+// Strategies 列出所有策略，按预期从慢到快排列
+var Strategies = []Strategy{Sequential, Batched, Multicall}
+
+// ParseStrategy 解析策略名称
+func ParseStrategy(s string) (Strategy, error) {
+	if !slices.Contains(Strategies, Strategy(s)) {
+		return "", fmt.Errorf("unknown strategy %q (want sequential, batched or multicall)", s)
+	}
+	return Strategy(s), nil
+}
+
+// MulticallAddress 是 Multicall 策略使用的 Multicall3 地址，自定义链可能部署在其他地址
+var MulticallAddress = multicall.DefaultAddress
+
+// Balances 使用指定策略读取 addrs 在 block 区块的余额，结果与 addrs 一一对应
+func Balances(ctx context.Context, client *rpc.Client, strategy Strategy, addrs []common.Address, block *big.Int) ([]*big.Int, error) {
+	switch strategy {
+	case Sequential:
+		return sequentialBalances(ctx, client, addrs, block)
+	case Batched:
+		return batchedBalances(ctx, client, addrs, block)
+	case Multicall:
+		return multicallBalances(ctx, client, addrs, block)
+	}
+	return nil, fmt.Errorf("unknown strategy %q", strategy)
+}
+
+func sequentialBalances(ctx context.Context, client *rpc.Client, addrs []common.Address, block *big.Int) ([]*big.Int, error) {
+	ec := ethclient.NewClient(client)
+	balances := make([]*big.Int, len(addrs))
+	for i, addr := range addrs {
+		balance, err := ec.BalanceAt(ctx, addr, block)
+		if err != nil {
+			return nil, fmt.Errorf("get balance of %s: %w", addr.Hex(), err)
+		}
+		balances[i] = balance
+	}
+	return balances, nil
+}
+
+func batchedBalances(ctx context.Context, client *rpc.Client, addrs []common.Address, block *big.Int) ([]*big.Int, error) {
+	results := make([]hexutil.Big, len(addrs))
+	batch := make([]rpc.BatchElem, len(addrs))
+	for i, addr := range addrs {
+		batch[i] = rpc.BatchElem{Method: "eth_getBalance", Args: []interface{}{addr, blockArg(block)}, Result: &results[i]}
+	}
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("batch eth_getBalance: %w", err)
+	}
+	balances := make([]*big.Int, len(addrs))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("get balance of %s: %w", addrs[i].Hex(), elem.Error)
+		}
+		balances[i] = results[i].ToInt()
+	}
+	return balances, nil
+}
+
+func multicallBalances(ctx context.Context, client *rpc.Client, addrs []common.Address, block *big.Int) ([]*big.Int, error) {
+	return multicall.New(ethclient.NewClient(client), MulticallAddress).Balances(ctx, addrs, block)
+}
+
+// blockArg 把区块号转换为 JSON-RPC 参数，nil 表示最新区块
+func blockArg(block *big.Int) string {
+	if block == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(block)
+}
+
+// Result 是一种策略多轮运行的延迟统计
+type Result struct {
+	Strategy Strategy
+	Calls    int // 每轮查询的地址数
+	Rounds   int
+	Min      time.Duration
+	Mean     time.Duration
+	P50      time.Duration
+	P95      time.Duration
+	Max      time.Duration
+}
+
+// PerCall 返回平均到每个地址的耗时
+func (r *Result) PerCall() time.Duration {
+	if r.Calls == 0 {
+		return 0
+	}
+	return r.Mean / time.Duration(r.Calls)
+}
+
+// Run 用指定策略重复读取 rounds 轮并统计延迟。所有轮次都在同一个区块上查询，
+// 保证各策略读到的数据相同。
+func Run(ctx context.Context, client *rpc.Client, strategy Strategy, addrs []common.Address, block *big.Int, rounds int) (*Result, error) {
+	if rounds <= 0 {
+		return nil, fmt.Errorf("rounds must be positive, got %d", rounds)
+	}
+	durations := make([]time.Duration, rounds)
+	var total time.Duration
+	for i := range durations {
+		start := time.Now()
+		if _, err := Balances(ctx, client, strategy, addrs, block); err != nil {
+			return nil, err
+		}
+		durations[i] = time.Since(start)
+		total += durations[i]
+	}
+	slices.Sort(durations)
+	return &Result{
+		Strategy: strategy,
+		Calls:    len(addrs),
+		Rounds:   rounds,
+		Min:      durations[0],
+		Mean:     total / time.Duration(rounds),
+		P50:      percentile(durations, 50),
+		P95:      percentile(durations, 95),
+		Max:      durations[rounds-1],
+	}, nil
+}
+
+// percentile 返回已排序样本的第 p 百分位（最近秩法）
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
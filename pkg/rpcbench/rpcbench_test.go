@@ -0,0 +1,156 @@
+package rpcbench
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/internal/multicalltest"
+	"github.com/local/go-eth-demo/pkg/multicall"
+)
+
+// fakeEth 是进程内的 eth 命名空间实现，余额由地址确定，并模拟 Multicall3 的 aggregate3
+type fakeEth struct{}
+
+func fakeBalance(addr common.Address) *big.Int {
+	return new(big.Int).Mul(big.NewInt(int64(addr[19])+1), big.NewInt(1e15))
+}
+
+func (fakeEth) GetBalance(addr common.Address, block string) *hexutil.Big {
+	return (*hexutil.Big)(fakeBalance(addr))
+}
+
+type callArgs struct {
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+}
+
+func (fakeEth) Call(args callArgs, block string) (hexutil.Bytes, error) {
+	if args.To == nil || *args.To != MulticallAddress || len(args.Input) < 4 {
+		return nil, nil
+	}
+	method := multicall.ABI.Methods["aggregate3"]
+	values, err := method.Inputs.Unpack(args.Input[4:])
+	if err != nil {
+		return nil, err
+	}
+	calls := *abi.ConvertType(values[0], new([]multicalltest.Call3)).(*[]multicalltest.Call3)
+	results := make([]multicall.Result, len(calls))
+	for i, c := range calls {
+		arg, err := multicall.ABI.Methods["getEthBalance"].Inputs.Unpack(c.CallData[4:])
+		if err != nil {
+			return nil, err
+		}
+		results[i] = multicall.Result{Success: true, ReturnData: common.LeftPadBytes(fakeBalance(arg[0].(common.Address)).Bytes(), 32)}
+	}
+	return method.Outputs.Pack(results)
+}
+
+// benchClient 连接 BENCH_RPC_URL（设置时）或进程内的模拟节点
+func benchClient(tb testing.TB) *rpc.Client {
+	tb.Helper()
+	if url := os.Getenv("BENCH_RPC_URL"); url != "" {
+		client, err := rpc.Dial(url)
+		if err != nil {
+			tb.Fatalf("dial %s: %v", url, err)
+		}
+		tb.Cleanup(client.Close)
+		return client
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", fakeEth{}); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(server.Stop)
+	client := rpc.DialInProc(server)
+	tb.Cleanup(client.Close)
+	return client
+}
+
+// testAddresses 生成 n 个确定的地址
+func testAddresses(n int) []common.Address {
+	addrs := make([]common.Address, n)
+	for i := range addrs {
+		addrs[i] = common.BytesToAddress(crypto.Keccak256([]byte(fmt.Sprint("rpcbench", i))))
+	}
+	return addrs
+}
+
+func TestStrategiesAgree(t *testing.T) {
+	client := benchClient(t)
+	addrs := testAddresses(25)
+	want, err := Balances(context.Background(), client, Sequential, addrs, nil)
+	if err != nil {
+		t.Fatalf("sequential: %v", err)
+	}
+	for _, strategy := range Strategies[1:] {
+		got, err := Balances(context.Background(), client, strategy, addrs, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", strategy, err)
+		}
+		for i := range want {
+			if got[i].Cmp(want[i]) != 0 {
+				t.Errorf("%s: balance of %s = %s, want %s", strategy, addrs[i].Hex(), got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	result, err := Run(context.Background(), benchClient(t), Batched, testAddresses(3), nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rounds != 10 || result.Calls != 3 {
+		t.Errorf("unexpected result shape: %+v", result)
+	}
+	if !(result.Min <= result.P50 && result.P50 <= result.P95 && result.P95 <= result.Max) {
+		t.Errorf("percentiles out of order: %+v", result)
+	}
+	if _, err := Run(context.Background(), benchClient(t), Batched, nil, nil, 0); err == nil {
+		t.Error("Run with zero rounds succeeded")
+	}
+}
+
+func TestMulticallNotDeployed(t *testing.T) {
+	server := rpc.NewServer()
+	server.RegisterName("eth", emptyCallEth{})
+	client := rpc.DialInProc(server)
+	defer client.Close()
+	_, err := Balances(context.Background(), client, Multicall, testAddresses(1), nil)
+	if err == nil || !strings.Contains(err.Error(), "not deployed") {
+		t.Fatalf("expected not-deployed error, got %v", err)
+	}
+}
+
+// emptyCallEth 模拟没有部署 Multicall3 的链：eth_call 返回空数据
+type emptyCallEth struct{}
+
+func (emptyCallEth) Call(args callArgs, block string) hexutil.Bytes { return nil }
+
+// BenchmarkBalances 对比三种策略。默认使用进程内节点，衡量客户端编码和批处理的开销；
+// 设置 BENCH_RPC_URL 时测量真实提供商的端到端延迟。
+func BenchmarkBalances(b *testing.B) {
+	client := benchClient(b)
+	for _, n := range []int{1, 10, 100} {
+		addrs := testAddresses(n)
+		for _, strategy := range Strategies {
+			b.Run(fmt.Sprintf("%s/%d", strategy, n), func(b *testing.B) {
+				ctx := context.Background()
+				for b.Loop() {
+					if _, err := Balances(ctx, client, strategy, addrs, nil); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
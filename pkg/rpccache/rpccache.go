@@ -0,0 +1,462 @@
+// Package rpccache 在 HTTP 传输层缓存不会再变化的 JSON-RPC 结果，重复运行任务或浏览命令时不再重新获取：
+// 链 ID、按哈希查询的区块、已最终确定（finalized）的区块、收据、交易、代码和日志。这些结果缓存在内存中，
+// 设置了目录时同时写入磁盘，下次运行继续使用。最新区块号、gas 价格等 "latest" 类数据可以按 TTL 短暂缓存，
+// 只保存在内存中。错误和空结果（如尚未上链的收据）不缓存，批量请求原样转发。
+//
+// 区块是否已最终确定以节点的 finalized 区块为准（每 12 秒刷新一次），不支持 finalized 标签的节点
+// 只缓存链 ID 和按哈希查询的结果。磁盘上的条目按端点和创世区块哈希区分，重启后重新生成的本地开发链不会读到旧数据。
+package rpccache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// finalizedRefresh 是重新查询 finalized 区块号的间隔
+const finalizedRefresh = 12 * time.Second
+
+// Options 是缓存参数
+type Options struct {
+	Dir        string        // 持久化目录，为空时只缓存在内存中
+	TTL        time.Duration // "latest" 类数据的缓存时间，0 表示不缓存
+	MaxEntries int           // 内存中最多保存的条目数，默认 10000
+
+	// OnHit 在请求由缓存回答时调用（可选）
+	OnHit func(method string)
+}
+
+// Cache 是可以被多个 Transport 共用的缓存，并发安全
+type Cache struct {
+	opts Options
+
+	mu        sync.Mutex
+	entries   map[string]entry
+	endpoints map[string]*endpoint
+}
+
+type entry struct {
+	result  json.RawMessage
+	expires time.Time // 零值表示不会过期
+}
+
+// endpoint 是单个端点的链信息
+type endpoint struct {
+	mu          sync.Mutex
+	genesis     string // 创世区块哈希，查询失败时为空，此时不使用磁盘缓存
+	genesisDone bool
+	finalized   uint64
+	checkedAt   time.Time
+}
+
+// New 创建缓存
+func New(opts Options) *Cache {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 10000
+	}
+	return &Cache{opts: opts, entries: make(map[string]entry), endpoints: make(map[string]*endpoint)}
+}
+
+// Transport 返回先查缓存的 http.RoundTripper，未命中时交给 next（为 nil 时使用 http.DefaultTransport）
+func (c *Cache) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{cache: c, next: next}
+}
+
+type transport struct {
+	cache *Cache
+	next  http.RoundTripper
+}
+
+// message 是 JSON-RPC 请求或响应中用到的字段
+type message struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method,omitempty"`
+	Params  []json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   json.RawMessage   `json:"error,omitempty"`
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Method != http.MethodPost {
+		return t.next.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	var m message
+	if json.Unmarshal(body, &m) != nil || !cacheable(m.Method) {
+		return t.next.RoundTrip(req)
+	}
+	ep := t.cache.endpoint(req.URL.String())
+	key := requestKey(req.URL.String(), m)
+	if result, ok := t.cache.lookup(key, t.diskKey(req, ep, key)); ok {
+		if t.cache.opts.OnHit != nil {
+			t.cache.opts.OnHit(m.Method)
+		}
+		return respond(req, m.ID, result), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+
+	var out message
+	if json.Unmarshal(data, &out) != nil || out.Error != nil || len(out.Result) == 0 || string(out.Result) == "null" {
+		return resp, nil
+	}
+	switch t.lifetime(req, ep, m, out.Result) {
+	case immutable:
+		t.cache.store(key, t.diskKey(req, ep, key), out.Result, time.Time{})
+	case latest:
+		if t.cache.opts.TTL > 0 {
+			t.cache.store(key, "", out.Result, time.Now().Add(t.cache.opts.TTL))
+		}
+	}
+	return resp, nil
+}
+
+// respond 构造由缓存结果组成的响应，id 与请求一致
+func respond(req *http.Request, id, result json.RawMessage) *http.Response {
+	data, _ := json.Marshal(message{JSONRPC: "2.0", ID: id, Result: result})
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
+
+// requestKey 由端点、方法和参数组成缓存键，端点 URL 中可能含有 API key，只保存哈希
+func requestKey(url string, m message) string {
+	h := sha256.New()
+	h.Write([]byte(url))
+	h.Write([]byte{0})
+	h.Write([]byte(m.Method))
+	for _, p := range m.Params {
+		var buf bytes.Buffer
+		if json.Compact(&buf, p) != nil {
+			buf.Reset()
+			buf.Write(p)
+		}
+		h.Write([]byte{0})
+		h.Write(bytes.ToLower(buf.Bytes()))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// diskKey 返回磁盘缓存的键，在 key 中加入创世区块哈希。未设置目录或无法获取创世区块时返回空字符串
+func (t *transport) diskKey(req *http.Request, ep *endpoint, key string) string {
+	if t.cache.opts.Dir == "" {
+		return ""
+	}
+	genesis := t.genesis(req, ep)
+	if genesis == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(genesis + key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) endpoint(url string) *endpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ep, ok := c.endpoints[url]
+	if !ok {
+		ep = &endpoint{}
+		c.endpoints[url] = ep
+	}
+	return ep
+}
+
+func (c *Cache) lookup(key, diskKey string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && !e.expires.IsZero() && time.Now().After(e.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if ok {
+		return e.result, true
+	}
+	if diskKey == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(diskKey))
+	if err != nil || !json.Valid(data) {
+		return nil, false
+	}
+	c.remember(key, entry{result: data})
+	return data, true
+}
+
+// store 保存结果，diskKey 不为空时同时写入磁盘。写磁盘失败不影响请求，只是下次运行不能命中
+func (c *Cache) store(key, diskKey string, result json.RawMessage, expires time.Time) {
+	result = bytes.Clone(result)
+	c.remember(key, entry{result: result, expires: expires})
+	if diskKey == "" {
+		return
+	}
+	path := c.path(diskKey)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(result)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// remember 把条目放入内存，超出 MaxEntries 时先清理过期条目，仍然不够再随机淘汰
+func (c *Cache) remember(key string, e entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.opts.MaxEntries {
+		now := time.Now()
+		for k, old := range c.entries {
+			if !old.expires.IsZero() && now.After(old.expires) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.opts.MaxEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = e
+}
+
+func (c *Cache) path(diskKey string) string {
+	return filepath.Join(c.opts.Dir, diskKey[:2], diskKey+".json")
+}
+
+// lifetime 表示结果可以缓存多久
+type lifetime int
+
+const (
+	uncached  lifetime = iota
+	latest             // 按 TTL 缓存
+	immutable          // 永久缓存
+)
+
+// blockParam 是以区块号、标签或区块哈希作为参数的方法及参数位置
+var blockParam = map[string]int{
+	"eth_getBlockByNumber":                    0,
+	"eth_getBlockReceipts":                    0,
+	"eth_getBlockTransactionCountByNumber":    0,
+	"eth_getTransactionByBlockNumberAndIndex": 0,
+	"eth_getUncleCountByBlockNumber":          0,
+	"eth_getCode":                             1,
+	"eth_getBalance":                          1,
+	"eth_getTransactionCount":                 1,
+	"eth_call":                                1,
+	"eth_getStorageAt":                        2,
+	"eth_getProof":                            2,
+}
+
+// cacheable 报告方法的结果是否可能被缓存
+func cacheable(method string) bool {
+	switch method {
+	case "eth_chainId", "net_version",
+		"eth_getBlockByHash", "eth_getBlockTransactionCountByHash", "eth_getTransactionByBlockHashAndIndex",
+		"eth_getTransactionByHash", "eth_getTransactionReceipt", "eth_getLogs",
+		"eth_blockNumber", "eth_gasPrice", "eth_maxPriorityFeePerGas", "eth_blobBaseFee":
+		return true
+	}
+	_, ok := blockParam[method]
+	return ok
+}
+
+// lifetime 判断结果可以缓存多久。按区块号查询的结果只有区块已最终确定时才不会再变化，
+// 收据和交易按结果中的区块号判断
+func (t *transport) lifetime(req *http.Request, ep *endpoint, m message, result json.RawMessage) lifetime {
+	switch m.Method {
+	case "eth_chainId", "net_version",
+		"eth_getBlockByHash", "eth_getBlockTransactionCountByHash", "eth_getTransactionByBlockHashAndIndex":
+		return immutable
+	case "eth_blockNumber", "eth_gasPrice", "eth_maxPriorityFeePerGas", "eth_blobBaseFee":
+		return latest
+	case "eth_getTransactionByHash", "eth_getTransactionReceipt":
+		var r struct {
+			BlockNumber string `json:"blockNumber"`
+		}
+		if json.Unmarshal(result, &r) != nil {
+			return uncached
+		}
+		if n, ok := parseNumber(r.BlockNumber); ok && t.finalized(req, ep, n) {
+			return immutable
+		}
+		return uncached
+	case "eth_getLogs":
+		if len(m.Params) != 1 {
+			return uncached
+		}
+		var f struct {
+			BlockHash string `json:"blockHash"`
+			ToBlock   string `json:"toBlock"`
+		}
+		if json.Unmarshal(m.Params[0], &f) != nil {
+			return uncached
+		}
+		if f.BlockHash != "" {
+			return immutable
+		}
+		if n, ok := parseNumber(f.ToBlock); ok && t.finalized(req, ep, n) {
+			return immutable
+		}
+		return uncached
+	}
+	i, ok := blockParam[m.Method]
+	if !ok || i >= len(m.Params) {
+		return uncached
+	}
+	number, hash, tag := blockRef(m.Params[i])
+	switch {
+	case hash:
+		return immutable
+	case tag != "":
+		// 只有区块本身适合按 TTL 缓存，余额、nonce 等状态在发送交易后需要立刻看到变化
+		if m.Method == "eth_getBlockByNumber" && tag != "pending" {
+			return latest
+		}
+		return uncached
+	case t.finalized(req, ep, number):
+		return immutable
+	}
+	return uncached
+}
+
+// blockRef 解析区块参数：十六进制区块号、区块哈希（字符串或 EIP-1898 对象）或 latest 等标签
+func blockRef(p json.RawMessage) (number uint64, hash bool, tag string) {
+	var s string
+	if json.Unmarshal(p, &s) != nil {
+		var obj struct {
+			BlockHash   string `json:"blockHash"`
+			BlockNumber string `json:"blockNumber"`
+		}
+		if json.Unmarshal(p, &obj) != nil {
+			return 0, false, "invalid"
+		}
+		if obj.BlockHash != "" {
+			return 0, true, ""
+		}
+		s = obj.BlockNumber
+	}
+	if len(s) == 66 && strings.HasPrefix(s, "0x") {
+		return 0, true, ""
+	}
+	if n, ok := parseNumber(s); ok {
+		return n, false, ""
+	}
+	if s == "" {
+		return 0, false, "latest"
+	}
+	return 0, false, s
+}
+
+func parseNumber(s string) (uint64, bool) {
+	if !strings.HasPrefix(s, "0x") {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(s[2:], 16, 64)
+	return n, err == nil
+}
+
+// finalized 报告区块 n 是否已最终确定，按需向节点查询 finalized 区块号
+func (t *transport) finalized(req *http.Request, ep *endpoint, n uint64) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if n <= ep.finalized {
+		return true
+	}
+	if time.Since(ep.checkedAt) < finalizedRefresh {
+		return false
+	}
+	ep.checkedAt = time.Now()
+	var block struct {
+		Number string `json:"number"`
+	}
+	if t.call(req, "eth_getBlockByNumber", `["finalized",false]`, &block) {
+		if f, ok := parseNumber(block.Number); ok {
+			ep.finalized = f
+		}
+	}
+	return n <= ep.finalized
+}
+
+// genesis 返回端点的创世区块哈希，每个端点只查询一次
+func (t *transport) genesis(req *http.Request, ep *endpoint) string {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if !ep.genesisDone {
+		ep.genesisDone = true
+		var block struct {
+			Hash string `json:"hash"`
+		}
+		if t.call(req, "eth_getBlockByNumber", `["0x0",false]`, &block) {
+			ep.genesis = strings.ToLower(block.Hash)
+		}
+	}
+	return ep.genesis
+}
+
+// call 用 req 的 URL 和请求头向 next 发送一个内部请求，结果解码到 out
+func (t *transport) call(req *http.Request, method, params string, out any) bool {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":` + params + `}`)
+	r := req.Clone(req.Context())
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	resp, err := t.next.RoundTrip(r)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	var m message
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&m) != nil || m.Error != nil || len(m.Result) == 0 {
+		return false
+	}
+	return json.Unmarshal(m.Result, out) == nil
+}
@@ -0,0 +1,241 @@
+package rpccache
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/local/go-eth-demo/internal/rpctest"
+)
+
+// fakeNode 是只实现测试用到的方法的节点：finalized 区块为 100，最新区块为 110，
+// 0xaa 交易在已最终确定的区块 90 中，0xbb 交易在区块 105 中，genesis 可以修改以模拟重启的开发链
+type fakeNode struct {
+	mu      sync.Mutex
+	calls   map[string]int
+	genesis string
+	head    uint64
+}
+
+func (n *fakeNode) count(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[method]
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var m message
+	json.NewDecoder(r.Body).Decode(&m)
+	n.mu.Lock()
+	n.calls[m.Method]++
+	genesis, head := n.genesis, n.head
+	n.mu.Unlock()
+
+	var result any
+	param := func(i int) string {
+		var s string
+		if i < len(m.Params) {
+			json.Unmarshal(m.Params[i], &s)
+		}
+		return s
+	}
+	switch m.Method {
+	case "eth_chainId":
+		result = "0x1"
+	case "eth_blockNumber":
+		result = hexNum(head)
+	case "eth_getBlockByNumber":
+		switch p := param(0); p {
+		case "0x0":
+			result = block(0, genesis)
+		case "finalized":
+			result = block(100, "")
+		case "latest":
+			result = block(head, "")
+		default:
+			num, _ := parseNumber(p)
+			result = block(num, "")
+		}
+	case "eth_getTransactionReceipt":
+		switch param(0) {
+		case "0x" + repeat("aa"):
+			result = receipt(90)
+		case "0x" + repeat("bb"):
+			result = receipt(105)
+		}
+	case "eth_getBalance":
+		result = "0x64"
+	}
+	data, _ := json.Marshal(result)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(message{JSONRPC: "2.0", ID: m.ID, Result: data})
+}
+
+func hexNum(n uint64) string { return "0x" + big.NewInt(int64(n)).Text(16) }
+
+func repeat(b string) string { return strings.Repeat(b, 32) }
+
+func block(n uint64, hash string) map[string]any {
+	if hash == "" {
+		hash = common.BigToHash(big.NewInt(int64(n) + 1)).Hex()
+	}
+	zero := common.Hash{}.Hex()
+	return map[string]any{
+		"number": hexNum(n), "hash": hash, "parentHash": zero, "sha3Uncles": zero, "miner": common.Address{}.Hex(),
+		"stateRoot": zero, "transactionsRoot": zero, "receiptsRoot": zero, "logsBloom": "0x" + strings.Repeat("0", 512),
+		"difficulty": "0x0", "gasLimit": "0x1c9c380", "gasUsed": "0x0", "timestamp": "0x0", "extraData": "0x",
+		"mixHash": zero, "nonce": "0x0000000000000000", "transactions": []any{}, "uncles": []any{},
+	}
+}
+
+func receipt(n uint64) map[string]any {
+	zero := common.Hash{}.Hex()
+	return map[string]any{
+		"type": "0x2", "status": "0x1", "cumulativeGasUsed": "0x5208", "logsBloom": "0x" + strings.Repeat("0", 512), "logs": []any{},
+		"transactionHash": zero, "gasUsed": "0x5208", "effectiveGasPrice": "0x1", "blockHash": zero,
+		"blockNumber": hexNum(n), "transactionIndex": "0x0",
+	}
+}
+
+func newNode(t *testing.T) (*fakeNode, *httptest.Server) {
+	t.Helper()
+	n := &fakeNode{calls: make(map[string]int), genesis: common.HexToHash("0x01").Hex(), head: 110}
+	return n, rpctest.Serve(t, n)
+}
+
+func TestImmutableData(t *testing.T) {
+	node, srv := newNode(t)
+	var hits int
+	client := ethclient.NewClient(rpctest.Dial(t, srv.URL, New(Options{OnHit: func(string) { hits++ }}).Transport(nil)))
+	ctx := context.Background()
+
+	for range 3 {
+		if id, err := client.ChainID(ctx); err != nil || id.Int64() != 1 {
+			t.Fatalf("ChainID = %v, %v", id, err)
+		}
+		if _, err := client.HeaderByNumber(ctx, big.NewInt(90)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.TransactionReceipt(ctx, common.HexToHash(repeat("aa"))); err != nil {
+			t.Fatal(err)
+		}
+		// 区块 105 和其中的收据尚未最终确定，每次都重新查询
+		if _, err := client.TransactionReceipt(ctx, common.HexToHash(repeat("bb"))); err != nil {
+			t.Fatal(err)
+		}
+		// 余额随时会变，不缓存
+		if _, err := client.BalanceAt(ctx, common.Address{}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := node.count("eth_chainId"); got != 1 {
+		t.Errorf("eth_chainId sent %d times, want 1", got)
+	}
+	if got := node.count("eth_getTransactionReceipt"); got != 4 {
+		t.Errorf("eth_getTransactionReceipt sent %d times, want 4 (1 finalized + 3 recent)", got)
+	}
+	if got := node.count("eth_getBalance"); got != 3 {
+		t.Errorf("eth_getBalance sent %d times, want 3", got)
+	}
+	// 区块 90 查询 1 次，finalized 在 12 秒内只查询 1 次
+	if got := node.count("eth_getBlockByNumber"); got != 2 {
+		t.Errorf("eth_getBlockByNumber sent %d times, want 2", got)
+	}
+	if hits != 6 {
+		t.Errorf("%d cache hits, want 6", hits)
+	}
+}
+
+func TestLatestTTL(t *testing.T) {
+	node, srv := newNode(t)
+	client := ethclient.NewClient(rpctest.Dial(t, srv.URL, New(Options{TTL: 50 * time.Millisecond}).Transport(nil)))
+	ctx := context.Background()
+
+	for range 3 {
+		if n, err := client.BlockNumber(ctx); err != nil || n != 110 {
+			t.Fatalf("BlockNumber = %d, %v", n, err)
+		}
+	}
+	node.mu.Lock()
+	node.head = 111
+	node.mu.Unlock()
+	time.Sleep(60 * time.Millisecond)
+	if n, err := client.BlockNumber(ctx); err != nil || n != 111 {
+		t.Errorf("BlockNumber after the TTL = %d, %v, want 111", n, err)
+	}
+	if got := node.count("eth_blockNumber"); got != 2 {
+		t.Errorf("eth_blockNumber sent %d times, want 2", got)
+	}
+
+	// TTL 为 0 时不缓存 latest 数据
+	node2, srv2 := newNode(t)
+	client = ethclient.NewClient(rpctest.Dial(t, srv2.URL, New(Options{}).Transport(nil)))
+	client.BlockNumber(ctx)
+	client.BlockNumber(ctx)
+	if got := node2.count("eth_blockNumber"); got != 2 {
+		t.Errorf("eth_blockNumber without TTL sent %d times, want 2", got)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	node, srv := newNode(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+	hash := common.HexToHash(repeat("aa"))
+	// receipt 每次都用新的 Cache 查询，模拟新的进程
+	receipt := func() error {
+		client := ethclient.NewClient(rpctest.Dial(t, srv.URL, New(Options{Dir: dir}).Transport(nil)))
+		_, err := client.TransactionReceipt(ctx, hash)
+		return err
+	}
+
+	if err := receipt(); err != nil {
+		t.Fatal(err)
+	}
+	// 新的进程（新的 Cache）从磁盘读取
+	if err := receipt(); err != nil {
+		t.Fatal(err)
+	}
+	if got := node.count("eth_getTransactionReceipt"); got != 1 {
+		t.Errorf("eth_getTransactionReceipt sent %d times across runs, want 1", got)
+	}
+
+	// 开发链重启后创世区块不同，不使用旧数据
+	node.mu.Lock()
+	node.genesis = common.HexToHash("0x02").Hex()
+	node.mu.Unlock()
+	if err := receipt(); err != nil {
+		t.Fatal(err)
+	}
+	if got := node.count("eth_getTransactionReceipt"); got != 2 {
+		t.Errorf("eth_getTransactionReceipt sent %d times after a new genesis, want 2", got)
+	}
+}
+
+func TestBlockRef(t *testing.T) {
+	for param, want := range map[string]struct {
+		number uint64
+		hash   bool
+		tag    string
+	}{
+		`"0x10"`:                                 {number: 16},
+		`"latest"`:                               {tag: "latest"},
+		`"finalized"`:                            {tag: "finalized"},
+		`"0x` + repeat("ab") + `"`:               {hash: true},
+		`{"blockHash":"0x` + repeat("ab") + `"}`: {hash: true},
+		`{"blockNumber":"0x5"}`:                  {number: 5},
+	} {
+		number, hash, tag := blockRef(json.RawMessage(param))
+		if number != want.number || hash != want.hash || tag != want.tag {
+			t.Errorf("blockRef(%s) = %d, %v, %q, want %+v", param, number, hash, tag, want)
+		}
+	}
+}
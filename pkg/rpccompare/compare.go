@@ -0,0 +1,179 @@
+// Package rpccompare 对多个 RPC 提供商发出同一组查询（余额、区块、日志）并比较结果，
+// 用于发现落后或返回异常数据的节点。
+package rpccompare
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// Provider 是一个被比较的 RPC 端点
+type Provider struct {
+	Name   string
+	Client chain.Client
+}
+
+// Options 控制比较哪些数据
+type Options struct {
+	Addresses  []common.Address // 需要比较余额的地址
+	LogAddress *common.Address  // 需要比较日志的合约地址（可选）
+	LogRange   uint64           // 日志查询覆盖的区块数
+	Lag        uint64           // 在最小区块高度之下再回退的区块数，避免比较正在传播的新块
+}
+
+// Check 是一项查询在各提供商上的结果
+type Check struct {
+	Name   string
+	Values map[string]string // provider name -> 结果（或错误信息）
+}
+
+// Consistent 判断所有提供商的结果是否一致
+func (c Check) Consistent() bool {
+	var first string
+	seen := false
+	for _, v := range c.Values {
+		if !seen {
+			first, seen = v, true
+			continue
+		}
+		if v != first {
+			return false
+		}
+	}
+	return true
+}
+
+// Report 是一次比较的完整结果
+type Report struct {
+	Heads     map[string]uint64 // 各提供商的最新区块高度
+	CompareAt uint64            // 实际用于比较的区块高度
+	Checks    []Check
+}
+
+// Lagging 返回区块高度落后最高者超过 maxLag 的提供商
+func (r *Report) Lagging(maxLag uint64) []string {
+	var highest uint64
+	for _, h := range r.Heads {
+		if h > highest {
+			highest = h
+		}
+	}
+	var names []string
+	for name, h := range r.Heads {
+		if highest-h > maxLag {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Mismatches 返回结果不一致的检查项
+func (r *Report) Mismatches() []Check {
+	var out []Check
+	for _, c := range r.Checks {
+		if !c.Consistent() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Compare 在所有提供商上执行同一组查询。各提供商先报告自己的最新高度，
+// 然后在共同可见的区块（最小高度减去 Lag）上比较区块哈希、余额和日志。
+func Compare(ctx context.Context, providers []Provider, opts Options) (*Report, error) {
+	if len(providers) < 2 {
+		return nil, fmt.Errorf("need at least two providers to compare, got %d", len(providers))
+	}
+
+	report := &Report{Heads: make(map[string]uint64)}
+	chainIDs := Check{Name: "chain id", Values: make(map[string]string)}
+	var minHead uint64
+	for i, p := range providers {
+		head, err := p.Client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: get block number: %w", p.Name, err)
+		}
+		report.Heads[p.Name] = head
+		if i == 0 || head < minHead {
+			minHead = head
+		}
+		chainIDs.Values[p.Name] = result(p.Client.ChainID(ctx))
+	}
+	report.Checks = append(report.Checks, chainIDs)
+
+	if minHead < opts.Lag {
+		return nil, fmt.Errorf("lowest head %d is below lag %d", minHead, opts.Lag)
+	}
+	report.CompareAt = minHead - opts.Lag
+	at := new(big.Int).SetUint64(report.CompareAt)
+
+	blockHash := Check{Name: fmt.Sprintf("block %d hash", report.CompareAt), Values: make(map[string]string)}
+	for _, p := range providers {
+		header, err := p.Client.HeaderByNumber(ctx, at)
+		if err != nil {
+			blockHash.Values[p.Name] = "error: " + err.Error()
+			continue
+		}
+		blockHash.Values[p.Name] = header.Hash().Hex()
+	}
+	report.Checks = append(report.Checks, blockHash)
+
+	for _, addr := range opts.Addresses {
+		balance := Check{Name: "balance " + addr.Hex(), Values: make(map[string]string)}
+		for _, p := range providers {
+			balance.Values[p.Name] = result(p.Client.BalanceAt(ctx, addr, at))
+		}
+		report.Checks = append(report.Checks, balance)
+	}
+
+	if opts.LogAddress != nil {
+		from := uint64(0)
+		if report.CompareAt > opts.LogRange {
+			from = report.CompareAt - opts.LogRange
+		}
+		query := ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   at,
+			Addresses: []common.Address{*opts.LogAddress},
+		}
+		logs := Check{
+			Name:   fmt.Sprintf("logs %s [%d, %d]", opts.LogAddress.Hex(), from, report.CompareAt),
+			Values: make(map[string]string),
+		}
+		for _, p := range providers {
+			found, err := p.Client.FilterLogs(ctx, query)
+			if err != nil {
+				logs.Values[p.Name] = "error: " + err.Error()
+				continue
+			}
+			// 用日志的 (区块哈希, 交易哈希, 索引) 生成摘要，比较时无需逐条展示
+			h := sha256.New()
+			for _, l := range found {
+				h.Write(l.BlockHash.Bytes())
+				h.Write(l.TxHash.Bytes())
+				fmt.Fprintf(h, "%d", l.Index)
+			}
+			logs.Values[p.Name] = fmt.Sprintf("%d logs, digest %s", len(found), hex.EncodeToString(h.Sum(nil))[:16])
+		}
+		report.Checks = append(report.Checks, logs)
+	}
+
+	return report, nil
+}
+
+// result 把查询结果或错误统一格式化为字符串，便于比较
+func result(v *big.Int, err error) string {
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return v.String()
+}
@@ -0,0 +1,87 @@
+package rpccompare
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// fakeProvider 返回一个行为固定的模拟节点：head 为最新高度，balance 为任何地址的余额
+func fakeProvider(name string, head uint64, balance int64) Provider {
+	return Provider{Name: name, Client: &chain.ClientMock{
+		BlockNumberFunc: func(ctx context.Context) (uint64, error) { return head, nil },
+		ChainIDFunc:     func(ctx context.Context) (*big.Int, error) { return big.NewInt(11155111), nil },
+		HeaderByNumberFunc: func(ctx context.Context, number *big.Int) (*types.Header, error) {
+			return &types.Header{Number: number}, nil
+		},
+		BalanceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+			return big.NewInt(balance), nil
+		},
+		FilterLogsFunc: func(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+			return []types.Log{{BlockNumber: q.ToBlock.Uint64()}}, nil
+		},
+	}}
+}
+
+func TestCompareConsistent(t *testing.T) {
+	a := fakeProvider("a", 100, 5)
+	b := fakeProvider("b", 101, 5)
+	logAddr := common.HexToAddress("0x01")
+	report, err := Compare(context.Background(), []Provider{a, b}, Options{
+		Addresses:  []common.Address{common.HexToAddress("0x02")},
+		LogAddress: &logAddr,
+		LogRange:   10,
+		Lag:        2,
+	})
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if report.CompareAt != 98 {
+		t.Errorf("CompareAt = %d, want 98 (lowest head 100 minus lag 2)", report.CompareAt)
+	}
+	if m := report.Mismatches(); len(m) != 0 {
+		t.Errorf("unexpected mismatches: %+v", m)
+	}
+	if l := report.Lagging(3); len(l) != 0 {
+		t.Errorf("unexpected lagging providers: %v", l)
+	}
+	if got := len(report.Checks); got != 4 {
+		t.Errorf("got %d checks, want 4 (chain id, block hash, balance, logs)", got)
+	}
+	// 所有查询都应在比较高度上执行
+	for _, call := range a.Client.(*chain.ClientMock).BalanceAtCalls() {
+		if call.BlockNumber.Uint64() != 98 {
+			t.Errorf("BalanceAt queried at %d, want 98", call.BlockNumber)
+		}
+	}
+}
+
+func TestCompareDetectsMismatchAndLag(t *testing.T) {
+	a := fakeProvider("a", 100, 5)
+	b := fakeProvider("b", 110, 7)
+	report, err := Compare(context.Background(), []Provider{a, b}, Options{
+		Addresses: []common.Address{common.HexToAddress("0x02")},
+		Lag:       1,
+	})
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	mismatches := report.Mismatches()
+	if len(mismatches) != 1 || mismatches[0].Values["a"] != "5" || mismatches[0].Values["b"] != "7" {
+		t.Errorf("mismatches = %+v, want the balance check", mismatches)
+	}
+	if lagging := report.Lagging(3); len(lagging) != 1 || lagging[0] != "a" {
+		t.Errorf("Lagging(3) = %v, want [a]", lagging)
+	}
+}
+
+func TestCompareNeedsTwoProviders(t *testing.T) {
+	if _, err := Compare(context.Background(), []Provider{fakeProvider("a", 1, 0)}, Options{}); err == nil {
+		t.Fatal("Compare with one provider succeeded, want error")
+	}
+}
@@ -0,0 +1,302 @@
+// Package safe 通过 Safe（原 Gnosis Safe）多签钱包发送交易：读取 owners、threshold 和 nonce，按 EIP-712 计算
+// SafeTx 哈希，收集 owner 的签名，调用 execTransaction 执行；也可以把提案和签名提交到 Safe Transaction Service，
+// 让其他 owner 在 Safe{Wallet} 网页中确认。
+package safe
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/local/go-eth-demo/pkg/chain"
+)
+
+// ErrNotEnoughSignatures 表示有效的 owner 签名少于 threshold
+var ErrNotEnoughSignatures = errors.New("not enough owner signatures")
+
+// 交易的操作类型
+const (
+	Call         uint8 = 0
+	DelegateCall uint8 = 1
+)
+
+// ABI 是 Safe 合约中用到的方法
+var ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[
+{"type":"function","name":"VERSION","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+{"type":"function","name":"nonce","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"getThreshold","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"function","name":"getOwners","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address[]"}]},
+{"type":"function","name":"execTransaction","stateMutability":"payable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}],"outputs":[{"name":"success","type":"bool"}]},
+{"type":"event","name":"ExecutionSuccess","anonymous":false,"inputs":[{"name":"txHash","type":"bytes32","indexed":false},{"name":"payment","type":"uint256","indexed":false}]},
+{"type":"event","name":"ExecutionFailure","anonymous":false,"inputs":[{"name":"txHash","type":"bytes32","indexed":false},{"name":"payment","type":"uint256","indexed":false}]}
+]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// Info 是 Safe 的当前状态
+type Info struct {
+	Address   common.Address
+	ChainID   *big.Int
+	Version   string
+	Owners    []common.Address
+	Threshold int
+	Nonce     *big.Int
+}
+
+// Load 读取 Safe 的版本、owners、threshold 和 nonce。地址上没有合约或不是 Safe 时返回错误
+func Load(ctx context.Context, client chain.Client, address common.Address) (*Info, error) {
+	code, err := client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("no contract at %s", address.Hex())
+	}
+	info := &Info{Address: address}
+	if info.ChainID, err = client.ChainID(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	var threshold *big.Int
+	for _, c := range []struct {
+		method string
+		out    interface{}
+	}{{"VERSION", &info.Version}, {"getOwners", &info.Owners}, {"getThreshold", &threshold}, {"nonce", &info.Nonce}} {
+		if err := call(ctx, client, address, c.out, c.method); err != nil {
+			return nil, fmt.Errorf("%s does not look like a Safe: %w", address.Hex(), err)
+		}
+	}
+	info.Threshold = int(threshold.Int64())
+	return info, nil
+}
+
+// call 调用 Safe 的只读方法并把唯一的返回值写入 out
+func call(ctx context.Context, client chain.Client, address common.Address, out interface{}, method string) error {
+	data, err := ABI.Pack(method)
+	if err != nil {
+		return err
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("call %s: %w", method, err)
+	}
+	values, err := ABI.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("decode %s: %w", method, err)
+	}
+	abi.ConvertType(values[0], out)
+	return nil
+}
+
+// IsOwner 判断 address 是否是 owner
+func (i *Info) IsOwner(address common.Address) bool {
+	for _, o := range i.Owners {
+		if o == address {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTx 构造下一个 nonce 上的 SafeTx，不退还 gas（safeTxGas、baseGas、gasPrice 为 0）
+func (i *Info) NewTx(to common.Address, value *big.Int, data []byte, operation uint8) *Tx {
+	return &Tx{
+		Safe: i.Address, ChainID: i.ChainID, Version: i.Version,
+		To: to, Value: value, Data: data, Operation: operation,
+		SafeTxGas: new(big.Int), BaseGas: new(big.Int), GasPrice: new(big.Int),
+		Nonce: i.Nonce,
+	}
+}
+
+// Tx 是一笔 Safe 交易及已收集的签名，可以保存为 JSON 在 owner 之间传递
+type Tx struct {
+	Safe    common.Address `json:"safe"`
+	ChainID *big.Int       `json:"chainId"`
+	Version string         `json:"version"` // Safe 合约版本，1.3.0 之前的签名域不含 chainId
+
+	To             common.Address `json:"to"`
+	Value          *big.Int       `json:"value"`
+	Data           hexutil.Bytes  `json:"data"`
+	Operation      uint8          `json:"operation"`
+	SafeTxGas      *big.Int       `json:"safeTxGas"`
+	BaseGas        *big.Int       `json:"baseGas"`
+	GasPrice       *big.Int       `json:"gasPrice"`
+	GasToken       common.Address `json:"gasToken"`
+	RefundReceiver common.Address `json:"refundReceiver"`
+	Nonce          *big.Int       `json:"nonce"`
+
+	Signatures []Signature `json:"signatures"`
+}
+
+// Signature 是一个 owner 对 SafeTx 哈希的 65 字节 ECDSA 签名（r || s || v，v 为 27 或 28）
+type Signature struct {
+	Signer common.Address `json:"signer"`
+	Data   hexutil.Bytes  `json:"data"`
+}
+
+// legacyDomain 判断版本是否使用不含 chainId 的签名域
+func legacyDomain(version string) bool {
+	switch version {
+	case "1.0.0", "1.1.0", "1.1.1", "1.2.0":
+		return true
+	}
+	return false
+}
+
+// TypedData 返回 SafeTx 的 EIP-712 结构化数据
+func (tx *Tx) TypedData() apitypes.TypedData {
+	domainType := []apitypes.Type{{Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}}
+	domain := apitypes.TypedDataDomain{ChainId: (*math.HexOrDecimal256)(tx.ChainID), VerifyingContract: tx.Safe.Hex()}
+	if legacyDomain(tx.Version) {
+		domainType, domain.ChainId = domainType[1:], nil
+	}
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": domainType,
+			"SafeTx": {
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain:      domain,
+		Message: apitypes.TypedDataMessage{
+			"to":             tx.To.Hex(),
+			"value":          tx.Value.String(),
+			"data":           hexutil.Bytes(nonNil(tx.Data)),
+			"operation":      fmt.Sprint(tx.Operation),
+			"safeTxGas":      tx.SafeTxGas.String(),
+			"baseGas":        tx.BaseGas.String(),
+			"gasPrice":       tx.GasPrice.String(),
+			"gasToken":       tx.GasToken.Hex(),
+			"refundReceiver": tx.RefundReceiver.Hex(),
+			"nonce":          tx.Nonce.String(),
+		},
+	}
+}
+
+func nonNil(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
+
+// Hash 返回 SafeTx 哈希（safeTxHash），即 owner 签名的 EIP-712 摘要
+func (tx *Tx) Hash() (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(tx.TypedData())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("hash SafeTx: %w", err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// Sign 用 key 签名并记录签名，同一个 owner 的旧签名被替换。不检查 key 是否是 owner
+func (tx *Tx) Sign(key *ecdsa.PrivateKey) (Signature, error) {
+	hash, err := tx.Hash()
+	if err != nil {
+		return Signature{}, err
+	}
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		return Signature{}, fmt.Errorf("sign SafeTx: %w", err)
+	}
+	sig[64] += 27
+	s := Signature{Signer: crypto.PubkeyToAddress(key.PublicKey), Data: sig}
+	tx.addSignature(s)
+	return s, nil
+}
+
+// AddSignature 恢复签名者并记录签名，用于加入其他 owner 或 Transaction Service 中的确认
+func (tx *Tx) AddSignature(sig []byte) (common.Address, error) {
+	hash, err := tx.Hash()
+	if err != nil {
+		return common.Address{}, err
+	}
+	signer, err := recoverSigner(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	tx.addSignature(Signature{Signer: signer, Data: common.CopyBytes(sig)})
+	return signer, nil
+}
+
+// recoverSigner 从 v 为 27/28 的 ECDSA 签名中恢复签名者
+func recoverSigner(hash common.Hash, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength || (sig[64] != 27 && sig[64] != 28) {
+		return common.Address{}, errors.New("unsupported signature: want a 65-byte ECDSA signature with v 27 or 28")
+	}
+	raw := common.CopyBytes(sig)
+	raw[64] -= 27
+	pub, err := crypto.SigToPub(hash.Bytes(), raw)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+func (tx *Tx) addSignature(s Signature) {
+	for i := range tx.Signatures {
+		if tx.Signatures[i].Signer == s.Signer {
+			tx.Signatures[i] = s
+			return
+		}
+	}
+	tx.Signatures = append(tx.Signatures, s)
+}
+
+// PackSignatures 按 execTransaction 的要求把签名按签名者地址升序拼接。只使用 owners 的签名；
+// executor 是还没有签名的 owner 时加入它的“预先批准”签名（r 为地址、v 为 1），合约在 msg.sender 是该 owner 时接受，
+// 这样执行者不需要单独签名。有效签名少于 threshold 时返回 ErrNotEnoughSignatures
+func (tx *Tx) PackSignatures(info *Info, executor common.Address) ([]byte, error) {
+	var sigs []Signature
+	signed := make(map[common.Address]bool)
+	for _, s := range tx.Signatures {
+		if info.IsOwner(s.Signer) && !signed[s.Signer] {
+			sigs = append(sigs, s)
+			signed[s.Signer] = true
+		}
+	}
+	if info.IsOwner(executor) && !signed[executor] && len(sigs) < info.Threshold {
+		data := append(common.LeftPadBytes(executor.Bytes(), 32), make([]byte, 32)...)
+		sigs = append(sigs, Signature{Signer: executor, Data: append(data, 1)})
+	}
+	if len(sigs) < info.Threshold {
+		return nil, fmt.Errorf("%w: have %d, threshold is %d", ErrNotEnoughSignatures, len(sigs), info.Threshold)
+	}
+	sort.Slice(sigs, func(i, j int) bool { return bytes.Compare(sigs[i].Signer[:], sigs[j].Signer[:]) < 0 })
+	var out []byte
+	for _, s := range sigs[:info.Threshold] {
+		out = append(out, s.Data...)
+	}
+	return out, nil
+}
+
+// ExecData 返回 execTransaction 的调用数据，发给 Safe 地址、value 为 0
+func (tx *Tx) ExecData(signatures []byte) ([]byte, error) {
+	return ABI.Pack("execTransaction", tx.To, tx.Value, []byte(nonNil(tx.Data)), tx.Operation,
+		tx.SafeTxGas, tx.BaseGas, tx.GasPrice, tx.GasToken, tx.RefundReceiver, signatures)
+}
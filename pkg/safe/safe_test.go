@@ -0,0 +1,163 @@
+package safe
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newInfo(threshold int, keys ...*ecdsa.PrivateKey) *Info {
+	info := &Info{
+		Address: common.HexToAddress("0x5afe000000000000000000000000000000000001"), ChainID: big.NewInt(11155111),
+		Version: "1.4.1", Threshold: threshold, Nonce: big.NewInt(7),
+	}
+	for _, k := range keys {
+		info.Owners = append(info.Owners, crypto.PubkeyToAddress(k.PublicKey))
+	}
+	return info
+}
+
+// safeTxHash 按 Safe 合约的 encodeTransactionData 独立计算哈希
+func safeTxHash(tx *Tx) common.Hash {
+	u := func(v *big.Int) []byte { return common.BigToHash(v).Bytes() }
+	a := func(addr common.Address) []byte { return common.LeftPadBytes(addr.Bytes(), 32) }
+	domain := crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(uint256 chainId,address verifyingContract)")),
+		u(tx.ChainID), a(tx.Safe),
+	)
+	message := crypto.Keccak256(
+		crypto.Keccak256([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)")),
+		a(tx.To), u(tx.Value), crypto.Keccak256(tx.Data), u(big.NewInt(int64(tx.Operation))),
+		u(tx.SafeTxGas), u(tx.BaseGas), u(tx.GasPrice), a(tx.GasToken), a(tx.RefundReceiver), u(tx.Nonce),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domain, message)
+}
+
+func TestHash(t *testing.T) {
+	tx := newInfo(1).NewTx(common.Address{0xaa}, big.NewInt(1e15), []byte{0xde, 0xad}, Call)
+	hash, err := tx.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := safeTxHash(tx); hash != want {
+		t.Errorf("Hash = %s, want %s", hash.Hex(), want.Hex())
+	}
+}
+
+func TestPackSignatures(t *testing.T) {
+	var keys []*ecdsa.PrivateKey
+	for range 3 {
+		k, _ := crypto.GenerateKey()
+		keys = append(keys, k)
+	}
+	info := newInfo(2, keys...)
+	tx := info.NewTx(common.Address{0xaa}, big.NewInt(1), nil, Call)
+	stranger, _ := crypto.GenerateKey()
+	for _, k := range []*ecdsa.PrivateKey{keys[2], stranger} {
+		if _, err := tx.Sign(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tx.PackSignatures(info, common.Address{}); !errors.Is(err, ErrNotEnoughSignatures) {
+		t.Errorf("PackSignatures with one owner signature = %v, want ErrNotEnoughSignatures", err)
+	}
+
+	// 执行者是 owner 时使用预先批准的签名
+	executor := info.Owners[0]
+	packed, err := tx.PackSignatures(info, executor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packed) != 130 {
+		t.Fatalf("packed %d bytes, want 2 signatures", len(packed))
+	}
+	var approved []byte
+	for _, sig := range [][]byte{packed[:65], packed[65:]} {
+		if sig[64] == 1 {
+			approved = sig
+		}
+	}
+	if approved == nil || common.BytesToAddress(approved[:32]) != executor {
+		t.Errorf("no pre-approved signature for %s in %x", executor.Hex(), packed)
+	}
+
+	// 签名按 owner 地址升序排列
+	if _, err := tx.Sign(keys[1]); err != nil {
+		t.Fatal(err)
+	}
+	if packed, err = tx.PackSignatures(info, common.Address{}); err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := tx.Hash()
+	var signers []common.Address
+	for _, sig := range [][]byte{packed[:65], packed[65:]} {
+		raw := common.CopyBytes(sig)
+		raw[64] -= 27
+		pub, err := crypto.SigToPub(hash.Bytes(), raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers = append(signers, crypto.PubkeyToAddress(*pub))
+	}
+	if bytes.Compare(signers[0][:], signers[1][:]) >= 0 {
+		t.Errorf("signatures not sorted by signer: %v", signers)
+	}
+	if _, err := tx.ExecData(packed); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestService(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	info := newInfo(1, key)
+	tx := info.NewTx(common.Address{0xaa}, big.NewInt(5), nil, Call)
+	if _, err := tx.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := tx.Hash()
+
+	var proposed map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/safes/{safe}/multisig-transactions/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&proposed)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /api/v1/multisig-transactions/{hash}/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"safe": info.Address, "to": tx.To, "value": "5", "data": nil, "operation": 0,
+			"safeTxGas": "0", "baseGas": "0", "gasPrice": "0",
+			"gasToken": common.Address{}, "refundReceiver": common.Address{}, "nonce": "7",
+			"safeTxHash": r.PathValue("hash"), "isExecuted": false,
+			"confirmations": []map[string]interface{}{
+				{"owner": info.Owners[0], "signature": proposed["signature"]},
+				{"owner": common.Address{1}, "signature": proposed["signature"]}, // 与签名者不符
+			},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := &Service{URL: server.URL}
+	if err := s.Propose(context.Background(), tx, info.Owners[0]); err != nil {
+		t.Fatal(err)
+	}
+	if proposed["contractTransactionHash"] != hash.Hex() || proposed["nonce"] != "7" {
+		t.Errorf("proposal = %v", proposed)
+	}
+	got, executed, err := s.Get(context.Background(), info, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if executed || len(got.Signatures) != 1 || got.Signatures[0].Signer != info.Owners[0] {
+		t.Errorf("Get = %+v, executed %v", got, executed)
+	}
+}
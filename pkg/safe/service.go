@@ -0,0 +1,200 @@
+package safe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// serviceURLs 是各链的 Safe Transaction Service 地址
+var serviceURLs = map[uint64]string{
+	1:        "https://safe-transaction-mainnet.safe.global",
+	10:       "https://safe-transaction-optimism.safe.global",
+	100:      "https://safe-transaction-gnosis-chain.safe.global",
+	137:      "https://safe-transaction-polygon.safe.global",
+	8453:     "https://safe-transaction-base.safe.global",
+	42161:    "https://safe-transaction-arbitrum.safe.global",
+	84532:    "https://safe-transaction-base-sepolia.safe.global",
+	11155111: "https://safe-transaction-sepolia.safe.global",
+}
+
+// ServiceURL 返回链的 Safe Transaction Service 地址，不支持的链返回空字符串
+func ServiceURL(chainID uint64) string {
+	return serviceURLs[chainID]
+}
+
+// Service 是 Safe Transaction Service 的客户端。提交到服务的提案和确认会出现在 Safe{Wallet} 网页中
+type Service struct {
+	URL    string // 如 https://safe-transaction-sepolia.safe.global
+	APIKey string // 可选，以 Bearer 发送
+	HTTP   *http.Client
+}
+
+// serviceTx 是服务中的多签交易，数量为十进制字符串
+type serviceTx struct {
+	Safe           common.Address `json:"safe"`
+	To             common.Address `json:"to"`
+	Value          string         `json:"value"`
+	Data           *hexutil.Bytes `json:"data"`
+	Operation      uint8          `json:"operation"`
+	SafeTxGas      string         `json:"safeTxGas"`
+	BaseGas        string         `json:"baseGas"`
+	GasPrice       string         `json:"gasPrice"`
+	GasToken       common.Address `json:"gasToken"`
+	RefundReceiver common.Address `json:"refundReceiver"`
+	Nonce          string         `json:"nonce"`
+	SafeTxHash     common.Hash    `json:"safeTxHash"`
+	IsExecuted     bool           `json:"isExecuted"`
+	Confirmations  []struct {
+		Owner     common.Address `json:"owner"`
+		Signature hexutil.Bytes  `json:"signature"`
+	} `json:"confirmations"`
+}
+
+// proposal 是 POST multisig-transactions 的请求体
+type proposal struct {
+	To                      common.Address `json:"to"`
+	Value                   string         `json:"value"`
+	Data                    *hexutil.Bytes `json:"data"`
+	Operation               uint8          `json:"operation"`
+	SafeTxGas               string         `json:"safeTxGas"`
+	BaseGas                 string         `json:"baseGas"`
+	GasPrice                string         `json:"gasPrice"`
+	GasToken                common.Address `json:"gasToken"`
+	RefundReceiver          common.Address `json:"refundReceiver"`
+	Nonce                   string         `json:"nonce"`
+	ContractTransactionHash common.Hash    `json:"contractTransactionHash"`
+	Sender                  common.Address `json:"sender"`
+	Signature               hexutil.Bytes  `json:"signature"`
+	Origin                  string         `json:"origin,omitempty"`
+}
+
+// Propose 提交 tx 作为新提案，附带 sender 已有的签名（sender 必须是 owner 或代理）
+func (s *Service) Propose(ctx context.Context, tx *Tx, sender common.Address) error {
+	hash, err := tx.Hash()
+	if err != nil {
+		return err
+	}
+	var sig hexutil.Bytes
+	for _, sg := range tx.Signatures {
+		if sg.Signer == sender {
+			sig = sg.Data
+		}
+	}
+	if sig == nil {
+		return fmt.Errorf("transaction has no signature from the sender %s", sender.Hex())
+	}
+	var data *hexutil.Bytes
+	if len(tx.Data) > 0 {
+		data = &tx.Data
+	}
+	body := proposal{
+		To: tx.To, Value: tx.Value.String(), Data: data, Operation: tx.Operation,
+		SafeTxGas: tx.SafeTxGas.String(), BaseGas: tx.BaseGas.String(), GasPrice: tx.GasPrice.String(),
+		GasToken: tx.GasToken, RefundReceiver: tx.RefundReceiver, Nonce: tx.Nonce.String(),
+		ContractTransactionHash: hash, Sender: sender, Signature: sig, Origin: "go-eth-demo",
+	}
+	return s.do(ctx, http.MethodPost, "/api/v1/safes/"+tx.Safe.Hex()+"/multisig-transactions/", body, nil)
+}
+
+// Confirm 提交 owner 对已有提案的签名
+func (s *Service) Confirm(ctx context.Context, safeTxHash common.Hash, sig []byte) error {
+	body := struct {
+		Signature hexutil.Bytes `json:"signature"`
+	}{sig}
+	return s.do(ctx, http.MethodPost, "/api/v1/multisig-transactions/"+safeTxHash.Hex()+"/confirmations/", body, nil)
+}
+
+// Get 读取提案及其确认。返回的 Tx 使用 info 的链 ID 和版本，计算出的哈希与服务给出的不一致时返回错误；
+// 签名无法恢复或与确认中的 owner 不符的确认被忽略。executed 表示提案已经执行
+func (s *Service) Get(ctx context.Context, info *Info, safeTxHash common.Hash) (tx *Tx, executed bool, err error) {
+	var st serviceTx
+	if err := s.do(ctx, http.MethodGet, "/api/v1/multisig-transactions/"+safeTxHash.Hex()+"/", nil, &st); err != nil {
+		return nil, false, err
+	}
+	if st.Safe != info.Address {
+		return nil, false, fmt.Errorf("transaction %s belongs to Safe %s, not %s", safeTxHash.Hex(), st.Safe.Hex(), info.Address.Hex())
+	}
+	tx = &Tx{Safe: st.Safe, ChainID: info.ChainID, Version: info.Version, To: st.To, Operation: st.Operation,
+		GasToken: st.GasToken, RefundReceiver: st.RefundReceiver}
+	if st.Data != nil {
+		tx.Data = *st.Data
+	}
+	for _, f := range []struct {
+		name, s string
+		out     **big.Int
+	}{{"value", st.Value, &tx.Value}, {"safeTxGas", st.SafeTxGas, &tx.SafeTxGas}, {"baseGas", st.BaseGas, &tx.BaseGas},
+		{"gasPrice", st.GasPrice, &tx.GasPrice}, {"nonce", st.Nonce, &tx.Nonce}} {
+		v, ok := new(big.Int).SetString(f.s, 10)
+		if !ok {
+			return nil, false, fmt.Errorf("transaction service returned an invalid %s %q", f.name, f.s)
+		}
+		*f.out = v
+	}
+	hash, err := tx.Hash()
+	if err != nil {
+		return nil, false, err
+	}
+	if hash != safeTxHash {
+		return nil, false, fmt.Errorf("transaction service returned a transaction hashing to %s, not %s", hash.Hex(), safeTxHash.Hex())
+	}
+	for _, c := range st.Confirmations {
+		if signer, err := recoverSigner(hash, c.Signature); err == nil && signer == c.Owner {
+			tx.addSignature(Signature{Signer: signer, Data: c.Signature})
+		}
+	}
+	return tx, st.IsExecuted, nil
+}
+
+// do 发送 JSON 请求，out 不为 nil 时解析响应
+func (s *Service) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(s.URL, "/")+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("safe transaction service: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("safe transaction service: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("safe transaction service: %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("safe transaction service: decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// Package secret 从环境变量以外的地方读取私钥等秘密：权限受限的文件和操作系统钥匙串
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// ErrInsecureFile 表示秘密文件可以被所有者以外的用户读写
+var ErrInsecureFile = errors.New("secret file is accessible by other users")
+
+// ReadFile 读取 path 中的秘密并去掉首尾空白。在类 Unix 系统上文件权限必须只允许所有者访问
+// （如 0600 或 0400），否则返回 ErrInsecureFile，以免私钥被同一台机器上的其他用户读取
+func ReadFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("%s has mode %04o, want 0600 or stricter (chmod 600 %s): %w", path, info.Mode().Perm(), path, ErrInsecureFile)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return s, nil
+}
@@ -0,0 +1,173 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrNotFound 表示钥匙串中没有对应的条目
+var ErrNotFound = errors.New("secret not found in keychain")
+
+// ErrUnsupported 表示当前系统没有可用的钥匙串工具
+var ErrUnsupported = errors.New("keychain is not supported on this system")
+
+// DefaultService 是钥匙串条目默认的服务名
+const DefaultService = "go-eth-demo"
+
+// Keychain 是操作系统钥匙串中按服务名和账户名定位的一个条目。macOS 上使用 security 命令访问登录钥匙串，
+// Linux 和 BSD 上使用 libsecret 的 secret-tool（GNOME Keyring、KWallet 等 Secret Service 实现）。
+// 读取时系统可能弹出解锁或授权提示
+type Keychain struct {
+	Service string
+	Account string
+}
+
+// goos 是选择钥匙串工具的操作系统，测试时替换
+var goos = runtime.GOOS
+
+// run 执行钥匙串工具，stdin 非空时作为标准输入，返回标准输出和标准错误，测试时替换
+var run = func(stdin string, name string, args ...string) (string, string, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return "", "", &toolError{name: name, code: exit.ExitCode(), stderr: strings.TrimSpace(stderr.String())}
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			return "", "", fmt.Errorf("%s not found: %w", name, ErrUnsupported)
+		}
+		return "", "", err
+	}
+	return stdout.String(), stderr.String(), nil
+}
+
+// toolError 是钥匙串工具以非零状态退出
+type toolError struct {
+	name   string
+	code   int
+	stderr string
+}
+
+func (e *toolError) Error() string {
+	if e.stderr == "" {
+		return fmt.Sprintf("%s exited with status %d", e.name, e.code)
+	}
+	return fmt.Sprintf("%s: %s", e.name, e.stderr)
+}
+
+// notFound 报告 err 是否表示条目不存在：security 返回 44，secret-tool 查不到时以 1 退出且没有输出
+func notFound(err error) bool {
+	var te *toolError
+	if !errors.As(err, &te) {
+		return false
+	}
+	switch te.name {
+	case "security":
+		return te.code == 44
+	case "secret-tool":
+		return te.code == 1 && te.stderr == ""
+	}
+	return false
+}
+
+func (k Keychain) service() string {
+	if k.Service == "" {
+		return DefaultService
+	}
+	return k.Service
+}
+
+// Get 返回条目中保存的秘密，条目不存在时返回 ErrNotFound
+func (k Keychain) Get() (string, error) {
+	var out string
+	var err error
+	switch goos {
+	case "darwin":
+		out, _, err = run("", "security", "find-generic-password", "-s", k.service(), "-a", k.Account, "-w")
+	case "windows":
+		return "", ErrUnsupported
+	default:
+		out, _, err = run("", "secret-tool", "lookup", "service", k.service(), "account", k.Account)
+	}
+	if notFound(err) {
+		return "", fmt.Errorf("%s/%s: %w", k.service(), k.Account, ErrNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	s := strings.TrimSpace(out)
+	if s == "" {
+		return "", fmt.Errorf("%s/%s: %w", k.service(), k.Account, ErrNotFound)
+	}
+	return s, nil
+}
+
+// Set 保存秘密，覆盖已有的条目。秘密只通过标准输入传给钥匙串工具，不出现在命令行参数中：
+// 进程列表对本机所有用户可见，任何本地进程都能用 ps 读到参数
+func (k Keychain) Set(secret string) error {
+	switch goos {
+	case "darwin":
+		// security 的 -w 只接受参数或终端输入，改为在 -i 交互模式下从标准输入读取整条命令
+		line, err := securityCommand("add-generic-password", "-U", "-s", k.service(), "-a", k.Account, "-l", k.service()+" "+k.Account, "-w", secret)
+		if err != nil {
+			return err
+		}
+		_, stderr, err := run(line+"\n", "security", "-i")
+		if err != nil {
+			return err
+		}
+		// 交互模式下命令失败时 security 仍可能以 0 退出，错误只写到标准错误
+		if msg := strings.TrimSpace(stderr); msg != "" {
+			return &toolError{name: "security", stderr: msg}
+		}
+		return nil
+	case "windows":
+		return ErrUnsupported
+	default:
+		_, _, err := run(secret, "secret-tool", "store", "--label", k.service()+" "+k.Account, "service", k.service(), "account", k.Account)
+		return err
+	}
+}
+
+// securityCommand 把参数拼成 security -i 的一行命令：每个参数放在双引号中，转义反斜杠和双引号。
+// 命令按行读取，参数中不能有换行
+func securityCommand(args ...string) (string, error) {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if strings.ContainsAny(a, "\r\n\x00") {
+			return "", errors.New("keychain values must not contain line breaks")
+		}
+		quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(a) + `"`
+	}
+	return strings.Join(quoted, " "), nil
+}
+
+// Delete 删除条目，条目不存在时返回 ErrNotFound
+func (k Keychain) Delete() error {
+	var err error
+	switch goos {
+	case "darwin":
+		_, _, err = run("", "security", "delete-generic-password", "-s", k.service(), "-a", k.Account)
+	case "windows":
+		return ErrUnsupported
+	default:
+		// secret-tool clear 在没有匹配条目时也成功退出，先查询以便报告 ErrNotFound
+		if _, err := k.Get(); err != nil {
+			return err
+		}
+		_, _, err = run("", "secret-tool", "clear", "service", k.service(), "account", k.Account)
+	}
+	if notFound(err) {
+		return fmt.Errorf("%s/%s: %w", k.service(), k.Account, ErrNotFound)
+	}
+	return err
+}
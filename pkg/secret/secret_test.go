@@ -0,0 +1,157 @@
+package secret
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte("  0xabc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := ReadFile(path); err != nil || s != "0xabc" {
+		t.Fatalf("ReadFile = %q, %v", s, err)
+	}
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(path); !errors.Is(err, ErrInsecureFile) {
+		t.Fatalf("world-readable file: %v, want ErrInsecureFile", err)
+	}
+}
+
+// fakeKeychain 模拟 security 和 secret-tool 的退出状态和输出，argv 记录所有调用的命令行参数
+type fakeKeychain struct {
+	entries map[string]string
+	argv    []string
+}
+
+func (f *fakeKeychain) run(stdin string, name string, args ...string) (string, string, error) {
+	f.argv = append(f.argv, args...)
+	if name == "security" && args[0] == "-i" {
+		// 交互模式从标准输入读取一行带引号的命令
+		args = unquoteCommand(strings.TrimSuffix(stdin, "\n"))
+	}
+	// 两种工具的参数里 -s/service 后是服务名，-a/account 后是账户名
+	var service, account string
+	for i := 0; i+1 < len(args); i++ {
+		switch args[i] {
+		case "-s", "service":
+			service = args[i+1]
+		case "-a", "account":
+			account = args[i+1]
+		}
+	}
+	key := service + "/" + account
+	missing := &toolError{name: name, code: 1}
+	if name == "security" {
+		missing.code = 44
+	}
+	switch args[0] {
+	case "lookup", "find-generic-password":
+		if v, ok := f.entries[key]; ok {
+			return v + "\n", "", nil
+		}
+		return "", "", missing
+	case "store":
+		f.entries[key] = stdin
+	case "add-generic-password":
+		f.entries[key] = args[len(args)-1]
+	case "clear":
+		delete(f.entries, key)
+	case "delete-generic-password":
+		if _, ok := f.entries[key]; !ok {
+			return "", "", missing
+		}
+		delete(f.entries, key)
+	}
+	return "", "", nil
+}
+
+// unquoteCommand 按 security -i 的规则拆分 securityCommand 生成的一行命令
+func unquoteCommand(line string) []string {
+	var args []string
+	var cur strings.Builder
+	inQuote, escaped := false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			if inQuote {
+				args = append(args, cur.String())
+				cur.Reset()
+			}
+			inQuote = !inQuote
+		case inQuote:
+			cur.WriteRune(r)
+		}
+	}
+	return args
+}
+
+func TestKeychain(t *testing.T) {
+	defer func(orig func(string, string, ...string) (string, string, error)) { run = orig }(run)
+	defer func(orig string) { goos = orig }(goos)
+
+	const key = `0x4c0883a6"910395b\2`
+	for _, system := range []string{"darwin", "linux"} {
+		t.Run(system, func(t *testing.T) {
+			goos = system
+			f := &fakeKeychain{entries: map[string]string{}}
+			run = f.run
+
+			k := Keychain{Account: "deployer"}
+			if _, err := k.Get(); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get before Set: %v, want ErrNotFound", err)
+			}
+			if err := k.Set(key); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := f.entries[DefaultService+"/deployer"]; !ok {
+				t.Fatalf("entry stored as %v, want service %s", f.entries, DefaultService)
+			}
+			// 秘密不能出现在命令行参数中，否则本机的其他进程可以用 ps 读到
+			for _, arg := range f.argv {
+				if strings.Contains(arg, "4c0883a6") {
+					t.Fatalf("secret passed on the command line: %q", f.argv)
+				}
+			}
+			if s, err := k.Get(); err != nil || s != key {
+				t.Fatalf("Get = %q, %v", s, err)
+			}
+			if err := k.Delete(); err != nil {
+				t.Fatal(err)
+			}
+			if err := k.Delete(); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("second Delete: %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestKeychainInteractiveError(t *testing.T) {
+	defer func(orig func(string, string, ...string) (string, string, error)) { run = orig }(run)
+	defer func(orig string) { goos = orig }(goos)
+	goos = "darwin"
+	run = func(stdin string, name string, args ...string) (string, string, error) {
+		return "", "security: SecKeychainItemCreateFromContent: User interaction is not allowed.\n", nil
+	}
+	if err := (Keychain{Account: "deployer"}).Set("0x01"); err == nil || !strings.Contains(err.Error(), "User interaction") {
+		t.Errorf("Set with an error on stderr = %v", err)
+	}
+	if _, err := securityCommand("-w", "0x01\n"); err == nil {
+		t.Error("securityCommand accepted a line break")
+	}
+}
@@ -0,0 +1,115 @@
+// Package stuckwatch 在交易历史中找出发送后长时间没有打包的交易：仍在交易池中等待（费用太低或前面的 nonce
+// 没有打包）、已被节点丢弃，或者同一 nonce 已经被另一笔交易使用。已经打包的交易把收据写回历史。
+package stuckwatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/txhistory"
+)
+
+// 卡住的交易的状态（Stuck.State）
+const (
+	StatePending  = "pending"  // 仍在节点的交易池中，可以用更高的费用加速
+	StateDropped  = "dropped"  // 节点不知道这笔交易，需要重新广播或用同一 nonce 替换
+	StateReplaced = "replaced" // 同一 nonce 已被另一笔交易使用，这笔交易不会再被打包
+)
+
+// Stuck 是一笔等待时间超过阈值的交易
+type Stuck struct {
+	Record txhistory.Record
+	Age    time.Duration // 从发送到检查时经过的时间
+	State  string
+}
+
+// Message 返回给人看的说明，如 "pending for 15m0s, still in the mempool"
+func (s Stuck) Message() string {
+	var state string
+	switch s.State {
+	case StatePending:
+		state = "still in the mempool"
+	case StateDropped:
+		state = "no longer known to the node"
+	case StateReplaced:
+		state = "its nonce was used by another transaction"
+	}
+	return fmt.Sprintf("pending for %s, %s", s.Age.Round(time.Second), state)
+}
+
+// Detector 检查一条链上的待确认交易，记录已经报告过的交易，以便每笔交易在状态变化前只报告一次。不是并发安全的
+type Detector struct {
+	store    *txhistory.Store
+	chainID  uint64
+	after    time.Duration
+	reported map[common.Hash]string // 已报告的交易和报告时的状态
+}
+
+// New 创建检查 store 中 chainID 链上交易的 Detector，发送超过 after 仍没有打包的交易视为卡住
+func New(store *txhistory.Store, chainID uint64, after time.Duration) *Detector {
+	return &Detector{store: store, chainID: chainID, after: after, reported: make(map[common.Hash]string)}
+}
+
+// Check 检查 since 之后发送、历史中仍为待确认的交易，返回新卡住或状态有变化的交易，最近发送的在前。
+// 节点上已有收据的交易写回历史；单笔交易查询失败时跳过它，继续检查其余交易，并返回合并的错误
+func (d *Detector) Check(ctx context.Context, client chain.Client, since, now time.Time) ([]Stuck, error) {
+	records, err := d.store.Pending(since)
+	if err != nil {
+		return nil, err
+	}
+	var stuck []Stuck
+	var errs []error
+	for _, r := range records {
+		age := now.Sub(r.SentAt)
+		if r.ChainID != d.chainID || age < d.after {
+			continue
+		}
+		receipt, err := client.TransactionReceipt(ctx, r.Hash)
+		if err == nil {
+			delete(d.reported, r.Hash)
+			if err := d.store.SetReceipt(receipt, now); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", r.Hash.Hex(), err))
+			}
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Hash.Hex(), err))
+			continue
+		}
+		state, err := d.state(ctx, client, r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Hash.Hex(), err))
+			continue
+		}
+		if d.reported[r.Hash] == state {
+			continue
+		}
+		d.reported[r.Hash] = state
+		stuck = append(stuck, Stuck{Record: r, Age: age, State: state})
+	}
+	return stuck, errors.Join(errs...)
+}
+
+// state 判断一笔没有收据的交易是仍在交易池中、已被丢弃还是已被替换
+func (d *Detector) state(ctx context.Context, client chain.Client, r txhistory.Record) (string, error) {
+	_, _, err := client.TransactionByHash(ctx, r.Hash)
+	if err == nil {
+		return StatePending, nil
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return "", err
+	}
+	nonce, err := client.NonceAt(ctx, r.From, nil)
+	if err != nil {
+		return "", err
+	}
+	if nonce > r.Nonce {
+		return StateReplaced, nil
+	}
+	return StateDropped, nil
+}
@@ -0,0 +1,124 @@
+package stuckwatch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/local/go-eth-demo/pkg/chain"
+	"github.com/local/go-eth-demo/pkg/txhistory"
+)
+
+var sender = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+// fakeChain 按哈希返回收据和交易池中的交易，账户 nonce 可以修改
+type fakeChain struct {
+	receipts map[common.Hash]*types.Receipt
+	mempool  map[common.Hash]bool
+	nonce    uint64
+	fail     bool
+}
+
+func (f *fakeChain) client() *chain.ClientMock {
+	return &chain.ClientMock{
+		TransactionReceiptFunc: func(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+			if f.fail {
+				return nil, errors.New("connection refused")
+			}
+			if r, ok := f.receipts[hash]; ok {
+				return r, nil
+			}
+			return nil, ethereum.NotFound
+		},
+		TransactionByHashFunc: func(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+			if f.mempool[hash] {
+				return types.NewTx(&types.LegacyTx{}), true, nil
+			}
+			return nil, false, ethereum.NotFound
+		},
+		NonceAtFunc: func(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+			return f.nonce, nil
+		},
+	}
+}
+
+func record(n byte, chainID, nonce uint64, sentAt time.Time) txhistory.Record {
+	return txhistory.Record{
+		Hash: common.Hash{n}, ChainID: chainID, From: sender, Nonce: nonce, Value: "0",
+		Status: txhistory.StatusPending, SentAt: sentAt,
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	store := txhistory.NewStore(filepath.Join(t.TempDir(), "history.db"))
+	defer store.Close()
+	records := []txhistory.Record{
+		record(1, 11155111, 5, now.Add(-time.Hour)),      // 仍在交易池中
+		record(2, 11155111, 6, now.Add(-50*time.Minute)), // 节点已丢弃
+		record(3, 11155111, 2, now.Add(-40*time.Minute)), // nonce 已被使用
+		record(4, 11155111, 7, now.Add(-30*time.Minute)), // 已经打包
+		record(5, 11155111, 8, now.Add(-time.Minute)),    // 还没到阈值
+		record(6, 1, 9, now.Add(-time.Hour)),             // 其他链
+		record(7, 11155111, 1, now.Add(-48*time.Hour)),   // since 之前发送
+	}
+	for _, r := range records {
+		if err := store.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f := &fakeChain{
+		receipts: map[common.Hash]*types.Receipt{
+			{4}: {Status: types.ReceiptStatusSuccessful, TxHash: common.Hash{4}, BlockNumber: big.NewInt(100), GasUsed: 21000},
+		},
+		mempool: map[common.Hash]bool{{1}: true},
+		nonce:   5,
+	}
+	d := New(store, 11155111, 10*time.Minute)
+	since := now.Add(-24 * time.Hour)
+
+	stuck, err := d.Check(context.Background(), f.client(), since, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		hash  common.Hash
+		state string
+	}{{common.Hash{3}, StateReplaced}, {common.Hash{2}, StateDropped}, {common.Hash{1}, StatePending}}
+	if len(stuck) != len(want) {
+		t.Fatalf("got %d stuck transactions, want %d: %+v", len(stuck), len(want), stuck)
+	}
+	for i, w := range want {
+		if stuck[i].Record.Hash != w.hash || stuck[i].State != w.state {
+			t.Errorf("stuck[%d] = %s %s, want %s %s", i, stuck[i].Record.Hash, stuck[i].State, w.hash, w.state)
+		}
+	}
+	if got := stuck[2].Message(); got != "pending for 1h0m0s, still in the mempool" {
+		t.Errorf("Message() = %q", got)
+	}
+	if r, err := store.Get(common.Hash{4}.Hex()); err != nil || r.Status != txhistory.StatusSuccess || r.Block != 100 {
+		t.Errorf("mined transaction in history = %+v, %v", r, err)
+	}
+
+	// 状态没有变化的交易不再报告，状态变化后再报告一次
+	f.mempool = nil
+	stuck, err = d.Check(context.Background(), f.client(), since, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stuck) != 1 || stuck[0].Record.Hash != (common.Hash{1}) || stuck[0].State != StateDropped {
+		t.Errorf("second check = %+v, want only the dropped 0x01", stuck)
+	}
+
+	// 查询失败时返回错误，不报告
+	f.fail = true
+	if stuck, err := d.Check(context.Background(), f.client(), since, now); err == nil || len(stuck) != 0 {
+		t.Errorf("check with a failing node = %+v, %v", stuck, err)
+	}
+}
@@ -0,0 +1,184 @@
+// Package tracing 记录交易生命周期各步骤（读取 nonce、估算 gas、签名、广播、等待打包）和其中每个
+// JSON-RPC 请求的耗时，按 OpenTelemetry 的 OTLP/HTTP JSON 格式导出到 Jaeger、Tempo 或 OTel Collector，
+// 便于看出慢的 RPC 端点拖慢了哪一步。这里只实现了 span 的父子关系、属性和错误状态，不依赖 OpenTelemetry SDK。
+//
+// 没有通过 SetDefault 设置 Tracer 时，Start 返回 nil span，所有方法都是空操作，库代码可以无条件调用。
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TraceID 和 SpanID 是 W3C Trace Context 格式的标识
+type (
+	TraceID [16]byte
+	SpanID  [8]byte
+)
+
+// Kind 是 span 的类型，取值与 OTLP 相同
+type Kind int
+
+const (
+	KindInternal Kind = 1 // 进程内的步骤
+	KindClient   Kind = 3 // 发往节点的请求
+)
+
+// Span 是一次被计时的操作。nil Span 的方法都是空操作
+type Span struct {
+	tracer *Tracer
+	Trace  TraceID
+	ID     SpanID
+	Parent SpanID // 根 span 为零值
+	Name   string
+	Kind   Kind
+	Start  time.Time
+
+	mu    sync.Mutex
+	attrs map[string]string
+	end   time.Time
+	err   error
+}
+
+// SetAttr 设置一个属性，v 用 fmt 的 %v 格式化
+func (s *Span) SetAttr(key string, v interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attrs == nil {
+		s.attrs = make(map[string]string)
+	}
+	s.attrs[key] = fmt.Sprint(v)
+}
+
+// End 结束 span，err 不为 nil 时标记为错误。结束的是根 span 时导出整个 trace，导出错误交给 Tracer.OnError
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.end, s.err = time.Now(), err
+	s.mu.Unlock()
+	s.tracer.finish(s)
+}
+
+// Exporter 导出一批已结束的 span
+type Exporter interface {
+	Export(ctx context.Context, spans []*Span) error
+}
+
+// maxBatch 是根 span 结束前最多缓存的 span 数，serve、watch 等常驻命令的根 span 一直不结束，
+// 达到该数量时先导出已结束的部分
+const maxBatch = 512
+
+// Tracer 收集已结束的 span，在根 span 结束时一并导出
+type Tracer struct {
+	exporter Exporter
+	// OnError 在导出失败时调用，为 nil 时忽略导出错误
+	OnError func(err error)
+
+	mu    sync.Mutex
+	root  *Span
+	spans []*Span
+}
+
+// New 创建通过 exporter 导出的 Tracer
+func New(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+// SetDefault 设置 Start 使用的 Tracer，t 为 nil 时关闭追踪
+func SetDefault(t *Tracer) {
+	defaultTracer.Store(t)
+}
+
+// Enabled 报告是否设置了默认 Tracer
+func Enabled() bool {
+	return defaultTracer.Load() != nil
+}
+
+type spanKey struct{}
+
+// FromContext 返回 ctx 中的当前 span，没有时为 nil
+func FromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// Start 在默认 Tracer 上开始一个 span，返回携带该 span 的 ctx。父 span 取自 ctx；ctx 中没有 span 时
+// 使用 StartRoot 开始的根 span，这样不传递 ctx 的命令中的步骤也属于同一个 trace
+func Start(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	t := defaultTracer.Load()
+	if t == nil {
+		return ctx, nil
+	}
+	parent := FromContext(ctx)
+	if parent == nil {
+		t.mu.Lock()
+		parent = t.root
+		t.mu.Unlock()
+	}
+	s := &Span{tracer: t, ID: newSpanID(), Name: name, Kind: kind, Start: time.Now()}
+	if parent != nil {
+		s.Trace, s.Parent = parent.Trace, parent.ID
+	} else {
+		s.Trace = newTraceID()
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// StartRoot 在默认 Tracer 上开始新的 trace，之前未结束的根 span 被丢弃。一个任务或命令对应一个根 span
+func StartRoot(name string) *Span {
+	t := defaultTracer.Load()
+	if t == nil {
+		return nil
+	}
+	s := &Span{tracer: t, Trace: newTraceID(), ID: newSpanID(), Name: name, Kind: KindInternal, Start: time.Now()}
+	t.mu.Lock()
+	t.root, t.spans = s, nil
+	t.mu.Unlock()
+	return s
+}
+
+func (t *Tracer) finish(s *Span) {
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	if s != t.root && t.root != nil && len(t.spans) < maxBatch {
+		t.mu.Unlock()
+		return
+	}
+	spans := t.spans
+	if s == t.root {
+		t.root = nil
+	}
+	t.spans = nil
+	t.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := t.exporter.Export(ctx, spans); err != nil && t.OnError != nil {
+		t.OnError(err)
+	}
+}
+
+func newTraceID() (id TraceID) {
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	rand.Read(id[:])
+	return id
+}
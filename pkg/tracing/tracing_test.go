@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// collector 是接收 OTLP/HTTP JSON 的模拟 Collector，返回收到的所有 span
+func collector(t *testing.T) (*httptest.Server, *[]otlpSpan) {
+	var spans []otlpSpan
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("x-api-key") != "secret" {
+			t.Errorf("unexpected export %s %s %v", r.Method, r.URL.Path, r.Header)
+		}
+		var req otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		for _, rs := range req.ResourceSpans {
+			if rs.Resource.Attributes[0].Value.StringValue != "test" {
+				t.Errorf("service.name = %+v", rs.Resource.Attributes)
+			}
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &spans
+}
+
+func TestTrace(t *testing.T) {
+	srv, spans := collector(t)
+	SetDefault(New(&OTLP{Endpoint: srv.URL, ServiceName: "test", Headers: map[string]string{"x-api-key": "secret"}}))
+	t.Cleanup(func() { SetDefault(nil) })
+
+	var traceparent string
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":"0x5"}`)
+	}))
+	defer node.Close()
+	client := &http.Client{Transport: Transport(nil)}
+
+	root := StartRoot("task01")
+	// 不带 span 的 ctx 中开始的步骤挂在根 span 下
+	ctx, step := Start(context.Background(), "fetch nonce", KindInternal)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, node.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionCount","params":[]}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	step.SetAttr("tx.nonce", 5)
+	step.End(nil)
+	_, failed := Start(context.Background(), "broadcast", KindInternal)
+	failed.End(errors.New("nonce too low"))
+	if len(*spans) != 0 {
+		t.Fatalf("exported %d spans before the root ended", len(*spans))
+	}
+	root.End(nil)
+
+	byName := make(map[string]otlpSpan)
+	for _, s := range *spans {
+		byName[s.Name] = s
+	}
+	if len(*spans) != 4 {
+		t.Fatalf("exported spans %v, want 4", byName)
+	}
+	rootSpan, step2, rpc, broadcast := byName["task01"], byName["fetch nonce"], byName["rpc eth_getTransactionCount"], byName["broadcast"]
+	if rootSpan.ParentSpanID != "" || step2.ParentSpanID != rootSpan.SpanID || rpc.ParentSpanID != step2.SpanID || broadcast.ParentSpanID != rootSpan.SpanID {
+		t.Errorf("wrong span tree: %+v", *spans)
+	}
+	for _, s := range *spans {
+		if s.TraceID != rootSpan.TraceID {
+			t.Errorf("span %s in trace %s, want %s", s.Name, s.TraceID, rootSpan.TraceID)
+		}
+	}
+	if rpc.Kind != KindClient || traceparent != "00-"+rpc.TraceID+"-"+rpc.SpanID+"-01" {
+		t.Errorf("rpc span kind %d, traceparent %q", rpc.Kind, traceparent)
+	}
+	if len(step2.Attributes) != 1 || step2.Attributes[0] != (otlpAttr{"tx.nonce", otlpValue{"5"}}) {
+		t.Errorf("fetch nonce attributes = %+v", step2.Attributes)
+	}
+	if broadcast.Status != (otlpStatus{Code: 2, Message: "nonce too low"}) || step2.Status.Code != 1 {
+		t.Errorf("status: broadcast %+v, fetch nonce %+v", broadcast.Status, step2.Status)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	ctx, s := Start(context.Background(), "sign", KindInternal)
+	if s != nil || FromContext(ctx) != nil || StartRoot("task") != nil {
+		t.Fatal("spans started without a tracer")
+	}
+	s.SetAttr("k", "v")
+	s.End(nil)
+}
+
+func TestParseHeaders(t *testing.T) {
+	h, err := ParseHeaders("x-honeycomb-team=abc, Authorization=Basic%20dXNlcg==")
+	if err != nil || h["x-honeycomb-team"] != "abc" || h["Authorization"] != "Basic dXNlcg==" {
+		t.Errorf("ParseHeaders = %v, %v", h, err)
+	}
+	if _, err := ParseHeaders("novalue"); err == nil {
+		t.Error("ParseHeaders accepted a header without =")
+	}
+}
@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Transport 返回为每个 JSON-RPC HTTP 请求记录一个 client span 的 http.RoundTripper，span 名为 "rpc " 加方法名
+// （批量请求为逗号分隔的方法名），父 span 取自请求的 ctx（go-ethereum 的 rpc 客户端会传递调用时的 ctx）。
+// 请求带上 W3C traceparent 头，支持的节点或网关可以把自己的 span 接到同一个 trace 上。
+// 没有设置默认 Tracer 时直接转发。next 为 nil 时使用 http.DefaultTransport。
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Enabled() {
+		return t.next.RoundTrip(req)
+	}
+	var methods []string
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		methods = rpcMethods(body)
+	}
+	ctx, span := Start(req.Context(), "rpc "+strings.Join(methods, ","), KindClient)
+	span.SetAttr("rpc.system", "jsonrpc")
+	span.SetAttr("server.address", req.URL.Host)
+	if len(methods) == 1 {
+		span.SetAttr("rpc.method", methods[0])
+	}
+	req = req.Clone(ctx)
+	req.Header.Set("traceparent", "00-"+hex.EncodeToString(span.Trace[:])+"-"+hex.EncodeToString(span.ID[:])+"-01")
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		span.SetAttr("http.response.status_code", resp.StatusCode)
+		if resp.StatusCode/100 != 2 {
+			err = httpError(resp.StatusCode)
+		}
+		span.End(err)
+		return resp, nil
+	}
+	span.End(err)
+	return resp, err
+}
+
+type httpError int
+
+func (e httpError) Error() string {
+	return fmt.Sprintf("HTTP %d %s", int(e), http.StatusText(int(e)))
+}
+
+// rpcMethods 提取单个或批量 JSON-RPC 请求中的方法名
+func rpcMethods(body []byte) []string {
+	type call struct {
+		Method string `json:"method"`
+	}
+	var batch []call
+	if json.Unmarshal(body, &batch) != nil {
+		var single call
+		if json.Unmarshal(body, &single) != nil {
+			return nil
+		}
+		batch = []call{single}
+	}
+	methods := make([]string, 0, len(batch))
+	for _, c := range batch {
+		if c.Method != "" {
+			methods = append(methods, c.Method)
+		}
+	}
+	return methods
+}
@@ -0,0 +1,126 @@
+package txhistory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Transport 返回记录交易的 http.RoundTripper：节点接受的 eth_sendRawTransaction 加入历史，
+// eth_getTransactionReceipt 返回的收据更新历史中对应交易的状态。记录失败不影响请求本身，
+// 错误交给 onError（可以为 nil）。无法解码或恢复发送方的交易（如 zkSync 的 EIP-712 交易）不记录。
+// next 为 nil 时使用 http.DefaultTransport。
+func Transport(s *Store, next http.RoundTripper, onError func(error)) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if onError == nil {
+		onError = func(error) {}
+	}
+	return &transport{store: s, next: next, onError: onError, now: time.Now}
+}
+
+type transport struct {
+	store   *Store
+	next    http.RoundTripper
+	onError func(error)
+	now     func() time.Time
+}
+
+// rpcMessage 是 JSON-RPC 请求或响应中用到的字段
+type rpcMessage struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Result json.RawMessage   `json:"result"`
+	Error  json.RawMessage   `json:"error"`
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	// 只关心发送交易和查询收据的请求，按 id 对应到响应
+	watched := make(map[string]rpcMessage)
+	for _, m := range decodeMessages(body) {
+		if m.Method == "eth_sendRawTransaction" || m.Method == "eth_getTransactionReceipt" {
+			watched[string(m.ID)] = m
+		}
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || len(watched) == 0 || resp.StatusCode/100 != 2 {
+		return resp, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	for _, m := range decodeMessages(respBody) {
+		call, ok := watched[string(m.ID)]
+		if !ok || len(m.Error) > 0 || len(m.Result) == 0 || string(m.Result) == "null" {
+			continue
+		}
+		if err := t.record(call, m.Result); err != nil {
+			t.onError(err)
+		}
+	}
+	return resp, nil
+}
+
+// record 处理一个成功的调用：call 是请求，result 是响应中的结果
+func (t *transport) record(call rpcMessage, result json.RawMessage) error {
+	switch call.Method {
+	case "eth_sendRawTransaction":
+		var raw hexutil.Bytes
+		if len(call.Params) == 0 || json.Unmarshal(call.Params[0], &raw) != nil {
+			return nil
+		}
+		tx := new(types.Transaction)
+		if tx.UnmarshalBinary(raw) != nil {
+			return nil
+		}
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return nil
+		}
+		return t.store.Add(NewRecord(tx, from, t.now()))
+	case "eth_getTransactionReceipt":
+		var receipt types.Receipt
+		if err := json.Unmarshal(result, &receipt); err != nil {
+			return nil
+		}
+		if err := t.store.SetReceipt(&receipt, t.now()); !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeMessages 解析单个或批量 JSON-RPC 消息
+func decodeMessages(body []byte) []rpcMessage {
+	var batch []rpcMessage
+	if json.Unmarshal(body, &batch) == nil {
+		return batch
+	}
+	var single rpcMessage
+	if json.Unmarshal(body, &single) != nil {
+		return nil
+	}
+	return []rpcMessage{single}
+}
@@ -0,0 +1,345 @@
+// Package txhistory 在本地 SQLite 数据库中记录发送过的交易：哈希、nonce、接收方、金额、gas 参数，
+// 以及看到收据后的状态、区块和实际费用。Transport 在 RPC 层记录，所有经过它的 eth_sendRawTransaction
+// 都会被记下，不需要每个命令分别处理。数据库由 SQLite 加锁，多个命令同时运行时可以安全地读写同一份历史。
+package txhistory
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	_ "modernc.org/sqlite" // 纯 Go 实现，不需要 cgo
+)
+
+// ErrNotFound 表示历史中没有该交易
+var ErrNotFound = errors.New("transaction not in history")
+
+// 交易状态
+const (
+	StatusPending  = "pending"
+	StatusSuccess  = "success"
+	StatusReverted = "reverted"
+)
+
+// Record 是一笔发送过的交易。数量都是十进制的 wei 字符串
+type Record struct {
+	Hash     common.Hash     `json:"hash"`
+	ChainID  uint64          `json:"chainId"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to,omitempty"` // 部署合约时为空
+	Nonce    uint64          `json:"nonce"`
+	Value    string          `json:"value"`
+	Gas      uint64          `json:"gas"`
+	GasPrice string          `json:"gasPrice,omitempty"` // 传统交易
+	// EIP-1559 交易的费用上限
+	MaxFeePerGas         string `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas,omitempty"`
+
+	Status            string    `json:"status"`
+	Block             uint64    `json:"block,omitempty"`
+	GasUsed           uint64    `json:"gasUsed,omitempty"`
+	EffectiveGasPrice string    `json:"effectiveGasPrice,omitempty"`
+	SentAt            time.Time `json:"sentAt"`
+	MinedAt           time.Time `json:"minedAt,omitzero"` // 第一次看到收据的时间
+}
+
+// NewRecord 从刚广播的已签名交易创建待确认的记录
+func NewRecord(tx *types.Transaction, from common.Address, sentAt time.Time) Record {
+	r := Record{
+		Hash:    tx.Hash(),
+		ChainID: tx.ChainId().Uint64(),
+		From:    from,
+		To:      tx.To(),
+		Nonce:   tx.Nonce(),
+		Value:   tx.Value().String(),
+		Gas:     tx.Gas(),
+		Status:  StatusPending,
+		SentAt:  sentAt.UTC(),
+	}
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		r.GasPrice = tx.GasPrice().String()
+	} else {
+		r.MaxFeePerGas = tx.GasFeeCap().String()
+		r.MaxPriorityFeePerGas = tx.GasTipCap().String()
+	}
+	return r
+}
+
+// busyTimeout 是等待其他进程释放数据库锁的最长时间
+const busyTimeout = 5 * time.Second
+
+// schema 创建交易表和索引：哈希为主键，Pending 按状态和发送时间查询，List 按发送时间排序。
+// 时间以 Unix 纳秒保存，数量以十进制字符串保存
+const schema = `
+CREATE TABLE IF NOT EXISTS transactions (
+	hash                     TEXT PRIMARY KEY,
+	chain_id                 INTEGER NOT NULL,
+	from_addr                TEXT NOT NULL,
+	to_addr                  TEXT,
+	nonce                    INTEGER NOT NULL,
+	value                    TEXT NOT NULL,
+	gas                      INTEGER NOT NULL,
+	gas_price                TEXT,
+	max_fee_per_gas          TEXT,
+	max_priority_fee_per_gas TEXT,
+	status                   TEXT NOT NULL,
+	block                    INTEGER,
+	gas_used                 INTEGER,
+	effective_gas_price      TEXT,
+	sent_at                  INTEGER NOT NULL,
+	mined_at                 INTEGER
+);
+CREATE INDEX IF NOT EXISTS transactions_status ON transactions (status);
+CREATE INDEX IF NOT EXISTS transactions_sent_at ON transactions (sent_at);
+`
+
+// columns 是 scan 读取的列，顺序与 Record 的字段一致
+const columns = `hash, chain_id, from_addr, to_addr, nonce, value, gas, gas_price, max_fee_per_gas,
+	max_priority_fee_per_gas, status, block, gas_used, effective_gas_price, sent_at, mined_at`
+
+// Store 是保存在 path 中的交易历史。数据库在第一次使用时打开（不存在时创建），
+// 每个操作都是一条 SQL 语句，多个进程同时写入时由 SQLite 的锁串行化
+type Store struct {
+	path string
+
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+// NewStore 返回保存在 path 的历史
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// open 打开数据库并创建表。WAL 模式下读取不会阻塞其他进程的写入，写入时锁被占用则最多等待 busyTimeout
+func (s *Store) open() (*sql.DB, error) {
+	s.once.Do(func() {
+		dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)", s.path, busyTimeout.Milliseconds())
+		db, err := sql.Open("sqlite", dsn)
+		if err == nil {
+			_, err = db.Exec(schema)
+		}
+		if err != nil {
+			if db != nil {
+				db.Close()
+			}
+			s.err = fmt.Errorf("open transaction history %s: %w", s.path, err)
+			return
+		}
+		s.db = db
+	})
+	return s.db, s.err
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Add 记录一笔交易，已有同一哈希的记录时保留原来的记录（同一交易可能被重复广播）
+func (s *Store) Add(r Record) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	_, err = insert(db, r)
+	return err
+}
+
+// insert 插入一条记录，报告是否是新的哈希
+func insert(db *sql.DB, r Record) (bool, error) {
+	var to sql.NullString
+	if r.To != nil {
+		to = nullString(r.To.Hex())
+	}
+	res, err := db.Exec(`INSERT INTO transactions (`+columns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (hash) DO NOTHING`,
+		r.Hash.Hex(), int64(r.ChainID), r.From.Hex(), to, int64(r.Nonce), r.Value, int64(r.Gas),
+		nullString(r.GasPrice), nullString(r.MaxFeePerGas), nullString(r.MaxPriorityFeePerGas),
+		r.Status, nullInt(r.Block), nullInt(r.GasUsed), nullString(r.EffectiveGasPrice),
+		r.SentAt.UnixNano(), nullTime(r.MinedAt))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// SetReceipt 用收据更新历史中的交易，不在历史中的交易返回 ErrNotFound。
+// 状态和区块都没有变化时不写入；第一次看到收据的时间保留最早的值
+func (s *Store) SetReceipt(receipt *types.Receipt, now time.Time) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	status := StatusSuccess
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = StatusReverted
+	}
+	var price *string
+	if receipt.EffectiveGasPrice != nil {
+		v := receipt.EffectiveGasPrice.String()
+		price = &v
+	}
+	block := int64(receipt.BlockNumber.Uint64())
+	res, err := db.Exec(`UPDATE transactions
+		SET status = ?, block = ?, gas_used = ?, effective_gas_price = coalesce(?, effective_gas_price),
+			mined_at = coalesce(mined_at, ?)
+		WHERE hash = ? AND (status <> ? OR block IS NOT ?)`,
+		status, block, int64(receipt.GasUsed), price, now.UnixNano(), receipt.TxHash.Hex(), status, block)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	var exists int
+	err = db.QueryRow(`SELECT 1 FROM transactions WHERE hash = ?`, receipt.TxHash.Hex()).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// List 返回所有记录，最近发送的在前
+func (s *Store) List() ([]Record, error) {
+	return s.query(`SELECT ` + columns + ` FROM transactions ORDER BY sent_at DESC, rowid`)
+}
+
+// Pending 返回 since 之后发送、还没有看到收据的交易，最近发送的在前
+func (s *Store) Pending(since time.Time) ([]Record, error) {
+	return s.query(`SELECT `+columns+` FROM transactions WHERE status = ? AND sent_at >= ? ORDER BY sent_at DESC, rowid`,
+		StatusPending, since.UnixNano())
+}
+
+// Get 按哈希查找交易，hash 可以是完整哈希或不少于 6 位十六进制的前缀（如 tx list 显示的缩写）
+func (s *Store) Get(hash string) (Record, error) {
+	prefix := strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(hash, "…"), "0x"))
+	if len(prefix) < 6 {
+		return Record{}, fmt.Errorf("transaction hash prefix %q is too short", hash)
+	}
+	// 哈希以 Hex() 的小写形式保存，按前缀比较
+	found, err := s.query(`SELECT `+columns+` FROM transactions WHERE substr(hash, 1, ?) = ?`, len(prefix)+2, "0x"+prefix)
+	if err != nil {
+		return Record{}, err
+	}
+	switch len(found) {
+	case 0:
+		return Record{}, fmt.Errorf("%s: %w", hash, ErrNotFound)
+	case 1:
+		return found[0], nil
+	}
+	return Record{}, fmt.Errorf("transaction hash prefix %q matches %d transactions", hash, len(found))
+}
+
+// Import 把旧版本保存的 JSON 历史文件中的记录加入数据库，返回新加入的记录数，已有的哈希保持不变
+func (s *Store) Import(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return 0, fmt.Errorf("parse transaction history %s: %w", path, err)
+	}
+	db, err := s.open()
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, r := range records {
+		ok, err := insert(db, r)
+		if err != nil {
+			return added, err
+		}
+		if ok {
+			added++
+		}
+	}
+	return added, nil
+}
+
+func (s *Store) query(query string, args ...any) ([]Record, error) {
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var records []Record
+	for rows.Next() {
+		r, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// scan 读取 columns 中的一行
+func scan(rows *sql.Rows) (Record, error) {
+	var (
+		r                                  Record
+		hash, from                         string
+		to, gasPrice, maxFee, maxTip, paid sql.NullString
+		chainID, nonce, gas, sentAt        int64
+		block, gasUsed, minedAt            sql.NullInt64
+	)
+	err := rows.Scan(&hash, &chainID, &from, &to, &nonce, &r.Value, &gas, &gasPrice, &maxFee, &maxTip,
+		&r.Status, &block, &gasUsed, &paid, &sentAt, &minedAt)
+	if err != nil {
+		return Record{}, err
+	}
+	if !isHash(hash) || !common.IsHexAddress(from) {
+		return Record{}, fmt.Errorf("corrupt transaction history row %q", hash)
+	}
+	r.Hash, r.From = common.HexToHash(hash), common.HexToAddress(from)
+	if to.Valid {
+		addr := common.HexToAddress(to.String)
+		r.To = &addr
+	}
+	r.ChainID, r.Nonce, r.Gas = uint64(chainID), uint64(nonce), uint64(gas)
+	r.GasPrice, r.MaxFeePerGas, r.MaxPriorityFeePerGas = gasPrice.String, maxFee.String, maxTip.String
+	r.Block, r.GasUsed, r.EffectiveGasPrice = uint64(block.Int64), uint64(gasUsed.Int64), paid.String
+	r.SentAt = time.Unix(0, sentAt).UTC()
+	if minedAt.Valid {
+		r.MinedAt = time.Unix(0, minedAt.Int64).UTC()
+	}
+	return r, nil
+}
+
+func isHash(s string) bool {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	return err == nil && len(b) == common.HashLength
+}
+
+// nullString 把空字符串保存为 NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullInt 把 0 保存为 NULL（未打包交易的区块号和 gas 用量）
+func nullInt(v uint64) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
+
+func nullTime(t time.Time) sql.NullInt64 {
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: !t.IsZero()}
+}
@@ -0,0 +1,226 @@
+package txhistory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// node 是只实现 eth_sendRawTransaction 和 eth_getTransactionReceipt 的模拟节点，
+// 收到交易后返回 receipt 给出的收据
+func node(t *testing.T, receipt *types.Receipt) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		var result interface{}
+		switch req.Method {
+		case "eth_sendRawTransaction":
+			result = receipt.TxHash
+		case "eth_getTransactionReceipt":
+			result = receipt
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTransport(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(31337)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(31337), Nonce: 3, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(2e9),
+		Gas: 21000, To: &to, Value: big.NewInt(1e15),
+	})
+	receipt := &types.Receipt{
+		Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash(), BlockNumber: big.NewInt(42),
+		GasUsed: 21000, EffectiveGasPrice: big.NewInt(15e8), Logs: []*types.Log{},
+	}
+	srv := node(t, receipt)
+
+	store := NewStore(filepath.Join(t.TempDir(), "history.db"))
+	defer store.Close()
+	var recordErr error
+	c, err := rpc.DialOptions(context.Background(), srv.URL, rpc.WithHTTPClient(&http.Client{
+		Transport: Transport(store, nil, func(err error) { recordErr = err }),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := ethclient.NewClient(c)
+	defer client.Close()
+
+	if err := client.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	r, err := store.Get(tx.Hash().Hex()[:10])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != StatusPending || r.From != crypto.PubkeyToAddress(key.PublicKey) || *r.To != to ||
+		r.Nonce != 3 || r.Value != "1000000000000000" || r.MaxFeePerGas != "2000000000" || r.SentAt.IsZero() {
+		t.Errorf("record after send = %+v", r)
+	}
+
+	if _, err := client.TransactionReceipt(context.Background(), tx.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ = store.Get(tx.Hash().Hex()); r.Status != StatusSuccess || r.Block != 42 || r.GasUsed != 21000 ||
+		r.EffectiveGasPrice != "1500000000" || r.MinedAt.IsZero() {
+		t.Errorf("record after receipt = %+v", r)
+	}
+	if recordErr != nil {
+		t.Errorf("recording failed: %v", recordErr)
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	store := NewStore(path)
+	defer store.Close()
+	if records, err := store.List(); err != nil || len(records) != 0 {
+		t.Fatalf("List on a new database = %v, %v", records, err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	for i, h := range []common.Hash{{0xab, 0xcd, 0xef, 1}, {0xab, 0xcd, 0xef, 2}} {
+		if err := store.Add(Record{Hash: h, Status: StatusPending, SentAt: now.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, _ := store.List()
+	if len(records) != 2 || records[0].Hash[3] != 2 {
+		t.Errorf("List = %+v, want newest first", records)
+	}
+	if _, err := store.Get("0xabcdef"); err == nil {
+		t.Error("Get with an ambiguous prefix succeeded")
+	}
+	if _, err := store.Get("0x1234567890"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(unknown) = %v, want ErrNotFound", err)
+	}
+	unknown := &types.Receipt{TxHash: common.Hash{9}, BlockNumber: big.NewInt(1)}
+	if err := store.SetReceipt(unknown, now); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SetReceipt(unknown) = %v, want ErrNotFound", err)
+	}
+
+	// Pending 只返回 since 之后发送且未确认的交易
+	mined := &types.Receipt{TxHash: common.Hash{0xab, 0xcd, 0xef, 2}, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(7)}
+	if err := store.SetReceipt(mined, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(Record{Hash: common.Hash{3}, Status: StatusPending, SentAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := store.Pending(now)
+	if err != nil || len(pending) != 1 || pending[0].Hash[3] != 1 {
+		t.Errorf("Pending = %+v, %v, want the first transaction", pending, err)
+	}
+
+	// 重复广播的交易保留原来的记录，收据不变时不更新
+	if err := store.Add(Record{Hash: common.Hash{0xab, 0xcd, 0xef, 2}, Status: StatusPending, SentAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetReceipt(mined, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// 另一个进程打开同一个数据库看到相同的历史
+	other := NewStore(path)
+	defer other.Close()
+	r, err := other.Get("0xabcdef02")
+	if err != nil || r.Status != StatusSuccess || r.Block != 7 || !r.MinedAt.Equal(now) || !r.SentAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Get from a second store = %+v, %v", r, err)
+	}
+}
+
+// 多个 Store（对应多个同时运行的命令）并发写入同一个数据库，不丢失记录
+func TestStoreConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	const writers, each = 4, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*each)
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := NewStore(path)
+			defer store.Close()
+			for i := range each {
+				r := Record{Hash: common.Hash{byte(w), byte(i)}, Status: StatusPending, SentAt: time.Now()}
+				if err := store.Add(r); err != nil {
+					errs <- err
+				}
+				receipt := &types.Receipt{TxHash: r.Hash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(int64(i + 1))}
+				if err := store.SetReceipt(receipt, time.Now()); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	store := NewStore(path)
+	defer store.Close()
+	records, err := store.List()
+	if err != nil || len(records) != writers*each {
+		t.Fatalf("List = %d records, %v, want %d", len(records), err, writers*each)
+	}
+	if pending, err := store.Pending(time.Time{}); err != nil || len(pending) != 0 {
+		t.Errorf("Pending = %d records, %v, want none", len(pending), err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	legacy := []Record{
+		{Hash: common.Hash{1}, ChainID: 11155111, To: &to, Nonce: 1, Value: "1", Gas: 21000, GasPrice: "5", Status: StatusPending, SentAt: time.Unix(1_700_000_000, 0).UTC()},
+		{Hash: common.Hash{2}, ChainID: 11155111, Nonce: 2, Value: "0", Gas: 90000, MaxFeePerGas: "9", MaxPriorityFeePerGas: "1",
+			Status: StatusSuccess, Block: 12, GasUsed: 80000, EffectiveGasPrice: "4", SentAt: time.Unix(1_700_000_060, 0).UTC(), MinedAt: time.Unix(1_700_000_072, 0).UTC()},
+	}
+	data, err := json.Marshal(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(dir, "history.json")
+	if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore(filepath.Join(dir, "history.db"))
+	defer store.Close()
+	if n, err := store.Import(jsonPath); err != nil || n != 2 {
+		t.Fatalf("Import = %d, %v, want 2", n, err)
+	}
+	if n, err := store.Import(jsonPath); err != nil || n != 0 {
+		t.Errorf("second Import = %d, %v, want 0", n, err)
+	}
+	records, err := store.List()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("List = %+v, %v", records, err)
+	}
+	// 所有字段原样保存，最近发送的在前
+	for i, want := range []Record{legacy[1], legacy[0]} {
+		got, _ := json.Marshal(records[i])
+		exp, _ := json.Marshal(want)
+		if string(got) != string(exp) {
+			t.Errorf("record %d = %s, want %s", i, got, exp)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package units
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// Rounding 是截断到指定小数位时的舍入方式，都按绝对值计算（负数与正数对称）
+type Rounding int
+
+const (
+	RoundHalfUp Rounding = iota // 四舍五入（默认）
+	RoundDown                   // 向零截断，显示的余额不会多于实际值
+	RoundUp                     // 远离零进位，显示的费用不会少于实际值
+)
+
+// FormatOptions 控制 Format 的输出
+type FormatOptions struct {
+	Places    int      // 小数位数，负数表示精确显示全部有效小数位（同 FormatUnits）
+	Rounding  Rounding // Places 不足以精确表示时的舍入方式
+	TrimZeros bool     // 去掉小数部分末尾的零，如 "1.50" 显示为 "1.5"
+	Group     bool     // 整数部分每三位加逗号，如 "1,234,567.5"
+}
+
+// Format 把整数数量按 decimals 位小数格式化，例如 USDC（6 位）的 1234567500000 在
+// FormatOptions{Places: 2, Group: true} 下为 "1,234,567.50"。
+func Format(amount *big.Int, decimals int, opts FormatOptions) string {
+	var s string
+	if opts.Places < 0 {
+		s = FormatUnits(amount, decimals)
+	} else {
+		s = formatRounded(amount, decimals, opts.Places, opts.Rounding)
+		if opts.TrimZeros && strings.Contains(s, ".") {
+			s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		}
+	}
+	if opts.Group {
+		s = group(s)
+	}
+	return s
+}
+
+// formatRounded 按 decimals 换算后舍入到 places 位小数，保留末尾的零，舍入为零的负数不带负号
+func formatRounded(amount *big.Int, decimals, places int, rounding Rounding) string {
+	// 先放大到 places 位小数的整数，再按绝对值舍入
+	scaled := new(big.Int).Abs(amount)
+	if shift := places - decimals; shift >= 0 {
+		scaled.Mul(scaled, pow10(shift))
+	} else {
+		div := pow10(-shift)
+		rem := new(big.Int)
+		scaled.QuoRem(scaled, div, rem)
+		switch {
+		case rounding == RoundUp && rem.Sign() != 0,
+			rounding == RoundHalfUp && rem.Lsh(rem, 1).Cmp(div) >= 0:
+			scaled.Add(scaled, big.NewInt(1))
+		}
+	}
+	digits := scaled.String()
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+	sign := ""
+	if amount.Sign() < 0 && strings.Trim(digits, "0") != "" {
+		sign = "-"
+	}
+	if places == 0 {
+		return sign + digits
+	}
+	return sign + digits[:len(digits)-places] + "." + digits[len(digits)-places:]
+}
+
+// group 在十进制字符串的整数部分每三位插入逗号
+func group(s string) string {
+	sign, rest := "", s
+	if strings.HasPrefix(rest, "-") {
+		sign, rest = "-", rest[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(rest, ".")
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if hasFrac {
+		return sign + b.String() + "." + frac
+	}
+	return sign + b.String()
+}
+
+// ToRat 把整数数量按 decimals 位小数换算为精确的有理数，便于与其他数量做比例或汇率计算
+func ToRat(amount *big.Int, decimals int) *big.Rat {
+	r := new(big.Rat).SetInt(amount)
+	if decimals >= 0 {
+		return r.Quo(r, new(big.Rat).SetInt(pow10(decimals)))
+	}
+	return r.Mul(r, new(big.Rat).SetInt(pow10(-decimals)))
+}
+
+// FromRat 把有理数换算为 decimals 位小数的整数数量，无法精确表示（如 1/3 或超出精度的小数）时报错。
+// FromRat(ToRat(x, 6), 18) 把 6 位小数的数量转换为 18 位小数。
+func FromRat(r *big.Rat, decimals int) (*big.Int, error) {
+	scaled := new(big.Rat).Set(r)
+	if decimals >= 0 {
+		scaled.Mul(scaled, new(big.Rat).SetInt(pow10(decimals)))
+	} else {
+		scaled.Quo(scaled, new(big.Rat).SetInt(pow10(-decimals)))
+	}
+	if !scaled.IsInt() {
+		return nil, errors.New("not a whole number of the base unit")
+	}
+	return new(big.Int).Set(scaled.Num()), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,47 @@
+package units
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		opts     FormatOptions
+		want     string
+	}{
+		{"1234567500000", 6, FormatOptions{Places: 2, Group: true}, "1,234,567.50"},
+		{"1234567500000", 6, FormatOptions{Places: 2, Group: true, TrimZeros: true}, "1,234,567.5"},
+		{"123456789", 8, FormatOptions{Places: -1}, "1.23456789"},
+		{"123456789", 8, FormatOptions{Places: 4, Rounding: RoundDown}, "1.2345"},
+		{"123456789", 8, FormatOptions{Places: 4, Rounding: RoundUp}, "1.2346"},
+		{"123450000", 8, FormatOptions{Places: 4, Rounding: RoundUp}, "1.2345"},
+		{"-1999", 3, FormatOptions{Places: 0, Rounding: RoundDown}, "-1"},
+		{"-1000000", 0, FormatOptions{Places: -1, Group: true}, "-1,000,000"},
+		{"100", 0, FormatOptions{Places: 2, TrimZeros: true}, "100"},
+		{"999", 18, FormatOptions{Places: 6, Rounding: RoundUp}, "0.000001"},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		if got := Format(amount, tt.decimals, tt.opts); got != tt.want {
+			t.Errorf("Format(%s, %d, %+v) = %q, want %q", tt.amount, tt.decimals, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestRat(t *testing.T) {
+	// 1.5 USDC（6 位）换算为 18 位小数的数量
+	got, err := FromRat(ToRat(big.NewInt(1500000), 6), 18)
+	if err != nil || got.String() != "1500000000000000000" {
+		t.Errorf("FromRat(ToRat(1500000, 6), 18) = %v, %v", got, err)
+	}
+	// 反过来超出 6 位的精度无法表示
+	if got, err := FromRat(ToRat(big.NewInt(1), 18), 6); err == nil {
+		t.Errorf("FromRat(1e-18, 6) = %s, want error", got)
+	}
+	if got, err := FromRat(big.NewRat(1, 3), 18); err == nil {
+		t.Errorf("FromRat(1/3, 18) = %s, want error", got)
+	}
+}
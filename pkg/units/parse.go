@@ -0,0 +1,147 @@
+// Package units 在 wei 与带单位的十进制金额之间进行精确转换（不经过 float64）。
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// unitExponents 是各单位相对于 wei 的十进制指数
+var unitExponents = map[string]int{
+	"wei":    0,
+	"kwei":   3,
+	"mwei":   6,
+	"gwei":   9,
+	"szabo":  12,
+	"finney": 15,
+	"eth":    18,
+	"ether":  18,
+}
+
+// amountPattern 匹配 "1", "1.5", "1e15", "2.5E-3" 形式的十进制数
+var amountPattern = regexp.MustCompile(`^([0-9]+)(?:\.([0-9]+))?(?:[eE]([+-]?[0-9]{1,3}))?$`)
+
+// maxWei 是金额上限（uint256 最大值），同时防止 "1e999" 之类的输入分配巨大的整数
+var maxWei = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// ParseAmount 解析带单位的金额并返回 wei，例如 "1.5 eth"、"300 gwei"、"1e15 wei"。
+// 没有单位时默认为 wei。结果必须是非负整数 wei，无法精确表示（如 "0.5 wei"）时报错。
+func ParseAmount(s string) (*big.Int, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	unit := "wei"
+	switch len(fields) {
+	case 1:
+		// 允许单位与数字连写，例如 "1.5eth"
+		num := strings.TrimRightFunc(fields[0], func(r rune) bool { return r >= 'a' && r <= 'z' })
+		if suffix := fields[0][len(num):]; suffix != "" && suffix != "e" {
+			unit = suffix
+			fields[0] = num
+		}
+	case 2:
+		unit = fields[1]
+	default:
+		return nil, fmt.Errorf("invalid amount %q: want <number> [unit]", s)
+	}
+	exp, ok := unitExponents[unit]
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q: unknown unit %q", s, unit)
+	}
+
+	wei, err := parseDecimal(fields[0], exp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	return wei, nil
+}
+
+// ParseUnits 把十进制字符串按 decimals 位小数转换为整数数量，例如 ParseUnits("1.5", 6)
+// 返回 1500000（USDC）。允许前导负号和指数写法，无法精确表示时报错。
+func ParseUnits(s string, decimals int) (*big.Int, error) {
+	if decimals < 0 {
+		return nil, fmt.Errorf("invalid decimals %d", decimals)
+	}
+	num, negative := strings.CutPrefix(strings.TrimSpace(s), "-")
+	amount, err := parseDecimal(num, decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	if negative {
+		amount.Neg(amount)
+	}
+	return amount, nil
+}
+
+// parseDecimal 返回 num × 10^exp，结果必须是不超过 uint256 的非负整数
+func parseDecimal(num string, exp int) (*big.Int, error) {
+	m := amountPattern.FindStringSubmatch(num)
+	if m == nil {
+		return nil, errors.New("malformed number")
+	}
+	intPart, fracPart := m[1], m[2]
+	if m[3] != "" {
+		var e int
+		fmt.Sscan(m[3], &e)
+		exp += e
+	}
+	// 数字 = (intPart + fracPart) × 10^(exp - len(fracPart))
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	exp -= len(fracPart)
+	if digits == "" {
+		return new(big.Int), nil
+	}
+	for exp < 0 {
+		if !strings.HasSuffix(digits, "0") {
+			return nil, errors.New("not a whole number of the base unit")
+		}
+		digits = digits[:len(digits)-1]
+		exp++
+	}
+	if len(digits)+exp > len(maxWei.String()) {
+		return nil, errors.New("exceeds uint256")
+	}
+	amount, _ := new(big.Int).SetString(digits+strings.Repeat("0", exp), 10)
+	if amount.Cmp(maxWei) > 0 {
+		return nil, errors.New("exceeds uint256")
+	}
+	return amount, nil
+}
+
+// FormatUnits 把整数数量按 decimals 位小数格式化为精确的十进制字符串，去掉末尾多余的零，
+// 例如 FormatUnits(1500000, 6) 返回 "1.5"。ParseUnits(FormatUnits(x, d), d) 总是等于 x。
+func FormatUnits(amount *big.Int, decimals int) string {
+	digits := new(big.Int).Abs(amount).String()
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals <= 0 {
+		return sign + digits + strings.Repeat("0", -decimals)
+	}
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fracPart == "" {
+		return sign + intPart
+	}
+	return sign + intPart + "." + fracPart
+}
+
+// FormatFixed 把整数数量按 decimals 位小数换算后四舍五入到 places 位，保留末尾的零，
+// 例如 FormatFixed(159396525300000000, 18, 6) 返回 "0.159397"。适合固定宽度的展示。
+func FormatFixed(amount *big.Int, decimals, places int) string {
+	return Format(amount, decimals, FormatOptions{Places: max(places, 0)})
+}
+
+// FormatEther 把 wei 格式化为保留 places 位小数的 ETH
+func FormatEther(wei *big.Int, places int) string {
+	return FormatFixed(wei, 18, places)
+}
+
+// FormatGwei 把 wei 格式化为保留 places 位小数的 Gwei，常用于显示 Gas 价格
+func FormatGwei(wei *big.Int, places int) string {
+	return FormatFixed(wei, 9, places)
+}
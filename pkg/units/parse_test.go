@@ -0,0 +1,117 @@
+package units
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"21000", "21000"},
+		{"21000 wei", "21000"},
+		{"1e15 wei", "1000000000000000"},
+		{"0.001 eth", "1000000000000000"},
+		{"1.5 ETH", "1500000000000000000"},
+		{"1.5eth", "1500000000000000000"},
+		{"300 gwei", "300000000000"},
+		{"2.5e-3 ether", "2500000000000000"},
+		{"0.000000001 gwei", "1"},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if err != nil {
+			t.Errorf("ParseAmount(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseAmount(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmountErrors(t *testing.T) {
+	for _, in := range []string{"", "eth", "-1 eth", "1.5", "0.5 wei", "1 btc", "1 2 eth", "1e", "1e999 eth", ".5 eth", "1,5 eth", "0x10"} {
+		if got, err := ParseAmount(in); err == nil {
+			t.Errorf("ParseAmount(%q) = %s, want error", in, got)
+		}
+	}
+}
+
+func TestUnits(t *testing.T) {
+	tests := []struct {
+		amount    string
+		decimals  int
+		formatted string
+	}{
+		{"0", 18, "0"},
+		{"1500000", 6, "1.5"},
+		{"1", 18, "0.000000000000000001"},
+		{"123456789", 8, "1.23456789"},
+		{"-2500000000000000000", 18, "-2.5"},
+		{"42", 0, "42"},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		if got := FormatUnits(amount, tt.decimals); got != tt.formatted {
+			t.Errorf("FormatUnits(%s, %d) = %q, want %q", tt.amount, tt.decimals, got, tt.formatted)
+		}
+		got, err := ParseUnits(tt.formatted, tt.decimals)
+		if err != nil || got.Cmp(amount) != 0 {
+			t.Errorf("ParseUnits(%q, %d) = %v, %v, want %s", tt.formatted, tt.decimals, got, err, tt.amount)
+		}
+	}
+	for _, in := range []string{"", "-", "1.0000001", "abc", "1 usdc"} {
+		if got, err := ParseUnits(in, 6); err == nil {
+			t.Errorf("ParseUnits(%q, 6) = %s, want error", in, got)
+		}
+	}
+}
+
+func TestFormatFixed(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		places   int
+		want     string
+	}{
+		{"0", 18, 6, "0.000000"},
+		{"159396525300000000", 18, 6, "0.159397"},
+		{"999999999", 9, 2, "1.00"},
+		{"1500000000", 9, 2, "1.50"},
+		{"1499", 3, 0, "1"},
+		{"1500", 3, 0, "2"},
+		{"-1500", 3, 1, "-1.5"},
+		{"-1", 18, 6, "0.000000"},
+		{"42", 0, 2, "42.00"},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		if got := FormatFixed(amount, tt.decimals, tt.places); got != tt.want {
+			t.Errorf("FormatFixed(%s, %d, %d) = %q, want %q", tt.amount, tt.decimals, tt.places, got, tt.want)
+		}
+	}
+}
+
+func FuzzParseAmount(f *testing.F) {
+	for _, seed := range []string{"1.5 eth", "300 gwei", "1e15 wei", "0", "2.5E-3 ether", "1e999", "0.0000000000000000001 eth", "9e77"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		wei, err := ParseAmount(s)
+		if err != nil {
+			return
+		}
+		if wei.Sign() < 0 || wei.Cmp(maxWei) > 0 {
+			t.Fatalf("ParseAmount(%q) = %s, out of uint256 range", s, wei)
+		}
+		// 解析结果按 wei 再解析一次必须不变
+		again, err := ParseAmount(wei.String() + " wei")
+		if err != nil || again.Cmp(wei) != 0 {
+			t.Fatalf("ParseAmount(%q) = %s does not round trip: %v, %v", s, wei, again, err)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package units
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"pgregory.net/rapid"
+)
+
+// amountGen 生成 [0, 2^256) 范围内的数量，偏向小值和边界附近的值
+func amountGen() *rapid.Generator[*big.Int] {
+	return rapid.Custom(func(t *rapid.T) *big.Int {
+		b := rapid.SliceOfN(rapid.Byte(), 0, 32).Draw(t, "bytes")
+		return new(big.Int).SetBytes(b)
+	})
+}
+
+// signedAmountGen 生成绝对值不超过 uint256 的有符号数量
+func signedAmountGen() *rapid.Generator[*big.Int] {
+	return rapid.Custom(func(t *rapid.T) *big.Int {
+		x := amountGen().Draw(t, "abs")
+		if rapid.Bool().Draw(t, "negative") {
+			x.Neg(x)
+		}
+		return x
+	})
+}
+
+var decimalsGen = rapid.IntRange(0, 36)
+
+func TestPropertyRoundTrip(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		x := signedAmountGen().Draw(t, "x")
+		d := decimalsGen.Draw(t, "decimals")
+		s := FormatUnits(x, d)
+		got, err := ParseUnits(s, d)
+		if err != nil {
+			t.Fatalf("ParseUnits(%q, %d): %v", s, d, err)
+		}
+		if got.Cmp(x) != 0 {
+			t.Fatalf("ParseUnits(FormatUnits(%s, %d) = %q) = %s", x, d, s, got)
+		}
+	})
+}
+
+// 格式化结果按精确的有理数解释，乘以 10^decimals 后必须恰好等于原值
+func TestPropertyNoPrecisionLoss(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		x := signedAmountGen().Draw(t, "x")
+		d := decimalsGen.Draw(t, "decimals")
+		s := FormatUnits(x, d)
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			t.Fatalf("FormatUnits(%s, %d) = %q is not a decimal number", x, d, s)
+		}
+		r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d)), nil)))
+		if !r.IsInt() || r.Num().Cmp(x) != 0 {
+			t.Fatalf("FormatUnits(%s, %d) = %q loses precision", x, d, s)
+		}
+	})
+}
+
+// 格式化结果是规范形式：没有多余的前导零，小数部分没有末尾零
+func TestPropertyCanonical(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		x := signedAmountGen().Draw(t, "x")
+		d := decimalsGen.Draw(t, "decimals")
+		s := strings.TrimPrefix(FormatUnits(x, d), "-")
+		intPart, fracPart, hasFrac := strings.Cut(s, ".")
+		if len(intPart) > 1 && intPart[0] == '0' {
+			t.Fatalf("FormatUnits(%s, %d) = %q has leading zeros", x, d, s)
+		}
+		if hasFrac && (fracPart == "" || strings.HasSuffix(fracPart, "0")) {
+			t.Fatalf("FormatUnits(%s, %d) = %q has trailing zeros", x, d, s)
+		}
+	})
+}
+
+func TestPropertyMonotonic(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		x := signedAmountGen().Draw(t, "x")
+		y := signedAmountGen().Draw(t, "y")
+		d := decimalsGen.Draw(t, "decimals")
+		rx, _ := new(big.Rat).SetString(FormatUnits(x, d))
+		ry, _ := new(big.Rat).SetString(FormatUnits(y, d))
+		if got, want := rx.Cmp(ry), x.Cmp(y); got != want {
+			t.Fatalf("order not preserved: %s vs %s at %d decimals compares %d, want %d", x, y, d, got, want)
+		}
+	})
+}
+
+// 以 wei/gwei/eth 为单位格式化后，ParseAmount 必须还原出相同的 wei
+func TestPropertyEtherUnits(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		wei := amountGen().Draw(t, "wei")
+		unit := rapid.SampledFrom([]string{"wei", "kwei", "mwei", "gwei", "szabo", "finney", "eth", "ether"}).Draw(t, "unit")
+		s := FormatUnits(wei, unitExponents[unit]) + " " + unit
+		got, err := ParseAmount(s)
+		if err != nil {
+			t.Fatalf("ParseAmount(%q): %v", s, err)
+		}
+		if got.Cmp(wei) != 0 {
+			t.Fatalf("ParseAmount(%q) = %s, want %s", s, got, wei)
+		}
+	})
+}
+
+// 舍入到任意位数时，向零截断和远离零进位的结果夹住精确值（按绝对值），且 FromRat 能还原 ToRat
+func TestPropertyRoundingBounds(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		x := signedAmountGen().Draw(t, "x")
+		d := decimalsGen.Draw(t, "decimals")
+		places := rapid.IntRange(0, 40).Draw(t, "places")
+		exact := ToRat(new(big.Int).Abs(x), d)
+		down, _ := new(big.Rat).SetString(strings.TrimPrefix(Format(x, d, FormatOptions{Places: places, Rounding: RoundDown}), "-"))
+		up, _ := new(big.Rat).SetString(strings.TrimPrefix(Format(x, d, FormatOptions{Places: places, Rounding: RoundUp}), "-"))
+		if down.Cmp(exact) > 0 || up.Cmp(exact) < 0 {
+			t.Fatalf("%s at %d decimals, %d places: down %s, up %s do not bound %s", x, d, places, down.FloatString(places), up.FloatString(places), exact.FloatString(d))
+		}
+		if back, err := FromRat(ToRat(x, d), d); err != nil || back.Cmp(x) != 0 {
+			t.Fatalf("FromRat(ToRat(%s, %d)) = %v, %v", x, d, back, err)
+		}
+	})
+}
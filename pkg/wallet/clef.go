@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Clef 通过 Clef 的外部 API（account_signTransaction）签名交易，私钥不进入本进程。
+// 每次签名都需要在 Clef 中确认，或由 Clef 的规则文件自动批准。
+type Clef struct {
+	signer  *external.ExternalSigner
+	account accounts.Account
+}
+
+// DialClef 连接 Clef（如 http://localhost:8550 或 IPC 路径）并选择签名账户。
+// account 为零地址时使用 Clef 列出的第一个账户。
+func DialClef(url string, account common.Address) (*Clef, error) {
+	signer, err := external.NewExternalSigner(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clef at %s: %w", url, err)
+	}
+	accts := signer.Accounts()
+	if len(accts) == 0 {
+		return nil, fmt.Errorf("clef at %s returned no accounts (was the listing approved?)", url)
+	}
+	if account == (common.Address{}) {
+		return &Clef{signer: signer, account: accts[0]}, nil
+	}
+	for _, a := range accts {
+		if a.Address == account {
+			return &Clef{signer: signer, account: a}, nil
+		}
+	}
+	return nil, fmt.Errorf("clef at %s does not manage account %s", url, account.Hex())
+}
+
+// Address 返回签名账户地址
+func (c *Clef) Address() common.Address {
+	return c.account.Address
+}
+
+// SignTx 请求 Clef 签名交易，并检查返回的交易确实由签名账户签名
+func (c *Clef) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := c.signer.SignTx(c.account, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("clef failed to sign transaction: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, fmt.Errorf("clef returned an invalid signature: %w", err)
+	}
+	if from != c.account.Address {
+		return nil, fmt.Errorf("clef signed with %s instead of %s", from.Hex(), c.account.Address.Hex())
+	}
+	return signed, nil
+}
@@ -0,0 +1,117 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// fakeClef 实现 Clef 外部 API 中用到的 account_* 方法，用 key 自动批准所有签名
+type fakeClef struct {
+	key *ecdsa.PrivateKey
+}
+
+func (f *fakeClef) Version() string { return "7.0.1" }
+
+func (f *fakeClef) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(f.key.PublicKey)}
+}
+
+func (f *fakeClef) SignTransaction(args apitypes.SendTxArgs) (map[string]interface{}, error) {
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), f.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := signed.MarshalBinary()
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+}
+
+// SignData 只支持 text/plain，返回 v 为 27/28 的签名（与 Clef 相同）
+func (f *fakeClef) SignData(contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if contentType != accounts.MimetypeTextPlain {
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+	sig, err := crypto.Sign(accounts.TextHash(data), f.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+func newFakeClef(t *testing.T) (*fakeClef, string) {
+	t.Helper()
+	key, _ := crypto.GenerateKey()
+	fake := &fakeClef{key: key}
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", fake); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server)
+	t.Cleanup(func() {
+		ts.Close()
+		server.Stop()
+	})
+	return fake, ts.URL
+}
+
+func TestClefSignTx(t *testing.T) {
+	fake, url := newFakeClef(t)
+	want := crypto.PubkeyToAddress(fake.key.PublicKey)
+
+	c, err := DialClef(url, common.Address{})
+	if err != nil {
+		t.Fatalf("DialClef: %v", err)
+	}
+	if c.Address() != want {
+		t.Errorf("address = %s, want %s", c.Address(), want)
+	}
+
+	chainID := big.NewInt(11155111)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 3, To: &to, Value: big.NewInt(1), Gas: 21000, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(2e9)})
+	signed, err := NewTransactor(c, chainID).Signer(want, tx)
+	if err != nil {
+		t.Fatalf("sign via transactor: %v", err)
+	}
+	if signed.Nonce() != 3 || signed.Type() != types.DynamicFeeTxType {
+		t.Errorf("signed tx = type %d nonce %d", signed.Type(), signed.Nonce())
+	}
+
+	if _, err := DialClef(url, common.HexToAddress("0x01")); err == nil {
+		t.Error("DialClef with an unmanaged account succeeded, want error")
+	}
+}
+
+func TestClefSignMessage(t *testing.T) {
+	fake, url := newFakeClef(t)
+	c, err := DialClef(url, common.Address{})
+	if err != nil {
+		t.Fatalf("DialClef: %v", err)
+	}
+	msg := []byte("hello")
+	sig, err := c.SignMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		t.Errorf("v = %d, want 27 or 28", sig[64])
+	}
+	if err := VerifyMessage(crypto.PubkeyToAddress(fake.key.PublicKey), msg, sig); err != nil {
+		t.Error(err)
+	}
+}
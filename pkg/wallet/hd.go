@@ -0,0 +1,121 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultHDBase 是以太坊账户的 BIP-44 基础路径，第 n 个账户为 m/44'/60'/0'/0/n
+const DefaultHDBase = "m/44'/60'/0'/0"
+
+// HDWallet 是从 BIP-39 助记词恢复的分层确定性（BIP-32）钱包
+type HDWallet struct {
+	key       *big.Int // 主私钥
+	chainCode []byte
+}
+
+// NewHDWallet 用 BIP-39 英文助记词和可选的口令（第 25 个词）恢复钱包。每个单词都必须在英文词表中，
+// 校验和也必须正确：拼错或顺序错误的助记词返回错误，而不是派生出另一组空账户。
+// 错误信息只给出单词的位置，不包含单词本身
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("invalid mnemonic: %d words, want 12, 15, 18, 21 or 24", len(words))
+	}
+	for i, word := range words {
+		if _, ok := bip39.GetWordIndex(word); !ok {
+			return nil, fmt.Errorf("invalid mnemonic: word %d is not in the BIP-39 English wordlist", i+1)
+		}
+	}
+	normalized := strings.Join(words, " ")
+	_, err := bip39.EntropyFromMnemonic(normalized)
+	if errors.Is(err, bip39.ErrChecksumIncorrect) {
+		return nil, errors.New("invalid mnemonic: checksum mismatch, check the spelling and order of the words")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+	return NewHDWalletFromSeed(bip39.NewSeed(normalized, passphrase))
+}
+
+// NewHDWalletFromSeed 从 BIP-32 种子创建钱包
+func NewHDWalletFromSeed(seed []byte) (*HDWallet, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("invalid seed length %d", len(seed))
+	}
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(secp256k1N) >= 0 {
+		return nil, errors.New("invalid seed: master key out of range")
+	}
+	return &HDWallet{key: key, chainCode: sum[32:]}, nil
+}
+
+var secp256k1N = crypto.S256().Params().N
+
+// Derive 按 BIP-32 路径派生账户
+func (h *HDWallet) Derive(path accounts.DerivationPath) (*Wallet, error) {
+	key, chainCode := h.key, h.chainCode
+	for _, index := range path {
+		var err error
+		if key, chainCode, err = deriveChild(key, chainCode, index); err != nil {
+			return nil, fmt.Errorf("derive %s: %w", path, err)
+		}
+	}
+	priv, err := crypto.ToECDSA(key.FillBytes(make([]byte, 32)))
+	if err != nil {
+		return nil, err
+	}
+	return New(priv), nil
+}
+
+// DeriveIndex 派生 base/index 路径上的账户，例如 DeriveIndex(DefaultHDBase, 1) 对应 m/44'/60'/0'/0/1
+func (h *HDWallet) DeriveIndex(base string, index uint32) (*Wallet, error) {
+	path, err := accounts.ParseDerivationPath(fmt.Sprintf("%s/%d", strings.TrimSuffix(base, "/"), index))
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %q: %w", base, err)
+	}
+	return h.Derive(path)
+}
+
+// deriveChild 计算 BIP-32 子私钥：硬化索引使用父私钥，普通索引使用压缩的父公钥
+func deriveChild(key *big.Int, chainCode []byte, index uint32) (*big.Int, []byte, error) {
+	var data []byte
+	if index >= 0x80000000 {
+		data = append([]byte{0}, key.FillBytes(make([]byte, 32))...)
+	} else {
+		priv, err := crypto.ToECDSA(key.FillBytes(make([]byte, 32)))
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&priv.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(secp256k1N) >= 0 {
+		return nil, nil, fmt.Errorf("index %d yields an invalid key", index)
+	}
+	child := il.Add(il, key)
+	child.Mod(child, secp256k1N)
+	if child.Sign() == 0 {
+		return nil, nil, fmt.Errorf("index %d yields an invalid key", index)
+	}
+	return child, sum[32:], nil
+}
@@ -0,0 +1,98 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Anvil/Hardhat 的默认助记词及其前几个账户
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestHDWalletAnvilAccounts(t *testing.T) {
+	h, err := NewHDWallet(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+	} {
+		w, err := h.DeriveIndex(DefaultHDBase, uint32(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Address() != common.HexToAddress(want) {
+			t.Errorf("account %d = %s, want %s", i, w.Address(), want)
+		}
+	}
+	w, _ := h.DeriveIndex(DefaultHDBase, 0)
+	if got := hex.EncodeToString(crypto.FromECDSA(w.PrivateKey())); got != testKey {
+		t.Errorf("account 0 key = %s, want %s", got, testKey)
+	}
+}
+
+// BIP-32 测试向量 1
+func TestHDWalletBIP32Vector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	h, err := NewHDWalletFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+	}
+	for _, tt := range tests {
+		path, err := accounts.ParseDerivationPath(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := h.Derive(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(crypto.FromECDSA(w.PrivateKey())); got != tt.key {
+			t.Errorf("%s key = %s, want %s", tt.path, got, tt.key)
+		}
+	}
+}
+
+func TestNewHDWalletErrors(t *testing.T) {
+	tests := []struct {
+		mnemonic, want string
+	}{
+		{"", "0 words"},
+		{"test test test", "3 words"},
+		{testMnemonic + " extra", "13 words"},
+		{"test test test test test test test test test test test jünk", "word 12 is not in the BIP-39 English wordlist"},
+		// 拼错的单词：junk 写成 junky
+		{"test test test test test test test test test test test junky", "word 12 is not in the BIP-39 English wordlist"},
+		// 每个单词都在词表中，但校验和不对
+		{"test test test test test test test test test test test test", "checksum mismatch"},
+		{"junk test test test test test test test test test test test", "checksum mismatch"},
+	}
+	for _, tt := range tests {
+		_, err := NewHDWallet(tt.mnemonic, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NewHDWallet(%q) = %v, want error containing %q", tt.mnemonic, err, tt.want)
+		}
+	}
+	// BIP-39 口令会得到另一组账户
+	a, _ := NewHDWallet(testMnemonic, "")
+	b, _ := NewHDWallet(testMnemonic, "secret")
+	wa, _ := a.DeriveIndex(DefaultHDBase, 0)
+	wb, _ := b.DeriveIndex(DefaultHDBase, 0)
+	if wa.Address() == wb.Address() {
+		t.Error("passphrase did not change the derived account")
+	}
+}
@@ -0,0 +1,40 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// scrypt 参数，测试中改为轻量参数以加快速度
+var (
+	scryptN = keystore.StandardScryptN
+	scryptP = keystore.StandardScryptP
+)
+
+// FromKeystore 用口令解密 go-ethereum keystore 文件（UTC--... JSON）
+func FromKeystore(path, passphrase string) (*Wallet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	return New(key.PrivateKey), nil
+}
+
+// SaveKeystore 用口令加密私钥，以标准的 UTC--<时间>--<地址> 文件名写入 dir，返回文件路径
+func (w *Wallet) SaveKeystore(dir, passphrase string) (string, error) {
+	ks := keystore.NewKeyStore(dir, scryptN, scryptP)
+	if ks.HasAddress(w.address) {
+		return "", fmt.Errorf("account %s already exists in %s", w.address.Hex(), dir)
+	}
+	account, err := ks.ImportECDSA(w.key, passphrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to write keystore: %w", err)
+	}
+	return account.URL.Path, nil
+}
@@ -0,0 +1,36 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	t.Cleanup(func() { scryptN, scryptP = keystore.StandardScryptN, keystore.StandardScryptP })
+
+	w, err := FromHex(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	path, err := w.SaveKeystore(dir, "correct horse")
+	if err != nil {
+		t.Fatalf("SaveKeystore: %v", err)
+	}
+
+	loaded, err := FromKeystore(path, "correct horse")
+	if err != nil {
+		t.Fatalf("FromKeystore: %v", err)
+	}
+	if loaded.Address() != testAddress {
+		t.Errorf("loaded address = %s, want %s", loaded.Address(), testAddress)
+	}
+	if _, err := FromKeystore(path, "wrong"); err == nil {
+		t.Error("FromKeystore with the wrong passphrase succeeded, want error")
+	}
+	if _, err := w.SaveKeystore(dir, "again"); err == nil {
+		t.Error("SaveKeystore of an existing account succeeded, want error")
+	}
+}
@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrSignatureMismatch 表示签名有效但不是由期望的地址签名
+var ErrSignatureMismatch = errors.New("signature does not match address")
+
+// MessageSigner 是还能按 personal_sign（EIP-191 版本 0x45）签名消息的账户
+type MessageSigner interface {
+	Signer
+	SignMessage(msg []byte) ([]byte, error)
+}
+
+var (
+	_ MessageSigner = (*Wallet)(nil)
+	_ MessageSigner = (*Clef)(nil)
+)
+
+// HashMessage 返回 personal_sign 签名的哈希：keccak256("\x19Ethereum Signed Message:\n" + len(msg) + msg)
+func HashMessage(msg []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(msg))
+}
+
+// SignMessage 按 personal_sign 签名 msg，返回 65 字节的 r || s || v，v 为 27 或 28（与 eth_sign 和钱包一致）
+func (w *Wallet) SignMessage(msg []byte) ([]byte, error) {
+	sig, err := crypto.Sign(HashMessage(msg).Bytes(), w.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// SignMessage 请求 Clef 以 text/plain 签名 msg，返回格式与 Wallet.SignMessage 相同，并检查签名确实来自签名账户
+func (c *Clef) SignMessage(msg []byte) ([]byte, error) {
+	sig, err := c.signer.SignText(c.account, msg)
+	if err != nil {
+		return nil, fmt.Errorf("clef failed to sign message: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("clef returned a %d-byte signature", len(sig))
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	if err := VerifyMessage(c.account.Address, msg, sig); err != nil {
+		return nil, fmt.Errorf("clef returned an invalid signature: %w", err)
+	}
+	return sig, nil
+}
+
+// RecoverMessage 从 personal_sign 签名中恢复签名地址。v 可以是 27/28，也可以是 0/1
+func RecoverMessage(msg, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(sig))
+	}
+	sig = common.CopyBytes(sig)
+	if v := sig[crypto.RecoveryIDOffset]; v == 27 || v == 28 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(HashMessage(msg).Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// VerifyMessage 检查 sig 是 address 对 msg 的 personal_sign 签名，签名者不同时返回 ErrSignatureMismatch
+func VerifyMessage(address common.Address, msg, sig []byte) error {
+	signer, err := RecoverMessage(msg, sig)
+	if err != nil {
+		return err
+	}
+	if signer != address {
+		return fmt.Errorf("%w: signed by %s, not %s", ErrSignatureMismatch, signer.Hex(), address.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// web3.js 文档中 eth.accounts.sign 的示例
+func TestSignMessage(t *testing.T) {
+	w, err := FromHex("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("Some data")
+	if got := HashMessage(msg).Hex(); got != "0x1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655" {
+		t.Errorf("HashMessage = %s", got)
+	}
+	sig, err := w.SignMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hexutil.Encode(sig); got != "0xb91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c" {
+		t.Errorf("signature = %s", got)
+	}
+	if err := VerifyMessage(w.Address(), msg, sig); err != nil {
+		t.Errorf("VerifyMessage: %v", err)
+	}
+
+	// v 为 0/1 的签名同样可以恢复
+	raw := common.CopyBytes(sig)
+	raw[64] -= 27
+	if signer, err := RecoverMessage(msg, raw); err != nil || signer != w.Address() {
+		t.Errorf("RecoverMessage with v=%d = %s, %v", raw[64], signer.Hex(), err)
+	}
+	if err := VerifyMessage(w.Address(), []byte("Other data"), sig); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("VerifyMessage of another message = %v, want ErrSignatureMismatch", err)
+	}
+	if _, err := RecoverMessage(msg, sig[:64]); err == nil {
+		t.Error("RecoverMessage accepted a 64-byte signature")
+	}
+}
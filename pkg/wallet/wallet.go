@@ -0,0 +1,70 @@
+// Package wallet 管理签名用的私钥，提供地址、交易签名和 abigen 绑定所需的 TransactOpts。
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNoKey 表示没有提供私钥
+var ErrNoKey = errors.New("no private key provided")
+
+// Wallet 是一个持有私钥的签名账户
+type Wallet struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// New 用已有的私钥创建 Wallet
+func New(key *ecdsa.PrivateKey) *Wallet {
+	return &Wallet{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// FromHex 解析十六进制私钥（可带 0x 前缀）
+func FromHex(keyHex string) (*Wallet, error) {
+	keyHex = strings.TrimPrefix(strings.TrimSpace(keyHex), "0x")
+	if keyHex == "" {
+		return nil, ErrNoKey
+	}
+	key, err := crypto.HexToECDSA(keyHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return New(key), nil
+}
+
+// Address 返回账户地址
+func (w *Wallet) Address() common.Address {
+	return w.address
+}
+
+// PrivateKey 返回底层私钥
+func (w *Wallet) PrivateKey() *ecdsa.PrivateKey {
+	return w.key
+}
+
+// SignTx 用与交易类型匹配的最新签名规则签名交易
+func (w *Wallet) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), w.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signed, nil
+}
+
+// Transactor 返回供 abigen 绑定使用的交易签名器
+func (w *Wallet) Transactor(chainID *big.Int) (*bind.TransactOpts, error) {
+	auth, err := bind.NewKeyedTransactorWithChainID(w.key, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authorized transactor: %w", err)
+	}
+	return auth, nil
+}
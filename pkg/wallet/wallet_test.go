@@ -0,0 +1,55 @@
+package wallet
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 第一个 Anvil/Hardhat 测试账户
+const testKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+var testAddress = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+func TestFromHex(t *testing.T) {
+	for _, in := range []string{testKey, "0x" + testKey, " 0x" + testKey + "\n"} {
+		w, err := FromHex(in)
+		if err != nil {
+			t.Fatalf("FromHex(%q): %v", in, err)
+		}
+		if w.Address() != testAddress {
+			t.Errorf("FromHex(%q) address = %s, want %s", in, w.Address(), testAddress)
+		}
+	}
+	if _, err := FromHex(""); !errors.Is(err, ErrNoKey) {
+		t.Errorf("FromHex(\"\") error = %v, want ErrNoKey", err)
+	}
+	if _, err := FromHex("zz"); err == nil {
+		t.Error("FromHex(\"zz\") succeeded, want error")
+	}
+}
+
+func TestSignTx(t *testing.T) {
+	w, err := FromHex(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(11155111)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	for _, tx := range []*types.Transaction{
+		types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1e9)}),
+		types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(2e9)}),
+	} {
+		signed, err := w.SignTx(tx, chainID)
+		if err != nil {
+			t.Fatalf("SignTx type %d: %v", tx.Type(), err)
+		}
+		from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		if err != nil || from != testAddress {
+			t.Errorf("type %d sender = %s, %v, want %s", tx.Type(), from, err, testAddress)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package watch 通过 WebSocket 订阅链上的新区块头、事件日志和交易池中的待处理交易。连接断开、订阅出错或长时间
+// 收不到数据时自动重连并重新订阅，重连间隔按指数退避增长。区块头和日志的订阅保留最近一段区块，
+// 发现链重组时报告并重新发出受影响的区块或撤回受影响的日志。
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Options 是重连参数，零值使用默认值
+type Options struct {
+	MinBackoff   time.Duration // 第一次重连前的等待时间，默认 1 秒
+	MaxBackoff   time.Duration // 重连等待时间的上限，默认 30 秒
+	StallTimeout time.Duration // 超过该时间没有收到任何数据时视为连接失效并重连，0 表示不检查
+	ReorgWindow  int           // 用于检测重组的最近区块数，默认 DefaultReorgWindow
+
+	// OnReconnect 在每次重连前调用，attempt 从 1 开始，连续失败时递增
+	OnReconnect func(attempt int, err error)
+	// OnReorg 在 Heads 发现重组时调用（可选），之后新链上的区块会依次交给处理函数
+	OnReorg func(Reorg)
+}
+
+func (o Options) withDefaults() Options {
+	if o.MinBackoff == 0 {
+		o.MinBackoff = time.Second
+	}
+	if o.MaxBackoff == 0 {
+		o.MaxBackoff = 30 * time.Second
+	}
+	if o.ReorgWindow <= 0 {
+		o.ReorgWindow = DefaultReorgWindow
+	}
+	return o
+}
+
+// errStalled 表示在 StallTimeout 内没有收到数据
+var errStalled = errors.New("no data received before the stall timeout")
+
+// handlerError 包装处理函数返回的错误，这类错误直接结束订阅而不重连
+type handlerError struct{ err error }
+
+func (e *handlerError) Error() string { return e.err.Error() }
+func (e *handlerError) Unwrap() error { return e.err }
+
+// Heads 连接 url（ws:// 或 wss://）订阅新区块头，对每个区块头调用 handle，直到 ctx 结束或
+// handle 返回错误。handle 收到的 client 是当前的连接，可用于查询区块的其他信息。
+// 区块按链上顺序交给 handle：断线期间错过的区块（不超过 ReorgWindow 个）会补上；发生重组时先调用
+// OnReorg，再把新链上共同祖先之后的区块依次交给 handle，被替换区块上的处理结果应由调用方撤销。
+func Heads(ctx context.Context, url string, opts Options, handle func(client *ethclient.Client, head *types.Header) error) error {
+	tracker := NewTracker(opts.withDefaults().ReorgWindow)
+	return run(ctx, url, opts, func(ctx context.Context, client *ethclient.Client, received func()) error {
+		heads := make(chan *types.Header)
+		sub, err := client.SubscribeNewHead(ctx, heads)
+		if err != nil {
+			return fmt.Errorf("subscribe to new heads: %w", err)
+		}
+		defer sub.Unsubscribe()
+		stall := newStallTimer(opts.StallTimeout)
+		defer stall.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-sub.Err():
+				return fmt.Errorf("new heads subscription: %w", err)
+			case <-stall.C():
+				return errStalled
+			case head := <-heads:
+				received()
+				stall.Reset()
+				added, reorg, err := tracker.Add(ctx, head, client.HeaderByHash)
+				if err != nil {
+					return fmt.Errorf("check for reorg: %w", err)
+				}
+				if reorg != nil && opts.OnReorg != nil {
+					opts.OnReorg(*reorg)
+				}
+				for _, h := range added {
+					if err := handle(client, h); err != nil {
+						return &handlerError{err}
+					}
+				}
+			}
+		}
+	})
+}
+
+// run 反复连接 url 并执行 session，直到 ctx 结束或 session 返回 handlerError。
+// session 收到数据时调用 received，使下一次重连的退避时间重新从 MinBackoff 开始。
+func run(ctx context.Context, url string, opts Options, session func(ctx context.Context, client *ethclient.Client, received func()) error) error {
+	opts = opts.withDefaults()
+	backoff, attempt := opts.MinBackoff, 0
+	for {
+		err := func() error {
+			client, err := ethclient.DialContext(ctx, url)
+			if err != nil {
+				return fmt.Errorf("connect: %w", err)
+			}
+			defer client.Close()
+			return session(ctx, client, func() { backoff, attempt = opts.MinBackoff, 0 })
+		}()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var herr *handlerError
+		if errors.As(err, &herr) {
+			return herr.err
+		}
+		attempt++
+		if opts.OnReconnect != nil {
+			opts.OnReconnect(attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, opts.MaxBackoff)
+	}
+}
+
+// stallTimer 在 timeout 为 0 时永不触发
+type stallTimer struct {
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func newStallTimer(timeout time.Duration) *stallTimer {
+	if timeout == 0 {
+		return &stallTimer{}
+	}
+	return &stallTimer{timer: time.NewTimer(timeout), timeout: timeout}
+}
+
+func (s *stallTimer) C() <-chan time.Time {
+	if s.timer == nil {
+		return nil
+	}
+	return s.timer.C
+}
+
+func (s *stallTimer) Reset() {
+	if s.timer != nil {
+		s.timer.Reset(s.timeout)
+	}
+}
+
+func (s *stallTimer) Stop() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
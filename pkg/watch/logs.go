@@ -0,0 +1,165 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// backfillChunk 是补齐日志时单次 eth_getLogs 查询的区块数，多数提供商限制在几千个区块以内
+const backfillChunk = 2000
+
+// logKey 唯一标识一条日志
+type logKey struct {
+	block common.Hash
+	index uint
+}
+
+// Logs 订阅匹配 q 中地址和主题的日志，对每条日志调用 handle，直到 ctx 结束或 handle 返回错误。
+// 每次（重新）订阅后先用 FilterLogs 补齐上次处理到的区块至当前区块之间的日志，因此断线期间的事件
+// 不会丢失；q.FromBlock 非空时第一次订阅也从该区块开始补齐历史日志，否则只处理新日志。
+// 补齐范围与订阅推送可能重叠，重复的日志会被过滤；因重组被移除的日志以 Removed 为 true 传给 handle。
+// 重连后的补齐会重新检查最近 ReorgWindow 个区块：断线期间被重组替换的区块上已处理的日志同样以
+// Removed 撤回，新链上的日志作为新日志交给 handle。
+func Logs(ctx context.Context, url string, q ethereum.FilterQuery, opts Options, handle func(log types.Log) error) error {
+	window := uint64(opts.withDefaults().ReorgWindow)
+	var next *big.Int // 下一个需要补齐的区块，其中可能有已处理的日志
+	if q.FromBlock != nil {
+		next = new(big.Int).Set(q.FromBlock)
+	}
+	floor := next // 第一次订阅时开始处理的区块，重新检查时不早于该区块
+	seen := make(map[logKey]types.Log)
+
+	deliver := func(log types.Log) error {
+		key := logKey{log.BlockHash, log.Index}
+		if _, ok := seen[key]; ok == !log.Removed {
+			// 已处理的日志重复出现，或撤回从未处理（或已撤回）的日志
+			return nil
+		}
+		if log.Removed {
+			delete(seen, key)
+		} else {
+			seen[key] = log
+		}
+		if n := new(big.Int).SetUint64(log.BlockNumber); next == nil || n.Cmp(next) > 0 {
+			next = n
+		}
+		// 只保留可能再次出现在补齐范围内的日志
+		for k, l := range seen {
+			if l.BlockNumber+window < next.Uint64() {
+				delete(seen, k)
+			}
+		}
+		if err := handle(log); err != nil {
+			return &handlerError{err}
+		}
+		return nil
+	}
+
+	// reconcile 撤回 [from, to] 中已处理但不在补齐结果 logs 中的日志，这些日志所在的区块已被重组替换
+	reconcile := func(from, to uint64, logs []types.Log) error {
+		current := make(map[logKey]bool, len(logs))
+		for _, l := range logs {
+			current[logKey{l.BlockHash, l.Index}] = true
+		}
+		var stale []types.Log
+		for k, l := range seen {
+			if l.BlockNumber >= from && l.BlockNumber <= to && !current[k] {
+				stale = append(stale, l)
+			}
+		}
+		sort.Slice(stale, func(i, j int) bool {
+			if stale[i].BlockNumber != stale[j].BlockNumber {
+				return stale[i].BlockNumber > stale[j].BlockNumber
+			}
+			return stale[i].Index > stale[j].Index
+		})
+		for _, l := range stale {
+			l.Removed = true
+			if err := deliver(l); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return run(ctx, url, opts, func(ctx context.Context, client *ethclient.Client, received func()) error {
+		// 先订阅再补齐，补齐期间产生的日志会留在订阅通道中
+		logs := make(chan types.Log, 128)
+		live := q
+		live.FromBlock, live.ToBlock = nil, nil
+		sub, err := client.SubscribeFilterLogs(ctx, live, logs)
+		if err != nil {
+			return fmt.Errorf("subscribe to logs: %w", err)
+		}
+		defer sub.Unsubscribe()
+
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get block number: %w", err)
+		}
+		if next != nil {
+			from := next.Uint64() - min(next.Uint64(), window)
+			if floor != nil {
+				from = max(from, floor.Uint64())
+			}
+			if err := backfill(ctx, client, q, from, head, reconcile, deliver); err != nil {
+				return err
+			}
+			received()
+		}
+		if next == nil || next.Uint64() <= head {
+			next = new(big.Int).SetUint64(head + 1)
+		}
+		if floor == nil {
+			floor = new(big.Int).Set(next)
+		}
+
+		stall := newStallTimer(opts.StallTimeout)
+		defer stall.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-sub.Err():
+				return fmt.Errorf("logs subscription: %w", err)
+			case <-stall.C():
+				return errStalled
+			case log := <-logs:
+				received()
+				stall.Reset()
+				if err := deliver(log); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
+
+// backfill 分段查询 [from, to] 区块中的日志，每段先交给 reconcile 撤回已失效的日志，再把日志依次交给 deliver
+func backfill(ctx context.Context, client *ethclient.Client, q ethereum.FilterQuery, from, to uint64,
+	reconcile func(from, to uint64, logs []types.Log) error, deliver func(types.Log) error) error {
+	for start := from; start <= to; start += backfillChunk {
+		end := min(start+backfillChunk-1, to)
+		q.FromBlock, q.ToBlock = new(big.Int).SetUint64(start), new(big.Int).SetUint64(end)
+		logs, err := client.FilterLogs(ctx, q)
+		if err != nil {
+			return fmt.Errorf("backfill logs in blocks %d-%d: %w", start, end, err)
+		}
+		if err := reconcile(start, end, logs); err != nil {
+			return err
+		}
+		for _, log := range logs {
+			if err := deliver(log); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
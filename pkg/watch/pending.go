@@ -0,0 +1,145 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+)
+
+// PendingFilter 选择关心的待处理交易，零值匹配所有交易
+type PendingFilter struct {
+	From     []common.Address // 发送方是其中之一，空表示不限
+	To       []common.Address // 接收方是其中之一，空表示不限
+	Address  []common.Address // 发送方或接收方是其中之一，空表示不限
+	MinValue *big.Int         // 转账金额（wei）不少于该值，nil 表示不限
+}
+
+// Match 报告发送方为 from 的 tx 是否匹配
+func (f PendingFilter) Match(tx *types.Transaction, from common.Address) bool {
+	var to common.Address
+	if tx.To() != nil {
+		to = *tx.To()
+	}
+	if len(f.From) > 0 && !contains(f.From, from) {
+		return false
+	}
+	if len(f.To) > 0 && (tx.To() == nil || !contains(f.To, to)) {
+		return false
+	}
+	if len(f.Address) > 0 && !contains(f.Address, from) && (tx.To() == nil || !contains(f.Address, to)) {
+		return false
+	}
+	return f.MinValue == nil || tx.Value().Cmp(f.MinValue) >= 0
+}
+
+func contains(list []common.Address, a common.Address) bool {
+	for _, x := range list {
+		if x == a {
+			return true
+		}
+	}
+	return false
+}
+
+// seenLimit 是记住的已处理交易哈希数，超过后清空重新记录
+const seenLimit = 50000
+
+// Pending 订阅节点交易池中的新交易（newPendingTransactions），对匹配 filter 的交易调用 handle，直到 ctx 结束或
+// handle 返回错误。优先订阅完整的交易对象；节点不支持时改为订阅交易哈希再逐个读取交易，流量大的链上
+// 这会产生大量请求。同一交易重复推送时只处理一次。交易池中的交易不保证会被打包，也看不到私有交易池中的交易。
+func Pending(ctx context.Context, url string, filter PendingFilter, opts Options, handle func(tx *types.Transaction, from common.Address) error) error {
+	hashesOnly := false
+	seen := make(map[common.Hash]bool)
+	return run(ctx, url, opts, func(ctx context.Context, client *ethclient.Client, received func()) error {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		signer := types.LatestSignerForChainID(chainID)
+		deliver := func(tx *types.Transaction) error {
+			received()
+			if seen[tx.Hash()] {
+				return nil
+			}
+			if len(seen) >= seenLimit {
+				clear(seen)
+			}
+			seen[tx.Hash()] = true
+			from, err := types.Sender(signer, tx)
+			if err != nil || !filter.Match(tx, from) {
+				return nil
+			}
+			if err := handle(tx, from); err != nil {
+				return &handlerError{err}
+			}
+			return nil
+		}
+
+		stall := newStallTimer(opts.StallTimeout)
+		defer stall.Stop()
+		gc := gethclient.New(client.Client())
+		if !hashesOnly {
+			txs := make(chan *types.Transaction, 256)
+			sub, err := gc.SubscribeFullPendingTransactions(ctx, txs)
+			if err == nil {
+				defer sub.Unsubscribe()
+				for {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case err := <-sub.Err():
+						// 忽略 full 参数、仍然推送哈希的节点会导致解码失败，之后改为订阅哈希
+						if err != nil && strings.Contains(err.Error(), "unmarshal") {
+							hashesOnly = true
+						}
+						return fmt.Errorf("pending transactions subscription: %w", err)
+					case <-stall.C():
+						return errStalled
+					case tx := <-txs:
+						stall.Reset()
+						if err := deliver(tx); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			hashesOnly = true
+		}
+
+		hashes := make(chan common.Hash, 1024)
+		sub, err := gc.SubscribePendingTransactions(ctx, hashes)
+		if err != nil {
+			return fmt.Errorf("subscribe to pending transactions: %w", err)
+		}
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case err := <-sub.Err():
+				return fmt.Errorf("pending transactions subscription: %w", err)
+			case <-stall.C():
+				return errStalled
+			case hash := <-hashes:
+				stall.Reset()
+				if seen[hash] {
+					continue
+				}
+				// 读取时交易可能已经打包或被替换，这类交易直接跳过
+				tx, pending, err := client.TransactionByHash(ctx, hash)
+				if err != nil || !pending {
+					continue
+				}
+				if err := deliver(tx); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
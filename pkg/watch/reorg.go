@@ -0,0 +1,117 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultReorgWindow 是默认保留的最近区块数，远大于以太坊主网和常见 L2 上实际出现的重组深度
+const DefaultReorgWindow = 64
+
+// Reorg 描述一次链重组
+type Reorg struct {
+	Depth    int             // 被替换的区块数
+	Ancestor uint64          // 新旧链的共同祖先区块号
+	Removed  []*types.Header // 被替换的旧区块，从低到高
+	Added    []*types.Header // 替换它们的新区块，从低到高，最后一个是新的链头
+	// Deep 表示共同祖先已不在窗口内，实际深度可能大于 Depth
+	Deep bool
+}
+
+// Tracker 保留最近一段连续区块的哈希，发现新链头的父区块与记录不符时沿新链向前查找共同祖先，
+// 报告重组并给出新链上需要重新处理的区块。不是并发安全的
+type Tracker struct {
+	window  int
+	headers []*types.Header // 连续的最近区块，从低到高
+}
+
+// NewTracker 创建保留 window 个区块的 Tracker，window 不大于 0 时使用 DefaultReorgWindow
+func NewTracker(window int) *Tracker {
+	if window <= 0 {
+		window = DefaultReorgWindow
+	}
+	return &Tracker{window: window}
+}
+
+// HeaderByHash 按哈希读取区块头，*ethclient.Client 的同名方法满足该类型
+type HeaderByHash func(ctx context.Context, hash common.Hash) (*types.Header, error)
+
+// Add 记录新链头 head，返回按顺序需要处理的区块：通常只有 head 本身；中间有缺失的区块（如断线重连后）时
+// 补上缺失的区块；发生重组时为新链上共同祖先之后的区块，同时返回 Reorg。已经记录过的区块返回空。
+// 与上一个链头相差超过窗口时无法检查，从 head 重新开始记录。
+func (t *Tracker) Add(ctx context.Context, head *types.Header, byHash HeaderByHash) ([]*types.Header, *Reorg, error) {
+	if len(t.headers) == 0 || head.Number.Uint64() > t.tip().Number.Uint64()+uint64(t.window) {
+		t.headers = []*types.Header{head}
+		return []*types.Header{head}, nil, nil
+	}
+	if known, ok := t.at(head.Number.Uint64()); ok && known.Hash() == head.Hash() {
+		return nil, nil, nil
+	}
+
+	// 沿新链向前，直到父区块是窗口中记录的区块
+	segment := []*types.Header{head}
+	cur, deep := head, false
+	for {
+		if cur.Number.Sign() == 0 {
+			deep = true
+			break
+		}
+		pn := cur.Number.Uint64() - 1
+		if known, ok := t.at(pn); ok && known.Hash() == cur.ParentHash {
+			break
+		}
+		if pn < t.headers[0].Number.Uint64() {
+			deep = true
+			break
+		}
+		parent, err := byHash(ctx, cur.ParentHash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get block %s: %w", cur.ParentHash.Hex(), err)
+		}
+		if parent.Hash() != cur.ParentHash || parent.Number.Uint64() != pn {
+			return nil, nil, fmt.Errorf("node returned block %d (%s) for parent %s of block %d",
+				parent.Number, parent.Hash().Hex(), cur.ParentHash.Hex(), cur.Number)
+		}
+		segment = append([]*types.Header{parent}, segment...)
+		cur = parent
+	}
+
+	first, lo := segment[0].Number.Uint64(), t.headers[0].Number.Uint64()
+	var keep, removed []*types.Header
+	switch {
+	case first < lo:
+		removed = t.headers
+	case first-lo < uint64(len(t.headers)):
+		keep, removed = t.headers[:first-lo], t.headers[first-lo:]
+	default:
+		keep = t.headers
+	}
+	t.headers = append(keep[:len(keep):len(keep)], segment...)
+	if len(t.headers) > t.window {
+		t.headers = t.headers[len(t.headers)-t.window:]
+	}
+	if len(removed) == 0 {
+		return segment, nil, nil
+	}
+	return segment, &Reorg{
+		Depth:    len(removed),
+		Ancestor: first - min(first, 1),
+		Removed:  removed,
+		Added:    segment,
+		Deep:     deep,
+	}, nil
+}
+
+func (t *Tracker) tip() *types.Header { return t.headers[len(t.headers)-1] }
+
+// at 返回窗口中区块号为 n 的区块
+func (t *Tracker) at(n uint64) (*types.Header, bool) {
+	lo := t.headers[0].Number.Uint64()
+	if n < lo || n-lo >= uint64(len(t.headers)) {
+		return nil, false
+	}
+	return t.headers[n-lo], true
+}
@@ -0,0 +1,100 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// testChain 按哈希保存区块头，fork 用来区分同一高度上不同分支的区块
+type testChain map[common.Hash]*types.Header
+
+// extend 在 parent 之上生成 count 个区块，返回新区块（从低到高）
+func (c testChain) extend(parent *types.Header, count int, fork byte) []*types.Header {
+	var out []*types.Header
+	for range count {
+		h := &types.Header{Difficulty: new(big.Int), Extra: []byte{fork}}
+		if parent == nil {
+			h.Number = new(big.Int)
+		} else {
+			h.Number = new(big.Int).Add(parent.Number, big.NewInt(1))
+			h.ParentHash = parent.Hash()
+		}
+		c[h.Hash()] = h
+		out = append(out, h)
+		parent = h
+	}
+	return out
+}
+
+func (c testChain) byHash(_ context.Context, hash common.Hash) (*types.Header, error) {
+	if h, ok := c[hash]; ok {
+		return h, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func numbers(headers []*types.Header) []uint64 {
+	out := make([]uint64, len(headers))
+	for i, h := range headers {
+		out[i] = h.Number.Uint64()
+	}
+	return out
+}
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	chain := make(testChain)
+	main := chain.extend(nil, 10, 0) // 区块 0-9
+	tr := NewTracker(8)
+	for _, h := range main[:6] {
+		if added, reorg, err := tr.Add(ctx, h, chain.byHash); err != nil || reorg != nil || len(added) != 1 {
+			t.Fatalf("Add(%d) = %v, %v, %v", h.Number, numbers(added), reorg, err)
+		}
+	}
+
+	// 重复推送的链头忽略
+	if added, reorg, _ := tr.Add(ctx, main[5], chain.byHash); len(added) != 0 || reorg != nil {
+		t.Errorf("duplicate head: added %v, reorg %v", numbers(added), reorg)
+	}
+
+	// 缺失的区块 6、7 补上
+	added, reorg, err := tr.Add(ctx, main[8], chain.byHash)
+	if err != nil || reorg != nil || len(added) != 3 || added[0] != main[6] {
+		t.Fatalf("gap: added %v, reorg %v, %v", numbers(added), reorg, err)
+	}
+
+	// 区块 7 之后分叉：新链的 8'、9'、10' 替换 8
+	fork := chain.extend(main[7], 3, 1)
+	added, reorg, err = tr.Add(ctx, fork[2], chain.byHash)
+	if err != nil || reorg == nil {
+		t.Fatalf("fork: reorg %v, %v", reorg, err)
+	}
+	if reorg.Depth != 1 || reorg.Ancestor != 7 || reorg.Removed[0] != main[8] || reorg.Deep || len(added) != 3 || added[0] != fork[0] {
+		t.Errorf("fork: reorg %+v, added %v", reorg, numbers(added))
+	}
+
+	// 同一高度上替换链头
+	sibling := chain.extend(fork[1], 1, 2)[0]
+	added, reorg, _ = tr.Add(ctx, sibling, chain.byHash)
+	if reorg == nil || reorg.Depth != 1 || reorg.Removed[0] != fork[2] || len(added) != 1 || added[0] != sibling {
+		t.Errorf("sibling: reorg %+v, added %v", reorg, numbers(added))
+	}
+
+	// 深于窗口的重组：共同祖先是区块 1，窗口只保留 8 个区块
+	deep := chain.extend(main[1], 10, 3)
+	_, reorg, err = tr.Add(ctx, deep[9], chain.byHash)
+	if err != nil || reorg == nil || !reorg.Deep || reorg.Depth != 8 {
+		t.Errorf("deep: reorg %+v, %v", reorg, err)
+	}
+
+	// 与链头相差超过窗口时重新开始
+	far := chain.extend(deep[9], 20, 3)
+	if added, reorg, _ := tr.Add(ctx, far[19], chain.byHash); reorg != nil || len(added) != 1 {
+		t.Errorf("far head: added %v, reorg %v", numbers(added), reorg)
+	}
+}
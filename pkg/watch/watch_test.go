@@ -0,0 +1,413 @@
+package watch
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/local/go-eth-demo/internal/chaos"
+)
+
+// fakeChain 每隔 10ms 产生一个新区块头
+type fakeChain struct {
+	number atomic.Uint64
+}
+
+func (c *fakeChain) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sub.Err():
+				return
+			case <-ticker.C:
+				head := &types.Header{Number: new(big.Int).SetUint64(c.number.Add(1)), Difficulty: new(big.Int)}
+				notifier.Notify(sub.ID, head)
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// newProxiedNode 启动 WebSocket 节点，返回经过可断开代理的 ws:// 地址
+func newProxiedNode(t *testing.T, api any) *chaos.Proxy {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	ws := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(ws.Close)
+	proxy, err := chaos.NewProxy(strings.TrimPrefix(ws.URL, "http://"), 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { proxy.Close() })
+	return proxy
+}
+
+func TestHeadsReconnect(t *testing.T) {
+	proxy := newProxiedNode(t, new(fakeChain))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var received, reconnects atomic.Int32
+	opts := Options{MinBackoff: 10 * time.Millisecond, OnReconnect: func(int, error) { reconnects.Add(1) }}
+	done := errors.New("done")
+	err := Heads(ctx, "ws://"+proxy.Addr(), opts, func(_ *ethclient.Client, head *types.Header) error {
+		switch received.Add(1) {
+		case 3:
+			proxy.DropAll()
+		case 6:
+			return done
+		}
+		return nil
+	})
+	if !errors.Is(err, done) {
+		t.Fatalf("Heads = %v, want the handler error", err)
+	}
+	if reconnects.Load() == 0 {
+		t.Error("no reconnect after the connection was dropped")
+	}
+}
+
+func TestHeadsStall(t *testing.T) {
+	// 没有 newHeads 推送的节点：订阅成功但一直收不到数据
+	proxy := newProxiedNode(t, new(silentChain))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var lastErr atomic.Value
+	opts := Options{MinBackoff: time.Millisecond, StallTimeout: 20 * time.Millisecond, OnReconnect: func(attempt int, err error) {
+		lastErr.Store(err)
+		if attempt == 2 {
+			cancel()
+		}
+	}}
+	if err := Heads(ctx, "ws://"+proxy.Addr(), opts, func(*ethclient.Client, *types.Header) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Heads = %v, want context.Canceled", err)
+	}
+	if err, _ := lastErr.Load().(error); !errors.Is(err, errStalled) {
+		t.Errorf("reconnect reason = %v, want errStalled", err)
+	}
+}
+
+type silentChain struct{}
+
+func (silentChain) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	return notifier.CreateSubscription(), nil
+}
+
+// logChain 每隔 10ms 产生一个包含一条日志的新区块，支持 logs 订阅和 eth_getLogs。
+// reorg 替换某个区块之后的所有区块（区块哈希改变）
+type logChain struct {
+	mu       sync.Mutex
+	logs     []types.Log // 第 i 条日志在区块 i+1
+	forkFrom uint64      // 从该区块起属于新链，0 表示没有重组
+}
+
+// hash 返回区块 n 当前的哈希，调用时持有 mu
+func (c *logChain) hash(n uint64) common.Hash {
+	if c.forkFrom != 0 && n >= c.forkFrom {
+		n += 1 << 32
+	}
+	return common.BigToHash(new(big.Int).SetUint64(n))
+}
+
+func (c *logChain) reorg(from uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forkFrom = from
+	logs := make([]types.Log, len(c.logs))
+	copy(logs, c.logs)
+	for i := range logs {
+		logs[i].BlockHash = c.hash(logs[i].BlockNumber)
+	}
+	c.logs = logs
+}
+
+func newLogChain(t *testing.T) *logChain {
+	c := new(logChain)
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				c.mu.Lock()
+				n := uint64(len(c.logs) + 1)
+				c.logs = append(c.logs, types.Log{
+					Topics:      []common.Hash{},
+					Data:        []byte{},
+					BlockNumber: n,
+					BlockHash:   c.hash(n),
+				})
+				c.mu.Unlock()
+			}
+		}
+	}()
+	return c
+}
+
+func (c *logChain) snapshot() []types.Log {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.logs[:len(c.logs):len(c.logs)]
+}
+
+func (c *logChain) BlockNumber() hexutil.Uint64 { return hexutil.Uint64(len(c.snapshot())) }
+
+type filterArg struct {
+	FromBlock rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   rpc.BlockNumber `json:"toBlock"`
+}
+
+func (c *logChain) GetLogs(arg filterArg) []types.Log {
+	logs := c.snapshot()
+	to := int64(len(logs))
+	if arg.ToBlock >= 0 {
+		to = min(int64(arg.ToBlock), to)
+	}
+	from := max(int64(arg.FromBlock), 1)
+	if from > to {
+		return []types.Log{}
+	}
+	return logs[from-1 : to]
+}
+
+func (c *logChain) Logs(ctx context.Context, _ filterArg) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	sent := len(c.snapshot())
+	go func() {
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sub.Err():
+				return
+			case <-ticker.C:
+				logs := c.snapshot()
+				for _, log := range logs[sent:] {
+					notifier.Notify(sub.ID, log)
+				}
+				sent = len(logs)
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func TestLogsBackfill(t *testing.T) {
+	proxy := newProxiedNode(t, newLogChain(t))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 断线后退避 50ms，期间产生的日志只能靠补齐拿到
+	var blocks []uint64
+	var reconnects atomic.Int32
+	opts := Options{MinBackoff: 50 * time.Millisecond, OnReconnect: func(int, error) { reconnects.Add(1) }}
+	done := errors.New("done")
+	q := ethereum.FilterQuery{FromBlock: big.NewInt(1)}
+	err := Logs(ctx, "ws://"+proxy.Addr(), q, opts, func(log types.Log) error {
+		blocks = append(blocks, log.BlockNumber)
+		switch len(blocks) {
+		case 5:
+			proxy.DropAll()
+		case 20:
+			return done
+		}
+		return nil
+	})
+	if !errors.Is(err, done) {
+		t.Fatalf("Logs = %v, want the handler error", err)
+	}
+	if reconnects.Load() == 0 {
+		t.Error("no reconnect after the connection was dropped")
+	}
+	// 从区块 1 开始连续，没有丢失也没有重复
+	for i, n := range blocks {
+		if n != uint64(i+1) {
+			t.Fatalf("log %d in block %d, want %d (blocks %v)", i, n, i+1, blocks)
+		}
+	}
+}
+
+func TestLogsReorgWhileDisconnected(t *testing.T) {
+	chain := newLogChain(t)
+	proxy := newProxiedNode(t, chain)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 处理到区块 5 后断线，断线期间区块 4 起被重组替换
+	state := make(map[uint64]common.Hash) // 调用方视角下每个区块的日志
+	var added, removed int
+	done := errors.New("done")
+	q := ethereum.FilterQuery{FromBlock: big.NewInt(1)}
+	err := Logs(ctx, "ws://"+proxy.Addr(), q, Options{MinBackoff: 50 * time.Millisecond}, func(log types.Log) error {
+		if log.Removed {
+			if state[log.BlockNumber] != log.BlockHash {
+				t.Errorf("removed log in block %d that was not delivered", log.BlockNumber)
+			}
+			delete(state, log.BlockNumber)
+			removed++
+			return nil
+		}
+		state[log.BlockNumber] = log.BlockHash
+		switch added++; added {
+		case 5:
+			chain.reorg(4)
+			proxy.DropAll()
+		case 25:
+			return done
+		}
+		return nil
+	})
+	if !errors.Is(err, done) {
+		t.Fatalf("Logs = %v, want the handler error", err)
+	}
+	if removed < 2 {
+		t.Errorf("%d logs removed, want at least the ones in blocks 4 and 5", removed)
+	}
+	chain.mu.Lock()
+	defer chain.mu.Unlock()
+	for n, hash := range state {
+		if want := chain.hash(n); hash != want {
+			t.Errorf("block %d: log from %s is left after the reorg, want %s", n, hash.Hex(), want.Hex())
+		}
+	}
+}
+
+// mempool 推送一组已签名的待处理交易（每笔推送两次），full 为 false 时只支持推送哈希
+type mempool struct {
+	full bool
+	txs  []*types.Transaction
+}
+
+func (m *mempool) ChainId() hexutil.Uint64 { return 1 }
+
+func (m *mempool) NewPendingTransactions(ctx context.Context, fullTx *bool) (*rpc.Subscription, error) {
+	full := fullTx != nil && *fullTx
+	if full && !m.full {
+		return nil, errors.New("full transactions not supported")
+	}
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	go func() {
+		for range 2 {
+			for _, tx := range m.txs {
+				if full {
+					notifier.Notify(sub.ID, tx)
+				} else {
+					notifier.Notify(sub.ID, tx.Hash())
+				}
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func (m *mempool) GetTransactionByHash(hash common.Hash) map[string]any {
+	for _, tx := range m.txs {
+		if tx.Hash() == hash {
+			data, _ := tx.MarshalJSON()
+			var out map[string]any
+			json.Unmarshal(data, &out)
+			out["blockHash"], out["blockNumber"], out["transactionIndex"] = nil, nil, nil
+			return out
+		}
+	}
+	return nil
+}
+
+func TestPending(t *testing.T) {
+	alice, _ := crypto.GenerateKey()
+	bob, _ := crypto.GenerateKey()
+	me := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	var txs []*types.Transaction
+	for i, c := range []struct {
+		key   *ecdsa.PrivateKey
+		to    common.Address
+		value int64
+	}{{alice, me, 100}, {alice, other, 100}, {bob, me, 5}, {bob, me, 1000}} {
+		txs = append(txs, types.MustSignNewTx(c.key, signer, &types.DynamicFeeTx{
+			ChainID: big.NewInt(1), Nonce: uint64(i), GasFeeCap: big.NewInt(1), Gas: 21000, To: &c.to, Value: big.NewInt(c.value),
+		}))
+	}
+
+	for _, full := range []bool{true, false} {
+		proxy := newProxiedNode(t, &mempool{full: full, txs: txs})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		filter := PendingFilter{To: []common.Address{me}, MinValue: big.NewInt(50)}
+		var got []common.Hash
+		done := errors.New("done")
+		err := Pending(ctx, "ws://"+proxy.Addr(), filter, Options{}, func(tx *types.Transaction, from common.Address) error {
+			got = append(got, tx.Hash())
+			if len(got) == 2 {
+				return done
+			}
+			return nil
+		})
+		cancel()
+		if !errors.Is(err, done) {
+			t.Fatalf("full=%v: Pending = %v after %d matches", full, err, len(got))
+		}
+		// 重复推送的交易只处理一次，其余被过滤
+		if got[0] != txs[0].Hash() || got[1] != txs[3].Hash() {
+			t.Errorf("full=%v: matched %v, want transactions 0 and 3", full, got)
+		}
+	}
+}
+
+func TestPendingFilter(t *testing.T) {
+	a, b := common.HexToAddress("0xa"), common.HexToAddress("0xb")
+	tx := types.NewTx(&types.LegacyTx{To: &b, Value: big.NewInt(10)})
+	create := types.NewTx(&types.LegacyTx{Value: big.NewInt(10)})
+	for _, c := range []struct {
+		f    PendingFilter
+		tx   *types.Transaction
+		want bool
+	}{
+		{PendingFilter{}, tx, true},
+		{PendingFilter{From: []common.Address{a}}, tx, true},
+		{PendingFilter{From: []common.Address{b}}, tx, false},
+		{PendingFilter{To: []common.Address{b}}, tx, true},
+		{PendingFilter{To: []common.Address{b}}, create, false},
+		{PendingFilter{Address: []common.Address{b}}, tx, true},
+		{PendingFilter{Address: []common.Address{b}}, create, false},
+		{PendingFilter{MinValue: big.NewInt(11)}, tx, false},
+	} {
+		if got := c.f.Match(c.tx, a); got != c.want {
+			t.Errorf("%+v matches tx to %v: %v, want %v", c.f, c.tx.To(), got, c.want)
+		}
+	}
+}
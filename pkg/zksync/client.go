@@ -0,0 +1,142 @@
+package zksync
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Fee 是 zks_estimateFee 的结果
+type Fee struct {
+	GasLimit             *hexutil.Big `json:"gas_limit"`
+	GasPerPubdataLimit   *hexutil.Big `json:"gas_per_pubdata_limit"`
+	MaxFeePerGas         *hexutil.Big `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"max_priority_fee_per_gas"`
+}
+
+// callRequest 是 zks_estimateFee 的参数，eip712Meta 中是 Era 特有的字段
+type callRequest struct {
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to,omitempty"`
+	Value      *hexutil.Big    `json:"value,omitempty"`
+	Data       hexutil.Bytes   `json:"data,omitempty"`
+	Eip712Meta eip712Meta      `json:"eip712Meta"`
+}
+
+type eip712Meta struct {
+	GasPerPubdata   *hexutil.Big     `json:"gasPerPubdata"`
+	FactoryDeps     []hexutil.Bytes  `json:"factoryDeps,omitempty"`
+	PaymasterParams *paymasterParams `json:"paymasterParams,omitempty"`
+}
+
+type paymasterParams struct {
+	Paymaster      common.Address `json:"paymaster"`
+	PaymasterInput hexutil.Bytes  `json:"paymasterInput"`
+}
+
+// Client 是 zkSync Era 节点的客户端，普通的以太坊方法通过内嵌的 ethclient 调用
+type Client struct {
+	*ethclient.Client
+	rpc *rpc.Client
+}
+
+// Dial 连接 Era 节点
+func Dial(ctx context.Context, url string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient 包装已有的 RPC 连接
+func NewClient(c *rpc.Client) *Client {
+	return &Client{Client: ethclient.NewClient(c), rpc: c}
+}
+
+// EstimateFee 调用 zks_estimateFee，返回 gas 上限、费用和 gasPerPubdata 上限。
+// 与 eth_estimateGas 不同，它考虑了 pubdata 的开销和 tx.GasPerPubdata 的设置。
+func (c *Client) EstimateFee(ctx context.Context, tx *Transaction) (*Fee, error) {
+	req := callRequest{
+		From:  tx.From,
+		To:    tx.To,
+		Value: (*hexutil.Big)(tx.Value),
+		Data:  tx.Data,
+		Eip712Meta: eip712Meta{
+			GasPerPubdata: (*hexutil.Big)(new(big.Int).SetUint64(gasPerPubdataOrDefault(tx.GasPerPubdata))),
+		},
+	}
+	for _, dep := range tx.FactoryDeps {
+		req.Eip712Meta.FactoryDeps = append(req.Eip712Meta.FactoryDeps, dep)
+	}
+	if tx.Paymaster != (common.Address{}) {
+		req.Eip712Meta.PaymasterParams = &paymasterParams{Paymaster: tx.Paymaster, PaymasterInput: nonNil(tx.PaymasterInput)}
+	}
+	var fee Fee
+	if err := c.rpc.CallContext(ctx, &fee, "zks_estimateFee", req); err != nil {
+		return nil, fmt.Errorf("zks_estimateFee: %w", err)
+	}
+	if fee.GasLimit == nil || fee.MaxFeePerGas == nil {
+		return nil, fmt.Errorf("zks_estimateFee returned an incomplete result")
+	}
+	return &fee, nil
+}
+
+// Populate 填入 tx 中未设置的链 ID、nonce 和费用参数（由 zks_estimateFee 估算）
+func (c *Client) Populate(ctx context.Context, tx *Transaction) error {
+	if tx.ChainID == nil {
+		id, err := c.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("get chain ID: %w", err)
+		}
+		tx.ChainID = id
+	}
+	nonce, err := c.PendingNonceAt(ctx, tx.From)
+	if err != nil {
+		return fmt.Errorf("get nonce: %w", err)
+	}
+	tx.Nonce = nonce
+	if tx.GasLimit == 0 || tx.MaxFeePerGas == nil {
+		fee, err := c.EstimateFee(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if tx.GasLimit == 0 {
+			tx.GasLimit = fee.GasLimit.ToInt().Uint64()
+		}
+		if tx.MaxFeePerGas == nil {
+			tx.MaxFeePerGas = fee.MaxFeePerGas.ToInt()
+			tx.MaxPriorityFeePerGas = fee.MaxPriorityFeePerGas.ToInt()
+		}
+		if tx.GasPerPubdata == 0 && fee.GasPerPubdataLimit != nil {
+			tx.GasPerPubdata = fee.GasPerPubdataLimit.ToInt().Uint64()
+		}
+	}
+	tx.GasPerPubdata = gasPerPubdataOrDefault(tx.GasPerPubdata)
+	return nil
+}
+
+// SendEIP712 用 eth_sendRawTransaction 提交已签名的 EIP-712 交易，返回节点计算的交易哈希
+func (c *Client) SendEIP712(ctx context.Context, tx *Transaction) (common.Hash, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var hash common.Hash
+	if err := c.rpc.CallContext(ctx, &hash, "eth_sendRawTransaction", hexutil.Bytes(raw)); err != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendRawTransaction: %w", err)
+	}
+	return hash, nil
+}
+
+func gasPerPubdataOrDefault(v uint64) uint64 {
+	if v == 0 {
+		return DefaultGasPerPubdata
+	}
+	return v
+}
@@ -0,0 +1,233 @@
+// Package zksync 实现 zkSync Era 的原生 EIP-712 交易（类型 0x71）：按 EIP-712 签名，
+// 按 Era 的 RLP 格式序列化，并用 zks_estimateFee 估算包括 gasPerPubdata 在内的费用参数。
+// Era 的 gas 用量包含发布状态差异（pubdata）到 L1 的开销，gasPerPubdata 是用户愿意为每字节
+// pubdata 支付的 gas 上限。
+package zksync
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const (
+	// TxType 是 EIP-712 交易的类型字节
+	TxType = 0x71
+	// DefaultGasPerPubdata 是 zksync-ethers 等 SDK 使用的默认 gasPerPubdata 上限
+	DefaultGasPerPubdata = 50_000
+)
+
+// Transaction 是一笔 zkSync Era EIP-712 交易。FactoryDeps 是随交易发布的合约字节码
+// （部署合约时需要），Paymaster 为零地址时由发送者支付费用。
+type Transaction struct {
+	ChainID              *big.Int
+	Nonce                uint64
+	From                 common.Address
+	To                   *common.Address // Era 上部署合约也是调用 ContractDeployer，通常不为 nil
+	Value                *big.Int
+	Data                 []byte
+	GasLimit             uint64
+	GasPerPubdata        uint64
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	FactoryDeps          [][]byte
+	Paymaster            common.Address
+	PaymasterInput       []byte
+
+	// Signature 是 65 字节的 EIP-712 签名（r || s || v，v 为 27 或 28）
+	Signature []byte
+}
+
+// transactionType 是 Era 签名使用的 EIP-712 结构体定义
+var transactionType = []apitypes.Type{
+	{Name: "txType", Type: "uint256"},
+	{Name: "from", Type: "uint256"},
+	{Name: "to", Type: "uint256"},
+	{Name: "gasLimit", Type: "uint256"},
+	{Name: "gasPerPubdataByteLimit", Type: "uint256"},
+	{Name: "maxFeePerGas", Type: "uint256"},
+	{Name: "maxPriorityFeePerGas", Type: "uint256"},
+	{Name: "paymaster", Type: "uint256"},
+	{Name: "nonce", Type: "uint256"},
+	{Name: "value", Type: "uint256"},
+	{Name: "data", Type: "bytes"},
+	{Name: "factoryDeps", Type: "bytes32[]"},
+	{Name: "paymasterInput", Type: "bytes"},
+}
+
+// TypedData 返回交易的 EIP-712 结构化数据，地址按 uint256 编码，字节码以 HashBytecode 表示
+func (tx *Transaction) TypedData() (apitypes.TypedData, error) {
+	deps := make([]interface{}, len(tx.FactoryDeps))
+	for i, code := range tx.FactoryDeps {
+		hash, err := HashBytecode(code)
+		if err != nil {
+			return apitypes.TypedData{}, fmt.Errorf("factory dependency %d: %w", i, err)
+		}
+		deps[i] = hash.Hex()
+	}
+	var to common.Address
+	if tx.To != nil {
+		to = *tx.To
+	}
+	addr := func(a common.Address) string { return new(big.Int).SetBytes(a.Bytes()).String() }
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}, {Name: "version", Type: "string"}, {Name: "chainId", Type: "uint256"}},
+			"Transaction":  transactionType,
+		},
+		PrimaryType: "Transaction",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "zkSync",
+			Version: "2",
+			ChainId: (*math.HexOrDecimal256)(tx.ChainID),
+		},
+		Message: apitypes.TypedDataMessage{
+			"txType":                 fmt.Sprint(TxType),
+			"from":                   addr(tx.From),
+			"to":                     addr(to),
+			"gasLimit":               fmt.Sprint(tx.GasLimit),
+			"gasPerPubdataByteLimit": fmt.Sprint(tx.GasPerPubdata),
+			"maxFeePerGas":           orZero(tx.MaxFeePerGas).String(),
+			"maxPriorityFeePerGas":   orZero(tx.MaxPriorityFeePerGas).String(),
+			"paymaster":              addr(tx.Paymaster),
+			"nonce":                  fmt.Sprint(tx.Nonce),
+			"value":                  orZero(tx.Value).String(),
+			"data":                   hexutil.Bytes(nonNil(tx.Data)),
+			"factoryDeps":            deps,
+			"paymasterInput":         hexutil.Bytes(nonNil(tx.PaymasterInput)),
+		},
+	}, nil
+}
+
+// SigningHash 返回需要签名的 EIP-712 摘要
+func (tx *Transaction) SigningHash() (common.Hash, error) {
+	typed, err := tx.TypedData()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("hash EIP-712 transaction: %w", err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// Sign 用 key 签名交易，并把 From 设为 key 对应的地址
+func (tx *Transaction) Sign(key *ecdsa.PrivateKey) error {
+	tx.From = crypto.PubkeyToAddress(key.PublicKey)
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return err
+	}
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		return fmt.Errorf("sign transaction: %w", err)
+	}
+	sig[64] += 27
+	tx.Signature = sig
+	return nil
+}
+
+// Sender 从签名中恢复发送者地址
+func (tx *Transaction) Sender() (common.Address, error) {
+	if len(tx.Signature) != 65 {
+		return common.Address{}, errors.New("transaction is not signed")
+	}
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return common.Address{}, err
+	}
+	sig := common.CopyBytes(tx.Signature)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("recover sender: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// MarshalBinary 按 Era 的格式序列化已签名的交易：
+// 0x71 || rlp([nonce, maxPriorityFeePerGas, maxFeePerGas, gasLimit, to, value, data,
+// chainId, "", "", chainId, from, gasPerPubdata, factoryDeps, signature, paymasterParams])
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if len(tx.Signature) != 65 {
+		return nil, errors.New("transaction is not signed")
+	}
+	var to []byte
+	if tx.To != nil {
+		to = tx.To.Bytes()
+	}
+	// 没有 paymaster 时 paymasterParams 编码为空列表
+	pm := []interface{}{}
+	if tx.Paymaster != (common.Address{}) {
+		pm = []interface{}{tx.Paymaster, nonNil(tx.PaymasterInput)}
+	}
+	deps := tx.FactoryDeps
+	if deps == nil {
+		deps = [][]byte{}
+	}
+	fields := []interface{}{
+		tx.Nonce, orZero(tx.MaxPriorityFeePerGas), orZero(tx.MaxFeePerGas), tx.GasLimit, to, orZero(tx.Value), nonNil(tx.Data),
+		tx.ChainID, []byte{}, []byte{}, tx.ChainID, tx.From, tx.GasPerPubdata, deps, tx.Signature, pm,
+	}
+	enc, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, fmt.Errorf("encode transaction: %w", err)
+	}
+	return append([]byte{TxType}, enc...), nil
+}
+
+// Hash 返回交易在 Era 上的哈希。与以太坊不同，它不是序列化结果的 keccak256，而是
+// keccak256(EIP-712 摘要 || keccak256(签名))
+func (tx *Transaction) Hash() (common.Hash, error) {
+	if len(tx.Signature) != 65 {
+		return common.Hash{}, errors.New("transaction is not signed")
+	}
+	digest, err := tx.SigningHash()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(digest.Bytes(), crypto.Keccak256(tx.Signature)), nil
+}
+
+// HashBytecode 计算 Era 的字节码哈希：sha256(code)，前两个字节替换为版本号 1 和 0，
+// 第 3、4 字节替换为以 32 字节字计的长度。字节码长度必须是奇数个字。
+func HashBytecode(code []byte) (common.Hash, error) {
+	if len(code)%32 != 0 {
+		return common.Hash{}, fmt.Errorf("bytecode length %d is not a multiple of 32", len(code))
+	}
+	words := len(code) / 32
+	if words >= 1<<16 {
+		return common.Hash{}, fmt.Errorf("bytecode too long: %d words", words)
+	}
+	if words%2 == 0 {
+		return common.Hash{}, fmt.Errorf("bytecode length in words must be odd, got %d", words)
+	}
+	hash := sha256.Sum256(code)
+	hash[0], hash[1] = 1, 0
+	hash[2], hash[3] = byte(words>>8), byte(words)
+	return hash, nil
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+func nonNil(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
@@ -0,0 +1,169 @@
+package zksync
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	recipient  = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func testTx() *Transaction {
+	return &Transaction{
+		ChainID: big.NewInt(300), Nonce: 3, To: &recipient, Value: big.NewInt(1e15),
+		GasLimit: 300_000, GasPerPubdata: DefaultGasPerPubdata,
+		MaxFeePerGas: big.NewInt(25e6), MaxPriorityFeePerGas: big.NewInt(0),
+	}
+}
+
+func TestSignAndRecover(t *testing.T) {
+	tx := testTx()
+	if err := tx.Sign(testKey); err != nil {
+		t.Fatal(err)
+	}
+	sender, err := tx.Sender()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender != crypto.PubkeyToAddress(testKey.PublicKey) || sender != tx.From {
+		t.Errorf("sender = %s, want %s", sender.Hex(), tx.From.Hex())
+	}
+
+	// 签名覆盖 gasPerPubdata 和链 ID
+	digest, _ := tx.SigningHash()
+	tx.GasPerPubdata++
+	if other, _ := tx.SigningHash(); other == digest {
+		t.Error("signing hash does not cover gasPerPubdata")
+	}
+	tx.GasPerPubdata--
+	tx.ChainID = big.NewInt(324)
+	if other, _ := tx.SigningHash(); other == digest {
+		t.Error("signing hash does not cover the chain ID")
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	tx := testTx()
+	if _, err := tx.MarshalBinary(); err == nil {
+		t.Error("unsigned transaction serialized")
+	}
+	if err := tx.Sign(testKey); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw[0] != TxType {
+		t.Fatalf("type byte = %#x, want %#x", raw[0], TxType)
+	}
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(raw[1:], &fields); err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 16 {
+		t.Fatalf("got %d fields, want 16", len(fields))
+	}
+	var to, from common.Address
+	var chainID, gasPerPubdata uint64
+	var sig []byte
+	for _, f := range []struct {
+		index int
+		out   interface{}
+	}{{4, &to}, {10, &chainID}, {11, &from}, {12, &gasPerPubdata}, {14, &sig}} {
+		if err := rlp.DecodeBytes(fields[f.index], f.out); err != nil {
+			t.Fatalf("field %d: %v", f.index, err)
+		}
+	}
+	if to != recipient || chainID != 300 || from != tx.From || gasPerPubdata != DefaultGasPerPubdata || !bytes.Equal(sig, tx.Signature) {
+		t.Errorf("unexpected fields to=%s chainId=%d from=%s gasPerPubdata=%d", to.Hex(), chainID, from.Hex(), gasPerPubdata)
+	}
+	// 没有 paymaster 时 paymasterParams 是空列表
+	if !bytes.Equal(fields[15], []byte{0xc0}) {
+		t.Errorf("paymasterParams = %x, want empty list", fields[15])
+	}
+}
+
+func TestHashBytecode(t *testing.T) {
+	code := make([]byte, 3*32)
+	hash, err := HashBytecode(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash[0] != 1 || hash[1] != 0 || hash[2] != 0 || hash[3] != 3 {
+		t.Errorf("hash prefix = %x, want 01000003", hash[:4])
+	}
+	for _, n := range []int{31, 64} {
+		if _, err := HashBytecode(make([]byte, n)); err == nil {
+			t.Errorf("bytecode of %d bytes accepted", n)
+		}
+	}
+}
+
+// fakeEra 是进程内的 Era 节点，只实现 Populate 和发送需要的方法
+type fakeEra struct {
+	meta eip712Meta
+	raw  []byte
+}
+
+func (f *fakeEra) ChainId() hexutil.Uint64 { return 300 }
+
+func (f *fakeEra) GetTransactionCount(addr common.Address, block string) hexutil.Uint64 { return 7 }
+
+func (f *fakeEra) SendRawTransaction(raw hexutil.Bytes) common.Hash {
+	f.raw = raw
+	return common.HexToHash("0x01")
+}
+
+type fakeZks struct{ era *fakeEra }
+
+func (f fakeZks) EstimateFee(req callRequest) Fee {
+	f.era.meta = req.Eip712Meta
+	num := func(v int64) *hexutil.Big { return (*hexutil.Big)(big.NewInt(v)) }
+	return Fee{GasLimit: num(400_000), GasPerPubdataLimit: num(50_000), MaxFeePerGas: num(45e6), MaxPriorityFeePerGas: num(0)}
+}
+
+func TestPopulateAndSend(t *testing.T) {
+	era := &fakeEra{}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", era); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("zks", fakeZks{era}); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	ctx := context.Background()
+	tx := &Transaction{From: crypto.PubkeyToAddress(testKey.PublicKey), To: &recipient, Value: big.NewInt(1)}
+	if err := client.Populate(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.ChainID.Int64() != 300 || tx.Nonce != 7 || tx.GasLimit != 400_000 || tx.MaxFeePerGas.Int64() != 45e6 || tx.GasPerPubdata != 50_000 {
+		t.Errorf("unexpected populated tx %+v", tx)
+	}
+	if era.meta.GasPerPubdata.ToInt().Int64() != DefaultGasPerPubdata {
+		t.Errorf("estimate sent gasPerPubdata %s", era.meta.GasPerPubdata)
+	}
+	if err := tx.Sign(testKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendEIP712(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(era.raw) == 0 || era.raw[0] != TxType {
+		t.Errorf("node received %x", era.raw)
+	}
+}